@@ -3,6 +3,10 @@ package agent
 import (
 	"os"
 	"path/filepath"
+	"time"
+
+	"github.com/superfly/flyctl/flyctl"
+	"github.com/superfly/flyctl/wg"
 )
 
 // TODO: deprecate
@@ -12,10 +16,37 @@ func PathToSocket() string {
 		panic(err)
 	}
 
-	return filepath.Join(dir, ".fly", "fly-agent.sock")
+	return filepath.Join(flyctl.ProfileConfigDir(dir, flyctl.Profile()), "fly-agent.sock")
 }
 
 type Instances struct {
 	Labels    []string
 	Addresses []string
 }
+
+// Proxy is a port forwarded by the agent in the background, along with its
+// traffic counters.
+type Proxy struct {
+	ID          string
+	Org         string
+	Network     string
+	Local       string
+	Remote      string
+	StartedAt   time.Time
+	Connections int64
+	// BytesSent and BytesReceived count the traffic to and from the remote.
+	BytesSent     int64
+	BytesReceived int64
+}
+
+// TunnelStats are the diagnostics of a WireGuard tunnel of the agent.
+type TunnelStats struct {
+	Org    string
+	Peer   string
+	Region string
+	// RTT is the round trip time to the DNS server of the peer; RTTError is
+	// set instead when it couldn't be measured.
+	RTT      time.Duration
+	RTTError string `json:",omitempty"`
+	wg.Stats
+}
@@ -336,6 +336,116 @@ func (c *Client) Resolve(ctx context.Context, slug, host string) (addr string, e
 
 // WaitForTunnel waits for a tunnel to the given org slug to become available
 // in the next four minutes.
+// StartProxy has the agent forward the local port to the remote address
+// through the tunnel of the organization, until the proxy is stopped.
+func (c *Client) StartProxy(ctx context.Context, slug, network, local, remote string) (p *Proxy, err error) {
+	err = c.do(ctx, func(conn net.Conn) (err error) {
+		if err = proto.Write(conn, "proxystart", slug, network, local, remote); err != nil {
+			return
+		}
+
+		var data []byte
+		if data, err = proto.Read(conn); err != nil {
+			return
+		}
+
+		switch {
+		default:
+			err = errInvalidResponse(data)
+		case isOK(data):
+			p = &Proxy{}
+			if err = unmarshal(p, data); err != nil {
+				p = nil
+			}
+		case isError(data):
+			err = extractError(data)
+		}
+
+		return
+	})
+
+	return
+}
+
+// StopProxy stops the proxies of the agent matching either the ID or the
+// local address given.
+func (c *Client) StopProxy(ctx context.Context, idOrAddr string) error {
+	return c.do(ctx, func(conn net.Conn) (err error) {
+		if err = proto.Write(conn, "proxystop", idOrAddr); err != nil {
+			return
+		}
+
+		var data []byte
+		if data, err = proto.Read(conn); err != nil {
+			return
+		}
+
+		switch {
+		default:
+			err = errInvalidResponse(data)
+		case string(data) == "ok":
+			return
+		case isError(data):
+			err = extractError(data)
+		}
+
+		return
+	})
+}
+
+func (c *Client) Proxies(ctx context.Context) (proxies []Proxy, err error) {
+	err = c.do(ctx, func(conn net.Conn) (err error) {
+		if err = proto.Write(conn, "proxies"); err != nil {
+			return
+		}
+
+		var data []byte
+		if data, err = proto.Read(conn); err != nil {
+			return
+		}
+
+		switch {
+		default:
+			err = errInvalidResponse(data)
+		case isOK(data):
+			err = unmarshal(&proxies, data)
+		case isError(data):
+			err = extractError(data)
+		}
+
+		return
+	})
+
+	return
+}
+
+// Stats returns the diagnostics of the tunnels of the agent.
+func (c *Client) Stats(ctx context.Context) (stats []TunnelStats, err error) {
+	err = c.do(ctx, func(conn net.Conn) (err error) {
+		if err = proto.Write(conn, "stats"); err != nil {
+			return
+		}
+
+		var data []byte
+		if data, err = proto.Read(conn); err != nil {
+			return
+		}
+
+		switch {
+		default:
+			err = errInvalidResponse(data)
+		case isOK(data):
+			err = unmarshal(&stats, data)
+		case isError(data):
+			err = extractError(data)
+		}
+
+		return
+	})
+
+	return
+}
+
 func (c *Client) WaitForTunnel(parent context.Context, slug string) (err error) {
 	ctx, cancel := context.WithTimeout(parent, 4*time.Minute)
 	defer cancel()
@@ -624,10 +734,16 @@ func (d *dialer) DialContext(ctx context.Context, network, addr string) (conn ne
 		}
 	}()
 
+	// UDP flows are framed by the agent, see DatagramConn
+	command := "connect"
+	if strings.HasPrefix(network, "udp") {
+		command = "connectudp"
+	}
+
 	c := make(chan error, 1)
 	go func() {
 		timeout := strconv.FormatInt(int64(d.timeout), 10)
-		if err := proto.Write(conn, "connect", d.slug, addr, timeout); err != nil {
+		if err := proto.Write(conn, command, d.slug, addr, timeout); err != nil {
 			c <- err
 			return
 		}
@@ -653,6 +769,9 @@ func (d *dialer) DialContext(ctx context.Context, network, addr string) (conn ne
 		err = ctx.Err()
 	case err = <-c:
 	}
+	if err == nil && command == "connectudp" {
+		conn = NewDatagramConn(conn)
+	}
 	return
 }
 
@@ -0,0 +1,69 @@
+package agent
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+)
+
+// maxDatagramSize is the size of the largest UDP payload.
+const maxDatagramSize = 65535
+
+// DatagramConn carries the datagrams of a UDP flow over a stream connection
+// to the agent. Each datagram is framed as an NBO u16 length followed by the
+// payload, so that datagram boundaries survive the stream.
+type DatagramConn struct {
+	net.Conn
+}
+
+// NewDatagramConn wraps a stream connection carrying framed datagrams.
+func NewDatagramConn(c net.Conn) *DatagramConn {
+	return &DatagramConn{Conn: c}
+}
+
+// Write writes b as a single datagram.
+func (c *DatagramConn) Write(b []byte) (int, error) {
+	if len(b) > maxDatagramSize {
+		return 0, fmt.Errorf("datagram write: too large (%d bytes)", len(b))
+	}
+
+	frame := make([]byte, 2+len(b))
+	binary.BigEndian.PutUint16(frame, uint16(len(b)))
+	copy(frame[2:], b)
+
+	if _, err := c.Conn.Write(frame); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// Read reads a single datagram into b. Like with UDP sockets, the part of
+// the datagram which doesn't fit in b is discarded.
+func (c *DatagramConn) Read(b []byte) (int, error) {
+	var lbuf [2]byte
+	if _, err := io.ReadFull(c.Conn, lbuf[:]); err != nil {
+		return 0, err
+	}
+
+	payload := make([]byte, binary.BigEndian.Uint16(lbuf[:]))
+	if _, err := io.ReadFull(c.Conn, payload); err != nil {
+		return 0, err
+	}
+
+	return copy(b, payload), nil
+}
+
+// CopyDatagrams copies datagrams from src to dst until either fails.
+func CopyDatagrams(dst io.Writer, src io.Reader) error {
+	buf := make([]byte, maxDatagramSize)
+	for {
+		n, err := src.Read(buf)
+		if err != nil {
+			return err
+		}
+		if _, err := dst.Write(buf[:n]); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/superfly/flyctl/agent"
+	"github.com/superfly/flyctl/proxy"
+)
+
+var errNoSuchProxy = errors.New("no such proxy")
+
+// backgroundProxy is a port the agent forwards through the tunnel of an
+// organization until it's stopped or the agent exits.
+type backgroundProxy struct {
+	agent.Proxy
+
+	connections   atomic.Int64
+	bytesSent     atomic.Int64
+	bytesReceived atomic.Int64
+
+	cancel context.CancelFunc
+}
+
+func (p *backgroundProxy) snapshot() agent.Proxy {
+	ret := p.Proxy
+	ret.Connections = p.connections.Load()
+	ret.BytesSent = p.bytesSent.Load()
+	ret.BytesReceived = p.bytesReceived.Load()
+
+	return ret
+}
+
+func (p *backgroundProxy) dial(tunnel dialer) func(context.Context, string, string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := tunnel.DialContext(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		p.connections.Add(1)
+
+		return &countedConn{Conn: conn, proxy: p}, nil
+	}
+}
+
+type dialer interface {
+	DialContext(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+// countedConn counts the traffic of a proxied connection.
+type countedConn struct {
+	net.Conn
+	proxy *backgroundProxy
+}
+
+func (c *countedConn) Read(b []byte) (n int, err error) {
+	n, err = c.Conn.Read(b)
+	c.proxy.bytesReceived.Add(int64(n))
+
+	return
+}
+
+func (c *countedConn) Write(b []byte) (n int, err error) {
+	n, err = c.Conn.Write(b)
+	c.proxy.bytesSent.Add(int64(n))
+
+	return
+}
+
+// localAddress returns the address to listen on for local, which is either a
+// port of the loopback interface, an address or a unix socket path.
+func localAddress(network, local string) (string, string) {
+	if _, err := strconv.Atoi(local); err == nil {
+		return network, net.JoinHostPort("127.0.0.1", local)
+	}
+
+	if network == "tcp" && !strings.Contains(local, ":") {
+		return "unix", local
+	}
+
+	return network, local
+}
+
+func (s *server) startProxy(ctx context.Context, slug, network, local, remote string) (*agent.Proxy, error) {
+	tunnel := s.tunnelFor(slug)
+	if tunnel == nil {
+		return nil, agent.ErrTunnelUnavailable
+	}
+
+	p := &backgroundProxy{
+		Proxy: agent.Proxy{
+			Org:       slug,
+			Network:   network,
+			Remote:    remote,
+			StartedAt: time.Now(),
+		},
+	}
+
+	var serve func(context.Context) error
+
+	listenNetwork, addr := localAddress(network, local)
+	switch network {
+	case "tcp":
+		l, err := net.Listen(listenNetwork, addr)
+		if err != nil {
+			return nil, err
+		}
+		p.Local = l.Addr().String()
+
+		serve = (&proxy.Server{
+			LocalAddr: p.Local,
+			Addr:      remote,
+			Listener:  l,
+			Dial:      p.dial(tunnel),
+		}).ProxyServer
+	case "udp":
+		conn, err := net.ListenPacket(listenNetwork, addr)
+		if err != nil {
+			return nil, err
+		}
+		p.Local = conn.LocalAddr().String()
+
+		serve = (&proxy.PacketServer{
+			Addr: remote,
+			Conn: conn,
+			Dial: p.dial(tunnel),
+		}).ProxyServer
+	default:
+		return nil, fmt.Errorf("unsupported network %q", network)
+	}
+
+	// the proxy outlives the session which started it
+	ctx, p.cancel = context.WithCancel(ctx)
+
+	s.mu.Lock()
+	s.proxyID++
+	p.ID = strconv.FormatUint(s.proxyID, 10)
+	s.proxies[p.ID] = p
+	s.mu.Unlock()
+
+	s.printf("proxy %s: forwarding %s %s to %s in %q", p.ID, network, p.Local, remote, slug)
+
+	go func() {
+		if err := serve(ctx); err != nil {
+			s.printf("proxy %s: %v", p.ID, err)
+		}
+
+		s.mu.Lock()
+		delete(s.proxies, p.ID)
+		s.mu.Unlock()
+
+		s.printf("proxy %s: stopped", p.ID)
+	}()
+
+	ret := p.snapshot()
+	return &ret, nil
+}
+
+// stopProxy stops the proxies matching either the ID or the local address
+// given.
+func (s *server) stopProxy(idOrAddr string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var stopped bool
+	for id, p := range s.proxies {
+		if id == idOrAddr || p.Local == idOrAddr {
+			p.cancel()
+			delete(s.proxies, id)
+
+			stopped = true
+		}
+	}
+
+	if !stopped {
+		return errNoSuchProxy
+	}
+
+	return nil
+}
+
+func (s *server) listProxies() []agent.Proxy {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ret := make([]agent.Proxy, 0, len(s.proxies))
+	for _, p := range s.proxies {
+		ret = append(ret, p.snapshot())
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].StartedAt.Before(ret[j].StartedAt)
+	})
+
+	return ret
+}
@@ -7,6 +7,7 @@ import (
 	"log"
 	"net"
 	"os"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -57,6 +58,7 @@ func Run(ctx context.Context, opt Options) (err error) {
 		listener:      l,
 		currentChange: latestChangeAt,
 		tunnels:       make(map[string]*wg.Tunnel),
+		proxies:       make(map[string]*backgroundProxy),
 	}).serve(ctx, l)
 
 	return
@@ -106,6 +108,8 @@ type server struct {
 	mu            sync.Mutex
 	currentChange time.Time
 	tunnels       map[string]*wg.Tunnel
+	proxies       map[string]*backgroundProxy
+	proxyID       uint64
 }
 
 type terminateError struct{ error }
@@ -285,6 +289,55 @@ func (s *server) tunnelFor(slug string) *wg.Tunnel {
 	return s.tunnels[slug]
 }
 
+// tunnelStats returns the diagnostics of the tunnels, measuring the round
+// trip time of each concurrently.
+func (s *server) tunnelStats(ctx context.Context) []agent.TunnelStats {
+	s.mu.Lock()
+	tunnels := make([]*wg.Tunnel, 0, len(s.tunnels))
+	for _, tunnel := range s.tunnels {
+		tunnels = append(tunnels, tunnel)
+	}
+	s.mu.Unlock()
+
+	stats := make([]agent.TunnelStats, len(tunnels))
+
+	var eg errgroup.Group
+	for i, tunnel := range tunnels {
+		i, tunnel := i, tunnel
+
+		eg.Go(func() error {
+			ts := agent.TunnelStats{
+				Org:    tunnel.State.Org,
+				Peer:   tunnel.State.Name,
+				Region: tunnel.State.Region,
+			}
+
+			var err error
+			if ts.Stats, err = tunnel.Stats(); err != nil {
+				s.printf("failed reading stats of %q: %v", ts.Org, err)
+			}
+
+			ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			defer cancel()
+
+			if ts.RTT, err = tunnel.RTT(ctx); err != nil {
+				ts.RTTError = err.Error()
+			}
+
+			stats[i] = ts
+
+			return nil
+		})
+	}
+	_ = eg.Wait()
+
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Org < stats[j].Org
+	})
+
+	return stats
+}
+
 func (s *server) probeTunnel(ctx context.Context, slug string) (err error) {
 	tunnel := s.tunnelFor(slug)
 	if tunnel == nil {
@@ -113,10 +113,15 @@ var handlers = map[string]handlerFunc{
 	"establish":   (*session).establish,
 	"reestablish": (*session).reestablish,
 	"connect":     (*session).connect,
+	"connectudp":  (*session).connectUDP,
 	"probe":       (*session).probe,
 	"instances":   (*session).instances,
 	"resolve":     (*session).resolve,
 	"ping6":       (*session).ping6,
+	"proxystart":  (*session).proxyStart,
+	"proxystop":   (*session).proxyStop,
+	"proxies":     (*session).proxies,
+	"stats":       (*session).stats,
 }
 
 var errMalformedKill = errors.New("malformed kill command")
@@ -313,6 +318,16 @@ var (
 )
 
 func (s *session) connect(ctx context.Context, args ...string) {
+	s.connectNetwork(ctx, "tcp", args...)
+}
+
+// connectUDP relays a UDP flow, whose datagrams are framed over the agent
+// connection by agent.DatagramConn.
+func (s *session) connectUDP(ctx context.Context, args ...string) {
+	s.connectNetwork(ctx, "udp", args...)
+}
+
+func (s *session) connectNetwork(ctx context.Context, network string, args ...string) {
 	if !s.exactArgs(3, args, errMalformedConnect) {
 		return
 	}
@@ -340,7 +355,7 @@ func (s *session) connect(ctx context.Context, args ...string) {
 	}
 	defer cancel()
 
-	outconn, err := tunnel.DialContext(dialContext, "tcp", args[1])
+	outconn, err := tunnel.DialContext(dialContext, network, args[1])
 	if err != nil {
 		s.error(err)
 
@@ -367,6 +382,21 @@ func (s *session) connect(ctx context.Context, args ...string) {
 		return errDone
 	})
 
+	if network == "udp" {
+		conn := agent.NewDatagramConn(s.conn)
+
+		eg.Go(func() error {
+			return agent.CopyDatagrams(conn, outconn)
+		})
+
+		eg.Go(func() error {
+			return agent.CopyDatagrams(outconn, conn)
+		})
+
+		_ = eg.Wait()
+		return
+	}
+
 	eg.Go(func() (err error) {
 		if _, err = io.Copy(s.conn, outconn); err == nil {
 			err = io.EOF
@@ -386,6 +416,59 @@ func (s *session) connect(ctx context.Context, args ...string) {
 	_ = eg.Wait()
 }
 
+var errMalformedProxyStart = errors.New("malformed proxystart command")
+
+func (s *session) proxyStart(ctx context.Context, args ...string) {
+	if !s.exactArgs(4, args, errMalformedProxyStart) {
+		return
+	}
+
+	p, err := s.srv.startProxy(ctx, args[0], args[1], args[2], args[3])
+	if err != nil {
+		s.error(err)
+
+		return
+	}
+
+	_ = s.marshal(p)
+}
+
+var errMalformedProxyStop = errors.New("malformed proxystop command")
+
+func (s *session) proxyStop(_ context.Context, args ...string) {
+	if !s.exactArgs(1, args, errMalformedProxyStop) {
+		return
+	}
+
+	if err := s.srv.stopProxy(args[0]); err != nil {
+		s.error(err)
+
+		return
+	}
+
+	_ = s.ok()
+}
+
+var errMalformedProxies = errors.New("malformed proxies command")
+
+func (s *session) proxies(_ context.Context, args ...string) {
+	if !s.noArgs(args, errMalformedProxies) {
+		return
+	}
+
+	_ = s.marshal(s.srv.listProxies())
+}
+
+var errMalformedStats = errors.New("malformed stats command")
+
+func (s *session) stats(ctx context.Context, args ...string) {
+	if !s.noArgs(args, errMalformedStats) {
+		return
+	}
+
+	_ = s.marshal(s.srv.tunnelStats(ctx))
+}
+
 func (s *session) ping6(ctx context.Context, args ...string) {
 	// As with "dial", "ping6" handles an agent command and then
 	// repurposes the agent connection as a transport.
@@ -0,0 +1,137 @@
+package api
+
+import (
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// mutations counts the mutations sent by the HTTP clients of this package,
+// whether to the GraphQL API or to the Machines API, so that caches can tell
+// what they hold may be stale.
+var mutations uint64
+
+func mutationCount() uint64 {
+	return atomic.LoadUint64(&mutations)
+}
+
+// mutationCountingTransport counts the mutations it passes through.
+type mutationCountingTransport struct {
+	InnerTransport http.RoundTripper
+}
+
+func (t *mutationCountingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	mutation, err := describeMutation(req)
+	if err != nil {
+		return nil, err
+	}
+	if mutation != "" {
+		// count both before and after, as the mutation may apply anytime
+		// in between
+		atomic.AddUint64(&mutations, 1)
+		defer atomic.AddUint64(&mutations, 1)
+	}
+
+	return t.InnerTransport.RoundTrip(req)
+}
+
+// appCache holds the metadata of the apps a client fetched, which commands
+// look up again and again over a single invocation. Entries fetched before a
+// mutation are ignored, since the metadata may have changed since.
+//
+// Callers get copies of the entries, which they may modify freely. A nil
+// *appCache caches nothing.
+type appCache struct {
+	mu      sync.Mutex
+	compact map[string]appCacheEntry[AppCompact]
+	basic   map[string]appCacheEntry[AppBasic]
+}
+
+type appCacheEntry[T any] struct {
+	app T
+	// mutations is the mutation count when the app was requested.
+	mutations uint64
+}
+
+func newAppCache() *appCache {
+	return &appCache{
+		compact: map[string]appCacheEntry[AppCompact]{},
+		basic:   map[string]appCacheEntry[AppBasic]{},
+	}
+}
+
+func (c *appCache) getCompact(appName string) (*AppCompact, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.compact[appName]
+	if !ok || entry.mutations != mutationCount() {
+		return nil, false
+	}
+	return copyAppCompact(&entry.app), true
+}
+
+// setCompact caches app, requested when the mutation count was mutations.
+func (c *appCache) setCompact(appName string, app *AppCompact, mutations uint64) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.compact[appName] = appCacheEntry[AppCompact]{*copyAppCompact(app), mutations}
+}
+
+func (c *appCache) getBasic(appName string) (*AppBasic, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.basic[appName]
+	if !ok || entry.mutations != mutationCount() {
+		return nil, false
+	}
+	return copyAppBasic(&entry.app), true
+}
+
+// setBasic caches app, requested when the mutation count was mutations.
+func (c *appCache) setBasic(appName string, app *AppBasic, mutations uint64) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.basic[appName] = appCacheEntry[AppBasic]{*copyAppBasic(app), mutations}
+}
+
+func copyAppCompact(app *AppCompact) *AppCompact {
+	cp := *app
+	if app.Organization != nil {
+		org := *app.Organization
+		cp.Organization = &org
+	}
+	if app.PostgresAppRole != nil {
+		role := *app.PostgresAppRole
+		cp.PostgresAppRole = &role
+	}
+	return &cp
+}
+
+func copyAppBasic(app *AppBasic) *AppBasic {
+	cp := *app
+	if app.Organization != nil {
+		org := *app.Organization
+		cp.Organization = &org
+	}
+	return &cp
+}
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newAppCacheTestClient returns a client answering queries of app a, and the
+// number of queries it answered.
+func newAppCacheTestClient(t *testing.T) (*Client, *int) {
+	var queries int
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		var body struct {
+			Query string `json:"query"`
+		}
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+
+		resp := `{"data":{
+			"appcompact":{"id":"1","name":"a","organization":{"slug":"org"},"postgresAppRole":{"name":"postgres_cluster"}},
+			"appbasic":{"id":"1","name":"a","organization":{"slug":"org"}}
+		}}`
+		if strings.HasPrefix(body.Query, "mutation") {
+			resp = `{"data":{}}`
+		} else {
+			queries++
+		}
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(resp)),
+		}, nil
+	})
+
+	client := NewClientFromOptions(ClientOptions{
+		AccessToken: "token",
+		BaseURL:     "https://api.fly.io",
+		Transport:   &Transport{UnderlyingTransport: transport},
+	})
+
+	return client, &queries
+}
+
+func TestAppCacheClearedByMutations(t *testing.T) {
+	client, queries := newAppCacheTestClient(t)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		app, err := client.GetAppCompact(ctx, "a")
+		require.NoError(t, err)
+		assert.Equal(t, "a", app.Name)
+
+		basic, err := client.GetAppBasic(ctx, "a")
+		require.NoError(t, err)
+		assert.Equal(t, "1", basic.ID)
+	}
+	assert.Equal(t, 2, *queries)
+
+	_, err := client.RunWithContext(ctx, client.NewRequest(`mutation($appId: ID!) { x }`))
+	require.NoError(t, err)
+
+	_, err = client.GetAppCompact(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, 3, *queries)
+
+	// writes to the Machines API, through clients of their own, count too
+	machines, err := NewNonRetryingHTTPClient(nil, roundTripFunc(func(*http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	}))
+	require.NoError(t, err)
+
+	res, err := machines.Get("https://api.machines.dev/v1/apps/a/machines")
+	require.NoError(t, err)
+	res.Body.Close()
+	_, err = client.GetAppCompact(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, 3, *queries)
+
+	res, err = machines.Post("https://api.machines.dev/v1/apps/a/machines/m/stop", "application/json", strings.NewReader(`{}`))
+	require.NoError(t, err)
+	res.Body.Close()
+	_, err = client.GetAppCompact(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, 4, *queries)
+}
+
+func TestAppCacheReturnsCopies(t *testing.T) {
+	client, _ := newAppCacheTestClient(t)
+	ctx := context.Background()
+
+	app, err := client.GetAppCompact(ctx, "a")
+	require.NoError(t, err)
+	app.Name = "b"
+	app.Organization.Slug = "other"
+	app.PostgresAppRole.Name = "other"
+
+	basic, err := client.GetAppBasic(ctx, "a")
+	require.NoError(t, err)
+	basic.Organization.Slug = "other"
+
+	app, err = client.GetAppCompact(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, "a", app.Name)
+	assert.Equal(t, "org", app.Organization.Slug)
+	assert.True(t, app.IsPostgresApp())
+
+	basic, err = client.GetAppBasic(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, "org", basic.Organization.Slug)
+}
@@ -38,6 +38,7 @@ type Client struct {
 	GenqClient  genq.Client
 	accessToken string
 	logger      Logger
+	apps        *appCache
 }
 
 // NewClient - creates a new Client, takes an access token
@@ -90,7 +91,7 @@ func NewClientFromOptions(opts ClientOptions) *Client {
 	client := graphql.NewClient(url, graphql.WithHTTPClient(httpClient))
 	genqClient := genq.NewClient(url, httpClient)
 
-	return &Client{httpClient, client, genqClient, opts.AccessToken, opts.Logger}
+	return &Client{httpClient, client, genqClient, opts.AccessToken, opts.Logger, newAppCache()}
 }
 
 // NewRequest - creates a new GraphQL request
@@ -0,0 +1,198 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ErrDryRun is returned by clients in dry run mode instead of performing the
+// first API mutation of a command.
+var ErrDryRun = errors.New("dry run: stopped before applying changes")
+
+var dryRunOut io.Writer
+
+// SetDryRun sets the writer mutations are described to, instead of being
+// performed, by the clients created afterwards. A nil writer disables dry
+// run mode.
+func SetDryRun(w io.Writer) {
+	dryRunOut = w
+}
+
+// maxDryRunPayloadSize is the size past which payload summaries are truncated.
+const maxDryRunPayloadSize = 400
+
+// redactedDryRunKeys are the substrings of the payload keys whose values are
+// hidden from summaries, since they may hold secrets.
+var redactedDryRunKeys = []string{"value", "secret", "token", "password", "privatekey"}
+
+// DryRunTransport describes the mutating requests it gets to Out and fails
+// them with ErrDryRun, while passing the others through.
+type DryRunTransport struct {
+	InnerTransport http.RoundTripper
+	Out            io.Writer
+}
+
+func (t *DryRunTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	mutation, err := describeMutation(req)
+	if err != nil {
+		return nil, err
+	}
+	if mutation == "" {
+		return t.InnerTransport.RoundTrip(req)
+	}
+
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+	fmt.Fprintf(t.Out, "Dry run: would %s\n", mutation)
+
+	return nil, ErrDryRun
+}
+
+// describeMutation describes the mutation req performs, if any. GraphQL
+// queries and the leases guarding machines against concurrent changes aren't
+// mutations of the resources of users.
+func describeMutation(req *http.Request) (string, error) {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return "", nil
+	}
+
+	path := strings.TrimSuffix(req.URL.Path, "/")
+	if strings.HasSuffix(path, "/lease") {
+		return "", nil
+	}
+
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return "", err
+		}
+		_ = req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	if !strings.HasSuffix(path, "/graphql") {
+		return joinNonEmpty(req.Method, req.URL.Path, summarizePayload(body)), nil
+	}
+
+	var gql struct {
+		Query         string          `json:"query"`
+		OperationName string          `json:"operationName"`
+		Variables     json.RawMessage `json:"variables"`
+	}
+	if err := json.Unmarshal(body, &gql); err != nil {
+		return "", nil
+	}
+
+	name, ok := graphQLMutationName(gql.Query)
+	if !ok {
+		return "", nil
+	}
+	if gql.OperationName != "" {
+		name = gql.OperationName
+	}
+
+	return joinNonEmpty("run GraphQL mutation", name, summarizePayload(gql.Variables)), nil
+}
+
+// graphQLMutationName returns the name of the mutation query performs, if it's
+// one.
+func graphQLMutationName(query string) (string, bool) {
+	var fields []string
+	for _, line := range strings.Split(query, "\n") {
+		// skip the comments genqlient precedes queries with
+		if line = strings.TrimSpace(line); strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields = append(fields, strings.FieldsFunc(line, func(r rune) bool {
+			return r == ' ' || r == '\t' || r == '(' || r == '{'
+		})...)
+		if len(fields) > 1 {
+			break
+		}
+	}
+
+	if len(fields) == 0 || fields[0] != "mutation" {
+		return "", false
+	}
+	// anonymous mutations are followed by their variables
+	if len(fields) > 1 && !strings.HasPrefix(fields[1], "$") {
+		return fields[1], true
+	}
+	return "", true
+}
+
+// summarizePayload returns the compact JSON of payload with the values which
+// may be secrets redacted, truncated to maxDryRunPayloadSize.
+func summarizePayload(payload []byte) string {
+	if len(bytes.TrimSpace(payload)) == 0 {
+		return ""
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(payload, &v); err != nil {
+		return fmt.Sprintf("(%d bytes)", len(payload))
+	}
+	if v == nil {
+		return ""
+	}
+
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(redactPayload(v)); err != nil {
+		return fmt.Sprintf("(%d bytes)", len(payload))
+	}
+	summary := bytes.TrimSpace(b.Bytes())
+	if len(summary) > maxDryRunPayloadSize {
+		return string(summary[:maxDryRunPayloadSize]) + "..."
+	}
+	return string(summary)
+}
+
+// redactPayload redacts the strings of v held by keys which may denote
+// secrets, keeping the structure around them, e.g. the names of secrets.
+func redactPayload(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			if _, ok := e.(string); ok && isRedactedDryRunKey(k) {
+				v[k] = "<redacted>"
+				continue
+			}
+			v[k] = redactPayload(e)
+		}
+	case []interface{}:
+		for i, e := range v {
+			v[i] = redactPayload(e)
+		}
+	}
+	return v
+}
+
+func joinNonEmpty(parts ...string) string {
+	var nonEmpty []string
+	for _, p := range parts {
+		if p != "" {
+			nonEmpty = append(nonEmpty, p)
+		}
+	}
+	return strings.Join(nonEmpty, " ")
+}
+
+func isRedactedDryRunKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range redactedDryRunKeys {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestDescribeMutation(t *testing.T) {
+	cases := []struct {
+		method string
+		url    string
+		body   string
+		want   string
+	}{
+		{method: "GET", url: "https://api.machines.dev/v1/apps/a/machines"},
+		{method: "POST", url: "https://api.machines.dev/v1/apps/a/machines/m/lease"},
+		{method: "DELETE", url: "https://api.machines.dev/v1/apps/a/machines/m/lease/"},
+		{
+			method: "POST",
+			url:    "https://api.machines.dev/v1/apps/a/machines/m/stop",
+			want:   "POST /v1/apps/a/machines/m/stop",
+		},
+		{
+			method: "POST",
+			url:    "https://api.machines.dev/v1/apps/a/machines",
+			body:   `{"region":"ams","config":{"image":"x"}}`,
+			want:   `POST /v1/apps/a/machines {"config":{"image":"x"},"region":"ams"}`,
+		},
+		{
+			method: "POST",
+			url:    "https://api.fly.io/graphql",
+			body:   `{"query":"query($appName: String!) { app(name: $appName) { id } }"}`,
+		},
+		{
+			method: "POST",
+			url:    "https://api.fly.io/graphql",
+			body:   `{"query":"mutation($input: SetSecretsInput!) { setSecrets(input: $input) { release { id } } }","variables":{"input":{"appId":"a","secrets":[{"key":"K","value":"v"}]}}}`,
+			want:   `run GraphQL mutation {"input":{"appId":"a","secrets":[{"key":"K","value":"<redacted>"}]}}`,
+		},
+		{
+			method: "POST",
+			url:    "https://api.fly.io/graphql",
+			body:   `{"query":"# @genqlient\nmutation SetAppRegions($appName: ID!) { x }","operationName":"SetAppRegions","variables":{"appName":"a"}}`,
+			want:   `run GraphQL mutation SetAppRegions {"appName":"a"}`,
+		},
+	}
+
+	for _, c := range cases {
+		req, err := http.NewRequest(c.method, c.url, strings.NewReader(c.body))
+		require.NoError(t, err)
+
+		got, err := describeMutation(req)
+		require.NoError(t, err)
+		assert.Equal(t, c.want, got, c.url)
+	}
+}
+
+func TestDryRunTransport(t *testing.T) {
+	var passed int
+	var out bytes.Buffer
+	tr := &DryRunTransport{
+		InnerTransport: roundTripFunc(func(*http.Request) (*http.Response, error) {
+			passed++
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+		}),
+		Out: &out,
+	}
+	client := &http.Client{Transport: tr}
+
+	res, err := client.Get("https://api.machines.dev/v1/apps/a/machines")
+	require.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, 1, passed)
+
+	_, err = client.Post("https://api.machines.dev/v1/apps/a/machines/m/stop", "application/json", strings.NewReader(`{"signal":"SIGINT"}`))
+	assert.True(t, errors.Is(err, ErrDryRun))
+	assert.Equal(t, 1, passed)
+	assert.Equal(t, "Dry run: would POST /v1/apps/a/machines/m/stop {\"signal\":\"SIGINT\"}\n", out.String())
+}
+
+func TestSummarizePayloadTruncates(t *testing.T) {
+	summary := summarizePayload([]byte(`{"image":"` + strings.Repeat("x", 2*maxDryRunPayloadSize) + `"}`))
+	assert.Len(t, summary, maxDryRunPayloadSize+len("..."))
+	assert.True(t, strings.HasSuffix(summary, "..."))
+}
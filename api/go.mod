@@ -5,6 +5,7 @@ go 1.18
 require (
 	github.com/Khan/genqlient v0.5.0
 	github.com/PuerkitoBio/rehttp v1.1.0
+	github.com/stretchr/testify v1.8.0
 	github.com/superfly/graphql v0.2.3
 	golang.org/x/crypto v0.5.0
 	golang.org/x/exp v0.0.0-20230321023759-10a507213a29
@@ -12,8 +13,10 @@ require (
 )
 
 require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pkg/errors v0.9.1 // indirect
-	github.com/stretchr/testify v1.8.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/vektah/gqlparser/v2 v2.4.8 // indirect
 	golang.org/x/sys v0.5.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
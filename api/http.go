@@ -24,17 +24,32 @@ func NewHTTPClient(logger Logger, transport http.RoundTripper) (*http.Client, er
 		rehttp.ExpJitterDelay(100*time.Millisecond, 1*time.Second),
 	)
 
+	return NewNonRetryingHTTPClient(logger, retryTransport)
+}
+
+// NewNonRetryingHTTPClient returns a client like NewHTTPClient's, but which
+// doesn't retry failed requests, for callers implementing their own retry
+// policy.
+func NewNonRetryingHTTPClient(logger Logger, transport http.RoundTripper) (*http.Client, error) {
+	var inner http.RoundTripper = &mutationCountingTransport{InnerTransport: transport}
+	if dryRunOut != nil {
+		inner = &DryRunTransport{
+			InnerTransport: inner,
+			Out:            dryRunOut,
+		}
+	}
+
 	if logger != nil {
 		return &http.Client{
 			Transport: &LoggingTransport{
-				InnerTransport: retryTransport,
+				InnerTransport: inner,
 				Logger:         logger,
 			},
 		}, nil
 	}
 
 	return &http.Client{
-		Transport: retryTransport,
+		Transport: inner,
 	}, nil
 }
 
@@ -8,20 +8,24 @@ import (
 )
 
 const (
-	MachineConfigMetadataKeyFlyManagedPostgres = "fly-managed-postgres"
-	MachineConfigMetadataKeyFlyPlatformVersion = "fly_platform_version"
-	MachineConfigMetadataKeyFlyReleaseId       = "fly_release_id"
-	MachineConfigMetadataKeyFlyReleaseVersion  = "fly_release_version"
-	MachineConfigMetadataKeyFlyProcessGroup    = "fly_process_group"
-	MachineConfigMetadataKeyFlyPreviousAlloc   = "fly_previous_alloc"
-	MachineFlyPlatformVersion2                 = "v2"
-	MachineProcessGroupApp                     = "app"
-	MachineProcessGroupFlyAppReleaseCommand    = "fly_app_release_command"
-	MachineStateDestroyed                      = "destroyed"
-	MachineStateDestroying                     = "destroying"
-	MachineStateStarted                        = "started"
-	MachineStateStopped                        = "stopped"
-	MachineStateCreated                        = "created"
+	MachineConfigMetadataKeyFlyManagedPostgres  = "fly-managed-postgres"
+	MachineConfigMetadataKeyFlyPlatformVersion  = "fly_platform_version"
+	MachineConfigMetadataKeyFlyReleaseId        = "fly_release_id"
+	MachineConfigMetadataKeyFlyReleaseVersion   = "fly_release_version"
+	MachineConfigMetadataKeyFlyProcessGroup     = "fly_process_group"
+	MachineConfigMetadataKeyFlyPreviousAlloc    = "fly_previous_alloc"
+	MachineConfigMetadataKeyFlyContextDigest    = "fly_context_digest"
+	MachineConfigMetadataKeyFlyCordoned         = "fly_cordoned"
+	MachineConfigMetadataKeyFlyCordonedServices = "fly_cordoned_services"
+	MachineConfigMetadataKeyFlyDeployLock       = "fly_deploy_lock"
+	MachineFlyPlatformVersion2                  = "v2"
+	MachineProcessGroupApp                      = "app"
+	MachineProcessGroupFlyAppReleaseCommand     = "fly_app_release_command"
+	MachineStateDestroyed                       = "destroyed"
+	MachineStateDestroying                      = "destroying"
+	MachineStateStarted                         = "started"
+	MachineStateStopped                         = "stopped"
+	MachineStateCreated                         = "created"
 )
 
 type Machine struct {
@@ -73,6 +77,12 @@ func (m *Machine) IsAppsV2() bool {
 	return m.Config != nil && m.Config.Metadata[MachineConfigMetadataKeyFlyPlatformVersion] == MachineFlyPlatformVersion2
 }
 
+// IsCordoned returns true when the machine was taken out of service with
+// `fly machine cordon`.
+func (m *Machine) IsCordoned() bool {
+	return m.Config != nil && m.Config.Metadata[MachineConfigMetadataKeyFlyCordoned] == "true"
+}
+
 func (m *Machine) IsFlyAppsPlatform() bool {
 	return m.IsAppsV2() && m.IsActive()
 }
@@ -577,3 +587,20 @@ type MachineExecResponse struct {
 	StdOut   string `json:"stdout,omitempty"`
 	StdErr   string `json:"stderr,omitempty"`
 }
+
+// MachineVolume is a volume as described by the Machines API.
+type MachineVolume struct {
+	ID                string    `json:"id"`
+	Name              string    `json:"name"`
+	Region            string    `json:"region"`
+	SizeGb            int       `json:"size_gb"`
+	AttachedMachineID *string   `json:"attached_machine_id"`
+	CreatedAt         time.Time `json:"created_at"`
+	// SnapshotRetention is the number of days the daily snapshots of the
+	// volume are kept for.
+	SnapshotRetention int `json:"snapshot_retention"`
+}
+
+type UpdateVolumeRequest struct {
+	SnapshotRetention *int `json:"snapshot_retention,omitempty"`
+}
@@ -208,7 +208,14 @@ func (client *Client) GetApp(ctx context.Context, appName string) (*App, error)
 	return &data.App, nil
 }
 
+// GetAppCompact returns the metadata of the app, cached until any API client
+// performs a mutation, including Machines API ones.
 func (client *Client) GetAppCompact(ctx context.Context, appName string) (*AppCompact, error) {
+	if app, ok := client.apps.getCompact(appName); ok {
+		return app, nil
+	}
+	mutations := mutationCount()
+
 	query := `
 		query ($appName: String!) {
 			appcompact:app(name: $appName) {
@@ -243,6 +250,8 @@ func (client *Client) GetAppCompact(ctx context.Context, appName string) (*AppCo
 		return nil, err
 	}
 
+	client.apps.setCompact(appName, &data.AppCompact, mutations)
+
 	return &data.AppCompact, nil
 }
 
@@ -313,7 +322,14 @@ func (client *Client) GetAppInfo(ctx context.Context, appName string) (*AppInfo,
 	return &data.AppInfo, nil
 }
 
+// GetAppBasic returns the basic metadata of the app, cached until any API
+// client performs a mutation, including Machines API ones.
 func (client *Client) GetAppBasic(ctx context.Context, appName string) (*AppBasic, error) {
+	if app, ok := client.apps.getBasic(appName); ok {
+		return app, nil
+	}
+	mutations := mutationCount()
+
 	query := `
 		query ($appName: String!) {
 			appbasic:app(name: $appName) {
@@ -337,6 +353,8 @@ func (client *Client) GetAppBasic(ctx context.Context, appName string) (*AppBasi
 		return nil, err
 	}
 
+	client.apps.setBasic(appName, &data.AppBasic, mutations)
+
 	return &data.AppBasic, nil
 }
 
@@ -29,6 +29,46 @@ func (c *Client) GetAppCertificates(ctx context.Context, appName string) ([]AppC
 	return data.AppCertsCompact.Certificates.Nodes, nil
 }
 
+// GetAppCertificatesStatus returns the certificates of an app along with their
+// validation state and the expiry of the issued certificates.
+func (c *Client) GetAppCertificatesStatus(ctx context.Context, appName string) ([]AppCertificate, error) {
+	query := `
+		query($appName: String!) {
+			appcerts:app(name: $appName) {
+				certificates {
+					nodes {
+						acmeDnsConfigured
+						acmeAlpnConfigured
+						configured
+						createdAt
+						hostname
+						clientStatus
+						isApex
+						isWildcard
+						issued {
+							nodes {
+								type
+								expiresAt
+							}
+						}
+					}
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+
+	req.Var("appName", appName)
+
+	data, err := c.RunWithContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.AppCerts.Certificates.Nodes, nil
+}
+
 func (c *Client) CheckAppCertificate(ctx context.Context, appName, hostname string) (*AppCertificate, *HostnameCheck, error) {
 	query := `
 		mutation($input: CheckCertificateInput!) {
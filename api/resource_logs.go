@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"time"
 )
 
 type getLogsResponse struct {
@@ -19,13 +20,39 @@ type getLogsResponse struct {
 }
 
 func (c *Client) GetAppLogs(ctx context.Context, appName, token, region, instanceID string) (entries []LogEntry, nextToken string, err error) {
+	return c.SearchAppLogs(ctx, appName, token, AppLogsQuery{
+		Region:   region,
+		Instance: instanceID,
+	})
+}
+
+// AppLogsQuery narrows down the logs SearchAppLogs returns.
+type AppLogsQuery struct {
+	Region   string
+	Instance string
+	Search   string
+	Start    time.Time
+	End      time.Time
+}
+
+// SearchAppLogs returns a page of the app's logs matching the query.
+func (c *Client) SearchAppLogs(ctx context.Context, appName, token string, q AppLogsQuery) (entries []LogEntry, nextToken string, err error) {
 	data := url.Values{}
 	data.Set("next_token", token)
-	if instanceID != "" {
-		data.Set("instance", instanceID)
+	if q.Instance != "" {
+		data.Set("instance", q.Instance)
+	}
+	if q.Region != "" {
+		data.Set("region", q.Region)
+	}
+	if q.Search != "" {
+		data.Set("search", q.Search)
+	}
+	if !q.Start.IsZero() {
+		data.Set("start_time", q.Start.UTC().Format(time.RFC3339))
 	}
-	if region != "" {
-		data.Set("region", region)
+	if !q.End.IsZero() {
+		data.Set("end_time", q.End.UTC().Format(time.RFC3339))
 	}
 
 	url := fmt.Sprintf("%s/api/v1/apps/%s/logs?%s", baseURL, appName, data.Encode())
@@ -1,6 +1,9 @@
 package api
 
-import "context"
+import (
+	"context"
+	"fmt"
+)
 
 func (c *Client) GetAppReleasesNomad(ctx context.Context, appName string, limit int) ([]Release, error) {
 	query := `
@@ -78,6 +81,54 @@ func (c *Client) GetAppReleasesMachines(ctx context.Context, appName string, lim
 	return data.App.Releases.Nodes, nil
 }
 
+// GetAppReleaseMachinesByVersion returns the release of a machines app with
+// the given version, along with the app config it was deployed with. Only the
+// last limit releases are looked up.
+func (c *Client) GetAppReleaseMachinesByVersion(ctx context.Context, appName string, version int, limit int) (*Release, error) {
+	query := `
+		query ($appName: String!, $limit: Int!) {
+			app(name: $appName) {
+				releases: releasesUnprocessed(first: $limit) {
+					nodes {
+						id
+						version
+						description
+						reason
+						status
+						imageRef
+						stable
+						configDefinition
+						user {
+							id
+							email
+							name
+						}
+						createdAt
+					}
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+
+	req.Var("appName", appName)
+	req.Var("limit", limit)
+
+	data, err := c.RunWithContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range data.App.Releases.Nodes {
+		if r.Version == version {
+			return &r, nil
+		}
+	}
+
+	return nil, fmt.Errorf("release v%d not found among the last %d releases of %s", version, limit, appName)
+}
+
 func (c *Client) GetAppReleaseNomad(ctx context.Context, appName string, id string) (*Release, error) {
 	query := `
 		query ($appName: String!, $releaseId: ID!) {
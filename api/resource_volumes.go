@@ -189,6 +189,27 @@ func (c *Client) ForkVolume(ctx context.Context, input ForkVolumeInput) (*Volume
 	return &data.ForkVolume.Volume, nil
 }
 
+// CreateVolumeSnapshot requests an on-demand snapshot of the volume. The
+// snapshot is taken asynchronously and shows in GetVolumeSnapshots once done.
+func (c *Client) CreateVolumeSnapshot(ctx context.Context, volID string) error {
+	query := `
+		mutation($input: CreateVolumeSnapshotInput!) {
+			createVolumeSnapshot(input: $input) {
+				volume {
+					id
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+
+	req.Var("input", map[string]string{"volumeId": volID})
+
+	_, err := c.RunWithContext(ctx, req)
+	return err
+}
+
 func (c *Client) GetVolume(ctx context.Context, volID string) (Volume *Volume, err error) {
 	query := `
 	query($id: ID!) {
@@ -202,6 +223,7 @@ func (c *Client) GetVolume(ctx context.Context, volID string) (Volume *Volume, e
 				sizeGb
 				region
 				encrypted
+				state
 				createdAt
 				host {
 					id
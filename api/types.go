@@ -24,6 +24,7 @@ type Query struct {
 	AppMonitoring        AppMonitoring
 	AppPostgres          AppPostgres
 	AppCertsCompact      AppCertsCompact
+	AppCerts             AppCerts
 	Viewer               User
 	PersonalOrganization Organization
 	GqlMachine           GqlMachine
@@ -177,6 +178,8 @@ type Query struct {
 	ExtendVolume ExtendVolumePayload
 	ForkVolume   ForkVolumePayload
 
+	CreateVolumeSnapshot CreateVolumeSnapshotPayload
+
 	AddWireGuardPeer              CreatedWireGuardPeer
 	EstablishSSHKey               SSHCertificate
 	IssueCertificate              IssuedCertificate
@@ -432,12 +435,22 @@ type ForkVolumePayload struct {
 	Volume Volume
 }
 
+type CreateVolumeSnapshotPayload struct {
+	Volume Volume
+}
+
 type AppCertsCompact struct {
 	Certificates struct {
 		Nodes []AppCertificateCompact
 	}
 }
 
+type AppCerts struct {
+	Certificates struct {
+		Nodes []AppCertificate
+	}
+}
+
 type AppCertificateCompact struct {
 	CreatedAt    time.Time
 	Hostname     string
@@ -744,6 +757,9 @@ type Release struct {
 	EvaluationID       string
 	CreatedAt          time.Time
 	ImageRef           string
+	// ConfigDefinition is the app config the release was deployed with, only
+	// fetched by GetAppReleaseMachinesByVersion
+	ConfigDefinition *Definition `json:",omitempty"`
 }
 
 type Build struct {
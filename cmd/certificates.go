@@ -1,12 +1,16 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net"
 	"strings"
+	"time"
 
 	"github.com/dustin/go-humanize"
 	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/internal/buildinfo"
 	"github.com/superfly/flyctl/client"
 	"github.com/superfly/flyctl/cmdctx"
 
@@ -38,6 +42,10 @@ func newCertificatesCommand(client *client.Client) *Command {
 	createCmd := BuildCommandKS(cmd, runCertAdd, certsCreateStrings, client, requireSession, requireAppName)
 	createCmd.Aliases = []string{"create"}
 	createCmd.Command.Args = cobra.ExactArgs(1)
+	createCmd.AddBoolFlag(BoolFlagOpts{Name: "wildcard", Description: "Add a wildcard certificate covering every subdomain of the hostname"})
+	createCmd.AddBoolFlag(BoolFlagOpts{Name: "wait", Description: "Wait until the certificate has been issued"})
+	createCmd.AddIntFlag(IntFlagOpts{Name: "wait-timeout", Description: "Seconds to wait for the certificate to be issued", Default: 600})
+	createCmd.AddStringFlag(StringFlagOpts{Name: "dns-provider", Description: "Create the DNS records of the certificate with a DNS provider and wait for issuance: cloudflare, route53 or dnsimple"})
 
 	certsDeleteStrings := docstrings.Get("certs.remove")
 	deleteCmd := BuildCommandKS(cmd, runCertDelete, certsDeleteStrings, client, requireSession, requireAppName)
@@ -51,9 +59,12 @@ func newCertificatesCommand(client *client.Client) *Command {
 	show.AddBoolFlag(BoolFlagOpts{Name: "json", Shorthand: "j", Description: "JSON output"})
 
 	certsCheckStrings := docstrings.Get("certs.check")
-	check := BuildCommandKS(cmd, runCertCheck, certsCheckStrings, client, requireSession, requireAppName)
-	check.Command.Args = cobra.ExactArgs(1)
+	check := BuildCommandKS(cmd, runCertCheck, certsCheckStrings, client, requireSession, optionalAppName)
+	check.Command.Args = cobra.MaximumNArgs(1)
 	check.AddBoolFlag(BoolFlagOpts{Name: "json", Shorthand: "j", Description: "JSON output"})
+	check.AddBoolFlag(BoolFlagOpts{Name: "all", Description: "Check the certificates of every app of an organization"})
+	check.AddStringFlag(StringFlagOpts{Name: "org", Shorthand: "o", Description: "The organization to check with --all"})
+	check.AddIntFlag(IntFlagOpts{Name: "days", Description: "With --all, fail when a certificate expires within this many days", Default: 30})
 
 	return cmd
 }
@@ -92,6 +103,15 @@ func runCertShow(commandContext *cmdctx.CmdContext) error {
 func runCertCheck(commandContext *cmdctx.CmdContext) error {
 	ctx := commandContext.Command.Context()
 
+	switch {
+	case commandContext.Config.GetBool("all"):
+		return runCertCheckAll(commandContext)
+	case len(commandContext.Args) == 0:
+		return errors.New("requires a hostname, or --all to check every certificate of an organization")
+	case commandContext.AppName == "":
+		return fmt.Errorf("We couldn't find a fly.toml nor an app specified by the -a flag. If you want to launch a new app, use '" + buildinfo.Name() + " launch'")
+	}
+
 	hostname := commandContext.Args[0]
 
 	cert, hostcheck, err := commandContext.Client.API().CheckAppCertificate(ctx, commandContext.AppName, hostname)
@@ -103,7 +123,7 @@ func runCertCheck(commandContext *cmdctx.CmdContext) error {
 		// A certificate has been issued
 		commandContext.Statusf("certs", cmdctx.SINFO, "The certificate for %s has been issued.\n", hostname)
 		printCertificate(commandContext, cert)
-		// Details should go here
+		reportCertRenewal(commandContext, cert)
 		return nil
 	}
 
@@ -116,13 +136,228 @@ func runCertAdd(commandContext *cmdctx.CmdContext) error {
 	ctx := commandContext.Command.Context()
 
 	hostname := commandContext.Args[0]
+	if commandContext.Config.GetBool("wildcard") && !strings.HasPrefix(hostname, "*.") {
+		hostname = "*." + hostname
+	}
+
+	var provider dnsProvider
+	if name := commandContext.Config.GetString("dns-provider"); name != "" {
+		var err error
+		if provider, err = newDNSProvider(name); err != nil {
+			return err
+		}
+	}
 
 	cert, hostcheck, err := commandContext.Client.API().AddCertificate(ctx, commandContext.AppName, hostname)
 	if err != nil {
 		return err
 	}
 
-	return reportNextStepCert(commandContext, hostname, cert, hostcheck)
+	if provider != nil {
+		if err := createCertRecords(commandContext, provider, cert, hostcheck); err != nil {
+			return err
+		}
+	} else if err := reportNextStepCert(commandContext, hostname, cert, hostcheck); err != nil {
+		return err
+	}
+
+	if (provider == nil && !commandContext.Config.GetBool("wait")) || cert.ClientStatus == "Ready" {
+		return nil
+	}
+
+	timeout := time.Duration(commandContext.Config.GetInt("wait-timeout")) * time.Second
+	return waitForCertificate(commandContext, hostname, timeout)
+}
+
+// waitForCertificate polls the certificate until it's issued or the timeout
+// elapses.
+func waitForCertificate(cmdCtx *cmdctx.CmdContext, hostname string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(cmdCtx.Command.Context(), timeout)
+	defer cancel()
+
+	cmdCtx.Statusf("certs", cmdctx.SINFO, "Waiting for the certificate for %s to be issued...\n", hostname)
+
+	status := ""
+	for {
+		cert, _, err := cmdCtx.Client.API().CheckAppCertificate(ctx, cmdCtx.AppName, hostname)
+		switch {
+		case ctx.Err() != nil:
+			return fmt.Errorf("timed out waiting for the certificate for %s to be issued, last status was %q", hostname, status)
+		case err != nil:
+			return err
+		case cert.ClientStatus == "Ready":
+			cmdCtx.Statusf("certs", cmdctx.SINFO, "The certificate for %s has been issued.\n", hostname)
+			return nil
+		case cert.ClientStatus != status:
+			status = cert.ClientStatus
+			cmdCtx.Statusf("certs", cmdctx.SINFO, "Status: %s\n", status)
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(certPollInterval):
+		}
+	}
+}
+
+// createCertRecords creates the DNS records of a certificate with a DNS
+// provider. The hostname is pointed at the app only when it has no records
+// yet, so that traffic isn't moved before the certificate is issued; the
+// DNS-01 challenge record validates the certificate in the meantime.
+func createCertRecords(cmdCtx *cmdctx.CmdContext, provider dnsProvider, cert *api.AppCertificate, hostcheck *api.HostnameCheck) error {
+	ctx := cmdCtx.Command.Context()
+
+	var records []dnsRecord
+	if cert.DNSValidationHostname != "" && cert.DNSValidationTarget != "" {
+		records = append(records, dnsRecord{Type: "CNAME", Name: cert.DNSValidationHostname, Content: cert.DNSValidationTarget})
+	}
+
+	routed := len(hostcheck.ARecords)+len(hostcheck.AAAARecords)+len(hostcheck.CNAMERecords) > 0
+	switch {
+	case routed:
+		cmdCtx.Statusf("certs", cmdctx.SWARN, "%s already has DNS records, point it at %s.fly.dev when you are ready to move traffic.\n", cert.Hostname, cmdCtx.AppName)
+	case cert.IsApex:
+		ips, err := cmdCtx.Client.API().GetIPAddresses(ctx, cmdCtx.AppName)
+		if err != nil {
+			return err
+		}
+		for _, ip := range ips {
+			switch ip.Type {
+			case "v4", "shared_v4":
+				records = append(records, dnsRecord{Type: "A", Name: cert.Hostname, Content: ip.Address})
+			case "v6":
+				records = append(records, dnsRecord{Type: "AAAA", Name: cert.Hostname, Content: ip.Address})
+			}
+		}
+	default:
+		records = append(records, dnsRecord{Type: "CNAME", Name: cert.Hostname, Content: cmdCtx.AppName + ".fly.dev"})
+	}
+
+	if len(records) == 0 {
+		return fmt.Errorf("no DNS records to create for %s, allocate IP addresses to the app first", cert.Hostname)
+	}
+
+	for _, r := range records {
+		if err := provider.UpsertRecord(ctx, r); err != nil {
+			return fmt.Errorf("failed creating %s record %s: %w", r.Type, r.Name, err)
+		}
+		cmdCtx.Statusf("certs", cmdctx.SINFO, "Created %s %s %s\n", r.Type, r.Name, r.Content)
+	}
+
+	return nil
+}
+
+const (
+	certPollInterval = 10 * time.Second
+
+	// certRenewalWindow is how long before expiry certificates are renewed.
+	// Certificates expiring sooner than that are failing to renew.
+	certRenewalWindow = 30 * 24 * time.Hour
+)
+
+// reportCertRenewal warns about issued certificates whose renewal is failing
+// or bound to fail.
+func reportCertRenewal(cmdCtx *cmdctx.CmdContext, cert *api.AppCertificate) {
+	if cert.IsWildcard && !cert.AcmeDNSConfigured {
+		cmdCtx.Statusf("certs", cmdctx.SWARN, "The DNS-01 challenge record for %s is missing, renewing this wildcard certificate will fail.\n", cert.Hostname)
+		cmdCtx.Statusf("certs", cmdctx.SWARN, "Make sure your DNS service has the following record:\n\n    CNAME %s %s\n\n", cert.DNSValidationHostname, cert.DNSValidationTarget)
+	}
+
+	for _, issued := range cert.Issued.Nodes {
+		if until := time.Until(issued.ExpiresAt); until < certRenewalWindow {
+			cmdCtx.Statusf("certs", cmdctx.SWARN, "The %s certificate for %s expires %s and hasn't been renewed yet, renewal may be failing.\n",
+				issued.Type, cert.Hostname, humanize.Time(issued.ExpiresAt))
+		}
+	}
+}
+
+// certCheck is the state of a certificate checked with --all.
+type certCheck struct {
+	App       string     `json:"app"`
+	Hostname  string     `json:"hostname"`
+	Status    string     `json:"status"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Problem   string     `json:"problem,omitempty"`
+}
+
+func runCertCheckAll(cmdCtx *cmdctx.CmdContext) error {
+	ctx := cmdCtx.Command.Context()
+	client := cmdCtx.Client.API()
+	window := time.Duration(cmdCtx.Config.GetInt("days")) * 24 * time.Hour
+
+	org, err := selectOrganization(ctx, client, cmdCtx.Config.GetString("org"))
+	if err != nil {
+		return err
+	}
+
+	apps, err := client.GetAppsForOrganization(ctx, org.ID)
+	if err != nil {
+		return err
+	}
+
+	checks := []certCheck{}
+	for _, app := range apps {
+		certs, err := client.GetAppCertificatesStatus(ctx, app.Name)
+		if err != nil {
+			return fmt.Errorf("failed retrieving the certificates of %s: %w", app.Name, err)
+		}
+		for i := range certs {
+			checks = append(checks, checkCertificate(app.Name, &certs[i], time.Now(), window))
+		}
+	}
+
+	failing := 0
+	for _, c := range checks {
+		if c.Problem != "" {
+			failing++
+		}
+	}
+
+	if cmdCtx.OutputJSON() {
+		cmdCtx.WriteJSON(checks)
+	} else {
+		cmdCtx.Statusf("certs", cmdctx.STITLE, "%-20s %-30s %-25s %-20s %s\n", "App", "Host Name", "Status", "Expires", "Problem")
+		for _, c := range checks {
+			expires := ""
+			if c.ExpiresAt != nil {
+				expires = humanize.Time(*c.ExpiresAt)
+			}
+			cmdCtx.Statusf("certs", cmdctx.SINFO, "%-20s %-30s %-25s %-20s %s\n", c.App, c.Hostname, c.Status, expires, c.Problem)
+		}
+	}
+
+	if failing > 0 {
+		return fmt.Errorf("%d of %d certificates are failing or expiring within %d days", failing, len(checks), cmdCtx.Config.GetInt("days"))
+	}
+	return nil
+}
+
+// checkCertificate reports the problem of a certificate, if any: it isn't
+// issued, it expires within window, or its renewal is bound to fail.
+func checkCertificate(appName string, cert *api.AppCertificate, now time.Time, window time.Duration) certCheck {
+	c := certCheck{
+		App:      appName,
+		Hostname: cert.Hostname,
+		Status:   cert.ClientStatus,
+	}
+
+	for _, issued := range cert.Issued.Nodes {
+		expiresAt := issued.ExpiresAt
+		if c.ExpiresAt == nil || expiresAt.Before(*c.ExpiresAt) {
+			c.ExpiresAt = &expiresAt
+		}
+	}
+
+	switch {
+	case cert.ClientStatus != "Ready":
+		c.Problem = "not issued"
+	case c.ExpiresAt != nil && c.ExpiresAt.Sub(now) < window:
+		c.Problem = "expires " + humanize.RelTime(*c.ExpiresAt, now, "ago", "from now")
+	case cert.IsWildcard && !cert.AcmeDNSConfigured:
+		c.Problem = "DNS-01 challenge record missing"
+	}
+
+	return c
 }
 
 func runCertDelete(commandContext *cmdctx.CmdContext) error {
@@ -254,9 +489,14 @@ func reportNextStepCert(cmdCtx *cmdctx.CmdContext, hostname string, cert *api.Ap
 		}
 
 		if addCNAMErecord {
-			cmdCtx.Statusf("certs", cmdctx.SINFO, "You can validate your ownership of %s by:\n\n", hostname)
+			cmdCtx.Statusf("certs", cmdctx.SINFO, "You can validate your ownership of %s with a DNS-01 challenge by:\n\n", hostname)
 			cmdCtx.Statusf("certs", cmdctx.SINFO, "%d: Adding an CNAME record to your DNS service which reads:\n\n", stepcnt)
-			cmdCtx.Statusf("certs", cmdctx.SINFO, "    %s\n", cert.DNSValidationInstructions)
+			if cert.DNSValidationHostname != "" && cert.DNSValidationTarget != "" {
+				cmdCtx.Statusf("certs", cmdctx.SINFO, "    CNAME %s %s\n\n", cert.DNSValidationHostname, cert.DNSValidationTarget)
+			} else {
+				cmdCtx.Statusf("certs", cmdctx.SINFO, "    %s\n\n", cert.DNSValidationInstructions)
+			}
+			cmdCtx.Statusf("certs", cmdctx.SINFO, "Keep this record in place, it's required every time the certificate is renewed.\n")
 			// stepcnt = stepcnt + 1 Uncomment if more steps
 		}
 	} else {
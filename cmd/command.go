@@ -293,6 +293,16 @@ func setupAppName(ctx *cmdctx.CmdContext) error {
 	return nil
 }
 
+// optionalAppName resolves the app name like requireAppName, for commands
+// which also run without an app.
+func optionalAppName(cmd *Command) Initializer {
+	addAppConfigFlags(cmd)
+
+	return Initializer{
+		Setup: setupAppName,
+	}
+}
+
 func requireAppName(cmd *Command) Initializer {
 	// TODO: Add Flags to docStrings
 
@@ -0,0 +1,306 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// dnsRecord is a record created at a DNS provider. Name is fully qualified,
+// without the trailing dot.
+type dnsRecord struct {
+	Type    string
+	Name    string
+	Content string
+}
+
+// dnsProvider creates records with the API of a DNS provider, authenticating
+// with the local credentials of the user.
+type dnsProvider interface {
+	// UpsertRecord creates the record, or updates the record of the same type
+	// and name.
+	UpsertRecord(ctx context.Context, record dnsRecord) error
+}
+
+func newDNSProvider(name string) (dnsProvider, error) {
+	switch name {
+	case "cloudflare":
+		token := os.Getenv("CLOUDFLARE_API_TOKEN")
+		if token == "" {
+			return nil, errors.New("CLOUDFLARE_API_TOKEN must be set to a token allowed to edit the DNS of the zone")
+		}
+		return &cloudflareProvider{token: token}, nil
+	case "route53":
+		if _, err := exec.LookPath("aws"); err != nil {
+			return nil, fmt.Errorf("the aws CLI must be installed to create records in Route53: %w", err)
+		}
+		return &route53Provider{}, nil
+	case "dnsimple":
+		token := os.Getenv("DNSIMPLE_TOKEN")
+		if token == "" {
+			return nil, errors.New("DNSIMPLE_TOKEN must be set to an account or user token")
+		}
+		return &dnsimpleProvider{token: token}, nil
+	default:
+		return nil, fmt.Errorf("unknown DNS provider %s, must be cloudflare, route53 or dnsimple", name)
+	}
+}
+
+// parentDomains lists the domains name belongs to, from name itself to its
+// top level domain, to find the zone holding name.
+func parentDomains(name string) []string {
+	labels := strings.Split(strings.TrimSuffix(name, "."), ".")
+	domains := make([]string, 0, len(labels))
+	for i := range labels[:len(labels)-1] {
+		domains = append(domains, strings.Join(labels[i:], "."))
+	}
+	return domains
+}
+
+// doJSON sends in as the JSON body of a request, and decodes the response in
+// out. Responses with an error status are returned as errors.
+func doJSON(ctx context.Context, method, url string, headers map[string]string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s %s failed with status %d: %s", method, url, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, out)
+}
+
+type cloudflareProvider struct {
+	token string
+}
+
+const cloudflareAPI = "https://api.cloudflare.com/client/v4"
+
+func (p *cloudflareProvider) do(ctx context.Context, method, path string, in, out interface{}) error {
+	return doJSON(ctx, method, cloudflareAPI+path, map[string]string{"Authorization": "Bearer " + p.token}, in, out)
+}
+
+func (p *cloudflareProvider) zoneID(ctx context.Context, name string) (string, error) {
+	for _, domain := range parentDomains(name) {
+		var resp struct {
+			Result []struct {
+				ID string `json:"id"`
+			} `json:"result"`
+		}
+		if err := p.do(ctx, http.MethodGet, "/zones?name="+url.QueryEscape(domain), nil, &resp); err != nil {
+			return "", err
+		}
+		if len(resp.Result) > 0 {
+			return resp.Result[0].ID, nil
+		}
+	}
+	return "", fmt.Errorf("no Cloudflare zone found for %s", name)
+}
+
+func (p *cloudflareProvider) UpsertRecord(ctx context.Context, record dnsRecord) error {
+	zoneID, err := p.zoneID(ctx, record.Name)
+	if err != nil {
+		return err
+	}
+
+	var existing struct {
+		Result []struct {
+			ID string `json:"id"`
+		} `json:"result"`
+	}
+	query := url.Values{"type": {record.Type}, "name": {record.Name}}
+	if err := p.do(ctx, http.MethodGet, "/zones/"+zoneID+"/dns_records?"+query.Encode(), nil, &existing); err != nil {
+		return err
+	}
+
+	// records must not be proxied for the certificate to be validated
+	in := map[string]interface{}{
+		"type":    record.Type,
+		"name":    record.Name,
+		"content": record.Content,
+		"ttl":     1,
+		"proxied": false,
+	}
+	if len(existing.Result) > 0 {
+		return p.do(ctx, http.MethodPut, "/zones/"+zoneID+"/dns_records/"+existing.Result[0].ID, in, nil)
+	}
+	return p.do(ctx, http.MethodPost, "/zones/"+zoneID+"/dns_records", in, nil)
+}
+
+// route53Provider creates records with the aws CLI, which signs requests with
+// the local AWS credentials.
+type route53Provider struct{}
+
+func (p *route53Provider) aws(ctx context.Context, out interface{}, args ...string) error {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "aws", append(args, "--output", "json")...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("aws %s failed: %w: %s", strings.Join(args[:2], " "), err, strings.TrimSpace(stderr.String()))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(stdout.Bytes(), out)
+}
+
+func (p *route53Provider) zoneID(ctx context.Context, name string) (string, error) {
+	for _, domain := range parentDomains(name) {
+		var resp struct {
+			HostedZones []struct {
+				ID     string `json:"Id"`
+				Name   string `json:"Name"`
+				Config struct {
+					PrivateZone bool `json:"PrivateZone"`
+				} `json:"Config"`
+			} `json:"HostedZones"`
+		}
+		if err := p.aws(ctx, &resp, "route53", "list-hosted-zones-by-name", "--dns-name", domain); err != nil {
+			return "", err
+		}
+		for _, z := range resp.HostedZones {
+			if strings.TrimSuffix(z.Name, ".") == domain && !z.Config.PrivateZone {
+				return strings.TrimPrefix(z.ID, "/hostedzone/"), nil
+			}
+		}
+	}
+	return "", fmt.Errorf("no Route53 hosted zone found for %s", name)
+}
+
+func (p *route53Provider) UpsertRecord(ctx context.Context, record dnsRecord) error {
+	zoneID, err := p.zoneID(ctx, record.Name)
+	if err != nil {
+		return err
+	}
+
+	batch, err := json.Marshal(map[string]interface{}{
+		"Changes": []interface{}{
+			map[string]interface{}{
+				"Action": "UPSERT",
+				"ResourceRecordSet": map[string]interface{}{
+					"Name":            record.Name,
+					"Type":            record.Type,
+					"TTL":             300,
+					"ResourceRecords": []interface{}{map[string]string{"Value": record.Content}},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return p.aws(ctx, nil, "route53", "change-resource-record-sets", "--hosted-zone-id", zoneID, "--change-batch", string(batch))
+}
+
+type dnsimpleProvider struct {
+	token   string
+	account string
+}
+
+const dnsimpleAPI = "https://api.dnsimple.com/v2"
+
+func (p *dnsimpleProvider) do(ctx context.Context, method, path string, in, out interface{}) error {
+	return doJSON(ctx, method, dnsimpleAPI+path, map[string]string{"Authorization": "Bearer " + p.token}, in, out)
+}
+
+func (p *dnsimpleProvider) accountID(ctx context.Context) (string, error) {
+	if p.account != "" {
+		return p.account, nil
+	}
+
+	var resp struct {
+		Data struct {
+			Account *struct {
+				ID int `json:"id"`
+			} `json:"account"`
+		} `json:"data"`
+	}
+	if err := p.do(ctx, http.MethodGet, "/whoami", nil, &resp); err != nil {
+		return "", err
+	}
+	if resp.Data.Account == nil {
+		return "", errors.New("DNSIMPLE_TOKEN must be an account token")
+	}
+	p.account = fmt.Sprint(resp.Data.Account.ID)
+	return p.account, nil
+}
+
+func (p *dnsimpleProvider) UpsertRecord(ctx context.Context, record dnsRecord) error {
+	account, err := p.accountID(ctx)
+	if err != nil {
+		return err
+	}
+
+	zone := ""
+	for _, domain := range parentDomains(record.Name) {
+		if err := p.do(ctx, http.MethodGet, "/"+account+"/zones/"+domain, nil, nil); err == nil {
+			zone = domain
+			break
+		}
+	}
+	if zone == "" {
+		return fmt.Errorf("no DNSimple zone found for %s", record.Name)
+	}
+
+	// names are relative to the zone, and empty for the apex
+	name := strings.TrimSuffix(strings.TrimSuffix(record.Name, zone), ".")
+
+	var existing struct {
+		Data []struct {
+			ID int `json:"id"`
+		} `json:"data"`
+	}
+	query := url.Values{"name": {name}, "type": {record.Type}}
+	path := "/" + account + "/zones/" + zone + "/records"
+	if err := p.do(ctx, http.MethodGet, path+"?"+query.Encode(), nil, &existing); err != nil {
+		return err
+	}
+
+	if len(existing.Data) > 0 {
+		return p.do(ctx, http.MethodPatch, fmt.Sprintf("%s/%d", path, existing.Data[0].ID), map[string]interface{}{"content": record.Content}, nil)
+	}
+	return p.do(ctx, http.MethodPost, path, map[string]interface{}{
+		"name":    name,
+		"type":    record.Type,
+		"content": record.Content,
+		"ttl":     300,
+	}, nil)
+}
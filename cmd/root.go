@@ -12,7 +12,9 @@ import (
 	"github.com/superfly/flyctl/client"
 	"github.com/superfly/flyctl/docstrings"
 	"github.com/superfly/flyctl/flyctl"
+	"github.com/superfly/flyctl/internal/flag"
 	"github.com/superfly/flyctl/internal/flyerr"
+	"github.com/superfly/flyctl/internal/render"
 )
 
 // BUG(tqbf): this code is called by root.New() in internal/command/root/root.go; we're apparently
@@ -40,6 +42,8 @@ func NewRootCmd(client *client.Client) *cobra.Command {
 	err = viper.BindPFlag(flyctl.ConfigVerboseOutput, rootCmd.PersistentFlags().Lookup("verbose"))
 	checkErr(err)
 
+	rootCmd.PersistentFlags().String("profile", "", "Use the token, default organization and config directory of this profile, also set by FLY_PROFILE")
+
 	rootCmd.PersistentFlags().String("builtinsfile", "", "Load builtins from named file")
 	err = viper.BindPFlag(flyctl.ConfigBuiltinsfile, rootCmd.PersistentFlags().Lookup("builtinsfile"))
 	checkErr(err)
@@ -47,6 +51,12 @@ func NewRootCmd(client *client.Client) *cobra.Command {
 	err = rootCmd.PersistentFlags().MarkHidden("builtinsfile")
 	checkErr(err)
 
+	rootCmd.PersistentFlags().String(flag.OutputName, "", render.FormatUsage)
+
+	rootCmd.PersistentFlags().Bool(flag.DryRunName, false, "Print the API changes the command would make instead of making them")
+
+	rootCmd.PersistentFlags().String(flag.AnswersFileName, "", "Answer interactive prompts from this JSON file, or from stdin with '-'")
+
 	rootCmd.SetHelpCommand(&cobra.Command{
 		Use:    "no-help",
 		Hidden: true,
@@ -56,7 +66,6 @@ func NewRootCmd(client *client.Client) *cobra.Command {
 		newCertificatesCommand(client),
 		newDashboardCommand(client),
 		newRegionsCommand(client),
-		newAutoscaleCommand(client),
 		newDNSCommand(client),
 		newDomainsCommand(client),
 		newWireGuardCommand(client),
@@ -39,7 +39,10 @@ func newWireGuardCommand(client *client.Client) *Command {
 	list.Args = cobra.MaximumNArgs(1)
 	list.AddBoolFlag(BoolFlagOpts{Name: "json", Shorthand: "j", Description: "JSON output"})
 
-	child(cmd, runWireGuardCreate, "wireguard.create").Args = cobra.MaximumNArgs(4)
+	create := child(cmd, runWireGuardCreate, "wireguard.create")
+	create.Args = cobra.MaximumNArgs(4)
+	create.AddBoolFlag(BoolFlagOpts{Name: "qr", Description: "Print the configuration as a QR code, for the WireGuard mobile apps"})
+	create.AddStringFlag(StringFlagOpts{Name: "format", Description: "Format of the configuration file: conf, or mobileconfig for iOS", Default: "conf"})
 	child(cmd, runWireGuardRemove, "wireguard.remove").Args = cobra.MaximumNArgs(2)
 	child(cmd, runWireGuardStat, "wireguard.status").Args = cobra.MaximumNArgs(2)
 	child(cmd, runWireGuardResetPeer, "wireguard.reset").Args = cobra.MaximumNArgs(1)
@@ -281,6 +284,11 @@ func runWireGuardCreate(ctx *cmdctx.CmdContext) error {
 		name = ctx.Args[2]
 	}
 
+	format := ctx.Config.GetString("format")
+	if format != "conf" && format != "mobileconfig" {
+		return fmt.Errorf("invalid format %q, must be conf or mobileconfig", format)
+	}
+
 	state, err := wireguard.Create(ctx.Client.API(), org, region, name)
 	if err != nil {
 		return err
@@ -294,6 +302,26 @@ func runWireGuardCreate(ctx *cmdctx.CmdContext) error {
 !!!! and re-add the peering connection.                                     !!!!
 `)
 
+	var conf bytes.Buffer
+	generateWgConf(data, state.LocalPrivate, &conf)
+
+	if ctx.Config.GetBool("qr") {
+		fmt.Println("\nScan the QR code with the WireGuard app to add the tunnel:")
+		if err := writeWgQR(os.Stdout, conf.Bytes()); err != nil {
+			return err
+		}
+
+		// the QR code is the configuration unless a file is asked for too
+		if len(ctx.Args) < 4 {
+			return nil
+		}
+	}
+
+	out := conf.Bytes()
+	if format == "mobileconfig" {
+		out = generateMobileConfig(state.Name, org.Slug, net.JoinHostPort(data.Endpointip, "51820"), out)
+	}
+
 	w, shouldClose, err := resolveOutputWriter(ctx, 3, "Filename to store WireGuard configuration in, or 'stdout': ")
 	if err != nil {
 		return err
@@ -302,7 +330,9 @@ func runWireGuardCreate(ctx *cmdctx.CmdContext) error {
 		defer w.Close()
 	}
 
-	generateWgConf(data, state.LocalPrivate, w)
+	if _, err := w.Write(out); err != nil {
+		return err
+	}
 
 	if shouldClose {
 		filename := w.(*os.File).Name()
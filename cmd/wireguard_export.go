@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/skip2/go-qrcode"
+)
+
+// writeWgQR writes the WireGuard configuration as a QR code drawn with text,
+// for the WireGuard mobile apps to scan.
+func writeWgQR(w io.Writer, conf []byte) error {
+	qr, err := qrcode.New(strings.TrimSpace(string(conf)), qrcode.Low)
+	if err != nil {
+		return fmt.Errorf("failed encoding QR code: %w", err)
+	}
+
+	_, err = io.WriteString(w, qr.ToSmallString(false))
+	return err
+}
+
+// generateMobileConfig wraps the WireGuard configuration in an Apple
+// configuration profile, which installs the tunnel of the WireGuard app on
+// iOS devices.
+func generateMobileConfig(name, org, endpoint string, conf []byte) []byte {
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	b.WriteString(`<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">` + "\n")
+
+	escape := func(s string) string {
+		var e bytes.Buffer
+		_ = xml.EscapeText(&e, []byte(s))
+		return e.String()
+	}
+
+	profileID := "io.fly.wireguard." + org + "." + name
+
+	fmt.Fprintf(&b, `<plist version="1.0">
+<dict>
+	<key>PayloadDisplayName</key>
+	<string>Fly.io %[1]s (%[2]s)</string>
+	<key>PayloadType</key>
+	<string>Configuration</string>
+	<key>PayloadVersion</key>
+	<integer>1</integer>
+	<key>PayloadIdentifier</key>
+	<string>%[3]s</string>
+	<key>PayloadUUID</key>
+	<string>%[4]s</string>
+	<key>PayloadContent</key>
+	<array>
+		<dict>
+			<key>PayloadDisplayName</key>
+			<string>VPN</string>
+			<key>PayloadType</key>
+			<string>com.apple.vpn.managed</string>
+			<key>PayloadVersion</key>
+			<integer>1</integer>
+			<key>PayloadIdentifier</key>
+			<string>%[3]s.vpn</string>
+			<key>PayloadUUID</key>
+			<string>%[5]s</string>
+			<key>UserDefinedName</key>
+			<string>Fly.io %[1]s</string>
+			<key>VPNType</key>
+			<string>VPN</string>
+			<key>VPNSubType</key>
+			<string>com.wireguard.ios</string>
+			<key>VendorConfig</key>
+			<dict>
+				<key>WgQuickConfig</key>
+				<string>%[6]s</string>
+			</dict>
+			<key>VPN</key>
+			<dict>
+				<key>RemoteAddress</key>
+				<string>%[7]s</string>
+				<key>AuthenticationMethod</key>
+				<string>Password</string>
+			</dict>
+		</dict>
+	</array>
+</dict>
+</plist>
+`,
+		escape(org),
+		escape(name),
+		escape(profileID),
+		strings.ToUpper(uuid.NewString()),
+		strings.ToUpper(uuid.NewString()),
+		escape(strings.TrimSpace(string(conf))),
+		escape(endpoint),
+	)
+
+	return b.Bytes()
+}
@@ -115,25 +115,6 @@ independent of flyctl.`,
 			`Displays the users email address/service identity currently
 authenticated and in use.`,
 		}
-	case "autoscale":
-		return KeyStrings{"autoscale", "V1 APPS ONLY: Autoscaling app resources",
-			`V1 APPS ONLY: Autoscaling application resources`,
-		}
-	case "autoscale.disable":
-		return KeyStrings{"disable", "V1 APPS ONLY: Disable autoscaling",
-			`V1 APPS ONLY: Disable autoscaling to manually control app resources`,
-		}
-	case "autoscale.set":
-		return KeyStrings{"set", "Set app autoscaling parameters",
-			`V1 APPS ONLY: Enable autoscaling and set the application's autoscaling parameters:
-
-min=int - minimum number of instances to be allocated globally.
-max=int - maximum number of instances to be allocated globally.`,
-		}
-	case "autoscale.show":
-		return KeyStrings{"show", "V1 APPS ONLY: Show current autoscaling configuration",
-			`V1 APPS ONLY: Show current autoscaling configuration`,
-		}
 	case "builds":
 		return KeyStrings{"builds", "Work with Fly builds",
 			`Fly builds are templates to make developing Fly applications easier.`,
@@ -176,12 +157,27 @@ certificates issued for the hostname/domain by Let's Encrypt.`,
 	case "certs.add":
 		return KeyStrings{"add <hostname>", "Add a certificate for an app.",
 			`Add a certificate for an application. Takes a hostname
-as a parameter for the certificate.`,
+as a parameter for the certificate.
+
+Pass --wildcard to add a certificate for every subdomain of the hostname.
+Wildcard certificates are validated with a DNS-01 challenge, which requires
+a CNAME record for _acme-challenge.<hostname>. Pass --wait to wait until
+the certificate has been issued.
+
+Pass --dns-provider to create the DNS records with Cloudflare, Route53 or
+DNSimple and wait for issuance. Cloudflare reads its token from
+CLOUDFLARE_API_TOKEN, DNSimple from DNSIMPLE_TOKEN, and Route53 records are
+created with the aws CLI and its credentials.`,
 		}
 	case "certs.check":
-		return KeyStrings{"check <hostname>", "Checks DNS configuration",
+		return KeyStrings{"check [<hostname>]", "Checks DNS configuration",
 			`Checks the DNS configuration for the specified hostname.
-Displays results in the same format as the SHOW command.`,
+Displays results in the same format as the SHOW command.
+
+With --all, checks the certificates of every app of an organization instead,
+listing their validation state and expiry. The command fails when any
+certificate isn't issued, expires within --days days, or is a wildcard
+certificate missing its DNS-01 challenge record, for use in monitoring.`,
 		}
 	case "certs.list":
 		return KeyStrings{"list", "List certificates for an app.",
@@ -803,8 +799,12 @@ number to operate. This can be found through the volumes list command`,
 			`Commands that manage WireGuard peer connections`,
 		}
 	case "wireguard.create":
-		return KeyStrings{"create [org] [region] [name]", "Add a WireGuard peer connection",
-			`Add a WireGuard peer connection to an organization`,
+		return KeyStrings{"create [org] [region] [name] [file]", "Add a WireGuard peer connection",
+			`Add a WireGuard peer connection to an organization.
+
+With --qr, the configuration is printed as a QR code to scan with the WireGuard
+mobile apps. With --format mobileconfig, the configuration is written as an
+Apple configuration profile, which installs the tunnel on iOS devices.`,
 		}
 	case "wireguard.list":
 		return KeyStrings{"list [<org>]", "List all WireGuard peer connections",
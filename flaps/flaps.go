@@ -16,6 +16,7 @@ import (
 	"time"
 
 	"github.com/google/go-querystring/query"
+	"github.com/google/uuid"
 	"github.com/samber/lo"
 	"github.com/superfly/flyctl/internal/metrics"
 
@@ -26,6 +27,7 @@ import (
 	"github.com/superfly/flyctl/internal/buildinfo"
 	"github.com/superfly/flyctl/internal/logger"
 	"github.com/superfly/flyctl/terminal"
+	"golang.org/x/sync/errgroup"
 )
 
 var NonceHeader = "fly-machine-lease-nonce"
@@ -33,11 +35,12 @@ var NonceHeader = "fly-machine-lease-nonce"
 const headerFlyRequestId = "fly-request-id"
 
 type Client struct {
-	appName    string
-	baseUrl    *url.URL
-	authToken  string
-	httpClient *http.Client
-	userAgent  string
+	appName     string
+	baseUrl     *url.URL
+	authToken   string
+	httpClient  *http.Client
+	userAgent   string
+	retryPolicy RetryPolicy
 }
 
 func New(ctx context.Context, app *api.AppCompact) (*Client, error) {
@@ -77,20 +80,25 @@ func NewWithOptions(ctx context.Context, opts *NewClientOpts) (*Client, error) {
 	if err != nil {
 		return nil, fmt.Errorf("invalid FLY_FLAPS_BASE_URL '%s' with error: %w", flapsBaseURL, err)
 	}
+	retryPolicy, err := retryPolicyFromEnv()
+	if err != nil {
+		return nil, err
+	}
 	var logger api.Logger = logger.MaybeFromContext(ctx)
 	if opts.Logger != nil {
 		logger = opts.Logger
 	}
-	httpClient, err := api.NewHTTPClient(logger, http.DefaultTransport)
+	httpClient, err := api.NewNonRetryingHTTPClient(logger, http.DefaultTransport)
 	if err != nil {
 		return nil, fmt.Errorf("flaps: can't setup HTTP client to %s: %w", flapsUrl.String(), err)
 	}
 	return &Client{
-		appName:    appName,
-		baseUrl:    flapsUrl,
-		authToken:  flyctl.GetAPIToken(),
-		httpClient: httpClient,
-		userAgent:  strings.TrimSpace(fmt.Sprintf("fly-cli/%s", buildinfo.Version())),
+		appName:     appName,
+		baseUrl:     flapsUrl,
+		authToken:   flyctl.GetAPIToken(),
+		httpClient:  httpClient,
+		userAgent:   strings.TrimSpace(fmt.Sprintf("fly-cli/%s", buildinfo.Version())),
+		retryPolicy: retryPolicy,
 	}, nil
 }
 
@@ -106,6 +114,11 @@ func resolveApp(ctx context.Context, app *api.AppCompact, appName string) (*api.
 func newWithUsermodeWireguard(ctx context.Context, app *api.AppCompact) (*Client, error) {
 	logger := logger.MaybeFromContext(ctx)
 
+	retryPolicy, err := retryPolicyFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
 	client := client.FromContext(ctx).API()
 	agentclient, err := agent.Establish(ctx, client)
 	if err != nil {
@@ -123,7 +136,7 @@ func newWithUsermodeWireguard(ctx context.Context, app *api.AppCompact) (*Client
 		},
 	}
 
-	httpClient, err := api.NewHTTPClient(logger, transport)
+	httpClient, err := api.NewNonRetryingHTTPClient(logger, transport)
 	if err != nil {
 		return nil, fmt.Errorf("flaps: can't setup HTTP client for %s: %w", app.Organization.Slug, err)
 	}
@@ -135,14 +148,20 @@ func newWithUsermodeWireguard(ctx context.Context, app *api.AppCompact) (*Client
 	}
 
 	return &Client{
-		appName:    app.Name,
-		baseUrl:    flapsBaseUrl,
-		authToken:  flyctl.GetAPIToken(),
-		httpClient: httpClient,
-		userAgent:  strings.TrimSpace(fmt.Sprintf("fly-cli/%s", buildinfo.Version())),
+		appName:     app.Name,
+		baseUrl:     flapsBaseUrl,
+		authToken:   flyctl.GetAPIToken(),
+		httpClient:  httpClient,
+		userAgent:   strings.TrimSpace(fmt.Sprintf("fly-cli/%s", buildinfo.Version())),
+		retryPolicy: retryPolicy,
 	}, nil
 }
 
+// AppName returns the name of the app the client operates on.
+func (f *Client) AppName() string {
+	return f.appName
+}
+
 func (f *Client) CreateApp(ctx context.Context, name string, org string) (err error) {
 	in := map[string]interface{}{
 		"app_name": name,
@@ -170,7 +189,9 @@ func (f *Client) Launch(ctx context.Context, builder api.LaunchMachineInput) (ou
 		}
 	}()
 
-	if err := f.sendRequest(ctx, http.MethodPost, endpoint, builder, out, nil); err != nil {
+	// retried launches must not create several machines
+	headers := map[string][]string{IdempotencyKeyHeader: {uuid.NewString()}}
+	if err := f.sendRequest(ctx, http.MethodPost, endpoint, builder, out, headers); err != nil {
 		return nil, fmt.Errorf("failed to launch VM: %w", err)
 	}
 
@@ -178,7 +199,7 @@ func (f *Client) Launch(ctx context.Context, builder api.LaunchMachineInput) (ou
 }
 
 func (f *Client) Update(ctx context.Context, builder api.LaunchMachineInput, nonce string) (out *api.Machine, err error) {
-	headers := make(map[string][]string)
+	headers := map[string][]string{IdempotencyKeyHeader: {uuid.NewString()}}
 	if nonce != "" {
 		headers[NonceHeader] = []string{nonce}
 	}
@@ -307,15 +328,31 @@ func (f *Client) Get(ctx context.Context, machineID string) (*api.Machine, error
 	return out, nil
 }
 
+// MaxConcurrentRequests caps the requests callers fetching many machines at
+// once send concurrently.
+const MaxConcurrentRequests = 8
+
+// GetMany fetches the machines concurrently, returning them in the order of
+// their IDs. Errors are *MachineError naming the machine which couldn't be
+// fetched.
 func (f *Client) GetMany(ctx context.Context, machineIDs []string) ([]*api.Machine, error) {
-	machines := make([]*api.Machine, 0, len(machineIDs))
-	for _, id := range machineIDs {
-		m, err := f.Get(ctx, id)
-		if err != nil {
-			return machines, err
-		}
-		machines = append(machines, m)
+	machines := make([]*api.Machine, len(machineIDs))
+
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.SetLimit(MaxConcurrentRequests)
+	for i, id := range machineIDs {
+		i, id := i, id
+		eg.Go(func() (err error) {
+			if machines[i], err = f.Get(ctx, id); err != nil {
+				return &MachineError{MachineID: id, Err: err}
+			}
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
 	}
+
 	return machines, nil
 }
 
@@ -456,6 +493,37 @@ func (f *Client) ReleaseLease(ctx context.Context, machineID, nonce string) erro
 	return f.sendRequest(ctx, http.MethodDelete, endpoint, nil, nil, headers)
 }
 
+// SetMetadata sets a metadata key of the machine without updating it.
+func (f *Client) SetMetadata(ctx context.Context, machineID, key, value, nonce string) error {
+	endpoint := fmt.Sprintf("/%s/metadata/%s", machineID, url.PathEscape(key))
+
+	headers := make(map[string][]string)
+	if nonce != "" {
+		headers[NonceHeader] = []string{nonce}
+	}
+
+	in := map[string]string{"value": value}
+	if err := f.sendRequest(ctx, http.MethodPost, endpoint, in, nil, headers); err != nil {
+		return fmt.Errorf("failed to set metadata %s on VM %s: %w", key, machineID, err)
+	}
+	return nil
+}
+
+// DeleteMetadata removes a metadata key of the machine without updating it.
+func (f *Client) DeleteMetadata(ctx context.Context, machineID, key, nonce string) error {
+	endpoint := fmt.Sprintf("/%s/metadata/%s", machineID, url.PathEscape(key))
+
+	headers := make(map[string][]string)
+	if nonce != "" {
+		headers[NonceHeader] = []string{nonce}
+	}
+
+	if err := f.sendRequest(ctx, http.MethodDelete, endpoint, nil, nil, headers); err != nil {
+		return fmt.Errorf("failed to delete metadata %s on VM %s: %w", key, machineID, err)
+	}
+	return nil
+}
+
 func (f *Client) Exec(ctx context.Context, machineID string, in *api.MachineExecRequest) (*api.MachineExecResponse, error) {
 	endpoint := fmt.Sprintf("/%s/exec", machineID)
 
@@ -469,13 +537,20 @@ func (f *Client) Exec(ctx context.Context, machineID string, in *api.MachineExec
 }
 
 func (f *Client) sendRequest(ctx context.Context, method, endpoint string, in, out interface{}, headers map[string][]string) error {
-	req, err := f.NewRequest(ctx, method, endpoint, in, headers)
-	if err != nil {
-		return err
-	}
-	req.Header.Set("User-Agent", f.userAgent)
+	return f.send(ctx, method, fmt.Sprintf("/v1/apps/%s/machines%s", f.appName, endpoint), in, out, headers)
+}
 
-	resp, err := f.httpClient.Do(req)
+// send sends a request to path, relative to the root of the API rather than
+// to the machines of the app.
+func (f *Client) send(ctx context.Context, method, path string, in, out interface{}, headers map[string][]string) error {
+	resp, err := f.do(ctx, func() (*http.Request, error) {
+		req, err := f.newRequest(ctx, method, path, in, cloneHeaders(headers))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("User-Agent", f.userAgent)
+		return req, nil
+	})
 	if err != nil {
 		return err
 	}
@@ -506,6 +581,19 @@ func (f *Client) sendRequest(ctx context.Context, method, endpoint string, in, o
 	return nil
 }
 
+// cloneHeaders copies headers, which newRequest adds to, so that each attempt
+// of a request starts from the same ones.
+func cloneHeaders(headers map[string][]string) map[string][]string {
+	if headers == nil {
+		return nil
+	}
+	cloned := make(map[string][]string, len(headers))
+	for k, v := range headers {
+		cloned[k] = append([]string(nil), v...)
+	}
+	return cloned
+}
+
 func (f *Client) urlFromBaseUrl(pathAndQueryString string) (*url.URL, error) {
 	newUrl := *f.baseUrl // this does a copy: https://github.com/golang/go/issues/38351#issue-597797864
 	newPath, err := url.Parse(pathAndQueryString)
@@ -516,13 +604,17 @@ func (f *Client) urlFromBaseUrl(pathAndQueryString string) (*url.URL, error) {
 }
 
 func (f *Client) NewRequest(ctx context.Context, method, path string, in interface{}, headers map[string][]string) (*http.Request, error) {
+	return f.newRequest(ctx, method, fmt.Sprintf("/v1/apps/%s/machines%s", f.appName, path), in, headers)
+}
+
+func (f *Client) newRequest(ctx context.Context, method, path string, in interface{}, headers map[string][]string) (*http.Request, error) {
 	var body io.Reader
 
 	if headers == nil {
 		headers = make(map[string][]string)
 	}
 
-	targetEndpoint, err := f.urlFromBaseUrl(fmt.Sprintf("/v1/apps/%s/machines%s", f.appName, path))
+	targetEndpoint, err := f.urlFromBaseUrl(path)
 	if err != nil {
 		return nil, err
 	}
@@ -21,3 +21,18 @@ func (fe *FlapsError) Unwrap() error {
 func (fe *FlapsError) ResponseBodyString() string {
 	return string(fe.ResponseBody)
 }
+
+// MachineError is the error of an operation on one of many machines, naming
+// the machine it failed for.
+type MachineError struct {
+	MachineID string
+	Err       error
+}
+
+func (me *MachineError) Error() string {
+	return me.Err.Error()
+}
+
+func (me *MachineError) Unwrap() error {
+	return me.Err
+}
@@ -0,0 +1,48 @@
+package flaps
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/superfly/flyctl/api"
+)
+
+func TestGetMany(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/v1/apps/app/machines/")
+		if id == "missing" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"machine not found"}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(api.Machine{ID: id})
+	}))
+	defer server.Close()
+
+	baseURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	client := &Client{appName: "app", baseUrl: baseURL, httpClient: server.Client()}
+
+	ids := []string{"m5", "m1", "m4", "m2", "m3", "m9", "m7", "m8", "m6", "m10"}
+	machines, err := client.GetMany(context.Background(), ids)
+	require.NoError(t, err)
+	require.Len(t, machines, len(ids))
+	for i, m := range machines {
+		assert.Equal(t, ids[i], m.ID)
+	}
+
+	_, err = client.GetMany(context.Background(), []string{"m1", "missing", "m2"})
+	var machineErr *MachineError
+	require.True(t, errors.As(err, &machineErr))
+	assert.Equal(t, "missing", machineErr.MachineID)
+	assert.Contains(t, err.Error(), "machine not found")
+}
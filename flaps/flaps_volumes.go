@@ -0,0 +1,31 @@
+package flaps
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/superfly/flyctl/api"
+)
+
+func (f *Client) GetVolume(ctx context.Context, volumeID string) (*api.MachineVolume, error) {
+	endpoint := fmt.Sprintf("/v1/apps/%s/volumes/%s", f.appName, volumeID)
+
+	out := new(api.MachineVolume)
+
+	if err := f.send(ctx, http.MethodGet, endpoint, nil, out, nil); err != nil {
+		return nil, fmt.Errorf("failed to get volume %s: %w", volumeID, err)
+	}
+	return out, nil
+}
+
+func (f *Client) UpdateVolume(ctx context.Context, volumeID string, in api.UpdateVolumeRequest) (*api.MachineVolume, error) {
+	endpoint := fmt.Sprintf("/v1/apps/%s/volumes/%s", f.appName, volumeID)
+
+	out := new(api.MachineVolume)
+
+	if err := f.send(ctx, http.MethodPut, endpoint, in, out, nil); err != nil {
+		return nil, fmt.Errorf("failed to update volume %s: %w", volumeID, err)
+	}
+	return out, nil
+}
@@ -0,0 +1,211 @@
+package flaps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/superfly/flyctl/terminal"
+)
+
+// IdempotencyKeyHeader is the header identifying requests creating or updating
+// machines, so that flaps applies them once however many times they're retried.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// RetryPolicy determines how requests failing with transient errors, such as
+// 5xx responses and timeouts, are retried.
+type RetryPolicy struct {
+	// MaxRetries is the number of times a request is retried at most.
+	MaxRetries int
+
+	// InitialDelay is the delay before the first retry, which doubles with
+	// each following retry.
+	InitialDelay time.Duration
+
+	// MaxDelay caps the delay between retries.
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy is the policy of clients, unless set otherwise by the
+// environment.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries:   4,
+	InitialDelay: 500 * time.Millisecond,
+	MaxDelay:     8 * time.Second,
+}
+
+// The environment variables overriding the retry policy of clients.
+const (
+	retriesEnvKey       = "FLY_FLAPS_RETRIES"
+	retryDelayEnvKey    = "FLY_FLAPS_RETRY_DELAY"
+	retryMaxDelayEnvKey = "FLY_FLAPS_RETRY_MAX_DELAY"
+)
+
+// retryPolicyFromEnv returns DefaultRetryPolicy with the settings of the
+// environment applied, e.g. FLY_FLAPS_RETRIES=0 to disable retries.
+func retryPolicyFromEnv() (RetryPolicy, error) {
+	policy := DefaultRetryPolicy
+
+	if v := os.Getenv(retriesEnvKey); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return policy, fmt.Errorf("invalid %s '%s': expected a number of retries", retriesEnvKey, v)
+		}
+		policy.MaxRetries = n
+	}
+
+	for key, dst := range map[string]*time.Duration{
+		retryDelayEnvKey:    &policy.InitialDelay,
+		retryMaxDelayEnvKey: &policy.MaxDelay,
+	} {
+		v := os.Getenv(key)
+		if v == "" {
+			continue
+		}
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return policy, fmt.Errorf("invalid %s '%s': expected a duration such as 500ms", key, v)
+		}
+		*dst = d
+	}
+
+	if policy.MaxDelay < policy.InitialDelay {
+		policy.MaxDelay = policy.InitialDelay
+	}
+
+	return policy, nil
+}
+
+// delay returns the delay before the given retry, counting from 0, with a
+// jitter spreading the retries of concurrent requests.
+func (p RetryPolicy) delay(retry int) time.Duration {
+	d := p.InitialDelay
+	for i := 0; i < retry && d < p.MaxDelay; i++ {
+		d *= 2
+	}
+	if d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1)) // skipcq: GSC-G404
+}
+
+// retryable reports whether a request may be retried after failing with err or
+// with a response with the given status. Requests which aren't idempotent are
+// only retried when they carry an idempotency key.
+func retryable(req *http.Request, status int, err error) bool {
+	if !idempotent(req) {
+		return false
+	}
+
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false
+		}
+		var netErr net.Error
+		return (errors.As(err, &netErr) && netErr.Timeout()) ||
+			errors.Is(err, io.ErrUnexpectedEOF) ||
+			errors.Is(err, io.EOF) ||
+			errors.Is(err, syscall.ECONNRESET) ||
+			errors.Is(err, syscall.ECONNREFUSED)
+	}
+
+	switch status {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+func idempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return req.Header.Get(IdempotencyKeyHeader) != ""
+	}
+}
+
+// retryAfter returns the delay the Retry-After header asks for, given either
+// as a number of seconds or as an HTTP date, if any.
+func retryAfter(header http.Header, now time.Time) (time.Duration, bool) {
+	v := header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(v); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	at, err := http.ParseTime(v)
+	if err != nil {
+		return 0, false
+	}
+	if d := at.Sub(now); d > 0 {
+		return d, true
+	}
+	return 0, true
+}
+
+// do sends the request newRequest returns, retrying it according to the
+// policy of the client, or after the delay responses ask for with Retry-After.
+// Requests are built anew for each attempt since their bodies can only be read
+// once.
+func (f *Client) do(ctx context.Context, newRequest func() (*http.Request, error)) (*http.Response, error) {
+	for retry := 0; ; retry++ {
+		req, err := newRequest()
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := f.httpClient.Do(req)
+
+		var status int
+		if err == nil {
+			status = resp.StatusCode
+		}
+		if retry >= f.retryPolicy.MaxRetries || !retryable(req, status, err) {
+			return resp, err
+		}
+
+		delay := f.retryPolicy.delay(retry)
+		if err == nil {
+			if d, ok := retryAfter(resp.Header, time.Now()); ok {
+				delay = d
+			}
+
+			// drain the body so that the connection may be reused
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+			err = fmt.Errorf("status %d", status)
+		}
+
+		terminal.Debugf("flaps: retrying %s %s in %s after error: %v\n", req.Method, req.URL.Path, delay, err)
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package flaps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestRetryable(t *testing.T) {
+	withKey := func(req *http.Request) *http.Request {
+		req.Header.Set(IdempotencyKeyHeader, "key")
+		return req
+	}
+
+	cases := []struct {
+		name   string
+		req    *http.Request
+		status int
+		err    error
+		want   bool
+	}{
+		{name: "GET 503", req: httptest.NewRequest(http.MethodGet, "/", nil), status: 503, want: true},
+		{name: "GET 429", req: httptest.NewRequest(http.MethodGet, "/", nil), status: 429, want: true},
+		{name: "GET 404", req: httptest.NewRequest(http.MethodGet, "/", nil), status: 404},
+		{name: "GET 200", req: httptest.NewRequest(http.MethodGet, "/", nil), status: 200},
+		{name: "DELETE 502", req: httptest.NewRequest(http.MethodDelete, "/", nil), status: 502, want: true},
+		{name: "POST 503 without key", req: httptest.NewRequest(http.MethodPost, "/", nil), status: 503},
+		{name: "POST 503 with key", req: withKey(httptest.NewRequest(http.MethodPost, "/", nil)), status: 503, want: true},
+		{name: "GET timeout", req: httptest.NewRequest(http.MethodGet, "/", nil), err: &url.Error{Op: "Get", Err: timeoutError{}}, want: true},
+		{name: "GET connection reset", req: httptest.NewRequest(http.MethodGet, "/", nil), err: fmt.Errorf("read: %w", syscall.ECONNRESET), want: true},
+		{name: "GET EOF", req: httptest.NewRequest(http.MethodGet, "/", nil), err: io.ErrUnexpectedEOF, want: true},
+		{name: "GET canceled", req: httptest.NewRequest(http.MethodGet, "/", nil), err: context.Canceled},
+		{name: "GET other error", req: httptest.NewRequest(http.MethodGet, "/", nil), err: errors.New("boom")},
+		{name: "POST connection refused without key", req: httptest.NewRequest(http.MethodPost, "/", nil), err: syscall.ECONNREFUSED},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.want, retryable(c.req, c.status, c.err), c.name)
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	p := RetryPolicy{MaxRetries: 5, InitialDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	for retry, want := range []time.Duration{100, 200, 400, 800, 1000, 1000} {
+		want *= time.Millisecond
+		for i := 0; i < 20; i++ {
+			d := p.delay(retry)
+			assert.GreaterOrEqual(t, d, want/2, "retry %d", retry)
+			assert.LessOrEqual(t, d, want, "retry %d", retry)
+		}
+	}
+
+	assert.Zero(t, RetryPolicy{}.delay(3))
+}
+
+func TestRetryPolicyFromEnv(t *testing.T) {
+	policy, err := retryPolicyFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, DefaultRetryPolicy, policy)
+
+	t.Setenv(retriesEnvKey, "0")
+	t.Setenv(retryDelayEnvKey, "2s")
+	t.Setenv(retryMaxDelayEnvKey, "1s")
+	policy, err = retryPolicyFromEnv()
+	require.NoError(t, err)
+	// the maximum delay can't be lower than the initial one
+	assert.Equal(t, RetryPolicy{MaxRetries: 0, InitialDelay: 2 * time.Second, MaxDelay: 2 * time.Second}, policy)
+
+	for key, value := range map[string]string{
+		retriesEnvKey:       "-1",
+		retryDelayEnvKey:    "soon",
+		retryMaxDelayEnvKey: "-1s",
+	} {
+		t.Run(key+"="+value, func(t *testing.T) {
+			t.Setenv(key, value)
+			_, err := retryPolicyFromEnv()
+			assert.ErrorContains(t, err, "invalid "+key)
+		})
+	}
+
+	t.Setenv(retriesEnvKey, "many")
+	_, err = retryPolicyFromEnv()
+	assert.EqualError(t, err, "invalid FLY_FLAPS_RETRIES 'many': expected a number of retries")
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2023, 5, 4, 12, 0, 0, 0, time.UTC)
+	header := func(v string) http.Header { return http.Header{"Retry-After": []string{v}} }
+
+	_, ok := retryAfter(http.Header{}, now)
+	assert.False(t, ok)
+
+	d, ok := retryAfter(header("3"), now)
+	assert.True(t, ok)
+	assert.Equal(t, 3*time.Second, d)
+
+	d, ok = retryAfter(header(now.Add(90*time.Second).Format(http.TimeFormat)), now)
+	assert.True(t, ok)
+	assert.Equal(t, 90*time.Second, d)
+
+	d, ok = retryAfter(header(now.Add(-time.Minute).Format(http.TimeFormat)), now)
+	assert.True(t, ok)
+	assert.Zero(t, d)
+
+	_, ok = retryAfter(header("-1"), now)
+	assert.False(t, ok)
+	_, ok = retryAfter(header("later"), now)
+	assert.False(t, ok)
+}
+
+// newRetryTestClient returns a client of the server whose handler answers
+// with the statuses given in turn, then 200, counting the requests it gets.
+func newRetryTestClient(t *testing.T, policy RetryPolicy, statuses ...int) (*Client, *int32) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&requests, 1)
+		if int(n) <= len(statuses) {
+			if statuses[n-1] == http.StatusTooManyRequests {
+				w.Header().Set("Retry-After", "0")
+			}
+			w.WriteHeader(statuses[n-1])
+			fmt.Fprint(w, `{"error":"try again"}`)
+			return
+		}
+		fmt.Fprint(w, `{}`)
+	}))
+	t.Cleanup(server.Close)
+
+	baseURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	return &Client{
+		appName:     "app",
+		baseUrl:     baseURL,
+		httpClient:  server.Client(),
+		retryPolicy: policy,
+	}, &requests
+}
+
+var fastRetries = RetryPolicy{MaxRetries: 3, InitialDelay: time.Millisecond, MaxDelay: time.Millisecond}
+
+func TestClientRetriesServerErrors(t *testing.T) {
+	client, requests := newRetryTestClient(t, fastRetries, 503, 502)
+
+	err := client.sendRequest(context.Background(), http.MethodGet, "/m1", nil, &struct{}{}, nil)
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, *requests)
+}
+
+func TestClientGivesUpAfterMaxRetries(t *testing.T) {
+	client, requests := newRetryTestClient(t, fastRetries, 503, 503, 503, 503, 503)
+
+	err := client.sendRequest(context.Background(), http.MethodGet, "/m1", nil, nil, nil)
+	var flapsErr *FlapsError
+	require.ErrorAs(t, err, &flapsErr)
+	assert.Equal(t, http.StatusServiceUnavailable, flapsErr.ResponseStatusCode)
+	assert.EqualValues(t, 4, *requests)
+}
+
+func TestClientWithoutRetries(t *testing.T) {
+	client, requests := newRetryTestClient(t, RetryPolicy{}, 503)
+
+	err := client.sendRequest(context.Background(), http.MethodGet, "/m1", nil, nil, nil)
+	assert.Error(t, err)
+	assert.EqualValues(t, 1, *requests)
+}
+
+func TestClientRetriesPostsWithIdempotencyKeysOnly(t *testing.T) {
+	client, requests := newRetryTestClient(t, fastRetries, 503)
+	err := client.sendRequest(context.Background(), http.MethodPost, "/m1/stop", struct{}{}, nil, nil)
+	assert.Error(t, err)
+	assert.EqualValues(t, 1, *requests)
+
+	client, requests = newRetryTestClient(t, fastRetries, 503)
+	headers := map[string][]string{IdempotencyKeyHeader: {"key"}}
+	err = client.sendRequest(context.Background(), http.MethodPost, "/m1/stop", struct{}{}, nil, headers)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, *requests)
+}
+
+func TestClientHonoursRetryAfter(t *testing.T) {
+	// without Retry-After, the client would wait an hour
+	slow := RetryPolicy{MaxRetries: 1, InitialDelay: time.Hour, MaxDelay: time.Hour}
+	client, requests := newRetryTestClient(t, slow, http.StatusTooManyRequests)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := client.sendRequest(ctx, http.MethodGet, "/m1", nil, nil, nil)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, *requests)
+}
+
+func TestClientStopsRetryingWhenCanceled(t *testing.T) {
+	slow := RetryPolicy{MaxRetries: 3, InitialDelay: time.Hour, MaxDelay: time.Hour}
+	client, requests := newRetryTestClient(t, slow, 503)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	started := time.Now()
+	err := client.sendRequest(ctx, http.MethodGet, "/m1", nil, nil, nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(started), 5*time.Second)
+	assert.EqualValues(t, 1, *requests)
+}
@@ -5,7 +5,6 @@ import (
 	"io/ioutil"
 	"os"
 	"path"
-	"path/filepath"
 
 	"github.com/spf13/viper"
 	"github.com/superfly/flyctl/api"
@@ -19,7 +18,7 @@ var configDir string
 // InitConfig - Initialises config file for Viper
 func InitConfig() {
 	if err := initConfigDir(); err != nil {
-		fmt.Println("Error accessing config directory at $HOME/.fly", err)
+		fmt.Println("Error accessing config directory", err)
 		return
 	}
 
@@ -42,7 +41,7 @@ func initConfigDir() error {
 		return err
 	}
 
-	dir := filepath.Join(homeDir, ".fly")
+	dir := ProfileConfigDir(homeDir, Profile())
 
 	if !helpers.DirectoryExists(dir) {
 		if err := os.MkdirAll(dir, 0o700); err != nil {
@@ -0,0 +1,74 @@
+package flyctl
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	// ProfileEnvKey is the environment variable naming the profile in use.
+	// The --profile flag sets it so that the processes flyctl starts, like
+	// the agent, use the same profile.
+	ProfileEnvKey = "FLY_PROFILE"
+
+	// DefaultProfile is the name of the profile in use when none is selected.
+	DefaultProfile = "default"
+)
+
+var profileNameRE = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`)
+
+// ValidateProfileName returns an error if name can't be the name of a
+// profile.
+func ValidateProfileName(name string) error {
+	if !profileNameRE.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q, use letters, digits, dashes and underscores", name)
+	}
+
+	return nil
+}
+
+// Profile returns the name of the profile in use.
+func Profile() string {
+	if name := os.Getenv(ProfileEnvKey); name != "" {
+		return name
+	}
+
+	return DefaultProfile
+}
+
+// ProfilesDir returns the directory holding the config directories of the
+// profiles other than the default one.
+func ProfilesDir(homeDir string) string {
+	return filepath.Join(homeDir, ".fly", "profiles")
+}
+
+// ProfileConfigDir returns the config directory of the named profile. The
+// default profile uses the config directory of flyctl itself.
+func ProfileConfigDir(homeDir, name string) string {
+	if name == "" || name == DefaultProfile {
+		return filepath.Join(homeDir, ".fly")
+	}
+
+	return filepath.Join(ProfilesDir(homeDir), name)
+}
+
+// ProfileFromArgs returns the value of the --profile flag among the command
+// line arguments, which are parsed before commands as the profile determines
+// the config directory.
+func ProfileFromArgs(args []string) (name string, ok bool) {
+	for i, arg := range args {
+		switch {
+		case arg == "--":
+			return name, ok
+		case arg == "--profile" && i+1 < len(args):
+			name, ok = args[i+1], true
+		case strings.HasPrefix(arg, "--profile="):
+			name, ok = strings.TrimPrefix(arg, "--profile="), true
+		}
+	}
+
+	return name, ok
+}
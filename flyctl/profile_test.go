@@ -0,0 +1,37 @@
+package flyctl
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfileFromArgs(t *testing.T) {
+	name, ok := ProfileFromArgs([]string{"--profile", "work", "apps", "list"})
+	assert.True(t, ok)
+	assert.Equal(t, "work", name)
+
+	name, ok = ProfileFromArgs([]string{"apps", "list", "--profile=personal"})
+	assert.True(t, ok)
+	assert.Equal(t, "personal", name)
+
+	_, ok = ProfileFromArgs([]string{"ssh", "console", "--", "--profile", "work"})
+	assert.False(t, ok)
+
+	_, ok = ProfileFromArgs([]string{"apps", "list"})
+	assert.False(t, ok)
+}
+
+func TestProfileConfigDir(t *testing.T) {
+	assert.Equal(t, filepath.Join("/home/u", ".fly"), ProfileConfigDir("/home/u", DefaultProfile))
+	assert.Equal(t, filepath.Join("/home/u", ".fly"), ProfileConfigDir("/home/u", ""))
+	assert.Equal(t, filepath.Join("/home/u", ".fly", "profiles", "work"), ProfileConfigDir("/home/u", "work"))
+}
+
+func TestValidateProfileName(t *testing.T) {
+	assert.NoError(t, ValidateProfileName("client_a-2"))
+	assert.Error(t, ValidateProfileName(""))
+	assert.Error(t, ValidateProfileName("../etc"))
+	assert.Error(t, ValidateProfileName("-work"))
+}
@@ -53,6 +53,9 @@ type CreateClusterInput struct {
 	Autostart          bool
 	ScaleToZero        bool
 	ForkFrom           string
+	// RestoreConfig points the cluster at the WAL archive of another cluster
+	// to replay it up to a target, for point-in-time restores
+	RestoreConfig string
 }
 
 func NewLauncher(client *api.Client) *Launcher {
@@ -426,6 +429,10 @@ func (l *Launcher) setSecrets(ctx context.Context, config *CreateClusterInput) (
 		secrets["FLY_RESTORED_FROM"] = *config.SnapshotID
 	}
 
+	if config.RestoreConfig != "" {
+		secrets["S3_ARCHIVE_REMOTE_RESTORE_CONFIG"] = config.RestoreConfig
+	}
+
 	if config.ConsulURL == "" {
 		consulURL, err := l.generateConsulURL(ctx, config)
 		if err != nil {
@@ -165,6 +165,165 @@ type AgentGetInstancesResponse struct {
 // GetApp returns AgentGetInstancesResponse.App, and is useful for accessing the field via an interface.
 func (v *AgentGetInstancesResponse) GetApp() AgentGetInstancesApp { return v.App }
 
+// AppAuditLogApp includes the requested fields of the GraphQL type App.
+type AppAuditLogApp struct {
+	// Individual releases for this application, without any config processing
+	ReleasesUnprocessed AppAuditLogAppReleasesUnprocessedReleaseUnprocessedConnection `json:"releasesUnprocessed"`
+	// Secrets set on the application
+	Secrets  []AppAuditLogAppSecretsSecret           `json:"secrets"`
+	Machines AppAuditLogAppMachinesMachineConnection `json:"machines"`
+}
+
+// GetReleasesUnprocessed returns AppAuditLogApp.ReleasesUnprocessed, and is useful for accessing the field via an interface.
+func (v *AppAuditLogApp) GetReleasesUnprocessed() AppAuditLogAppReleasesUnprocessedReleaseUnprocessedConnection {
+	return v.ReleasesUnprocessed
+}
+
+// GetSecrets returns AppAuditLogApp.Secrets, and is useful for accessing the field via an interface.
+func (v *AppAuditLogApp) GetSecrets() []AppAuditLogAppSecretsSecret { return v.Secrets }
+
+// GetMachines returns AppAuditLogApp.Machines, and is useful for accessing the field via an interface.
+func (v *AppAuditLogApp) GetMachines() AppAuditLogAppMachinesMachineConnection { return v.Machines }
+
+// AppAuditLogAppMachinesMachineConnection includes the requested fields of the GraphQL type MachineConnection.
+// The GraphQL type's documentation follows.
+//
+// The connection type for Machine.
+type AppAuditLogAppMachinesMachineConnection struct {
+	// A list of nodes.
+	Nodes []AppAuditLogAppMachinesMachineConnectionNodesMachine `json:"nodes"`
+}
+
+// GetNodes returns AppAuditLogAppMachinesMachineConnection.Nodes, and is useful for accessing the field via an interface.
+func (v *AppAuditLogAppMachinesMachineConnection) GetNodes() []AppAuditLogAppMachinesMachineConnectionNodesMachine {
+	return v.Nodes
+}
+
+// AppAuditLogAppMachinesMachineConnectionNodesMachine includes the requested fields of the GraphQL type Machine.
+type AppAuditLogAppMachinesMachineConnectionNodesMachine struct {
+	Id        string    `json:"id"`
+	Name      string    `json:"name"`
+	Region    string    `json:"region"`
+	State     string    `json:"state"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// GetId returns AppAuditLogAppMachinesMachineConnectionNodesMachine.Id, and is useful for accessing the field via an interface.
+func (v *AppAuditLogAppMachinesMachineConnectionNodesMachine) GetId() string { return v.Id }
+
+// GetName returns AppAuditLogAppMachinesMachineConnectionNodesMachine.Name, and is useful for accessing the field via an interface.
+func (v *AppAuditLogAppMachinesMachineConnectionNodesMachine) GetName() string { return v.Name }
+
+// GetRegion returns AppAuditLogAppMachinesMachineConnectionNodesMachine.Region, and is useful for accessing the field via an interface.
+func (v *AppAuditLogAppMachinesMachineConnectionNodesMachine) GetRegion() string { return v.Region }
+
+// GetState returns AppAuditLogAppMachinesMachineConnectionNodesMachine.State, and is useful for accessing the field via an interface.
+func (v *AppAuditLogAppMachinesMachineConnectionNodesMachine) GetState() string { return v.State }
+
+// GetUpdatedAt returns AppAuditLogAppMachinesMachineConnectionNodesMachine.UpdatedAt, and is useful for accessing the field via an interface.
+func (v *AppAuditLogAppMachinesMachineConnectionNodesMachine) GetUpdatedAt() time.Time {
+	return v.UpdatedAt
+}
+
+// AppAuditLogAppReleasesUnprocessedReleaseUnprocessedConnection includes the requested fields of the GraphQL type ReleaseUnprocessedConnection.
+// The GraphQL type's documentation follows.
+//
+// The connection type for ReleaseUnprocessed.
+type AppAuditLogAppReleasesUnprocessedReleaseUnprocessedConnection struct {
+	// A list of nodes.
+	Nodes []AppAuditLogAppReleasesUnprocessedReleaseUnprocessedConnectionNodesReleaseUnprocessed `json:"nodes"`
+}
+
+// GetNodes returns AppAuditLogAppReleasesUnprocessedReleaseUnprocessedConnection.Nodes, and is useful for accessing the field via an interface.
+func (v *AppAuditLogAppReleasesUnprocessedReleaseUnprocessedConnection) GetNodes() []AppAuditLogAppReleasesUnprocessedReleaseUnprocessedConnectionNodesReleaseUnprocessed {
+	return v.Nodes
+}
+
+// AppAuditLogAppReleasesUnprocessedReleaseUnprocessedConnectionNodesReleaseUnprocessed includes the requested fields of the GraphQL type ReleaseUnprocessed.
+type AppAuditLogAppReleasesUnprocessedReleaseUnprocessedConnectionNodesReleaseUnprocessed struct {
+	// The version of the release
+	Version int `json:"version"`
+	// A description of the release
+	Description string `json:"description"`
+	// The status of the release
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"createdAt"`
+	// The user who created the release
+	User AppAuditLogAppReleasesUnprocessedReleaseUnprocessedConnectionNodesReleaseUnprocessedUser `json:"user"`
+}
+
+// GetVersion returns AppAuditLogAppReleasesUnprocessedReleaseUnprocessedConnectionNodesReleaseUnprocessed.Version, and is useful for accessing the field via an interface.
+func (v *AppAuditLogAppReleasesUnprocessedReleaseUnprocessedConnectionNodesReleaseUnprocessed) GetVersion() int {
+	return v.Version
+}
+
+// GetDescription returns AppAuditLogAppReleasesUnprocessedReleaseUnprocessedConnectionNodesReleaseUnprocessed.Description, and is useful for accessing the field via an interface.
+func (v *AppAuditLogAppReleasesUnprocessedReleaseUnprocessedConnectionNodesReleaseUnprocessed) GetDescription() string {
+	return v.Description
+}
+
+// GetStatus returns AppAuditLogAppReleasesUnprocessedReleaseUnprocessedConnectionNodesReleaseUnprocessed.Status, and is useful for accessing the field via an interface.
+func (v *AppAuditLogAppReleasesUnprocessedReleaseUnprocessedConnectionNodesReleaseUnprocessed) GetStatus() string {
+	return v.Status
+}
+
+// GetCreatedAt returns AppAuditLogAppReleasesUnprocessedReleaseUnprocessedConnectionNodesReleaseUnprocessed.CreatedAt, and is useful for accessing the field via an interface.
+func (v *AppAuditLogAppReleasesUnprocessedReleaseUnprocessedConnectionNodesReleaseUnprocessed) GetCreatedAt() time.Time {
+	return v.CreatedAt
+}
+
+// GetUser returns AppAuditLogAppReleasesUnprocessedReleaseUnprocessedConnectionNodesReleaseUnprocessed.User, and is useful for accessing the field via an interface.
+func (v *AppAuditLogAppReleasesUnprocessedReleaseUnprocessedConnectionNodesReleaseUnprocessed) GetUser() AppAuditLogAppReleasesUnprocessedReleaseUnprocessedConnectionNodesReleaseUnprocessedUser {
+	return v.User
+}
+
+// AppAuditLogAppReleasesUnprocessedReleaseUnprocessedConnectionNodesReleaseUnprocessedUser includes the requested fields of the GraphQL type User.
+type AppAuditLogAppReleasesUnprocessedReleaseUnprocessedConnectionNodesReleaseUnprocessedUser struct {
+	// Email address for user (private)
+	Email string `json:"email"`
+}
+
+// GetEmail returns AppAuditLogAppReleasesUnprocessedReleaseUnprocessedConnectionNodesReleaseUnprocessedUser.Email, and is useful for accessing the field via an interface.
+func (v *AppAuditLogAppReleasesUnprocessedReleaseUnprocessedConnectionNodesReleaseUnprocessedUser) GetEmail() string {
+	return v.Email
+}
+
+// AppAuditLogAppSecretsSecret includes the requested fields of the GraphQL type Secret.
+type AppAuditLogAppSecretsSecret struct {
+	// The name of the secret
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"createdAt"`
+	// The user who initiated the deployment
+	User AppAuditLogAppSecretsSecretUser `json:"user"`
+}
+
+// GetName returns AppAuditLogAppSecretsSecret.Name, and is useful for accessing the field via an interface.
+func (v *AppAuditLogAppSecretsSecret) GetName() string { return v.Name }
+
+// GetCreatedAt returns AppAuditLogAppSecretsSecret.CreatedAt, and is useful for accessing the field via an interface.
+func (v *AppAuditLogAppSecretsSecret) GetCreatedAt() time.Time { return v.CreatedAt }
+
+// GetUser returns AppAuditLogAppSecretsSecret.User, and is useful for accessing the field via an interface.
+func (v *AppAuditLogAppSecretsSecret) GetUser() AppAuditLogAppSecretsSecretUser { return v.User }
+
+// AppAuditLogAppSecretsSecretUser includes the requested fields of the GraphQL type User.
+type AppAuditLogAppSecretsSecretUser struct {
+	// Email address for user (private)
+	Email string `json:"email"`
+}
+
+// GetEmail returns AppAuditLogAppSecretsSecretUser.Email, and is useful for accessing the field via an interface.
+func (v *AppAuditLogAppSecretsSecretUser) GetEmail() string { return v.Email }
+
+// AppAuditLogResponse is returned by AppAuditLog on success.
+type AppAuditLogResponse struct {
+	// Find an app by name
+	App AppAuditLogApp `json:"app"`
+}
+
+// GetApp returns AppAuditLogResponse.App, and is useful for accessing the field via an interface.
+func (v *AppAuditLogResponse) GetApp() AppAuditLogApp { return v.App }
+
 // AppData includes the GraphQL fields of App requested by the fragment AppData.
 type AppData struct {
 	// Unique application ID
@@ -1451,6 +1610,84 @@ type ListAddOnsResponse struct {
 // GetAddOns returns ListAddOnsResponse.AddOns, and is useful for accessing the field via an interface.
 func (v *ListAddOnsResponse) GetAddOns() ListAddOnsAddOnsAddOnConnection { return v.AddOns }
 
+// ListAppTokensApp includes the requested fields of the GraphQL type App.
+type ListAppTokensApp struct {
+	LimitedAccessTokens ListAppTokensAppLimitedAccessTokensLimitedAccessTokenConnection `json:"limitedAccessTokens"`
+}
+
+// GetLimitedAccessTokens returns ListAppTokensApp.LimitedAccessTokens, and is useful for accessing the field via an interface.
+func (v *ListAppTokensApp) GetLimitedAccessTokens() ListAppTokensAppLimitedAccessTokensLimitedAccessTokenConnection {
+	return v.LimitedAccessTokens
+}
+
+// ListAppTokensAppLimitedAccessTokensLimitedAccessTokenConnection includes the requested fields of the GraphQL type LimitedAccessTokenConnection.
+// The GraphQL type's documentation follows.
+//
+// The connection type for LimitedAccessToken.
+type ListAppTokensAppLimitedAccessTokensLimitedAccessTokenConnection struct {
+	// A list of nodes.
+	Nodes []ListAppTokensAppLimitedAccessTokensLimitedAccessTokenConnectionNodesLimitedAccessToken `json:"nodes"`
+}
+
+// GetNodes returns ListAppTokensAppLimitedAccessTokensLimitedAccessTokenConnection.Nodes, and is useful for accessing the field via an interface.
+func (v *ListAppTokensAppLimitedAccessTokensLimitedAccessTokenConnection) GetNodes() []ListAppTokensAppLimitedAccessTokensLimitedAccessTokenConnectionNodesLimitedAccessToken {
+	return v.Nodes
+}
+
+// ListAppTokensAppLimitedAccessTokensLimitedAccessTokenConnectionNodesLimitedAccessToken includes the requested fields of the GraphQL type LimitedAccessToken.
+type ListAppTokensAppLimitedAccessTokensLimitedAccessTokenConnectionNodesLimitedAccessToken struct {
+	Id        string                                                                                     `json:"id"`
+	Name      string                                                                                     `json:"name"`
+	CreatedAt time.Time                                                                                  `json:"createdAt"`
+	ExpiresAt time.Time                                                                                  `json:"expiresAt"`
+	User      ListAppTokensAppLimitedAccessTokensLimitedAccessTokenConnectionNodesLimitedAccessTokenUser `json:"user"`
+}
+
+// GetId returns ListAppTokensAppLimitedAccessTokensLimitedAccessTokenConnectionNodesLimitedAccessToken.Id, and is useful for accessing the field via an interface.
+func (v *ListAppTokensAppLimitedAccessTokensLimitedAccessTokenConnectionNodesLimitedAccessToken) GetId() string {
+	return v.Id
+}
+
+// GetName returns ListAppTokensAppLimitedAccessTokensLimitedAccessTokenConnectionNodesLimitedAccessToken.Name, and is useful for accessing the field via an interface.
+func (v *ListAppTokensAppLimitedAccessTokensLimitedAccessTokenConnectionNodesLimitedAccessToken) GetName() string {
+	return v.Name
+}
+
+// GetCreatedAt returns ListAppTokensAppLimitedAccessTokensLimitedAccessTokenConnectionNodesLimitedAccessToken.CreatedAt, and is useful for accessing the field via an interface.
+func (v *ListAppTokensAppLimitedAccessTokensLimitedAccessTokenConnectionNodesLimitedAccessToken) GetCreatedAt() time.Time {
+	return v.CreatedAt
+}
+
+// GetExpiresAt returns ListAppTokensAppLimitedAccessTokensLimitedAccessTokenConnectionNodesLimitedAccessToken.ExpiresAt, and is useful for accessing the field via an interface.
+func (v *ListAppTokensAppLimitedAccessTokensLimitedAccessTokenConnectionNodesLimitedAccessToken) GetExpiresAt() time.Time {
+	return v.ExpiresAt
+}
+
+// GetUser returns ListAppTokensAppLimitedAccessTokensLimitedAccessTokenConnectionNodesLimitedAccessToken.User, and is useful for accessing the field via an interface.
+func (v *ListAppTokensAppLimitedAccessTokensLimitedAccessTokenConnectionNodesLimitedAccessToken) GetUser() ListAppTokensAppLimitedAccessTokensLimitedAccessTokenConnectionNodesLimitedAccessTokenUser {
+	return v.User
+}
+
+// ListAppTokensAppLimitedAccessTokensLimitedAccessTokenConnectionNodesLimitedAccessTokenUser includes the requested fields of the GraphQL type User.
+type ListAppTokensAppLimitedAccessTokensLimitedAccessTokenConnectionNodesLimitedAccessTokenUser struct {
+	// Email address for user (private)
+	Email string `json:"email"`
+}
+
+// GetEmail returns ListAppTokensAppLimitedAccessTokensLimitedAccessTokenConnectionNodesLimitedAccessTokenUser.Email, and is useful for accessing the field via an interface.
+func (v *ListAppTokensAppLimitedAccessTokensLimitedAccessTokenConnectionNodesLimitedAccessTokenUser) GetEmail() string {
+	return v.Email
+}
+
+// ListAppTokensResponse is returned by ListAppTokens on success.
+type ListAppTokensResponse struct {
+	// Find an app by name
+	App ListAppTokensApp `json:"app"`
+}
+
+// GetApp returns ListAppTokensResponse.App, and is useful for accessing the field via an interface.
+func (v *ListAppTokensResponse) GetApp() ListAppTokensApp { return v.App }
+
 // Autogenerated input type of LockApp
 type LockAppInput struct {
 	// The ID of the app
@@ -1602,6 +1839,125 @@ func (v *MigrateMachinesCreateReleaseResponse) GetCreateRelease() MigrateMachine
 	return v.CreateRelease
 }
 
+// OrgAuditLogOrganization includes the requested fields of the GraphQL type Organization.
+type OrgAuditLogOrganization struct {
+	Members     OrgAuditLogOrganizationMembersOrganizationMembershipsConnection    `json:"members"`
+	Invitations OrgAuditLogOrganizationInvitationsOrganizationInvitationConnection `json:"invitations"`
+}
+
+// GetMembers returns OrgAuditLogOrganization.Members, and is useful for accessing the field via an interface.
+func (v *OrgAuditLogOrganization) GetMembers() OrgAuditLogOrganizationMembersOrganizationMembershipsConnection {
+	return v.Members
+}
+
+// GetInvitations returns OrgAuditLogOrganization.Invitations, and is useful for accessing the field via an interface.
+func (v *OrgAuditLogOrganization) GetInvitations() OrgAuditLogOrganizationInvitationsOrganizationInvitationConnection {
+	return v.Invitations
+}
+
+// OrgAuditLogOrganizationInvitationsOrganizationInvitationConnection includes the requested fields of the GraphQL type OrganizationInvitationConnection.
+// The GraphQL type's documentation follows.
+//
+// The connection type for OrganizationInvitation.
+type OrgAuditLogOrganizationInvitationsOrganizationInvitationConnection struct {
+	// A list of nodes.
+	Nodes []OrgAuditLogOrganizationInvitationsOrganizationInvitationConnectionNodesOrganizationInvitation `json:"nodes"`
+}
+
+// GetNodes returns OrgAuditLogOrganizationInvitationsOrganizationInvitationConnection.Nodes, and is useful for accessing the field via an interface.
+func (v *OrgAuditLogOrganizationInvitationsOrganizationInvitationConnection) GetNodes() []OrgAuditLogOrganizationInvitationsOrganizationInvitationConnectionNodesOrganizationInvitation {
+	return v.Nodes
+}
+
+// OrgAuditLogOrganizationInvitationsOrganizationInvitationConnectionNodesOrganizationInvitation includes the requested fields of the GraphQL type OrganizationInvitation.
+type OrgAuditLogOrganizationInvitationsOrganizationInvitationConnectionNodesOrganizationInvitation struct {
+	CreatedAt time.Time `json:"createdAt"`
+	Email     string    `json:"email"`
+	// The user who created the invitation
+	Inviter OrgAuditLogOrganizationInvitationsOrganizationInvitationConnectionNodesOrganizationInvitationInviterUser `json:"inviter"`
+}
+
+// GetCreatedAt returns OrgAuditLogOrganizationInvitationsOrganizationInvitationConnectionNodesOrganizationInvitation.CreatedAt, and is useful for accessing the field via an interface.
+func (v *OrgAuditLogOrganizationInvitationsOrganizationInvitationConnectionNodesOrganizationInvitation) GetCreatedAt() time.Time {
+	return v.CreatedAt
+}
+
+// GetEmail returns OrgAuditLogOrganizationInvitationsOrganizationInvitationConnectionNodesOrganizationInvitation.Email, and is useful for accessing the field via an interface.
+func (v *OrgAuditLogOrganizationInvitationsOrganizationInvitationConnectionNodesOrganizationInvitation) GetEmail() string {
+	return v.Email
+}
+
+// GetInviter returns OrgAuditLogOrganizationInvitationsOrganizationInvitationConnectionNodesOrganizationInvitation.Inviter, and is useful for accessing the field via an interface.
+func (v *OrgAuditLogOrganizationInvitationsOrganizationInvitationConnectionNodesOrganizationInvitation) GetInviter() OrgAuditLogOrganizationInvitationsOrganizationInvitationConnectionNodesOrganizationInvitationInviterUser {
+	return v.Inviter
+}
+
+// OrgAuditLogOrganizationInvitationsOrganizationInvitationConnectionNodesOrganizationInvitationInviterUser includes the requested fields of the GraphQL type User.
+type OrgAuditLogOrganizationInvitationsOrganizationInvitationConnectionNodesOrganizationInvitationInviterUser struct {
+	// Email address for user (private)
+	Email string `json:"email"`
+}
+
+// GetEmail returns OrgAuditLogOrganizationInvitationsOrganizationInvitationConnectionNodesOrganizationInvitationInviterUser.Email, and is useful for accessing the field via an interface.
+func (v *OrgAuditLogOrganizationInvitationsOrganizationInvitationConnectionNodesOrganizationInvitationInviterUser) GetEmail() string {
+	return v.Email
+}
+
+// OrgAuditLogOrganizationMembersOrganizationMembershipsConnection includes the requested fields of the GraphQL type OrganizationMembershipsConnection.
+// The GraphQL type's documentation follows.
+//
+// The connection type for User.
+type OrgAuditLogOrganizationMembersOrganizationMembershipsConnection struct {
+	// A list of edges.
+	Edges []OrgAuditLogOrganizationMembersOrganizationMembershipsConnectionEdgesOrganizationMembershipsEdge `json:"edges"`
+}
+
+// GetEdges returns OrgAuditLogOrganizationMembersOrganizationMembershipsConnection.Edges, and is useful for accessing the field via an interface.
+func (v *OrgAuditLogOrganizationMembersOrganizationMembershipsConnection) GetEdges() []OrgAuditLogOrganizationMembersOrganizationMembershipsConnectionEdgesOrganizationMembershipsEdge {
+	return v.Edges
+}
+
+// OrgAuditLogOrganizationMembersOrganizationMembershipsConnectionEdgesOrganizationMembershipsEdge includes the requested fields of the GraphQL type OrganizationMembershipsEdge.
+// The GraphQL type's documentation follows.
+//
+// An edge in a connection.
+type OrgAuditLogOrganizationMembersOrganizationMembershipsConnectionEdgesOrganizationMembershipsEdge struct {
+	// The date the user joined the organization
+	JoinedAt time.Time `json:"joinedAt"`
+	// The item at the end of the edge.
+	Node OrgAuditLogOrganizationMembersOrganizationMembershipsConnectionEdgesOrganizationMembershipsEdgeNodeUser `json:"node"`
+}
+
+// GetJoinedAt returns OrgAuditLogOrganizationMembersOrganizationMembershipsConnectionEdgesOrganizationMembershipsEdge.JoinedAt, and is useful for accessing the field via an interface.
+func (v *OrgAuditLogOrganizationMembersOrganizationMembershipsConnectionEdgesOrganizationMembershipsEdge) GetJoinedAt() time.Time {
+	return v.JoinedAt
+}
+
+// GetNode returns OrgAuditLogOrganizationMembersOrganizationMembershipsConnectionEdgesOrganizationMembershipsEdge.Node, and is useful for accessing the field via an interface.
+func (v *OrgAuditLogOrganizationMembersOrganizationMembershipsConnectionEdgesOrganizationMembershipsEdge) GetNode() OrgAuditLogOrganizationMembersOrganizationMembershipsConnectionEdgesOrganizationMembershipsEdgeNodeUser {
+	return v.Node
+}
+
+// OrgAuditLogOrganizationMembersOrganizationMembershipsConnectionEdgesOrganizationMembershipsEdgeNodeUser includes the requested fields of the GraphQL type User.
+type OrgAuditLogOrganizationMembersOrganizationMembershipsConnectionEdgesOrganizationMembershipsEdgeNodeUser struct {
+	// Email address for user (private)
+	Email string `json:"email"`
+}
+
+// GetEmail returns OrgAuditLogOrganizationMembersOrganizationMembershipsConnectionEdgesOrganizationMembershipsEdgeNodeUser.Email, and is useful for accessing the field via an interface.
+func (v *OrgAuditLogOrganizationMembersOrganizationMembershipsConnectionEdgesOrganizationMembershipsEdgeNodeUser) GetEmail() string {
+	return v.Email
+}
+
+// OrgAuditLogResponse is returned by OrgAuditLog on success.
+type OrgAuditLogResponse struct {
+	// Find an organization by ID
+	Organization OrgAuditLogOrganization `json:"organization"`
+}
+
+// GetOrganization returns OrgAuditLogResponse.Organization, and is useful for accessing the field via an interface.
+func (v *OrgAuditLogResponse) GetOrganization() OrgAuditLogOrganization { return v.Organization }
+
 type PlatformVersionEnum string
 
 const (
@@ -1613,6 +1969,62 @@ const (
 	PlatformVersionEnumNomad PlatformVersionEnum = "nomad"
 )
 
+// PostgresAttachmentCountPostgresAttachmentsPostgresClusterAttachmentConnection includes the requested fields of the GraphQL type PostgresClusterAttachmentConnection.
+// The GraphQL type's documentation follows.
+//
+// The connection type for PostgresClusterAttachment.
+type PostgresAttachmentCountPostgresAttachmentsPostgresClusterAttachmentConnection struct {
+	TotalCount int `json:"totalCount"`
+}
+
+// GetTotalCount returns PostgresAttachmentCountPostgresAttachmentsPostgresClusterAttachmentConnection.TotalCount, and is useful for accessing the field via an interface.
+func (v *PostgresAttachmentCountPostgresAttachmentsPostgresClusterAttachmentConnection) GetTotalCount() int {
+	return v.TotalCount
+}
+
+// PostgresAttachmentCountResponse is returned by PostgresAttachmentCount on success.
+type PostgresAttachmentCountResponse struct {
+	// List postgres attachments
+	PostgresAttachments PostgresAttachmentCountPostgresAttachmentsPostgresClusterAttachmentConnection `json:"postgresAttachments"`
+}
+
+// GetPostgresAttachments returns PostgresAttachmentCountResponse.PostgresAttachments, and is useful for accessing the field via an interface.
+func (v *PostgresAttachmentCountResponse) GetPostgresAttachments() PostgresAttachmentCountPostgresAttachmentsPostgresClusterAttachmentConnection {
+	return v.PostgresAttachments
+}
+
+// PreviewAppActivityApp includes the requested fields of the GraphQL type App.
+type PreviewAppActivityApp struct {
+	CreatedAt time.Time `json:"createdAt"`
+	// The latest release of this application, without any config processing
+	CurrentReleaseUnprocessed PreviewAppActivityAppCurrentReleaseUnprocessed `json:"currentReleaseUnprocessed"`
+}
+
+// GetCreatedAt returns PreviewAppActivityApp.CreatedAt, and is useful for accessing the field via an interface.
+func (v *PreviewAppActivityApp) GetCreatedAt() time.Time { return v.CreatedAt }
+
+// GetCurrentReleaseUnprocessed returns PreviewAppActivityApp.CurrentReleaseUnprocessed, and is useful for accessing the field via an interface.
+func (v *PreviewAppActivityApp) GetCurrentReleaseUnprocessed() PreviewAppActivityAppCurrentReleaseUnprocessed {
+	return v.CurrentReleaseUnprocessed
+}
+
+// PreviewAppActivityAppCurrentReleaseUnprocessed includes the requested fields of the GraphQL type ReleaseUnprocessed.
+type PreviewAppActivityAppCurrentReleaseUnprocessed struct {
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// GetCreatedAt returns PreviewAppActivityAppCurrentReleaseUnprocessed.CreatedAt, and is useful for accessing the field via an interface.
+func (v *PreviewAppActivityAppCurrentReleaseUnprocessed) GetCreatedAt() time.Time { return v.CreatedAt }
+
+// PreviewAppActivityResponse is returned by PreviewAppActivity on success.
+type PreviewAppActivityResponse struct {
+	// Find an app by name
+	App PreviewAppActivityApp `json:"app"`
+}
+
+// GetApp returns PreviewAppActivityResponse.App, and is useful for accessing the field via an interface.
+func (v *PreviewAppActivityResponse) GetApp() PreviewAppActivityApp { return v.App }
+
 // ResetAddOnPasswordResetAddOnPasswordResetAddOnPasswordPayload includes the requested fields of the GraphQL type ResetAddOnPasswordPayload.
 // The GraphQL type's documentation follows.
 //
@@ -1708,6 +2120,29 @@ func (v *ResolverFinishBuildResponse) GetFinishBuild() ResolverFinishBuildFinish
 	return v.FinishBuild
 }
 
+// RevokeLimitedAccessTokenDeleteLimitedAccessTokenDeleteLimitedAccessTokenPayload includes the requested fields of the GraphQL type DeleteLimitedAccessTokenPayload.
+// The GraphQL type's documentation follows.
+//
+// Autogenerated return type of DeleteLimitedAccessToken
+type RevokeLimitedAccessTokenDeleteLimitedAccessTokenDeleteLimitedAccessTokenPayload struct {
+	Token string `json:"token"`
+}
+
+// GetToken returns RevokeLimitedAccessTokenDeleteLimitedAccessTokenDeleteLimitedAccessTokenPayload.Token, and is useful for accessing the field via an interface.
+func (v *RevokeLimitedAccessTokenDeleteLimitedAccessTokenDeleteLimitedAccessTokenPayload) GetToken() string {
+	return v.Token
+}
+
+// RevokeLimitedAccessTokenResponse is returned by RevokeLimitedAccessToken on success.
+type RevokeLimitedAccessTokenResponse struct {
+	DeleteLimitedAccessToken RevokeLimitedAccessTokenDeleteLimitedAccessTokenDeleteLimitedAccessTokenPayload `json:"deleteLimitedAccessToken"`
+}
+
+// GetDeleteLimitedAccessToken returns RevokeLimitedAccessTokenResponse.DeleteLimitedAccessToken, and is useful for accessing the field via an interface.
+func (v *RevokeLimitedAccessTokenResponse) GetDeleteLimitedAccessToken() RevokeLimitedAccessTokenDeleteLimitedAccessTokenDeleteLimitedAccessTokenPayload {
+	return v.DeleteLimitedAccessToken
+}
+
 type RuntimeType string
 
 const (
@@ -2173,6 +2608,14 @@ type __AgentGetInstancesInput struct {
 // GetAppName returns __AgentGetInstancesInput.AppName, and is useful for accessing the field via an interface.
 func (v *__AgentGetInstancesInput) GetAppName() string { return v.AppName }
 
+// __AppAuditLogInput is used internally by genqlient
+type __AppAuditLogInput struct {
+	AppName string `json:"appName"`
+}
+
+// GetAppName returns __AppAuditLogInput.AppName, and is useful for accessing the field via an interface.
+func (v *__AppAuditLogInput) GetAppName() string { return v.AppName }
+
 // __CreateAddOnInput is used internally by genqlient
 type __CreateAddOnInput struct {
 	Input CreateAddOnInput `json:"input"`
@@ -2289,6 +2732,14 @@ type __ListAddOnsInput struct {
 // GetAddOnType returns __ListAddOnsInput.AddOnType, and is useful for accessing the field via an interface.
 func (v *__ListAddOnsInput) GetAddOnType() AddOnType { return v.AddOnType }
 
+// __ListAppTokensInput is used internally by genqlient
+type __ListAppTokensInput struct {
+	AppName string `json:"appName"`
+}
+
+// GetAppName returns __ListAppTokensInput.AppName, and is useful for accessing the field via an interface.
+func (v *__ListAppTokensInput) GetAppName() string { return v.AppName }
+
 // __LockAppInput is used internally by genqlient
 type __LockAppInput struct {
 	Input LockAppInput `json:"input"`
@@ -2321,6 +2772,30 @@ type __MigrateMachinesCreateReleaseInput struct {
 // GetInput returns __MigrateMachinesCreateReleaseInput.Input, and is useful for accessing the field via an interface.
 func (v *__MigrateMachinesCreateReleaseInput) GetInput() CreateReleaseInput { return v.Input }
 
+// __OrgAuditLogInput is used internally by genqlient
+type __OrgAuditLogInput struct {
+	Slug string `json:"slug"`
+}
+
+// GetSlug returns __OrgAuditLogInput.Slug, and is useful for accessing the field via an interface.
+func (v *__OrgAuditLogInput) GetSlug() string { return v.Slug }
+
+// __PostgresAttachmentCountInput is used internally by genqlient
+type __PostgresAttachmentCountInput struct {
+	PostgresAppName string `json:"postgresAppName"`
+}
+
+// GetPostgresAppName returns __PostgresAttachmentCountInput.PostgresAppName, and is useful for accessing the field via an interface.
+func (v *__PostgresAttachmentCountInput) GetPostgresAppName() string { return v.PostgresAppName }
+
+// __PreviewAppActivityInput is used internally by genqlient
+type __PreviewAppActivityInput struct {
+	AppName string `json:"appName"`
+}
+
+// GetAppName returns __PreviewAppActivityInput.AppName, and is useful for accessing the field via an interface.
+func (v *__PreviewAppActivityInput) GetAppName() string { return v.AppName }
+
 // __ResetAddOnPasswordInput is used internally by genqlient
 type __ResetAddOnPasswordInput struct {
 	Name string `json:"name"`
@@ -2345,6 +2820,14 @@ type __ResolverFinishBuildInput struct {
 // GetInput returns __ResolverFinishBuildInput.Input, and is useful for accessing the field via an interface.
 func (v *__ResolverFinishBuildInput) GetInput() FinishBuildInput { return v.Input }
 
+// __RevokeLimitedAccessTokenInput is used internally by genqlient
+type __RevokeLimitedAccessTokenInput struct {
+	Token string `json:"token"`
+}
+
+// GetToken returns __RevokeLimitedAccessTokenInput.Token, and is useful for accessing the field via an interface.
+func (v *__RevokeLimitedAccessTokenInput) GetToken() string { return v.Token }
+
 // __SelfServiceSetPlatformVersionInput is used internally by genqlient
 type __SelfServiceSetPlatformVersionInput struct {
 	Input SetPlatformVersionInput `json:"input"`
@@ -2468,6 +2951,64 @@ query AgentGetInstances ($appName: String!) {
 	return &data, err
 }
 
+func AppAuditLog(
+	ctx context.Context,
+	client graphql.Client,
+	appName string,
+) (*AppAuditLogResponse, error) {
+	req := &graphql.Request{
+		OpName: "AppAuditLog",
+		Query: `
+query AppAuditLog ($appName: String!) {
+	app(name: $appName) {
+		releasesUnprocessed(first: 50) {
+			nodes {
+				version
+				description
+				status
+				createdAt
+				user {
+					email
+				}
+			}
+		}
+		secrets {
+			name
+			createdAt
+			user {
+				email
+			}
+		}
+		machines(active: false, first: 100) {
+			nodes {
+				id
+				name
+				region
+				state
+				updatedAt
+			}
+		}
+	}
+}
+`,
+		Variables: &__AppAuditLogInput{
+			AppName: appName,
+		},
+	}
+	var err error
+
+	var data AppAuditLogResponse
+	resp := &graphql.Response{Data: &data}
+
+	err = client.MakeRequest(
+		ctx,
+		req,
+		resp,
+	)
+
+	return &data, err
+}
+
 func CreateAddOn(
 	ctx context.Context,
 	client graphql.Client,
@@ -3021,6 +3562,48 @@ query ListAddOns ($addOnType: AddOnType) {
 	return &data, err
 }
 
+func ListAppTokens(
+	ctx context.Context,
+	client graphql.Client,
+	appName string,
+) (*ListAppTokensResponse, error) {
+	req := &graphql.Request{
+		OpName: "ListAppTokens",
+		Query: `
+query ListAppTokens ($appName: String!) {
+	app(name: $appName) {
+		limitedAccessTokens {
+			nodes {
+				id
+				name
+				createdAt
+				expiresAt
+				user {
+					email
+				}
+			}
+		}
+	}
+}
+`,
+		Variables: &__ListAppTokensInput{
+			AppName: appName,
+		},
+	}
+	var err error
+
+	var data ListAppTokensResponse
+	resp := &graphql.Response{Data: &data}
+
+	err = client.MakeRequest(
+		ctx,
+		req,
+		resp,
+	)
+
+	return &data, err
+}
+
 func LockApp(
 	ctx context.Context,
 	client graphql.Client,
@@ -3158,6 +3741,121 @@ mutation MigrateMachinesCreateRelease ($input: CreateReleaseInput!) {
 	return &data, err
 }
 
+func OrgAuditLog(
+	ctx context.Context,
+	client graphql.Client,
+	slug string,
+) (*OrgAuditLogResponse, error) {
+	req := &graphql.Request{
+		OpName: "OrgAuditLog",
+		Query: `
+query OrgAuditLog ($slug: String!) {
+	organization(slug: $slug) {
+		members {
+			edges {
+				joinedAt
+				node {
+					email
+				}
+			}
+		}
+		invitations {
+			nodes {
+				createdAt
+				email
+				inviter {
+					email
+				}
+			}
+		}
+	}
+}
+`,
+		Variables: &__OrgAuditLogInput{
+			Slug: slug,
+		},
+	}
+	var err error
+
+	var data OrgAuditLogResponse
+	resp := &graphql.Response{Data: &data}
+
+	err = client.MakeRequest(
+		ctx,
+		req,
+		resp,
+	)
+
+	return &data, err
+}
+
+func PostgresAttachmentCount(
+	ctx context.Context,
+	client graphql.Client,
+	postgresAppName string,
+) (*PostgresAttachmentCountResponse, error) {
+	req := &graphql.Request{
+		OpName: "PostgresAttachmentCount",
+		Query: `
+query PostgresAttachmentCount ($postgresAppName: String!) {
+	postgresAttachments(postgresAppName: $postgresAppName) {
+		totalCount
+	}
+}
+`,
+		Variables: &__PostgresAttachmentCountInput{
+			PostgresAppName: postgresAppName,
+		},
+	}
+	var err error
+
+	var data PostgresAttachmentCountResponse
+	resp := &graphql.Response{Data: &data}
+
+	err = client.MakeRequest(
+		ctx,
+		req,
+		resp,
+	)
+
+	return &data, err
+}
+
+func PreviewAppActivity(
+	ctx context.Context,
+	client graphql.Client,
+	appName string,
+) (*PreviewAppActivityResponse, error) {
+	req := &graphql.Request{
+		OpName: "PreviewAppActivity",
+		Query: `
+query PreviewAppActivity ($appName: String!) {
+	app(name: $appName) {
+		createdAt
+		currentReleaseUnprocessed {
+			createdAt
+		}
+	}
+}
+`,
+		Variables: &__PreviewAppActivityInput{
+			AppName: appName,
+		},
+	}
+	var err error
+
+	var data PreviewAppActivityResponse
+	resp := &graphql.Response{Data: &data}
+
+	err = client.MakeRequest(
+		ctx,
+		req,
+		resp,
+	)
+
+	return &data, err
+}
+
 func ResetAddOnPassword(
 	ctx context.Context,
 	client graphql.Client,
@@ -3259,6 +3957,38 @@ mutation ResolverFinishBuild ($input: FinishBuildInput!) {
 	return &data, err
 }
 
+func RevokeLimitedAccessToken(
+	ctx context.Context,
+	client graphql.Client,
+	token string,
+) (*RevokeLimitedAccessTokenResponse, error) {
+	req := &graphql.Request{
+		OpName: "RevokeLimitedAccessToken",
+		Query: `
+mutation RevokeLimitedAccessToken ($token: ID!) {
+	deleteLimitedAccessToken(input: {token:$token}) {
+		token
+	}
+}
+`,
+		Variables: &__RevokeLimitedAccessTokenInput{
+			Token: token,
+		},
+	}
+	var err error
+
+	var data RevokeLimitedAccessTokenResponse
+	resp := &graphql.Response{Data: &data}
+
+	err = client.MakeRequest(
+		ctx,
+		req,
+		resp,
+	)
+
+	return &data, err
+}
+
 func SelfServiceSetPlatformVersion(
 	ctx context.Context,
 	client graphql.Client,
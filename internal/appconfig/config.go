@@ -43,15 +43,18 @@ type Config struct {
 	Env          map[string]string `toml:"env,omitempty" json:"env,omitempty"`
 
 	// Fields that are process group aware must come after Processes
-	Processes   map[string]string         `toml:"processes,omitempty" json:"processes,omitempty"`
-	Mounts      []Mount                   `toml:"mounts,omitempty" json:"mounts,omitempty"`
-	HTTPService *HTTPService              `toml:"http_service,omitempty" json:"http_service,omitempty"`
-	Services    []Service                 `toml:"services,omitempty" json:"services,omitempty"`
-	Checks      map[string]*ToplevelCheck `toml:"checks,omitempty" json:"checks,omitempty"`
+	Processes      map[string]string         `toml:"processes,omitempty" json:"processes,omitempty"`
+	Mounts         []Mount                   `toml:"mounts,omitempty" json:"mounts,omitempty"`
+	HTTPService    *HTTPService              `toml:"http_service,omitempty" json:"http_service,omitempty"`
+	Services       []Service                 `toml:"services,omitempty" json:"services,omitempty"`
+	Checks         map[string]*ToplevelCheck `toml:"checks,omitempty" json:"checks,omitempty"`
+	Autoscaling    []Autoscaling             `toml:"autoscaling,omitempty" json:"autoscaling,omitempty"`
+	ScaleSchedules []ScaleSchedule           `toml:"scale_schedules,omitempty" json:"scale_schedules,omitempty"`
 
 	// Others, less important.
-	Statics []Static            `toml:"statics,omitempty" json:"statics,omitempty"`
-	Metrics *api.MachineMetrics `toml:"metrics,omitempty" json:"metrics,omitempty"`
+	Statics  []Static            `toml:"statics,omitempty" json:"statics,omitempty"`
+	Metrics  *api.MachineMetrics `toml:"metrics,omitempty" json:"metrics,omitempty"`
+	Bindings []Binding           `toml:"bindings,omitempty" json:"bindings,omitempty"`
 
 	// RawDefinition contains fly.toml parsed as-is
 	// If you add any config field that is v2 specific, be sure to remove it in SanitizeDefinition()
@@ -74,6 +77,34 @@ type Config struct {
 type Deploy struct {
 	ReleaseCommand string `toml:"release_command,omitempty" json:"release_command,omitempty"`
 	Strategy       string `toml:"strategy,omitempty" json:"strategy,omitempty"`
+
+	// WaitTimeout is how long to wait for machines to start during updates.
+	WaitTimeout *api.Duration `toml:"wait_timeout,omitempty" json:"wait_timeout,omitempty"`
+	// CheckTimeout is how long to wait for health checks to pass.
+	CheckTimeout *api.Duration `toml:"check_timeout,omitempty" json:"check_timeout,omitempty"`
+	// CheckGracePeriod is how long to let machines boot before requiring
+	// health checks to pass, for slow-booting apps.
+	CheckGracePeriod *api.Duration `toml:"check_grace_period,omitempty" json:"check_grace_period,omitempty"`
+	// SmokeChecks run once machines are updated. Deployments fail, and the
+	// machines updated in place are rolled back, when any of them fails.
+	SmokeChecks []SmokeCheck `toml:"smoke_checks,omitempty" json:"smoke_checks,omitempty"`
+}
+
+// SmokeCheck is either an HTTP request to the app, with Path set, or a command
+// run in an ephemeral machine of the new image, with Command set.
+type SmokeCheck struct {
+	Name string `toml:"name,omitempty" json:"name,omitempty"`
+	// Path is requested from https://<app>.fly.dev, unless it's a full URL.
+	Path    string            `toml:"path,omitempty" json:"path,omitempty"`
+	Method  string            `toml:"method,omitempty" json:"method,omitempty"`
+	Headers map[string]string `toml:"headers,omitempty" json:"headers,omitempty"`
+	// Status is the expected response status, 200 by default.
+	Status       int    `toml:"status,omitempty" json:"status,omitempty"`
+	BodyContains string `toml:"body_contains,omitempty" json:"body_contains,omitempty"`
+	// Command must exit with status 0.
+	Command string `toml:"command,omitempty" json:"command,omitempty"`
+	// Timeout bounds how long the check may take to pass, retries included.
+	Timeout *api.Duration `toml:"timeout,omitempty" json:"timeout,omitempty"`
 }
 
 type Static struct {
@@ -81,6 +112,39 @@ type Static struct {
 	UrlPrefix string `toml:"url_prefix" json:"url_prefix,omitempty" validate:"required"`
 }
 
+// Binding is a connection string template other apps may attach to with
+// `fly attach`. See internal/command/attach for the template data.
+type Binding struct {
+	Name     string `toml:"name" json:"name"`
+	Template string `toml:"template" json:"template"`
+	Secret   string `toml:"secret,omitempty" json:"secret,omitempty"`
+}
+
+// Autoscaling bounds the number of machines of process groups in every region
+// they run in. Deployments launch or destroy machines to stay within the
+// bounds, and the proxy starts and stops the machines in between to keep their
+// load around the target concurrency. With a CPU target, deployments also size
+// every region so that the average CPU usage of its machines gets close to it.
+type Autoscaling struct {
+	MinMachines       int      `toml:"min_machines" json:"min_machines"`
+	MaxMachines       int      `toml:"max_machines" json:"max_machines"`
+	TargetConcurrency int      `toml:"target_concurrency,omitempty" json:"target_concurrency,omitempty"`
+	TargetCPUPercent  int      `toml:"target_cpu_percent,omitempty" json:"target_cpu_percent,omitempty"`
+	Processes         []string `toml:"processes,omitempty" json:"processes,omitempty"`
+}
+
+// ScaleSchedule is a daily window during which the machines of process groups
+// are stopped, applied by `fly scale schedule apply`. Stop and Start are times
+// of day formatted as HH:MM; the window spans midnight when Start is earlier
+// than Stop. Days restricts the days the window begins on, e.g. ["mon", "fri"].
+type ScaleSchedule struct {
+	Stop      string   `toml:"stop" json:"stop"`
+	Start     string   `toml:"start" json:"start"`
+	Days      []string `toml:"days,omitempty" json:"days,omitempty"`
+	Timezone  string   `toml:"timezone,omitempty" json:"timezone,omitempty"`
+	Processes []string `toml:"processes,omitempty" json:"processes,omitempty"`
+}
+
 type Mount struct {
 	Source      string   `toml:"source,omitempty" json:"source,omitempty"`
 	Destination string   `toml:"destination" json:"destination,omitempty"`
@@ -97,6 +161,8 @@ type Build struct {
 	Dockerfile        string            `toml:"dockerfile,omitempty" json:"dockerfile,omitempty"`
 	Ignorefile        string            `toml:"ignorefile,omitempty" json:"ignorefile,omitempty"`
 	DockerBuildTarget string            `toml:"build-target,omitempty" json:"build-target,omitempty"`
+	FollowSymlinks    bool              `toml:"follow_symlinks,omitempty" json:"follow_symlinks,omitempty"`
+	Dereference       bool              `toml:"dereference,omitempty" json:"dereference,omitempty"`
 }
 
 type Experimental struct {
@@ -237,3 +303,15 @@ func (cfg *Config) URL() (*url.URL, error) {
 		return nil, nil
 	}
 }
+
+// Label names the check in output: its name, or its path or command.
+func (c SmokeCheck) Label() string {
+	switch {
+	case c.Name != "":
+		return c.Name
+	case c.Path != "":
+		return c.Path
+	default:
+		return c.Command
+	}
+}
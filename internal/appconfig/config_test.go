@@ -246,3 +246,25 @@ func TestURLCalculation(t *testing.T) {
 	url, _ = cfg.URL()
 	assert.Equal(t, https, url)
 }
+
+func TestSmokeCheckValidate(t *testing.T) {
+	valid := []SmokeCheck{
+		{Path: "/"},
+		{Path: "https://example.com/health", Method: "HEAD", Status: 204},
+		{Command: "bin/smoke --all"},
+	}
+	for _, c := range valid {
+		assert.Empty(t, c.validate(), c.Label())
+	}
+
+	invalid := map[string]SmokeCheck{
+		"must set either path or command": {Name: "empty"},
+		"can't set both path and command": {Path: "/", Command: "true"},
+		"can't shell split command":       {Command: "echo 'unterminated"},
+		"sets HTTP options on a command":  {Command: "true", Status: 200},
+		"expects invalid status 42":       {Path: "/", Status: 42},
+	}
+	for want, c := range invalid {
+		assert.Contains(t, c.validate(), want)
+	}
+}
@@ -169,13 +169,15 @@ func TestToDefinition(t *testing.T) {
 		"kill_timeout":   "3s",
 
 		"build": map[string]any{
-			"builder":      "dockerfile",
-			"image":        "foo/fighter",
-			"builtin":      "whatisthis",
-			"dockerfile":   "Dockerfile",
-			"ignorefile":   ".gitignore",
-			"build-target": "target",
-			"buildpacks":   []any{"packme", "well"},
+			"builder":         "dockerfile",
+			"image":           "foo/fighter",
+			"builtin":         "whatisthis",
+			"dockerfile":      "Dockerfile",
+			"ignorefile":      ".gitignore",
+			"build-target":    "target",
+			"follow_symlinks": true,
+			"dereference":     true,
+			"buildpacks":      []any{"packme", "well"},
 			"settings": map[string]any{
 				"foo":   "bar",
 				"other": float64(2),
@@ -224,8 +226,23 @@ func TestToDefinition(t *testing.T) {
 		},
 
 		"deploy": map[string]any{
-			"release_command": "release command",
-			"strategy":        "rolling-eyes",
+			"release_command":    "release command",
+			"strategy":           "rolling-eyes",
+			"wait_timeout":       "5m0s",
+			"check_timeout":      "2m0s",
+			"check_grace_period": "30s",
+			"smoke_checks": []map[string]any{
+				{
+					"name":          "home",
+					"path":          "/",
+					"status":        int64(200),
+					"body_contains": "Welcome",
+				},
+				{
+					"command": "bin/smoke",
+					"timeout": "1m0s",
+				},
+			},
 		},
 		"env": map[string]any{
 			"FOO": "BAR",
@@ -240,6 +257,31 @@ func TestToDefinition(t *testing.T) {
 				"url_prefix": "/static-assets",
 			},
 		},
+		"bindings": []map[string]any{
+			{
+				"name":     "redis",
+				"template": "redis://{{ .Hostname }}:6379",
+				"secret":   "REDIS_URL",
+			},
+		},
+		"autoscaling": []map[string]any{
+			{
+				"min_machines":       int64(1),
+				"max_machines":       int64(4),
+				"target_concurrency": int64(20),
+				"target_cpu_percent": int64(70),
+				"processes":          []any{"web"},
+			},
+		},
+		"scale_schedules": []map[string]any{
+			{
+				"stop":      "22:00",
+				"start":     "07:00",
+				"days":      []any{"mon", "tue", "wed", "thu", "fri"},
+				"timezone":  "Europe/Paris",
+				"processes": []any{"task"},
+			},
+		},
 		"mounts": []map[string]any{{
 			"source":      "data",
 			"destination": "/data",
@@ -23,9 +23,33 @@ func (c *Config) ToReleaseMachineConfig() (*api.MachineConfig, error) {
 		return nil, err
 	}
 
+	mConfig := c.toEphemeralMachineConfig(releaseCmd)
+	mConfig.Env["RELEASE_COMMAND"] = "1"
+
+	return mConfig, nil
+}
+
+// ToSmokeCheckMachineConfig returns the config of the ephemeral machine that
+// runs the command of a smoke check. Such machines belong to the release
+// command group so that deployments leave them alone.
+func (c *Config) ToSmokeCheckMachineConfig(command string) (*api.MachineConfig, error) {
+	cmd, err := shlex.Split(command)
+	if err != nil {
+		return nil, err
+	}
+
+	mConfig := c.toEphemeralMachineConfig(cmd)
+	mConfig.Env["SMOKE_CHECK"] = "1"
+
+	return mConfig, nil
+}
+
+// toEphemeralMachineConfig returns the config of a machine running cmd once
+// and destroyed as it exits.
+func (c *Config) toEphemeralMachineConfig(cmd []string) *api.MachineConfig {
 	mConfig := &api.MachineConfig{
 		Init: api.MachineInit{
-			Cmd: releaseCmd,
+			Cmd: cmd,
 		},
 		Restart: api.MachineRestart{
 			Policy: api.MachineRestartPolicyNo,
@@ -41,7 +65,6 @@ func (c *Config) ToReleaseMachineConfig() (*api.MachineConfig, error) {
 		Env: lo.Assign(c.Env),
 	}
 
-	mConfig.Env["RELEASE_COMMAND"] = "1"
 	mConfig.Env["FLY_PROCESS_GROUP"] = api.MachineProcessGroupFlyAppReleaseCommand
 	if c.PrimaryRegion != "" {
 		mConfig.Env["PRIMARY_REGION"] = c.PrimaryRegion
@@ -50,7 +73,7 @@ func (c *Config) ToReleaseMachineConfig() (*api.MachineConfig, error) {
 	// StopConfig
 	c.tomachineSetStopConfig(mConfig)
 
-	return mConfig, nil
+	return mConfig
 }
 
 // updateMachineConfig applies configuration options from the optional MachineConfig passed in, then the base config, into a new MachineConfig
@@ -87,6 +110,22 @@ func (c *Config) updateMachineConfig(src *api.MachineConfig) (*api.MachineConfig
 		})
 	}
 
+	// Autoscaling lets the proxy start and stop machines around the target
+	if len(c.Autoscaling) > 0 && c.Autoscaling[0].TargetConcurrency > 0 {
+		target := c.Autoscaling[0].TargetConcurrency
+		for i := range mConfig.Services {
+			s := &mConfig.Services[i]
+			concurrency := api.MachineServiceConcurrency{Type: "connections"}
+			if s.Concurrency != nil {
+				concurrency = *s.Concurrency
+			}
+			concurrency.SoftLimit = target
+			s.Concurrency = &concurrency
+			s.Autostart = api.Pointer(true)
+			s.Autostop = api.Pointer(true)
+		}
+	}
+
 	// Checks
 	mConfig.Checks = nil
 	if len(c.Checks) > 0 {
@@ -135,6 +135,20 @@ func TestToReleaseMachineConfig(t *testing.T) {
 	assert.Equal(t, want, got)
 }
 
+func TestToSmokeCheckMachineConfig(t *testing.T) {
+	cfg, err := LoadConfig("./testdata/tomachine.toml")
+	require.NoError(t, err)
+
+	got, err := cfg.ToSmokeCheckMachineConfig("bin/smoke --url 'http://localhost:8080'")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bin/smoke", "--url", "http://localhost:8080"}, got.Init.Cmd)
+	assert.Equal(t, map[string]string{"FOO": "BAR", "PRIMARY_REGION": "mia", "SMOKE_CHECK": "1", "FLY_PROCESS_GROUP": "fly_app_release_command"}, got.Env)
+	assert.True(t, got.AutoDestroy)
+
+	_, err = cfg.ToSmokeCheckMachineConfig("bin/smoke 'unterminated")
+	assert.Error(t, err)
+}
+
 func TestToMachineConfig_multiProcessGroups(t *testing.T) {
 	cfg, err := LoadConfig("./testdata/tomachine-processgroups.toml")
 	require.NoError(t, err)
@@ -313,3 +327,34 @@ func TestToMachineConfig_services(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, want, got.Services)
 }
+
+func TestToMachineConfig_autoscaling(t *testing.T) {
+	cfg, err := LoadConfig("./testdata/tomachine-autoscaling.toml")
+	require.NoError(t, err)
+
+	got, err := cfg.ToMachineConfig("web", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []api.MachineService{{
+		Protocol:     "tcp",
+		InternalPort: 8080,
+		Autostart:    api.Pointer(true),
+		Autostop:     api.Pointer(true),
+		Concurrency:  &api.MachineServiceConcurrency{Type: "requests", HardLimit: 50, SoftLimit: 20},
+	}}, got.Services)
+
+	rule, err := cfg.AutoscalingFor("web")
+	require.NoError(t, err)
+	assert.Equal(t, &Autoscaling{MinMachines: 1, MaxMachines: 3, TargetConcurrency: 20, Processes: []string{"web"}}, rule)
+
+	got, err = cfg.ToMachineConfig("worker", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []api.MachineService{{
+		Protocol:     "tcp",
+		InternalPort: 8080,
+		Concurrency:  &api.MachineServiceConcurrency{Type: "requests", HardLimit: 50},
+	}}, got.Services)
+
+	rule, err = cfg.AutoscalingFor("worker")
+	require.NoError(t, err)
+	assert.Nil(t, rule)
+}
@@ -146,9 +146,31 @@ func (c *Config) Flatten(groupName string) (*Config, error) {
 		return matchesGroups(x.Processes)
 	})
 
+	// [[autoscaling]]
+	dst.Autoscaling = lo.Filter(c.Autoscaling, func(x Autoscaling, _ int) bool {
+		return matchesGroups(x.Processes)
+	})
+
+	// [[scale_schedules]]
+	dst.ScaleSchedules = lo.Filter(c.ScaleSchedules, func(x ScaleSchedule, _ int) bool {
+		return matchesGroups(x.Processes)
+	})
+
 	return dst, nil
 }
 
+// AutoscalingFor returns the autoscaling rule of the process group, if any.
+func (c *Config) AutoscalingFor(groupName string) (*Autoscaling, error) {
+	fc, err := c.Flatten(groupName)
+	if err != nil {
+		return nil, err
+	}
+	if len(fc.Autoscaling) == 0 {
+		return nil, nil
+	}
+	return &fc.Autoscaling[0], nil
+}
+
 func (c *Config) InitCmd(groupName string) ([]string, error) {
 	if groupName == "" {
 		groupName = c.DefaultProcessName()
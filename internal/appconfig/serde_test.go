@@ -373,6 +373,8 @@ func TestLoadTOMLAppConfigReferenceFormat(t *testing.T) {
 			Dockerfile:        "Dockerfile",
 			Ignorefile:        ".gitignore",
 			DockerBuildTarget: "target",
+			FollowSymlinks:    true,
+			Dereference:       true,
 			Buildpacks:        []string{"packme", "well"},
 			Settings: map[string]any{
 				"foo":   "bar",
@@ -386,8 +388,15 @@ func TestLoadTOMLAppConfigReferenceFormat(t *testing.T) {
 		},
 
 		Deploy: &Deploy{
-			ReleaseCommand: "release command",
-			Strategy:       "rolling-eyes",
+			ReleaseCommand:   "release command",
+			Strategy:         "rolling-eyes",
+			WaitTimeout:      api.MustParseDuration("5m"),
+			CheckTimeout:     api.MustParseDuration("2m"),
+			CheckGracePeriod: api.MustParseDuration("30s"),
+			SmokeChecks: []SmokeCheck{
+				{Name: "home", Path: "/", Status: 200, BodyContains: "Welcome"},
+				{Command: "bin/smoke", Timeout: api.MustParseDuration("1m")},
+			},
 		},
 
 		Env: map[string]string{
@@ -434,6 +443,34 @@ func TestLoadTOMLAppConfigReferenceFormat(t *testing.T) {
 			},
 		},
 
+		Bindings: []Binding{
+			{
+				Name:     "redis",
+				Template: "redis://{{ .Hostname }}:6379",
+				Secret:   "REDIS_URL",
+			},
+		},
+
+		Autoscaling: []Autoscaling{
+			{
+				MinMachines:       1,
+				MaxMachines:       4,
+				TargetConcurrency: 20,
+				TargetCPUPercent:  70,
+				Processes:         []string{"web"},
+			},
+		},
+
+		ScaleSchedules: []ScaleSchedule{
+			{
+				Stop:      "22:00",
+				Start:     "07:00",
+				Days:      []string{"mon", "tue", "wed", "thu", "fri"},
+				Timezone:  "Europe/Paris",
+				Processes: []string{"task"},
+			},
+		},
+
 		Mounts: []Mount{{
 			Source:      "data",
 			Destination: "/data",
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/google/shlex"
 	"github.com/logrusorgru/aurora"
@@ -94,6 +95,8 @@ func (cfg *Config) ValidateForMachinesPlatform(ctx context.Context) (err error,
 		cfg.validateChecksSection,
 		cfg.validateServicesSection,
 		cfg.validateProcessesSection,
+		cfg.validateAutoscalingSection,
+		cfg.validateScaleSchedulesSection,
 		cfg.validateMachineConversion,
 	}
 
@@ -135,10 +138,36 @@ func (cfg *Config) validateDeploySection() (extraInfo string, err error) {
 			extraInfo += fmt.Sprintf("Can't shell split release command: '%s'\n", cfg.Deploy.ReleaseCommand)
 			err = ValidationError
 		}
+		for i, check := range cfg.Deploy.SmokeChecks {
+			if msg := check.validate(); msg != "" {
+				extraInfo += fmt.Sprintf("Smoke check #%d (%s) %s\n", i+1, check.Label(), msg)
+				err = ValidationError
+			}
+		}
 	}
 	return
 }
 
+// validate returns why the check is invalid, or an empty string.
+func (c SmokeCheck) validate() string {
+	switch {
+	case c.Path == "" && c.Command == "":
+		return "must set either path or command"
+	case c.Path != "" && c.Command != "":
+		return "can't set both path and command"
+	case c.Command != "":
+		if _, err := shlex.Split(c.Command); err != nil {
+			return fmt.Sprintf("can't shell split command: '%s'", c.Command)
+		}
+		if c.Method != "" || len(c.Headers) > 0 || c.Status != 0 || c.BodyContains != "" {
+			return "sets HTTP options on a command"
+		}
+	case c.Status != 0 && (c.Status < 100 || c.Status > 599):
+		return fmt.Sprintf("expects invalid status %d", c.Status)
+	}
+	return ""
+}
+
 func (cfg *Config) validateChecksSection() (extraInfo string, err error) {
 	for name, check := range cfg.Checks {
 		if _, vErr := check.toMachineCheck(); vErr != nil {
@@ -210,6 +239,95 @@ func (cfg *Config) validateProcessesSection() (extraInfo string, err error) {
 	return extraInfo, err
 }
 
+func (cfg *Config) validateAutoscalingSection() (extraInfo string, err error) {
+	validGroupNames := cfg.ProcessNames()
+	ruled := map[string]bool{}
+
+	for _, rule := range cfg.Autoscaling {
+		switch {
+		case rule.MinMachines < 0:
+			extraInfo += fmt.Sprintf("Autoscaling min_machines can't be negative: %d\n", rule.MinMachines)
+			err = ValidationError
+		case rule.MaxMachines < 1:
+			extraInfo += fmt.Sprintf("Autoscaling max_machines must be at least 1: %d\n", rule.MaxMachines)
+			err = ValidationError
+		case rule.MinMachines > rule.MaxMachines:
+			extraInfo += fmt.Sprintf("Autoscaling min_machines (%d) is greater than max_machines (%d)\n", rule.MinMachines, rule.MaxMachines)
+			err = ValidationError
+		}
+		if rule.TargetConcurrency < 0 {
+			extraInfo += fmt.Sprintf("Autoscaling target_concurrency can't be negative: %d\n", rule.TargetConcurrency)
+			err = ValidationError
+		}
+		if rule.TargetCPUPercent < 0 || rule.TargetCPUPercent > 100 {
+			extraInfo += fmt.Sprintf("Autoscaling target_cpu_percent must be between 1 and 100: %d\n", rule.TargetCPUPercent)
+			err = ValidationError
+		}
+
+		groups := rule.Processes
+		if len(groups) == 0 {
+			groups = []string{cfg.DefaultProcessName()}
+		}
+		for _, name := range groups {
+			if !slices.Contains(validGroupNames, name) {
+				extraInfo += fmt.Sprintf("Autoscaling rule specifies '%s' as one of its processes, but no processes are defined with that name\n", name)
+				err = ValidationError
+			}
+			if ruled[name] {
+				extraInfo += fmt.Sprintf("Process group '%s' has more than one autoscaling rule\n", name)
+				err = ValidationError
+			}
+			ruled[name] = true
+		}
+	}
+
+	return extraInfo, err
+}
+
+var scheduleDays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+func (cfg *Config) validateScaleSchedulesSection() (extraInfo string, err error) {
+	validGroupNames := cfg.ProcessNames()
+
+	for _, schedule := range cfg.ScaleSchedules {
+		stop, stopErr := time.Parse("15:04", schedule.Stop)
+		if stopErr != nil {
+			extraInfo += fmt.Sprintf("Scale schedule stop time '%s' must be formatted as HH:MM\n", schedule.Stop)
+			err = ValidationError
+		}
+		start, startErr := time.Parse("15:04", schedule.Start)
+		if startErr != nil {
+			extraInfo += fmt.Sprintf("Scale schedule start time '%s' must be formatted as HH:MM\n", schedule.Start)
+			err = ValidationError
+		}
+		if stopErr == nil && startErr == nil && stop.Equal(start) {
+			extraInfo += fmt.Sprintf("Scale schedule stop and start times are the same: %s\n", schedule.Stop)
+			err = ValidationError
+		}
+
+		if _, tzErr := time.LoadLocation(schedule.Timezone); tzErr != nil {
+			extraInfo += fmt.Sprintf("Scale schedule timezone '%s' is unknown\n", schedule.Timezone)
+			err = ValidationError
+		}
+
+		for _, day := range schedule.Days {
+			if !slices.Contains(scheduleDays, strings.ToLower(day)) {
+				extraInfo += fmt.Sprintf("Scale schedule day '%s' is invalid, use one of %s\n", day, strings.Join(scheduleDays, ", "))
+				err = ValidationError
+			}
+		}
+
+		for _, name := range schedule.Processes {
+			if !slices.Contains(validGroupNames, name) {
+				extraInfo += fmt.Sprintf("Scale schedule specifies '%s' as one of its processes, but no processes are defined with that name\n", name)
+				err = ValidationError
+			}
+		}
+	}
+
+	return extraInfo, err
+}
+
 func (cfg *Config) validateMachineConversion() (extraInfo string, err error) {
 	for _, name := range cfg.ProcessNames() {
 		if _, vErr := cfg.ToMachineConfig(name, nil); err != nil {
@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"io"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
@@ -20,6 +21,13 @@ type archiveOptions struct {
 	exclusions []string
 	compressed bool
 	additions  map[string][]byte
+
+	// followSymlinks archives the contents of symlinked directories in place
+	// of the links themselves.
+	followSymlinks bool
+	// dereference archives the contents of symlinked files in place of the
+	// links themselves.
+	dereference bool
 }
 
 type ArchiveInfo struct {
@@ -70,7 +78,8 @@ func archiveDirectory(options archiveOptions) (io.ReadCloser, error) {
 	opts := &archive.TarOptions{
 		ExcludePatterns: options.exclusions,
 	}
-	if options.compressed && len(options.additions) == 0 {
+	resolveLinks := options.followSymlinks || options.dereference
+	if options.compressed && len(options.additions) == 0 && !resolveLinks {
 		opts.Compression = archive.Gzip
 	}
 
@@ -83,10 +92,18 @@ func archiveDirectory(options archiveOptions) (io.ReadCloser, error) {
 		return nil, err
 	}
 
+	if resolveLinks {
+		r = resolveSymlinks(r, sourcePath, options)
+	}
+
 	if options.additions != nil {
 		mods := map[string]archive.TarModifierFunc{}
 		for name, contents := range options.additions {
-			mods[name] = func(path string, header *tar.Header, content io.Reader) (*tar.Header, []byte, error) {
+			// additions may be given as "./Dockerfile" or with OS separators;
+			// normalize them so they replace the matching entry instead of
+			// being appended as a duplicate.
+			name, contents := path.Clean(filepath.ToSlash(name)), contents
+			mods[name] = func(_ string, header *tar.Header, content io.Reader) (*tar.Header, []byte, error) {
 				newHeader := &tar.Header{
 					Name: name,
 					Size: int64(len(contents)),
@@ -102,6 +119,160 @@ func archiveDirectory(options archiveOptions) (io.ReadCloser, error) {
 	return r, nil
 }
 
+// resolveSymlinks rewrites the tar stream in r, replacing symlinks that point
+// to directories (when followSymlinks is set) or files (when dereference is
+// set) with the content they point to. Links that can't be resolved are left
+// untouched, as are links that would recurse into a directory that is already
+// being archived.
+func resolveSymlinks(r io.ReadCloser, sourcePath string, options archiveOptions) io.ReadCloser {
+	pr, pw := io.Pipe()
+
+	go func() {
+		defer r.Close()
+
+		tr := tar.NewReader(r)
+		tw := tar.NewWriter(pw)
+		visited := map[string]bool{sourcePath: true}
+
+		err := func() error {
+			for {
+				hdr, err := tr.Next()
+				if err == io.EOF {
+					return tw.Close()
+				}
+				if err != nil {
+					return err
+				}
+
+				if hdr.Typeflag == tar.TypeSymlink {
+					linkPath := filepath.Join(sourcePath, filepath.FromSlash(hdr.Name))
+					resolved, err := writeResolvedSymlink(tw, linkPath, hdr.Name, options, visited)
+					if err != nil {
+						return err
+					}
+					if resolved {
+						continue
+					}
+				}
+
+				if err := tw.WriteHeader(hdr); err != nil {
+					return err
+				}
+				if _, err := io.Copy(tw, tr); err != nil {
+					return err
+				}
+			}
+		}()
+
+		pw.CloseWithError(err)
+	}()
+
+	return pr
+}
+
+// writeResolvedSymlink writes the target of the symlink at linkPath to tw
+// under name. It reports whether the link was resolved.
+func writeResolvedSymlink(tw *tar.Writer, linkPath, name string, options archiveOptions, visited map[string]bool) (bool, error) {
+	target, err := filepath.EvalSymlinks(linkPath)
+	if err != nil {
+		terminal.Debugf("leaving unresolvable symlink %s in build context: %v\n", name, err)
+		return false, nil
+	}
+
+	fi, err := os.Stat(target)
+	if err != nil {
+		return false, nil
+	}
+
+	switch {
+	case fi.IsDir() && options.followSymlinks:
+		if visited[target] {
+			terminal.Debugf("not following symlink %s: it loops back to %s\n", name, target)
+			return false, nil
+		}
+		return true, writeLinkedDirectory(tw, target, name, options, visited)
+	case fi.Mode().IsRegular() && options.dereference:
+		return true, writeLinkedFile(tw, target, name, fi)
+	default:
+		return false, nil
+	}
+}
+
+// writeLinkedDirectory archives the contents of dir under name, honoring the
+// exclusions of the build context.
+func writeLinkedDirectory(tw *tar.Writer, dir, name string, options archiveOptions, visited map[string]bool) error {
+	visited[dir] = true
+	defer delete(visited, dir)
+
+	return filepath.Walk(dir, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		entryName := path.Join(name, filepath.ToSlash(rel))
+
+		if excluded, _ := fileutils.Matches(entryName, options.exclusions); excluded {
+			if fi.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if fi.Mode()&os.ModeSymlink != 0 {
+			resolved, err := writeResolvedSymlink(tw, p, entryName, options, visited)
+			if err != nil || resolved {
+				return err
+			}
+
+			link, err := os.Readlink(p)
+			if err != nil {
+				return err
+			}
+			hdr, err := tar.FileInfoHeader(fi, link)
+			if err != nil {
+				return err
+			}
+			hdr.Name = entryName
+			return tw.WriteHeader(hdr)
+		}
+
+		if fi.IsDir() {
+			hdr, err := tar.FileInfoHeader(fi, "")
+			if err != nil {
+				return err
+			}
+			hdr.Name = entryName + "/"
+			return tw.WriteHeader(hdr)
+		}
+
+		return writeLinkedFile(tw, p, entryName, fi)
+	})
+}
+
+func writeLinkedFile(tw *tar.Writer, p, name string, fi os.FileInfo) error {
+	hdr, err := tar.FileInfoHeader(fi, "")
+	if err != nil {
+		return err
+	}
+	hdr.Name = name
+
+	f, err := os.Open(p)
+	if err != nil {
+		return err
+	}
+	defer f.Close() // skipcq: GO-S2307
+
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
+
 func readDockerignore(workingDir string, ignoreFile string) ([]string, error) {
 	if ignoreFile == "" {
 		ignoreFile = filepath.Join(workingDir, ".dockerignore")
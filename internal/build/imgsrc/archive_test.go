@@ -193,3 +193,80 @@ func TestIsPathInRoot(t *testing.T) {
 		assert.Equal(t, c.rooted, isPathInRoot(c.filename, c.rootDir), "target: %s root:%s", c.filename, c.rootDir)
 	}
 }
+
+func TestArchiverMultipleAdditions(t *testing.T) {
+	testDir, err := newTestDir("a.jpg", "Dockerfile")
+	assert.NoError(t, err)
+	defer os.RemoveAll(testDir)
+
+	r, err := archiveDirectory(archiveOptions{
+		sourcePath: testDir,
+		additions: map[string][]byte{
+			"./Dockerfile": []byte("this is a dockerfile"),
+			"fly.toml":     []byte("app = 'foo'"),
+		},
+	})
+	assert.NoError(t, err)
+
+	names, contents, err := unpackTar(r)
+	assert.NoError(t, err)
+
+	assert.ElementsMatch(t, names, []string{"a.jpg", "Dockerfile", "fly.toml"})
+	assert.Equal(t, []byte("this is a dockerfile"), contents["Dockerfile"])
+	assert.Equal(t, []byte("app = 'foo'"), contents["fly.toml"])
+}
+
+func TestArchiverSymlinks(t *testing.T) {
+	testDir, err := newTestDir("app/main.go")
+	assert.NoError(t, err)
+	defer os.RemoveAll(testDir)
+
+	sharedDir, err := newTestDir("lib/util.go", "lib/util_test.go", "README.md")
+	assert.NoError(t, err)
+	defer os.RemoveAll(sharedDir)
+
+	assert.NoError(t, os.Symlink(filepath.Join(sharedDir, "lib"), filepath.Join(testDir, "lib")))
+	assert.NoError(t, os.Symlink(filepath.Join(sharedDir, "README.md"), filepath.Join(testDir, "README.md")))
+	assert.NoError(t, os.Symlink(testDir, filepath.Join(testDir, "app", "loop")))
+
+	cases := []struct {
+		name     string
+		options  archiveOptions
+		expected []string
+	}{
+		{
+			name:     "default",
+			options:  archiveOptions{},
+			expected: []string{"app/main.go"},
+		},
+		{
+			name:     "follow",
+			options:  archiveOptions{followSymlinks: true, exclusions: []string{"**/*_test.go"}},
+			expected: []string{"app/main.go", "lib/util.go"},
+		},
+		{
+			name:     "dereference",
+			options:  archiveOptions{dereference: true},
+			expected: []string{"app/main.go", "README.md"},
+		},
+		{
+			name:     "both",
+			options:  archiveOptions{followSymlinks: true, dereference: true},
+			expected: []string{"app/main.go", "lib/util.go", "lib/util_test.go", "README.md"},
+		},
+	}
+
+	for _, c := range cases {
+		c.options.sourcePath = testDir
+		r, err := archiveDirectory(c.options)
+		assert.NoError(t, err, c.name)
+
+		names, contents, err := unpackTar(r)
+		assert.NoError(t, err, c.name)
+		assert.ElementsMatch(t, c.expected, names, c.name)
+
+		if c.options.dereference {
+			assert.Equal(t, []byte("README.md"), contents["README.md"], c.name)
+		}
+	}
+}
@@ -61,8 +61,10 @@ func (*builtinBuilder) Run(ctx context.Context, dockerFactory *dockerClientFacto
 	build.ContextBuildStart()
 	cmdfmt.PrintBegin(streams.ErrOut, "Creating build context")
 	archiveOpts := archiveOptions{
-		sourcePath: opts.WorkingDir,
-		compressed: dockerFactory.IsRemote(),
+		sourcePath:     opts.WorkingDir,
+		compressed:     dockerFactory.IsRemote(),
+		followSymlinks: opts.FollowSymlinks,
+		dereference:    opts.Dereference,
 	}
 
 	excludes, err := readDockerignore(opts.WorkingDir, opts.IgnorefilePath)
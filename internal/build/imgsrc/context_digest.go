@@ -0,0 +1,95 @@
+package imgsrc
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// ContextDigest returns a digest of everything that goes into building an
+// image from source with opts: the build context after applying ignore rules,
+// the Dockerfile, the build args and secrets, and the builder settings. Builds
+// with equal digests are expected to produce equivalent images.
+//
+// File modification times are left out of the digest so that fresh checkouts
+// of the same tree digest the same.
+func ContextDigest(opts ImageOptions) (string, error) {
+	h := sha256.New()
+
+	settings := struct {
+		BuildArgs       map[string]string
+		ExtraBuildArgs  map[string]string
+		BuildSecrets    map[string]string
+		Target          string
+		BuiltIn         string
+		BuiltInSettings map[string]interface{}
+		Builder         string
+		Buildpacks      []string
+	}{
+		opts.BuildArgs,
+		opts.ExtraBuildArgs,
+		opts.BuildSecrets,
+		opts.Target,
+		opts.BuiltIn,
+		opts.BuiltInSettings,
+		opts.Builder,
+		opts.Buildpacks,
+	}
+	// encoding/json sorts map keys, which keeps the encoding stable
+	if err := json.NewEncoder(h).Encode(settings); err != nil {
+		return "", err
+	}
+
+	dockerfile := opts.DockerfilePath
+	if dockerfile == "" {
+		dockerfile = ResolveDockerfile(opts.WorkingDir)
+	}
+	if dockerfile != "" {
+		data, err := os.ReadFile(dockerfile)
+		if err != nil {
+			return "", errors.Wrap(err, "error reading Dockerfile")
+		}
+		fmt.Fprintf(h, "dockerfile %d\n", len(data))
+		h.Write(data)
+	}
+
+	excludes, err := readDockerignore(opts.WorkingDir, opts.IgnorefilePath)
+	if err != nil {
+		return "", errors.Wrap(err, "error reading .dockerignore")
+	}
+
+	r, err := archiveDirectory(archiveOptions{
+		sourcePath:     opts.WorkingDir,
+		exclusions:     excludes,
+		followSymlinks: opts.FollowSymlinks,
+		dereference:    opts.Dereference,
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "error archiving build context")
+	}
+	defer r.Close()
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", errors.Wrap(err, "error reading build context")
+		}
+
+		fmt.Fprintf(h, "%s %c %o %s %d\n", hdr.Name, hdr.Typeflag, hdr.Mode, hdr.Linkname, hdr.Size)
+		if _, err := io.Copy(h, tr); err != nil {
+			return "", errors.Wrap(err, "error reading build context")
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
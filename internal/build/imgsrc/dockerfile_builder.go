@@ -97,8 +97,10 @@ func (*dockerfileBuilder) Run(ctx context.Context, dockerFactory *dockerClientFa
 	tb := render.NewTextBlock(ctx, "Creating build context")
 
 	archiveOpts := archiveOptions{
-		sourcePath: opts.WorkingDir,
-		compressed: dockerFactory.IsRemote(),
+		sourcePath:     opts.WorkingDir,
+		compressed:     dockerFactory.IsRemote(),
+		followSymlinks: opts.FollowSymlinks,
+		dereference:    opts.Dereference,
 	}
 
 	excludes, err := readDockerignore(opts.WorkingDir, opts.IgnorefilePath)
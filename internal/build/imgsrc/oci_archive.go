@@ -0,0 +1,207 @@
+package imgsrc
+
+import (
+	"archive/tar"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/pkg/errors"
+
+	"github.com/superfly/flyctl/flyctl"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+type ArchiveOptions struct {
+	AppName    string
+	Path       string
+	ImageLabel string
+	Publish    bool
+}
+
+// PushImageArchive pushes the image of an OCI image layout, exported to a tar
+// archive or a directory, to the Fly registry. Neither a Docker daemon nor
+// access to any other registry is needed.
+func PushImageArchive(ctx context.Context, streams *iostreams.IOStreams, opts ArchiveOptions) (*DeploymentImage, error) {
+	dir := opts.Path
+
+	info, err := os.Stat(opts.Path)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading image archive")
+	}
+	if !info.IsDir() {
+		if dir, err = os.MkdirTemp("", "flyctl-oci-*"); err != nil {
+			return nil, err
+		}
+		defer os.RemoveAll(dir)
+
+		if err := extractArchive(opts.Path, dir); err != nil {
+			return nil, errors.Wrap(err, "error extracting image archive")
+		}
+	}
+
+	index, err := layout.ImageIndexFromPath(dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading OCI image layout")
+	}
+
+	img, err := selectImage(index)
+	if err != nil {
+		return nil, err
+	}
+
+	digest, err := img.Digest()
+	if err != nil {
+		return nil, err
+	}
+
+	size, err := imageSize(img)
+	if err != nil {
+		return nil, err
+	}
+
+	tag := NewDeploymentTag(opts.AppName, opts.ImageLabel)
+
+	di := &DeploymentImage{
+		ID:   digest.String(),
+		Tag:  tag,
+		Size: size,
+	}
+
+	if !opts.Publish {
+		return di, nil
+	}
+
+	ref, err := name.ParseReference(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(streams.ErrOut, "Pushing image %s from %s\n", tag, opts.Path)
+
+	auth := &authn.Basic{
+		Username: "x",
+		Password: flyctl.GetAPIToken(),
+	}
+	started := time.Now()
+	if err := remote.Write(ref, img, remote.WithAuth(auth), remote.WithContext(ctx)); err != nil {
+		return nil, errors.Wrap(err, "error pushing image to registry")
+	}
+	di.PushDuration = time.Since(started)
+
+	return di, nil
+}
+
+// selectImage returns the linux/amd64 image of the index, which is the only
+// platform Fly machines run, or its only image when there's a single one.
+func selectImage(index v1.ImageIndex) (v1.Image, error) {
+	manifest, err := index.IndexManifest()
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading OCI image index")
+	}
+
+	var candidates []v1.Descriptor
+	for _, desc := range manifest.Manifests {
+		if desc.Platform == nil || (desc.Platform.OS == "linux" && desc.Platform.Architecture == "amd64") {
+			candidates = append(candidates, desc)
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return nil, errors.New("the image archive contains no linux/amd64 image")
+	case 1:
+	default:
+		return nil, errors.New("the image archive contains more than one image; export a single image")
+	}
+
+	desc := candidates[0]
+	if desc.MediaType.IsIndex() {
+		nested, err := index.ImageIndex(desc.Digest)
+		if err != nil {
+			return nil, err
+		}
+		return selectImage(nested)
+	}
+
+	return index.Image(desc.Digest)
+}
+
+func imageSize(img v1.Image) (size int64, err error) {
+	manifest, err := img.Manifest()
+	if err != nil {
+		return 0, err
+	}
+
+	size = manifest.Config.Size
+	for _, layer := range manifest.Layers {
+		size += layer.Size
+	}
+
+	return size, nil
+}
+
+// extractArchive extracts the tar archive at path into dir.
+func extractArchive(path, dir string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(dir, filepath.FromSlash(hdr.Name))
+		if target == filepath.Clean(dir) {
+			continue
+		}
+		if !strings.HasPrefix(target, filepath.Clean(dir)+string(os.PathSeparator)) {
+			return fmt.Errorf("invalid path in archive: %s", hdr.Name)
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			if err := writeFile(target, tr); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func writeFile(path string, r io.Reader) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,53 @@
+package imgsrc
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/pkg/archive"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func TestPushImageArchive(t *testing.T) {
+	img, err := random.Image(1024, 2)
+	require.NoError(t, err)
+
+	digest, err := img.Digest()
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	p, err := layout.Write(dir, empty.Index)
+	require.NoError(t, err)
+	require.NoError(t, p.AppendImage(img))
+
+	r, err := archive.Tar(dir, archive.Uncompressed)
+	require.NoError(t, err)
+	defer r.Close()
+
+	tarPath := filepath.Join(t.TempDir(), "app.oci.tar")
+	f, err := os.Create(tarPath)
+	require.NoError(t, err)
+	_, err = f.ReadFrom(r)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	streams, _, _, _ := iostreams.Test()
+
+	for _, path := range []string{dir, tarPath} {
+		di, err := PushImageArchive(context.Background(), streams, ArchiveOptions{
+			AppName: "app",
+			Path:    path,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, digest.String(), di.ID)
+		assert.Greater(t, di.Size, int64(2048))
+	}
+}
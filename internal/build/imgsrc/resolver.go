@@ -43,6 +43,8 @@ type ImageOptions struct {
 	BuiltInSettings map[string]interface{}
 	Builder         string
 	Buildpacks      []string
+	FollowSymlinks  bool
+	Dereference     bool
 }
 
 type RefOptions struct {
@@ -58,6 +60,15 @@ type DeploymentImage struct {
 	ID   string
 	Tag  string
 	Size int64
+
+	// ContextDigest is the digest of the build context the image was built
+	// from, if it was built from source. See ContextDigest.
+	ContextDigest string
+
+	// BuildDuration and PushDuration are how long building and pushing the
+	// image took, when measured.
+	BuildDuration time.Duration
+	PushDuration  time.Duration
 }
 
 type Resolver struct {
@@ -101,6 +112,7 @@ func (r *Resolver) ResolveReference(ctx context.Context, streams *iostreams.IOSt
 		if img != nil {
 			bld.BuildAndPushFinish()
 			bld.FinishImageStrategy(s, false /* success */, nil, note)
+			bld.applyTimings(img)
 			r.finishBuild(ctx, bld, false /* completed */, "", img)
 			return img, nil
 		}
@@ -153,6 +165,7 @@ func (r *Resolver) BuildImage(ctx context.Context, streams *iostreams.IOStreams,
 		if img != nil {
 			bld.BuildAndPushFinish()
 			bld.FinishStrategy(s, false /* success */, nil, note)
+			bld.applyTimings(img)
 			r.finishBuild(ctx, bld, false /* completed */, "", img)
 			return img, nil
 		}
@@ -365,6 +378,16 @@ func (b *build) PushFinish() {
 	b.Timings.PushMs = time.Now().UnixMilli() - b.StartTimes.PushMs
 }
 
+// applyTimings copies the measured build and push durations to img.
+func (b *build) applyTimings(img *DeploymentImage) {
+	if b.Timings.BuildMs >= 0 {
+		img.BuildDuration = time.Duration(b.Timings.BuildMs) * time.Millisecond
+	}
+	if b.Timings.PushMs >= 0 {
+		img.PushDuration = time.Duration(b.Timings.PushMs) * time.Millisecond
+	}
+}
+
 func (b *build) finishStrategyCommon(strategy string, failed bool, err error, note string) {
 	result := "failed"
 	if !failed {
@@ -7,9 +7,12 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"os"
 
 	"github.com/AlecAivazis/survey/v2/terminal"
 	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/flyctl"
 	"github.com/superfly/flyctl/internal/metrics"
 
 	"github.com/superfly/flyctl/iostreams"
@@ -27,14 +30,21 @@ func Run(ctx context.Context, io *iostreams.IOStreams, args ...string) int {
 	ctx = iostreams.NewContext(ctx, io)
 	ctx = logger.NewContext(ctx, logger.FromEnv(io.ErrOut))
 
+	cs := io.ColorScheme()
+
+	// the profile determines the config directory, which is set up along
+	// with the commands
+	if err := selectProfile(args); err != nil {
+		fmt.Fprintln(io.ErrOut, cs.Red("Error:"), err)
+		return 1
+	}
+
 	cmd := root.New()
 	cmd.SetOut(io.Out)
 	cmd.SetErr(io.ErrOut)
 	cmd.SetArgs(args)
 	cmd.SilenceErrors = true
 
-	cs := io.ColorScheme()
-
 	defer func() {
 		metrics.FlushPending()
 	}()
@@ -42,11 +52,17 @@ func Run(ctx context.Context, io *iostreams.IOStreams, args ...string) int {
 	switch _, err := cmd.ExecuteContextC(ctx); {
 	case err == nil:
 		return 0
+	case errors.As(err, new(*flyerr.ExitCodeError)):
+		code, _ := flyerr.GetExitCode(err)
+		return code
 	case errors.Is(err, context.Canceled), errors.Is(err, terminal.InterruptErr):
 		return 127
 	case errors.Is(err, context.DeadlineExceeded):
 		printError(io.ErrOut, cs, cmd, err)
 		return 126
+	case errors.Is(err, api.ErrDryRun):
+		fmt.Fprintln(io.ErrOut, "Dry run: no changes were made; the changes depending on the first one weren't evaluated")
+		return 0
 	case isUnchangedError(err):
 		// This means the deployment was a noop, which is noteworthy but not something we should
 		// fail CI on. Print a warning and exit 0. Remove this once we're fully on Machines!
@@ -65,6 +81,20 @@ func Run(ctx context.Context, io *iostreams.IOStreams, args ...string) int {
 	}
 }
 
+// selectProfile exports the profile of the --profile flag, if any, for the
+// config directory and the processes flyctl starts to use it.
+func selectProfile(args []string) error {
+	name, ok := flyctl.ProfileFromArgs(args)
+	if !ok {
+		return flyctl.ValidateProfileName(flyctl.Profile())
+	}
+	if err := flyctl.ValidateProfileName(name); err != nil {
+		return err
+	}
+
+	return os.Setenv(flyctl.ProfileEnvKey, name)
+}
+
 // isUnchangedError returns true if the error returned is an UNCHANGED GraphQL error.
 // Remove this once we're fully on Machines!
 func isUnchangedError(err error) bool {
@@ -29,6 +29,7 @@ func New() (cmd *cobra.Command) {
 	cmd.AddCommand(
 		newRun(),
 		newPing(),
+		newStats(),
 		newStart(),
 		newStop(),
 		newRestart(),
@@ -36,7 +36,7 @@ func runEstablish(ctx context.Context) (err error) {
 	var res *agent.EstablishResponse
 	if res, err = client.Establish(ctx, flag.FirstArg(ctx)); err == nil {
 		out := iostreams.FromContext(ctx).Out
-		err = render.JSON(out, res)
+		err = render.Output(ctx, out, res)
 	}
 
 	return
@@ -44,7 +44,7 @@ func runPing(ctx context.Context) (err error) {
 	}
 
 	if out := iostreams.FromContext(ctx).Out; config.FromContext(ctx).JSONOutput {
-		err = render.JSON(out, pong)
+		err = render.Output(ctx, out, pong)
 	} else {
 		var buf bytes.Buffer
 
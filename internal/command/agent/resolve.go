@@ -50,7 +50,7 @@ func runResolve(ctx context.Context) (err error) {
 	}
 
 	if out := iostreams.FromContext(ctx).Out; config.FromContext(ctx).JSONOutput {
-		err = render.JSON(out, struct {
+		err = render.Output(ctx, out, struct {
 			Addr string `json:"addr"`
 		}{
 			Addr: addr,
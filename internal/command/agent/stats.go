@@ -0,0 +1,94 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newStats() (cmd *cobra.Command) {
+	const (
+		short = "Show the diagnostics of the tunnels of the Fly agent"
+		long  = `Show the WireGuard tunnels of the Fly agent, along with the round trip
+time to their peer, the traffic and handshakes of WireGuard, and the dials,
+DNS queries, DNS cache hits and errors since they were connected.
+`
+	)
+
+	cmd = command.New("stats", short, long, runStats)
+
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd, flag.JSONOutput())
+	return
+}
+
+func runStats(ctx context.Context) error {
+	client, err := dial(ctx)
+	if err != nil {
+		return err
+	}
+
+	stats, err := client.Stats(ctx)
+	if err != nil {
+		return fmt.Errorf("failed fetching agent stats: %w", err)
+	}
+
+	out := iostreams.FromContext(ctx).Out
+	if config.FromContext(ctx).JSONOutput {
+		return render.Output(ctx, out, stats)
+	}
+
+	if len(stats) == 0 {
+		fmt.Fprintln(out, "The agent has no tunnels")
+
+		return nil
+	}
+
+	rows := make([][]string, 0, len(stats))
+	for _, s := range stats {
+		rtt := s.RTT.Round(time.Millisecond).String()
+		if s.RTTError != "" {
+			rtt = "error: " + s.RTTError
+		}
+
+		handshake := "never"
+		if !s.LastHandshake.IsZero() {
+			handshake = humanize.Time(s.LastHandshake)
+		}
+
+		hitRate := "-"
+		if s.DNSQueries > 0 {
+			hitRate = fmt.Sprintf("%.0f%%", 100*float64(s.DNSCacheHits)/float64(s.DNSQueries))
+		}
+
+		rows = append(rows, []string{
+			s.Org,
+			s.Peer,
+			s.Region,
+			s.Endpoint,
+			rtt,
+			handshake,
+			humanize.IBytes(uint64(s.BytesSent)),
+			humanize.IBytes(uint64(s.BytesReceived)),
+			strconv.FormatInt(s.Dials, 10),
+			strconv.FormatInt(s.DialErrors, 10),
+			strconv.FormatInt(s.DNSQueries, 10),
+			hitRate,
+			strconv.FormatInt(s.DNSErrors, 10),
+		})
+	}
+
+	return render.Table(out, "", rows, "Organization", "Peer", "Region", "Endpoint", "RTT", "Handshake",
+		"Sent", "Received", "Dials", "Dial Errors", "DNS Queries", "DNS Cache Hits", "DNS Errors")
+}
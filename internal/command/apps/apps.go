@@ -41,6 +41,8 @@ The LIST command will list all currently registered applications.
 		NewOpen(),
 		NewReleases(),
 		newSetPlatformVersion(),
+		newExport(),
+		newImport(),
 	)
 
 	return apps
@@ -0,0 +1,79 @@
+package apps
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/internal/command/orgs"
+	"github.com/superfly/flyctl/internal/render"
+)
+
+// bundleVersion is bumped whenever bundles change in a way older versions of
+// import can't read.
+const bundleVersion = 1
+
+// appBundle is the state of an app as exported by apps export and recreated
+// by apps import: its fly.toml along with the resources exported for it by
+// orgs export.
+type appBundle struct {
+	Version      int            `json:"version"`
+	ExportedAt   time.Time      `json:"exported_at"`
+	Organization string         `json:"organization"`
+	Config       api.Definition `json:"config,omitempty"`
+	orgs.AppExport
+}
+
+// writeBundle encodes the bundle as JSON or YAML. YAML documents are converted
+// from JSON so that both formats share the json tags of the API types.
+func writeBundle(w io.Writer, bundle *appBundle, format string) error {
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case "json":
+		_, err = fmt.Fprintf(w, "%s\n", data)
+		return err
+	case "yaml", "yml":
+		var doc any
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return err
+		}
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(doc); err != nil {
+			return err
+		}
+		return enc.Close()
+	default:
+		return fmt.Errorf("unsupported format %q, use json or yaml", format)
+	}
+}
+
+// readBundle decodes a bundle written by writeBundle in either format.
+func readBundle(data []byte) (*appBundle, error) {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) == 0 || trimmed[0] != '{' {
+		var err error
+		if data, err = render.YAMLToJSON(data); err != nil {
+			return nil, err
+		}
+	}
+
+	var bundle appBundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return nil, err
+	}
+
+	if bundle.Version > bundleVersion {
+		return nil, fmt.Errorf("bundle version %d is newer than this version of flyctl supports, upgrade flyctl", bundle.Version)
+	}
+
+	return &bundle, nil
+}
@@ -0,0 +1,72 @@
+package apps
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/internal/command/orgs"
+)
+
+func testBundle() *appBundle {
+	return &appBundle{
+		Version:      bundleVersion,
+		ExportedAt:   time.Date(2023, 5, 4, 12, 0, 0, 0, time.UTC),
+		Organization: "acme",
+		Config: api.Definition{
+			"app":            "web",
+			"primary_region": "ams",
+			"env":            map[string]any{"PORT": "8080"},
+		},
+		AppExport: orgs.AppExport{
+			Name:            "web",
+			PlatformVersion: "machines",
+			Status:          "deployed",
+			Machines: []orgs.MachineExport{{
+				ID:     "m1",
+				Name:   "web-1",
+				Region: "ams",
+				Config: &api.MachineConfig{
+					Image:  "registry.fly.io/web:deployment-1",
+					Env:    map[string]string{"PORT": "8080"},
+					Mounts: []api.MachineMount{{Volume: "vol_1", Path: "/data"}},
+					Guest:  &api.MachineGuest{CPUKind: "shared", CPUs: 1, MemoryMB: 256},
+				},
+			}},
+			Volumes:      []orgs.VolumeExport{{ID: "vol_1", Name: "data", Region: "ams", SizeGb: 1, Encrypted: true, AttachedMachine: "m1"}},
+			IPAddresses:  []orgs.IPAddressExport{{Address: "2a09:8280:1::1", Type: "v6", Region: "global"}},
+			Certificates: []orgs.CertificateExport{{Hostname: "example.com", Status: "Ready"}},
+			Secrets:      []string{"DATABASE_URL"},
+		},
+	}
+}
+
+func TestBundleRoundTrip(t *testing.T) {
+	for _, format := range []string{"json", "yaml"} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, writeBundle(&buf, testBundle(), format))
+
+			bundle, err := readBundle(buf.Bytes())
+			require.NoError(t, err)
+			assert.Equal(t, testBundle(), bundle)
+		})
+	}
+}
+
+func TestWriteBundleRejectsUnknownFormats(t *testing.T) {
+	err := writeBundle(&bytes.Buffer{}, testBundle(), "toml")
+	assert.EqualError(t, err, `unsupported format "toml", use json or yaml`)
+}
+
+func TestReadBundleRejectsNewerVersions(t *testing.T) {
+	_, err := readBundle([]byte(`{"version": 99}`))
+	assert.ErrorContains(t, err, "bundle version 99 is newer")
+
+	_, err = readBundle([]byte("version: 99\n"))
+	assert.ErrorContains(t, err, "bundle version 99 is newer")
+}
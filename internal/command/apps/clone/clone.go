@@ -0,0 +1,437 @@
+// Package clone implements the apps clone command.
+package clone
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/command/orgs"
+	"github.com/superfly/flyctl/internal/command/postgres"
+	"github.com/superfly/flyctl/internal/flag"
+	mach "github.com/superfly/flyctl/internal/machine"
+	"github.com/superfly/flyctl/internal/policy"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+// New initializes and returns a new clone Command. It's registered under the
+// apps command by the root command, as attaching Postgres clusters depends on
+// the apps package.
+func New() *cobra.Command {
+	const (
+		long = `The APPS CLONE command creates a scaled down copy of an application,
+e.g. a staging environment of a production app, in one step.
+
+The copy runs a single machine of each process group of the original on
+smaller guests, shared-cpu-1x unless --vm-size says otherwise. Environment
+variables are copied, except those whose names look sensitive, like
+API_KEY or SMTP_PASSWORD. Secrets aren't copied; their names are listed
+once the clone is done.
+
+Volumes are created empty, or from the latest snapshot of the original
+volumes with --fork-volumes. Pass --postgres to attach a fresh database of
+a Postgres cluster, which sets DATABASE_URL.
+`
+		short = "Create a scaled down copy of an application"
+		usage = "clone"
+	)
+
+	cmd := command.New(usage, short, long, runClone,
+		command.RequireSession,
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd,
+		flag.Org(),
+		flag.Region(),
+		flag.String{
+			Name:        "from",
+			Description: "The name of the app to clone",
+		},
+		flag.String{
+			Name:        "to",
+			Description: "The name of the new app",
+		},
+		flag.String{
+			Name:        "vm-size",
+			Description: "The size of the machines of the new app",
+			Default:     "shared-cpu-1x",
+		},
+		flag.Bool{
+			Name:        "fork-volumes",
+			Description: "Create the volumes from the latest snapshots of the original volumes",
+		},
+		flag.String{
+			Name:        "postgres",
+			Description: "Attach a new database of this Postgres cluster to the new app",
+		},
+	)
+
+	_ = cmd.MarkFlagRequired("from")
+	_ = cmd.MarkFlagRequired("to")
+
+	return cmd
+}
+
+func runClone(ctx context.Context) error {
+	var (
+		io        = iostreams.FromContext(ctx)
+		apiClient = client.FromContext(ctx).API()
+		from      = flag.GetString(ctx, "from")
+		to        = flag.GetString(ctx, "to")
+		region    = flag.GetRegion(ctx)
+		pgAppName = flag.GetString(ctx, "postgres")
+	)
+
+	guest := &api.MachineGuest{}
+	if err := guest.SetSize(flag.GetString(ctx, "vm-size")); err != nil {
+		return err
+	}
+
+	source, err := apiClient.GetApp(ctx, from)
+	if err != nil {
+		return fmt.Errorf("failed retrieving app %s: %w", from, err)
+	}
+	if source.PlatformVersion != appconfig.MachinesPlatform {
+		return fmt.Errorf("only apps on the machines platform can be cloned, %s is on %s", from, source.PlatformVersion)
+	}
+
+	export, err := orgs.ExportApp(ctx, *source)
+	if err != nil {
+		return fmt.Errorf("failed reading app %s: %w", from, err)
+	}
+
+	orgSlug := flag.GetOrg(ctx)
+	if orgSlug == "" {
+		orgSlug = source.Organization.Slug
+	}
+	org, err := apiClient.GetOrganizationBySlug(ctx, orgSlug)
+	if err != nil {
+		return fmt.Errorf("failed retrieving organization %s: %w", orgSlug, err)
+	}
+
+	machines := stagingMachines(export.Machines)
+	if len(machines) == 0 {
+		return fmt.Errorf("app %s has no machines to clone", from)
+	}
+
+	if err := checkPolicy(ctx, org.Slug, machines, guest, region); err != nil {
+		return err
+	}
+
+	input := api.CreateAppInput{
+		Name:           to,
+		OrganizationID: org.ID,
+		Machines:       true,
+	}
+	if region != "" {
+		input.PreferredRegion = api.StringPointer(region)
+	}
+
+	app, err := apiClient.CreateApp(ctx, input)
+	if err != nil {
+		return fmt.Errorf("failed creating app %s: %w", to, err)
+	}
+	fmt.Fprintf(io.Out, "Created app %s in organization %s\n", app.Name, org.Slug)
+
+	// Attach the database before launching machines, so that they start with
+	// DATABASE_URL set.
+	if pgAppName != "" {
+		err := postgres.AttachCluster(ctx, postgres.AttachParams{
+			AppName:   app.Name,
+			PgAppName: pgAppName,
+		})
+		if err != nil {
+			return fmt.Errorf("failed attaching postgres cluster %s: %w", pgAppName, err)
+		}
+	}
+
+	volumes, err := cloneVolumes(ctx, app, export.Volumes, machines, region)
+	if err != nil {
+		return err
+	}
+
+	var dropped []string
+	for _, m := range machines {
+		config, removed := stagingConfig(m.Config, guest)
+		dropped = append(dropped, removed...)
+
+		if err := mach.RemapMounts(config, volumes); err != nil {
+			return fmt.Errorf("failed cloning machine %s: %w", m.ID, err)
+		}
+
+		if err := launchMachine(ctx, app.Name, m, config, region); err != nil {
+			return err
+		}
+	}
+
+	if err := allocateIPAddresses(ctx, app.Name, export.IPAddresses); err != nil {
+		return err
+	}
+
+	if dropped = uniqueSorted(dropped); len(dropped) > 0 {
+		fmt.Fprintf(io.Out, "\nThese environment variables look sensitive and were not copied:\n")
+		for _, name := range dropped {
+			fmt.Fprintf(io.Out, "  %s\n", name)
+		}
+	}
+
+	var secrets []string
+	for _, name := range export.Secrets {
+		if name != "DATABASE_URL" || pgAppName == "" {
+			secrets = append(secrets, name)
+		}
+	}
+	if len(secrets) > 0 {
+		fmt.Fprintf(io.Out, "\nSecrets are not copied, set these with 'fly secrets set -a %s':\n", app.Name)
+		for _, name := range secrets {
+			fmt.Fprintf(io.Out, "  %s\n", name)
+		}
+	}
+
+	return nil
+}
+
+// checkPolicy returns an error if the policy of the organization forbids the
+// machines of the clone.
+func checkPolicy(ctx context.Context, orgSlug string, machines []orgs.MachineExport, guest *api.MachineGuest, region string) error {
+	p, err := policy.Load(ctx, orgSlug)
+	if err != nil {
+		return err
+	}
+
+	var violations []string
+	for _, m := range machines {
+		r := m.Region
+		if region != "" {
+			r = region
+		}
+		violations = append(violations, p.MachineViolations(r, stagingGuest(m.Config.Guest, guest))...)
+	}
+
+	return p.Error(uniqueSorted(violations))
+}
+
+// cloneVolumes creates copies of the volumes mounted by the machines and
+// returns the IDs of the new volumes by the IDs of the original ones.
+func cloneVolumes(ctx context.Context, app *api.App, volumes []orgs.VolumeExport, machines []orgs.MachineExport, region string) (map[string]string, error) {
+	var (
+		out       = iostreams.FromContext(ctx).Out
+		apiClient = client.FromContext(ctx).API()
+		fork      = flag.GetBool(ctx, "fork-volumes")
+		ids       = map[string]string{}
+	)
+
+	mounted := map[string]bool{}
+	for _, m := range machines {
+		for _, mount := range m.Config.Mounts {
+			mounted[mount.Volume] = true
+		}
+	}
+
+	for _, v := range volumes {
+		if !mounted[v.ID] {
+			continue
+		}
+
+		input := api.CreateVolumeInput{
+			AppID:     app.ID,
+			Name:      v.Name,
+			Region:    v.Region,
+			SizeGb:    v.SizeGb,
+			Encrypted: v.Encrypted,
+		}
+		if region != "" {
+			input.Region = region
+		}
+
+		if fork {
+			snapshots, err := apiClient.GetVolumeSnapshots(ctx, v.ID)
+			if err != nil {
+				return nil, fmt.Errorf("failed retrieving snapshots of volume %s: %w", v.ID, err)
+			}
+			if snapshot := latestSnapshot(snapshots); snapshot != nil {
+				input.SnapshotID = api.StringPointer(snapshot.ID)
+			} else {
+				fmt.Fprintf(out, "Volume %s (%s) has no snapshots, its copy is created empty\n", v.Name, v.ID)
+			}
+		}
+
+		volume, err := apiClient.CreateVolume(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed creating volume %s: %w", v.Name, err)
+		}
+		ids[v.ID] = volume.ID
+
+		if input.SnapshotID != nil {
+			fmt.Fprintf(out, "Created volume %s (%s) of %dGB in %s from snapshot %s\n", volume.Name, volume.ID, volume.SizeGb, volume.Region, *input.SnapshotID)
+		} else {
+			fmt.Fprintf(out, "Created volume %s (%s) of %dGB in %s\n", volume.Name, volume.ID, volume.SizeGb, volume.Region)
+		}
+	}
+
+	return ids, nil
+}
+
+func launchMachine(ctx context.Context, appName string, m orgs.MachineExport, config *api.MachineConfig, region string) error {
+	flapsClient, err := flaps.NewFromAppName(ctx, appName)
+	if err != nil {
+		return err
+	}
+
+	input := api.LaunchMachineInput{
+		Name:   m.Name,
+		Region: m.Region,
+		Config: config,
+	}
+	if region != "" {
+		input.Region = region
+	}
+
+	machine, err := flapsClient.Launch(ctx, input)
+	if err != nil {
+		return fmt.Errorf("failed cloning machine %s: %w", m.ID, err)
+	}
+
+	fmt.Fprintf(iostreams.FromContext(ctx).Out, "Created machine %s in %s from %s\n", machine.ID, machine.Region, m.ID)
+
+	return nil
+}
+
+// allocateIPAddresses gives the clone a shared IPv4 and an IPv6 address when
+// the original app has public addresses. Dedicated IPv4 addresses aren't
+// copied, as they're billed.
+func allocateIPAddresses(ctx context.Context, appName string, ips []orgs.IPAddressExport) error {
+	var (
+		out       = iostreams.FromContext(ctx).Out
+		apiClient = client.FromContext(ctx).API()
+		public    bool
+	)
+
+	for _, ip := range ips {
+		if ip.Type == "v4" || ip.Type == "v6" {
+			public = true
+		}
+	}
+	if !public {
+		return nil
+	}
+
+	v6, err := apiClient.AllocateIPAddress(ctx, appName, "v6", "", nil, "")
+	if err != nil {
+		return fmt.Errorf("failed allocating v6 address: %w", err)
+	}
+	fmt.Fprintf(out, "Allocated v6 address %s\n", v6.Address)
+
+	v4, err := apiClient.AllocateSharedIPAddress(ctx, appName)
+	if err != nil {
+		return fmt.Errorf("failed allocating shared v4 address: %w", err)
+	}
+	fmt.Fprintf(out, "Allocated shared v4 address %s\n", v4)
+
+	return nil
+}
+
+// stagingMachines returns the first machine of each process group.
+func stagingMachines(machines []orgs.MachineExport) []orgs.MachineExport {
+	var (
+		kept []orgs.MachineExport
+		seen = map[string]bool{}
+	)
+
+	for _, m := range machines {
+		if m.Config == nil {
+			continue
+		}
+		group := m.Config.ProcessGroup()
+		if seen[group] {
+			continue
+		}
+		seen[group] = true
+		kept = append(kept, m)
+	}
+
+	return kept
+}
+
+// stagingConfig returns a copy of the config running on the guest, without
+// the environment variables which look sensitive, and the names of those.
+func stagingConfig(config *api.MachineConfig, guest *api.MachineGuest) (*api.MachineConfig, []string) {
+	c := *config
+	c.Guest = stagingGuest(config.Guest, guest)
+
+	var dropped []string
+	if config.Env != nil {
+		c.Env = make(map[string]string, len(config.Env))
+		for name, value := range config.Env {
+			if sensitiveEnv(name) {
+				dropped = append(dropped, name)
+				continue
+			}
+			c.Env[name] = value
+		}
+	}
+	sort.Strings(dropped)
+
+	return &c, dropped
+}
+
+// stagingGuest returns the guest of the clone of a machine, keeping the
+// original guest when it isn't larger.
+func stagingGuest(original, guest *api.MachineGuest) *api.MachineGuest {
+	if original != nil && original.CPUKind == guest.CPUKind && original.CPUs <= guest.CPUs && original.MemoryMB <= guest.MemoryMB {
+		g := *original
+		return &g
+	}
+
+	g := *guest
+	return &g
+}
+
+var sensitiveWords = []string{"SECRET", "TOKEN", "PASSWORD", "PASSWD", "KEY", "CREDENTIAL", "PRIVATE", "DSN", "DATABASE_URL"}
+
+// sensitiveEnv reports whether the name of an environment variable suggests it
+// holds a credential.
+func sensitiveEnv(name string) bool {
+	name = strings.ToUpper(name)
+	for _, word := range sensitiveWords {
+		if strings.Contains(name, word) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func latestSnapshot(snapshots []api.Snapshot) *api.Snapshot {
+	var latest *api.Snapshot
+	for i := range snapshots {
+		if latest == nil || snapshots[i].CreatedAt.After(latest.CreatedAt) {
+			latest = &snapshots[i]
+		}
+	}
+
+	return latest
+}
+
+func uniqueSorted(values []string) []string {
+	sort.Strings(values)
+
+	var unique []string
+	for i, v := range values {
+		if i == 0 || v != values[i-1] {
+			unique = append(unique, v)
+		}
+	}
+
+	return unique
+}
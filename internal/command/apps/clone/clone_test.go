@@ -0,0 +1,68 @@
+package clone
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/internal/command/orgs"
+)
+
+func TestStagingMachines(t *testing.T) {
+	machine := func(id, group string) orgs.MachineExport {
+		return orgs.MachineExport{
+			ID: id,
+			Config: &api.MachineConfig{
+				Metadata: map[string]string{api.MachineConfigMetadataKeyFlyProcessGroup: group},
+			},
+		}
+	}
+
+	kept := stagingMachines([]orgs.MachineExport{
+		machine("1", "app"),
+		machine("2", "app"),
+		{ID: "3"},
+		machine("4", "worker"),
+		machine("5", "worker"),
+	})
+
+	ids := make([]string, 0, len(kept))
+	for _, m := range kept {
+		ids = append(ids, m.ID)
+	}
+	assert.Equal(t, []string{"1", "4"}, ids)
+}
+
+func TestStagingConfig(t *testing.T) {
+	original := &api.MachineConfig{
+		Env: map[string]string{
+			"PORT":          "8080",
+			"STRIPE_KEY":    "sk_live",
+			"smtp_password": "hunter2",
+			"LOG_LEVEL":     "info",
+		},
+		Guest: &api.MachineGuest{CPUKind: "performance", CPUs: 2, MemoryMB: 4096},
+	}
+	small := api.MachinePresets["shared-cpu-1x"]
+
+	config, dropped := stagingConfig(original, small)
+
+	assert.Equal(t, map[string]string{"PORT": "8080", "LOG_LEVEL": "info"}, config.Env)
+	assert.Equal(t, []string{"STRIPE_KEY", "smtp_password"}, dropped)
+	assert.Equal(t, *small, *config.Guest)
+	assert.Len(t, original.Env, 4, "original config is left untouched")
+	assert.Equal(t, "performance", original.Guest.CPUKind)
+}
+
+func TestStagingGuest(t *testing.T) {
+	preset := &api.MachineGuest{CPUKind: "shared", CPUs: 2, MemoryMB: 512}
+
+	smaller := &api.MachineGuest{CPUKind: "shared", CPUs: 1, MemoryMB: 256}
+	assert.Equal(t, *smaller, *stagingGuest(smaller, preset))
+
+	larger := &api.MachineGuest{CPUKind: "shared", CPUs: 4, MemoryMB: 1024}
+	assert.Equal(t, *preset, *stagingGuest(larger, preset))
+
+	assert.Equal(t, *preset, *stagingGuest(nil, preset))
+}
@@ -117,7 +117,7 @@ func RunCreate(ctx context.Context) (err error) {
 
 	if err == nil {
 		if cfg.JSONOutput {
-			return render.JSON(io.Out, app)
+			return render.Output(ctx, io.Out, app)
 		}
 		fmt.Fprintf(io.Out, "New app created: %s\n", app.Name)
 	}
@@ -0,0 +1,119 @@
+package apps
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/command/orgs"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newExport() *cobra.Command {
+	const (
+		long = `The APPS EXPORT command exports the state of an application as a
+single document: its configuration, machines and their configs, volume
+metadata, IP addresses, certificates and the names (never the values) of its
+secrets. The document may be recreated elsewhere with 'fly apps import'.
+
+Volume contents aren't part of the export; back them up separately.
+`
+		short = "Export the state of an application"
+		usage = "export <APPNAME>"
+	)
+
+	cmd := command.New(usage, short, long, runExport,
+		command.RequireSession,
+	)
+
+	cmd.Args = cobra.ExactArgs(1)
+
+	flag.Add(cmd,
+		flag.String{
+			Name:        "format",
+			Description: "The format of the export, json or yaml",
+			Default:     "json",
+		},
+		flag.String{
+			Name:        "output",
+			Shorthand:   "o",
+			Description: "Write the export to this file instead of stdout",
+		},
+	)
+
+	return cmd
+}
+
+func runExport(ctx context.Context) (err error) {
+	var (
+		appName = flag.FirstArg(ctx)
+		format  = flag.GetString(ctx, "format")
+		client  = client.FromContext(ctx).API()
+	)
+
+	if format != "json" && format != "yaml" {
+		return fmt.Errorf("unsupported export format %q, use json or yaml", format)
+	}
+
+	app, err := client.GetApp(ctx, appName)
+	if err != nil {
+		return fmt.Errorf("failed fetching app: %w", err)
+	}
+
+	export, err := orgs.ExportApp(ctx, *app)
+	if err != nil {
+		return fmt.Errorf("failed exporting app %s: %w", app.Name, err)
+	}
+
+	bundle := &appBundle{
+		Version:      bundleVersion,
+		ExportedAt:   time.Now().UTC(),
+		Organization: app.Organization.Slug,
+		AppExport:    *export,
+	}
+
+	if app.Deployed {
+		if app.PlatformVersion == appconfig.MachinesPlatform {
+			flapsClient, err := flaps.NewFromAppName(ctx, app.Name)
+			if err != nil {
+				return err
+			}
+			ctx = flaps.NewContext(ctx, flapsClient)
+		}
+
+		cfg, err := appconfig.FromRemoteApp(ctx, app.Name)
+		if err != nil {
+			return fmt.Errorf("failed fetching app config: %w", err)
+		}
+		definition, err := cfg.ToDefinition()
+		if err != nil {
+			return fmt.Errorf("failed encoding app config: %w", err)
+		}
+		bundle.Config = *definition
+	}
+
+	out := iostreams.FromContext(ctx).Out
+	if path := flag.GetString(ctx, "output"); path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("failed creating export file: %w", err)
+		}
+		defer func() {
+			if e := f.Close(); err == nil {
+				err = e
+			}
+		}()
+
+		out = f
+	}
+
+	return writeBundle(out, bundle, format)
+}
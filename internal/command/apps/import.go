@@ -0,0 +1,320 @@
+package apps
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/command/orgs"
+	"github.com/superfly/flyctl/internal/flag"
+	mach "github.com/superfly/flyctl/internal/machine"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newImport() *cobra.Command {
+	const (
+		long = `The APPS IMPORT command recreates an application from a document
+written by 'fly apps export', in the same or another organization. Its
+volumes, machines, IP addresses and certificates are created anew; pass
+--region to move all of them to a single region.
+
+Secret values aren't exported. Pass --secrets-from-env to read them from
+environment variables of the same names; the names of secrets left unset are
+listed once the import is done. Volumes are created empty.
+`
+		short = "Recreate an application from an export"
+		usage = "import <PATH>"
+	)
+
+	cmd := command.New(usage, short, long, runImport,
+		command.RequireSession,
+	)
+
+	cmd.Args = cobra.ExactArgs(1)
+
+	flag.Add(cmd,
+		flag.Org(),
+		flag.Region(),
+		flag.String{
+			Name:        "name",
+			Description: "The name of the new app, defaults to the name of the exported app",
+		},
+		flag.Bool{
+			Name:        "secrets-from-env",
+			Description: "Set the secrets of the app from environment variables of the same names",
+		},
+		flag.String{
+			Name:        "save-config",
+			Description: "Write the fly.toml of the new app to this path",
+		},
+	)
+
+	return cmd
+}
+
+func runImport(ctx context.Context) error {
+	var (
+		io        = iostreams.FromContext(ctx)
+		apiClient = client.FromContext(ctx).API()
+		path      = flag.FirstArg(ctx)
+		region    = flag.GetRegion(ctx)
+	)
+
+	bundle, err := readBundleFile(path)
+	if err != nil {
+		return err
+	}
+	if bundle.PlatformVersion != appconfig.MachinesPlatform {
+		return fmt.Errorf("only apps on the machines platform can be imported, %s is on %s", bundle.Name, bundle.PlatformVersion)
+	}
+
+	name := flag.GetString(ctx, "name")
+	if name == "" {
+		name = bundle.Name
+	}
+
+	org, err := prompt.Org(ctx)
+	if err != nil {
+		return err
+	}
+
+	input := api.CreateAppInput{
+		Name:           name,
+		OrganizationID: org.ID,
+		Machines:       true,
+	}
+	if region != "" {
+		input.PreferredRegion = api.StringPointer(region)
+	}
+
+	app, err := apiClient.CreateApp(ctx, input)
+	if err != nil {
+		return fmt.Errorf("failed creating app %s: %w", name, err)
+	}
+	fmt.Fprintf(io.Out, "Created app %s in organization %s\n", app.Name, org.Slug)
+
+	missing, err := importSecrets(ctx, app.Name, bundle.Secrets)
+	if err != nil {
+		return err
+	}
+
+	volumes, err := importVolumes(ctx, app, bundle.Volumes, region)
+	if err != nil {
+		return err
+	}
+
+	if err := importMachines(ctx, app.Name, bundle.Machines, volumes, region); err != nil {
+		return err
+	}
+
+	if err := importIPAddresses(ctx, app.Name, bundle.IPAddresses); err != nil {
+		return err
+	}
+
+	for _, cert := range bundle.Certificates {
+		if _, _, err := apiClient.AddCertificate(ctx, app.Name, cert.Hostname); err != nil {
+			return fmt.Errorf("failed adding certificate for %s: %w", cert.Hostname, err)
+		}
+		fmt.Fprintf(io.Out, "Added certificate for %s, update its DNS records to point at the new app\n", cert.Hostname)
+	}
+
+	if path := flag.GetString(ctx, "save-config"); path != "" && bundle.Config != nil {
+		cfg, err := appconfig.FromDefinition(&bundle.Config)
+		if err != nil {
+			return fmt.Errorf("failed decoding app config: %w", err)
+		}
+		cfg.AppName = app.Name
+		if region != "" {
+			cfg.PrimaryRegion = region
+		}
+		if err := cfg.WriteToDisk(ctx, path); err != nil {
+			return fmt.Errorf("failed writing app config: %w", err)
+		}
+	}
+
+	if len(missing) > 0 {
+		fmt.Fprintf(io.Out, "\nThese secrets were not set, set them with 'fly secrets set -a %s':\n", app.Name)
+		for _, name := range missing {
+			fmt.Fprintf(io.Out, "  %s\n", name)
+		}
+	}
+
+	return nil
+}
+
+func readBundleFile(path string) (*appBundle, error) {
+	var (
+		data []byte
+		err  error
+	)
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed reading export: %w", err)
+	}
+
+	bundle, err := readBundle(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing export %s: %w", path, err)
+	}
+
+	return bundle, nil
+}
+
+// importSecrets sets the secrets found in the environment when requested and
+// returns the names of those left unset.
+func importSecrets(ctx context.Context, appName string, names []string) (missing []string, err error) {
+	if !flag.GetBool(ctx, "secrets-from-env") {
+		return names, nil
+	}
+
+	values := map[string]string{}
+	for _, name := range names {
+		if value, ok := os.LookupEnv(name); ok {
+			values[name] = value
+		} else {
+			missing = append(missing, name)
+		}
+	}
+
+	if len(values) == 0 {
+		return missing, nil
+	}
+
+	if _, err := client.FromContext(ctx).API().SetSecrets(ctx, appName, values); err != nil {
+		return nil, fmt.Errorf("failed setting secrets: %w", err)
+	}
+	fmt.Fprintf(iostreams.FromContext(ctx).Out, "Set %d secrets from the environment\n", len(values))
+
+	return missing, nil
+}
+
+// importVolumes creates empty copies of the volumes and returns the IDs of the
+// new volumes by the IDs of the exported ones.
+func importVolumes(ctx context.Context, app *api.App, volumes []orgs.VolumeExport, region string) (map[string]string, error) {
+	var (
+		out       = iostreams.FromContext(ctx).Out
+		apiClient = client.FromContext(ctx).API()
+		ids       = make(map[string]string, len(volumes))
+	)
+
+	for _, v := range volumes {
+		input := api.CreateVolumeInput{
+			AppID:     app.ID,
+			Name:      v.Name,
+			Region:    v.Region,
+			SizeGb:    v.SizeGb,
+			Encrypted: v.Encrypted,
+		}
+		if region != "" {
+			input.Region = region
+		}
+
+		volume, err := apiClient.CreateVolume(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed creating volume %s: %w", v.Name, err)
+		}
+		ids[v.ID] = volume.ID
+
+		fmt.Fprintf(out, "Created volume %s (%s) of %dGB in %s\n", volume.Name, volume.ID, volume.SizeGb, volume.Region)
+	}
+
+	return ids, nil
+}
+
+// importMachines launches copies of the machines, mounting the new volumes in
+// place of the exported ones.
+func importMachines(ctx context.Context, appName string, machines []orgs.MachineExport, volumes map[string]string, region string) error {
+	out := iostreams.FromContext(ctx).Out
+
+	flapsClient, err := flaps.NewFromAppName(ctx, appName)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range machines {
+		if m.Config == nil {
+			continue
+		}
+
+		config := *m.Config
+		if err := mach.RemapMounts(&config, volumes); err != nil {
+			return fmt.Errorf("failed recreating machine %s: %w", m.ID, err)
+		}
+
+		input := api.LaunchMachineInput{
+			Name:   m.Name,
+			Region: m.Region,
+			Config: &config,
+		}
+		if region != "" {
+			input.Region = region
+		}
+
+		machine, err := flapsClient.Launch(ctx, input)
+		if err != nil {
+			return fmt.Errorf("failed recreating machine %s: %w", m.ID, err)
+		}
+
+		fmt.Fprintf(out, "Created machine %s in %s from %s\n", machine.ID, machine.Region, m.ID)
+	}
+
+	return nil
+}
+
+// importIPAddresses allocates new public addresses of the same types as the
+// exported ones. Apps that had no dedicated IPv4 get a shared one, which
+// exports don't list.
+func importIPAddresses(ctx context.Context, appName string, ips []orgs.IPAddressExport) error {
+	var (
+		out       = iostreams.FromContext(ctx).Out
+		apiClient = client.FromContext(ctx).API()
+		types     []string
+		public    bool
+		hasV4     bool
+	)
+
+	for _, ip := range ips {
+		switch ip.Type {
+		case "v4":
+			hasV4 = true
+		case "v6":
+		default:
+			continue
+		}
+		public = true
+		types = append(types, ip.Type)
+	}
+	sort.Strings(types)
+
+	for _, t := range types {
+		ip, err := apiClient.AllocateIPAddress(ctx, appName, t, "", nil, "")
+		if err != nil {
+			return fmt.Errorf("failed allocating %s address: %w", t, err)
+		}
+		fmt.Fprintf(out, "Allocated %s address %s\n", t, ip.Address)
+	}
+
+	if public && !hasV4 {
+		ip, err := apiClient.AllocateSharedIPAddress(ctx, appName)
+		if err != nil {
+			return fmt.Errorf("failed allocating shared v4 address: %w", err)
+		}
+		fmt.Fprintf(out, "Allocated shared v4 address %s\n", ip)
+	}
+
+	return nil
+}
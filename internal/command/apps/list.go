@@ -59,7 +59,7 @@ func runList(ctx context.Context) (err error) {
 
 	out := iostreams.FromContext(ctx).Out
 	if cfg.JSONOutput {
-		_ = render.JSON(out, apps)
+		_ = render.Output(ctx, out, apps)
 
 		return
 	}
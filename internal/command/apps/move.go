@@ -20,6 +20,12 @@ func newMove() *cobra.Command {
 	const (
 		long = `The APPS MOVE command will move an application to another
 organization the current user belongs to.
+
+Before moving the app, it checks what stands in the way: Postgres clusters
+the app is attached to in its current organization, which it can't reach
+once moved unless they're moved along with --move-postgres, volumes and
+certificates whose DNS is managed in the current organization. Pass
+--dry-run to only run the checks.
 `
 		short = "Move an app to another organization"
 		usage = "move <APPNAME>"
@@ -39,6 +45,14 @@ organization the current user belongs to.
 			Description: "Update machines without waiting for health checks. (Machines only)",
 			Default:     false,
 		},
+		flag.Bool{
+			Name:        "move-postgres",
+			Description: "Move the Postgres clusters the app is attached to along with it, when no other app uses them",
+		},
+		flag.Bool{
+			Name:        "dry-run",
+			Description: "Only check what stands in the way of moving the app",
+		},
 	)
 
 	return move
@@ -70,6 +84,32 @@ func RunMove(ctx context.Context) error {
 		return nil
 	}
 
+	checks, clusters, err := moveChecks(ctx, app, flag.GetBool(ctx, "move-postgres"))
+	if err != nil {
+		return err
+	}
+
+	var blockers []string
+	for _, c := range checks {
+		bullet := colorize.Yellow("*")
+		if c.Blocker {
+			bullet = colorize.Red("✗")
+			blockers = append(blockers, c.Message)
+		}
+		fmt.Fprintf(io.Out, "%s %s\n", bullet, c.Message)
+		if c.Fix != "" {
+			fmt.Fprintf(io.Out, "  %s\n", c.Fix)
+		}
+	}
+
+	switch {
+	case len(blockers) > 0:
+		return fmt.Errorf("%s can't be moved to %s until %d blockers are resolved", app.Name, org.Slug, len(blockers))
+	case flag.GetBool(ctx, "dry-run"):
+		fmt.Fprintf(io.Out, "%s can be moved to %s\n", app.Name, org.Slug)
+		return nil
+	}
+
 	if !flag.GetYes(ctx) {
 		const msg = `Moving an app between organizations requires a complete shutdown and restart. This will result in some app downtime.
 If the app relies on other services within the current organization, it may not come back up in a healthy manner.
@@ -88,17 +128,33 @@ Please confirm whether you wish to restart this app now.`
 		}
 	}
 
+	// Move the clusters first, so that the app can reach them again as soon
+	// as it's moved.
+	for _, c := range clusters {
+		pgApp, err := client.GetAppCompact(ctx, c.App.Name)
+		if err != nil {
+			return fmt.Errorf("failed fetching postgres app %s: %w", c.App.Name, err)
+		}
+		if err := moveApp(ctx, pgApp, org); err != nil {
+			return err
+		}
+	}
+
+	return moveApp(ctx, app, org)
+}
+
+func moveApp(ctx context.Context, app *api.AppCompact, org *api.Organization) error {
 	// Run machine specific migration process.
 	if app.PlatformVersion == "machines" {
 		return runMoveAppOnMachines(ctx, app, org)
 	}
 
-	_, err = client.MoveApp(ctx, appName, org.ID)
+	_, err := client.FromContext(ctx).API().MoveApp(ctx, app.Name, org.ID)
 	if err != nil {
 		return fmt.Errorf("failed moving app: %w", err)
 	}
 
-	fmt.Fprintf(io.Out, "successfully moved %s to %s\n", appName, org.Slug)
+	fmt.Fprintf(iostreams.FromContext(ctx).Out, "successfully moved %s to %s\n", app.Name, org.Slug)
 
 	return nil
 }
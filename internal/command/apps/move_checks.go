@@ -0,0 +1,165 @@
+package apps
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/gql"
+)
+
+// moveCheck is something standing in the way of moving an app to another
+// organization, or worth knowing before doing so.
+type moveCheck struct {
+	// Blocker checks stop the move until they're resolved.
+	Blocker bool
+	Message string
+	// Fix is how to resolve the check, if anything can be done.
+	Fix string
+}
+
+// attachedCluster is a Postgres cluster of the current organization of the
+// app that the app is attached to.
+type attachedCluster struct {
+	App       *api.App
+	Variables []string
+	// Shared is whether other apps are attached to the cluster too.
+	Shared bool
+}
+
+// moveChecks returns the checks of moving the app out of its organization,
+// along with the Postgres clusters it is attached to there.
+func moveChecks(ctx context.Context, app *api.AppCompact, movePostgres bool) ([]moveCheck, []attachedCluster, error) {
+	apiClient := client.FromContext(ctx).API()
+
+	clusters, err := attachedClusters(ctx, app)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var checks []moveCheck
+	for _, c := range clusters {
+		vars := strings.Join(c.Variables, ", ")
+		switch {
+		case c.Shared:
+			checks = append(checks, moveCheck{
+				Blocker: true,
+				Message: fmt.Sprintf("%s is attached to the Postgres cluster %s through %s, which other apps of %s use too and the app can't reach once moved", app.Name, c.App.Name, vars, app.Organization.Slug),
+				Fix:     fmt.Sprintf("detach it with 'fly postgres detach %s -a %s', then attach a cluster of the new organization once moved", c.App.Name, app.Name),
+			})
+		case !movePostgres:
+			checks = append(checks, moveCheck{
+				Blocker: true,
+				Message: fmt.Sprintf("%s is attached to the Postgres cluster %s through %s, which the app can't reach once moved", app.Name, c.App.Name, vars),
+				Fix:     fmt.Sprintf("pass --move-postgres to move %s along, keeping %s as is", c.App.Name, vars),
+			})
+		default:
+			checks = append(checks, moveCheck{
+				Message: fmt.Sprintf("The Postgres cluster %s will be moved along, %s keeps pointing to it", c.App.Name, vars),
+			})
+		}
+	}
+
+	volumes, err := apiClient.GetVolumes(ctx, app.Name)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed retrieving volumes: %w", err)
+	}
+	if len(volumes) > 0 {
+		if app.PlatformVersion == "nomad" {
+			checks = append(checks, moveCheck{
+				Blocker: true,
+				Message: fmt.Sprintf("%s has %d volumes, which apps of the nomad platform can't take along", app.Name, len(volumes)),
+				Fix:     fmt.Sprintf("migrate the app to machines first with 'fly migrate-to-v2 -a %s'", app.Name),
+			})
+		} else {
+			checks = append(checks, moveCheck{
+				Message: fmt.Sprintf("The %d volumes of %s stay attached to its machines, which restart with them", len(volumes), app.Name),
+			})
+		}
+	}
+
+	certs, err := apiClient.GetAppCertificates(ctx, app.Name)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed retrieving certificates: %w", err)
+	}
+	domains, err := apiClient.GetDomains(ctx, app.Organization.Slug)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed retrieving domains: %w", err)
+	}
+	for _, cert := range certs {
+		if zone := zoneOf(cert.Hostname, domains); zone != "" {
+			checks = append(checks, moveCheck{
+				Message: fmt.Sprintf("The DNS of %s is managed in the zone %s, which stays in %s", cert.Hostname, zone, app.Organization.Slug),
+				Fix:     "keep its records pointing to the addresses of the app, they don't change when it moves",
+			})
+		}
+	}
+
+	return checks, clusters, nil
+}
+
+// zoneOf returns the name of the DNS zone the hostname belongs to, if any.
+func zoneOf(hostname string, domains []*api.Domain) string {
+	hostname = strings.TrimSuffix(strings.ToLower(hostname), ".")
+	for _, d := range domains {
+		zone := strings.ToLower(d.Name)
+		if hostname == zone || strings.HasSuffix(hostname, "."+zone) {
+			return d.Name
+		}
+	}
+
+	return ""
+}
+
+// attachedClusters returns the Postgres clusters of the organization of the
+// app which it is attached to.
+func attachedClusters(ctx context.Context, app *api.AppCompact) ([]attachedCluster, error) {
+	apiClient := client.FromContext(ctx).API()
+
+	pgApps, err := apiClient.GetApps(ctx, api.StringPointer("postgres_cluster"))
+	if err != nil {
+		return nil, fmt.Errorf("failed listing postgres clusters: %w", err)
+	}
+
+	_ = `# @genqlient
+	query PostgresAttachmentCount($postgresAppName: String!) {
+		postgresAttachments(postgresAppName: $postgresAppName) {
+			totalCount
+		}
+	}
+	`
+
+	var clusters []attachedCluster
+	for i := range pgApps {
+		pgApp := &pgApps[i]
+		if pgApp.Organization.Slug != app.Organization.Slug || pgApp.Name == app.Name {
+			continue
+		}
+
+		attachments, err := apiClient.ListPostgresClusterAttachments(ctx, app.Name, pgApp.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed listing attachments of %s: %w", pgApp.Name, err)
+		}
+		if len(attachments) == 0 {
+			continue
+		}
+
+		resp, err := gql.PostgresAttachmentCount(ctx, apiClient.GenqClient, pgApp.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed listing attachments of %s: %w", pgApp.Name, err)
+		}
+
+		c := attachedCluster{
+			App:    pgApp,
+			Shared: resp.PostgresAttachments.TotalCount > len(attachments),
+		}
+		for _, a := range attachments {
+			c.Variables = append(c.Variables, a.EnvironmentVariableName)
+		}
+		clusters = append(clusters, c)
+	}
+
+	return clusters, nil
+}
@@ -76,7 +76,7 @@ func runReleases(ctx context.Context) error {
 
 	out := iostreams.FromContext(ctx).Out
 	if config.FromContext(ctx).JSONOutput {
-		return render.JSON(out, releases)
+		return render.Output(ctx, out, releases)
 	}
 
 	var (
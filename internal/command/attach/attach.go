@@ -0,0 +1,193 @@
+// Package attach implements the attach command chain.
+package attach
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/command/secrets"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+var sharedFlags = flag.Set{
+	flag.App(),
+	flag.AppConfig(),
+	flag.Detach(),
+	flag.Bool{
+		Name:        "stage",
+		Description: "Set secrets but skip deployment for machine apps",
+	},
+	flag.String{
+		Name:        "binding",
+		Description: "The name of the binding, required when the provider app exposes more than one",
+	},
+}
+
+func New() *cobra.Command {
+	const (
+		long = `Attach an app to a service provided by another app of the organization.
+
+Provider apps expose bindings in their fly.toml, each rendering a connection
+string from a template:
+
+  [[bindings]]
+    name = "redis"
+    template = "redis://{{ .Hostname }}:6379"
+    secret = "REDIS_URL"
+
+Attaching renders the template and sets the result as a secret of the
+consuming app. Templates may refer to {{ .App }}, {{ .Hostname }} (the
+provider's .internal hostname), {{ .Flycast }} (its .flycast hostname) and
+{{ .Consumer }}, the name of the consuming app.
+`
+		short = "Attach an app to a service provided by another app"
+		usage = "attach <provider-app>"
+	)
+
+	cmd := command.New(usage, short, long, runAttach,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	cmd.Args = cobra.ExactArgs(1)
+
+	flag.Add(cmd,
+		sharedFlags,
+		flag.String{
+			Name:        "secret-name",
+			Description: "The name of the secret to set, defaults to the one the binding names",
+		},
+	)
+
+	cmd.AddCommand(
+		newList(),
+		newDetach(),
+	)
+
+	return cmd
+}
+
+// bindingData is the data binding templates are rendered with.
+type bindingData struct {
+	App      string
+	Hostname string
+	Flycast  string
+	Consumer string
+}
+
+func runAttach(ctx context.Context) error {
+	var (
+		io           = iostreams.FromContext(ctx)
+		client       = client.FromContext(ctx).API()
+		consumerName = appconfig.NameFromContext(ctx)
+		providerName = flag.FirstArg(ctx)
+	)
+
+	if providerName == consumerName {
+		return fmt.Errorf("an app can't be attached to itself")
+	}
+
+	consumer, err := client.GetAppCompact(ctx, consumerName)
+	if err != nil {
+		return fmt.Errorf("failed retrieving app %s: %w", consumerName, err)
+	}
+
+	binding, err := selectBinding(ctx, providerName, flag.GetString(ctx, "binding"))
+	if err != nil {
+		return err
+	}
+
+	secretName := flag.GetString(ctx, "secret-name")
+	if secretName == "" {
+		secretName = binding.Secret
+	}
+	if secretName == "" {
+		return fmt.Errorf("binding %s of %s names no secret, specify one with --secret-name", binding.Name, providerName)
+	}
+	if !secretNamePattern.MatchString(secretName) {
+		return fmt.Errorf("invalid secret name %q", secretName)
+	}
+
+	value, err := renderBinding(binding, bindingData{
+		App:      providerName,
+		Hostname: providerName + ".internal",
+		Flycast:  providerName + ".flycast",
+		Consumer: consumerName,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(io.Out, "Attaching %s to binding %s of %s as secret %s\n", consumerName, binding.Name, providerName, secretName)
+
+	return secrets.SetSecretsAndDeploy(ctx, consumer, map[string]string{
+		secretName: value,
+		attachment{Provider: providerName, Binding: binding.Name, Secret: secretName}.markerName(): providerName + "/" + binding.Name,
+	}, flag.GetBool(ctx, "stage"), flag.GetDetach(ctx))
+}
+
+// selectBinding returns the binding of the provider app with the given name,
+// or its only binding if name is empty.
+func selectBinding(ctx context.Context, providerName, name string) (*appconfig.Binding, error) {
+	cfg, err := appconfig.FromRemoteApp(ctx, providerName)
+	if err != nil {
+		return nil, fmt.Errorf("failed retrieving the config of %s: %w", providerName, err)
+	}
+
+	bindings := cfg.Bindings
+	switch {
+	case len(bindings) == 0:
+		return nil, fmt.Errorf("%s exposes no bindings, add a [[bindings]] section to its fly.toml", providerName)
+	case name != "":
+		for i := range bindings {
+			if bindings[i].Name == name {
+				return &bindings[i], nil
+			}
+		}
+		return nil, fmt.Errorf("%s exposes no binding named %s", providerName, name)
+	case len(bindings) == 1:
+		return &bindings[0], nil
+	}
+
+	names := make([]string, 0, len(bindings))
+	for _, b := range bindings {
+		names = append(names, b.Name)
+	}
+
+	var index int
+	switch err := prompt.Select(ctx, &index, "Select a binding:", "", names...); {
+	case prompt.IsNonInteractive(err):
+		return nil, fmt.Errorf("--binding must be specified when not running interactively, %s exposes %s", providerName, strings.Join(names, ", "))
+	case err != nil:
+		return nil, err
+	}
+
+	return &bindings[index], nil
+}
+
+func renderBinding(binding *appconfig.Binding, data bindingData) (string, error) {
+	tmpl, err := template.New(binding.Name).Option("missingkey=error").Parse(binding.Template)
+	if err != nil {
+		return "", fmt.Errorf("invalid template for binding %s: %w", binding.Name, err)
+	}
+
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("failed rendering binding %s: %w", binding.Name, err)
+	}
+
+	return b.String(), nil
+}
+
+var secretNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
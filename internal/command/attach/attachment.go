@@ -0,0 +1,67 @@
+package attach
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/superfly/flyctl/client"
+)
+
+// Attachments are recorded on the consuming app as marker secrets, named after
+// the provider app, the binding and the secret the attachment set. App and
+// binding names can't contain underscores, which makes the name reversible.
+const markerPrefix = "FLY_ATTACHMENT_"
+
+type attachment struct {
+	Provider string `json:"provider"`
+	Binding  string `json:"binding"`
+	Secret   string `json:"secret"`
+}
+
+func (a attachment) markerName() string {
+	return markerPrefix + envName(a.Provider) + "__" + envName(a.Binding) + "__" + a.Secret
+}
+
+func envName(name string) string {
+	return strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+func parseMarkerName(name string) (attachment, bool) {
+	rest, ok := strings.CutPrefix(name, markerPrefix)
+	if !ok {
+		return attachment{}, false
+	}
+
+	parts := strings.SplitN(rest, "__", 3)
+	if len(parts) != 3 {
+		return attachment{}, false
+	}
+
+	unenv := func(s string) string {
+		return strings.ToLower(strings.ReplaceAll(s, "_", "-"))
+	}
+
+	return attachment{
+		Provider: unenv(parts[0]),
+		Binding:  unenv(parts[1]),
+		Secret:   parts[2],
+	}, true
+}
+
+// listAttachments returns the attachments of the consuming app.
+func listAttachments(ctx context.Context, consumerName string) ([]attachment, error) {
+	secrets, err := client.FromContext(ctx).API().GetAppSecrets(ctx, consumerName)
+	if err != nil {
+		return nil, fmt.Errorf("failed retrieving secrets of %s: %w", consumerName, err)
+	}
+
+	var attachments []attachment
+	for _, s := range secrets {
+		if a, ok := parseMarkerName(s.Name); ok {
+			attachments = append(attachments, a)
+		}
+	}
+
+	return attachments, nil
+}
@@ -0,0 +1,21 @@
+package attach
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkerName(t *testing.T) {
+	a := attachment{Provider: "my-redis", Binding: "primary-rw", Secret: "REDIS__URL"}
+
+	name := a.markerName()
+	assert.Equal(t, "FLY_ATTACHMENT_MY_REDIS__PRIMARY_RW__REDIS__URL", name)
+
+	parsed, ok := parseMarkerName(name)
+	assert.True(t, ok)
+	assert.Equal(t, a, parsed)
+
+	_, ok = parseMarkerName("DATABASE_URL")
+	assert.False(t, ok)
+}
@@ -0,0 +1,74 @@
+package attach
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/command/secrets"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newDetach() *cobra.Command {
+	const (
+		long = `Detach an app from a service provided by another app, unsetting the
+secrets set when attaching.
+`
+		short = "Detach an app from a service provided by another app"
+		usage = "detach <provider-app>"
+	)
+
+	cmd := command.New(usage, short, long, runDetach,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	cmd.Args = cobra.ExactArgs(1)
+
+	flag.Add(cmd,
+		sharedFlags,
+	)
+
+	return cmd
+}
+
+func runDetach(ctx context.Context) error {
+	var (
+		io           = iostreams.FromContext(ctx)
+		client       = client.FromContext(ctx).API()
+		consumerName = appconfig.NameFromContext(ctx)
+		providerName = flag.FirstArg(ctx)
+		bindingName  = flag.GetString(ctx, "binding")
+	)
+
+	consumer, err := client.GetAppCompact(ctx, consumerName)
+	if err != nil {
+		return fmt.Errorf("failed retrieving app %s: %w", consumerName, err)
+	}
+
+	attachments, err := listAttachments(ctx, consumerName)
+	if err != nil {
+		return err
+	}
+
+	var unset []string
+	for _, a := range attachments {
+		if a.Provider != providerName || (bindingName != "" && a.Binding != bindingName) {
+			continue
+		}
+
+		fmt.Fprintf(io.Out, "Detaching %s from binding %s of %s, unsetting %s\n", consumerName, a.Binding, a.Provider, a.Secret)
+		unset = append(unset, a.Secret, a.markerName())
+	}
+
+	if len(unset) == 0 {
+		return fmt.Errorf("%s isn't attached to %s", consumerName, providerName)
+	}
+
+	return secrets.UnsetSecretsAndDeploy(ctx, consumer, unset, flag.GetBool(ctx, "stage"), flag.GetDetach(ctx))
+}
@@ -0,0 +1,57 @@
+package attach
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newList() *cobra.Command {
+	const (
+		long  = "List the services an app is attached to"
+		short = long
+		usage = "list"
+	)
+
+	cmd := command.New(usage, short, long, runList,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	cmd.Aliases = []string{"ls"}
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.JSONOutput(),
+	)
+
+	return cmd
+}
+
+func runList(ctx context.Context) error {
+	io := iostreams.FromContext(ctx)
+
+	attachments, err := listAttachments(ctx, appconfig.NameFromContext(ctx))
+	if err != nil {
+		return err
+	}
+
+	if config.FromContext(ctx).JSONOutput {
+		return render.Output(ctx, io.Out, attachments)
+	}
+
+	rows := make([][]string, 0, len(attachments))
+	for _, a := range attachments {
+		rows = append(rows, []string{a.Provider, a.Binding, a.Secret})
+	}
+
+	return render.Table(io.Out, "", rows, "Provider", "Binding", "Secret")
+}
@@ -42,6 +42,7 @@ If you do have and account, begin with the AUTH LOGIN subcommand.
 		newDocker(),
 		newLogout(),
 		newSignup(),
+		newProfiles(),
 	)
 
 	return auth
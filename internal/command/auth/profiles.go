@@ -0,0 +1,170 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flyctl"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/internal/state"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newProfiles() *cobra.Command {
+	const (
+		long = `Profiles keep separate tokens, default organizations and config
+directories, e.g. for several Fly.io accounts. Select one with --profile or
+FLY_PROFILE for any command, and log in to it with
+'fly auth login --profile <name>'.
+`
+		short = "Manage profiles"
+	)
+
+	cmd := command.New("profiles", short, long, nil)
+
+	cmd.AddCommand(
+		newProfilesList(),
+		newProfilesSetOrg(),
+	)
+
+	return cmd
+}
+
+func newProfilesList() *cobra.Command {
+	const (
+		long  = "Lists the profiles along with the user logged in and their default organization."
+		short = "List profiles"
+	)
+
+	cmd := command.New("list", short, long, runProfilesList)
+
+	cmd.Aliases = []string{"ls"}
+
+	flag.Add(cmd, flag.JSONOutput())
+
+	return cmd
+}
+
+type profile struct {
+	Name       string
+	Current    bool
+	User       string
+	DefaultOrg string
+	ConfigDir  string
+}
+
+func runProfilesList(ctx context.Context) error {
+	io := iostreams.FromContext(ctx)
+	homeDir := state.UserHomeDirectory(ctx)
+
+	names := []string{flyctl.DefaultProfile}
+	switch entries, err := os.ReadDir(flyctl.ProfilesDir(homeDir)); {
+	case err == nil:
+		for _, e := range entries {
+			if e.IsDir() && flyctl.ValidateProfileName(e.Name()) == nil {
+				names = append(names, e.Name())
+			}
+		}
+	case !errors.Is(err, fs.ErrNotExist):
+		return fmt.Errorf("failed listing profiles: %w", err)
+	}
+	sort.Strings(names[1:])
+
+	profiles := make([]profile, 0, len(names))
+	for _, name := range names {
+		p := profile{
+			Name:      name,
+			Current:   name == flyctl.Profile(),
+			ConfigDir: flyctl.ProfileConfigDir(homeDir, name),
+		}
+
+		cfg := config.New()
+		if err := cfg.ApplyFile(filepath.Join(p.ConfigDir, config.FileName)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed reading config of profile %s: %w", name, err)
+		}
+		p.DefaultOrg = cfg.Organization
+
+		if cfg.AccessToken != "" {
+			if user, err := client.FromToken(cfg.AccessToken).API().GetCurrentUser(ctx); err == nil {
+				p.User = user.Email
+			} else {
+				p.User = "(token invalid)"
+			}
+		}
+
+		profiles = append(profiles, p)
+	}
+
+	if config.FromContext(ctx).JSONOutput {
+		return render.Output(ctx, io.Out, profiles)
+	}
+
+	rows := make([][]string, 0, len(profiles))
+	for _, p := range profiles {
+		name := p.Name
+		if p.Current {
+			name += " *"
+		}
+		user := p.User
+		if user == "" {
+			user = "(logged out)"
+		}
+		rows = append(rows, []string{name, user, p.DefaultOrg, p.ConfigDir})
+	}
+
+	return render.Table(io.Out, "", rows, "Name", "User", "Default Org", "Config Directory")
+}
+
+func newProfilesSetOrg() *cobra.Command {
+	const (
+		long = `Sets the organization commands of the profile use when none is
+selected with --org. Pass an empty slug to unset it.
+`
+		short = "Set the default organization of the profile"
+		usage = "set-org <slug>"
+	)
+
+	cmd := command.New(usage, short, long, runProfilesSetOrg)
+
+	cmd.Args = cobra.ExactArgs(1)
+
+	return cmd
+}
+
+func runProfilesSetOrg(ctx context.Context) error {
+	io := iostreams.FromContext(ctx)
+	slug := flag.FirstArg(ctx)
+
+	if slug != "" {
+		c := client.FromContext(ctx)
+		if !c.Authenticated() {
+			return client.ErrNoAuthToken
+		}
+		if _, err := c.API().GetOrganizationBySlug(ctx, slug); err != nil {
+			return fmt.Errorf("failed retrieving organization %s: %w", slug, err)
+		}
+	}
+
+	if err := config.SetDefaultOrg(state.ConfigFile(ctx), slug); err != nil {
+		return fmt.Errorf("failed saving default organization: %w", err)
+	}
+
+	if slug == "" {
+		fmt.Fprintf(io.Out, "Unset the default organization of profile %s\n", flyctl.Profile())
+	} else {
+		fmt.Fprintf(io.Out, "Set the default organization of profile %s to %s\n", flyctl.Profile(), slug)
+	}
+
+	return nil
+}
@@ -35,7 +35,7 @@ func runAuthToken(ctx context.Context) error {
 	token := cfg.AccessToken
 
 	if io := iostreams.FromContext(ctx); cfg.JSONOutput {
-		render.JSON(io.Out, map[string]string{"token": token})
+		render.Output(ctx, io.Out, map[string]string{"token": token})
 	} else {
 		fmt.Fprintln(io.Out, token)
 	}
@@ -41,7 +41,7 @@ func runWhoAmI(ctx context.Context) error {
 	cfg := config.FromContext(ctx)
 
 	if cfg.JSONOutput {
-		_ = render.JSON(io.Out, map[string]string{"email": user.Email})
+		_ = render.Output(ctx, io.Out, map[string]string{"email": user.Email})
 	} else {
 		fmt.Fprintln(io.Out, user.Email)
 	}
@@ -0,0 +1,48 @@
+// Package autoscale implements the autoscale command chain.
+package autoscale
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/internal/command"
+)
+
+// New initializes and returns a new autoscale Command.
+func New() *cobra.Command {
+	const (
+		short = "Autoscaling app resources"
+		long  = `Autoscaling application resources.
+
+For Machines apps, autoscaling rules are set in fly.toml and applied when
+deploying:
+
+  [[autoscaling]]
+    min_machines = 1
+    max_machines = 5
+    target_concurrency = 20
+    processes = ["app"]
+
+Deployments keep every region of the process groups between min_machines and
+max_machines, and the proxy starts and stops the machines in between to keep
+their concurrency around target_concurrency. Rules setting target_cpu_percent
+also have deployments launch or destroy machines in every region, within the
+bounds, so that the average CPU usage of its machines over the last 10 minutes
+gets close to the target. Use 'fly autoscale status' to see the current state
+of every region.
+
+For Nomad apps, the set, disable and show commands manage the platform's
+autoscaler.
+`
+	)
+
+	cmd := command.New("autoscale", short, long, nil)
+
+	cmd.AddCommand(
+		newShow(),
+		newStatus(),
+		newSet(),
+		newDisable(),
+	)
+
+	return cmd
+}
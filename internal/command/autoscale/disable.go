@@ -0,0 +1,62 @@
+package autoscale
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+)
+
+func newDisable() *cobra.Command {
+	const (
+		short = "Disable autoscaling"
+		long  = `NOMAD APPS ONLY: Disable autoscaling to manually control app resources.
+
+Machines apps disable autoscaling by removing the [[autoscaling]] sections of
+fly.toml and deploying.`
+	)
+
+	cmd := command.New("disable", short, long, runDisable,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	// extra arguments were always ignored, keep accepting them
+	cmd.Args = cobra.RangeArgs(0, 2)
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.JSONOutput(),
+	)
+
+	return cmd
+}
+
+func runDisable(ctx context.Context) error {
+	appName := appconfig.NameFromContext(ctx)
+
+	isV2, err := command.IsMachinesPlatform(ctx, appName)
+	if err != nil {
+		return err
+	}
+	if isV2 {
+		printMachinesBanner(ctx)
+		return nil
+	}
+
+	cfg, err := client.FromContext(ctx).API().UpdateAutoscaleConfig(ctx, api.UpdateAutoscaleConfigInput{
+		AppID:   appName,
+		Enabled: api.BoolPointer(false),
+	})
+	if err != nil {
+		return err
+	}
+
+	return printNomadConfig(ctx, cfg)
+}
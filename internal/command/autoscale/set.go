@@ -0,0 +1,127 @@
+package autoscale
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newSet() *cobra.Command {
+	const (
+		short = "Set app autoscaling parameters"
+		long  = `NOMAD APPS ONLY: Enable autoscaling and set the application's autoscaling parameters:
+
+min=int - minimum number of instances to be allocated globally.
+max=int - maximum number of instances to be allocated globally.
+
+Machines apps set their autoscaling rules in fly.toml, see 'fly autoscale --help'.`
+
+		usage = "set [min=int] [max=int]"
+	)
+
+	cmd := command.New(usage, short, long, runSet,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	cmd.Args = cobra.RangeArgs(0, 2)
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.JSONOutput(),
+	)
+
+	return cmd
+}
+
+func runSet(ctx context.Context) error {
+	var (
+		appName   = appconfig.NameFromContext(ctx)
+		apiClient = client.FromContext(ctx).API()
+	)
+
+	isV2, err := command.IsMachinesPlatform(ctx, appName)
+	if err != nil {
+		return err
+	}
+	if isV2 {
+		printMachinesBanner(ctx)
+		return nil
+	}
+
+	current, err := apiClient.AppAutoscalingConfig(ctx, appName)
+	if err != nil {
+		return err
+	}
+
+	input := api.UpdateAutoscaleConfigInput{
+		AppID:          appName,
+		BalanceRegions: api.BoolPointer(false),
+		MinCount:       &current.MinCount,
+		MaxCount:       &current.MaxCount,
+	}
+
+	params := map[string]string{}
+	for _, pair := range flag.Args(ctx) {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return fmt.Errorf("scale parameters must be provided as NAME=VALUE pairs (%s is invalid)", pair)
+		}
+		params[strings.ToLower(key)] = value
+	}
+
+	if value, ok := params["min"]; ok {
+		count, err := strconv.Atoi(value)
+		if err != nil {
+			return errors.New("could not parse min count value")
+		}
+		input.MinCount = &count
+		delete(params, "min")
+	}
+
+	if value, ok := params["max"]; ok {
+		count, err := strconv.Atoi(value)
+		if err != nil {
+			return errors.New("could not parse max count value")
+		}
+		input.MaxCount = &count
+		delete(params, "max")
+	}
+
+	if len(params) > 0 {
+		return fmt.Errorf("unrecognised parameters in command: %s", strings.Join(lo.Keys(params), ", "))
+	}
+
+	cfg, err := apiClient.UpdateAutoscaleConfig(ctx, input)
+	if err != nil {
+		return err
+	}
+
+	return printNomadConfig(ctx, cfg)
+}
+
+func printMachinesBanner(ctx context.Context) {
+	io := iostreams.FromContext(ctx)
+
+	fmt.Fprintln(io.Out, `Autoscaling rules of Machines apps are set in fly.toml and applied when deploying, e.g.:
+
+  [[autoscaling]]
+    min_machines = 1
+    max_machines = 5
+    target_concurrency = 20
+
+Use 'fly autoscale show' and 'fly autoscale status' to see the current rules and their effect.`)
+}
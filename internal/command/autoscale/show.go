@@ -0,0 +1,128 @@
+package autoscale
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newShow() *cobra.Command {
+	const (
+		short = "Show current autoscaling configuration"
+		long  = short + "\n"
+	)
+
+	cmd := command.New("show", short, long, runShow,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.JSONOutput(),
+	)
+
+	return cmd
+}
+
+func runShow(ctx context.Context) error {
+	appName := appconfig.NameFromContext(ctx)
+
+	isV2, err := command.IsMachinesPlatform(ctx, appName)
+	if err != nil {
+		return err
+	}
+	if isV2 {
+		return runMachinesShow(ctx)
+	}
+
+	cfg, err := client.FromContext(ctx).API().AppAutoscalingConfig(ctx, appName)
+	if err != nil {
+		return err
+	}
+
+	return printNomadConfig(ctx, cfg)
+}
+
+func runMachinesShow(ctx context.Context) error {
+	var (
+		io      = iostreams.FromContext(ctx)
+		appName = appconfig.NameFromContext(ctx)
+	)
+
+	appConfig, err := appconfig.FromRemoteApp(ctx, appName)
+	if err != nil {
+		return fmt.Errorf("failed to get the configuration of app %s: %w", appName, err)
+	}
+
+	if config.FromContext(ctx).JSONOutput {
+		return render.Output(ctx, io.Out, appConfig.Autoscaling)
+	}
+
+	if len(appConfig.Autoscaling) == 0 {
+		fmt.Fprintln(io.Out, "No autoscaling rules are set, add an [[autoscaling]] section to fly.toml and deploy to set some")
+		return nil
+	}
+
+	rows := make([][]string, 0, len(appConfig.Autoscaling))
+	for _, rule := range appConfig.Autoscaling {
+		groups := rule.Processes
+		if len(groups) == 0 {
+			groups = []string{appConfig.DefaultProcessName()}
+		}
+
+		target, cpuTarget := "-", "-"
+		if rule.TargetConcurrency > 0 {
+			target = strconv.Itoa(rule.TargetConcurrency)
+		}
+		if rule.TargetCPUPercent > 0 {
+			cpuTarget = fmt.Sprintf("%d%%", rule.TargetCPUPercent)
+		}
+
+		rows = append(rows, []string{
+			strings.Join(groups, ","),
+			strconv.Itoa(rule.MinMachines),
+			strconv.Itoa(rule.MaxMachines),
+			target,
+			cpuTarget,
+		})
+	}
+
+	return render.Table(io.Out, "", rows, "Process Groups", "Min Machines Per Region", "Max Machines Per Region", "Target Concurrency", "Target CPU")
+}
+
+func printNomadConfig(ctx context.Context, cfg *api.AutoscalingConfig) error {
+	io := iostreams.FromContext(ctx)
+
+	if config.FromContext(ctx).JSONOutput {
+		return render.Output(ctx, io.Out, cfg)
+	}
+
+	mode := "Disabled"
+	if cfg.Enabled {
+		mode = "Enabled"
+	}
+
+	fmt.Fprintf(io.Out, "%15s: %s\n", "Autoscaling", mode)
+	if cfg.Enabled {
+		fmt.Fprintf(io.Out, "%15s: %d\n", "Min Count", cfg.MinCount)
+		fmt.Fprintf(io.Out, "%15s: %d\n", "Max Count", cfg.MaxCount)
+	}
+
+	return nil
+}
@@ -0,0 +1,185 @@
+package autoscale
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/format"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newStatus() *cobra.Command {
+	const (
+		short = "Show the state of autoscaled process groups"
+		long  = `Show, for every region of the process groups with an autoscaling rule, how
+many machines run and are started, and what the scaler last did there.
+Machines apps only.`
+	)
+
+	cmd := command.New("status", short, long, runStatus,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.JSONOutput(),
+	)
+
+	return cmd
+}
+
+// regionStatus is the autoscaling state of a process group in a region.
+type regionStatus struct {
+	Group             string    `json:"group"`
+	Region            string    `json:"region"`
+	MinMachines       int       `json:"min_machines"`
+	MaxMachines       int       `json:"max_machines"`
+	TargetConcurrency int       `json:"target_concurrency,omitempty"`
+	TargetCPUPercent  int       `json:"target_cpu_percent,omitempty"`
+	Machines          int       `json:"machines"`
+	Started           int       `json:"started"`
+	Decision          string    `json:"decision"`
+	LastEvent         time.Time `json:"last_event,omitempty"`
+}
+
+func runStatus(ctx context.Context) error {
+	var (
+		io      = iostreams.FromContext(ctx)
+		appName = appconfig.NameFromContext(ctx)
+	)
+
+	isV2, err := command.IsMachinesPlatform(ctx, appName)
+	if err != nil {
+		return err
+	}
+	if !isV2 {
+		return errors.New("autoscale status is only supported for Machines apps, use 'fly autoscale show' for Nomad apps")
+	}
+
+	appConfig, err := appconfig.FromRemoteApp(ctx, appName)
+	if err != nil {
+		return fmt.Errorf("failed to get the configuration of app %s: %w", appName, err)
+	}
+
+	flapsClient, err := flaps.NewFromAppName(ctx, appName)
+	if err != nil {
+		return fmt.Errorf("could not create flaps client: %w", err)
+	}
+
+	machines, err := flapsClient.ListActive(ctx)
+	if err != nil {
+		return fmt.Errorf("could not list machines: %w", err)
+	}
+
+	var statuses []regionStatus
+	for _, group := range appConfig.ProcessNames() {
+		rule, err := appConfig.AutoscalingFor(group)
+		if err != nil {
+			return err
+		}
+		if rule == nil {
+			continue
+		}
+
+		inGroup := lo.Filter(machines, func(m *api.Machine, _ int) bool { return m.ProcessGroup() == group })
+		byRegion := lo.GroupBy(inGroup, func(m *api.Machine) string { return m.Region })
+
+		regions := lo.Keys(byRegion)
+		slices.Sort(regions)
+
+		for _, region := range regions {
+			statuses = append(statuses, newRegionStatus(group, region, rule, byRegion[region]))
+		}
+	}
+
+	if config.FromContext(ctx).JSONOutput {
+		return render.Output(ctx, io.Out, statuses)
+	}
+
+	if len(statuses) == 0 {
+		fmt.Fprintln(io.Out, "No machines run in process groups with autoscaling rules")
+		return nil
+	}
+
+	rows := lo.Map(statuses, func(s regionStatus, _ int) []string {
+		lastEvent := "-"
+		if !s.LastEvent.IsZero() {
+			lastEvent = format.RelativeTime(s.LastEvent)
+		}
+
+		return []string{
+			s.Group,
+			s.Region,
+			fmt.Sprintf("%d-%d", s.MinMachines, s.MaxMachines),
+			strconv.Itoa(s.Machines),
+			strconv.Itoa(s.Started),
+			s.Decision,
+			lastEvent,
+		}
+	})
+
+	return render.Table(io.Out, "", rows, "Process Group", "Region", "Bounds", "Machines", "Started", "Decision", "Last Event")
+}
+
+func newRegionStatus(group, region string, rule *appconfig.Autoscaling, machines []*api.Machine) regionStatus {
+	started := lo.CountBy(machines, func(m *api.Machine) bool { return m.State == api.MachineStateStarted })
+
+	var lastEvent time.Time
+	for _, m := range machines {
+		for _, e := range m.Events {
+			if ts := time.UnixMilli(e.Timestamp); ts.After(lastEvent) {
+				lastEvent = ts
+			}
+		}
+	}
+
+	return regionStatus{
+		Group:             group,
+		Region:            region,
+		MinMachines:       rule.MinMachines,
+		MaxMachines:       rule.MaxMachines,
+		TargetConcurrency: rule.TargetConcurrency,
+		TargetCPUPercent:  rule.TargetCPUPercent,
+		Machines:          len(machines),
+		Started:           started,
+		Decision:          scalerDecision(rule, len(machines), started),
+		LastEvent:         lastEvent,
+	}
+}
+
+// scalerDecision describes the state a region was scaled to, given the number
+// of machines of the group it runs and how many of them are started.
+func scalerDecision(rule *appconfig.Autoscaling, total, started int) string {
+	switch {
+	case total < rule.MinMachines:
+		return fmt.Sprintf("below minimum, next deploy launches %d", rule.MinMachines-total)
+	case total > rule.MaxMachines:
+		return fmt.Sprintf("above maximum, next deploy destroys %d", total-rule.MaxMachines)
+	case started == 0:
+		return "scaled to zero"
+	case started < total:
+		return fmt.Sprintf("scaled in to %d of %d", started, total)
+	case total == rule.MaxMachines:
+		return "scaled out to maximum"
+	default:
+		return "all machines started"
+	}
+}
@@ -0,0 +1,20 @@
+package autoscale
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/superfly/flyctl/internal/appconfig"
+)
+
+func TestScalerDecision(t *testing.T) {
+	rule := &appconfig.Autoscaling{MinMachines: 2, MaxMachines: 4}
+
+	assert.Equal(t, "below minimum, next deploy launches 1", scalerDecision(rule, 1, 1))
+	assert.Equal(t, "above maximum, next deploy destroys 2", scalerDecision(rule, 6, 6))
+	assert.Equal(t, "scaled to zero", scalerDecision(rule, 3, 0))
+	assert.Equal(t, "scaled in to 2 of 3", scalerDecision(rule, 3, 2))
+	assert.Equal(t, "scaled out to maximum", scalerDecision(rule, 4, 4))
+	assert.Equal(t, "all machines started", scalerDecision(rule, 3, 3))
+}
@@ -0,0 +1,24 @@
+// Package billing implements the billing command chain.
+package billing
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/internal/command"
+)
+
+// New initializes and returns a new billing Command.
+func New() *cobra.Command {
+	const (
+		short = "Report the usage and costs of organizations"
+		long  = short + "\n"
+	)
+
+	cmd := command.New("billing", short, long, nil)
+
+	cmd.AddCommand(
+		newUsage(),
+	)
+
+	return cmd
+}
@@ -0,0 +1,93 @@
+package billing
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/internal/prometheus"
+)
+
+func TestMachineHourlyPrice(t *testing.T) {
+	monthly := func(guest *api.MachineGuest) float64 {
+		return machineHourlyPrice(guest) * hoursPerMonth
+	}
+
+	assert.InDelta(t, 1.94, monthly(&api.MachineGuest{CPUKind: "shared", CPUs: 1, MemoryMB: 256}), 0.001)
+	assert.InDelta(t, 10.69, monthly(&api.MachineGuest{CPUKind: "shared", CPUs: 1, MemoryMB: 2048}), 0.001)
+	assert.InDelta(t, 31, monthly(&api.MachineGuest{CPUKind: "performance", CPUs: 1, MemoryMB: 2048}), 0.001)
+}
+
+func TestBandwidthPrice(t *testing.T) {
+	assert.Equal(t, 0.02, bandwidthPrice("fra"))
+	assert.Equal(t, 0.04, bandwidthPrice("syd"))
+	assert.Equal(t, 0.12, bandwidthPrice("jnb"))
+}
+
+func TestMonthAndForecast(t *testing.T) {
+	start, end := month(time.Date(2023, 2, 14, 12, 0, 0, 0, time.FixedZone("X", 3600)))
+	assert.Equal(t, time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC), end)
+
+	// a quarter of February
+	now := start.Add(7 * 24 * time.Hour)
+	assert.InDelta(t, 40, forecast(10, start, end, now), 0.001)
+	assert.Equal(t, 10.0, forecast(10, start, end, start))
+}
+
+func TestVolumeItems(t *testing.T) {
+	start, end := month(time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC))
+	now := start.Add(365 * time.Hour)
+
+	items := volumeItems("app", []api.Volume{
+		{ID: "vol_old", SizeGb: 10, CreatedAt: start.AddDate(0, -2, 0)},
+		{ID: "vol_new", SizeGb: 10, CreatedAt: now.Add(-73 * time.Hour)},
+	}, start, end, now)
+
+	require.Len(t, items, 2)
+	assert.InDelta(t, 0.75, items[0].Cost, 0.001)
+	assert.InDelta(t, 0.15, items[1].Cost, 0.001)
+	// both exist until the end of the month, 720 hours
+	assert.InDelta(t, 1.5*720/hoursPerMonth, items[0].Forecast, 0.001)
+}
+
+func TestBandwidthItems(t *testing.T) {
+	start, end := month(time.Date(2023, 4, 15, 0, 0, 0, 0, time.UTC))
+
+	items := bandwidthItems([]prometheus.Series{
+		{Metric: map[string]string{"app": "a", "region": "syd"}, Points: []prometheus.Point{{Value: 2e9}}},
+		{Metric: map[string]string{"app": "a", "region": "fra"}, Points: []prometheus.Point{{Value: 0}}},
+	}, start, end, start.Add(24*time.Hour))
+
+	require.Len(t, items, 1)
+	assert.Equal(t, "syd", items[0].Region)
+	assert.InDelta(t, 0.08, items[0].Cost, 0.0001)
+}
+
+func TestSummarize(t *testing.T) {
+	apps := summarize([]lineItem{
+		{App: "a", Kind: "machine", Quantity: 10, Cost: 1},
+		{App: "b", Kind: "machine", Quantity: 20, Cost: 2},
+		{App: "b", Kind: "volume", Cost: 0.5},
+		{App: "b", Kind: "bandwidth", Quantity: 3, Cost: 0.06},
+	})
+
+	require.Len(t, apps, 2)
+	assert.Equal(t, "b", apps[0].app)
+	assert.InDelta(t, 2.56, apps[0].cost, 0.0001)
+	assert.Equal(t, 3.0, apps[0].bandwidthGB)
+	assert.Equal(t, "a", apps[1].app)
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeCSV(&buf, []lineItem{
+		{App: "a", Kind: "volume", ID: "vol_1", Region: "fra", Description: "data 1GB", Quantity: 1, Unit: "hours", Cost: 0.5, Forecast: 1},
+	}))
+
+	assert.Equal(t, "app,kind,id,region,description,quantity,unit,cost,forecast\n"+
+		"a,volume,vol_1,fra,data 1GB,1.0000,hours,0.5000,1.0000\n", buf.String())
+}
@@ -0,0 +1,72 @@
+package billing
+
+import (
+	"time"
+
+	"github.com/superfly/flyctl/api"
+)
+
+// The list prices of resources, in US dollars. Machines are billed by the
+// second they run and volumes by the second they exist, at these monthly
+// rates of 730 hours.
+const (
+	hoursPerMonth = 730
+
+	sharedCPUMonthly      = 0.69
+	performanceCPUMonthly = 21.0
+	memoryGBMonthly       = 5.0
+	volumeGBMonthly       = 0.15
+)
+
+// bandwidthPrices are the prices of a GB of outbound data transfer by edge
+// region; regions not listed cost defaultBandwidthPrice.
+var (
+	defaultBandwidthPrice = 0.02
+
+	bandwidthPrices = map[string]float64{
+		// Asia Pacific, Oceania and South America
+		"hkg": 0.04, "nrt": 0.04, "sin": 0.04, "syd": 0.04,
+		"gru": 0.04, "gig": 0.04, "scl": 0.04, "eze": 0.04, "bog": 0.04,
+		// India and Africa
+		"bom": 0.12, "maa": 0.12, "jnb": 0.12,
+	}
+)
+
+// machineHourlyPrice returns the price of running a machine of the guest for
+// an hour.
+func machineHourlyPrice(guest *api.MachineGuest) float64 {
+	cpu := sharedCPUMonthly
+	if guest.CPUKind == "performance" {
+		cpu = performanceCPUMonthly
+	}
+
+	monthly := float64(guest.CPUs)*cpu + float64(guest.MemoryMB)/1024*memoryGBMonthly
+
+	return monthly / hoursPerMonth
+}
+
+// bandwidthPrice returns the price of a GB sent from the edge region.
+func bandwidthPrice(region string) float64 {
+	if price, ok := bandwidthPrices[region]; ok {
+		return price
+	}
+
+	return defaultBandwidthPrice
+}
+
+// month returns the bounds of the month of t, in UTC as billing periods are.
+func month(t time.Time) (start, end time.Time) {
+	t = t.UTC()
+	start = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, 1, 0)
+}
+
+// forecast extrapolates the cost so far of the period to its end.
+func forecast(cost float64, start, end, now time.Time) float64 {
+	elapsed := now.Sub(start)
+	if elapsed <= 0 {
+		return cost
+	}
+
+	return cost * float64(end.Sub(start)) / float64(elapsed)
+}
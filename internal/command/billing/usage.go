@@ -0,0 +1,395 @@
+package billing
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/prometheus"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newUsage() *cobra.Command {
+	const (
+		short = "Break down the usage and estimated costs of the current month"
+		long  = `Break down the usage of the organization since the start of the month, by app,
+machine, volume and outbound bandwidth, along with its cost estimated from list
+prices. Machine run time and bandwidth come from the metrics of the apps.
+
+Estimates leave out free allowances, discounts, plans, IP addresses,
+certificates and machines destroyed during the month; invoices are on the
+dashboard.`
+	)
+
+	cmd := command.New("usage", short, long, runUsage,
+		command.RequireSession,
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd,
+		flag.Org(),
+		flag.JSONOutput(),
+		flag.Bool{
+			Name:        "csv",
+			Description: "Output the usage as CSV, one line per resource",
+		},
+		flag.Bool{
+			Name:        "detail",
+			Description: "List every machine, volume and bandwidth region, not only the totals of apps",
+		},
+		flag.Bool{
+			Name:        "forecast",
+			Description: "Forecast the costs of the whole month from the usage so far",
+		},
+	)
+
+	return cmd
+}
+
+// lineItem is the usage of a resource during the month.
+type lineItem struct {
+	App         string
+	Kind        string
+	ID          string
+	Region      string
+	Description string
+	Quantity    float64
+	Unit        string
+	Cost        float64
+	Forecast    float64
+}
+
+type usageReport struct {
+	Organization string
+	Start        time.Time
+	End          time.Time
+	Items        []lineItem
+	Cost         float64
+	Forecast     float64
+}
+
+func runUsage(ctx context.Context) error {
+	var (
+		io     = iostreams.FromContext(ctx)
+		client = client.FromContext(ctx).API()
+		now    = time.Now().UTC()
+	)
+
+	if config.FromContext(ctx).JSONOutput && flag.GetBool(ctx, "csv") {
+		return fmt.Errorf("--json and --csv are not supported together")
+	}
+
+	org, err := prompt.Org(ctx)
+	if err != nil {
+		return err
+	}
+
+	apps, err := client.GetAppsForOrganization(ctx, org.ID)
+	if err != nil {
+		return fmt.Errorf("failed listing apps: %w", err)
+	}
+
+	start, end := month(now)
+	report := &usageReport{Organization: org.Slug, Start: start, End: end}
+
+	// the lookback of the queries covers the month so far
+	window := fmt.Sprintf("%ds", int(now.Sub(start).Seconds()))
+	metrics := prometheus.New(ctx, org.Slug)
+
+	uptime, err := metrics.Query(ctx, fmt.Sprintf(`count_over_time((max by (app, instance) (fly_instance_up))[%s:1m])`, window), now)
+	if err != nil {
+		return fmt.Errorf("failed querying machine run time: %w", err)
+	}
+	minutes := map[string]float64{}
+	for _, s := range uptime {
+		minutes[s.Metric["instance"]] = s.Last()
+	}
+
+	bandwidth, err := metrics.Query(ctx, fmt.Sprintf(`sum by (app, region) (increase(fly_edge_data_out[%s]))`, window), now)
+	if err != nil {
+		return fmt.Errorf("failed querying bandwidth: %w", err)
+	}
+
+	for _, app := range apps {
+		if app.PlatformVersion == "machines" {
+			items, err := machineItems(ctx, app.Name, minutes, start, end, now)
+			if err != nil {
+				return err
+			}
+			report.Items = append(report.Items, items...)
+		}
+
+		volumes, err := client.GetVolumes(ctx, app.Name)
+		if err != nil {
+			return fmt.Errorf("failed listing volumes of %s: %w", app.Name, err)
+		}
+		report.Items = append(report.Items, volumeItems(app.Name, volumes, start, end, now)...)
+	}
+
+	report.Items = append(report.Items, bandwidthItems(bandwidth, start, end, now)...)
+
+	for _, item := range report.Items {
+		report.Cost += item.Cost
+		report.Forecast += item.Forecast
+	}
+
+	switch {
+	case config.FromContext(ctx).JSONOutput:
+		return render.Output(ctx, io.Out, report)
+	case flag.GetBool(ctx, "csv"):
+		return writeCSV(io.Out, report.Items)
+	}
+
+	return renderUsage(ctx, report)
+}
+
+func machineItems(ctx context.Context, appName string, minutes map[string]float64, start, end, now time.Time) ([]lineItem, error) {
+	flapsClient, err := flaps.NewFromAppName(ctx, appName)
+	if err != nil {
+		return nil, err
+	}
+
+	machines, err := flapsClient.List(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed listing machines of %s: %w", appName, err)
+	}
+
+	items := make([]lineItem, 0, len(machines))
+	for _, m := range machines {
+		if m.Config == nil || m.Config.Guest == nil {
+			continue
+		}
+
+		guest := m.Config.Guest
+		hours := minutes[m.ID] / 60
+		cost := hours * machineHourlyPrice(guest)
+
+		items = append(items, lineItem{
+			App:         appName,
+			Kind:        "machine",
+			ID:          m.ID,
+			Region:      m.Region,
+			Description: fmt.Sprintf("%s/%dMB", guest.ToSize(), guest.MemoryMB),
+			Quantity:    hours,
+			Unit:        "hours",
+			Cost:        cost,
+			Forecast:    forecast(cost, start, end, now),
+		})
+	}
+
+	return items, nil
+}
+
+// volumeItems returns the usage of volumes, which are billed for as long as
+// they exist, so they're forecast to exist until the end of the month.
+func volumeItems(appName string, volumes []api.Volume, start, end, now time.Time) []lineItem {
+	items := make([]lineItem, 0, len(volumes))
+	for _, v := range volumes {
+		from := v.CreatedAt
+		if from.Before(start) {
+			from = start
+		}
+
+		monthly := float64(v.SizeGb) * volumeGBMonthly
+		hours := now.Sub(from).Hours()
+		cost := monthly * hours / hoursPerMonth
+
+		items = append(items, lineItem{
+			App:         appName,
+			Kind:        "volume",
+			ID:          v.ID,
+			Region:      v.Region,
+			Description: fmt.Sprintf("%s %dGB", v.Name, v.SizeGb),
+			Quantity:    hours,
+			Unit:        "hours",
+			Cost:        cost,
+			Forecast:    cost + monthly*end.Sub(now).Hours()/hoursPerMonth,
+		})
+	}
+
+	return items
+}
+
+func bandwidthItems(series []prometheus.Series, start, end, now time.Time) []lineItem {
+	items := make([]lineItem, 0, len(series))
+	for _, s := range series {
+		gb := s.Last() / 1e9
+		if gb <= 0 {
+			continue
+		}
+
+		region := s.Metric["region"]
+		cost := gb * bandwidthPrice(region)
+
+		items = append(items, lineItem{
+			App:         s.Metric["app"],
+			Kind:        "bandwidth",
+			Region:      region,
+			Description: "outbound data transfer",
+			Quantity:    gb,
+			Unit:        "GB",
+			Cost:        cost,
+			Forecast:    forecast(cost, start, end, now),
+		})
+	}
+
+	return items
+}
+
+// appUsage is the usage of an app, by kind of resource.
+type appUsage struct {
+	app                          string
+	machineHours, bandwidthGB    float64
+	machines, volumes, bandwidth float64
+	cost, forecast               float64
+}
+
+// summarize returns the usage of every app, most expensive first.
+func summarize(items []lineItem) []*appUsage {
+	byApp := map[string]*appUsage{}
+	for _, item := range items {
+		u := byApp[item.App]
+		if u == nil {
+			u = &appUsage{app: item.App}
+			byApp[item.App] = u
+		}
+
+		switch item.Kind {
+		case "machine":
+			u.machineHours += item.Quantity
+			u.machines += item.Cost
+		case "volume":
+			u.volumes += item.Cost
+		case "bandwidth":
+			u.bandwidthGB += item.Quantity
+			u.bandwidth += item.Cost
+		}
+		u.cost += item.Cost
+		u.forecast += item.Forecast
+	}
+
+	apps := make([]*appUsage, 0, len(byApp))
+	for _, u := range byApp {
+		apps = append(apps, u)
+	}
+	sort.Slice(apps, func(i, j int) bool {
+		if apps[i].cost != apps[j].cost {
+			return apps[i].cost > apps[j].cost
+		}
+		return apps[i].app < apps[j].app
+	})
+
+	return apps
+}
+
+func dollars(v float64) string {
+	return fmt.Sprintf("$%.2f", v)
+}
+
+func renderUsage(ctx context.Context, report *usageReport) error {
+	var (
+		out          = iostreams.FromContext(ctx).Out
+		showForecast = flag.GetBool(ctx, "forecast")
+	)
+
+	headers := []string{"App", "Machine Hours", "Machines", "Volumes", "Bandwidth", "Bandwidth Cost", "Cost"}
+	if showForecast {
+		headers = append(headers, "Forecast")
+	}
+
+	var rows [][]string
+	for _, u := range summarize(report.Items) {
+		row := []string{
+			u.app,
+			fmt.Sprintf("%.1f", u.machineHours),
+			dollars(u.machines),
+			dollars(u.volumes),
+			fmt.Sprintf("%.2f GB", u.bandwidthGB),
+			dollars(u.bandwidth),
+			dollars(u.cost),
+		}
+		if showForecast {
+			row = append(row, dollars(u.forecast))
+		}
+		rows = append(rows, row)
+	}
+
+	title := fmt.Sprintf("Usage of %s from %s to %s", report.Organization,
+		report.Start.Format("2006-01-02"), time.Now().UTC().Format("2006-01-02 15:04 MST"))
+	if err := render.Table(out, title, rows, headers...); err != nil {
+		return err
+	}
+
+	if flag.GetBool(ctx, "detail") {
+		headers := []string{"App", "Kind", "ID", "Region", "Description", "Usage", "Cost"}
+		if showForecast {
+			headers = append(headers, "Forecast")
+		}
+
+		rows := make([][]string, 0, len(report.Items))
+		for _, item := range report.Items {
+			row := []string{
+				item.App,
+				item.Kind,
+				item.ID,
+				item.Region,
+				item.Description,
+				fmt.Sprintf("%.2f %s", item.Quantity, item.Unit),
+				dollars(item.Cost),
+			}
+			if showForecast {
+				row = append(row, dollars(item.Forecast))
+			}
+			rows = append(rows, row)
+		}
+
+		if err := render.Table(out, "Resources", rows, headers...); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(out, "Estimated cost so far: %s\n", dollars(report.Cost))
+	if showForecast {
+		fmt.Fprintf(out, "Forecast for %s: %s\n", report.Start.Format("January 2006"), dollars(report.Forecast))
+	}
+	fmt.Fprintln(out, "Estimates use list prices, without free allowances, discounts, plans, IP addresses, certificates or destroyed machines.")
+
+	return nil
+}
+
+func writeCSV(w io.Writer, items []lineItem) error {
+	cw := csv.NewWriter(w)
+
+	_ = cw.Write([]string{"app", "kind", "id", "region", "description", "quantity", "unit", "cost", "forecast"})
+	for _, item := range items {
+		_ = cw.Write([]string{
+			item.App,
+			item.Kind,
+			item.ID,
+			item.Region,
+			item.Description,
+			strconv.FormatFloat(item.Quantity, 'f', 4, 64),
+			item.Unit,
+			strconv.FormatFloat(item.Cost, 'f', 4, 64),
+			strconv.FormatFloat(item.Forecast, 'f', 4, 64),
+		})
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
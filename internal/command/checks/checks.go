@@ -1,6 +1,8 @@
 package checks
 
 import (
+	"time"
+
 	"github.com/spf13/cobra"
 	"github.com/superfly/flyctl/internal/command"
 	"github.com/superfly/flyctl/internal/flag"
@@ -16,7 +18,22 @@ func New() *cobra.Command {
 	listCmd := command.New("list", "List health checks", "", runAppCheckList, command.RequireSession, command.RequireAppName)
 	flag.Add(listCmd, commonFlags,
 		flag.String{Name: "check-name", Description: "Filter checks by name"},
+		flag.Bool{Name: "watch", Description: "Keep watching the checks, printing their transitions"},
+		flag.Duration{Name: "interval", Description: "How often to poll the checks with --watch", Default: 5 * time.Second},
 	)
 	cmd.AddCommand(listCmd)
+
+	// fly checks history
+	historyCmd := command.New("history", "Show the recent transitions of health checks",
+		`Show the recent events of the machines of the app, such as exits and
+restarts, along with the last transition of each of their checks, to diagnose
+flapping checks. Use 'fly checks list --watch' to follow every transition.`,
+		runAppCheckHistory, command.RequireSession, command.RequireAppName)
+	flag.Add(historyCmd, commonFlags,
+		flag.String{Name: "check-name", Description: "Filter checks by name"},
+		flag.String{Name: "machine", Description: "Only show the history of this machine"},
+		flag.JSONOutput(),
+	)
+	cmd.AddCommand(historyCmd)
 	return cmd
 }
@@ -0,0 +1,148 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/format"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+// historyEntry is a transition of a machine or of one of its checks.
+type historyEntry struct {
+	Time    time.Time
+	Machine string
+	Region  string
+	Kind    string
+	Status  string
+	Detail  string
+}
+
+func runAppCheckHistory(ctx context.Context) error {
+	var (
+		out           = iostreams.FromContext(ctx).Out
+		appName       = appconfig.NameFromContext(ctx)
+		nameFilter    = flag.GetString(ctx, "check-name")
+		machineFilter = flag.GetString(ctx, "machine")
+	)
+
+	app, err := client.FromContext(ctx).API().GetAppCompact(ctx, appName)
+	if err != nil {
+		return fmt.Errorf("failed to get app: %w", err)
+	}
+	if app.PlatformVersion != "machines" {
+		return fmt.Errorf("check history is only supported for apps on the machines platform")
+	}
+
+	flapsClient, err := flaps.New(ctx, app)
+	if err != nil {
+		return err
+	}
+
+	machines, err := flapsClient.ListActive(ctx)
+	if err != nil {
+		return err
+	}
+
+	var entries []historyEntry
+	for _, m := range machines {
+		if machineFilter == "" || machineFilter == m.ID {
+			entries = append(entries, machineHistory(m, nameFilter)...)
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Machine != entries[j].Machine {
+			return entries[i].Machine < entries[j].Machine
+		}
+		return entries[i].Time.After(entries[j].Time)
+	})
+
+	if config.FromContext(ctx).JSONOutput {
+		return render.Output(ctx, out, entries)
+	}
+
+	rows := make([][]string, 0, len(entries))
+	for _, e := range entries {
+		rows = append(rows, []string{
+			e.Machine,
+			e.Region,
+			e.Time.Format(time.RFC3339),
+			format.RelativeTime(e.Time),
+			e.Kind,
+			e.Status,
+			e.Detail,
+		})
+	}
+
+	return render.Table(out, fmt.Sprintf("Check history for %s", app.Name), rows, "Machine", "Region", "Time", "When", "Event", "Status", "Detail")
+}
+
+// machineHistory returns the recent events of the machine interleaved with the
+// last transition of each of its checks, which is all the platform keeps of
+// their history.
+func machineHistory(m *api.Machine, nameFilter string) []historyEntry {
+	var entries []historyEntry
+
+	for _, c := range m.Checks {
+		if c.UpdatedAt == nil || (nameFilter != "" && nameFilter != c.Name) {
+			continue
+		}
+		entries = append(entries, historyEntry{
+			Time:    *c.UpdatedAt,
+			Machine: m.ID,
+			Region:  m.Region,
+			Kind:    "check " + c.Name,
+			Status:  c.Status,
+			Detail:  firstLine(c.Output),
+		})
+	}
+
+	for _, e := range m.Events {
+		entries = append(entries, historyEntry{
+			Time:    time.UnixMilli(e.Timestamp),
+			Machine: m.ID,
+			Region:  m.Region,
+			Kind:    e.Type,
+			Status:  e.Status,
+			Detail:  eventDetail(e),
+		})
+	}
+
+	return entries
+}
+
+// eventDetail describes the exit of machines, which usually explains why their
+// checks flap.
+func eventDetail(e *api.MachineEvent) string {
+	details := []string{"by " + e.Source}
+
+	if e.Request != nil {
+		if code, err := e.Request.GetExitCode(); err == nil {
+			details = append(details, fmt.Sprintf("exit code %d", code))
+		}
+		if exit := e.Request.ExitEvent; exit != nil {
+			if exit.OOMKilled {
+				details = append(details, "out of memory")
+			}
+			if exit.Restarting {
+				details = append(details, "restarting")
+			}
+		}
+		if e.Request.RestartCount > 0 {
+			details = append(details, fmt.Sprintf("restart #%d", e.Request.RestartCount))
+		}
+	}
+
+	return strings.Join(details, ", ")
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/superfly/flyctl/api"
 	"github.com/superfly/flyctl/client"
@@ -30,6 +31,9 @@ func runAppCheckList(ctx context.Context) error {
 	if app.PlatformVersion == "machines" {
 		return runMachinesAppCheckList(ctx, app)
 	}
+	if flag.GetBool(ctx, "watch") {
+		return fmt.Errorf("--watch is only supported for apps on the machines platform")
+	}
 	return runNomadAppCheckList(ctx)
 }
 
@@ -67,7 +71,16 @@ func runMachinesAppCheckList(ctx context.Context, app *api.AppCompact) error {
 	}
 	table.Render()
 
-	return nil
+	if !flag.GetBool(ctx, "watch") {
+		return nil
+	}
+
+	interval := flag.GetDuration(ctx, "interval")
+	if interval < time.Second {
+		return fmt.Errorf("--interval must be at least 1s")
+	}
+
+	return watchChecks(ctx, flapsClient, checkStates(machines, nameFilter), nameFilter, interval)
 }
 
 func runNomadAppCheckList(ctx context.Context) error {
@@ -86,7 +99,7 @@ func runNomadAppCheckList(ctx context.Context) error {
 	}
 
 	if config.FromContext(ctx).JSONOutput {
-		return render.JSON(out, checks)
+		return render.Output(ctx, out, checks)
 	}
 
 	fmt.Fprintf(out, "Health Checks for %s\n", appName)
@@ -0,0 +1,141 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/azazeal/pause"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+type checkKey struct {
+	machine string
+	check   string
+}
+
+type checkState struct {
+	region string
+	status string
+	output string
+}
+
+// checkStates returns the state of the checks of machines, those named
+// nameFilter only when it's set.
+func checkStates(machines []*api.Machine, nameFilter string) map[checkKey]checkState {
+	states := map[checkKey]checkState{}
+	for _, m := range machines {
+		for _, c := range m.Checks {
+			if nameFilter != "" && nameFilter != c.Name {
+				continue
+			}
+			states[checkKey{machine: m.ID, check: c.Name}] = checkState{
+				region: m.Region,
+				status: c.Status,
+				output: c.Output,
+			}
+		}
+	}
+
+	return states
+}
+
+// watchChecks polls the checks of the machines of the app, printing their
+// transitions from the states given until ctx is done.
+func watchChecks(ctx context.Context, flapsClient *flaps.Client, states map[checkKey]checkState, nameFilter string, interval time.Duration) error {
+	var (
+		io       = iostreams.FromContext(ctx)
+		colorize = io.ColorScheme()
+	)
+
+	colorStatus := func(status string) string {
+		switch status {
+		case "passing":
+			return colorize.Green(status)
+		case "warning":
+			return colorize.Yellow(status)
+		case "critical":
+			return colorize.Red(status)
+		default:
+			return status
+		}
+	}
+
+	fmt.Fprintf(io.ErrOut, "\nWatching for check transitions every %s, press Ctrl-C to stop\n", interval)
+
+	for {
+		pause.For(ctx, interval)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		machines, err := flapsClient.ListActive(ctx)
+		if ctx.Err() != nil {
+			return nil
+		} else if err != nil {
+			fmt.Fprintf(io.ErrOut, "failed listing machines: %v\n", err)
+			continue
+		}
+
+		current := checkStates(machines, nameFilter)
+		now := time.Now().Format("15:04:05")
+
+		keys := make([]checkKey, 0, len(current)+len(states))
+		for k := range current {
+			keys = append(keys, k)
+		}
+		for k := range states {
+			if _, ok := current[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if keys[i].machine != keys[j].machine {
+				return keys[i].machine < keys[j].machine
+			}
+			return keys[i].check < keys[j].check
+		})
+
+		for _, k := range keys {
+			before, existed := states[k]
+			after, exists := current[k]
+
+			switch {
+			case !exists:
+				fmt.Fprintf(io.Out, "%s %s (%s) %s: gone\n", now, k.machine, before.region, k.check)
+			case !existed:
+				fmt.Fprintf(io.Out, "%s %s (%s) %s: %s%s\n", now, k.machine, after.region, k.check, colorStatus(after.status), formatTransitionOutput(after.output))
+			case before.status != after.status:
+				fmt.Fprintf(io.Out, "%s %s (%s) %s: %s -> %s%s\n", now, k.machine, after.region, k.check,
+					colorStatus(before.status), colorStatus(after.status), formatTransitionOutput(after.output))
+			}
+		}
+
+		states = current
+	}
+}
+
+// formatTransitionOutput returns the first line of the output of a check, to
+// print along with its transitions.
+func formatTransitionOutput(output string) string {
+	if line := firstLine(output); line != "" {
+		return " (" + line + ")"
+	}
+
+	return ""
+}
+
+// firstLine returns the first line of the output of a check, shortened.
+func firstLine(output string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(output), "\n")
+	if len(line) > 120 {
+		line = line[:117] + "..."
+	}
+
+	return line
+}
@@ -0,0 +1,24 @@
+// Package ci implements the ci command chain.
+package ci
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/internal/command"
+)
+
+// New initializes and returns a new ci Command.
+func New() *cobra.Command {
+	const (
+		short = "Set up continuous deployment of apps"
+		long  = short + "\n"
+	)
+
+	cmd := command.New("ci", short, long, nil)
+
+	cmd.AddCommand(
+		newInit(),
+	)
+
+	return cmd
+}
@@ -0,0 +1,159 @@
+package ci
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/command/tokens"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newInit() *cobra.Command {
+	const (
+		long = `Writes a CI workflow deploying the app whenever its branch is pushed,
+tailored to the app: apps built from source are built once and the same image
+is deployed, apps with several process groups are deployed one group at a time,
+and apps serving HTTP are smoke tested once deployed. Deploys fail when updated
+machines crash loop or their checks flap.
+
+The workflow authenticates with the FLY_API_TOKEN secret, which --create-token
+creates as a deploy token limited to the app.
+`
+		short = "Write a CI workflow deploying the app"
+	)
+
+	cmd := command.New("init", short, long, runInit,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.String{
+			Name:        "provider",
+			Description: "The CI service to write the workflow for, github or gitlab",
+			Default:     "github",
+		},
+		flag.String{
+			Name:        "branch",
+			Description: "The branch deployed when pushed",
+			Default:     "main",
+		},
+		flag.String{
+			Name:        "output",
+			Shorthand:   "o",
+			Description: "Write the workflow to this path instead of where the CI service expects it",
+		},
+		flag.Bool{
+			Name:        "force",
+			Description: "Overwrite an existing workflow",
+		},
+		flag.Bool{
+			Name:        "create-token",
+			Description: "Create a deploy token for the FLY_API_TOKEN secret",
+		},
+	)
+
+	return cmd
+}
+
+func runInit(ctx context.Context) error {
+	var (
+		io        = iostreams.FromContext(ctx)
+		apiClient = client.FromContext(ctx).API()
+		appName   = appconfig.NameFromContext(ctx)
+		provider  = flag.GetString(ctx, "provider")
+	)
+
+	if _, ok := providers[provider]; !ok {
+		return fmt.Errorf("unsupported provider %q, use one of %s", provider, strings.Join(providerNames(), ", "))
+	}
+
+	cfg := appconfig.ConfigFromContext(ctx)
+	if cfg == nil {
+		return errors.New("the workflow deploys the fly.toml of the repository, run this command next to it or save it with 'fly config save' first")
+	}
+
+	app, err := apiClient.GetAppCompact(ctx, appName)
+	if err != nil {
+		return fmt.Errorf("failed fetching app: %w", err)
+	}
+	if app.PlatformVersion != appconfig.MachinesPlatform {
+		return fmt.Errorf("workflows are only generated for apps on the machines platform, %s is on %s", app.Name, app.PlatformVersion)
+	}
+
+	secrets, err := apiClient.GetAppSecrets(ctx, appName)
+	if err != nil {
+		return fmt.Errorf("failed listing secrets: %w", err)
+	}
+	postgres := lo.ContainsBy(secrets, func(s api.Secret) bool { return s.Name == "DATABASE_URL" })
+
+	p := newPipeline(cfg, app.Name, flag.GetString(ctx, "branch"), postgres)
+
+	var buf bytes.Buffer
+	if err := p.write(&buf, provider); err != nil {
+		return fmt.Errorf("failed rendering workflow: %w", err)
+	}
+
+	path := flag.GetString(ctx, "output")
+	if path == "" {
+		path = filepath.Join(filepath.Dir(cfg.ConfigFilePath()), providers[provider].path)
+	}
+
+	if _, err := os.Stat(path); err == nil && !flag.GetBool(ctx, "force") {
+		return fmt.Errorf("%s already exists, pass --force to overwrite it", path)
+	} else if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed creating directory of workflow: %w", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed writing workflow: %w", err)
+	}
+
+	fmt.Fprintf(io.Out, "Wrote %s workflow deploying %s from %s to %s\n", provider, app.Name, p.Branch, path)
+	if note := p.DatabaseNote(); note != "" && p.ReleaseCommand == "" {
+		fmt.Fprintf(io.ErrOut, "Warning: %s\n", note)
+	}
+
+	if !flag.GetBool(ctx, "create-token") {
+		fmt.Fprintf(io.Out, "Set the FLY_API_TOKEN secret of the repository to a token from 'fly tokens create deploy -a %s'\n", app.Name)
+		return nil
+	}
+
+	token, err := tokens.CreateDeployToken(ctx, app.Name, "ci deploy token", "")
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(io.Out, "Set the FLY_API_TOKEN secret of the repository to this deploy token:")
+	fmt.Fprintln(io.Out, token)
+
+	return nil
+}
+
+func providerNames() []string {
+	names := lo.Keys(providers)
+	sort.Strings(names)
+	return names
+}
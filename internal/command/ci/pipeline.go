@@ -0,0 +1,276 @@
+package ci
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/samber/lo"
+
+	"github.com/superfly/flyctl/internal/appconfig"
+)
+
+// watchTime is how long deploys watch the updated machines, failing the
+// pipeline if they crash loop or their checks flap.
+const watchTime = "60s"
+
+// pipeline is what CI pipelines need to know about the app to deploy it.
+type pipeline struct {
+	App    string
+	Branch string
+	// Image is the image deployed as is when fly.toml has one, which leaves
+	// nothing to build.
+	Image string
+	// Groups are the process groups, deployed one stage at a time when there
+	// are several, those serving HTTP last.
+	Groups         []string
+	ReleaseCommand string
+	Postgres       bool
+	// SmokeURL is requested once the app is deployed when it serves HTTP.
+	SmokeURL string
+}
+
+// stage is a step deploying the app, or one of its process groups.
+type stage struct {
+	Name    string
+	Command string
+}
+
+func newPipeline(cfg *appconfig.Config, appName, branch string, postgres bool) *pipeline {
+	p := &pipeline{
+		App:      appName,
+		Branch:   branch,
+		Postgres: postgres,
+	}
+
+	if cfg.Build != nil {
+		p.Image = cfg.Build.Image
+	}
+	if cfg.Deploy != nil {
+		p.ReleaseCommand = cfg.Deploy.ReleaseCommand
+	}
+
+	httpGroups := map[string]bool{}
+	for _, s := range cfg.AllServices() {
+		if !servesHTTP(s) {
+			continue
+		}
+		groups := s.Processes
+		if len(groups) == 0 {
+			groups = []string{cfg.DefaultProcessName()}
+		}
+		for _, g := range groups {
+			httpGroups[g] = true
+		}
+	}
+
+	p.Groups = cfg.ProcessNames()
+	sort.SliceStable(p.Groups, func(i, j int) bool {
+		return !httpGroups[p.Groups[i]] && httpGroups[p.Groups[j]]
+	})
+
+	if u, err := cfg.URL(); err == nil && u != nil {
+		u.Scheme = "https"
+		u.Host = appName + ".fly.dev"
+		u.Path = smokePath(cfg)
+		p.SmokeURL = u.String()
+	}
+
+	return p
+}
+
+func servesHTTP(s appconfig.Service) bool {
+	for _, port := range s.Ports {
+		if lo.Contains(port.Handlers, "http") {
+			return true
+		}
+	}
+	return false
+}
+
+// smokePath returns the path of the first HTTP check of the app, which is
+// expected to succeed once it's deployed.
+func smokePath(cfg *appconfig.Config) string {
+	for _, s := range cfg.AllServices() {
+		for _, check := range s.HTTPChecks {
+			if check.HTTPPath != nil && *check.HTTPPath != "" {
+				return *check.HTTPPath
+			}
+		}
+	}
+
+	names := lo.Keys(cfg.Checks)
+	sort.Strings(names)
+	for _, name := range names {
+		check := cfg.Checks[name]
+		if check.Type != nil && *check.Type == "http" && check.HTTPPath != nil && *check.HTTPPath != "" {
+			return *check.HTTPPath
+		}
+	}
+
+	return "/"
+}
+
+// Stages returns the deploy steps, deploying the image labeled by the
+// pipeline when the app is built.
+func (p *pipeline) Stages(label string) []stage {
+	args := []string{"flyctl", "deploy"}
+	if p.Image == "" {
+		args = append(args, "--image", fmt.Sprintf("registry.fly.io/%s:%s", p.App, label))
+	}
+	args = append(args, "--watch", watchTime)
+
+	if len(p.Groups) < 2 {
+		return []stage{{Name: "Deploy", Command: strings.Join(args, " ")}}
+	}
+
+	stages := make([]stage, 0, len(p.Groups))
+	for _, g := range p.Groups {
+		stages = append(stages, stage{
+			Name:    "Deploy " + g,
+			Command: strings.Join(append(args, "--only-process-groups", g), " "),
+		})
+	}
+
+	return stages
+}
+
+// DatabaseNote explains how migrations of the attached Postgres database run,
+// if at all.
+func (p *pipeline) DatabaseNote() string {
+	switch {
+	case !p.Postgres:
+		return ""
+	case p.ReleaseCommand != "":
+		return fmt.Sprintf("The release command %q migrates the attached Postgres database before machines are updated.", p.ReleaseCommand)
+	default:
+		return "DATABASE_URL is set but fly.toml has no release_command, so database migrations don't run on deploy."
+	}
+}
+
+// providers are the CI services workflows are written for, along with where
+// they're expected in repositories.
+var providers = map[string]struct {
+	path     string
+	template string
+}{
+	"github": {".github/workflows/fly-deploy.yml", githubTemplate},
+	"gitlab": {".gitlab-ci.yml", gitlabTemplate},
+}
+
+func (p *pipeline) write(w io.Writer, provider string) error {
+	tmpl, err := template.New(provider).Delims("[[", "]]").Parse(providers[provider].template)
+	if err != nil {
+		return err
+	}
+
+	return tmpl.Execute(w, p)
+}
+
+const githubTemplate = `# Deploys [[.App]] to Fly.io, generated by fly ci init.
+#
+# The FLY_API_TOKEN secret of the repository must hold a deploy token:
+#   fly tokens create deploy -a [[.App]] | gh secret set FLY_API_TOKEN
+name: Fly Deploy
+
+on:
+  push:
+    branches:
+      - [[.Branch]]
+
+concurrency:
+  group: fly-deploy-[[.App]]
+  cancel-in-progress: false
+
+env:
+  FLY_API_TOKEN: ${{ secrets.FLY_API_TOKEN }}
+
+jobs:
+[[- if not .Image]]
+  build:
+    name: Build
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v3
+      - uses: superfly/flyctl-actions/setup-flyctl@master
+      - run: flyctl deploy --build-only --push --remote-only --image-label ${{ github.sha }}
+[[end]]
+  deploy:
+    name: Deploy
+[[- if not .Image]]
+    needs: build
+[[- end]]
+    runs-on: ubuntu-latest
+    environment: production
+    steps:
+      - uses: actions/checkout@v3
+      - uses: superfly/flyctl-actions/setup-flyctl@master
+[[- with .DatabaseNote]]
+      # [[.]]
+[[- end]]
+[[- range .Stages "${{ github.sha }}"]]
+      - name: [[.Name]]
+        run: [[.Command]]
+[[- end]]
+[[- if .SmokeURL]]
+
+  smoke-test:
+    name: Smoke test
+    needs: deploy
+    runs-on: ubuntu-latest
+    steps:
+      - run: curl --fail --silent --show-error --retry 5 --retry-all-errors --retry-delay 5 [[.SmokeURL]]
+[[- end]]
+`
+
+const gitlabTemplate = `# Deploys [[.App]] to Fly.io, generated by fly ci init.
+#
+# The FLY_API_TOKEN variable of the project (Settings > CI/CD > Variables)
+# must hold a deploy token, masked and protected:
+#   fly tokens create deploy -a [[.App]]
+stages:
+  - build
+  - deploy
+  - test
+
+.flyctl:
+  image: curlimages/curl:latest
+  rules:
+    - if: $CI_COMMIT_BRANCH == "[[.Branch]]"
+  before_script:
+    - curl -L https://fly.io/install.sh | sh
+    - export PATH="$HOME/.fly/bin:$PATH"
+[[- if not .Image]]
+
+build:
+  extends: .flyctl
+  stage: build
+  script:
+    - flyctl deploy --build-only --push --remote-only --image-label $CI_COMMIT_SHA
+[[- end]]
+
+deploy:
+  extends: .flyctl
+  stage: deploy
+  environment: production
+  resource_group: fly-deploy-[[.App]]
+[[- with .DatabaseNote]]
+  # [[.]]
+[[- end]]
+  script:
+[[- range .Stages "$CI_COMMIT_SHA"]]
+    - [[.Command]]
+[[- end]]
+[[- if .SmokeURL]]
+
+smoke-test:
+  stage: test
+  image: curlimages/curl:latest
+  rules:
+    - if: $CI_COMMIT_BRANCH == "[[.Branch]]"
+  script:
+    - curl --fail --silent --show-error --retry 5 --retry-all-errors --retry-delay 5 [[.SmokeURL]]
+[[- end]]
+`
@@ -0,0 +1,105 @@
+package ci
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/superfly/flyctl/internal/appconfig"
+)
+
+func loadConfig(t *testing.T, toml string) *appconfig.Config {
+	path := filepath.Join(t.TempDir(), "fly.toml")
+	require.NoError(t, os.WriteFile(path, []byte(toml), 0o600))
+
+	cfg, err := appconfig.LoadConfig(path)
+	require.NoError(t, err)
+
+	return cfg
+}
+
+const webAndWorker = `
+app = "my-app"
+
+[deploy]
+  release_command = "bin/migrate"
+
+[processes]
+  web = "bin/web"
+  worker = "bin/worker"
+
+[http_service]
+  internal_port = 8080
+  processes = ["web"]
+
+[checks.health]
+  type = "http"
+  port = 8080
+  path = "/healthz"
+`
+
+func TestNewPipeline(t *testing.T) {
+	p := newPipeline(loadConfig(t, webAndWorker), "my-app", "main", true)
+
+	assert.Equal(t, []string{"worker", "web"}, p.Groups)
+	assert.Equal(t, "https://my-app.fly.dev/healthz", p.SmokeURL)
+	assert.Equal(t, "", p.Image)
+	assert.Contains(t, p.DatabaseNote(), `"bin/migrate"`)
+
+	stages := p.Stages("abc")
+	require.Len(t, stages, 2)
+	assert.Equal(t, "Deploy worker", stages[0].Name)
+	assert.Equal(t, "flyctl deploy --image registry.fly.io/my-app:abc --watch 60s --only-process-groups worker", stages[0].Command)
+	assert.Equal(t, "flyctl deploy --image registry.fly.io/my-app:abc --watch 60s --only-process-groups web", stages[1].Command)
+}
+
+func TestNewPipelineImage(t *testing.T) {
+	p := newPipeline(loadConfig(t, `
+app = "my-worker"
+
+[build]
+  image = "nginx:latest"
+`), "my-worker", "main", false)
+
+	assert.Equal(t, "nginx:latest", p.Image)
+	assert.Equal(t, "", p.SmokeURL)
+	assert.Equal(t, "", p.DatabaseNote())
+	assert.Equal(t, []stage{{Name: "Deploy", Command: "flyctl deploy --watch 60s"}}, p.Stages("abc"))
+}
+
+func TestWriteGitHub(t *testing.T) {
+	p := newPipeline(loadConfig(t, webAndWorker), "my-app", "release", false)
+
+	var b strings.Builder
+	require.NoError(t, p.write(&b, "github"))
+	out := b.String()
+
+	assert.Contains(t, out, "    branches:\n      - release\n")
+	assert.Contains(t, out, "--image-label ${{ github.sha }}\n")
+	assert.Contains(t, out, "    needs: build\n")
+	assert.Contains(t, out, "      - name: Deploy worker\n        run: flyctl deploy --image registry.fly.io/my-app:${{ github.sha }} --watch 60s --only-process-groups worker\n")
+	assert.Contains(t, out, "  smoke-test:\n")
+	assert.Contains(t, out, "https://my-app.fly.dev/healthz\n")
+	assert.NotContains(t, out, "DATABASE_URL")
+}
+
+func TestWriteGitLab(t *testing.T) {
+	p := newPipeline(loadConfig(t, `
+app = "my-worker"
+
+[build]
+  image = "nginx:latest"
+`), "my-worker", "main", true)
+
+	var b strings.Builder
+	require.NoError(t, p.write(&b, "gitlab"))
+	out := b.String()
+
+	assert.NotContains(t, out, "\nbuild:")
+	assert.NotContains(t, out, "smoke-test")
+	assert.Contains(t, out, "  # DATABASE_URL is set but fly.toml has no release_command")
+	assert.Contains(t, out, "  script:\n    - flyctl deploy --watch 60s\n")
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
@@ -19,14 +20,19 @@ import (
 	"github.com/superfly/flyctl/iostreams"
 
 	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flyctl"
 	"github.com/superfly/flyctl/internal/appconfig"
 	"github.com/superfly/flyctl/internal/buildinfo"
 	"github.com/superfly/flyctl/internal/cache"
 	"github.com/superfly/flyctl/internal/config"
 	"github.com/superfly/flyctl/internal/env"
 	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/gitsource"
 	"github.com/superfly/flyctl/internal/logger"
 	"github.com/superfly/flyctl/internal/metrics"
+	"github.com/superfly/flyctl/internal/progress"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/internal/render"
 	"github.com/superfly/flyctl/internal/state"
 	"github.com/superfly/flyctl/internal/task"
 	"github.com/superfly/flyctl/internal/update"
@@ -59,6 +65,9 @@ var commonPreparers = []Preparer{
 	ensureConfigDirPerms,
 	loadCache,
 	loadConfig,
+	determineOutputFormat,
+	initProgress,
+	loadAnswers,
 	initTaskManager,
 	startQueryingForNewRelease,
 	promptToUpdate,
@@ -78,6 +87,8 @@ func WrapRunE(fn func(*cobra.Command, []string) error) func(*cobra.Command, []st
 			return
 		}
 
+		noticeDeprecation(ctx)
+
 		err = fn(cmd, args)
 
 		// and the
@@ -118,6 +129,8 @@ func newRunE(fn Runner, preparers ...Preparer) func(*cobra.Command, []string) er
 			return
 		}
 
+		noticeDeprecation(ctx)
+
 		sendOsMetric(ctx, "started")
 		defer func() {
 			if err == nil {
@@ -140,6 +153,29 @@ func newRunE(fn Runner, preparers ...Preparer) func(*cobra.Command, []string) er
 	}
 }
 
+// PrepareCompletion returns the context shell completion functions of cmd run
+// in. Unlike commands, they only get the configuration and an authenticated
+// API client: they must not print anything nor check for updates.
+func PrepareCompletion(cmd *cobra.Command, preparers ...Preparer) (context.Context, error) {
+	ctx := cmd.Context()
+	ctx = NewContext(ctx, cmd)
+	ctx = flag.NewContext(ctx, cmd.Flags())
+
+	ctx, err := prepare(ctx,
+		determineWorkingDir,
+		determineUserHomeDir,
+		determineConfigDir,
+		loadConfig,
+		initClient,
+		RequireSession,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return prepare(ctx, preparers...)
+}
+
 func prepare(parent context.Context, preparers ...Preparer) (ctx context.Context, err error) {
 	ctx = parent
 
@@ -191,6 +227,38 @@ func determineWorkingDir(ctx context.Context) (context.Context, error) {
 	return state.WithWorkingDirectory(ctx, wd), nil
 }
 
+// CloneFromGitIfRequested clones the repository named by --from-git, at the
+// ref named by --git-ref, and makes it the working directory. It's cloned into
+// the directory named by --path, or one named after the repository.
+func CloneFromGitIfRequested(ctx context.Context) (context.Context, error) {
+	repo := flag.GetString(ctx, "from-git")
+	if repo == "" {
+		return ctx, nil
+	}
+
+	dir := flag.GetString(ctx, "path")
+	if !flag.IsSpecified(ctx, "path") {
+		var err error
+		if dir, err = gitsource.DirName(repo); err != nil {
+			return nil, err
+		}
+	}
+
+	io := iostreams.FromContext(ctx)
+	ref := flag.GetString(ctx, "git-ref")
+	if ref != "" {
+		fmt.Fprintf(io.Out, "Cloning %s at %s into %s\n", repo, ref, dir)
+	} else {
+		fmt.Fprintf(io.Out, "Cloning %s into %s\n", repo, dir)
+	}
+
+	if err := gitsource.Clone(ctx, repo, ref, dir); err != nil {
+		return nil, fmt.Errorf("failed cloning %s: %w", repo, err)
+	}
+
+	return ChangeWorkingDirectory(ctx, dir)
+}
+
 func determineUserHomeDir(ctx context.Context) (context.Context, error) {
 	wd, err := os.UserHomeDir()
 	if err != nil {
@@ -204,7 +272,7 @@ func determineUserHomeDir(ctx context.Context) (context.Context, error) {
 }
 
 func determineConfigDir(ctx context.Context) (context.Context, error) {
-	dir := filepath.Join(state.UserHomeDirectory(ctx), ".fly")
+	dir := flyctl.ProfileConfigDir(state.UserHomeDirectory(ctx), flyctl.Profile())
 
 	logger.FromContext(ctx).
 		Debugf("determined config directory: %q", dir)
@@ -304,6 +372,76 @@ func loadConfig(ctx context.Context) (context.Context, error) {
 	return config.NewContext(ctx, cfg), nil
 }
 
+// determineOutputFormat applies the global output flag. Commands rendering
+// structured output do so when their json flag is set, so that flag is implied
+// by the structured formats, which the commands lacking it don't support.
+func determineOutputFormat(ctx context.Context) (context.Context, error) {
+	cfg := config.FromContext(ctx)
+	cmd := FromContext(ctx)
+
+	format := render.Format{Kind: render.FormatTable}
+	if cfg.JSONOutput {
+		format.Kind = render.FormatJSON
+	}
+
+	// commands defining their own output flag, e.g. to denote a file path,
+	// shadow the global one
+	if f := cmd.Flags().Lookup(flag.OutputName); f != nil && f.Changed && f == cmd.Root().PersistentFlags().Lookup(flag.OutputName) {
+		var err error
+		if format, err = render.ParseFormat(f.Value.String()); err != nil {
+			return nil, err
+		}
+
+		if format.Structured() && cmd.Flags().Lookup(flag.JSONOutputName) == nil {
+			return nil, fmt.Errorf("%s doesn't support the %s output format", cmd.CommandPath(), format.Kind)
+		}
+	}
+
+	cfg.JSONOutput = format.Structured()
+	cfg.WideOutput = format.Wide()
+
+	return render.NewContext(ctx, format), nil
+}
+
+// initProgress sets up the bus long running commands publish their progress
+// to. With JSON output, the events are written to stdout as newline delimited
+// JSON.
+func initProgress(ctx context.Context) (context.Context, error) {
+	bus := progress.NewBus()
+	if render.FormatFromContext(ctx).Kind == render.FormatJSON {
+		bus.Subscribe(progress.NDJSON(iostreams.FromContext(ctx).Out))
+	}
+
+	return progress.NewContext(ctx, bus), nil
+}
+
+func loadAnswers(ctx context.Context) (context.Context, error) {
+	path := config.FromContext(ctx).AnswersFile
+	if path == "" {
+		return ctx, nil
+	}
+
+	var r io.Reader = iostreams.FromContext(ctx).In
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed opening answers file: %w", err)
+		}
+		defer f.Close() // skipcq: GO-S2307
+
+		r = f
+	}
+
+	answers, err := prompt.ReadAnswers(r)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx).Debugf("loaded %d answers from %s", len(answers), path)
+
+	return prompt.WithAnswers(ctx, answers), nil
+}
+
 func initClient(ctx context.Context) (context.Context, error) {
 	logger := logger.FromContext(ctx)
 	cfg := config.FromContext(ctx)
@@ -311,12 +449,25 @@ func initClient(ctx context.Context) (context.Context, error) {
 	// TODO: refactor so that api package does NOT depend on global state
 	api.SetBaseURL(cfg.APIBaseURL)
 	api.SetErrorLog(cfg.LogGQLErrors)
+	api.SetDryRun(nil)
+	if cfg.DryRun && !plansDryRun(ctx) {
+		api.SetDryRun(iostreams.FromContext(ctx).Out)
+	}
 	c := client.FromToken(cfg.AccessToken)
 	logger.Debug("client initialized.")
 
 	return client.NewContext(ctx, c), nil
 }
 
+// plansDryRun reports whether the command implements dry runs itself, by
+// defining its own dry-run flag. Dry runs of the other commands stop at their
+// first API mutation.
+func plansDryRun(ctx context.Context) bool {
+	cmd := FromContext(ctx)
+	f := cmd.Flags().Lookup(flag.DryRunName)
+	return f != nil && f != cmd.Root().PersistentFlags().Lookup(flag.DryRunName)
+}
+
 func initTaskManager(ctx context.Context) (context.Context, error) {
 	tm := task.New(ctx)
 
@@ -2,7 +2,6 @@ package config
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 
 	"github.com/spf13/cobra"
@@ -11,6 +10,7 @@ import (
 	"github.com/superfly/flyctl/internal/command"
 	"github.com/superfly/flyctl/internal/command/apps"
 	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/render"
 	"github.com/superfly/flyctl/iostreams"
 )
 
@@ -18,7 +18,8 @@ func newShow() (cmd *cobra.Command) {
 	const (
 		short = "Show an app's configuration"
 		long  = `Show an application's configuration. The configuration is presented
-in JSON format. The configuration data is retrieved from the Fly service.`
+in JSON format, or YAML with --output yaml. The configuration data is retrieved
+from the Fly service.`
 	)
 	cmd = command.New("show", short, long, runShow,
 		command.RequireSession,
@@ -26,7 +27,7 @@ in JSON format. The configuration data is retrieved from the Fly service.`
 	)
 	cmd.Args = cobra.NoArgs
 	cmd.Aliases = []string{"display"}
-	flag.Add(cmd, flag.App(), flag.AppConfig())
+	flag.Add(cmd, flag.App(), flag.AppConfig(), flag.JSONOutput())
 	return
 }
 
@@ -50,10 +51,5 @@ func runShow(ctx context.Context) error {
 		return err
 	}
 
-	b, err := json.MarshalIndent(cfg, "", "  ")
-	if err != nil {
-		return err
-	}
-	fmt.Fprintln(io.Out, string(b))
-	return nil
+	return render.Output(ctx, io.Out, cfg)
 }
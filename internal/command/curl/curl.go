@@ -69,7 +69,7 @@ func run(ctx context.Context) error {
 	if io := iostreams.FromContext(ctx); !config.FromContext(ctx).JSONOutput {
 		renderTextTimings(io.Out, io.ColorScheme(), timings)
 	} else {
-		renderJSONTimings(io.Out, timings)
+		renderJSONTimings(ctx, io.Out, timings)
 	}
 
 	return nil
@@ -310,7 +310,7 @@ func renderTextTimings(w io.Writer, cs *iostreams.ColorScheme, timings []*timing
 	render.Table(w, "Failures", rows, "Region", "Error")
 }
 
-func renderJSONTimings(w io.Writer, timings []*timing) {
+func renderJSONTimings(ctx context.Context, w io.Writer, timings []*timing) {
 	items := make(map[string]interface{}, len(timings))
 	for _, t := range timings {
 		if t.error != nil {
@@ -324,5 +324,5 @@ func renderJSONTimings(w io.Writer, timings []*timing) {
 		}
 	}
 
-	render.JSON(w, items)
+	render.Output(ctx, w, items)
 }
@@ -2,6 +2,7 @@ package deploy
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -15,7 +16,9 @@ import (
 	"github.com/superfly/flyctl/internal/appconfig"
 	"github.com/superfly/flyctl/internal/build/imgsrc"
 	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
 	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/progress"
 	"github.com/superfly/flyctl/internal/render"
 	"github.com/superfly/flyctl/internal/sentry"
 
@@ -43,6 +46,36 @@ var CommonFlags = flag.Set{
 	flag.NoCache(),
 	flag.Nixpacks(),
 	flag.BuildOnly(),
+	flag.String{
+		Name:        "from-git",
+		Description: "Clone this Git repository and use it as the source of the app",
+	},
+	flag.String{
+		Name:        "git-ref",
+		Description: "The branch, tag or commit of the --from-git repository to use, defaults to its default branch",
+	},
+	flag.String{
+		Name:        "image-archive",
+		Description: "Deploy the image of an OCI image layout exported to a tar archive or directory, without a Docker daemon",
+	},
+	flag.String{
+		Name:        "canary-size",
+		Description: "Number of machines, or percentage of them (e.g. 10%), to update first with the canary strategy",
+		Default:     "1",
+	},
+	flag.Duration{
+		Name:        "canary-bake-time",
+		Description: "How long canary machines must stay healthy before updating the others",
+		Default:     time.Minute,
+	},
+	flag.Int{
+		Name:        "canary-max-error-rate",
+		Description: "Roll canary machines back when more than this percentage of their HTTP responses are 5xx errors while baking, as reported by app metrics. 0 disables the check",
+	},
+	flag.Bool{
+		Name:        "force-build",
+		Description: "Build the image even if the build context is unchanged since the last deployment",
+	},
 	flag.StringSlice{
 		Name:        "env",
 		Shorthand:   "e",
@@ -54,7 +87,7 @@ var CommonFlags = flag.Set{
 	},
 	flag.Int{
 		Name:        "wait-timeout",
-		Description: "Seconds to wait for individual machines to transition states and become healthy.",
+		Description: "Seconds to wait for individual machines to transition states and become healthy. Overrides deploy.wait_timeout in fly.toml.",
 		Default:     int(DefaultWaitTimeout.Seconds()),
 	},
 	flag.Int{
@@ -88,6 +121,8 @@ func New() (cmd *cobra.Command) {
 		long = `Deploy Fly applications from source or an image using a local or remote builder.
 
 		To disable colorized output and show full Docker build output, set the environment variable NO_COLOR=1.
+
+		Pass --from-git with the URL of a Git repository to deploy it, at --git-ref if set. It's cloned into a directory named after the repository.
 	`
 		short = "Deploy Fly applications"
 	)
@@ -95,6 +130,7 @@ func New() (cmd *cobra.Command) {
 	cmd = command.New("deploy [WORKING_DIRECTORY]", short, long, run,
 		command.RequireSession,
 		command.ChangeWorkingDirectoryToFirstArgIfPresent,
+		command.CloneFromGitIfRequested,
 		command.RequireAppName,
 	)
 
@@ -104,6 +140,27 @@ func New() (cmd *cobra.Command) {
 		CommonFlags,
 		flag.App(),
 		flag.AppConfig(),
+		flag.JSONOutput(),
+		flag.Bool{
+			Name:        "dry-run",
+			Description: "Print the machine configs the deployment would apply, without changing any machine. (Machines only)",
+		},
+		flag.Bool{
+			Name:        "bluegreen-abort",
+			Description: "Abort a blue-green deployment which was interrupted, destroying its green machines",
+		},
+		flag.StringSlice{
+			Name:        "only-process-groups",
+			Description: "Only deploy the machines of the given process groups, leaving the others untouched. (Machines only)",
+		},
+		flag.Bool{
+			Name:        "force-unlock",
+			Description: "Break the deploy lock left by another deployment of the app. (Machines only)",
+		},
+		flag.Duration{
+			Name:        "watch",
+			Description: "After deploying, tail logs and health checks for the given duration and fail if machines crash loop or checks flap. (Machines only)",
+		},
 	)
 
 	return
@@ -117,6 +174,10 @@ func run(ctx context.Context) error {
 	}
 	ctx = flaps.NewContext(ctx, flapsClient)
 
+	if flag.GetBool(ctx, "bluegreen-abort") {
+		return AbortBlueGreen(ctx)
+	}
+
 	appConfig, err := determineAppConfig(ctx)
 	if err != nil {
 		if strings.Contains(err.Error(), "Could not find App") {
@@ -125,11 +186,40 @@ func run(ctx context.Context) error {
 		return err
 	}
 
-	return DeployWithConfig(ctx, appConfig, DeployWithConfigArgs{
+	timeline := watch.NewTimeline()
+	ctx = watch.WithTimeline(ctx, timeline)
+
+	// stdout holds the progress events when they're streamed
+	ctx = progress.SeparateOutput(ctx)
+
+	event := progress.Event{Stage: progress.StageDeploy, Message: appName}
+	progress.Publish(ctx, event.WithStatus(progress.StatusStarted))
+
+	if err := DeployWithConfig(ctx, appConfig, DeployWithConfigArgs{
 		ForceNomad:    flag.GetBool(ctx, "force-nomad"),
 		ForceMachines: flag.GetBool(ctx, "force-machines"),
 		ForceYes:      flag.GetBool(ctx, "auto-confirm"),
-	})
+	}); err != nil {
+		progress.Fail(ctx, event, err)
+		return err
+	}
+
+	switch bus := progress.FromContext(ctx); {
+	case flag.GetBool(ctx, "dry-run"):
+		// dry runs publish the changes they'd apply themselves
+		return nil
+	case bus.Active():
+		// the timeline completes the stream of events rather than following it
+		event.Data = timeline.Summary()
+		bus.Publish(event.WithStatus(progress.StatusCompleted))
+		return nil
+	case flag.GetBuildOnly(ctx):
+		return nil
+	}
+
+	io := iostreams.FromContext(ctx)
+	fmt.Fprintln(io.Out)
+	return timeline.Render(ctx, io.Out, config.FromContext(ctx).JSONOutput)
 }
 
 type DeployWithConfigArgs struct {
@@ -147,11 +237,22 @@ func DeployWithConfig(ctx context.Context, appConfig *appconfig.Config, args Dep
 	}
 
 	// Fetch an image ref or build from source to get the final image reference to deploy
+	imageStarted := time.Now()
+	endBuild := progress.Begin(ctx, progress.Event{Stage: progress.StageBuild})
 	img, err := determineImage(ctx, appConfig)
+	endBuild(err)
 	if err != nil {
 		return fmt.Errorf("failed to fetch an image or build from source: %w", err)
 	}
 
+	timeline := watch.TimelineFromContext(ctx)
+	if img.BuildDuration > 0 || img.PushDuration > 0 {
+		timeline.Add("Build image", img.BuildDuration)
+		timeline.Add("Push image", img.PushDuration)
+	} else {
+		timeline.Add("Resolve image", time.Since(imageStarted))
+	}
+
 	if flag.GetBuildOnly(ctx) {
 		return nil
 	}
@@ -164,10 +265,13 @@ func DeployWithConfig(ctx context.Context, appConfig *appconfig.Config, args Dep
 			return fmt.Errorf("Can't deploy an invalid v2 app config: %s", err)
 		}
 		err := deployToMachines(ctx, appConfig, appCompact, img)
-		if err != nil {
+		if err != nil || flag.GetBool(ctx, "dry-run") {
 			return err
 		}
 	default:
+		if flag.GetBool(ctx, "dry-run") {
+			return errors.New("--dry-run is only supported by apps on the machines platform")
+		}
 		err = deployToNomad(ctx, appConfig, appCompact, img)
 		if err != nil {
 			return err
@@ -175,7 +279,7 @@ func DeployWithConfig(ctx context.Context, appConfig *appconfig.Config, args Dep
 	}
 
 	url, err := appConfig.URL()
-	if err == nil && url != nil {
+	if err == nil && url != nil && !progress.FromContext(ctx).Active() {
 		fmt.Println("Visit your newly deployed app at", url)
 	}
 
@@ -194,14 +298,22 @@ func deployToMachines(ctx context.Context, appConfig *appconfig.Config, appCompa
 	md, err := NewMachineDeployment(ctx, MachineDeploymentArgs{
 		AppCompact:            appCompact,
 		DeploymentImage:       img.Tag,
+		ContextDigest:         img.ContextDigest,
 		Strategy:              flag.GetString(ctx, "strategy"),
 		EnvFromFlags:          flag.GetStringSlice(ctx, "env"),
 		PrimaryRegionFlag:     appConfig.PrimaryRegion,
 		SkipHealthChecks:      flag.GetDetach(ctx),
-		WaitTimeout:           time.Duration(flag.GetInt(ctx, "wait-timeout")) * time.Second,
+		WaitTimeout:           waitTimeout(ctx, appConfig),
 		LeaseTimeout:          time.Duration(flag.GetInt(ctx, "lease-timeout")) * time.Second,
 		VMSize:                flag.GetString(ctx, "vm-size"),
 		IncreasedAvailability: flag.GetBool(ctx, "ha"),
+		CanarySize:            flag.GetString(ctx, "canary-size"),
+		CanaryBakeTime:        flag.GetDuration(ctx, "canary-bake-time"),
+		CanaryMaxErrorRate:    flag.GetInt(ctx, "canary-max-error-rate"),
+		DryRun:                flag.GetBool(ctx, "dry-run"),
+		WatchDuration:         flag.GetDuration(ctx, "watch"),
+		ForceUnlock:           flag.GetBool(ctx, "force-unlock"),
+		OnlyProcessGroups:     flag.GetStringSlice(ctx, "only-process-groups"),
 	})
 	if err != nil {
 		sentry.CaptureExceptionWithAppInfo(err, "deploy", appCompact)
@@ -352,3 +464,13 @@ func createRelease(ctx context.Context, appConfig *appconfig.Config, img *imgsrc
 
 	return release, releaseCommand, err
 }
+
+// waitTimeout returns the --wait-timeout flag when specified, or the wait
+// timeout of the deploy section of the app config.
+func waitTimeout(ctx context.Context, appConfig *appconfig.Config) time.Duration {
+	if !flag.IsSpecified(ctx, "wait-timeout") && appConfig.Deploy != nil && appConfig.Deploy.WaitTimeout != nil {
+		return appConfig.Deploy.WaitTimeout.Duration
+	}
+
+	return time.Duration(flag.GetInt(ctx, "wait-timeout")) * time.Second
+}
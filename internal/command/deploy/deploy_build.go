@@ -7,7 +7,9 @@ import (
 	"path/filepath"
 
 	"github.com/dustin/go-humanize"
+	"github.com/superfly/flyctl/api"
 	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
 	"github.com/superfly/flyctl/internal/appconfig"
 	"github.com/superfly/flyctl/internal/build/imgsrc"
 	"github.com/superfly/flyctl/internal/cmdutil"
@@ -59,6 +61,26 @@ func determineImage(ctx context.Context, appConfig *appconfig.Config) (img *imgs
 
 	resolver := imgsrc.NewResolver(daemonType, client, appConfig.AppName, io)
 
+	// we're pushing an image exported elsewhere
+	if archive := flag.GetString(ctx, "image-archive"); archive != "" {
+		if flag.GetString(ctx, "image") != "" {
+			return nil, errors.New("--image and --image-archive are mutually exclusive")
+		}
+
+		img, err = imgsrc.PushImageArchive(ctx, io, imgsrc.ArchiveOptions{
+			AppName:    appConfig.AppName,
+			Path:       archive,
+			ImageLabel: flag.GetString(ctx, "image-label"),
+			Publish:    !flag.GetBuildOnly(ctx),
+		})
+		if err == nil {
+			tb.Printf("image: %s\n", img.Tag)
+			tb.Printf("image size: %s\n", humanize.Bytes(uint64(img.Size)))
+		}
+
+		return
+	}
+
 	var imageRef string
 	if imageRef, err = fetchImageRef(ctx, appConfig); err != nil {
 		return
@@ -95,6 +117,8 @@ func determineImage(ctx context.Context, appConfig *appconfig.Config) (img *imgs
 		BuiltInSettings: build.Settings,
 		Builder:         build.Builder,
 		Buildpacks:      build.Buildpacks,
+		FollowSymlinks:  build.FollowSymlinks,
+		Dereference:     build.Dereference,
 	}
 
 	cliBuildSecrets, err := cmdutil.ParseKVStringsToMap(flag.GetStringSlice(ctx, "build-secret"))
@@ -127,6 +151,18 @@ func determineImage(ctx context.Context, appConfig *appconfig.Config) (img *imgs
 		opts.Target = target
 	}
 
+	digest, err := imgsrc.ContextDigest(opts)
+	if err != nil {
+		terminal.Debugf("failed computing build context digest: %v\n", err)
+	}
+
+	if digest != "" && opts.Publish && !flag.GetBool(ctx, "force-build") {
+		if img = deployedImageForDigest(ctx, appConfig.AppName, digest); img != nil {
+			tb.Donef("build context unchanged since the last deployment, reusing image %s (use --force-build to rebuild)", img.Tag)
+			return img, nil
+		}
+	}
+
 	// finally, build the image
 	heartbeat, err := resolver.StartHeartbeat(ctx)
 	if err != nil {
@@ -147,6 +183,7 @@ func determineImage(ctx context.Context, appConfig *appconfig.Config) (img *imgs
 	}
 
 	if err == nil {
+		img.ContextDigest = digest
 		tb.Printf("image: %s\n", img.Tag)
 		tb.Printf("image size: %s\n", humanize.Bytes(uint64(img.Size)))
 	}
@@ -154,6 +191,32 @@ func determineImage(ctx context.Context, appConfig *appconfig.Config) (img *imgs
 	return
 }
 
+// deployedImageForDigest returns the image currently deployed to the app's
+// machines if it was built from a build context with the given digest.
+func deployedImageForDigest(ctx context.Context, appName, digest string) *imgsrc.DeploymentImage {
+	flapsClient, err := flaps.NewFromAppName(ctx, appName)
+	if err != nil {
+		return nil
+	}
+
+	machines, _, err := flapsClient.ListFlyAppsMachines(ctx)
+	if err != nil {
+		terminal.Debugf("failed listing machines to compare build context digests: %v\n", err)
+		return nil
+	}
+
+	for _, m := range machines {
+		if m.Config != nil && m.Config.Metadata[api.MachineConfigMetadataKeyFlyContextDigest] == digest {
+			return &imgsrc.DeploymentImage{
+				Tag:           m.Config.Image,
+				ContextDigest: digest,
+			}
+		}
+	}
+
+	return nil
+}
+
 // resolveDockerfilePath returns the absolute path to the Dockerfile
 // if one was specified in the app config or a command line argument
 func resolveDockerfilePath(ctx context.Context, appConfig *appconfig.Config) (path string, err error) {
@@ -6,7 +6,9 @@ import (
 
 	"github.com/samber/lo"
 	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/internal/policy"
 	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/terminal"
 )
 
 func (md *machineDeployment) provisionFirstDeploy(ctx context.Context) error {
@@ -39,6 +41,15 @@ func (md *machineDeployment) provisionIpsOnFirstDeploy(ctx context.Context) erro
 
 	switch md.appConfig.HasNonHttpAndHttpsStandardServices() {
 	case true:
+		p, err := policy.Load(ctx, md.app.Organization.Slug)
+		if err != nil {
+			return err
+		}
+		if violations := p.IPViolations("v4"); len(violations) > 0 {
+			terminal.Warnf("Not allocating a dedicated ipv4 address, %s\n", p.Error(violations))
+			return nil
+		}
+
 		hasUdpService := md.appConfig.HasUdpService()
 
 		ipStuffStr := "a dedicated ipv4 address"
@@ -0,0 +1,37 @@
+package deploy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/flag"
+)
+
+func TestWaitTimeout(t *testing.T) {
+	withFlag := func(value string) context.Context {
+		fs := pflag.NewFlagSet("deploy", pflag.ContinueOnError)
+		fs.Int("wait-timeout", int(DefaultWaitTimeout.Seconds()), "")
+		if value != "" {
+			require.NoError(t, fs.Set("wait-timeout", value))
+		}
+		return flag.NewContext(context.Background(), fs)
+	}
+
+	withoutDeploy := appconfig.NewConfig()
+	withDeploy := appconfig.NewConfig()
+	withDeploy.Deploy = &appconfig.Deploy{WaitTimeout: api.MustParseDuration("7m")}
+
+	// the default applies without flag or fly.toml setting
+	assert.Equal(t, DefaultWaitTimeout, waitTimeout(withFlag(""), withoutDeploy))
+	// fly.toml overrides the default
+	assert.Equal(t, 7*time.Minute, waitTimeout(withFlag(""), withDeploy))
+	// the flag overrides fly.toml
+	assert.Equal(t, 90*time.Second, waitTimeout(withFlag("90"), withDeploy))
+}
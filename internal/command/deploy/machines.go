@@ -19,11 +19,12 @@ import (
 	"github.com/superfly/flyctl/internal/machine"
 	"github.com/superfly/flyctl/iostreams"
 	"github.com/superfly/flyctl/terminal"
+	"golang.org/x/exp/slices"
 )
 
 const (
 	DefaultWaitTimeout = 120 * time.Second
-	DefaultLeaseTtl    = 13 * time.Second
+	DefaultLeaseTtl    = machine.DefaultLeaseTtl
 )
 
 type MachineDeployment interface {
@@ -33,6 +34,7 @@ type MachineDeployment interface {
 type MachineDeploymentArgs struct {
 	AppCompact            *api.AppCompact
 	DeploymentImage       string
+	ContextDigest         string
 	Strategy              string
 	EnvFromFlags          []string
 	PrimaryRegionFlag     string
@@ -42,6 +44,13 @@ type MachineDeploymentArgs struct {
 	LeaseTimeout          time.Duration
 	VMSize                string
 	IncreasedAvailability bool
+	CanarySize            string
+	CanaryBakeTime        time.Duration
+	CanaryMaxErrorRate    int
+	DryRun                bool
+	WatchDuration         time.Duration
+	ForceUnlock           bool
+	OnlyProcessGroups     []string
 }
 
 type machineDeployment struct {
@@ -53,6 +62,7 @@ type machineDeployment struct {
 	app                   *api.AppCompact
 	appConfig             *appconfig.Config
 	img                   string
+	contextDigest         string
 	machineSet            machine.MachineSet
 	releaseCommandMachine machine.MachineSet
 	volumes               map[string][]api.Volume
@@ -67,6 +77,19 @@ type machineDeployment struct {
 	isFirstDeploy         bool
 	machineGuest          *api.MachineGuest
 	increasedAvailability bool
+	canarySize            string
+	canaryBakeTime        time.Duration
+	canaryMaxErrorRate    int
+	checkTimeout          time.Duration
+	checkGracePeriod      time.Duration
+	dryRun                bool
+	watchDuration         time.Duration
+	forceUnlock           bool
+	onlyProcessGroups     []string
+	appMachines           []machine.LeasableMachine
+	// lockHolder holds the deploy lock, recorded in its metadata as lock
+	lockHolder machine.LeasableMachine
+	lock       string
 }
 
 func NewMachineDeployment(ctx context.Context, args MachineDeploymentArgs) (MachineDeployment, error) {
@@ -105,7 +128,7 @@ func NewMachineDeployment(ctx context.Context, args MachineDeploymentArgs) (Mach
 	if leaseTimeout == 0 {
 		leaseTimeout = DefaultLeaseTtl
 	}
-	leaseDelayBetween := (leaseTimeout - 1*time.Second) / 3
+	leaseDelayBetween := machine.LeaseDelayBetween(leaseTimeout)
 	if waitTimeout != DefaultWaitTimeout || leaseTimeout != DefaultLeaseTtl || args.WaitTimeout == 0 || args.LeaseTimeout == 0 {
 		terminal.Infof("Using wait timeout: %s lease timeout: %s delay between lease refreshes: %s\n", waitTimeout, leaseTimeout, leaseDelayBetween)
 	}
@@ -120,12 +143,28 @@ func NewMachineDeployment(ctx context.Context, args MachineDeploymentArgs) (Mach
 		app:                   args.AppCompact,
 		appConfig:             appConfig,
 		img:                   args.DeploymentImage,
+		contextDigest:         args.ContextDigest,
 		skipHealthChecks:      args.SkipHealthChecks,
 		restartOnly:           args.RestartOnly,
 		waitTimeout:           waitTimeout,
 		leaseTimeout:          leaseTimeout,
 		leaseDelayBetween:     leaseDelayBetween,
 		increasedAvailability: args.IncreasedAvailability,
+		canarySize:            args.CanarySize,
+		canaryBakeTime:        args.CanaryBakeTime,
+		canaryMaxErrorRate:    args.CanaryMaxErrorRate,
+		dryRun:                args.DryRun,
+		watchDuration:         args.WatchDuration,
+		forceUnlock:           args.ForceUnlock,
+	}
+	md.checkTimeout = waitTimeout
+	if appConfig.Deploy != nil {
+		if appConfig.Deploy.CheckTimeout != nil {
+			md.checkTimeout = appConfig.Deploy.CheckTimeout.Duration
+		}
+		if appConfig.Deploy.CheckGracePeriod != nil {
+			md.checkGracePeriod = appConfig.Deploy.CheckGracePeriod.Duration
+		}
 	}
 	if err := md.setStrategy(args.Strategy); err != nil {
 		return nil, err
@@ -133,6 +172,9 @@ func NewMachineDeployment(ctx context.Context, args MachineDeploymentArgs) (Mach
 	if err := md.setMachineGuest(args.VMSize); err != nil {
 		return nil, err
 	}
+	if err := md.setOnlyProcessGroups(args.OnlyProcessGroups); err != nil {
+		return nil, err
+	}
 	if err := md.setMachinesForDeployment(ctx); err != nil {
 		return nil, err
 	}
@@ -147,14 +189,19 @@ func NewMachineDeployment(ctx context.Context, args MachineDeploymentArgs) (Mach
 	}
 
 	// Provisioning must come after setVolumes
-	if err := md.provisionFirstDeploy(ctx); err != nil {
-		return nil, err
+	if !md.dryRun {
+		if err := md.provisionFirstDeploy(ctx); err != nil {
+			return nil, err
+		}
 	}
 
 	// validations must happen after every else
 	if err := md.validateVolumeConfig(); err != nil {
 		return nil, err
 	}
+	if md.dryRun {
+		return md, nil
+	}
 	if err = md.createReleaseInBackend(ctx); err != nil {
 		return nil, err
 	}
@@ -166,7 +213,7 @@ func (md *machineDeployment) setFirstDeploy(ctx context.Context) error {
 	// by checking for any existent machine.
 	// This is not exaustive as the app could still be scaled down to zero but the
 	// workaround works better for now until it is fixed
-	md.isFirstDeploy = !md.app.Deployed && md.machineSet.IsEmpty()
+	md.isFirstDeploy = !md.app.Deployed && len(md.appMachines) == 0
 	return nil
 }
 
@@ -205,6 +252,12 @@ func (md *machineDeployment) setMachinesForDeployment(ctx context.Context) error
 	}
 
 	md.machineSet = machine.NewMachineSet(md.flapsClient, md.io, machines)
+	md.appMachines = md.machineSet.GetMachines()
+	if len(md.onlyProcessGroups) > 0 {
+		md.machineSet = machine.NewMachineSetFromLeasables(lo.Filter(md.appMachines, func(lm machine.LeasableMachine, _ int) bool {
+			return slices.Contains(md.onlyProcessGroups, lm.Machine().ProcessGroup())
+		}))
+	}
 	var releaseCmdSet []*api.Machine
 	if releaseCmdMachine != nil {
 		releaseCmdSet = []*api.Machine{releaseCmdMachine}
@@ -252,7 +305,7 @@ func (md *machineDeployment) validateVolumeConfig() error {
 			return m.ProcessGroup()
 		})
 
-	for _, groupName := range md.appConfig.ProcessNames() {
+	for _, groupName := range md.processNames() {
 		groupConfig, err := md.appConfig.Flatten(groupName)
 		if err != nil {
 			return err
@@ -313,6 +366,28 @@ func (md *machineDeployment) validateVolumeConfig() error {
 	return nil
 }
 
+func (md *machineDeployment) setOnlyProcessGroups(groups []string) error {
+	groupsInConfig := md.appConfig.ProcessNames()
+	for _, group := range groups {
+		if !slices.Contains(groupsInConfig, group) {
+			return fmt.Errorf("process group '%s' isn't defined in the app config, available groups are: %s", group, strings.Join(groupsInConfig, ", "))
+		}
+	}
+	md.onlyProcessGroups = lo.Uniq(groups)
+	return nil
+}
+
+// processNames returns the process groups the deployment applies to: every
+// group of the app config unless limited with --only-process-groups.
+func (md *machineDeployment) processNames() []string {
+	if len(md.onlyProcessGroups) == 0 {
+		return md.appConfig.ProcessNames()
+	}
+	return lo.Filter(md.appConfig.ProcessNames(), func(name string, _ int) bool {
+		return slices.Contains(md.onlyProcessGroups, name)
+	})
+}
+
 func (md *machineDeployment) setImg(ctx context.Context) error {
 	if md.img != "" {
 		return nil
@@ -367,8 +442,8 @@ func (md *machineDeployment) setStrategy(passedInStrategy string) error {
 	} else {
 		md.strategy = "rolling"
 	}
-	if md.strategy != "rolling" && md.strategy != "immediate" {
-		return fmt.Errorf("error unsupported deployment strategy '%s'; fly deploy for machines supports rolling and immediate strategies", md.strategy)
+	if !lo.Contains([]string{"rolling", "immediate", "canary", "bluegreen"}, md.strategy) {
+		return fmt.Errorf("error unsupported deployment strategy '%s'; fly deploy for machines supports rolling, immediate, canary and bluegreen strategies", md.strategy)
 	}
 	return nil
 }
@@ -0,0 +1,185 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/samber/lo"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/internal/appconfig"
+	machcmd "github.com/superfly/flyctl/internal/command/machine"
+	"github.com/superfly/flyctl/internal/machine"
+	"github.com/superfly/flyctl/internal/prometheus"
+	"golang.org/x/exp/slices"
+)
+
+// applyAutoscaling launches or destroys machines so that every region of the
+// process groups with an autoscaling rule runs between the minimum and the
+// maximum number of machines of the rule, or the number of machines bringing
+// their CPU usage close to the target of the rule when it sets one. Groups
+// without machines in any region get their minimum in the primary region.
+func (md *machineDeployment) applyAutoscaling(ctx context.Context) error {
+	if len(md.appConfig.Autoscaling) == 0 {
+		return nil
+	}
+
+	if md.strategy == "bluegreen" {
+		fmt.Fprintf(md.io.Out, "%s Autoscaling bounds aren't enforced by bluegreen deployments, they will be on the next deployment\n", md.colorize.Yellow("NOTE:"))
+		return nil
+	}
+
+	cpu := md.autoscalingCPUUsage(ctx)
+
+	for _, group := range md.processNames() {
+		rule, err := md.appConfig.AutoscalingFor(group)
+		if err != nil {
+			return err
+		}
+		if rule == nil {
+			continue
+		}
+
+		groupConfig, err := md.appConfig.Flatten(group)
+		if err != nil {
+			return err
+		}
+
+		machines := lo.Filter(md.machineSet.GetMachines(), func(lm machine.LeasableMachine, _ int) bool {
+			return lm.Machine().ProcessGroup() == group && !lm.Machine().IsCordoned()
+		})
+		byRegion := lo.GroupBy(machines, func(lm machine.LeasableMachine) string { return lm.Machine().Region })
+		if len(byRegion) == 0 && md.appConfig.PrimaryRegion != "" {
+			byRegion[md.appConfig.PrimaryRegion] = nil
+		}
+
+		regions := lo.Keys(byRegion)
+		slices.Sort(regions)
+
+		for _, region := range regions {
+			count := len(byRegion[region])
+			low, high := rule.MinMachines, rule.MaxMachines
+			lowReason, highReason := "to reach its autoscaling minimum", "to stay within its autoscaling maximum"
+
+			if rule.TargetCPUPercent > 0 && cpu != nil {
+				regionMachines := lo.Map(byRegion[region], func(lm machine.LeasableMachine, _ int) *api.Machine { return lm.Machine() })
+				if usage, ok := regionCPUPercent(regionMachines, cpu); ok {
+					low = cpuTargetCount(rule, count, usage)
+					high = low
+					lowReason = fmt.Sprintf("to bring its CPU usage of %.0f%% down to its %d%% target", usage, rule.TargetCPUPercent)
+					highReason = fmt.Sprintf("to bring its CPU usage of %.0f%% up to its %d%% target", usage, rule.TargetCPUPercent)
+				}
+			}
+
+			switch {
+			case count < low:
+				if len(groupConfig.Mounts) > 0 {
+					fmt.Fprintf(md.io.Out, "%s Group %s runs %d machine(s) in %s, below the %d its autoscaling rule requires; groups with volumes must be scaled with 'fly scale count'\n",
+						md.colorize.Yellow("WARN"), md.colorize.Bold(group), count, region, low)
+					continue
+				}
+
+				missing := low - count
+				fmt.Fprintf(md.io.Out, "Launching %d machine(s) in group %s in %s %s\n", missing, md.colorize.Bold(group), region, lowReason)
+				for i := 0; i < missing; i++ {
+					if _, err := md.spawnMachineInGroup(ctx, group, region, i, missing, nil); err != nil {
+						return err
+					}
+				}
+
+			case count > high:
+				// prefer destroying machines which aren't serving traffic
+				extra := byRegion[region]
+				slices.SortStableFunc(extra, func(a, b machine.LeasableMachine) bool {
+					return a.Machine().State == "stopped" && b.Machine().State != "stopped"
+				})
+				extra = extra[:count-high]
+
+				fmt.Fprintf(md.io.Out, "Destroying %d machine(s) in group %s in %s %s\n", len(extra), md.colorize.Bold(group), region, highReason)
+				if err := md.machineSet.RemoveMachines(ctx, extra); err != nil {
+					return err
+				}
+				for _, lm := range extra {
+					if err := machcmd.Destroy(ctx, md.app, lm.Machine(), true); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// autoscalingCPUWindow is how far back the CPU usage sizing regions is
+// averaged over, as a PromQL duration.
+const autoscalingCPUWindow = "10m"
+
+// autoscalingCPUUsage returns the number of CPUs each machine of the app kept
+// busy on average lately, when an autoscaling rule of the app targets a CPU
+// usage. Deployments go on without sizing regions by CPU when the metrics
+// can't be queried.
+func (md *machineDeployment) autoscalingCPUUsage(ctx context.Context) map[string]float64 {
+	if !lo.SomeBy(md.appConfig.Autoscaling, func(a appconfig.Autoscaling) bool { return a.TargetCPUPercent > 0 }) {
+		return nil
+	}
+	if md.app.Organization == nil {
+		return nil
+	}
+
+	busy, err := prometheus.New(ctx, md.app.Organization.Slug).ValuesBy(ctx, fmt.Sprintf(
+		`avg_over_time((sum by (instance) (rate(fly_instance_cpu{app=%q,mode!="idle"}[1m])) / 100)[%s:1m])`,
+		md.app.Name, autoscalingCPUWindow), "instance")
+	if err != nil {
+		fmt.Fprintf(md.io.ErrOut, "%s Failed querying CPU usage, regions are only kept within their autoscaling bounds: %v\n", md.colorize.Yellow("WARN"), err)
+		return nil
+	}
+
+	return busy
+}
+
+// regionCPUPercent returns the average percentage of their CPUs the machines
+// kept busy, from the busy CPUs of each machine, and whether any of them
+// reported usage.
+func regionCPUPercent(machines []*api.Machine, busy map[string]float64) (float64, bool) {
+	var (
+		total    float64
+		reported int
+	)
+
+	for _, m := range machines {
+		cpus, ok := busy[m.ID]
+		if !ok || m.Config == nil || m.Config.Guest == nil || m.Config.Guest.CPUs == 0 {
+			continue
+		}
+		total += 100 * cpus / float64(m.Config.Guest.CPUs)
+		reported++
+	}
+
+	if reported == 0 {
+		return 0, false
+	}
+
+	return total / float64(reported), true
+}
+
+// cpuTargetCount returns the number of machines a region running count of them
+// at the given CPU usage needs for their usage to get close to the target of
+// the rule, within its bounds. Regions running machines keep at least one.
+func cpuTargetCount(rule *appconfig.Autoscaling, count int, usage float64) int {
+	n := int(math.Ceil(float64(count) * usage / float64(rule.TargetCPUPercent)))
+
+	low := rule.MinMachines
+	if low < 1 && count > 0 {
+		low = 1
+	}
+
+	switch {
+	case n < low:
+		return low
+	case n > rule.MaxMachines:
+		return rule.MaxMachines
+	default:
+		return n
+	}
+}
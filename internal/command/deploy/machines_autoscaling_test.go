@@ -0,0 +1,51 @@
+package deploy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/internal/appconfig"
+)
+
+func TestRegionCPUPercent(t *testing.T) {
+	machines := []*api.Machine{
+		{ID: "m1", Config: &api.MachineConfig{Guest: &api.MachineGuest{CPUs: 2}}},
+		{ID: "m2", Config: &api.MachineConfig{Guest: &api.MachineGuest{CPUs: 1}}},
+		{ID: "m3", Config: &api.MachineConfig{Guest: &api.MachineGuest{CPUs: 1}}},
+	}
+
+	// m3 reported no usage, so it's left out of the average
+	usage, ok := regionCPUPercent(machines, map[string]float64{"m1": 1, "m2": 0.9})
+	assert.True(t, ok)
+	assert.InDelta(t, 70, usage, 0.001)
+
+	_, ok = regionCPUPercent(machines, map[string]float64{"other": 1})
+	assert.False(t, ok)
+}
+
+func TestCPUTargetCount(t *testing.T) {
+	rule := &appconfig.Autoscaling{MinMachines: 0, MaxMachines: 6, TargetCPUPercent: 50}
+
+	cases := []struct {
+		count int
+		usage float64
+		want  int
+	}{
+		{count: 2, usage: 50, want: 2},
+		{count: 2, usage: 90, want: 4},
+		{count: 4, usage: 20, want: 2},
+		// regions running machines keep at least one
+		{count: 3, usage: 0, want: 1},
+		// the maximum of the rule caps the count
+		{count: 4, usage: 100, want: 6},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.want, cpuTargetCount(rule, c.count, c.usage), "%d machines at %.0f%%", c.count, c.usage)
+	}
+
+	rule.MinMachines = 3
+	assert.Equal(t, 3, cpuTargetCount(rule, 4, 10))
+}
@@ -0,0 +1,241 @@
+package deploy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/machine"
+	"github.com/superfly/flyctl/internal/watch"
+	"github.com/superfly/flyctl/iostreams"
+	"github.com/superfly/flyctl/terminal"
+)
+
+// metadataKeyBlueGreen marks machines launched as the green set of a blue-green
+// deployment, so that an interrupted deployment can be aborted later on.
+const metadataKeyBlueGreen = "fly_bluegreen"
+
+// deployBlueGreen launches a green machine for every existing (blue) machine,
+// waits for all of them to be healthy and only then destroys the blue ones.
+// Traffic is routed to the green machines as soon as they pass their health
+// checks, so destroying the blue set completes the cutover. If any green
+// machine fails, the green set is destroyed and the blue set is left untouched.
+func (md *machineDeployment) deployBlueGreen(ctx context.Context, updateEntries []*machineUpdateEntry) error {
+	for _, e := range updateEntries {
+		if len(e.launchInput.Config.Mounts) > 0 {
+			return fmt.Errorf("machine %s has a volume attached; the bluegreen strategy doesn't support machines with volumes", e.leasableMachine.FormattedMachineId())
+		}
+	}
+
+	// standbys must be launched after the machines they stand by for, so
+	// they can point to their green counterparts
+	entries := append(
+		lo.Filter(updateEntries, func(e *machineUpdateEntry, _ int) bool { return len(e.launchInput.Config.Standbys) == 0 }),
+		lo.Filter(updateEntries, func(e *machineUpdateEntry, _ int) bool { return len(e.launchInput.Config.Standbys) > 0 })...,
+	)
+
+	var (
+		timeline = watch.TimelineFromContext(ctx)
+		greenIDs = map[string]string{}
+		green    []machine.LeasableMachine
+	)
+
+	fmt.Fprintf(md.io.ErrOut, "  Creating green machines\n")
+	endGreen := timeline.Begin("Launch green machines")
+	for i, e := range entries {
+		lm, err := md.launchGreen(ctx, e, greenIDs, formatIndex(i, len(entries)))
+		if lm != nil {
+			green = append(green, lm)
+			greenIDs[e.leasableMachine.Machine().ID] = lm.Machine().ID
+		}
+		if err != nil {
+			return md.abortGreen(ctx, green, err)
+		}
+	}
+	endGreen()
+
+	blue := lo.Map(updateEntries, func(e *machineUpdateEntry, _ int) machine.LeasableMachine { return e.leasableMachine })
+
+	// the lock must outlive the blue machines to keep other deployments out
+	if err := md.moveDeployLock(ctx, blue, green); err != nil {
+		return md.abortGreen(ctx, green, err)
+	}
+
+	fmt.Fprintf(md.io.ErrOut, "  Green machines are %s, destroying blue machines\n", md.colorize.Green("healthy"))
+	defer timeline.Begin("Destroy blue machines")()
+
+	for i, lm := range blue {
+		if err := lm.Destroy(ctx, true); err != nil {
+			return fmt.Errorf("failed to destroy blue machine %s: %w", lm.FormattedMachineId(), err)
+		}
+		fmt.Fprintf(md.io.ErrOut, "  %s Destroyed blue machine %s\n", formatIndex(i, len(blue)), md.colorize.Bold(lm.FormattedMachineId()))
+	}
+
+	// the cutover is complete, there's nothing left to abort and only the
+	// lock holder keeps the lock
+	for _, lm := range green {
+		keys := []string{metadataKeyBlueGreen}
+		if lm != md.lockHolder && md.lock != "" {
+			keys = append(keys, api.MachineConfigMetadataKeyFlyDeployLock)
+		}
+		for _, key := range keys {
+			if err := lm.DeleteMetadata(ctx, key); err != nil {
+				terminal.Warnf("failed to clear %s from machine %s: %v\n", key, lm.FormattedMachineId(), err)
+				continue
+			}
+			delete(lm.Machine().Config.Metadata, key)
+		}
+	}
+
+	return md.machineSet.RemoveMachines(ctx, blue)
+}
+
+// launchGreen launches the green counterpart of the entry's machine and waits
+// for it to be healthy. The launched machine is returned even on error so that
+// it can be cleaned up.
+func (md *machineDeployment) launchGreen(ctx context.Context, e *machineUpdateEntry, greenIDs map[string]string, indexStr string) (machine.LeasableMachine, error) {
+	input := *e.launchInput
+	input.ID = ""
+	input.Config = machine.CloneConfig(e.launchInput.Config)
+	input.Config.Metadata = lo.Assign(input.Config.Metadata, map[string]string{
+		metadataKeyBlueGreen: "green",
+	})
+	// green machines may sort before the machine holding the lock, so they
+	// all carry it until the cutover settles which one keeps it
+	if md.lock != "" {
+		input.Config.Metadata[api.MachineConfigMetadataKeyFlyDeployLock] = md.lock
+	} else {
+		delete(input.Config.Metadata, api.MachineConfigMetadataKeyFlyDeployLock)
+	}
+	input.Config.Standbys = lo.Map(input.Config.Standbys, func(id string, _ int) string {
+		return lo.ValueOr(greenIDs, id, id)
+	})
+	input.LeaseTTL = int(md.waitTimeout.Seconds())
+
+	raw, err := md.flapsClient.Launch(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to launch green machine for %s: %w", e.leasableMachine.FormattedMachineId(), err)
+	}
+
+	lm := machine.NewLeasableMachine(md.flapsClient, md.io, raw)
+	defer lm.ReleaseLease(ctx)
+	fmt.Fprintf(md.io.ErrOut, "  %s Created green machine %s for %s\n", indexStr, md.colorize.Bold(lm.FormattedMachineId()), e.leasableMachine.Machine().ID)
+
+	if len(input.Config.Standbys) > 0 {
+		return lm, nil
+	}
+
+	if err := lm.WaitForState(ctx, api.MachineStateStarted, md.waitTimeout, indexStr); err != nil {
+		return lm, err
+	}
+
+	if !md.skipHealthChecks {
+		if err := md.waitForHealthchecks(ctx, lm, indexStr); err != nil {
+			return lm, err
+		}
+	}
+
+	return lm, nil
+}
+
+// abortGreen destroys the green machines launched so far and returns an error
+// wrapping the reason why.
+func (md *machineDeployment) abortGreen(ctx context.Context, green []machine.LeasableMachine, reason error) error {
+	fmt.Fprintf(md.io.ErrOut, "  Blue-green deployment %s: %v\n", md.colorize.Red("failed"), reason)
+	fmt.Fprintf(md.io.ErrOut, "  Destroying green machines, blue machines are left untouched\n")
+
+	// still clean up when the deployment was interrupted
+	if errors.Is(ctx.Err(), context.Canceled) {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+	}
+
+	var errs []error
+	for _, lm := range green {
+		if err := lm.Destroy(ctx, true); err != nil {
+			errs = append(errs, fmt.Errorf("failed to destroy green machine %s: %w", lm.FormattedMachineId(), err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("blue-green deployment failed: %w; cleaning up green machines also failed: %v", reason, errors.Join(errs...))
+	}
+
+	return fmt.Errorf("blue-green deployment failed, green machines were destroyed: %w", reason)
+}
+
+// AbortBlueGreen destroys the green machines of a blue-green deployment which
+// was interrupted before its blue machines were destroyed. The green machines
+// are the ones of the latest release, which must all have been launched by a
+// blue-green deployment; there's nothing to abort when every machine runs that
+// release, or when some were updated in place to it.
+func AbortBlueGreen(ctx context.Context) error {
+	var (
+		io          = iostreams.FromContext(ctx)
+		flapsClient = flaps.FromContext(ctx)
+	)
+
+	machines, err := flapsClient.ListActive(ctx)
+	if err != nil {
+		return fmt.Errorf("could not list machines: %w", err)
+	}
+
+	green := blueGreenInProgress(machines)
+	if len(green) == 0 {
+		fmt.Fprintln(io.Out, "No blue-green deployment in progress")
+		return nil
+	}
+
+	fmt.Fprintf(io.Out, "Aborting blue-green deployment, destroying %d green machine(s)\n", len(green))
+	for _, m := range green {
+		if err := flapsClient.Destroy(ctx, api.RemoveMachineInput{ID: m.ID, Kill: true}, ""); err != nil {
+			return fmt.Errorf("failed to destroy green machine %s: %w", m.ID, err)
+		}
+		fmt.Fprintf(io.Out, "  Destroyed green machine %s\n", m.ID)
+	}
+
+	return nil
+}
+
+// blueGreenInProgress returns the green machines of an interrupted blue-green
+// deployment, if any.
+func blueGreenInProgress(machines []*api.Machine) []*api.Machine {
+	releaseVersion := func(m *api.Machine) int {
+		if m.Config == nil {
+			return 0
+		}
+		v, _ := strconv.Atoi(m.Config.Metadata[api.MachineConfigMetadataKeyFlyReleaseVersion])
+		return v
+	}
+	isGreen := func(m *api.Machine) bool {
+		return m.Config != nil && m.Config.Metadata[metadataKeyBlueGreen] != ""
+	}
+
+	if len(machines) == 0 {
+		return nil
+	}
+	latest := releaseVersion(lo.MaxBy(machines, func(a, b *api.Machine) bool { return releaseVersion(a) > releaseVersion(b) }))
+
+	var green []*api.Machine
+	for _, m := range machines {
+		switch {
+		case releaseVersion(m) != latest:
+		case !isGreen(m):
+			// updated in place by a later deployment
+			return nil
+		default:
+			green = append(green, m)
+		}
+	}
+
+	if len(green) == len(machines) {
+		return nil
+	}
+	return green
+}
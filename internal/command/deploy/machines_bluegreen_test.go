@@ -0,0 +1,90 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/helpers"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func blueGreenMachine(id, version string, green bool) *api.Machine {
+	metadata := map[string]string{api.MachineConfigMetadataKeyFlyReleaseVersion: version}
+	if green {
+		metadata[metadataKeyBlueGreen] = "green"
+	}
+	return &api.Machine{ID: id, State: api.MachineStateStarted, Config: &api.MachineConfig{Metadata: metadata}}
+}
+
+func TestBlueGreenInProgress(t *testing.T) {
+	var (
+		blue1  = blueGreenMachine("blue1", "6", false)
+		blue2  = blueGreenMachine("blue2", "6", true) // launched by an earlier blue-green deployment
+		green1 = blueGreenMachine("green1", "7", true)
+		green2 = blueGreenMachine("green2", "7", true)
+		moved  = blueGreenMachine("moved", "7", false)
+	)
+
+	assert.Nil(t, blueGreenInProgress(nil))
+	assert.Equal(t, []*api.Machine{green1, green2}, blueGreenInProgress([]*api.Machine{blue1, green1, blue2, green2}))
+
+	// the blue machines are gone already
+	assert.Nil(t, blueGreenInProgress([]*api.Machine{green1, green2}))
+	// the latest release was deployed in place, at least partly
+	assert.Nil(t, blueGreenInProgress([]*api.Machine{blue2, green1, moved}))
+	assert.Nil(t, blueGreenInProgress([]*api.Machine{blue1, blue2}))
+}
+
+func newAbortTestContext(t *testing.T, fake *fakeFlaps) (context.Context, *bytes.Buffer) {
+	md, _ := newLockTestDeployment(t, fake)
+
+	streams, _, stdout, _ := iostreams.Test()
+	ctx := iostreams.NewContext(context.Background(), streams)
+
+	return flaps.NewContext(ctx, md.flapsClient), stdout
+}
+
+func TestAbortBlueGreen(t *testing.T) {
+	fake := &fakeFlaps{machines: []*api.Machine{
+		blueGreenMachine("blue1", "6", false),
+		blueGreenMachine("green1", "7", true),
+	}}
+	ctx, stdout := newAbortTestContext(t, fake)
+
+	require.NoError(t, AbortBlueGreen(ctx))
+	assert.Equal(t, []string{"DELETE green1 nonce="}, fake.requests)
+	assert.Contains(t, stdout.String(), "Destroyed green machine green1")
+}
+
+func TestAbortBlueGreenAfterRollingDeploy(t *testing.T) {
+	md, err := stabMachineDeployment(&appconfig.Config{AppName: "my-cool-app"})
+	require.NoError(t, err)
+	md.releaseVersion = 7
+
+	// a blue-green deployment of release 6 launched both machines, then a
+	// rolling deployment of release 7 updated the first one before failing
+	updated := blueGreenMachine("m1", "6", true)
+	pending := blueGreenMachine("m2", "6", true)
+
+	li, err := md.launchInputForUpdate(updated)
+	require.NoError(t, err)
+	assert.NotContains(t, li.Config.Metadata, metadataKeyBlueGreen)
+	assert.Equal(t, "7", li.Config.Metadata[api.MachineConfigMetadataKeyFlyReleaseVersion])
+
+	fake := &fakeFlaps{machines: []*api.Machine{
+		{ID: "m1", State: api.MachineStateStarted, Config: helpers.Clone(li.Config)},
+		pending,
+	}}
+	ctx, stdout := newAbortTestContext(t, fake)
+
+	require.NoError(t, AbortBlueGreen(ctx))
+	assert.Empty(t, fake.requests)
+	assert.Contains(t, stdout.String(), "No blue-green deployment in progress")
+}
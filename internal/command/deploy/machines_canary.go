@@ -0,0 +1,168 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/internal/machine"
+	"github.com/superfly/flyctl/internal/prometheus"
+)
+
+// splitCanaries splits off the machines to update first when deploying with
+// the canary strategy. Only machines updated in place and serving traffic are
+// eligible as canaries since others can't be rolled back or judged.
+func (md *machineDeployment) splitCanaries(entries []*machineUpdateEntry) (canaries, rest []*machineUpdateEntry, err error) {
+	size := md.canarySize
+	if size == "" {
+		size = "1"
+	}
+
+	n, err := machine.ParseMaxUnavailable(size, len(entries))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid canary size '%s', expected a number of machines (e.g. 2) or a percentage of them (e.g. 10%%): %w", size, err)
+	}
+
+	for _, e := range entries {
+		eligible := e.launchInput.ID == e.leasableMachine.Machine().ID && len(e.launchInput.Config.Standbys) == 0
+		if eligible && len(canaries) < n {
+			canaries = append(canaries, e)
+		} else {
+			rest = append(rest, e)
+		}
+	}
+
+	return canaries, rest, nil
+}
+
+// deployCanaries updates the canary machines, then waits for the bake time and
+// checks they're still healthy. Canaries are rolled back when they fail to
+// update or don't stay healthy.
+func (md *machineDeployment) deployCanaries(ctx context.Context, canaries []*machineUpdateEntry, total int) error {
+	if len(canaries) == 0 {
+		fmt.Fprintf(md.io.ErrOut, "  No machine can serve as a canary, updating all machines\n")
+		return nil
+	}
+
+	previous := previousConfigs(canaries)
+
+	fmt.Fprintf(md.io.ErrOut, "  Updating %d canary machine(s)\n", len(canaries))
+	if err := md.updateEntries(ctx, canaries, 0, total); err != nil {
+		return md.rollbackCanaries(ctx, canaries, previous, err)
+	}
+
+	if md.skipHealthChecks || md.canaryBakeTime <= 0 {
+		return nil
+	}
+
+	bakeStarted := time.Now()
+	fmt.Fprintf(md.io.ErrOut, "  Waiting %s for canary machine(s) to bake\n", md.canaryBakeTime)
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(md.canaryBakeTime):
+	}
+
+	for _, e := range canaries {
+		if err := md.checkCanary(ctx, e.leasableMachine.Machine().ID, bakeStarted); err != nil {
+			return md.rollbackCanaries(ctx, canaries, previous, err)
+		}
+	}
+
+	if md.canaryMaxErrorRate > 0 {
+		if err := md.checkCanaryErrorRates(ctx, canaries, time.Since(bakeStarted)); err != nil {
+			return md.rollbackCanaries(ctx, canaries, previous, err)
+		}
+	}
+
+	fmt.Fprintf(md.io.ErrOut, "  Canary machine(s) %s, updating the remaining machines\n", md.colorize.Green("healthy"))
+	return nil
+}
+
+// checkCanary returns an error when the canary isn't started, has failing
+// checks, or exited since the given time.
+func (md *machineDeployment) checkCanary(ctx context.Context, machineID string, since time.Time) error {
+	m, err := md.flapsClient.Get(ctx, machineID)
+	if err != nil {
+		return fmt.Errorf("could not get canary machine %s: %w", machineID, err)
+	}
+
+	if m.State != api.MachineStateStarted {
+		return fmt.Errorf("canary machine %s is %s", machineID, m.State)
+	}
+
+	for _, check := range m.Checks {
+		if check.Status != "passing" {
+			return fmt.Errorf("check %s of canary machine %s is %s", check.Name, machineID, check.Status)
+		}
+	}
+
+	for _, event := range m.Events {
+		if event.Type == "exit" && time.UnixMilli(event.Timestamp).After(since) {
+			return fmt.Errorf("canary machine %s exited while baking", machineID)
+		}
+	}
+
+	return nil
+}
+
+// checkCanaryErrorRates returns an error when a canary served a higher
+// percentage of 5xx responses than allowed over the bake time. Canaries which
+// served no responses pass, since there's nothing to judge them on.
+func (md *machineDeployment) checkCanaryErrorRates(ctx context.Context, canaries []*machineUpdateEntry, window time.Duration) error {
+	if md.app.Organization == nil {
+		return nil
+	}
+
+	rates, err := prometheus.New(ctx, md.app.Organization.Slug).ValuesBy(ctx, canaryErrorRateQuery(md.app.Name, window), "instance")
+	if err != nil {
+		return fmt.Errorf("failed querying the error rates of canary machines: %w", err)
+	}
+
+	ids := make([]string, len(canaries))
+	for i, e := range canaries {
+		ids[i] = e.leasableMachine.Machine().ID
+	}
+
+	return checkErrorRates(rates, ids, md.canaryMaxErrorRate)
+}
+
+// canaryErrorRateQuery returns the query of the percentage of 5xx responses
+// of each machine of the app over the window.
+func canaryErrorRateQuery(appName string, window time.Duration) string {
+	selector := fmt.Sprintf(`app=%q`, appName)
+	rng := fmt.Sprintf("%ds", int(window.Seconds()))
+
+	return fmt.Sprintf(
+		`100 * sum by (instance) (increase(fly_app_http_responses_count{%s,status=~"5.."}[%s])) / sum by (instance) (increase(fly_app_http_responses_count{%s}[%s]))`,
+		selector, rng, selector, rng,
+	)
+}
+
+// checkErrorRates returns an error naming the first of the machines whose
+// error rate exceeds max.
+func checkErrorRates(rates map[string]float64, machineIDs []string, max int) error {
+	for _, id := range machineIDs {
+		if rate, ok := rates[id]; ok && rate > float64(max) {
+			return fmt.Errorf("canary machine %s served %.1f%% of 5xx responses, above the %d%% allowed", id, rate, max)
+		}
+	}
+
+	return nil
+}
+
+// rollbackCanaries updates the canaries back to their previous config and
+// returns an error wrapping the reason why.
+func (md *machineDeployment) rollbackCanaries(ctx context.Context, canaries []*machineUpdateEntry, previous []*api.MachineConfig, reason error) error {
+	fmt.Fprintf(md.io.ErrOut, "  Canary deployment %s: %v\n", md.colorize.Red("failed"), reason)
+	fmt.Fprintf(md.io.ErrOut, "  Rolling canary machine(s) back\n")
+
+	rollback := rollbackEntries(canaries, previous)
+	if err := md.updateEntries(ctx, rollback, 0, len(rollback)); err != nil {
+		return fmt.Errorf("canary deployment failed: %w; rolling back canaries also failed: %v", reason, err)
+	}
+
+	return fmt.Errorf("canary deployment failed, canaries were rolled back: %w", reason)
+}
@@ -0,0 +1,31 @@
+package deploy
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanaryErrorRateQuery(t *testing.T) {
+	assert.Equal(t,
+		`100 * sum by (instance) (increase(fly_app_http_responses_count{app="my-app",status=~"5.."}[90s])) / sum by (instance) (increase(fly_app_http_responses_count{app="my-app"}[90s]))`,
+		canaryErrorRateQuery("my-app", 90*time.Second),
+	)
+}
+
+func TestCheckErrorRates(t *testing.T) {
+	rates := map[string]float64{"m1": 0.5, "m2": 12.5, "other": 90}
+
+	assert.NoError(t, checkErrorRates(rates, []string{"m1"}, 1))
+	// canaries without responses aren't judged
+	assert.NoError(t, checkErrorRates(rates, []string{"m3"}, 1))
+	// machines other than the canaries are ignored
+	assert.NoError(t, checkErrorRates(rates, []string{"m1", "m3"}, 1))
+	// NaN rates, of machines without responses, never exceed the limit
+	assert.NoError(t, checkErrorRates(map[string]float64{"m1": math.NaN()}, []string{"m1"}, 1))
+
+	err := checkErrorRates(rates, []string{"m1", "m2"}, 10)
+	assert.EqualError(t, err, "canary machine m2 served 12.5% of 5xx responses, above the 10% allowed")
+}
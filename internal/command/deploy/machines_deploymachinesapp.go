@@ -12,6 +12,8 @@ import (
 	"github.com/superfly/flyctl/flaps"
 	machcmd "github.com/superfly/flyctl/internal/command/machine"
 	"github.com/superfly/flyctl/internal/machine"
+	"github.com/superfly/flyctl/internal/progress"
+	"github.com/superfly/flyctl/internal/watch"
 	"github.com/superfly/flyctl/terminal"
 	"golang.org/x/exp/maps"
 	"golang.org/x/exp/slices"
@@ -26,11 +28,24 @@ type ProcessGroupsDiff struct {
 func (md *machineDeployment) DeployMachinesApp(ctx context.Context) error {
 	ctx = flaps.NewContext(ctx, md.flapsClient)
 
+	if err := md.checkPolicy(ctx); err != nil {
+		return err
+	}
+
+	if md.dryRun {
+		return md.renderDryRun(ctx)
+	}
+
+	unlock, err := md.acquireDeployLock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	if err := md.updateReleaseInBackend(ctx, "running"); err != nil {
 		return fmt.Errorf("failed to set release status to 'running': %w", err)
 	}
 
-	var err error
 	if md.restartOnly {
 		err = md.restartMachinesApp(ctx)
 	} else {
@@ -58,6 +73,10 @@ func (md *machineDeployment) DeployMachinesApp(ctx context.Context) error {
 			terminal.Warnf("failed to set final release status after deployment failure: %v\n", updateErr)
 		}
 	}
+
+	if err == nil && md.watchDuration > 0 {
+		err = md.watchDeployment(ctx)
+	}
 	return err
 }
 
@@ -81,6 +100,8 @@ func (md *machineDeployment) restartMachinesApp(ctx context.Context) error {
 //   - Remove spare machines from removed groups
 //   - Launch new machines on new groups
 //   - Update existing machines
+//   - Run smoke checks, rolling machines back when they fail
+//   - Launch or destroy machines to apply the autoscaling bounds
 func (md *machineDeployment) deployMachinesApp(ctx context.Context) error {
 	if err := md.runReleaseCommand(ctx); err != nil {
 		return fmt.Errorf("release command failed - aborting deployment. %w", err)
@@ -113,7 +134,7 @@ func (md *machineDeployment) deployMachinesApp(ctx context.Context) error {
 
 		for idx, name := range maps.Keys(processGroupMachineDiff.groupsNeedingMachines) {
 			fmt.Fprintf(md.io.Out, "No machines in group %s, launching one new machine\n", md.colorize.Bold(name))
-			machineID, err := md.spawnMachineInGroup(ctx, name, idx, total, nil)
+			machineID, err := md.spawnMachineInGroup(ctx, name, "", idx, total, nil)
 			if err != nil {
 				return err
 			}
@@ -144,13 +165,13 @@ func (md *machineDeployment) deployMachinesApp(ctx context.Context) error {
 				continue
 			case len(services) > 0:
 				fmt.Fprintf(md.io.Out, "Creating a second machine to increase service availability\n")
-				if _, err := md.spawnMachineInGroup(ctx, name, idx, total, nil); err != nil {
+				if _, err := md.spawnMachineInGroup(ctx, name, "", idx, total, nil); err != nil {
 					return err
 				}
 			default:
 				fmt.Fprintf(md.io.Out, "Creating a standby machine for %s\n", md.colorize.Bold(machineID))
 				standbyFor := []string{machineID}
-				if _, err := md.spawnMachineInGroup(ctx, name, idx, total, standbyFor); err != nil {
+				if _, err := md.spawnMachineInGroup(ctx, name, "", idx, total, standbyFor); err != nil {
 					return err
 				}
 			}
@@ -177,7 +198,16 @@ func (md *machineDeployment) deployMachinesApp(ctx context.Context) error {
 		machineUpdateEntries = append(machineUpdateEntries, &machineUpdateEntry{leasableMachine: lm, launchInput: li})
 	}
 
-	return md.updateExistingMachines(ctx, machineUpdateEntries)
+	previous := previousConfigs(machineUpdateEntries)
+	if err := md.updateExistingMachines(ctx, machineUpdateEntries); err != nil {
+		return err
+	}
+
+	if err := md.runSmokeChecks(ctx); err != nil {
+		return md.rollbackSmokeChecks(ctx, machineUpdateEntries, previous, err)
+	}
+
+	return md.applyAutoscaling(ctx)
 }
 
 type machineUpdateEntry struct {
@@ -185,6 +215,36 @@ type machineUpdateEntry struct {
 	launchInput     *api.LaunchMachineInput
 }
 
+// previousConfigs returns the current configs of the machines of the entries,
+// to roll them back to.
+func previousConfigs(entries []*machineUpdateEntry) []*api.MachineConfig {
+	previous := make([]*api.MachineConfig, len(entries))
+	for i, e := range entries {
+		previous[i] = machine.CloneConfig(e.leasableMachine.Machine().Config)
+	}
+	return previous
+}
+
+// rollbackEntries returns entries updating the machines back to their
+// previous config. Only machines updated in place can be rolled back.
+func rollbackEntries(entries []*machineUpdateEntry, previous []*api.MachineConfig) []*machineUpdateEntry {
+	var rollback []*machineUpdateEntry
+	for i, e := range entries {
+		m := e.leasableMachine.Machine()
+		if e.launchInput.ID != m.ID || m.IsCordoned() {
+			continue
+		}
+
+		input := *e.launchInput
+		input.Config = previous[i]
+		rollback = append(rollback, &machineUpdateEntry{
+			leasableMachine: e.leasableMachine,
+			launchInput:     &input,
+		})
+	}
+	return rollback
+}
+
 func formatIndex(n, total int) string {
 	pad := 0
 	for i := total; i != 0; i /= 10 {
@@ -194,12 +254,71 @@ func formatIndex(n, total int) string {
 }
 
 func (md *machineDeployment) updateExistingMachines(ctx context.Context, updateEntries []*machineUpdateEntry) error {
-	// FIXME: handle deploy strategy: rolling, immediate, canary, bluegreen
+	// cordoned machines are being debugged, leave them alone
+	updateEntries = lo.Filter(updateEntries, func(e *machineUpdateEntry, _ int) bool {
+		if e.leasableMachine.Machine().IsCordoned() {
+			fmt.Fprintf(md.io.Out, "Skipping cordoned machine %s\n", md.colorize.Bold(e.leasableMachine.FormattedMachineId()))
+			return false
+		}
+		return true
+	})
+
 	fmt.Fprintf(md.io.Out, "Updating existing machines in '%s' with %s strategy\n", md.colorize.Bold(md.app.Name), md.strategy)
+
+	remaining, offset := updateEntries, 0
+	switch md.strategy {
+	case "bluegreen":
+		if err := md.deployBlueGreen(ctx, updateEntries); err != nil {
+			return err
+		}
+		remaining = nil
+	case "canary":
+		canaries, rest, err := md.splitCanaries(updateEntries)
+		if err != nil {
+			return err
+		}
+		if err := md.deployCanaries(ctx, canaries, len(updateEntries)); err != nil {
+			return err
+		}
+		remaining, offset = rest, len(canaries)
+	}
+
+	if err := md.updateEntries(ctx, remaining, offset, len(updateEntries)); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(md.io.ErrOut, "  Finished deploying\n")
+	return nil
+}
+
+// updateEntries updates the machines of the entries one after the other. The
+// entries are numbered from offset out of total in the output.
+func (md *machineDeployment) updateEntries(ctx context.Context, updateEntries []*machineUpdateEntry, offset, total int) (err error) {
+	timeline := watch.TimelineFromContext(ctx)
+
+	// the update of the machine being updated fails when an error is returned
+	var event progress.Event
+	defer func() {
+		if err != nil && event.Stage != "" {
+			progress.Fail(ctx, event, err)
+		}
+	}()
+
 	for i, e := range updateEntries {
 		lm := e.leasableMachine
 		launchInput := e.launchInput
-		indexStr := formatIndex(i, len(updateEntries))
+		indexStr := formatIndex(offset+i, total)
+
+		event = progress.Event{Stage: progress.StageUpdateMachine, MachineID: lm.Machine().ID, Percent: progress.Percent(offset+i, total)}
+		progress.Publish(ctx, event.WithStatus(progress.StatusStarted))
+
+		updateStarted := time.Now()
+		recordUpdate := func() {
+			timeline.Add("Update machine "+lm.Machine().ID, time.Since(updateStarted))
+
+			event.Percent = progress.Percent(offset+i+1, total)
+			progress.Publish(ctx, event.WithStatus(progress.StatusCompleted))
+		}
 
 		if launchInput.ID != lm.Machine().ID {
 			// If IDs don't match, destroy the original machine and launch a new one
@@ -222,10 +341,12 @@ func (md *machineDeployment) updateExistingMachines(ctx context.Context, updateE
 					return err
 				}
 				fmt.Fprintf(md.io.ErrOut, "Continuing after error: %s\n", err)
+				progress.Fail(ctx, event, err)
 				continue
 			}
 
 			lm = machine.NewLeasableMachine(md.flapsClient, md.io, newMachineRaw)
+			event.Message = "replaced by " + newMachineRaw.ID
 			fmt.Fprintf(md.io.ErrOut, "  %s Created machine %s\n", indexStr, md.colorize.Bold(lm.FormattedMachineId()))
 			defer lm.ReleaseLease(ctx)
 
@@ -247,21 +368,30 @@ func (md *machineDeployment) updateExistingMachines(ctx context.Context, updateE
 				md.colorize.Bold(lm.FormattedMachineId()),
 				md.colorize.Green("success"),
 			)
+			recordUpdate()
 			continue
 		}
 
 		if md.strategy == "immediate" {
+			recordUpdate()
 			continue
 		}
 
 		if err := lm.WaitForState(ctx, api.MachineStateStarted, md.waitTimeout, indexStr); err != nil {
 			return err
 		}
+		recordUpdate()
+		event = progress.Event{}
 
 		if !md.skipHealthChecks {
-			if err := lm.WaitForHealthchecksToPass(ctx, md.waitTimeout, indexStr); err != nil {
+			endChecks := timeline.Begin("Health checks " + lm.Machine().ID)
+			endProgress := progress.Begin(ctx, progress.Event{Stage: progress.StageHealthChecks, MachineID: lm.Machine().ID})
+			err := md.waitForHealthchecks(ctx, lm, indexStr)
+			endProgress(err)
+			if err != nil {
 				return err
 			}
+			endChecks()
 			// FIXME: combine this wait with the wait for start as one update line (or two per in noninteractive case)
 			md.logClearLinesAbove(1)
 			fmt.Fprintf(md.io.ErrOut, "  %s Machine %s update finished: %s\n",
@@ -272,15 +402,46 @@ func (md *machineDeployment) updateExistingMachines(ctx context.Context, updateE
 		}
 	}
 
-	fmt.Fprintf(md.io.ErrOut, "  Finished deploying\n")
 	return nil
 }
 
-func (md *machineDeployment) spawnMachineInGroup(ctx context.Context, groupName string, i, total int, standbyFor []string) (string, error) {
+// waitForHealthchecks waits for the checks of the machine to pass, once the
+// check grace period of the app config is over.
+func (md *machineDeployment) waitForHealthchecks(ctx context.Context, lm machine.LeasableMachine, indexStr string) error {
+	if md.checkGracePeriod > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(md.checkGracePeriod):
+		}
+	}
+
+	return lm.WaitForHealthchecksToPass(ctx, md.checkTimeout, indexStr)
+}
+
+// spawnMachineInGroup launches a machine in the process group, in the given
+// region or the default one when empty.
+func (md *machineDeployment) spawnMachineInGroup(ctx context.Context, groupName, region string, i, total int, standbyFor []string) (id string, err error) {
+	defer watch.TimelineFromContext(ctx).Begin("Launch machine in group " + groupName)()
+
+	event := progress.Event{Stage: progress.StageLaunchMachine, Message: "group " + groupName, Percent: progress.Percent(i, total)}
+	progress.Publish(ctx, event.WithStatus(progress.StatusStarted))
+	defer func() {
+		event.MachineID, event.Percent = id, progress.Percent(i+1, total)
+		if err != nil {
+			progress.Fail(ctx, event, err)
+			return
+		}
+		progress.Publish(ctx, event.WithStatus(progress.StatusCompleted))
+	}()
+
 	launchInput, err := md.launchInputForLaunch(groupName, md.machineGuest, standbyFor)
 	if err != nil {
 		return "", fmt.Errorf("error creating machine configuration: %w", err)
 	}
+	if region != "" {
+		launchInput.Region = region
+	}
 
 	// Acquire a lease on the new machine to ensure external factors can't stop or update it
 	// while we wait for its state and/or health checks
@@ -317,7 +478,7 @@ func (md *machineDeployment) spawnMachineInGroup(ctx context.Context, groupName
 
 	// And wait (or not) for successful health checks
 	if !md.skipHealthChecks {
-		if err := lm.WaitForHealthchecksToPass(ctx, md.waitTimeout, indexStr); err != nil {
+		if err := md.waitForHealthchecks(ctx, lm, indexStr); err != nil {
 			return "", err
 		}
 
@@ -337,7 +498,7 @@ func (md *machineDeployment) resolveProcessGroupChanges() ProcessGroupsDiff {
 		groupsNeedingMachines: map[string]bool{},
 	}
 
-	groupsInConfig := md.appConfig.ProcessNames()
+	groupsInConfig := md.processNames()
 	groupHasMachine := map[string]bool{}
 
 	for _, leasableMachine := range md.machineSet.GetMachines() {
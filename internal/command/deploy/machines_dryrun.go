@@ -0,0 +1,102 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/progress"
+	"github.com/superfly/flyctl/internal/render"
+)
+
+// dryRunEntry is a change the deployment would apply to a machine.
+type dryRunEntry struct {
+	// Action is one of "launch", "update", "replace" or "destroy".
+	Action    string                  `json:"action"`
+	MachineID string                  `json:"machine_id,omitempty"`
+	Group     string                  `json:"process_group"`
+	Input     *api.LaunchMachineInput `json:"input,omitempty"`
+}
+
+// renderDryRun prints the launch input the deployment would send to flaps for
+// every machine, without acquiring leases or changing anything. Release
+// metadata is left empty since no release is created.
+func (md *machineDeployment) renderDryRun(ctx context.Context) error {
+	var entries []dryRunEntry
+
+	if md.restartOnly {
+		for _, lm := range md.machineSet.GetMachines() {
+			m := lm.Machine()
+			entries = append(entries, dryRunEntry{Action: "update", MachineID: m.ID, Group: m.ProcessGroup(), Input: md.launchInputForRestart(m)})
+		}
+		return md.writeDryRun(ctx, entries)
+	}
+
+	diff := md.resolveProcessGroupChanges()
+	for _, lm := range diff.machinesToRemove {
+		m := lm.Machine()
+		entries = append(entries, dryRunEntry{Action: "destroy", MachineID: m.ID, Group: m.ProcessGroup()})
+	}
+
+	for _, group := range md.processNames() {
+		if !diff.groupsNeedingMachines[group] {
+			continue
+		}
+		input, err := md.launchInputForLaunch(group, md.machineGuest, nil)
+		if err != nil {
+			return fmt.Errorf("error creating machine configuration: %w", err)
+		}
+		entries = append(entries, dryRunEntry{Action: "launch", Group: group, Input: input})
+	}
+
+	for _, lm := range md.machineSet.GetMachines() {
+		m := lm.Machine()
+		if _, removed := diff.groupsToRemove[m.ProcessGroup()]; removed {
+			continue
+		}
+
+		input, err := md.launchInputForUpdate(m)
+		if err != nil {
+			return fmt.Errorf("failed to update machine configuration for %s: %w", lm.FormattedMachineId(), err)
+		}
+
+		action := "update"
+		if input.ID != m.ID {
+			action = "replace"
+		}
+		entries = append(entries, dryRunEntry{Action: action, MachineID: m.ID, Group: m.ProcessGroup(), Input: input})
+	}
+
+	return md.writeDryRun(ctx, entries)
+}
+
+func (md *machineDeployment) writeDryRun(ctx context.Context, entries []dryRunEntry) error {
+	if bus := progress.FromContext(ctx); bus.Active() {
+		bus.Publish(progress.Event{Stage: progress.StageDeploy, Status: progress.StatusCompleted, Message: "dry run", Data: entries})
+		return nil
+	}
+	if config.FromContext(ctx).JSONOutput {
+		return render.Output(ctx, md.io.Out, entries)
+	}
+
+	fmt.Fprintf(md.io.Out, "Dry run: %d machine change(s) would be applied to '%s'\n", len(entries), md.colorize.Bold(md.app.Name))
+	for _, e := range entries {
+		fmt.Fprintln(md.io.Out)
+
+		target := e.MachineID
+		if target == "" {
+			target = "new machine"
+		}
+		fmt.Fprintf(md.io.Out, "%s %s [%s]\n", md.colorize.Bold(e.Action), target, e.Group)
+
+		if e.Input == nil {
+			continue
+		}
+		if err := render.Output(ctx, md.io.Out, e.Input.Config); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
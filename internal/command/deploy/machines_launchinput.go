@@ -123,6 +123,11 @@ func (md *machineDeployment) launchInputForUpdate(origMachineRaw *api.Machine) (
 		mID = "" // Forces machine replacement
 	}
 
+	if mID == "" {
+		// The deploy lock stays with the machine being replaced
+		delete(mConfig.Metadata, api.MachineConfigMetadataKeyFlyDeployLock)
+	}
+
 	return &api.LaunchMachineInput{
 		ID:         mID,
 		AppID:      md.app.Name,
@@ -138,10 +143,22 @@ func (md *machineDeployment) setMachineReleaseData(mConfig *api.MachineConfig) {
 		api.MachineConfigMetadataKeyFlyReleaseId:      md.releaseId,
 		api.MachineConfigMetadataKeyFlyReleaseVersion: strconv.Itoa(md.releaseVersion),
 	})
+	// machines of this release aren't part of an earlier blue-green deployment
+	delete(mConfig.Metadata, metadataKeyBlueGreen)
 
 	// These defaults should come from appConfig.ToMachineConfig() and set on launch;
 	// leave them here for the moment becase very old machines may not have them
 	// and we want to set in case of simple app restarts
+	// Keep track of the build context the image was built from so that
+	// unchanged contexts can skip the build on the next deploy.
+	if !md.restartOnly {
+		if md.contextDigest != "" {
+			mConfig.Metadata[api.MachineConfigMetadataKeyFlyContextDigest] = md.contextDigest
+		} else {
+			delete(mConfig.Metadata, api.MachineConfigMetadataKeyFlyContextDigest)
+		}
+	}
+
 	if _, ok := mConfig.Metadata[api.MachineConfigMetadataKeyFlyPlatformVersion]; !ok {
 		mConfig.Metadata[api.MachineConfigMetadataKeyFlyPlatformVersion] = api.MachineFlyPlatformVersion2
 	}
@@ -0,0 +1,227 @@
+package deploy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/internal/machine"
+	"github.com/superfly/flyctl/terminal"
+)
+
+// deployLock describes the deployment holding the deploy lock of an app. It's
+// recorded in the metadata of the lock machine for as long as the deployment
+// is in progress.
+type deployLock struct {
+	Owner    string    `json:"owner"`
+	Hostname string    `json:"hostname,omitempty"`
+	Since    time.Time `json:"since"`
+}
+
+// unknownLockOwner stands for the owner of locks which didn't record one, e.g.
+// when the user of the deployment couldn't be determined.
+const unknownLockOwner = "an unknown user"
+
+func (l deployLock) String() string {
+	owner := l.Owner
+	if owner == "" {
+		owner = unknownLockOwner
+	}
+
+	s := fmt.Sprintf("%s since %s", owner, l.Since.Local().Format(time.RFC1123))
+	if l.Hostname != "" {
+		s += fmt.Sprintf(" (from %s)", l.Hostname)
+	}
+	return s
+}
+
+// lockMachine returns the machine holding the deploy lock of the app: the one
+// with the lowest ID, so that concurrent deployments agree on it even when
+// they only deploy some process groups.
+func (md *machineDeployment) lockMachine() machine.LeasableMachine {
+	machines := md.appMachines
+	if len(machines) == 0 {
+		return nil
+	}
+
+	return lo.MinBy(machines, func(a, b machine.LeasableMachine) bool {
+		return a.Machine().ID < b.Machine().ID
+	})
+}
+
+// acquireDeployLock prevents concurrent deployments of the app by leasing its
+// lock machine and recording the owner of the deployment in its metadata. The
+// returned func releases the lock. Apps without machines have nothing to race
+// on and aren't locked.
+func (md *machineDeployment) acquireDeployLock(ctx context.Context) (func(), error) {
+	if md.forceUnlock {
+		if err := md.breakDeployLock(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	lm := md.lockMachine()
+	if lm == nil {
+		return func() {}, nil
+	}
+
+	if lock := currentDeployLock(lm); lock != nil {
+		return nil, deployInProgressError(*lock)
+	}
+
+	if err := lm.AcquireLease(ctx, md.leaseTimeout); err != nil {
+		return nil, md.lockedError(ctx, lm, err)
+	}
+	lm.StartBackgroundLeaseRefresh(ctx, md.leaseTimeout, md.leaseDelayBetween)
+	md.lockHolder = lm
+
+	lock := deployLock{Since: time.Now().UTC()}
+	lock.Hostname, _ = os.Hostname()
+	if user, err := md.apiClient.GetCurrentUser(ctx); err == nil {
+		lock.Owner = user.Email
+	}
+	if raw, err := json.Marshal(lock); err == nil {
+		if err := lm.SetMetadata(ctx, api.MachineConfigMetadataKeyFlyDeployLock, string(raw)); err != nil {
+			terminal.Warnf("failed to record the deploy lock on machine %s: %v\n", lm.FormattedMachineId(), err)
+		} else if m := lm.Machine(); m.Config != nil {
+			// keep it when the deployment updates the machine
+			m.Config.Metadata = lo.Assign(m.Config.Metadata, map[string]string{api.MachineConfigMetadataKeyFlyDeployLock: string(raw)})
+			md.lock = string(raw)
+		}
+	}
+
+	return func() { md.releaseDeployLock(ctx) }, nil
+}
+
+// releaseDeployLock removes the deploy lock from the machine holding it,
+// unless the deployment destroyed that machine.
+func (md *machineDeployment) releaseDeployLock(ctx context.Context) {
+	lm := md.lockHolder
+	if lm == nil || lm.IsDestroyed() {
+		return
+	}
+
+	// still release the lock when the deployment was interrupted
+	if errors.Is(ctx.Err(), context.Canceled) {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+	}
+
+	// the deployment may have released the lease along with the others
+	if !lm.HasLease() {
+		if err := lm.AcquireLease(ctx, md.leaseTimeout); err != nil {
+			terminal.Warnf("failed to release the deploy lock on machine %s: %v\n", lm.FormattedMachineId(), err)
+			return
+		}
+	}
+	if err := lm.DeleteMetadata(ctx, api.MachineConfigMetadataKeyFlyDeployLock); err != nil {
+		terminal.Warnf("failed to release the deploy lock on machine %s: %v\n", lm.FormattedMachineId(), err)
+	}
+	lm.ReleaseLease(ctx)
+}
+
+// moveDeployLock hands the deploy lock over to the machine concurrent
+// deployments will look for once the replaced machines are destroyed: the one
+// with the lowest ID among the machines left. It's a no-op unless the machine
+// holding the lock is replaced.
+func (md *machineDeployment) moveDeployLock(ctx context.Context, replaced, replacements []machine.LeasableMachine) error {
+	if md.lockHolder == nil {
+		return nil
+	}
+
+	replacedIDs := lo.SliceToMap(replaced, func(lm machine.LeasableMachine) (string, bool) { return lm.Machine().ID, true })
+	if !replacedIDs[md.lockHolder.Machine().ID] {
+		return nil
+	}
+
+	left := append(lo.Filter(md.appMachines, func(lm machine.LeasableMachine, _ int) bool {
+		return !replacedIDs[lm.Machine().ID]
+	}), replacements...)
+	if len(left) == 0 {
+		return nil
+	}
+	next := lo.MinBy(left, func(a, b machine.LeasableMachine) bool { return a.Machine().ID < b.Machine().ID })
+
+	if err := next.AcquireLease(ctx, md.leaseTimeout); err != nil {
+		return fmt.Errorf("failed to move the deploy lock to machine %s: %w", next.FormattedMachineId(), err)
+	}
+	next.StartBackgroundLeaseRefresh(ctx, md.leaseTimeout, md.leaseDelayBetween)
+
+	if md.lock != "" {
+		if err := next.SetMetadata(ctx, api.MachineConfigMetadataKeyFlyDeployLock, md.lock); err != nil {
+			next.ReleaseLease(ctx)
+			return fmt.Errorf("failed to move the deploy lock to machine %s: %w", next.FormattedMachineId(), err)
+		}
+		if m := next.Machine(); m.Config != nil {
+			m.Config.Metadata = lo.Assign(m.Config.Metadata, map[string]string{api.MachineConfigMetadataKeyFlyDeployLock: md.lock})
+		}
+	}
+
+	md.lockHolder = next
+	return nil
+}
+
+// currentDeployLock returns the deploy lock recorded on the machine, if any.
+func currentDeployLock(lm machine.LeasableMachine) *deployLock {
+	m := lm.Machine()
+	if m.Config == nil || m.Config.Metadata[api.MachineConfigMetadataKeyFlyDeployLock] == "" {
+		return nil
+	}
+
+	var lock deployLock
+	if err := json.Unmarshal([]byte(m.Config.Metadata[api.MachineConfigMetadataKeyFlyDeployLock]), &lock); err != nil {
+		lock.Owner = unknownLockOwner
+	}
+	return &lock
+}
+
+// lockedError describes who holds the lease of the lock machine when it
+// couldn't be acquired.
+func (md *machineDeployment) lockedError(ctx context.Context, lm machine.LeasableMachine, leaseErr error) error {
+	lease, err := md.flapsClient.FindLease(ctx, lm.Machine().ID)
+	if err != nil || lease.Data == nil {
+		return fmt.Errorf("failed to acquire the deploy lock on machine %s: %w", lm.FormattedMachineId(), leaseErr)
+	}
+
+	return deployInProgressError(deployLock{
+		Owner: lease.Data.Owner,
+		// leases don't record when they were taken, only when they expire
+		Since: time.Unix(lease.Data.ExpiresAt, 0).Add(-md.leaseTimeout),
+	})
+}
+
+func deployInProgressError(lock deployLock) error {
+	return fmt.Errorf("deploy in progress by %s; wait for it to finish or pass --force-unlock if it was interrupted", lock)
+}
+
+// breakDeployLock releases the leases of every machine of the app and removes
+// the deploy lock left by a deployment which didn't finish.
+func (md *machineDeployment) breakDeployLock(ctx context.Context) error {
+	for _, lm := range md.appMachines {
+		m := lm.Machine()
+
+		lease, err := md.flapsClient.FindLease(ctx, m.ID)
+		if err == nil && lease.Data != nil && lease.Data.Nonce != "" {
+			if err := md.flapsClient.ReleaseLease(ctx, m.ID, lease.Data.Nonce); err != nil {
+				return fmt.Errorf("failed to release lease of machine %s: %w", lm.FormattedMachineId(), err)
+			}
+			fmt.Fprintf(md.io.ErrOut, "Released lease of machine %s held by %s\n", lm.FormattedMachineId(), lease.Data.Owner)
+		}
+
+		if currentDeployLock(lm) != nil {
+			if err := md.flapsClient.DeleteMetadata(ctx, m.ID, api.MachineConfigMetadataKeyFlyDeployLock, ""); err != nil {
+				return fmt.Errorf("failed to remove deploy lock of machine %s: %w", lm.FormattedMachineId(), err)
+			}
+			delete(m.Config.Metadata, api.MachineConfigMetadataKeyFlyDeployLock)
+			fmt.Fprintf(md.io.ErrOut, "Removed deploy lock of machine %s\n", lm.FormattedMachineId())
+		}
+	}
+
+	return nil
+}
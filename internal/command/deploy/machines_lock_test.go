@@ -0,0 +1,250 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/logger"
+	"github.com/superfly/flyctl/internal/machine"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+// fakeFlaps serves the machines of an app named app along with their lease
+// and metadata endpoints, recording the mutating requests it gets.
+type fakeFlaps struct {
+	mu       sync.Mutex
+	machines []*api.Machine
+	leases   map[string]*api.MachineLeaseData
+	requests []string
+}
+
+func (f *fakeFlaps) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/v1/apps/app/machines/")
+	id, rest, _ := strings.Cut(path, "/")
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/v1/apps/app/machines":
+		_ = json.NewEncoder(w).Encode(f.machines)
+	case r.Method == http.MethodGet && rest == "lease":
+		lease, ok := f.leases[id]
+		if !ok {
+			http.Error(w, `{"error":"lease not found"}`, http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(api.MachineLease{Status: "success", Data: lease})
+	case r.Method == http.MethodPost && rest == "lease":
+		// refreshes aren't recorded, they're down to timing
+		if r.Header.Get(flaps.NonceHeader) == "" {
+			f.requests = append(f.requests, fmt.Sprintf("POST %s", path))
+		}
+		_ = json.NewEncoder(w).Encode(api.MachineLease{Status: "success", Data: &api.MachineLeaseData{Nonce: "nonce-" + id}})
+	case r.Method == http.MethodPost && strings.HasPrefix(rest, "metadata/"):
+		f.requests = append(f.requests, fmt.Sprintf("POST %s nonce=%s", path, r.Header.Get(flaps.NonceHeader)))
+		w.WriteHeader(http.StatusOK)
+	case r.Method == http.MethodDelete:
+		f.requests = append(f.requests, fmt.Sprintf("DELETE %s nonce=%s", path, r.Header.Get(flaps.NonceHeader)))
+		w.WriteHeader(http.StatusOK)
+	default:
+		http.Error(w, "unexpected request", http.StatusBadRequest)
+	}
+}
+
+func newLockTestDeployment(t *testing.T, fake *fakeFlaps, machines ...*api.Machine) (*machineDeployment, *bytes.Buffer) {
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	t.Setenv("FLY_FLAPS_BASE_URL", server.URL)
+	t.Setenv("FLY_FLAPS_RETRIES", "0")
+
+	ctx := logger.NewContext(context.Background(), logger.FromEnv(io.Discard))
+	flapsClient, err := flaps.NewWithOptions(ctx, &flaps.NewClientOpts{AppName: "app"})
+	require.NoError(t, err)
+
+	streams, _, _, stderr := iostreams.Test()
+	md := &machineDeployment{flapsClient: flapsClient, io: streams, leaseTimeout: time.Minute, leaseDelayBetween: time.Minute}
+	for _, m := range machines {
+		md.appMachines = append(md.appMachines, machine.NewLeasableMachine(flapsClient, streams, m))
+	}
+
+	return md, stderr
+}
+
+func lockedMachine(id, lock string) *api.Machine {
+	return &api.Machine{ID: id, Config: &api.MachineConfig{Metadata: map[string]string{
+		api.MachineConfigMetadataKeyFlyDeployLock: lock,
+	}}}
+}
+
+func TestLockMachine(t *testing.T) {
+	md := &machineDeployment{}
+	assert.Nil(t, md.lockMachine())
+
+	io, _, _, _ := iostreams.Test()
+	for _, id := range []string{"m3", "m1", "m2"} {
+		md.appMachines = append(md.appMachines, machine.NewLeasableMachine(nil, io, &api.Machine{ID: id}))
+	}
+	assert.Equal(t, "m1", md.lockMachine().Machine().ID)
+}
+
+func TestCurrentDeployLock(t *testing.T) {
+	io, _, _, _ := iostreams.Test()
+	lockOf := func(m *api.Machine) *deployLock {
+		return currentDeployLock(machine.NewLeasableMachine(nil, io, m))
+	}
+
+	assert.Nil(t, lockOf(&api.Machine{ID: "m1"}))
+	assert.Nil(t, lockOf(&api.Machine{ID: "m1", Config: &api.MachineConfig{}}))
+
+	since := time.Date(2023, 5, 4, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t,
+		&deployLock{Owner: "jane@example.com", Hostname: "laptop", Since: since},
+		lockOf(lockedMachine("m1", `{"owner":"jane@example.com","hostname":"laptop","since":"2023-05-04T12:00:00Z"}`)),
+	)
+
+	// locks which can't be read still lock
+	assert.Equal(t, &deployLock{Owner: unknownLockOwner}, lockOf(lockedMachine("m1", `{not json`)))
+}
+
+func TestDeployInProgressError(t *testing.T) {
+	since := time.Date(2023, 5, 4, 12, 0, 0, 0, time.UTC)
+	formatted := since.Local().Format(time.RFC1123)
+
+	err := deployInProgressError(deployLock{Owner: "jane@example.com", Hostname: "laptop", Since: since})
+	assert.EqualError(t, err, "deploy in progress by jane@example.com since "+formatted+" (from laptop); wait for it to finish or pass --force-unlock if it was interrupted")
+
+	// locks recorded without an owner, e.g. when the user couldn't be looked up
+	err = deployInProgressError(deployLock{Since: since})
+	assert.EqualError(t, err, "deploy in progress by an unknown user since "+formatted+"; wait for it to finish or pass --force-unlock if it was interrupted")
+}
+
+func TestBreakDeployLock(t *testing.T) {
+	fake := &fakeFlaps{leases: map[string]*api.MachineLeaseData{
+		"m1": {Nonce: "nonce1", Owner: "jane@example.com"},
+	}}
+
+	m1 := lockedMachine("m1", `{"owner":"jane@example.com"}`)
+	m2 := &api.Machine{ID: "m2", Config: &api.MachineConfig{}}
+	md, stderr := newLockTestDeployment(t, fake, m1, m2)
+
+	require.NoError(t, md.breakDeployLock(context.Background()))
+
+	assert.Equal(t, []string{
+		"DELETE m1/lease nonce=nonce1",
+		"DELETE m1/metadata/fly_deploy_lock nonce=",
+	}, fake.requests)
+	assert.NotContains(t, m1.Config.Metadata, api.MachineConfigMetadataKeyFlyDeployLock)
+	assert.Nil(t, currentDeployLock(md.appMachines[0]))
+
+	assert.Contains(t, stderr.String(), "Released lease of machine m1 held by jane@example.com")
+	assert.Contains(t, stderr.String(), "Removed deploy lock of machine m1")
+	assert.NotContains(t, stderr.String(), "m2")
+}
+
+func TestLockedError(t *testing.T) {
+	expires := time.Date(2023, 5, 4, 12, 1, 0, 0, time.UTC)
+	fake := &fakeFlaps{leases: map[string]*api.MachineLeaseData{
+		"m1": {Nonce: "nonce1", Owner: "jane@example.com", ExpiresAt: expires.Unix()},
+		"m2": {Nonce: "nonce2", ExpiresAt: expires.Unix()},
+	}}
+	machines := []*api.Machine{
+		{ID: "m1", Config: &api.MachineConfig{}},
+		{ID: "m2", Config: &api.MachineConfig{}},
+		{ID: "m3", Config: &api.MachineConfig{}},
+	}
+	md, _ := newLockTestDeployment(t, fake, machines...)
+	ctx := context.Background()
+	leaseErr := fmt.Errorf("lease currently held")
+
+	// leases are taken the lease timeout before they expire
+	since := expires.Add(-md.leaseTimeout).Local().Format(time.RFC1123)
+
+	err := md.lockedError(ctx, md.appMachines[0], leaseErr)
+	assert.EqualError(t, err, "deploy in progress by jane@example.com since "+since+"; wait for it to finish or pass --force-unlock if it was interrupted")
+
+	err = md.lockedError(ctx, md.appMachines[1], leaseErr)
+	assert.EqualError(t, err, "deploy in progress by an unknown user since "+since+"; wait for it to finish or pass --force-unlock if it was interrupted")
+
+	err = md.lockedError(ctx, md.appMachines[2], leaseErr)
+	assert.ErrorIs(t, err, leaseErr)
+}
+
+func TestReleaseDeployLock(t *testing.T) {
+	fake := &fakeFlaps{}
+	md, stderr := newLockTestDeployment(t, fake, lockedMachine("m1", `{"owner":"jane@example.com"}`))
+	ctx := context.Background()
+
+	md.lockHolder = md.appMachines[0]
+	md.releaseDeployLock(ctx)
+
+	assert.Equal(t, []string{
+		"POST m1/lease",
+		"DELETE m1/metadata/fly_deploy_lock nonce=nonce-m1",
+		"DELETE m1/lease nonce=nonce-m1",
+	}, fake.requests)
+	assert.Empty(t, stderr.String())
+}
+
+func TestReleaseDeployLockDestroyed(t *testing.T) {
+	fake := &fakeFlaps{}
+	md, stderr := newLockTestDeployment(t, fake, lockedMachine("m1", `{"owner":"jane@example.com"}`))
+	ctx := context.Background()
+
+	md.lockHolder = md.appMachines[0]
+	require.NoError(t, md.lockHolder.Destroy(ctx, true))
+	fake.requests = nil
+
+	// the lock went away with the machine
+	md.releaseDeployLock(ctx)
+	assert.Empty(t, fake.requests)
+	assert.Empty(t, stderr.String())
+}
+
+func TestMoveDeployLock(t *testing.T) {
+	fake := &fakeFlaps{}
+	lock := `{"owner":"jane@example.com"}`
+	md, _ := newLockTestDeployment(t, fake,
+		lockedMachine("m1", lock),
+		&api.Machine{ID: "m2", Config: &api.MachineConfig{}},
+		&api.Machine{ID: "m5", Config: &api.MachineConfig{}},
+	)
+	ctx := context.Background()
+	md.lockHolder = md.appMachines[0]
+	md.lock = lock
+
+	io, _, _, _ := iostreams.Test()
+	green := []machine.LeasableMachine{
+		machine.NewLeasableMachine(md.flapsClient, io, &api.Machine{ID: "m4", Config: &api.MachineConfig{}}),
+		machine.NewLeasableMachine(md.flapsClient, io, &api.Machine{ID: "m3", Config: &api.MachineConfig{}}),
+	}
+
+	// the lock stays put while its machine isn't replaced
+	require.NoError(t, md.moveDeployLock(ctx, md.appMachines[1:2], green))
+	assert.Empty(t, fake.requests)
+	assert.Equal(t, "m1", md.lockHolder.Machine().ID)
+
+	// and moves to the lowest ID left once it is
+	require.NoError(t, md.moveDeployLock(ctx, md.appMachines[:2], green))
+	assert.Equal(t, []string{
+		"POST m3/lease",
+		"POST m3/metadata/fly_deploy_lock nonce=nonce-m3",
+	}, fake.requests)
+	assert.Equal(t, "m3", md.lockHolder.Machine().ID)
+	assert.Equal(t, lock, md.lockHolder.Machine().Config.Metadata[api.MachineConfigMetadataKeyFlyDeployLock])
+}
@@ -0,0 +1,46 @@
+package deploy
+
+import (
+	"context"
+
+	"github.com/superfly/flyctl/internal/policy"
+)
+
+// checkPolicy returns an error if the machines the deployment leaves the app
+// with violate the policy of its organization.
+func (md *machineDeployment) checkPolicy(ctx context.Context) error {
+	p, err := policy.Load(ctx, md.app.Organization.Slug)
+	if err != nil || p == nil {
+		return err
+	}
+
+	var violations []string
+	counts := map[string]int{}
+
+	for _, lm := range md.machineSet.GetMachines() {
+		m := lm.Machine()
+		if m.Config != nil {
+			violations = append(violations, p.MachineViolations(m.Region, m.Config.Guest)...)
+		}
+		counts[m.ProcessGroup()]++
+	}
+
+	for name := range md.resolveProcessGroupChanges().groupsNeedingMachines {
+		violations = append(violations, p.MachineViolations(md.appConfig.PrimaryRegion, md.machineGuest)...)
+
+		counts[name] = 1
+		if groupConfig, err := md.appConfig.Flatten(name); err == nil && md.increasedAvailability && len(groupConfig.Mounts) == 0 && len(groupConfig.AllServices()) > 0 {
+			counts[name] = 2
+		}
+	}
+
+	serving := policy.ServingGroups(md.appConfig)
+	for name := range counts {
+		if !serving[name] {
+			delete(counts, name)
+		}
+	}
+	violations = append(violations, p.CountViolations(md.app.Name, counts)...)
+
+	return p.Error(violations)
+}
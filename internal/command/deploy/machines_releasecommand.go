@@ -13,18 +13,24 @@ import (
 	"github.com/superfly/flyctl/flaps"
 	"github.com/superfly/flyctl/helpers"
 	"github.com/superfly/flyctl/internal/machine"
+	"github.com/superfly/flyctl/internal/progress"
+	"github.com/superfly/flyctl/internal/watch"
 )
 
-func (md *machineDeployment) runReleaseCommand(ctx context.Context) error {
+func (md *machineDeployment) runReleaseCommand(ctx context.Context) (err error) {
 	if md.appConfig.Deploy == nil || md.appConfig.Deploy.ReleaseCommand == "" {
 		return nil
 	}
+	defer watch.TimelineFromContext(ctx).Begin("Release command")()
+
+	endProgress := progress.Begin(ctx, progress.Event{Stage: progress.StageReleaseCommand})
+	defer func() { endProgress(err) }()
 
 	fmt.Fprintf(md.io.ErrOut, "Running %s release_command: %s\n",
 		md.colorize.Bold(md.app.Name),
 		md.appConfig.Deploy.ReleaseCommand,
 	)
-	err := md.createOrUpdateReleaseCmdMachine(ctx)
+	err = md.createOrUpdateReleaseCmdMachine(ctx)
 	if err != nil {
 		return fmt.Errorf("error running release_command machine: %w", err)
 	}
@@ -0,0 +1,219 @@
+package deploy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/machine"
+	"github.com/superfly/flyctl/internal/progress"
+	"github.com/superfly/flyctl/internal/watch"
+)
+
+const (
+	defaultSmokeCheckTimeout  = 30 * time.Second
+	smokeCheckRetryInterval   = 2 * time.Second
+	smokeCheckRequestTimeout  = 10 * time.Second
+	maxSmokeCheckBodyReadSize = 1 << 20
+)
+
+// runSmokeChecks runs the smoke checks of the deploy section one after the
+// other and returns the first failure.
+func (md *machineDeployment) runSmokeChecks(ctx context.Context) (err error) {
+	if md.appConfig.Deploy == nil || len(md.appConfig.Deploy.SmokeChecks) == 0 {
+		return nil
+	}
+	defer watch.TimelineFromContext(ctx).Begin("Smoke checks")()
+
+	endProgress := progress.Begin(ctx, progress.Event{Stage: progress.StageSmokeChecks})
+	defer func() { endProgress(err) }()
+
+	fmt.Fprintf(md.io.ErrOut, "Running %d smoke check(s)\n", len(md.appConfig.Deploy.SmokeChecks))
+
+	for _, check := range md.appConfig.Deploy.SmokeChecks {
+		var err error
+		if check.Command != "" {
+			err = md.runSmokeCheckCommand(ctx, check)
+		} else {
+			err = md.runSmokeCheckRequest(ctx, check)
+		}
+		if err != nil {
+			fmt.Fprintf(md.io.ErrOut, "  %s %s\n", md.colorize.Red("✘"), check.Label())
+			return fmt.Errorf("smoke check %s failed: %w", check.Label(), err)
+		}
+		fmt.Fprintf(md.io.ErrOut, "  %s %s\n", md.colorize.Green("✓"), check.Label())
+	}
+
+	return nil
+}
+
+func smokeCheckTimeout(check appconfig.SmokeCheck) time.Duration {
+	if check.Timeout != nil && check.Timeout.Duration > 0 {
+		return check.Timeout.Duration
+	}
+	return defaultSmokeCheckTimeout
+}
+
+// smokeCheckURL returns the URL the check requests: its path on the default
+// hostname of the app, or the path itself when it's a full URL.
+func smokeCheckURL(appName, path string) (string, error) {
+	if u, err := url.Parse(path); err == nil && u.Scheme != "" && u.Host != "" {
+		return path, nil
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	u, err := url.Parse("https://" + appName + ".fly.dev" + path)
+	if err != nil {
+		return "", fmt.Errorf("invalid path %s: %w", path, err)
+	}
+	return u.String(), nil
+}
+
+// checkSmokeResponse returns an error when the response doesn't have the
+// expected status or body.
+func checkSmokeResponse(check appconfig.SmokeCheck, status int, body []byte) error {
+	want := check.Status
+	if want == 0 {
+		want = http.StatusOK
+	}
+	if status != want {
+		return fmt.Errorf("got status %d, expected %d", status, want)
+	}
+	if check.BodyContains != "" && !strings.Contains(string(body), check.BodyContains) {
+		return fmt.Errorf("response body doesn't contain %q", check.BodyContains)
+	}
+	return nil
+}
+
+// runSmokeCheckRequest retries the request of the check until it gets the
+// expected response or the check times out, since the proxy may take a few
+// seconds to route to updated machines.
+func (md *machineDeployment) runSmokeCheckRequest(ctx context.Context, check appconfig.SmokeCheck) error {
+	target, err := smokeCheckURL(md.app.Name, check.Path)
+	if err != nil {
+		return err
+	}
+
+	method := check.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, smokeCheckTimeout(check))
+	defer cancel()
+
+	client := &http.Client{Timeout: smokeCheckRequestTimeout}
+
+	attempt := func() error {
+		req, err := http.NewRequestWithContext(ctx, method, target, http.NoBody)
+		if err != nil {
+			return err
+		}
+		for name, value := range check.Headers {
+			req.Header.Set(name, value)
+		}
+
+		res, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close() // skipcq: GO-S2307
+
+		body, err := io.ReadAll(io.LimitReader(res.Body, maxSmokeCheckBodyReadSize))
+		if err != nil {
+			return fmt.Errorf("failed reading response body: %w", err)
+		}
+		return checkSmokeResponse(check, res.StatusCode, body)
+	}
+
+	for {
+		err := attempt()
+		if err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s %s: %w", method, target, err)
+		case <-time.After(smokeCheckRetryInterval):
+		}
+	}
+}
+
+// runSmokeCheckCommand runs the command of the check in an ephemeral machine
+// of the new image, the way release commands run, and expects it to exit
+// with status 0.
+func (md *machineDeployment) runSmokeCheckCommand(ctx context.Context, check appconfig.SmokeCheck) error {
+	mConfig, err := md.appConfig.ToSmokeCheckMachineConfig(check.Command)
+	if err != nil {
+		return err
+	}
+	mConfig.Guest = md.inferReleaseCommandGuest()
+	mConfig.Image = md.img
+	md.setMachineReleaseData(mConfig)
+
+	m, err := md.flapsClient.Launch(ctx, api.LaunchMachineInput{
+		AppID:   md.app.Name,
+		OrgSlug: md.app.Organization.ID,
+		Config:  mConfig,
+		Region:  md.appConfig.PrimaryRegion,
+	})
+	if err != nil {
+		return fmt.Errorf("error creating a smoke check machine: %w", err)
+	}
+	fmt.Fprintf(md.io.ErrOut, "  Running %s in machine %s\n", check.Command, md.colorize.Bold(m.ID))
+
+	lm := machine.NewLeasableMachine(md.flapsClient, md.io, m)
+	timeout := smokeCheckTimeout(check)
+
+	if err := lm.WaitForState(ctx, api.MachineStateStarted, timeout, ""); err != nil {
+		var flapsErr *flaps.FlapsError
+		if !errors.As(err, &flapsErr) || flapsErr.ResponseStatusCode != http.StatusNotFound {
+			return fmt.Errorf("error waiting for smoke check machine %s to start: %w", m.ID, err)
+		}
+	} else if err := lm.WaitForState(ctx, api.MachineStateDestroyed, timeout, ""); err != nil {
+		return fmt.Errorf("error waiting for smoke check machine %s to finish running: %w", m.ID, err)
+	}
+
+	exitEvent, err := lm.WaitForEventTypeAfterType(ctx, "exit", "start", timeout)
+	if err != nil {
+		return fmt.Errorf("error finding the smoke check machine %s exit event: %w", m.ID, err)
+	}
+	exitCode, err := exitEvent.Request.GetExitCode()
+	if err != nil {
+		return fmt.Errorf("error getting smoke check machine %s exit code: %w", m.ID, err)
+	}
+	if exitCode != 0 {
+		return fmt.Errorf("command exited with status %d, run 'fly logs -i %s' for its output", exitCode, m.ID)
+	}
+
+	return nil
+}
+
+// rollbackSmokeChecks updates the machines back to their previous config after
+// smoke checks failed and returns an error wrapping the reason why.
+func (md *machineDeployment) rollbackSmokeChecks(ctx context.Context, entries []*machineUpdateEntry, previous []*api.MachineConfig, reason error) error {
+	fmt.Fprintf(md.io.ErrOut, "  Smoke checks %s: %v\n", md.colorize.Red("failed"), reason)
+
+	rollback := rollbackEntries(entries, previous)
+	if md.strategy == "bluegreen" || len(rollback) == 0 {
+		return fmt.Errorf("smoke checks failed, no machine could be rolled back: %w", reason)
+	}
+
+	fmt.Fprintf(md.io.ErrOut, "  Rolling %d machine(s) back\n", len(rollback))
+	if err := md.updateEntries(ctx, rollback, 0, len(rollback)); err != nil {
+		return fmt.Errorf("smoke checks failed: %w; rolling back machines also failed: %v", reason, err)
+	}
+
+	return fmt.Errorf("smoke checks failed, machines were rolled back: %w", reason)
+}
@@ -0,0 +1,60 @@
+package deploy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/machine"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func Test_smokeCheckURL(t *testing.T) {
+	cases := map[string]string{
+		"/":                           "https://my-app.fly.dev/",
+		"health?full=1":               "https://my-app.fly.dev/health?full=1",
+		"https://example.com/status":  "https://example.com/status",
+		"http://my-app.internal:8080": "http://my-app.internal:8080",
+	}
+	for path, want := range cases {
+		got, err := smokeCheckURL("my-app", path)
+		require.NoError(t, err, path)
+		assert.Equal(t, want, got, path)
+	}
+}
+
+func Test_checkSmokeResponse(t *testing.T) {
+	assert.NoError(t, checkSmokeResponse(appconfig.SmokeCheck{}, 200, nil))
+	assert.ErrorContains(t, checkSmokeResponse(appconfig.SmokeCheck{}, 502, nil), "got status 502, expected 200")
+	assert.NoError(t, checkSmokeResponse(appconfig.SmokeCheck{Status: 404}, 404, nil))
+
+	check := appconfig.SmokeCheck{BodyContains: "Welcome"}
+	assert.NoError(t, checkSmokeResponse(check, 200, []byte("<h1>Welcome!</h1>")))
+	assert.ErrorContains(t, checkSmokeResponse(check, 200, []byte("Oops")), `doesn't contain "Welcome"`)
+}
+
+func Test_rollbackEntries(t *testing.T) {
+	ios, _, _, _ := iostreams.Test()
+	entry := func(id, newID string, metadata map[string]string) *machineUpdateEntry {
+		m := &api.Machine{ID: id, Config: &api.MachineConfig{Image: "new", Metadata: metadata}}
+		return &machineUpdateEntry{
+			leasableMachine: machine.NewLeasableMachine(nil, ios, m),
+			launchInput:     &api.LaunchMachineInput{ID: newID, Config: &api.MachineConfig{Image: "new"}},
+		}
+	}
+
+	entries := []*machineUpdateEntry{
+		entry("m1", "m1", nil),
+		entry("m2", "", nil),
+		entry("m3", "m3", map[string]string{api.MachineConfigMetadataKeyFlyCordoned: "true"}),
+	}
+	previous := []*api.MachineConfig{{Image: "old1"}, {Image: "old2"}, {Image: "old3"}}
+
+	rollback := rollbackEntries(entries, previous)
+	require.Len(t, rollback, 1)
+	assert.Equal(t, "m1", rollback[0].launchInput.ID)
+	assert.Equal(t, "old1", rollback[0].launchInput.Config.Image)
+	assert.Equal(t, "new", entries[0].launchInput.Config.Image)
+}
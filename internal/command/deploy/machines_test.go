@@ -366,3 +366,24 @@ func Test_resolveUpdatedMachineConfig_restartOnlyProcessGroup(t *testing.T) {
 		},
 	}, md.launchInputForRestart(origMachine))
 }
+
+func Test_setOnlyProcessGroups(t *testing.T) {
+	cfg := &appconfig.Config{
+		AppName: "my-cool-app",
+		Processes: map[string]string{
+			"web":    "run-web",
+			"worker": "run-worker",
+			"cron":   "run-cron",
+		},
+	}
+	require.NoError(t, cfg.SetMachinesPlatform())
+	md, err := stabMachineDeployment(cfg)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"cron", "web", "worker"}, md.processNames())
+
+	require.NoError(t, md.setOnlyProcessGroups([]string{"web", "worker", "web"}))
+	assert.Equal(t, []string{"web", "worker"}, md.processNames())
+
+	assert.ErrorContains(t, md.setOnlyProcessGroups([]string{"batch"}), "process group 'batch' isn't defined")
+}
@@ -0,0 +1,131 @@
+package deploy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/logs"
+)
+
+const (
+	// watchPollInterval is how often machines are checked on while watching
+	// a deployment.
+	watchPollInterval = 5 * time.Second
+
+	// crashLoopExits is how many times a machine must exit while watching a
+	// deployment to be considered crash looping.
+	crashLoopExits = 3
+
+	// flappingTransitions is how many times a check must change status while
+	// watching a deployment to be considered flapping.
+	flappingTransitions = 3
+)
+
+// deployWatcher detects crash loops and flapping checks from the successive
+// states of the machines of an app.
+type deployWatcher struct {
+	since  time.Time
+	exits  map[string]map[int64]bool
+	checks map[string]string
+	flips  map[string]int
+}
+
+func newDeployWatcher(since time.Time) *deployWatcher {
+	return &deployWatcher{
+		since:  since,
+		exits:  map[string]map[int64]bool{},
+		checks: map[string]string{},
+		flips:  map[string]int{},
+	}
+}
+
+// observe records the current state of the machines and returns the problems
+// found so far.
+func (w *deployWatcher) observe(machines []*api.Machine) (problems []string) {
+	for _, m := range machines {
+		for _, e := range m.Events {
+			if e.Type != "exit" || time.UnixMilli(e.Timestamp).Before(w.since) {
+				continue
+			}
+			if w.exits[m.ID] == nil {
+				w.exits[m.ID] = map[int64]bool{}
+			}
+			w.exits[m.ID][e.Timestamp] = true
+		}
+		if n := len(w.exits[m.ID]); n >= crashLoopExits {
+			problems = append(problems, fmt.Sprintf("machine %s is crash looping, it exited %d times", m.ID, n))
+		}
+
+		for _, c := range m.Checks {
+			key := m.ID + "/" + c.Name
+			if previous, known := w.checks[key]; known && previous != c.Status {
+				w.flips[key]++
+			}
+			w.checks[key] = c.Status
+
+			if n := w.flips[key]; n >= flappingTransitions {
+				problems = append(problems, fmt.Sprintf("check %s of machine %s is flapping, it changed status %d times", c.Name, m.ID, n))
+			}
+		}
+	}
+
+	return problems
+}
+
+// watchDeployment tails the logs of the app and watches its machines for the
+// watch duration, failing as soon as a machine crash loops or a check flaps.
+func (md *machineDeployment) watchDeployment(ctx context.Context) error {
+	fmt.Fprintf(md.io.Out, "\nWatching %s for %s\n", md.colorize.Bold(md.app.Name), md.watchDuration)
+
+	ctx, cancel := context.WithTimeout(ctx, md.watchDuration)
+	defer cancel()
+
+	go md.tailLogs(ctx)
+
+	watcher := newDeployWatcher(time.Now())
+	for {
+		machines, err := md.flapsClient.ListActive(ctx)
+		switch {
+		case errors.Is(err, context.DeadlineExceeded):
+			return md.watchPassed()
+		case err != nil:
+			return fmt.Errorf("could not list machines: %w", err)
+		}
+
+		if problems := watcher.observe(machines); len(problems) > 0 {
+			return fmt.Errorf("deployment is unhealthy: %s", strings.Join(problems, "; "))
+		}
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return md.watchPassed()
+			}
+			return ctx.Err()
+		case <-time.After(watchPollInterval):
+		}
+	}
+}
+
+func (md *machineDeployment) watchPassed() error {
+	fmt.Fprintf(md.io.Out, "No crash loops or flapping checks in %s, deployment is %s\n", md.watchDuration, md.colorize.Green("healthy"))
+	return nil
+}
+
+func (md *machineDeployment) tailLogs(ctx context.Context) {
+	opts := &logs.LogOptions{AppName: md.app.Name}
+
+	stream, err := logs.NewPollingStream(md.apiClient, opts)
+	if err != nil {
+		return
+	}
+
+	for entry := range stream.Stream(ctx, opts) {
+		_ = render.LogEntry(md.io.Out, entry)
+	}
+}
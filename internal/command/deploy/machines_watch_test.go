@@ -0,0 +1,42 @@
+package deploy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/superfly/flyctl/api"
+)
+
+func TestDeployWatcher(t *testing.T) {
+	since := time.Now()
+	w := newDeployWatcher(since)
+
+	exit := func(ts time.Time) *api.MachineEvent {
+		return &api.MachineEvent{Type: "exit", Timestamp: ts.UnixMilli()}
+	}
+	machine := &api.Machine{
+		ID:     "m1",
+		Events: []*api.MachineEvent{exit(since.Add(-time.Minute)), exit(since.Add(time.Second))},
+		Checks: []*api.MachineCheckStatus{{Name: "http", Status: "passing"}},
+	}
+
+	assert.Empty(t, w.observe([]*api.Machine{machine}))
+
+	machine.Events = append(machine.Events, exit(since.Add(2*time.Second)))
+	machine.Checks[0].Status = "critical"
+	assert.Empty(t, w.observe([]*api.Machine{machine}))
+
+	machine.Events = append(machine.Events, exit(since.Add(3*time.Second)))
+	assert.Equal(t, []string{"machine m1 is crash looping, it exited 3 times"}, w.observe([]*api.Machine{machine}))
+
+	w = newDeployWatcher(since)
+	machine.Events = nil
+	for i, status := range []string{"passing", "critical", "passing"} {
+		machine.Checks[0].Status = status
+		assert.Empty(t, w.observe([]*api.Machine{machine}), i)
+	}
+	machine.Checks[0].Status = "critical"
+	assert.Equal(t, []string{"check http of machine m1 is flapping, it changed status 3 times"}, w.observe([]*api.Machine{machine}))
+}
@@ -0,0 +1,130 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+// Deprecation describes why a command is deprecated and what replaces it.
+type Deprecation struct {
+	// Replacement is the command users should run instead, e.g.
+	// "fly apps create".
+	Replacement string `json:"replacement,omitempty"`
+
+	// Message optionally explains the deprecation further.
+	Message string `json:"message,omitempty"`
+
+	// RemovedIn is the version the command is expected to be removed in.
+	RemovedIn string `json:"removed_in,omitempty"`
+}
+
+const (
+	annotationDeprecationReplacement = "flyctl_deprecation_replacement"
+	annotationDeprecationMessage     = "flyctl_deprecation_message"
+	annotationDeprecationRemovedIn   = "flyctl_deprecation_removed_in"
+)
+
+// Deprecate marks cmd as deprecated. The command keeps working and is hidden
+// from help output; every run prints a deprecation notice to stderr.
+func Deprecate(cmd *cobra.Command, d Deprecation) {
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+
+	cmd.Annotations[annotationDeprecationReplacement] = d.Replacement
+	cmd.Annotations[annotationDeprecationMessage] = d.Message
+	cmd.Annotations[annotationDeprecationRemovedIn] = d.RemovedIn
+	cmd.Hidden = true
+}
+
+// Alias returns a deprecated alias of cmd with the given usage, for commands
+// which have been renamed or moved. The alias shares the flags, arguments and
+// implementation of cmd. The replacement defaults to the path of cmd.
+func Alias(cmd *cobra.Command, usage string, d Deprecation) *cobra.Command {
+	alias := &cobra.Command{
+		Use:   usage,
+		Short: cmd.Short,
+		Long:  cmd.Long,
+		Args:  cmd.Args,
+	}
+	alias.Flags().AddFlagSet(cmd.Flags())
+	Deprecate(alias, d)
+
+	alias.RunE = func(c *cobra.Command, args []string) error {
+		// the path of cmd is only known once it's part of the command tree
+		if c.Annotations[annotationDeprecationReplacement] == "" {
+			c.Annotations[annotationDeprecationReplacement] = cmd.CommandPath()
+		}
+
+		return cmd.RunE(c, args)
+	}
+
+	return alias
+}
+
+// DeprecationOf returns the deprecation of cmd, if it's deprecated.
+func DeprecationOf(cmd *cobra.Command) (d Deprecation, deprecated bool) {
+	if _, deprecated = cmd.Annotations[annotationDeprecationReplacement]; !deprecated {
+		return
+	}
+
+	d = Deprecation{
+		Replacement: cmd.Annotations[annotationDeprecationReplacement],
+		Message:     cmd.Annotations[annotationDeprecationMessage],
+		RemovedIn:   cmd.Annotations[annotationDeprecationRemovedIn],
+	}
+
+	return
+}
+
+// noticeDeprecation prints the deprecation notice of the command ctx carries,
+// if it's deprecated.
+func noticeDeprecation(ctx context.Context) {
+	cmd := FromContext(ctx)
+
+	d, deprecated := DeprecationOf(cmd)
+	if !deprecated {
+		return
+	}
+
+	_ = writeDeprecationNotice(iostreams.FromContext(ctx).ErrOut, cmd.CommandPath(), d, config.FromContext(ctx).JSONOutput)
+}
+
+func writeDeprecationNotice(w io.Writer, command string, d Deprecation, asJSON bool) error {
+	if asJSON {
+		type notice struct {
+			Command string `json:"command"`
+			Deprecation
+		}
+
+		return render.JSON(w, map[string]notice{
+			"deprecation": {command, d},
+		})
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Warning: %q is deprecated", command)
+	if d.RemovedIn != "" {
+		fmt.Fprintf(&b, " and will be removed in %s", d.RemovedIn)
+	}
+	if d.Replacement != "" {
+		fmt.Fprintf(&b, "; use %q instead", d.Replacement)
+	}
+	b.WriteString(".")
+	if d.Message != "" {
+		fmt.Fprintf(&b, " %s", d.Message)
+	}
+	b.WriteString("\n")
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
@@ -0,0 +1,60 @@
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlias(t *testing.T) {
+	var ran bool
+
+	root := &cobra.Command{Use: "fly"}
+	apps := &cobra.Command{Use: "apps"}
+	create := &cobra.Command{
+		Use:  "create",
+		RunE: func(*cobra.Command, []string) error { ran = true; return nil },
+	}
+	create.Flags().String("name", "", "")
+	apps.AddCommand(create)
+
+	alias := Alias(create, "create", Deprecation{RemovedIn: "v0.2.0"})
+	root.AddCommand(apps, alias)
+
+	assert.True(t, alias.Hidden)
+	assert.NotNil(t, alias.Flags().Lookup("name"))
+
+	_, deprecated := DeprecationOf(create)
+	assert.False(t, deprecated)
+
+	root.SetArgs([]string{"create", "--name", "app"})
+	require.NoError(t, root.Execute())
+	assert.True(t, ran)
+
+	d, deprecated := DeprecationOf(alias)
+	require.True(t, deprecated)
+	assert.Equal(t, Deprecation{Replacement: "fly apps create", RemovedIn: "v0.2.0"}, d)
+}
+
+func TestWriteDeprecationNotice(t *testing.T) {
+	d := Deprecation{Replacement: "fly apps create", RemovedIn: "v0.2.0"}
+
+	var b bytes.Buffer
+	require.NoError(t, writeDeprecationNotice(&b, "fly create", d, false))
+	assert.Equal(t, "Warning: \"fly create\" is deprecated and will be removed in v0.2.0; use \"fly apps create\" instead.\n", b.String())
+
+	b.Reset()
+	require.NoError(t, writeDeprecationNotice(&b, "fly create", d, true))
+
+	var notice map[string]map[string]string
+	require.NoError(t, json.Unmarshal(b.Bytes(), &notice))
+	assert.Equal(t, map[string]string{
+		"command":     "fly create",
+		"replacement": "fly apps create",
+		"removed_in":  "v0.2.0",
+	}, notice["deprecation"])
+}
@@ -7,6 +7,7 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"regexp"
 	"strings"
@@ -20,7 +21,9 @@ import (
 	"github.com/superfly/flyctl/client"
 	"github.com/superfly/flyctl/internal/appconfig"
 	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
 	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/render"
 )
 
 var nameErrorRx = regexp.MustCompile(`\[.*?\]:53`)
@@ -28,13 +31,17 @@ var nameErrorRx = regexp.MustCompile(`\[.*?\]:53`)
 func New() *cobra.Command {
 	const (
 		long = `Make DNS requests against Fly.io's internal DNS server. Valid types include
-AAAA and TXT (the two types our servers answer authoritatively), AAAA-NATIVE
+AAAA, TXT and SRV (the types our servers answer authoritatively), AAAA-NATIVE
 and TXT-NATIVE, which resolve with Go's resolver (they're slower,
 but may be useful if diagnosing a DNS bug) and A and CNAME
 (if you're using the server to test recursive lookups.)
 Note that this resolves names against the server for the current organization. You can
 set the organization with -o <org-slug>; otherwise, the command uses the organization
-attached to the current app (you can pass an app in with -a <appname>).`
+attached to the current app (you can pass an app in with -a <appname>).
+
+When the private network can't be reached through the agent, the request is
+sent to a DNS-over-HTTPS endpoint instead, which only resolves public names
+unless --doh-url points to a resolver serving .internal names.`
 
 		short = "Make DNS requests against Fly.io's internal DNS server"
 	)
@@ -50,115 +57,171 @@ attached to the current app (you can pass an app in with -a <appname>).`
 		flag.App(),
 		flag.AppConfig(),
 		flag.Org(),
+		flag.JSONOutput(),
 		flag.Bool{
 			Name:        "short",
 			Shorthand:   "s",
 			Default:     false,
 			Description: "Just print the answers, not DNS record details",
 		},
+		flag.Bool{
+			Name:        "doh",
+			Description: "Send the request over DNS-over-HTTPS rather than through the agent",
+		},
+		flag.String{
+			Name:        "doh-url",
+			Description: "The DNS-over-HTTPS endpoint to use when the private network can't be reached",
+			Default:     DefaultDoHURL,
+		},
 	)
 
 	return cmd
 }
 
 func run(ctx context.Context) error {
-	var (
-		client = client.FromContext(ctx).API()
-		io     = iostreams.FromContext(ctx)
+	dtype := "AAAA"
+	name := flag.FirstArg(ctx)
 
-		err error
-	)
+	if len(flag.Args(ctx)) > 1 {
+		dtype = strings.ToUpper(flag.FirstArg(ctx))
+		name = flag.Args(ctx)[1]
+	}
+	// add the trailing dot
+	name = dns.Fqdn(name)
 
-	orgSlug := flag.GetOrg(ctx)
+	switch dtype {
+	case "A", "CNAME", "TXT", "AAAA", "SRV":
+		return runQuery(ctx, dtype, name)
+	case "AAAA-NATIVE", "TXT-NATIVE":
+		return runNative(ctx, dtype, name)
+	default:
+		return fmt.Errorf("don't understand DNS type %s", dtype)
+	}
+}
 
-	if orgSlug == "" {
-		appName := appconfig.NameFromContext(ctx)
+func orgSlug(ctx context.Context) (string, error) {
+	if slug := flag.GetOrg(ctx); slug != "" {
+		return slug, nil
+	}
 
-		app, err := client.GetAppBasic(ctx, appName)
-		if err != nil {
-			return fmt.Errorf("get app: %w", err)
-		}
-		orgSlug = app.Organization.Slug
+	app, err := client.FromContext(ctx).API().GetAppBasic(ctx, appconfig.NameFromContext(ctx))
+	if err != nil {
+		return "", fmt.Errorf("get app: %w", err)
 	}
 
-	agentclient, err := agent.Establish(ctx, client)
+	return app.Organization.Slug, nil
+}
+
+func runQuery(ctx context.Context, dtype, name string) error {
+	io := iostreams.FromContext(ctx)
+
+	msg := &dns.Msg{}
+	msg.SetQuestion(name, dns.StringToType[dtype])
+	msg.RecursionDesired = !strings.HasSuffix(name, ".internal.")
+
+	reply, server, err := exchange(ctx, msg)
 	if err != nil {
 		return err
 	}
 
-	r, ns, err := ResolverForOrg(ctx, agentclient, orgSlug)
+	switch {
+	case config.FromContext(ctx).JSONOutput:
+		return render.Output(ctx, io.Out, newResult(server, msg.Question[0], reply))
+	case flag.GetBool(ctx, "short"):
+		if reply.MsgHdr.Rcode != dns.RcodeSuccess {
+			return fmt.Errorf("lookup failed: %s", dns.RcodeToString[reply.MsgHdr.Rcode])
+		}
+
+		for _, answer := range shortAnswers(msg.Question[0].Qtype, reply) {
+			fmt.Fprintln(io.Out, answer)
+		}
+	default:
+		fmt.Fprintf(io.Out, "%+v\n", reply)
+		fmt.Fprintf(io.Out, ";; SERVER: %s\n", server)
+	}
+
+	return nil
+}
+
+// exchange sends msg to the DNS server of the organization through the agent,
+// falling back to DNS-over-HTTPS when there's no tunnel to it. It returns the
+// reply along with the server which sent it.
+func exchange(ctx context.Context, msg *dns.Msg) (*dns.Msg, string, error) {
+	dohURL := flag.GetString(ctx, "doh-url")
+
+	if !flag.GetBool(ctx, "doh") {
+		reply, server, err := exchangeTunnel(ctx, msg)
+		if err == nil {
+			return reply, server, nil
+		}
+
+		fmt.Fprintf(iostreams.FromContext(ctx).ErrOut, "Falling back to %s, as the private network can't be reached: %v\n", dohURL, err)
+	}
+
+	reply, err := queryDoH(ctx, dohURL, msg)
 	if err != nil {
-		return err
+		return nil, "", err
 	}
 
-	d, err := agentclient.Dialer(ctx, orgSlug)
+	return reply, dohURL, nil
+}
+
+func exchangeTunnel(ctx context.Context, msg *dns.Msg) (*dns.Msg, string, error) {
+	orgSlug, err := orgSlug(ctx)
 	if err != nil {
-		return err
+		return nil, "", err
 	}
 
-	conn, err := d.DialContext(ctx, "tcp", net.JoinHostPort(ns, "53"))
+	agentclient, err := agent.Establish(ctx, client.FromContext(ctx).API())
 	if err != nil {
-		return err
+		return nil, "", err
 	}
 
-	msg := &dns.Msg{}
+	_, ns, err := ResolverForOrg(ctx, agentclient, orgSlug)
+	if err != nil {
+		return nil, "", err
+	}
 
-	dtype := "AAAA"
-	name := flag.FirstArg(ctx)
+	d, err := agentclient.Dialer(ctx, orgSlug)
+	if err != nil {
+		return nil, "", err
+	}
 
-	if len(flag.Args(ctx)) > 1 {
-		dtype = strings.ToUpper(flag.FirstArg(ctx))
-		name = flag.Args(ctx)[1]
+	server := net.JoinHostPort(ns, "53")
+
+	conn, err := d.DialContext(ctx, "tcp", server)
+	if err != nil {
+		return nil, "", err
 	}
-	// add the trailing dot
-	name = dns.Fqdn(name)
+	defer conn.Close()
 
-	if strings.HasSuffix(name, ".internal.") {
-		msg.RecursionDesired = false
-	} else {
-		msg.RecursionDesired = true
+	reply, err := roundTrip(conn, msg)
+	if err != nil {
+		return nil, "", err
 	}
 
-	// put this switch block in its own function to reduce the footprint of the main function
-	// e.g: func resolve(ctx context.Context, r *net.Resolver, msg *dns.Msg, name string, dtype string)
-	switch dtype {
-	case "A", "CNAME", "TXT", "AAAA":
-		msg.SetQuestion(name, dns.StringToType[dtype])
+	return reply, server, nil
+}
 
-		reply, err := roundTrip(conn, msg)
-		if err != nil {
-			return err
-		}
+func runNative(ctx context.Context, dtype, name string) error {
+	io := iostreams.FromContext(ctx)
 
-		if flag.GetBool(ctx, "short") {
-			if reply.MsgHdr.Rcode != dns.RcodeSuccess {
-				return fmt.Errorf("lookup failed: %s", dns.RcodeToString[reply.MsgHdr.Rcode])
-			}
+	orgSlug, err := orgSlug(ctx)
+	if err != nil {
+		return err
+	}
 
-			switch dtype {
-			case "AAAA":
-				for _, rr := range reply.Answer {
-					if aaaa, ok := rr.(*dns.AAAA); ok {
-						fmt.Fprintf(io.Out, "%s\n", aaaa.AAAA)
-					}
-				}
-			case "TXT":
-				buf := &bytes.Buffer{}
-
-				for _, rr := range reply.Answer {
-					if txt, ok := rr.(*dns.TXT); ok {
-						for _, s := range txt.Txt {
-							buf.WriteString(s)
-						}
-					}
-				}
-
-				fmt.Fprintf(io.Out, "%s\n", buf.String())
-			}
-		} else {
-			fmt.Fprintf(io.Out, "%+v\n", reply)
-		}
+	agentclient, err := agent.Establish(ctx, client.FromContext(ctx).API())
+	if err != nil {
+		return err
+	}
+
+	r, ns, err := ResolverForOrg(ctx, agentclient, orgSlug)
+	if err != nil {
+		return err
+	}
 
+	switch dtype {
 	case "AAAA-NATIVE":
 		hosts, err := r.LookupHost(ctx, name)
 		if err != nil {
@@ -176,14 +239,94 @@ func run(ctx context.Context) error {
 		}
 
 		fmt.Fprintf(io.Out, "%s\n", strings.Join(txts, ""))
-
-	default:
-		return fmt.Errorf("don't understand DNS type %s", dtype)
 	}
 
 	return nil
 }
 
+type record struct {
+	Name string
+	Type string
+	TTL  uint32
+	Data string
+}
+
+type result struct {
+	Server    string
+	Name      string
+	Type      string
+	Status    string
+	Answers   []record
+	Authority []record `json:",omitempty"`
+	Extra     []record `json:",omitempty"`
+}
+
+func newResult(server string, q dns.Question, reply *dns.Msg) result {
+	return result{
+		Server:    server,
+		Name:      q.Name,
+		Type:      dns.TypeToString[q.Qtype],
+		Status:    dns.RcodeToString[reply.Rcode],
+		Answers:   records(reply.Answer),
+		Authority: records(reply.Ns),
+		Extra:     records(reply.Extra),
+	}
+}
+
+func records(rrs []dns.RR) []record {
+	ret := make([]record, 0, len(rrs))
+	for _, rr := range rrs {
+		h := rr.Header()
+		ret = append(ret, record{
+			Name: h.Name,
+			Type: dns.TypeToString[h.Rrtype],
+			TTL:  h.Ttl,
+			Data: rdata(rr),
+		})
+	}
+	return ret
+}
+
+// rdata returns the data of a record in its zone file format, without the
+// header.
+func rdata(rr dns.RR) string {
+	return strings.TrimPrefix(rr.String(), rr.Header().String())
+}
+
+// shortAnswers returns the answers of the type asked for. TXT answers are
+// joined into one, as services split long values across records.
+func shortAnswers(qtype uint16, reply *dns.Msg) []string {
+	var (
+		answers []string
+		txt     bytes.Buffer
+	)
+
+	for _, rr := range reply.Answer {
+		if rr.Header().Rrtype != qtype {
+			continue
+		}
+
+		switch rr := rr.(type) {
+		case *dns.TXT:
+			for _, s := range rr.Txt {
+				txt.WriteString(s)
+			}
+		case *dns.A:
+			answers = append(answers, rr.A.String())
+		case *dns.AAAA:
+			answers = append(answers, rr.AAAA.String())
+		default:
+			answers = append(answers, rdata(rr))
+		}
+	}
+
+	if qtype == dns.TypeTXT {
+		answers = append(answers, txt.String())
+	}
+
+	return answers
+}
+
 // roundTrip a DNS request across a "TCP" socket; we'd just use miekg/dns's Client, but I don't think it promises to
 // work over our weird UDS TCP proxy.
 func roundTrip(conn net.Conn, m *dns.Msg) (*dns.Msg, error) {
@@ -206,14 +349,14 @@ func roundTrip(conn net.Conn, m *dns.Msg) (*dns.Msg, error) {
 		return nil, err
 	}
 
-	if _, err = conn.Read(lenbuf[:]); err != nil {
+	if _, err = io.ReadFull(conn, lenbuf[:]); err != nil {
 		return nil, err
 	}
 
 	l := int(binary.BigEndian.Uint16(lenbuf[:]))
 	buf = make([]byte, l)
 
-	if _, err = conn.Read(buf); err != nil {
+	if _, err = io.ReadFull(conn, buf); err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,58 @@
+package dig
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/miekg/dns"
+)
+
+// DefaultDoHURL is the DNS-over-HTTPS endpoint queries fall back to when no
+// tunnel to the private network is available.
+const DefaultDoHURL = "https://cloudflare-dns.com/dns-query"
+
+const dnsMessageType = "application/dns-message"
+
+// queryDoH sends m to the DNS-over-HTTPS endpoint at url, in the wire format
+// of RFC 8484.
+func queryDoH(ctx context.Context, url string, m *dns.Msg) (*dns.Msg, error) {
+	// RFC 8484 recommends an ID of 0, so that responses are cacheable
+	m.Id = 0
+
+	buf, err := m.Pack()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(buf))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", dnsMessageType)
+	req.Header.Set("Accept", dnsMessageType)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("DoH query to %s failed: %s", url, resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, dns.MaxMsgSize))
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &dns.Msg{}
+	if err := reply.Unpack(body); err != nil {
+		return nil, fmt.Errorf("failed parsing DoH response: %w", err)
+	}
+
+	return reply, nil
+}
@@ -0,0 +1,130 @@
+// Package dns implements the dns command chain, which debugs service
+// discovery on the private network.
+package dns
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/agent"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/command/dig"
+)
+
+func New() *cobra.Command {
+	const (
+		long = `Resolve .internal and .flycast names of the private network with the DNS
+server of the organization, through the agent, and show the machines backing
+each name. Use fly dig to make raw DNS requests.`
+
+		short = "Debug service discovery on the private network"
+	)
+
+	cmd := command.New("dns", short, long, nil)
+
+	cmd.AddCommand(
+		newQuery(),
+		newList(),
+	)
+
+	return cmd
+}
+
+// resolver resolves names with the DNS server of an organization.
+type resolver struct {
+	*net.Resolver
+	// Server is the address of the DNS server.
+	Server string
+}
+
+func newResolver(ctx context.Context, orgSlug string) (*resolver, error) {
+	agentclient, err := agent.Establish(ctx, client.FromContext(ctx).API())
+	if err != nil {
+		return nil, err
+	}
+
+	r, ns, err := dig.ResolverForOrg(ctx, agentclient, orgSlug)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resolver{Resolver: r, Server: ns}, nil
+}
+
+// lookup resolves the addresses of name. A name without records resolves to
+// no addresses rather than an error.
+func (r *resolver) lookup(ctx context.Context, name string) ([]string, error) {
+	addrs, err := r.LookupHost(ctx, name)
+	if dnsErr, ok := err.(*net.DNSError); ok && dnsErr.IsNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed resolving %s with %s: %w", name, r.Server, err)
+	}
+	return addrs, nil
+}
+
+// appFromName returns the app a name of the private network belongs to. Names
+// are <app>.internal or <app>.flycast, with an optional prefix selecting
+// machines such as a region, <id>.vm or top2.nearest.of.
+func appFromName(name string) (app string, flycast bool, ok bool) {
+	labels := strings.Split(strings.TrimSuffix(name, "."), ".")
+	if len(labels) < 2 {
+		return "", false, false
+	}
+
+	switch labels[len(labels)-1] {
+	case "internal":
+		app = labels[len(labels)-2]
+		// names of the organization rather than of an app
+		if strings.HasPrefix(app, "_") {
+			return "", false, false
+		}
+		return app, false, true
+	case "flycast":
+		return labels[len(labels)-2], true, true
+	default:
+		return "", false, false
+	}
+}
+
+// appMachines lists the machines of an app, indexed by private address.
+func appMachines(ctx context.Context, appName string) (map[string]*api.Machine, error) {
+	app, err := client.FromContext(ctx).API().GetAppCompact(ctx, appName)
+	if err != nil {
+		return nil, fmt.Errorf("failed retrieving app %s: %w", appName, err)
+	}
+
+	flapsClient, err := flaps.New(ctx, app)
+	if err != nil {
+		return nil, err
+	}
+
+	machines, err := flapsClient.ListActive(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("machines of %s could not be retrieved: %w", appName, err)
+	}
+
+	byAddr := make(map[string]*api.Machine, len(machines))
+	for _, m := range machines {
+		if ip := net.ParseIP(m.PrivateIP); ip != nil {
+			byAddr[ip.String()] = m
+		}
+	}
+	return byAddr, nil
+}
+
+// orgSlugOf returns the organization of appName.
+func orgSlugOf(ctx context.Context, appName string) (string, error) {
+	app, err := client.FromContext(ctx).API().GetAppBasic(ctx, appName)
+	if err != nil {
+		return "", fmt.Errorf("get app: %w", err)
+	}
+	return app.Organization.Slug, nil
+}
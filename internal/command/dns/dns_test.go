@@ -0,0 +1,38 @@
+package dns
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppFromName(t *testing.T) {
+	cases := []struct {
+		name    string
+		app     string
+		flycast bool
+		ok      bool
+	}{
+		{"my-app.internal", "my-app", false, true},
+		{"iad.my-app.internal.", "my-app", false, true},
+		{"top2.nearest.of.my-app.internal", "my-app", false, true},
+		{"148ed193b95e89.vm.my-app.internal", "my-app", false, true},
+		{"my-app.flycast", "my-app", true, true},
+		{"_apps.internal", "", false, false},
+		{"example.com", "", false, false},
+		{"internal", "", false, false},
+	}
+
+	for _, c := range cases {
+		app, flycast, ok := appFromName(c.name)
+		assert.Equal(t, c.app, app, c.name)
+		assert.Equal(t, c.flycast, flycast, c.name)
+		assert.Equal(t, c.ok, ok, c.name)
+	}
+}
+
+func TestSplitTXT(t *testing.T) {
+	assert.Equal(t, []string{"iad", "lhr"}, splitTXT([]string{"lhr,iad"}))
+	assert.Equal(t, []string{"ams", "iad"}, splitTXT([]string{"iad, ", "ams"}))
+	assert.Empty(t, splitTXT(nil))
+}
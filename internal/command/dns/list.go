@@ -0,0 +1,149 @@
+package dns
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newList() *cobra.Command {
+	const (
+		long = `List the private network names of an app, as the DNS server of its
+organization resolves them: the app name, a name per region and the Flycast
+name, along with the machines behind each. Addresses matching no active
+machine of the app are reported, they point at stale records.`
+
+		short = "List the private network names of an app"
+	)
+
+	cmd := command.New("list", short, long, runList,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	cmd.Aliases = []string{"ls"}
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.JSONOutput(),
+	)
+
+	return cmd
+}
+
+type name struct {
+	Name      string   `json:"name"`
+	Region    string   `json:"region,omitempty"`
+	Addresses []string `json:"addresses"`
+	Machines  []string `json:"machines,omitempty"`
+	// Unknown are the addresses matching no active machine.
+	Unknown []string `json:"unknown,omitempty"`
+}
+
+func runList(ctx context.Context) error {
+	var (
+		io      = iostreams.FromContext(ctx)
+		appName = appconfig.NameFromContext(ctx)
+	)
+
+	orgSlug, err := orgSlugOf(ctx, appName)
+	if err != nil {
+		return err
+	}
+
+	r, err := newResolver(ctx, orgSlug)
+	if err != nil {
+		return err
+	}
+
+	machines, err := appMachines(ctx, appName)
+	if err != nil {
+		return err
+	}
+
+	resolve := func(n, region string) (name, error) {
+		addrs, err := r.lookup(ctx, n)
+		if err != nil {
+			return name{}, err
+		}
+		sort.Strings(addrs)
+
+		res := name{Name: n, Region: region, Addresses: addrs}
+		for _, addr := range addrs {
+			if m, ok := machines[net.ParseIP(addr).String()]; ok {
+				res.Machines = append(res.Machines, m.ID)
+			} else {
+				res.Unknown = append(res.Unknown, addr)
+			}
+		}
+		return res, nil
+	}
+
+	app, err := resolve(appName+".internal", "")
+	if err != nil {
+		return err
+	}
+	names := []name{app}
+
+	regions, err := r.LookupTXT(ctx, "regions."+appName+".internal")
+	if err != nil {
+		return fmt.Errorf("failed listing the regions of %s with %s: %w", appName, r.Server, err)
+	}
+	for _, region := range splitTXT(regions) {
+		n, err := resolve(region+"."+appName+".internal", region)
+		if err != nil {
+			return err
+		}
+		names = append(names, n)
+	}
+
+	// Flycast addresses are routed by the proxy, not by machine
+	flycastAddrs, err := r.lookup(ctx, appName+".flycast")
+	if err != nil {
+		return err
+	}
+	if len(flycastAddrs) > 0 {
+		names = append(names, name{Name: appName + ".flycast", Addresses: flycastAddrs})
+	}
+
+	if config.FromContext(ctx).JSONOutput {
+		return render.Output(ctx, io.Out, names)
+	}
+
+	rows := make([][]string, 0, len(names))
+	for _, n := range names {
+		rows = append(rows, []string{
+			n.Name,
+			n.Region,
+			strings.Join(n.Addresses, ", "),
+			strings.Join(n.Machines, ", "),
+			strings.Join(n.Unknown, ", "),
+		})
+	}
+	return render.Table(io.Out, "", rows, "Name", "Region", "Addresses", "Machines", "Unknown Addresses")
+}
+
+// splitTXT splits the comma separated values of TXT records.
+func splitTXT(records []string) []string {
+	var values []string
+	for _, v := range strings.Split(strings.Join(records, ""), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	sort.Strings(values)
+	return values
+}
@@ -0,0 +1,129 @@
+package dns
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newQuery() *cobra.Command {
+	const (
+		long = `Resolve a .internal or .flycast name, such as my-app.internal,
+iad.my-app.internal or top2.nearest.of.my-app.internal, and show the machine
+behind each address. Flycast addresses are served by the proxy, which routes
+them to the machines of the app.`
+
+		short = "Resolve a private network name and show the machines behind it"
+	)
+
+	cmd := command.New("query <name>", short, long, runQuery,
+		command.RequireSession,
+		command.LoadAppNameIfPresent,
+	)
+
+	cmd.Args = cobra.ExactArgs(1)
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.Org(),
+		flag.JSONOutput(),
+	)
+
+	return cmd
+}
+
+type answer struct {
+	Address string `json:"address"`
+	App     string `json:"app,omitempty"`
+	Machine string `json:"machine,omitempty"`
+	Region  string `json:"region,omitempty"`
+	State   string `json:"state,omitempty"`
+}
+
+func runQuery(ctx context.Context) error {
+	var (
+		io   = iostreams.FromContext(ctx)
+		name = flag.FirstArg(ctx)
+	)
+
+	appName, flycast, ok := appFromName(name)
+	if !ok {
+		appName = appconfig.NameFromContext(ctx)
+	}
+
+	orgSlug := flag.GetOrg(ctx)
+	if orgSlug == "" {
+		if appName == "" {
+			return fmt.Errorf("specify an organization with --org to resolve %s", name)
+		}
+		var err error
+		if orgSlug, err = orgSlugOf(ctx, appName); err != nil {
+			return err
+		}
+	}
+
+	r, err := newResolver(ctx, orgSlug)
+	if err != nil {
+		return err
+	}
+
+	addrs, err := r.lookup(ctx, name)
+	if err != nil {
+		return err
+	}
+	if len(addrs) == 0 {
+		return fmt.Errorf("%s has no addresses", name)
+	}
+	sort.Strings(addrs)
+
+	answers := make([]answer, 0, len(addrs))
+	for _, addr := range addrs {
+		answers = append(answers, answer{Address: addr})
+	}
+
+	if appName != "" && !flycast {
+		machines, err := appMachines(ctx, appName)
+		if err != nil {
+			return err
+		}
+		for i := range answers {
+			if ip := net.ParseIP(answers[i].Address); ip != nil {
+				if m, ok := machines[ip.String()]; ok {
+					answers[i].App = appName
+					answers[i].Machine = m.ID
+					answers[i].Region = m.Region
+					answers[i].State = m.State
+				}
+			}
+		}
+	}
+
+	if config.FromContext(ctx).JSONOutput {
+		return render.Output(ctx, io.Out, answers)
+	}
+
+	rows := make([][]string, 0, len(answers))
+	for _, a := range answers {
+		rows = append(rows, []string{a.Address, a.App, a.Machine, a.Region, a.State})
+	}
+	if err := render.Table(io.Out, "", rows, "Address", "App", "Machine", "Region", "State"); err != nil {
+		return err
+	}
+
+	if flycast {
+		fmt.Fprintf(io.Out, "%s is a Flycast address, the proxy routes its connections to the machines of %s with services.\n", name, appName)
+	}
+
+	return nil
+}
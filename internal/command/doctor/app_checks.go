@@ -23,6 +23,7 @@ import (
 
 type AppChecker struct {
 	jsonOutput bool
+	fix        bool
 	checks     map[string]string
 	color      *iostreams.ColorScheme
 	ctx        context.Context
@@ -32,7 +33,7 @@ type AppChecker struct {
 	apiClient  *api.Client
 }
 
-func NewAppChecker(ctx context.Context, jsonOutput bool, color *iostreams.ColorScheme) (*AppChecker, error) {
+func NewAppChecker(ctx context.Context, jsonOutput, fix bool, color *iostreams.ColorScheme) (*AppChecker, error) {
 	appName := appconfig.NameFromContext(ctx)
 	if appName == "" {
 		if !jsonOutput {
@@ -53,6 +54,7 @@ func NewAppChecker(ctx context.Context, jsonOutput bool, color *iostreams.ColorS
 
 	ac := &AppChecker{
 		jsonOutput: jsonOutput,
+		fix:        fix,
 		checks:     make(map[string]string),
 		color:      color,
 		ctx:        ctx,
@@ -98,6 +100,10 @@ func (ac *AppChecker) checkAll() map[string]string {
 	ipAddresses := ac.checkIpsAllocated()
 	ac.checkDnsRecords(ipAddresses)
 
+	if ac.app.PlatformVersion == appconfig.MachinesPlatform {
+		ac.checkMachines()
+	}
+
 	relPath, err := filepath.Rel(ac.workDir, ac.appConfig.ConfigFilePath())
 	if err == nil && relPath == appconfig.DefaultConfigFileName {
 		ac.lprint(nil, "\nBuild checks for %s:\n", ac.app.Name)
@@ -9,6 +9,7 @@ import (
 
 	dockerclient "github.com/docker/docker/client"
 	"github.com/spf13/cobra"
+	"golang.zx2c4.com/wireguard/device"
 
 	"github.com/superfly/flyctl/agent"
 	"github.com/superfly/flyctl/iostreams"
@@ -28,7 +29,16 @@ import (
 func New() (cmd *cobra.Command) {
 	const (
 		short = `The DOCTOR command allows you to debug your Fly environment`
-		long  = short + "\n"
+		long  = short + `
+
+It checks your authentication, the flyctl agent, WireGuard connectivity and
+its MTU, and for apps, their IP addresses and DNS records, that the Machines
+API is reachable, and their machines for stale leases and orphaned release
+command machines.
+
+With --fix, it restarts the agent when it or WireGuard connectivity fail, and
+releases stale leases of the machines of the app.
+`
 	)
 
 	cmd = command.New("doctor", short, long, run,
@@ -48,6 +58,11 @@ func New() (cmd *cobra.Command) {
 			Default:     false,
 			Description: "Print extra diagnostic information.",
 		},
+		flag.Bool{
+			Name:        "fix",
+			Default:     false,
+			Description: "Restart the agent and release stale machine leases when they cause problems.",
+		},
 	)
 
 	cmd.AddCommand(diag.New())
@@ -59,6 +74,7 @@ func run(ctx context.Context) (err error) {
 	var (
 		isJson    = config.FromContext(ctx).JSONOutput
 		isVerbose = flag.GetBool(ctx, "verbose")
+		isFix     = flag.GetBool(ctx, "fix")
 		io        = iostreams.FromContext(ctx)
 		color     = io.ColorScheme()
 		checks    = map[string]string{}
@@ -90,7 +106,7 @@ func run(ctx context.Context) (err error) {
 
 	defer func() {
 		if isJson {
-			render.JSON(iostreams.FromContext(ctx).Out, checks)
+			render.Output(ctx, iostreams.FromContext(ctx).Out, checks)
 		}
 	}()
 
@@ -114,6 +130,11 @@ never signed up before.
 	lprint(nil, "Testing flyctl agent... ")
 
 	err = runAgent(ctx)
+	if err != nil && isFix {
+		lprint(color.Yellow, "FAILED\n(Error: %s)\n", err)
+		lprint(nil, "Restarting flyctl agent... ")
+		err = restartAgent(ctx)
+	}
 	if !check("agent", err) {
 		lprint(nil, `
 Can't communicate with flyctl's background agent.
@@ -146,6 +167,13 @@ Run 'flyctl agent restart'.
 
 	lprint(nil, "Pinging WireGuard gateway (give us a sec)... ")
 	err = runPersonalOrgPing(ctx)
+	if err != nil && isFix {
+		lprint(color.Yellow, "FAILED\n(Error: %s)\n", err)
+		lprint(nil, "Restarting flyctl agent and pinging again... ")
+		if err = restartAgent(ctx); err == nil {
+			err = runPersonalOrgPing(ctx)
+		}
+	}
 	if !check("ping", err) {
 		lprint(nil, `
 We can't establish connectivity with WireGuard for your personal organization.
@@ -164,11 +192,31 @@ followed by 'flyctl agent restart', and we'll run WireGuard over HTTPS.
 		return nil
 	}
 
+	// ------------------------------------------------------------
+
+	lprint(nil, "Testing WireGuard MTU... ")
+	err = runPersonalOrgMTU(ctx)
+	if err != nil {
+		checks["wireguardMTU"] = err.Error()
+		lprint(nil, `Nope
+    (We got: %s)
+    Small packets make it through WireGuard, but not packets as large as its MTU.
+    A VPN, PPPoE link or firewall on your network likely lowers the MTU, which
+    makes connections hang as they send large requests or responses.
+
+    Run 'flyctl wireguard websockets enable', followed by 'flyctl agent restart',
+    and we'll run WireGuard over HTTPS.
+`, err)
+	} else {
+		lprint(color.Green, "PASSED\n")
+		checks["wireguardMTU"] = "ok"
+	}
+
 	// ------------------------------------------------------------
 	// App specific checks below here
 	// ------------------------------------------------------------
 
-	appChecker, err := NewAppChecker(ctx, isJson, color)
+	appChecker, err := NewAppChecker(ctx, isJson, isFix, color)
 	if err != nil {
 		return err
 	}
@@ -212,7 +260,36 @@ func runAgent(ctx context.Context) (err error) {
 	return
 }
 
+// restartAgent kills the running agent, then starts a new one.
+func restartAgent(ctx context.Context) error {
+	if ac, err := agent.DefaultClient(ctx); err == nil {
+		_ = ac.Kill(ctx)
+	}
+
+	if _, err := agent.Establish(ctx, client.FromContext(ctx).API()); err != nil {
+		return fmt.Errorf("couldn't restart agent: %w", err)
+	}
+
+	return runAgent(ctx)
+}
+
 func runPersonalOrgPing(ctx context.Context) (err error) {
+	return pingPersonalOrgGateway(ctx, 12)
+}
+
+// mtuProbePadding pads echo requests so that they're as large as packets
+// going through WireGuard tunnels may be: the 40 bytes of the IPv6 header, 8
+// of the ICMPv6 header and 15 of the timestamp make up the rest.
+const mtuProbePadding = device.DefaultMTU - 40 - 8 - 15
+
+func runPersonalOrgMTU(ctx context.Context) error {
+	if err := pingPersonalOrgGateway(ctx, mtuProbePadding); err != nil {
+		return fmt.Errorf("%d-byte packets: %w", device.DefaultMTU, err)
+	}
+	return nil
+}
+
+func pingPersonalOrgGateway(ctx context.Context, pad uint) (err error) {
 	client := client.FromContext(ctx).API()
 
 	ac, err := agent.DefaultClient(ctx)
@@ -242,7 +319,7 @@ func runPersonalOrgPing(ctx context.Context) (err error) {
 	replyBuf := make([]byte, 1000)
 
 	for i := 0; i < 30; i++ {
-		_, err = pinger.WriteTo(ping.EchoRequest(0, i, time.Now(), 12), &net.IPAddr{IP: net.ParseIP(ns)})
+		_, err = pinger.WriteTo(ping.EchoRequest(0, i, time.Now(), pad), &net.IPAddr{IP: net.ParseIP(ns)})
 
 		pinger.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
 		_, _, err := pinger.ReadFrom(replyBuf)
@@ -0,0 +1,141 @@
+package doctor
+
+import (
+	"strings"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/flaps"
+)
+
+// checkMachines runs the checks specific to apps on the machines platform.
+func (ac *AppChecker) checkMachines() {
+	ac.lprint(nil, "\nMachines checks for %s:\n", ac.app.Name)
+
+	ac.lprint(nil, "Checking that the Machines API is reachable... ")
+	flapsClient, err := flaps.New(ac.ctx, ac.app)
+	var machines []*api.Machine
+	if err == nil {
+		machines, err = flapsClient.List(ac.ctx, "")
+	}
+	if err != nil {
+		ac.checks["flaps"] = err.Error()
+		ac.lprint(ac.color.Red, "FAILED\n(Error: %s)\n", err)
+		ac.lprint(nil, `	flyctl talks to the Machines API to deploy and manage machines.
+	Check https://status.flyio.net/ for ongoing incidents, or your network for proxies blocking api.machines.dev.
+`)
+		return
+	}
+	ac.checks["flaps"] = "ok"
+	ac.lprint(ac.color.Green, "PASSED\n")
+
+	ac.checkStaleLeases(flapsClient, machines)
+	ac.checkOrphanedReleaseCommandMachines(machines)
+}
+
+// deploymentInProgress reports whether a machine holds the deploy lock of the
+// app.
+func deploymentInProgress(machines []*api.Machine) bool {
+	for _, m := range machines {
+		if m.Config != nil && m.Config.Metadata[api.MachineConfigMetadataKeyFlyDeployLock] != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// checkStaleLeases looks for leases held on machines of the app while no
+// deployment is in progress, left behind by interrupted flyctl commands. They
+// prevent deployments and updates of the machines until they expire.
+func (ac *AppChecker) checkStaleLeases(flapsClient *flaps.Client, machines []*api.Machine) {
+	const checkKey = "appStaleLeases"
+
+	ac.lprint(nil, "Checking for stale machine leases... ")
+	if deploymentInProgress(machines) {
+		ac.checks[checkKey] = "skipped, a deployment is in progress"
+		ac.lprint(nil, "Skipped, a deployment is in progress\n")
+		return
+	}
+
+	leases := make(map[string]*api.MachineLeaseData)
+	var ids []string
+	for _, m := range machines {
+		if !m.IsActive() {
+			continue
+		}
+
+		lease, err := flapsClient.FindLease(ac.ctx, m.ID)
+		switch {
+		case err != nil && strings.Contains(err.Error(), " lease not found"):
+			continue
+		case err != nil:
+			ac.checks[checkKey] = err.Error()
+			ac.lprint(ac.color.Red, "FAILED\n(Error: %s)\n", err)
+			return
+		case lease.Data == nil || lease.Data.Nonce == "":
+			continue
+		}
+
+		leases[m.ID] = lease.Data
+		ids = append(ids, m.ID)
+	}
+
+	if len(ids) == 0 {
+		ac.checks[checkKey] = "ok"
+		ac.lprint(ac.color.Green, "PASSED\n")
+		return
+	}
+
+	ac.checks[checkKey] = "stale leases on machines: " + strings.Join(ids, ", ")
+	ac.lprint(nil, "Nope\n")
+	for _, id := range ids {
+		ac.lprint(nil, "	Machine %s is leased by %s\n", id, leases[id].Owner)
+	}
+
+	if !ac.fix {
+		ac.lprint(nil, `	These leases were likely left behind by interrupted flyctl commands, and block updates of the machines until they expire.
+	Run 'flyctl doctor --fix' to release them, unless a command is still running on these machines.
+`)
+		return
+	}
+
+	for _, id := range ids {
+		if err := flapsClient.ReleaseLease(ac.ctx, id, leases[id].Nonce); err != nil {
+			ac.lprint(ac.color.Red, "	Failed releasing the lease of machine %s: %s\n", id, err)
+			return
+		}
+		ac.lprint(nil, "	Released the lease of machine %s\n", id)
+	}
+	ac.checks[checkKey] = "fixed, released leases on machines: " + strings.Join(ids, ", ")
+}
+
+// checkOrphanedReleaseCommandMachines looks for release command machines left
+// behind by deployments. They're destroyed as soon as they exit otherwise.
+func (ac *AppChecker) checkOrphanedReleaseCommandMachines(machines []*api.Machine) {
+	const checkKey = "appOrphanedReleaseCommandMachines"
+
+	ac.lprint(nil, "Checking for orphaned release command machines... ")
+	if deploymentInProgress(machines) {
+		ac.checks[checkKey] = "skipped, a deployment is in progress"
+		ac.lprint(nil, "Skipped, a deployment is in progress\n")
+		return
+	}
+
+	var ids []string
+	for _, m := range machines {
+		if m.IsReleaseCommandMachine() && m.State != api.MachineStateDestroyed && m.State != api.MachineStateDestroying {
+			ids = append(ids, m.ID)
+		}
+	}
+
+	if len(ids) == 0 {
+		ac.checks[checkKey] = "ok"
+		ac.lprint(ac.color.Green, "PASSED\n")
+		return
+	}
+
+	ac.checks[checkKey] = "orphaned release command machines: " + strings.Join(ids, ", ")
+	ac.lprint(nil, `Nope
+	These release command machines weren't destroyed after running: %s
+	They count towards the machine limit of the organization; destroy them with 'flyctl machine destroy --force <id>'.
+`, strings.Join(ids, ", "))
+}
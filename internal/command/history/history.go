@@ -53,7 +53,7 @@ func run(ctx context.Context) error {
 
 	out := iostreams.FromContext(ctx).Out
 	if config.FromContext(ctx).JSONOutput {
-		return render.JSON(out, changes)
+		return render.Output(ctx, out, changes)
 	}
 
 	var rows [][]string
@@ -0,0 +1,193 @@
+package image
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/format"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newDelete() *cobra.Command {
+	const (
+		long = `Delete images from the app's repository on the Fly registry.
+
+Images may be selected by tag, or by age with --older-than while keeping the
+newest --keep images around. Images currently used by the app's machines are
+never deleted.
+`
+		short = "Delete images from the app's registry repository"
+
+		usage = "delete [tag...]"
+	)
+
+	cmd := command.New(usage, short, long, runDelete,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	cmd.Aliases = []string{"rm"}
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.Yes(),
+		flag.Duration{
+			Name:        "older-than",
+			Description: "Delete images created before this long ago, e.g. 720h",
+		},
+		flag.Int{
+			Name:        "keep",
+			Description: "Number of most recent images to keep when deleting by age",
+			Default:     10,
+		},
+	)
+
+	return cmd
+}
+
+func runDelete(ctx context.Context) error {
+	var (
+		io        = iostreams.FromContext(ctx)
+		appName   = appconfig.NameFromContext(ctx)
+		tags      = flag.Args(ctx)
+		olderThan = flag.GetDuration(ctx, "older-than")
+	)
+
+	if len(tags) == 0 && olderThan == 0 {
+		return errors.New("specify the tags to delete or use --older-than")
+	}
+
+	images, err := listRegistryImages(ctx, appName)
+	if err != nil {
+		return err
+	}
+
+	inUse, err := imagesInUse(ctx, appName)
+	if err != nil {
+		return err
+	}
+
+	var selected []*registryImage
+	if len(tags) > 0 {
+		selected, err = selectImagesByTag(images, tags)
+		if err != nil {
+			return err
+		}
+	} else {
+		selected = selectImagesByAge(images, time.Now().Add(-olderThan), flag.GetInt(ctx, "keep"))
+	}
+
+	var toDelete []*registryImage
+	for _, img := range selected {
+		if inUse[img.Digest] {
+			fmt.Fprintf(io.ErrOut, "Skipping %s (%s): it is in use by a machine\n", img.Digest, strings.Join(img.Tags, ", "))
+			continue
+		}
+		toDelete = append(toDelete, img)
+	}
+
+	if len(toDelete) == 0 {
+		fmt.Fprintln(io.Out, "No images to delete")
+		return nil
+	}
+
+	fmt.Fprintf(io.Out, "The following %d images will be deleted:\n", len(toDelete))
+	for _, img := range toDelete {
+		fmt.Fprintf(io.Out, "  %s %s (created %s)\n", img.Digest, strings.Join(img.Tags, ", "), format.RelativeTime(img.CreatedAt))
+	}
+
+	if !flag.GetYes(ctx) {
+		switch confirmed, err := prompt.Confirm(ctx, "Are you sure you want to delete these images?"); {
+		case err == nil:
+			if !confirmed {
+				return nil
+			}
+		case prompt.IsNonInteractive(err):
+			return prompt.NonInteractiveError("yes flag must be specified when not running interactively")
+		default:
+			return err
+		}
+	}
+
+	registry := newRegistryClient(ctx, appName)
+	for _, img := range toDelete {
+		if err := registry.Delete(ctx, img.Digest); err != nil {
+			return err
+		}
+		fmt.Fprintf(io.Out, "Deleted %s\n", img.Digest)
+	}
+
+	return nil
+}
+
+func selectImagesByTag(images []*registryImage, tags []string) ([]*registryImage, error) {
+	byTag := map[string]*registryImage{}
+	for _, img := range images {
+		for _, tag := range img.Tags {
+			byTag[tag] = img
+		}
+	}
+
+	var (
+		selected []*registryImage
+		seen     = map[string]bool{}
+	)
+	for _, tag := range tags {
+		img, ok := byTag[tag]
+		if !ok {
+			return nil, fmt.Errorf("tag %s: %w", tag, errTagNotFound)
+		}
+		if !seen[img.Digest] {
+			seen[img.Digest] = true
+			selected = append(selected, img)
+		}
+	}
+
+	return selected, nil
+}
+
+// selectImagesByAge expects images to be sorted newest first.
+func selectImagesByAge(images []*registryImage, before time.Time, keep int) (selected []*registryImage) {
+	for i, img := range images {
+		if i < keep || img.CreatedAt.IsZero() || !img.CreatedAt.Before(before) {
+			continue
+		}
+		selected = append(selected, img)
+	}
+
+	return
+}
+
+// imagesInUse returns the set of image digests referenced by the app's
+// machines.
+func imagesInUse(ctx context.Context, appName string) (map[string]bool, error) {
+	flapsClient, err := flaps.NewFromAppName(ctx, appName)
+	if err != nil {
+		return nil, err
+	}
+
+	machines, err := flapsClient.List(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed retrieving machines: %w", err)
+	}
+
+	inUse := map[string]bool{}
+	for _, m := range machines {
+		if m.ImageRef.Digest != "" {
+			inUse[m.ImageRef.Digest] = true
+		}
+	}
+
+	return inUse, nil
+}
@@ -0,0 +1,56 @@
+package image
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testImages(now time.Time) []*registryImage {
+	return []*registryImage{
+		{Digest: "sha256:3", Tags: []string{"latest", "v3"}, CreatedAt: now.Add(-1 * time.Hour)},
+		{Digest: "sha256:2", Tags: []string{"v2"}, CreatedAt: now.Add(-48 * time.Hour)},
+		{Digest: "sha256:x", Tags: []string{"unknown"}},
+		{Digest: "sha256:1", Tags: []string{"v1"}, CreatedAt: now.Add(-96 * time.Hour)},
+	}
+}
+
+func TestSelectImagesByTag(t *testing.T) {
+	images := testImages(time.Now())
+
+	selected, err := selectImagesByTag(images, []string{"v1", "latest", "v3"})
+	require.NoError(t, err)
+	// tags sharing a digest select it once
+	assert.Equal(t, []*registryImage{images[3], images[0]}, selected)
+
+	_, err = selectImagesByTag(images, []string{"v1", "v4"})
+	assert.ErrorIs(t, err, errTagNotFound)
+	assert.ErrorContains(t, err, "tag v4")
+}
+
+func TestSelectImagesByAge(t *testing.T) {
+	now := time.Now()
+	images := testImages(now)
+
+	cases := []struct {
+		name     string
+		before   time.Time
+		keep     int
+		expected []*registryImage
+	}{
+		{name: "older than a day", before: now.Add(-24 * time.Hour), expected: []*registryImage{images[1], images[3]}},
+		{name: "older than three days", before: now.Add(-72 * time.Hour), expected: []*registryImage{images[3]}},
+		{name: "keep the newest two", before: now, keep: 2, expected: []*registryImage{images[3]}},
+		{name: "keep them all", before: now, keep: 4},
+		{name: "none old enough", before: now.Add(-240 * time.Hour)},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// images of unknown age are never selected
+			assert.Equal(t, tc.expected, selectImagesByAge(images, tc.before, tc.keep))
+		})
+	}
+}
@@ -23,6 +23,9 @@ func New() *cobra.Command {
 	cmd.AddCommand(
 		newShow(),
 		newUpdate(),
+		newList(),
+		newTags(),
+		newDelete(),
 	)
 
 	return cmd
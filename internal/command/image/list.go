@@ -0,0 +1,100 @@
+package image
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/format"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newList() *cobra.Command {
+	const (
+		long = `List the images stored in the app's repository on the Fly registry,
+along with their tags, sizes and creation dates.
+`
+		short = "List images in the app's registry repository"
+
+		usage = "list"
+	)
+
+	cmd := command.New(usage, short, long, runList,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	cmd.Aliases = []string{"ls"}
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.JSONOutput(),
+	)
+
+	return cmd
+}
+
+func runList(ctx context.Context) error {
+	var (
+		io      = iostreams.FromContext(ctx)
+		appName = appconfig.NameFromContext(ctx)
+	)
+
+	images, err := listRegistryImages(ctx, appName)
+	if err != nil {
+		return err
+	}
+
+	if config.FromContext(ctx).JSONOutput {
+		return render.Output(ctx, io.Out, images)
+	}
+
+	rows := make([][]string, 0, len(images))
+	for _, img := range images {
+		rows = append(rows, []string{
+			img.Digest,
+			strings.Join(img.Tags, ", "),
+			humanize.Bytes(uint64(img.Size)),
+			format.RelativeTime(img.CreatedAt),
+		})
+	}
+
+	return render.Table(io.Out, appName, rows, "Digest", "Tags", "Size", "Created")
+}
+
+// listRegistryImages returns the images in the app's repository, newest first.
+func listRegistryImages(ctx context.Context, appName string) ([]*registryImage, error) {
+	registry := newRegistryClient(ctx, appName)
+
+	tags, err := registry.Tags(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	io := iostreams.FromContext(ctx)
+	io.StartProgressIndicatorMsg("Inspecting images")
+	images, err := registry.Images(ctx, tags, func(done, total int) {
+		io.ChangeProgressIndicatorMsg(fmt.Sprintf("Inspecting images (%d/%d)", done, total))
+	})
+	io.StopProgressIndicator()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(images, func(i, j int) bool {
+		return images[i].CreatedAt.After(images[j].CreatedAt)
+	})
+
+	return images, nil
+}
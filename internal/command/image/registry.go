@@ -0,0 +1,351 @@
+package image
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/superfly/flyctl/internal/config"
+)
+
+const (
+	manifestV2MediaType  = "application/vnd.docker.distribution.manifest.v2+json"
+	ociManifestMediaType = "application/vnd.oci.image.manifest.v1+json"
+)
+
+// maxConcurrentRequests bounds the requests sent to the registry at once.
+const maxConcurrentRequests = 8
+
+var (
+	errTagNotFound = errors.New("tag not found")
+
+	// errNotFound is returned for any 404, which callers interpret.
+	errNotFound = errors.New("not found")
+)
+
+// registryClient talks to the Docker Registry HTTP API V2 of the Fly registry
+// on behalf of a single repository (an app).
+type registryClient struct {
+	host       string
+	repository string
+	token      string
+	httpClient *http.Client
+
+	mu     sync.Mutex // guards bearer
+	bearer string
+}
+
+func newRegistryClient(ctx context.Context, repository string) *registryClient {
+	cfg := config.FromContext(ctx)
+
+	return &registryClient{
+		host:       cfg.RegistryHost,
+		repository: repository,
+		token:      cfg.AccessToken,
+		httpClient: http.DefaultClient,
+	}
+}
+
+// registryImage describes a manifest in the repository along with every tag
+// that points at it.
+type registryImage struct {
+	Digest    string    `json:"digest"`
+	Tags      []string  `json:"tags"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type registryManifest struct {
+	Config struct {
+		Digest string `json:"digest"`
+		Size   int64  `json:"size"`
+	} `json:"config"`
+	Layers []struct {
+		Size int64 `json:"size"`
+	} `json:"layers"`
+}
+
+// Tags lists the tags of the repository, which is empty when nothing was ever
+// pushed to it.
+func (c *registryClient) Tags(ctx context.Context) ([]string, error) {
+	var out struct {
+		Tags []string `json:"tags"`
+	}
+
+	if _, err := c.do(ctx, http.MethodGet, "tags/list", nil, &out); err != nil {
+		if errors.Is(err, errNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed listing tags: %w", err)
+	}
+
+	return out.Tags, nil
+}
+
+// Images resolves tags to their manifests, grouping tags that share a digest.
+// Requests are sent concurrently, and progress, when set, is called with the
+// number of tags resolved so far.
+func (c *registryClient) Images(ctx context.Context, tags []string, progress func(done, total int)) ([]*registryImage, error) {
+	var (
+		manifests = make([]registryManifest, len(tags))
+		digests   = make([]string, len(tags))
+		mu        sync.Mutex
+		done      int
+	)
+
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(maxConcurrentRequests)
+	for i, tag := range tags {
+		i, tag := i, tag
+		eg.Go(func() error {
+			res, err := c.do(egCtx, http.MethodGet, "manifests/"+tag, map[string]string{
+				"Accept": manifestV2MediaType + ", " + ociManifestMediaType,
+			}, &manifests[i])
+			if errors.Is(err, errNotFound) {
+				err = errTagNotFound
+			}
+			if err != nil {
+				return fmt.Errorf("failed retrieving manifest for tag %s: %w", tag, err)
+			}
+			digests[i] = res.Header.Get("Docker-Content-Digest")
+
+			if progress != nil {
+				mu.Lock()
+				defer mu.Unlock()
+				done++
+				progress(done, len(tags))
+			}
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+
+	var (
+		images   []*registryImage
+		configs  []string
+		byDigest = map[string]*registryImage{}
+	)
+	for i, tag := range tags {
+		if img, ok := byDigest[digests[i]]; ok {
+			img.Tags = append(img.Tags, tag)
+			continue
+		}
+
+		img := &registryImage{
+			Digest: digests[i],
+			Tags:   []string{tag},
+			Size:   manifests[i].Config.Size,
+		}
+		for _, layer := range manifests[i].Layers {
+			img.Size += layer.Size
+		}
+
+		byDigest[digests[i]] = img
+		images = append(images, img)
+		configs = append(configs, manifests[i].Config.Digest)
+	}
+
+	// creation times are best effort, as they're only found in image configs
+	eg, egCtx = errgroup.WithContext(ctx)
+	eg.SetLimit(maxConcurrentRequests)
+	for i, img := range images {
+		img, config := img, configs[i]
+		eg.Go(func() error {
+			var imageConfig struct {
+				Created time.Time `json:"created"`
+			}
+			if _, err := c.do(egCtx, http.MethodGet, "blobs/"+config, nil, &imageConfig); err == nil {
+				img.CreatedAt = imageConfig.Created
+			}
+			return nil
+		})
+	}
+	_ = eg.Wait()
+
+	return images, nil
+}
+
+// Delete removes the manifest with the given digest, along with every tag
+// that references it.
+func (c *registryClient) Delete(ctx context.Context, digest string) error {
+	if _, err := c.do(ctx, http.MethodDelete, "manifests/"+digest, nil, nil); err != nil {
+		return fmt.Errorf("failed deleting %s: %w", digest, err)
+	}
+
+	return nil
+}
+
+func (c *registryClient) do(ctx context.Context, method, path string, headers map[string]string, out any) (*http.Response, error) {
+	endpoint := fmt.Sprintf("https://%s/v2/%s/%s", c.host, c.repository, path)
+
+	bearer := c.bearerToken()
+	res, err := c.send(ctx, method, endpoint, headers, bearer)
+	if err != nil {
+		return nil, err
+	}
+
+	// the registry may require exchanging our credentials for a bearer token
+	if res.StatusCode == http.StatusUnauthorized && bearer == "" {
+		challenge := res.Header.Get("WWW-Authenticate")
+		res.Body.Close()
+
+		if bearer, err = c.authenticate(ctx, challenge); err != nil {
+			return nil, err
+		}
+		if res, err = c.send(ctx, method, endpoint, headers, bearer); err != nil {
+			return nil, err
+		}
+	}
+	defer res.Body.Close() // skipcq: GO-S2307
+
+	switch {
+	case res.StatusCode == http.StatusNotFound:
+		return nil, errNotFound
+	case res.StatusCode >= 300:
+		body, _ := io.ReadAll(res.Body)
+		return nil, fmt.Errorf("registry responded with %s: %s", res.Status, strings.TrimSpace(string(body)))
+	}
+
+	if out != nil {
+		if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+			return nil, fmt.Errorf("failed decoding registry response: %w", err)
+		}
+	}
+
+	return res, nil
+}
+
+func (c *registryClient) send(ctx context.Context, method, endpoint string, headers map[string]string, bearer string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	} else {
+		req.SetBasicAuth("x", c.token)
+	}
+
+	return c.httpClient.Do(req)
+}
+
+func (c *registryClient) bearerToken() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.bearer
+}
+
+// authenticate performs the token exchange described by a Bearer
+// WWW-Authenticate challenge and returns the bearer token. Concurrent callers
+// share a single exchange.
+func (c *registryClient) authenticate(ctx context.Context, challenge string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.bearer != "" {
+		return c.bearer, nil
+	}
+
+	scheme, values := parseChallenge(challenge)
+	if !strings.EqualFold(scheme, "Bearer") {
+		return "", errors.New("registry rejected the provided credentials")
+	}
+
+	realm, err := url.Parse(values["realm"])
+	if err != nil || realm.String() == "" {
+		return "", fmt.Errorf("invalid registry auth challenge: %q", challenge)
+	}
+
+	q := realm.Query()
+	for _, k := range []string{"service", "scope"} {
+		if v := values[k]; v != "" {
+			q.Set(k, v)
+		}
+	}
+	realm.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	req.SetBasicAuth("x", c.token)
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed authenticating with registry: %w", err)
+	}
+	defer res.Body.Close() // skipcq: GO-S2307
+
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed authenticating with registry: %s", res.Status)
+	}
+
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("failed decoding registry token: %w", err)
+	}
+
+	if c.bearer = token.Token; c.bearer == "" {
+		c.bearer = token.AccessToken
+	}
+
+	return c.bearer, nil
+}
+
+// parseChallenge splits a WWW-Authenticate challenge into its scheme and its
+// auth params, keyed in lower case. Quoted values may contain commas and
+// backslash escapes, as in scope="repository:app:pull,push".
+func parseChallenge(challenge string) (scheme string, params map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+	params = map[string]string{}
+
+	for {
+		rest = strings.TrimLeft(rest, " \t,")
+		key, value, ok := strings.Cut(rest, "=")
+		if !ok {
+			return scheme, params
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimLeft(value, " \t")
+
+		if !strings.HasPrefix(value, `"`) {
+			value, rest, _ = strings.Cut(value, ",")
+			params[key] = strings.TrimSpace(value)
+			continue
+		}
+
+		var b strings.Builder
+		i := 1
+		for ; i < len(value) && value[i] != '"'; i++ {
+			if value[i] == '\\' && i+1 < len(value) {
+				i++
+			}
+			b.WriteByte(value[i])
+		}
+		if i < len(value) {
+			i++ // closing quote
+		}
+		params[key] = b.String()
+		rest = value[i:]
+	}
+}
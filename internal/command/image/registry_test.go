@@ -0,0 +1,248 @@
+package image
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseChallenge(t *testing.T) {
+	cases := []struct {
+		challenge string
+		scheme    string
+		params    map[string]string
+	}{
+		{
+			challenge: `Bearer realm="https://auth.example.com/token",service="registry.example.com",scope="repository:app:pull,push,delete"`,
+			scheme:    "Bearer",
+			params: map[string]string{
+				"realm":   "https://auth.example.com/token",
+				"service": "registry.example.com",
+				"scope":   "repository:app:pull,push,delete",
+			},
+		},
+		{
+			challenge: `Bearer Realm = "https://auth.example.com/token" , scope=repository:app:pull`,
+			scheme:    "Bearer",
+			params: map[string]string{
+				"realm": "https://auth.example.com/token",
+				"scope": "repository:app:pull",
+			},
+		},
+		{
+			challenge: `Bearer realm="a \"quoted\" realm",error="insufficient_scope"`,
+			scheme:    "Bearer",
+			params: map[string]string{
+				"realm": `a "quoted" realm`,
+				"error": "insufficient_scope",
+			},
+		},
+		{
+			challenge: `Bearer realm="unterminated`,
+			scheme:    "Bearer",
+			params:    map[string]string{"realm": "unterminated"},
+		},
+		{
+			challenge: `Basic realm="registry"`,
+			scheme:    "Basic",
+			params:    map[string]string{"realm": "registry"},
+		},
+		{
+			challenge: ``,
+			scheme:    "",
+			params:    map[string]string{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.challenge, func(t *testing.T) {
+			scheme, params := parseChallenge(tc.challenge)
+			assert.Equal(t, tc.scheme, scheme)
+			assert.Equal(t, tc.params, params)
+		})
+	}
+}
+
+// testRegistry serves the repository "app" from manifests keyed by tag, and
+// configs keyed by digest.
+type testRegistry struct {
+	t         *testing.T
+	server    *httptest.Server
+	manifests map[string]string
+	digests   map[string]string
+	configs   map[string]string
+
+	mu       sync.Mutex
+	requests []string
+}
+
+func newTestRegistry(t *testing.T) *testRegistry {
+	r := &testRegistry{
+		t:         t,
+		manifests: map[string]string{},
+		digests:   map[string]string{},
+		configs:   map[string]string{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, req *http.Request) {
+		if _, token, _ := req.BasicAuth(); token != "fly-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		assert.Equal(t, "repository:app:pull,push,delete", req.URL.Query().Get("scope"))
+		assert.Equal(t, "registry.example.com", req.URL.Query().Get("service"))
+		json.NewEncoder(w).Encode(map[string]string{"token": "bearer-token"})
+	})
+	mux.HandleFunc("/v2/app/", func(w http.ResponseWriter, req *http.Request) {
+		r.mu.Lock()
+		r.requests = append(r.requests, req.URL.Path)
+		r.mu.Unlock()
+
+		if req.Header.Get("Authorization") != "Bearer bearer-token" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="`+r.server.URL+`/token",service="registry.example.com",scope="repository:app:pull,push,delete"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		path := strings.TrimPrefix(req.URL.Path, "/v2/app/")
+		switch {
+		case path == "tags/list":
+			if len(r.manifests) == 0 {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			tags := make([]string, 0, len(r.manifests))
+			for tag := range r.manifests {
+				tags = append(tags, tag)
+			}
+			json.NewEncoder(w).Encode(map[string]any{"name": "app", "tags": tags})
+		case strings.HasPrefix(path, "manifests/"):
+			tag := strings.TrimPrefix(path, "manifests/")
+			manifest, ok := r.manifests[tag]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Docker-Content-Digest", r.digests[tag])
+			w.Write([]byte(manifest))
+		case strings.HasPrefix(path, "blobs/"):
+			config, ok := r.configs[strings.TrimPrefix(path, "blobs/")]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write([]byte(config))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	r.server = httptest.NewTLSServer(mux)
+	t.Cleanup(r.server.Close)
+
+	return r
+}
+
+func (r *testRegistry) client() *registryClient {
+	return &registryClient{
+		host:       strings.TrimPrefix(r.server.URL, "https://"),
+		repository: "app",
+		token:      "fly-token",
+		httpClient: r.server.Client(),
+	}
+}
+
+func (r *testRegistry) push(tag, digest, configDigest, created string, layers ...int64) {
+	manifest := registryManifest{}
+	manifest.Config.Digest = configDigest
+	manifest.Config.Size = 100
+	for _, size := range layers {
+		manifest.Layers = append(manifest.Layers, struct {
+			Size int64 `json:"size"`
+		}{size})
+	}
+
+	data, err := json.Marshal(manifest)
+	require.NoError(r.t, err)
+
+	r.manifests[tag] = string(data)
+	r.digests[tag] = digest
+	if created != "" {
+		r.configs[configDigest] = `{"created":"` + created + `"}`
+	}
+}
+
+func TestRegistryTagsAuthenticates(t *testing.T) {
+	registry := newTestRegistry(t)
+	registry.push("v1", "sha256:1", "sha256:c1", "")
+
+	tags, err := registry.client().Tags(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"v1"}, tags)
+
+	// the first request is challenged, and then retried with the token
+	assert.Equal(t, []string{"/v2/app/tags/list", "/v2/app/tags/list"}, registry.requests)
+}
+
+func TestRegistryTagsMissingRepository(t *testing.T) {
+	registry := newTestRegistry(t)
+
+	tags, err := registry.client().Tags(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, tags)
+}
+
+func TestRegistryTagsRejectedCredentials(t *testing.T) {
+	registry := newTestRegistry(t)
+	client := registry.client()
+	client.token = "wrong"
+
+	_, err := client.Tags(context.Background())
+	assert.ErrorContains(t, err, "failed authenticating with registry: 401")
+}
+
+func TestRegistryImages(t *testing.T) {
+	registry := newTestRegistry(t)
+	registry.push("v1", "sha256:1", "sha256:c1", "2023-05-01T08:00:00Z", 1000, 2000)
+	registry.push("latest", "sha256:2", "sha256:c2", "2023-05-02T08:00:00Z", 1000, 3000)
+	registry.push("v2", "sha256:2", "sha256:c2", "2023-05-02T08:00:00Z", 1000, 3000)
+	registry.push("v0", "sha256:0", "sha256:c0", "", 500)
+
+	var (
+		mu       sync.Mutex
+		progress []int
+	)
+	images, err := registry.client().Images(context.Background(), []string{"v1", "latest", "v2", "v0"}, func(done, total int) {
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, 4, total)
+		progress = append(progress, done)
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []*registryImage{
+		{Digest: "sha256:1", Tags: []string{"v1"}, Size: 3100, CreatedAt: time.Date(2023, 5, 1, 8, 0, 0, 0, time.UTC)},
+		{Digest: "sha256:2", Tags: []string{"latest", "v2"}, Size: 4100, CreatedAt: time.Date(2023, 5, 2, 8, 0, 0, 0, time.UTC)},
+		// the config could not be found, so the creation time is unknown
+		{Digest: "sha256:0", Tags: []string{"v0"}, Size: 600},
+	}, images)
+	assert.Equal(t, []int{1, 2, 3, 4}, progress)
+}
+
+func TestRegistryImagesMissingTag(t *testing.T) {
+	registry := newTestRegistry(t)
+	registry.push("v1", "sha256:1", "sha256:c1", "")
+
+	_, err := registry.client().Images(context.Background(), []string{"v1", "gone"}, nil)
+	assert.ErrorIs(t, err, errTagNotFound)
+	assert.ErrorContains(t, err, "failed retrieving manifest for tag gone")
+}
@@ -94,7 +94,7 @@ func showNomadImage(ctx context.Context, app *api.AppCompact) error {
 	}
 
 	if cfg.JSONOutput {
-		return render.JSON(io.Out, info.ImageDetails)
+		return render.Output(ctx, io.Out, info.ImageDetails)
 	}
 
 	if info.ImageVersionTrackingEnabled && info.ImageUpgradeAvailable {
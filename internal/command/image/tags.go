@@ -0,0 +1,63 @@
+package image
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newTags() *cobra.Command {
+	const (
+		long  = "List the tags in the app's repository on the Fly registry.\n"
+		short = "List tags in the app's registry repository"
+
+		usage = "tags"
+	)
+
+	cmd := command.New(usage, short, long, runTags,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.JSONOutput(),
+	)
+
+	return cmd
+}
+
+func runTags(ctx context.Context) error {
+	var (
+		io      = iostreams.FromContext(ctx)
+		appName = appconfig.NameFromContext(ctx)
+	)
+
+	tags, err := newRegistryClient(ctx, appName).Tags(ctx)
+	if err != nil {
+		return err
+	}
+	sort.Strings(tags)
+
+	if config.FromContext(ctx).JSONOutput {
+		return render.Output(ctx, io.Out, tags)
+	}
+
+	for _, tag := range tags {
+		fmt.Fprintln(io.Out, tag)
+	}
+
+	return nil
+}
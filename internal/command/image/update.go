@@ -16,7 +16,10 @@ import (
 func newUpdate() *cobra.Command {
 	const (
 		long = `This will update the application's image to the latest available version.
-The update will perform a rolling restart against each VM, which may result in a brief service disruption.`
+The update will perform a rolling restart against each VM, which may result in a brief service disruption.
+The update stops at the first VM failing to update or become healthy; pass --rollback to also roll the VMs
+updated so far back to their previous image. Use --deploy-batch-size and --max-unavailable to update VMs
+in parallel waves.`
 		short = "Updates the app's image to the latest available version. (Fly Postgres only)"
 		usage = "update"
 	)
@@ -49,6 +52,19 @@ The update will perform a rolling restart against each VM, which may result in a
 			Description: "Skip waiting for health checks inbetween VM updates. (Machines only)",
 			Default:     false,
 		},
+		flag.Bool{
+			Name:        "rollback",
+			Description: "Roll machines updated so far back to their previous image when one fails to update. (Machines only)",
+		},
+		flag.Int{
+			Name:        "deploy-batch-size",
+			Description: "Number of machines to update at once. (Machines only)",
+			Default:     1,
+		},
+		flag.String{
+			Name:        "max-unavailable",
+			Description: "Maximum number of machines updated at once, as a number or a percentage of the machines, e.g. 25%. (Machines only)",
+		},
 	)
 
 	return cmd
@@ -29,7 +29,7 @@ func updateImageForMachines(ctx context.Context, app *api.AppCompact) error {
 		return err
 	}
 
-	eligible := map[*api.Machine]api.MachineConfig{}
+	var targets []mach.RollingUpdateTarget
 
 	// Loop through machines and compare/confirm changes.
 	for _, machine := range machines {
@@ -43,31 +43,26 @@ func updateImageForMachines(ctx context.Context, app *api.AppCompact) error {
 
 		machineConf.Image = image
 
-		if !autoConfirm {
-			confirmed, err := mach.ConfirmConfigChanges(ctx, machine, *machineConf, "")
-			if err != nil {
-				return err
-			}
-			if !confirmed {
-				continue
-			}
-		}
-
-		eligible[machine] = *machineConf
+		targets = append(targets, mach.RollingUpdateTarget{
+			Machine: machine,
+			Input: &api.LaunchMachineInput{
+				ID:               machine.ID,
+				AppID:            app.Name,
+				OrgSlug:          app.Organization.Slug,
+				Region:           machine.Region,
+				Config:           machineConf,
+				SkipHealthChecks: skipHealthChecks,
+			},
+		})
 	}
 
-	for machine, machineConf := range eligible {
-		input := &api.LaunchMachineInput{
-			ID:               machine.ID,
-			AppID:            app.Name,
-			OrgSlug:          app.Organization.Slug,
-			Region:           machine.Region,
-			Config:           &machineConf,
-			SkipHealthChecks: skipHealthChecks,
-		}
-		if err := mach.Update(ctx, machine, input); err != nil {
-			return err
-		}
+	if err := mach.RollingUpdate(ctx, targets, mach.RollingUpdateOptions{
+		Rollback:       flag.GetBool(ctx, "rollback"),
+		BatchSize:      flag.GetInt(ctx, "deploy-batch-size"),
+		MaxUnavailable: flag.GetString(ctx, "max-unavailable"),
+		AutoConfirm:    autoConfirm,
+	}); err != nil {
+		return err
 	}
 
 	fmt.Fprintln(io.Out, "Machines successfully updated")
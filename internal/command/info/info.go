@@ -26,8 +26,10 @@ func New() *cobra.Command {
 	)
 
 	cmd.Args = cobra.NoArgs
-	cmd.Hidden = true
-	cmd.Deprecated = "Replaced by 'status', 'ips list', and 'services list'"
+	command.Deprecate(cmd, command.Deprecation{
+		Replacement: "fly status",
+		Message:     "Use 'fly ips list' and 'fly services list' for the rest of the information.",
+	})
 
 	flag.Add(cmd,
 		flag.App(),
@@ -8,7 +8,7 @@ import (
 	"github.com/superfly/flyctl/client"
 	"github.com/superfly/flyctl/internal/command/apps"
 	"github.com/superfly/flyctl/internal/command/services"
-	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/config"
 	"github.com/superfly/flyctl/internal/render"
 	"github.com/superfly/flyctl/iostreams"
 )
@@ -16,7 +16,7 @@ import (
 func showMachineInfo(ctx context.Context, appName string) error {
 	var (
 		client    = client.FromContext(ctx).API()
-		jsonOuput = flag.GetBool(ctx, "json")
+		jsonOuput = config.FromContext(ctx).JSONOutput
 	)
 
 	if jsonOuput {
@@ -6,19 +6,19 @@ import (
 	"strings"
 
 	"github.com/superfly/flyctl/api"
-	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/config"
 	"github.com/superfly/flyctl/internal/render"
 	"github.com/superfly/flyctl/iostreams"
 )
 
 func showNomadInfo(ctx context.Context, info *api.AppInfo) error {
 	var (
-		jsonOutput = flag.GetBool(ctx, "json")
+		jsonOutput = config.FromContext(ctx).JSONOutput
 		io         = iostreams.FromContext(ctx)
 	)
 
 	if jsonOutput {
-		if err := render.JSON(io.Out, info); err != nil {
+		if err := render.Output(ctx, io.Out, info); err != nil {
 			return err
 		}
 		return nil
@@ -0,0 +1,102 @@
+package infra
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/command/orgs"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newExport() *cobra.Command {
+	const (
+		long = `Generates code describing the machines, volumes, IP addresses and
+certificates of the app as they're currently deployed, for the fly Terraform
+provider or a TypeScript Pulumi program using its Pulumi bridge.
+
+The code imports the existing app, volumes and machines rather than creating
+new ones, so that it may be adopted without downtime. IP addresses and
+certificates have to be imported by hand.
+`
+		short = "Generate Terraform or Pulumi code from the deployed app"
+	)
+
+	cmd := command.New("export", short, long, runExport,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.String{
+			Name:        "format",
+			Description: "The format of the code, terraform or pulumi",
+			Default:     "terraform",
+		},
+		flag.String{
+			Name:        "output",
+			Shorthand:   "o",
+			Description: "Write the code to this file instead of stdout",
+		},
+	)
+
+	return cmd
+}
+
+func runExport(ctx context.Context) (err error) {
+	var (
+		appName = appconfig.NameFromContext(ctx)
+		format  = flag.GetString(ctx, "format")
+	)
+
+	var write func(w io.Writer, resources []resource) error
+	switch format {
+	case "terraform":
+		write = writeTerraform
+	case "pulumi":
+		write = writePulumi
+	default:
+		return fmt.Errorf("unsupported format %q, use terraform or pulumi", format)
+	}
+
+	app, err := client.FromContext(ctx).API().GetApp(ctx, appName)
+	if err != nil {
+		return fmt.Errorf("failed fetching app: %w", err)
+	}
+	if app.PlatformVersion != appconfig.MachinesPlatform {
+		return fmt.Errorf("only apps on the machines platform can be exported, %s is on %s", app.Name, app.PlatformVersion)
+	}
+
+	export, err := orgs.ExportApp(ctx, *app)
+	if err != nil {
+		return fmt.Errorf("failed exporting app %s: %w", app.Name, err)
+	}
+
+	out := iostreams.FromContext(ctx).Out
+	if path := flag.GetString(ctx, "output"); path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("failed creating output file: %w", err)
+		}
+		defer func() {
+			if e := f.Close(); err == nil {
+				err = e
+			}
+		}()
+
+		out = f
+	}
+
+	return write(out, buildResources(app.Organization.Slug, export))
+}
@@ -0,0 +1,24 @@
+// Package infra implements the infra command chain.
+package infra
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/internal/command"
+)
+
+// New initializes and returns a new infra Command.
+func New() *cobra.Command {
+	const (
+		short = "Describe apps as infrastructure as code"
+		long  = short + "\n"
+	)
+
+	cmd := command.New("infra", short, long, nil)
+
+	cmd.AddCommand(
+		newExport(),
+	)
+
+	return cmd
+}
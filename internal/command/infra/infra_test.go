@@ -0,0 +1,105 @@
+package infra
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/internal/command/orgs"
+)
+
+func testExport() *orgs.AppExport {
+	return &orgs.AppExport{
+		Name: "my-app",
+		Volumes: []orgs.VolumeExport{
+			{ID: "vol_1", Name: "data", Region: "ams", SizeGb: 1},
+			{ID: "vol_2", Name: "data", Region: "ams", SizeGb: 1},
+		},
+		IPAddresses: []orgs.IPAddressExport{
+			{Type: "v6", Address: "2a09::1", Region: "global"},
+			{Type: "private_v6", Address: "fdaa::1"},
+		},
+		Certificates: []orgs.CertificateExport{{Hostname: "example.com"}},
+		Machines: []orgs.MachineExport{{
+			ID:     "148e",
+			Name:   "purple-sun-1",
+			Region: "ams",
+			Config: &api.MachineConfig{
+				Image: "registry.fly.io/my-app:v1",
+				Env:   map[string]string{"PORT": "8080", "GREETING": "${hi}"},
+				Guest: &api.MachineGuest{CPUKind: "shared", CPUs: 1, MemoryMB: 256},
+				Mounts: []api.MachineMount{
+					{Volume: "vol_2", Path: "/data"},
+				},
+				Services: []api.MachineService{{
+					Protocol:     "tcp",
+					InternalPort: 8080,
+					Ports: []api.MachinePort{
+						{Port: api.IntPointer(443), Handlers: []string{"tls", "http"}},
+						{StartPort: api.IntPointer(1000), EndPort: api.IntPointer(2000)},
+					},
+				}},
+			},
+		}},
+	}
+}
+
+func TestBuildResources(t *testing.T) {
+	resources := buildResources("personal", testExport())
+
+	var names []string
+	for _, r := range resources {
+		names = append(names, r.kind+"."+r.name)
+	}
+	assert.Equal(t, []string{"app.my_app", "volume.data", "volume.data_2", "ip.v6", "cert.example_com", "machine.purple_sun_1"}, names)
+
+	machine := resources[len(resources)-1]
+	assert.Equal(t, "my-app,148e", machine.importID)
+	for _, a := range machine.attrs {
+		if a.key == "mounts" {
+			assert.Equal(t, ref{"volume", "data_2", "id"}, a.value.([]object)[0][1].value)
+		}
+	}
+}
+
+func TestWriteTerraform(t *testing.T) {
+	var b strings.Builder
+	require.NoError(t, writeTerraform(&b, buildResources("personal", testExport())))
+	out := b.String()
+
+	assert.Contains(t, out, `source = "fly-apps/fly"`)
+	assert.Contains(t, out, "resource \"fly_volume\" \"data_2\" {\n  app    = fly_app.my_app.name\n  name   = \"data\"\n  size   = 1\n  region = \"ams\"\n}")
+	assert.Contains(t, out, `"GREETING" = "$${hi}"`)
+	assert.Contains(t, out, "      volume = fly_volume.data_2.id\n")
+	assert.Contains(t, out, `handlers = ["tls", "http"]`)
+	assert.Contains(t, out, "import {\n  to = fly_machine.purple_sun_1\n  id = \"my-app,148e\"\n}")
+	assert.NotContains(t, out, "fdaa::1")
+	assert.NotContains(t, out, "1000")
+}
+
+func TestWritePulumi(t *testing.T) {
+	var b strings.Builder
+	require.NoError(t, writePulumi(&b, buildResources("personal", testExport())))
+	out := b.String()
+
+	assert.Contains(t, out, `import * as fly from "@pulumi/fly";`)
+	assert.Contains(t, out, "const app_my_app = new fly.App(\"my_app\", {\n    name: \"my-app\",\n    org: \"personal\",\n}, { import: \"my-app\" });")
+	assert.Contains(t, out, "app: app_my_app.name,")
+	assert.Contains(t, out, "volume: volume_data_2.id,")
+	assert.Contains(t, out, "internalPort: 8080,")
+	assert.Contains(t, out, `"GREETING": "${hi}",`)
+	assert.Contains(t, out, "const cert_example_com = new fly.Cert(\"example_com\", {")
+}
+
+func TestIdentifier(t *testing.T) {
+	assert.Equal(t, "my_app", identifier("My-App"))
+	assert.Equal(t, "_148e", identifier("148e"))
+	assert.Equal(t, "_", identifier(""))
+}
+
+func TestCamelCase(t *testing.T) {
+	assert.Equal(t, "internalPort", camelCase("internal_port"))
+	assert.Equal(t, "memorymb", camelCase("memorymb"))
+}
@@ -0,0 +1,119 @@
+package infra
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// writePulumi renders the resources as a TypeScript Pulumi program for the
+// fly provider, importing the existing resources.
+func writePulumi(w io.Writer, resources []resource) error {
+	var b strings.Builder
+
+	b.WriteString("import * as fly from \"@pulumi/fly\";\n\n")
+
+	for _, r := range resources {
+		if r.comment != "" {
+			fmt.Fprintf(&b, "// %s\n", r.comment)
+		}
+
+		fmt.Fprintf(&b, "const %s = new fly.%s(%s, %s", pulumiVariable(r.kind, r.name),
+			providerKinds[r.kind].pulumi, tsString(r.name), tsObject(r.attrs, 0))
+		if r.importID != "" {
+			fmt.Fprintf(&b, ", { import: %s }", tsString(r.importID))
+		}
+		b.WriteString(");\n\n")
+	}
+
+	_, err := io.WriteString(w, strings.TrimSuffix(b.String(), "\n"))
+	return err
+}
+
+// pulumiVariable names the variable holding the resource, prefixed by its kind
+// since resources of different kinds share the same namespace.
+func pulumiVariable(kind, name string) string {
+	return kind + "_" + strings.TrimPrefix(name, "_")
+}
+
+func tsValue(v any, depth int) string {
+	switch v := v.(type) {
+	case string:
+		return tsString(v)
+	case int:
+		return strconv.Itoa(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case ref:
+		return pulumiVariable(v.kind, v.name) + "." + camelCase(v.attr)
+	case []string:
+		quoted := make([]string, len(v))
+		for i, s := range v {
+			quoted[i] = tsString(s)
+		}
+		return "[" + strings.Join(quoted, ", ") + "]"
+	case map[string]string:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		// keys are quoted since they aren't attributes of the provider
+		var b strings.Builder
+		b.WriteString("{\n")
+		for _, k := range keys {
+			fmt.Fprintf(&b, "%s%s: %s,\n", strings.Repeat("    ", depth+1), tsString(k), tsString(v[k]))
+		}
+		b.WriteString(strings.Repeat("    ", depth) + "}")
+		return b.String()
+	case object:
+		return tsObject(v, depth)
+	case []object:
+		if len(v) == 0 {
+			return "[]"
+		}
+
+		var b strings.Builder
+		b.WriteString("[\n")
+		for _, o := range v {
+			fmt.Fprintf(&b, "%s%s,\n", strings.Repeat("    ", depth+1), tsObject(o, depth+1))
+		}
+		b.WriteString(strings.Repeat("    ", depth) + "]")
+		return b.String()
+	default:
+		panic(fmt.Sprintf("unsupported TypeScript value of type %T", v))
+	}
+}
+
+func tsObject(attrs []attr, depth int) string {
+	var b strings.Builder
+	b.WriteString("{\n")
+	for _, a := range attrs {
+		fmt.Fprintf(&b, "%s%s: %s,\n", strings.Repeat("    ", depth+1), camelCase(a.key), tsValue(a.value, depth+1))
+	}
+	b.WriteString(strings.Repeat("    ", depth) + "}")
+	return b.String()
+}
+
+// tsString quotes the string as a JSON string, which is a valid TypeScript
+// string literal.
+func tsString(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
+
+// camelCase converts the snake case attributes of the Terraform provider to
+// the camel case of its Pulumi bridge.
+func camelCase(s string) string {
+	parts := strings.Split(s, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
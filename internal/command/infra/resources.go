@@ -0,0 +1,250 @@
+package infra
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/internal/command/orgs"
+)
+
+// resource is a resource of the fly provider, which both the Terraform and
+// Pulumi generators render.
+type resource struct {
+	// kind is the type of the resource without its provider prefix: app,
+	// volume, ip, cert or machine.
+	kind string
+	// name identifies the resource within the generated code.
+	name string
+	// importID adopts the existing resource instead of creating a new one.
+	importID string
+	// comment describes the resource when there's more to it than its
+	// attributes.
+	comment string
+	attrs   []attr
+}
+
+type attr struct {
+	key   string
+	value any
+}
+
+// object is a nested value of a resource, like the services of machines.
+type object []attr
+
+// ref refers to an attribute of another resource.
+type ref struct {
+	kind, name, attr string
+}
+
+// providerKinds are the names of the types of resources in the providers.
+var providerKinds = map[string]struct{ terraform, pulumi string }{
+	"app":     {"fly_app", "App"},
+	"volume":  {"fly_volume", "Volume"},
+	"ip":      {"fly_ip", "Ip"},
+	"cert":    {"fly_cert", "Cert"},
+	"machine": {"fly_machine", "Machine"},
+}
+
+// buildResources returns the resources describing the app, in the order in
+// which they depend on each other.
+func buildResources(org string, app *orgs.AppExport) []resource {
+	var (
+		names     = names{}
+		appRef    = ref{"app", identifier(app.Name), "name"}
+		resources []resource
+		volumes   = map[string]ref{}
+	)
+
+	resources = append(resources, resource{
+		kind:     "app",
+		name:     names.unique("app", app.Name),
+		importID: app.Name,
+		attrs: []attr{
+			{"name", app.Name},
+			{"org", org},
+		},
+	})
+
+	for _, v := range app.Volumes {
+		name := names.unique("volume", v.Name)
+		volumes[v.ID] = ref{"volume", name, "id"}
+
+		resources = append(resources, resource{
+			kind:     "volume",
+			name:     name,
+			importID: app.Name + "," + v.ID,
+			comment:  v.ID,
+			attrs: []attr{
+				{"app", appRef},
+				{"name", v.Name},
+				{"size", v.SizeGb},
+				{"region", v.Region},
+			},
+		})
+	}
+
+	for _, ip := range app.IPAddresses {
+		if ip.Type != "v4" && ip.Type != "v6" {
+			continue
+		}
+
+		attrs := []attr{
+			{"app", appRef},
+			{"type", ip.Type},
+		}
+		if ip.Region != "" && ip.Region != "global" {
+			attrs = append(attrs, attr{"region", ip.Region})
+		}
+
+		resources = append(resources, resource{
+			kind:    "ip",
+			name:    names.unique("ip", ip.Type),
+			comment: ip.Address,
+			attrs:   attrs,
+		})
+	}
+
+	for _, cert := range app.Certificates {
+		resources = append(resources, resource{
+			kind: "cert",
+			name: names.unique("cert", cert.Hostname),
+			attrs: []attr{
+				{"app", appRef},
+				{"hostname", cert.Hostname},
+			},
+		})
+	}
+
+	for _, m := range app.Machines {
+		if m.Config == nil {
+			continue
+		}
+
+		name := m.Name
+		if name == "" {
+			name = m.ID
+		}
+
+		resources = append(resources, resource{
+			kind:     "machine",
+			name:     names.unique("machine", name),
+			importID: app.Name + "," + m.ID,
+			comment:  m.ID,
+			attrs:    machineAttrs(appRef, m, volumes),
+		})
+	}
+
+	return resources
+}
+
+func machineAttrs(appRef ref, m orgs.MachineExport, volumes map[string]ref) []attr {
+	config := m.Config
+
+	attrs := []attr{
+		{"app", appRef},
+		{"name", m.Name},
+		{"region", m.Region},
+		{"image", config.Image},
+	}
+
+	if len(config.Env) > 0 {
+		attrs = append(attrs, attr{"env", config.Env})
+	}
+	if len(config.Init.Cmd) > 0 {
+		attrs = append(attrs, attr{"cmd", config.Init.Cmd})
+	}
+	if len(config.Init.Entrypoint) > 0 {
+		attrs = append(attrs, attr{"entrypoint", config.Init.Entrypoint})
+	}
+	if len(config.Init.Exec) > 0 {
+		attrs = append(attrs, attr{"exec", config.Init.Exec})
+	}
+
+	if guest := config.Guest; guest != nil {
+		attrs = append(attrs,
+			attr{"cputype", guest.CPUKind},
+			attr{"cpus", guest.CPUs},
+			attr{"memorymb", guest.MemoryMB},
+		)
+	}
+
+	if len(config.Mounts) > 0 {
+		mounts := make([]object, 0, len(config.Mounts))
+		for _, mount := range config.Mounts {
+			var volume any = mount.Volume
+			if r, ok := volumes[mount.Volume]; ok {
+				volume = r
+			}
+			mounts = append(mounts, object{{"path", mount.Path}, {"volume", volume}})
+		}
+		attrs = append(attrs, attr{"mounts", mounts})
+	}
+
+	if len(config.Services) > 0 {
+		services := make([]object, 0, len(config.Services))
+		for _, s := range config.Services {
+			services = append(services, object{
+				{"protocol", s.Protocol},
+				{"internal_port", s.InternalPort},
+				{"ports", servicePorts(s.Ports)},
+			})
+		}
+		attrs = append(attrs, attr{"services", services})
+	}
+
+	return attrs
+}
+
+func servicePorts(ports []api.MachinePort) []object {
+	objects := make([]object, 0, len(ports))
+	for _, p := range ports {
+		if p.Port == nil {
+			// port ranges aren't supported by the providers
+			continue
+		}
+
+		port := object{{"port", *p.Port}}
+		if len(p.Handlers) > 0 {
+			port = append(port, attr{"handlers", p.Handlers})
+		}
+		objects = append(objects, port)
+	}
+
+	return objects
+}
+
+// names hands out unique identifiers by kind of resource.
+type names map[string]int
+
+func (n names) unique(kind, name string) string {
+	id := identifier(name)
+
+	key := kind + "." + id
+	n[key]++
+	if count := n[key]; count > 1 {
+		return fmt.Sprintf("%s_%d", id, count)
+	}
+
+	return id
+}
+
+// identifier turns the name into one valid in both HCL and TypeScript.
+func identifier(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+
+	id := b.String()
+	if id == "" || (id[0] >= '0' && id[0] <= '9') {
+		id = "_" + id
+	}
+
+	return id
+}
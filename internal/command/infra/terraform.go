@@ -0,0 +1,123 @@
+package infra
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const terraformHeader = `terraform {
+  required_providers {
+    fly = {
+      source = "fly-apps/fly"
+    }
+  }
+}
+
+`
+
+// writeTerraform renders the resources as HCL for the fly Terraform provider,
+// along with import blocks adopting the existing resources.
+func writeTerraform(w io.Writer, resources []resource) error {
+	var b strings.Builder
+
+	b.WriteString(terraformHeader)
+
+	for _, r := range resources {
+		if r.comment != "" {
+			fmt.Fprintf(&b, "# %s\n", r.comment)
+		}
+		fmt.Fprintf(&b, "resource %q %q {\n", providerKinds[r.kind].terraform, r.name)
+		writeHCLAttrs(&b, r.attrs, 1)
+		b.WriteString("}\n\n")
+	}
+
+	for _, r := range resources {
+		if r.importID == "" {
+			continue
+		}
+		fmt.Fprintf(&b, "import {\n  to = %s.%s\n  id = %s\n}\n\n", providerKinds[r.kind].terraform, r.name, hclString(r.importID))
+	}
+
+	_, err := io.WriteString(w, strings.TrimSuffix(b.String(), "\n"))
+	return err
+}
+
+func writeHCLAttrs(b *strings.Builder, attrs []attr, depth int) {
+	indent := strings.Repeat("  ", depth)
+
+	width := 0
+	for _, a := range attrs {
+		if len(a.key) > width {
+			width = len(a.key)
+		}
+	}
+
+	for _, a := range attrs {
+		fmt.Fprintf(b, "%s%-*s = %s\n", indent, width, a.key, hclValue(a.value, depth))
+	}
+}
+
+func hclValue(v any, depth int) string {
+	switch v := v.(type) {
+	case string:
+		return hclString(v)
+	case int:
+		return strconv.Itoa(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case ref:
+		return fmt.Sprintf("%s.%s.%s", providerKinds[v.kind].terraform, v.name, v.attr)
+	case []string:
+		quoted := make([]string, len(v))
+		for i, s := range v {
+			quoted[i] = hclString(s)
+		}
+		return "[" + strings.Join(quoted, ", ") + "]"
+	case map[string]string:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		attrs := make([]attr, 0, len(keys))
+		for _, k := range keys {
+			attrs = append(attrs, attr{hclString(k), v[k]})
+		}
+		return hclObject(attrs, depth)
+	case object:
+		return hclObject(v, depth)
+	case []object:
+		if len(v) == 0 {
+			return "[]"
+		}
+
+		var b strings.Builder
+		b.WriteString("[\n")
+		for _, o := range v {
+			fmt.Fprintf(&b, "%s%s,\n", strings.Repeat("  ", depth+1), hclObject(o, depth+1))
+		}
+		b.WriteString(strings.Repeat("  ", depth) + "]")
+		return b.String()
+	default:
+		panic(fmt.Sprintf("unsupported HCL value of type %T", v))
+	}
+}
+
+func hclObject(attrs []attr, depth int) string {
+	var b strings.Builder
+	b.WriteString("{\n")
+	writeHCLAttrs(&b, attrs, depth+1)
+	b.WriteString(strings.Repeat("  ", depth) + "}")
+	return b.String()
+}
+
+// hclString quotes the string, escaping the template sequences of HCL.
+func hclString(s string) string {
+	s = strconv.Quote(s)
+	s = strings.ReplaceAll(s, "${", "$${")
+	return strings.ReplaceAll(s, "%{", "%%{")
+}
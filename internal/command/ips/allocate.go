@@ -2,6 +2,7 @@ package ips
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/spf13/cobra"
 	"github.com/superfly/flyctl/api"
@@ -10,6 +11,7 @@ import (
 	"github.com/superfly/flyctl/internal/command"
 	"github.com/superfly/flyctl/internal/command/orgs"
 	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/policy"
 	"github.com/superfly/flyctl/internal/prompt"
 )
 
@@ -74,6 +76,8 @@ func runAllocateIPAddressV4(ctx context.Context) error {
 	addrType := "v4"
 	if flag.GetBool(ctx, "shared") {
 		addrType = "shared_v4"
+	} else if err := checkPolicy(ctx, addrType); err != nil {
+		return err
 	} else if !flag.GetBool(ctx, "yes") {
 		switch confirmed, err := prompt.Confirm(ctx, "Looks like you're accessing a paid feature. Dedicated IPv4 addresses now costs $2/mo. Are you ok with this?"); {
 		case err == nil:
@@ -137,3 +141,21 @@ func runAllocateIPAddress(ctx context.Context, addrType string, org *api.Organiz
 	renderListTable(ctx, ipAddresses)
 	return nil
 }
+
+// checkPolicy returns an error if the policy of the organization of the app
+// forbids allocating addresses of the type.
+func checkPolicy(ctx context.Context, addrType string) error {
+	appName := appconfig.NameFromContext(ctx)
+
+	app, err := client.FromContext(ctx).API().GetAppCompact(ctx, appName)
+	if err != nil {
+		return fmt.Errorf("failed retrieving app %s: %w", appName, err)
+	}
+
+	p, err := policy.Load(ctx, app.Organization.Slug)
+	if err != nil {
+		return err
+	}
+
+	return p.Error(p.IPViolations(addrType))
+}
@@ -0,0 +1,128 @@
+package ips
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newEgress() *cobra.Command {
+	const (
+		long = `Lists the public addresses the machines of the application connect to the
+internet from, to allow them through the firewalls of third parties. The
+addresses are those of the hosts running the machines, observed from inside
+each started machine, so they change when a machine moves to another host.`
+		short = `List the egress IP addresses of the machines`
+	)
+
+	cmd := command.New("egress", short, long, runEgress,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.JSONOutput(),
+	)
+
+	cmd.Args = cobra.NoArgs
+	return cmd
+}
+
+// egressScript prints the IPv4 then the IPv6 egress address of a machine,
+// each on a line, with whichever of wget and curl the image ships.
+const egressScript = `for u in https://api.ipify.org https://api6.ipify.org; do (wget -qO- -T 5 $u || curl -fsS -m 5 $u) 2>/dev/null; echo; done`
+
+type machineEgress struct {
+	MachineID string `json:"machine_id"`
+	Region    string `json:"region"`
+	IPv4      string `json:"ipv4,omitempty"`
+	IPv6      string `json:"ipv6,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+func runEgress(ctx context.Context) error {
+	var (
+		io      = iostreams.FromContext(ctx)
+		appName = appconfig.NameFromContext(ctx)
+	)
+
+	app, err := client.FromContext(ctx).API().GetAppCompact(ctx, appName)
+	if err != nil {
+		return err
+	}
+	if app.PlatformVersion != "machines" {
+		return fmt.Errorf("egress addresses are only listed for machine apps")
+	}
+
+	flapsClient, err := flaps.New(ctx, app)
+	if err != nil {
+		return err
+	}
+
+	machines, err := flapsClient.ListActive(ctx)
+	if err != nil {
+		return err
+	}
+
+	egress := make([]machineEgress, 0, len(machines))
+	for _, m := range machines {
+		e := machineEgress{MachineID: m.ID, Region: m.Region}
+		switch {
+		case m.State != api.MachineStateStarted:
+			e.Error = "machine is " + m.State
+		default:
+			res, err := flapsClient.Exec(ctx, m.ID, &api.MachineExecRequest{
+				Cmd:     "sh -c '" + egressScript + "'",
+				Timeout: 30,
+			})
+			if err != nil {
+				e.Error = err.Error()
+				break
+			}
+			e.IPv4, e.IPv6 = parseEgress(res.StdOut)
+			if e.IPv4 == "" && e.IPv6 == "" {
+				e.Error = "the image needs wget or curl"
+			}
+		}
+		egress = append(egress, e)
+	}
+
+	if config.FromContext(ctx).JSONOutput {
+		return render.Output(ctx, io.Out, egress)
+	}
+
+	rows := make([][]string, 0, len(egress))
+	for _, e := range egress {
+		rows = append(rows, []string{e.MachineID, e.Region, e.IPv4, e.IPv6, e.Error})
+	}
+	return render.Table(io.Out, "", rows, "Machine", "Region", "IPv4", "IPv6", "Error")
+}
+
+// parseEgress extracts the addresses printed by egressScript.
+func parseEgress(out string) (ipv4, ipv6 string) {
+	for _, line := range strings.Split(out, "\n") {
+		ip := net.ParseIP(strings.TrimSpace(line))
+		switch {
+		case ip == nil:
+		case ip.To4() != nil:
+			ipv4 = ip.String()
+		default:
+			ipv6 = ip.String()
+		}
+	}
+	return
+}
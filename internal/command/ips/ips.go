@@ -20,6 +20,9 @@ func New() *cobra.Command {
 		newAllocatev6(),
 		newPrivate(),
 		newRelease(),
+		newUpgrade(),
+		newDowngrade(),
+		newEgress(),
 	)
 	return cmd
 }
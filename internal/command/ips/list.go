@@ -46,7 +46,7 @@ func runIPAddressesList(ctx context.Context) error {
 
 	if cfg.JSONOutput {
 		out := iostreams.FromContext(ctx).Out
-		return render.JSON(out, ipAddresses)
+		return render.Output(ctx, out, ipAddresses)
 	}
 
 	renderListTable(ctx, ipAddresses)
@@ -0,0 +1,180 @@
+package ips
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/flyerr"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+var migrateFlags = flag.Set{
+	flag.App(),
+	flag.AppConfig(),
+	flag.Yes(),
+	flag.Bool{
+		Name:        "release",
+		Description: "Release the previous address right away instead of after updating DNS records",
+	},
+}
+
+func newUpgrade() *cobra.Command {
+	const (
+		long = `Replaces the shared IPv4 address of the application with a dedicated one.
+The shared address keeps serving the application until it's released, so DNS
+records can be updated to the dedicated address first. Dedicated IPv4
+addresses cost $2/mo.`
+		short = `Replace the shared IPv4 address with a dedicated one`
+	)
+
+	cmd := command.New("upgrade", short, long, runUpgrade,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	flag.Add(cmd,
+		migrateFlags,
+		flag.Region(),
+	)
+
+	cmd.Args = cobra.NoArgs
+	return cmd
+}
+
+func newDowngrade() *cobra.Command {
+	const (
+		long = `Replaces a dedicated IPv4 address of the application with a shared one.
+The dedicated address keeps serving the application, and is billed, until
+it's released, so DNS records can be updated to the shared address first.`
+		short = `Replace a dedicated IPv4 address with a shared one`
+	)
+
+	cmd := command.New("downgrade [ADDRESS]", short, long, runDowngrade,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	flag.Add(cmd,
+		migrateFlags,
+	)
+
+	cmd.Args = cobra.MaximumNArgs(1)
+	return cmd
+}
+
+func runUpgrade(ctx context.Context) error {
+	var (
+		client  = client.FromContext(ctx).API()
+		appName = appconfig.NameFromContext(ctx)
+	)
+
+	ips, err := client.GetIPAddresses(ctx, appName)
+	if err != nil {
+		return err
+	}
+
+	var shared *api.IPAddress
+	for i := range ips {
+		if ips[i].Type == "shared_v4" {
+			shared = &ips[i]
+		}
+	}
+	if shared == nil {
+		return fmt.Errorf("app %s has no shared IPv4 address", appName)
+	}
+
+	if err := confirmMigration(ctx, fmt.Sprintf("Dedicated IPv4 addresses cost $2/mo. A records of your domains pointing at %s must be updated to the new address. Continue?", shared.Address)); err != nil {
+		return err
+	}
+
+	ip, err := client.AllocateIPAddress(ctx, appName, "v4", flag.GetRegion(ctx), nil, "")
+	if err != nil {
+		return err
+	}
+	renderListTable(ctx, []api.IPAddress{*ip})
+
+	return finishMigration(ctx, appName, shared.Address, ip.Address)
+}
+
+func runDowngrade(ctx context.Context) error {
+	var (
+		client  = client.FromContext(ctx).API()
+		appName = appconfig.NameFromContext(ctx)
+	)
+
+	ips, err := client.GetIPAddresses(ctx, appName)
+	if err != nil {
+		return err
+	}
+
+	var dedicated []api.IPAddress
+	for _, ip := range ips {
+		if ip.Type == "v4" && (flag.FirstArg(ctx) == "" || ip.Address == flag.FirstArg(ctx)) {
+			dedicated = append(dedicated, ip)
+		}
+	}
+	switch {
+	case len(dedicated) == 0 && flag.FirstArg(ctx) != "":
+		return fmt.Errorf("%s is not a dedicated IPv4 address of %s", flag.FirstArg(ctx), appName)
+	case len(dedicated) == 0:
+		return fmt.Errorf("app %s has no dedicated IPv4 address", appName)
+	case len(dedicated) > 1:
+		return fmt.Errorf("app %s has %d dedicated IPv4 addresses, specify the one to replace", appName, len(dedicated))
+	}
+	old := dedicated[0].Address
+
+	if err := confirmMigration(ctx, fmt.Sprintf("A records of your domains pointing at %s must be updated to the shared address. Continue?", old)); err != nil {
+		return err
+	}
+
+	shared, err := client.AllocateSharedIPAddress(ctx, appName)
+	if err != nil {
+		return err
+	}
+	renderSharedTable(ctx, shared)
+
+	return finishMigration(ctx, appName, old, shared.String())
+}
+
+func confirmMigration(ctx context.Context, msg string) error {
+	if flag.GetYes(ctx) {
+		return nil
+	}
+
+	switch confirmed, err := prompt.Confirm(ctx, msg); {
+	case err == nil:
+		if !confirmed {
+			return flyerr.ErrAbort
+		}
+		return nil
+	case prompt.IsNonInteractive(err):
+		return prompt.NonInteractiveError("yes flag must be specified when not running interactively")
+	default:
+		return err
+	}
+}
+
+// finishMigration releases the previous address with --release, or explains
+// how to release it once DNS records point at the new address.
+func finishMigration(ctx context.Context, appName, old, addr string) error {
+	out := iostreams.FromContext(ctx).Out
+
+	if !flag.GetBool(ctx, "release") {
+		fmt.Fprintf(out, "Update the DNS records pointing at %s to %s, then release the previous address with:\n", old, addr)
+		fmt.Fprintf(out, "  fly ips release %s -a %s\n", old, appName)
+		return nil
+	}
+
+	if err := client.FromContext(ctx).API().ReleaseIPAddress(ctx, appName, old); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Released %s from %s\n", old, appName)
+	return nil
+}
@@ -48,7 +48,7 @@ func runPrivateIPAddressesList(ctx context.Context) error {
 
 	out := iostreams.FromContext(ctx).Out
 	if conf := config.FromContext(ctx); conf.JSONOutput {
-		_ = render.JSON(out, appstatus.Allocations)
+		_ = render.Output(ctx, out, appstatus.Allocations)
 		return nil
 	}
 
@@ -17,7 +17,9 @@ import (
 	"github.com/superfly/flyctl/internal/command/deploy"
 	"github.com/superfly/flyctl/internal/flag"
 	"github.com/superfly/flyctl/internal/metrics"
+	"github.com/superfly/flyctl/internal/policy"
 	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/internal/state"
 	"github.com/superfly/flyctl/iostreams"
 	"github.com/superfly/flyctl/scanner"
 	"github.com/superfly/graphql"
@@ -25,11 +27,41 @@ import (
 
 func New() (cmd *cobra.Command) {
 	const (
-		long  = `Create and configure a new app from source code or a Docker image.`
-		short = long
+		long = `Create and configure a new app from source code or a Docker image.
+
+Pass --from-git with the URL of a Git repository, and optionally --git-ref,
+to launch it without cloning it first. It's cloned into --path, or into a
+directory named after the repository, along with the generated fly.toml.
+
+Frameworks flyctl doesn't detect can be supported by scanner plugins:
+executables named fly-scanner-<name> in the scanners directory of the
+config directory, e.g. ~/.fly/scanners. They run in the source directory
+before the built-in scanners, read the scan request as JSON on stdin, and
+print the detected framework, its files like a Dockerfile, and fly.toml
+defaults as JSON. Pass --no-scanner-plugins to skip them.
+
+--manifest reads the choices of launch from a YAML file, so that it runs the
+same way without prompting, e.g. in CI. Flags take precedence over it:
+
+  name: my-app
+  org: my-org
+  region: ams
+  vm_size: shared-cpu-1x
+  internal_port: 8080
+  env:
+    LOG_LEVEL: info
+  copy_config: true   # copy an existing fly.toml
+  postgres: true      # create and attach a Postgres cluster
+  redis: false        # create and attach an Upstash Redis database
+  secrets_file: .env  # dotenv or JSON file of secrets, relative to the manifest
+  deploy: true
+
+Choices left out of it are answered no. --write-manifest records the choices
+made during an interactive launch in such a file, without secret values.`
+		short = "Create and configure a new app from source code or a Docker image."
 	)
 
-	cmd = command.New("launch", short, long, run, command.RequireSession, command.LoadAppConfigIfPresent)
+	cmd = command.New("launch", short, long, run, command.RequireSession, command.CloneFromGitIfRequested, command.LoadAppConfigIfPresent)
 	cmd.Args = cobra.NoArgs
 
 	flag.Add(cmd,
@@ -74,6 +106,18 @@ func New() (cmd *cobra.Command) {
 			Description: "Set internal_port for all services in the generated fly.toml",
 			Default:     -1,
 		},
+		flag.Bool{
+			Name:        "no-scanner-plugins",
+			Description: "Only use the built-in scanners to detect the framework",
+		},
+		flag.String{
+			Name:        "manifest",
+			Description: "Path to a YAML manifest of the choices of launch, to launch without prompting",
+		},
+		flag.String{
+			Name:        "write-manifest",
+			Description: "Write the choices made during launch to a YAML manifest at this path",
+		},
 	)
 
 	return
@@ -83,6 +127,20 @@ func run(ctx context.Context) (err error) {
 	io := iostreams.FromContext(ctx)
 	client := client.FromContext(ctx).API()
 	workingDir := flag.GetString(ctx, "path")
+	if flag.GetString(ctx, "from-git") != "" {
+		workingDir = state.WorkingDirectory(ctx)
+	}
+
+	var manifestSecrets map[string]string
+	if path := flag.GetString(ctx, "manifest"); path != "" {
+		manifest, err := readManifest(path)
+		if err != nil {
+			return err
+		}
+		if ctx, manifestSecrets, err = manifest.apply(ctx); err != nil {
+			return err
+		}
+	}
 
 	deployArgs := deploy.DeployWithConfigArgs{
 		ForceNomad:    flag.GetBool(ctx, "force-nomad"),
@@ -172,6 +230,14 @@ func run(ctx context.Context) (err error) {
 	}
 	// Do not change PrimaryRegion after this line
 	appConfig.PrimaryRegion = region.Code
+
+	orgPolicy, err := policy.Load(ctx, org.Slug)
+	if err != nil {
+		return err
+	}
+	if err := orgPolicy.Error(orgPolicy.RegionViolations(region.Code)); err != nil {
+		return err
+	}
 	fmt.Fprintf(io.Out, "App will use '%s' region as primary\n", appConfig.PrimaryRegion)
 
 	shouldUseMachines, err := shouldAppUseMachinesPlatform(ctx, org.Slug, existingAppPlatform)
@@ -243,7 +309,7 @@ func run(ctx context.Context) (err error) {
 		return err
 	}
 	// If secrets are requested by the launch scanner, ask the user to input them
-	if err := createSecrets(ctx, srcInfo, appConfig.AppName); err != nil {
+	if err := createSecrets(ctx, srcInfo, appConfig.AppName, manifestSecrets); err != nil {
 		return err
 	}
 	// If volumes are requested by the launch scanner, create them
@@ -308,7 +374,7 @@ func run(ctx context.Context) (err error) {
 	}
 
 	if promptForDeploy {
-		confirm, err := prompt.Confirm(ctx, "Would you like to deploy now?")
+		confirm, err := prompt.Confirm(ctx, deployPrompt)
 		if confirm && err == nil {
 			deployNow = true
 		}
@@ -338,6 +404,28 @@ func run(ctx context.Context) (err error) {
 		return fmt.Errorf("invalid configuration file: %w", err)
 	}
 
+	if path := flag.GetString(ctx, "write-manifest"); path != "" {
+		manifest := &launchManifest{
+			Name:         appConfig.AppName,
+			Org:          org.Slug,
+			Region:       region.Code,
+			VMSize:       flag.GetString(ctx, "vm-size"),
+			InternalPort: flag.GetInt(ctx, "internal-port"),
+			Env:          envVars,
+			CopyConfig:   copyConfig,
+			Postgres:     options["postgresql"],
+			Redis:        options["redis"],
+			Deploy:       deployNow,
+		}
+		if manifest.InternalPort < 0 {
+			manifest.InternalPort = 0
+		}
+		if err := writeManifest(path, manifest); err != nil {
+			return err
+		}
+		fmt.Fprintf(io.Out, "Wrote the choices of this launch to %s\n", path)
+	}
+
 	if deployNow {
 		return deploy.DeployWithConfig(ctx, appConfig, deployArgs)
 	}
@@ -433,7 +521,7 @@ func determineBaseAppConfig(ctx context.Context) (*appconfig.Config, bool, error
 		copyConfig := flag.GetBool(ctx, "copy-config")
 		if !flag.IsSpecified(ctx, "copy-config") {
 			var err error
-			copyConfig, err = prompt.Confirm(ctx, "Would you like to copy its configuration to the new app?")
+			copyConfig, err = prompt.Confirm(ctx, copyConfigPrompt)
 			switch {
 			case prompt.IsNonInteractive(err) && !flag.GetBool(ctx, "auto-confirm"):
 				return nil, false, err
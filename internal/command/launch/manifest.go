@@ -0,0 +1,155 @@
+package launch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/superfly/flyctl/internal/command/secrets"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/prompt"
+)
+
+// Prompts of launch that manifests answer.
+const (
+	copyConfigPrompt = "Would you like to copy its configuration to the new app?"
+	postgresPrompt   = "Would you like to set up a Postgresql database now?"
+	redisPrompt      = "Would you like to set up an Upstash Redis database now?"
+	deployPrompt     = "Would you like to deploy now?"
+)
+
+// launchManifest holds the answers to the choices of launch, so that it may
+// run without prompting. Flags take precedence over it.
+type launchManifest struct {
+	Name         string            `yaml:"name,omitempty"`
+	Org          string            `yaml:"org,omitempty"`
+	Region       string            `yaml:"region,omitempty"`
+	VMSize       string            `yaml:"vm_size,omitempty"`
+	InternalPort int               `yaml:"internal_port,omitempty"`
+	Env          map[string]string `yaml:"env,omitempty"`
+	CopyConfig   bool              `yaml:"copy_config,omitempty"`
+	Postgres     bool              `yaml:"postgres,omitempty"`
+	Redis        bool              `yaml:"redis,omitempty"`
+	// SecretsFile is a dotenv or JSON file of secret values, relative to the
+	// manifest. Manifests never hold secret values themselves.
+	SecretsFile string `yaml:"secrets_file,omitempty"`
+	Deploy      bool   `yaml:"deploy,omitempty"`
+}
+
+func readManifest(path string) (*launchManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading manifest: %w", err)
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+
+	var m launchManifest
+	if err := dec.Decode(&m); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed parsing manifest %s: %w", path, err)
+	}
+
+	if m.SecretsFile != "" && !filepath.IsAbs(m.SecretsFile) {
+		m.SecretsFile = filepath.Join(filepath.Dir(path), m.SecretsFile)
+	}
+
+	return &m, nil
+}
+
+func writeManifest(path string, m *launchManifest) error {
+	var b bytes.Buffer
+
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(2)
+	if err := enc.Encode(m); err != nil {
+		return fmt.Errorf("failed encoding manifest: %w", err)
+	}
+
+	if err := os.WriteFile(path, b.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed writing manifest: %w", err)
+	}
+
+	return nil
+}
+
+// apply sets the flags the manifest holds values of, unless specified, and
+// answers the prompts of launch. It returns the secret values of the
+// manifest.
+func (m *launchManifest) apply(ctx context.Context) (context.Context, map[string]string, error) {
+	flags := flag.FromContext(ctx)
+
+	set := func(name, value string) error {
+		if value == "" || flag.IsSpecified(ctx, name) {
+			return nil
+		}
+		if err := flags.Set(name, value); err != nil {
+			return fmt.Errorf("invalid manifest value for %s: %w", name, err)
+		}
+		return nil
+	}
+
+	if err := set("name", m.Name); err != nil {
+		return nil, nil, err
+	}
+	if err := set(flag.RegionName, m.Region); err != nil {
+		return nil, nil, err
+	}
+	if err := set("vm-size", m.VMSize); err != nil {
+		return nil, nil, err
+	}
+	if m.InternalPort > 0 {
+		if err := set("internal-port", strconv.Itoa(m.InternalPort)); err != nil {
+			return nil, nil, err
+		}
+	}
+	if !flag.IsSpecified(ctx, "env") {
+		names := make([]string, 0, len(m.Env))
+		for name := range m.Env {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if err := flags.Set("env", name+"="+m.Env[name]); err != nil {
+				return nil, nil, fmt.Errorf("invalid manifest value for env: %w", err)
+			}
+		}
+	}
+
+	if m.Org != "" && !flag.IsSpecified(ctx, flag.OrgName) {
+		config.FromContext(ctx).Organization = m.Org
+	}
+
+	answers := map[string]bool{
+		copyConfigPrompt: m.CopyConfig,
+		postgresPrompt:   m.Postgres,
+		redisPrompt:      m.Redis,
+		deployPrompt:     m.Deploy,
+	}
+	for msg, value := range answers {
+		var err error
+		if ctx, err = prompt.WithAnswer(ctx, msg, value); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if m.SecretsFile == "" {
+		return ctx, map[string]string{}, nil
+	}
+
+	values, err := secrets.ReadFile(m.SecretsFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed reading secrets of manifest: %w", err)
+	}
+
+	return ctx, values, nil
+}
@@ -3,6 +3,7 @@ package launch
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 	"regexp"
 	"strings"
 
@@ -10,6 +11,7 @@ import (
 	"github.com/logrusorgru/aurora"
 	"github.com/superfly/flyctl/internal/appconfig"
 	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/state"
 	"github.com/superfly/flyctl/iostreams"
 	"github.com/superfly/flyctl/scanner"
 )
@@ -24,6 +26,9 @@ func determineSourceInfo(ctx context.Context, appConfig *appconfig.Config, copyC
 		ExistingPort: appConfig.InternalPort(),
 		Mode:         "launch",
 	}
+	if !flag.GetBool(ctx, "no-scanner-plugins") {
+		scannerConfig.PluginDir = filepath.Join(state.ConfigDirectory(ctx), "scanners")
+	}
 	// Detect if --copy-config and --now flags are set. If so, limited set of
 	// fly.toml file updates. Helpful for deploying PRs when the project is
 	// already setup and we only need fly.toml config changes.
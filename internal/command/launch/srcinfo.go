@@ -59,26 +59,39 @@ func createSourceInfoFiles(ctx context.Context, srcInfo *scanner.SourceInfo, wor
 	return nil
 }
 
-// If secrets are requested by the launch scanner, ask the user to input them
-func createSecrets(ctx context.Context, srcInfo *scanner.SourceInfo, appName string) error {
-	if srcInfo == nil || len(srcInfo.Secrets) == 0 {
+// If secrets are requested by the launch scanner, ask the user to input them.
+// The values of a manifest are used when set, in which case nothing is asked.
+func createSecrets(ctx context.Context, srcInfo *scanner.SourceInfo, appName string, manifestSecrets map[string]string) error {
+	if (srcInfo == nil || len(srcInfo.Secrets) == 0) && len(manifestSecrets) == 0 {
 		return nil
 	}
 
 	var err error
 	io := iostreams.FromContext(ctx)
 	secrets := map[string]string{}
+	for k, v := range manifestSecrets {
+		secrets[k] = v
+	}
+
+	var requested []scanner.Secret
+	if srcInfo != nil {
+		requested = srcInfo.Secrets
+	}
 
-	for _, secret := range srcInfo.Secrets {
+	for _, secret := range requested {
 		val := ""
 		// If a secret should be a random default, just generate it without displaying
 		// Otherwise, prompt to type it in
-		if secret.Generate != nil {
+		if v, ok := manifestSecrets[secret.Key]; ok {
+			val = v
+		} else if secret.Generate != nil {
 			if val, err = secret.Generate(); err != nil {
 				return fmt.Errorf("could not generate random string: %w", err)
 			}
 		} else if secret.Value != "" {
 			val = secret.Value
+		} else if manifestSecrets != nil {
+			fmt.Fprintf(io.Out, "Secret %s isn't in the secrets file of the manifest, set it with 'fly secrets set -a %s'\n", secret.Key, appName)
 		} else {
 			prompt := fmt.Sprintf("Set secret %s:", secret.Key)
 			surveyInput := &survey.Input{Message: prompt, Help: secret.Help}
@@ -141,7 +154,7 @@ func createDatabases(ctx context.Context, srcInfo *scanner.SourceInfo, appName s
 	io := iostreams.FromContext(ctx)
 	colorize := io.ColorScheme()
 
-	confirmPg, err := prompt.Confirm(ctx, "Would you like to set up a Postgresql database now?")
+	confirmPg, err := prompt.Confirm(ctx, postgresPrompt)
 	if confirmPg && err == nil {
 		db_app_name := fmt.Sprintf("%s-db", appName)
 		should_attach_db := false
@@ -191,7 +204,7 @@ func createDatabases(ctx context.Context, srcInfo *scanner.SourceInfo, appName s
 		}
 	}
 
-	confirmRedis, err := prompt.Confirm(ctx, "Would you like to set up an Upstash Redis database now?")
+	confirmRedis, err := prompt.Confirm(ctx, redisPrompt)
 	if confirmRedis && err == nil {
 		err := LaunchRedis(ctx, appName, org, region)
 		if err != nil {
@@ -0,0 +1,90 @@
+package logs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/samber/lo"
+
+	"github.com/superfly/flyctl/logs"
+)
+
+// logFields maps the names --field accepts to the log entry values they refer
+// to.
+var logFields = map[string]func(logs.LogEntry) string{
+	"level":     func(e logs.LogEntry) string { return e.Level },
+	"instance":  func(e logs.LogEntry) string { return e.Instance },
+	"region":    func(e logs.LogEntry) string { return e.Region },
+	"message":   func(e logs.LogEntry) string { return e.Message },
+	"timestamp": func(e logs.LogEntry) string { return e.Timestamp },
+	"provider":  func(e logs.LogEntry) string { return e.Meta.Event.Provider },
+}
+
+func logFieldNames() []string {
+	names := make([]string, 0, len(logFields))
+	for name := range logFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// fieldFilter selects log entries by the values of their fields. Entries match
+// when, for every field, they have one of the values given for it.
+type fieldFilter map[string][]string
+
+func parseFieldFilter(specs []string) (fieldFilter, error) {
+	filter := fieldFilter{}
+
+	for _, spec := range specs {
+		name, value, ok := strings.Cut(spec, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid field filter %q, must be in the form of name=value", spec)
+		}
+
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := logFields[name]; !ok {
+			return nil, fmt.Errorf("unknown field %q, must be one of %s", name, strings.Join(logFieldNames(), ", "))
+		}
+
+		filter[name] = append(filter[name], value)
+	}
+
+	return filter, nil
+}
+
+// restrict narrows the values of the field down to the given ones, or to those
+// of them already allowed. It reports whether any value is left.
+func (f fieldFilter) restrict(name string, values []string) bool {
+	if existing, ok := f[name]; ok {
+		values = lo.Filter(values, func(v string, _ int) bool {
+			return lo.ContainsBy(existing, func(e string) bool { return strings.EqualFold(e, v) })
+		})
+	}
+
+	f[name] = values
+
+	return len(values) > 0
+}
+
+func (f fieldFilter) matches(entry logs.LogEntry) bool {
+	for name, values := range f {
+		actual := logFields[name](entry)
+
+		var found bool
+		for _, v := range values {
+			if strings.EqualFold(actual, v) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
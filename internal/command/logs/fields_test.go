@@ -0,0 +1,35 @@
+package logs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/superfly/flyctl/logs"
+)
+
+func TestFieldFilter(t *testing.T) {
+	filter, err := parseFieldFilter([]string{"level=error", "level=warn", "region=ord"})
+	require.NoError(t, err)
+
+	assert.True(t, filter.matches(logs.LogEntry{Level: "error", Region: "ord"}))
+	assert.True(t, filter.matches(logs.LogEntry{Level: "WARN", Region: "ord"}))
+	assert.False(t, filter.matches(logs.LogEntry{Level: "info", Region: "ord"}))
+	assert.False(t, filter.matches(logs.LogEntry{Level: "error", Region: "ams"}))
+
+	assert.True(t, fieldFilter{}.matches(logs.LogEntry{}))
+
+	assert.True(t, filter.restrict("instance", []string{"a", "b"}))
+	assert.True(t, filter.matches(logs.LogEntry{Level: "error", Region: "ord", Instance: "b"}))
+	assert.False(t, filter.matches(logs.LogEntry{Level: "error", Region: "ord", Instance: "c"}))
+	assert.True(t, filter.restrict("instance", []string{"b", "c"}))
+	assert.Equal(t, []string{"b"}, filter["instance"])
+	assert.False(t, filter.restrict("instance", []string{"c"}))
+
+	_, err = parseFieldFilter([]string{"level"})
+	assert.Error(t, err)
+
+	_, err = parseFieldFilter([]string{"color=red"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,120 @@
+package logs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/flag"
+)
+
+// logLevels are the levels logs may be filtered by, in increasing severity.
+var logLevels = []string{"debug", "info", "warn", "error"}
+
+// shipFilter selects which logs are shipped to a sink. The app the sink is set
+// up for is always included.
+type shipFilter struct {
+	// Apps and ExcludeApps are glob patterns of app names, e.g. "*-production".
+	Apps        []string `json:"apps,omitempty"`
+	ExcludeApps []string `json:"exclude_apps,omitempty"`
+	MinLevel    string   `json:"min_level,omitempty"`
+}
+
+func shipFilterFromFlags(ctx context.Context) (shipFilter, error) {
+	f := shipFilter{
+		Apps:        flag.GetStringSlice(ctx, "include-app"),
+		ExcludeApps: flag.GetStringSlice(ctx, "exclude-app"),
+		MinLevel:    strings.ToLower(flag.GetString(ctx, "min-level")),
+	}
+
+	if f.MinLevel != "" && levelIndex(f.MinLevel) < 0 {
+		return f, fmt.Errorf("invalid log level %q, must be one of %s", f.MinLevel, strings.Join(logLevels, ", "))
+	}
+
+	return f, nil
+}
+
+func (f shipFilter) isZero() bool {
+	return len(f.Apps) == 0 && len(f.ExcludeApps) == 0 && f.MinLevel == ""
+}
+
+// orgWide reports whether the filter may match apps other than the one the
+// sink is set up for, in which case the shipper needs to read the logs of
+// every app of the organization.
+func (f shipFilter) orgWide() bool {
+	return len(f.Apps) > 0
+}
+
+func levelIndex(level string) int {
+	for i, l := range logLevels {
+		if l == level {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// condition renders the filter as a VRL condition for the given app.
+func (f shipFilter) condition(appName string) string {
+	var b strings.Builder
+
+	b.WriteString("app = string(.fly.app.name) ?? \"\"\n")
+	b.WriteString("level = downcase(string(.log.level) ?? \"info\")\n")
+
+	apps := []string{fmt.Sprintf("app == %q", appName)}
+	for _, pattern := range f.Apps {
+		apps = append(apps, fmt.Sprintf("match(app, r'%s')", globToRegexp(pattern)))
+	}
+	clauses := []string{"(" + strings.Join(apps, " || ") + ")"}
+
+	for _, pattern := range f.ExcludeApps {
+		clauses = append(clauses, fmt.Sprintf("!match(app, r'%s')", globToRegexp(pattern)))
+	}
+
+	if i := levelIndex(f.MinLevel); i > 0 {
+		levels, _ := json.Marshal(logLevels[i:])
+		clauses = append(clauses, fmt.Sprintf("includes(%s, level)", levels))
+	}
+
+	b.WriteString(strings.Join(clauses, " && "))
+	b.WriteString("\n")
+
+	return b.String()
+}
+
+func globToRegexp(pattern string) string {
+	re := regexp.QuoteMeta(pattern)
+	re = strings.ReplaceAll(re, `\*`, ".*")
+	re = strings.ReplaceAll(re, `\?`, ".")
+
+	return "^" + re + "$"
+}
+
+// filterHeader prefixes vector configs written by flyctl, recording the filter
+// they were written with.
+const filterHeader = "# fly-logs-ship-filter: "
+
+// readSinkFilter reads back the filter the sink at path was written with.
+func readSinkFilter(ctx context.Context, flapsClient *flaps.Client, machine *api.Machine, path string) (shipFilter, error) {
+	var f shipFilter
+
+	response, err := flapsClient.Exec(ctx, machine.ID, &api.MachineExecRequest{
+		Cmd: "head -n 1 " + path,
+	})
+	if err != nil {
+		return f, err
+	}
+
+	if header, ok := strings.CutPrefix(strings.TrimSpace(response.StdOut), filterHeader); ok {
+		if err := json.Unmarshal([]byte(header), &f); err != nil {
+			return f, fmt.Errorf("invalid filter in %s: %w", path, err)
+		}
+	}
+
+	return f, nil
+}
@@ -3,12 +3,16 @@ package logs
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"time"
 
 	"github.com/azazeal/pause"
+	"github.com/samber/lo"
 	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/superfly/flyctl/api"
@@ -16,6 +20,7 @@ import (
 	"github.com/superfly/flyctl/logs"
 
 	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
 	"github.com/superfly/flyctl/internal/appconfig"
 	"github.com/superfly/flyctl/internal/command"
 	"github.com/superfly/flyctl/internal/config"
@@ -31,6 +36,20 @@ the Fly platform.
 
 Logs can be filtered to a specific instance using the --instance/-i flag or
 to all instances running in a specific region using the --region/-r flag.
+The --machine flag filters logs to any of several machines, and the
+--process-group flag to the machines of the given process groups. Filters may
+be combined.
+
+Historical logs can be retrieved using the --since and --until flags, which
+accept either a duration (e.g. 2h) or an RFC 3339 timestamp, and searched using
+the --search flag. Historical logs are printed once, without tailing.
+
+Logs can also be filtered by the value of their fields using the --field flag,
+e.g. --field level=error. The flag may be specified multiple times; entries
+must match at least one of the values given for each field. Fields are
+level, instance, region, message, timestamp and provider.
+
+With --json, each log entry is printed as a JSON object on its own line.
 `
 		short = "View app logs"
 	)
@@ -52,8 +71,32 @@ to all instances running in a specific region using the --region/-r flag.
 			Shorthand:   "i",
 			Description: "Filter by instance ID",
 		},
+		flag.StringSlice{
+			Name:        "machine",
+			Description: "Filter by machine ID. Can be specified multiple times.",
+		},
+		flag.StringSlice{
+			Name:        "process-group",
+			Description: "Filter by process group. Can be specified multiple times.",
+		},
+		flag.String{
+			Name:        "since",
+			Description: "Show logs since a duration ago (e.g. 2h) or a timestamp (e.g. 2023-06-01T15:04:05Z)",
+		},
+		flag.String{
+			Name:        "until",
+			Description: "Show logs until a duration ago (e.g. 30m) or a timestamp (e.g. 2023-06-01T15:04:05Z)",
+		},
+		flag.String{
+			Name:        "search",
+			Description: "Only show logs containing the given text",
+		},
+		flag.StringSlice{
+			Name:        "field",
+			Description: "Only show logs whose field has the given value, in the form of name=value (e.g. level=error). Can be specified multiple times.",
+		},
 	)
-	cmd.AddCommand(newShip(), newUnship(), newDashboard())
+	cmd.AddCommand(newShip(), newUnship(), newDashboard(), newSave())
 	return
 }
 
@@ -64,8 +107,36 @@ func run(ctx context.Context) error {
 		AppName:    appconfig.NameFromContext(ctx),
 		RegionCode: config.FromContext(ctx).Region,
 		VMID:       flag.GetString(ctx, "instance"),
+		Search:     flag.GetString(ctx, "search"),
 	}
 
+	if err := parseTimeRange(ctx, opts); err != nil {
+		return err
+	}
+
+	filter, err := parseFieldFilter(flag.GetStringSlice(ctx, "field"))
+	if err != nil {
+		return err
+	}
+
+	if err := filterMachines(ctx, opts, filter); err != nil {
+		return err
+	}
+
+	if historical(ctx) {
+		return search(ctx, client, opts, filter)
+	}
+
+	var (
+		out    = iostreams.FromContext(ctx).Out
+		asJSON = config.FromContext(ctx).JSONOutput
+	)
+
+	return tail(ctx, client, opts, filter, out, asJSON)
+}
+
+// tail writes live logs to w until ctx is done.
+func tail(ctx context.Context, client *api.Client, opts *logs.LogOptions, filter fieldFilter, w io.Writer, asJSON bool) error {
 	var eg *errgroup.Group
 	eg, ctx = errgroup.WithContext(ctx)
 
@@ -74,7 +145,146 @@ func run(ctx context.Context) error {
 	liveEntries := nats(ctx, eg, client, opts, cancelPolling)
 
 	eg.Go(func() error {
-		return printStreams(ctx, pollEntries, liveEntries)
+		return printStreams(ctx, w, asJSON, filter, pollEntries, liveEntries)
+	})
+
+	return eg.Wait()
+}
+
+// filterMachines narrows filter down to the machines selected by the
+// --instance, --machine and --process-group flags. When a single machine is
+// selected, logs are filtered server side instead.
+func filterMachines(ctx context.Context, opts *logs.LogOptions, filter fieldFilter) error {
+	ids := flag.GetStringSlice(ctx, "machine")
+	if opts.VMID != "" {
+		ids = append(ids, opts.VMID)
+	}
+
+	if groups := flag.GetStringSlice(ctx, "process-group"); len(groups) > 0 {
+		inGroups, err := machinesInGroups(ctx, opts.AppName, groups)
+		if err != nil {
+			return err
+		}
+
+		if len(ids) > 0 {
+			ids = lo.Intersect(ids, inGroups)
+		} else {
+			ids = inGroups
+		}
+
+		if len(ids) == 0 {
+			return fmt.Errorf("no machines of app %s match the given machines and process groups", opts.AppName)
+		}
+	}
+
+	switch ids = lo.Uniq(ids); len(ids) {
+	case 0:
+	case 1:
+		opts.VMID = ids[0]
+	default:
+		opts.VMID = ""
+		if !filter.restrict("instance", ids) {
+			return errors.New("no machines match both --field instance and the given machines and process groups")
+		}
+	}
+
+	return nil
+}
+
+func machinesInGroups(ctx context.Context, appName string, groups []string) ([]string, error) {
+	flapsClient, err := flaps.NewFromAppName(ctx, appName)
+	if err != nil {
+		return nil, fmt.Errorf("could not create flaps client: %w", err)
+	}
+
+	machines, err := flapsClient.ListActive(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not list machines: %w", err)
+	}
+
+	var ids []string
+	for _, m := range machines {
+		if slices.Contains(groups, m.ProcessGroup()) {
+			ids = append(ids, m.ID)
+		}
+	}
+
+	return ids, nil
+}
+
+// defaultSearchWindow is how far back historical searches go when --since
+// isn't set.
+const defaultSearchWindow = time.Hour
+
+// historical reports whether the user asked for historical logs rather than
+// tailing live ones.
+func historical(ctx context.Context) bool {
+	return flag.IsSpecified(ctx, "since") ||
+		flag.IsSpecified(ctx, "until") ||
+		flag.IsSpecified(ctx, "search")
+}
+
+func parseTimeRange(ctx context.Context, opts *logs.LogOptions) (err error) {
+	now := time.Now()
+
+	if v := flag.GetString(ctx, "until"); v != "" {
+		if opts.Until, err = parseTime(v, now); err != nil {
+			return fmt.Errorf("invalid --until: %w", err)
+		}
+	}
+
+	if v := flag.GetString(ctx, "since"); v != "" {
+		if opts.Since, err = parseTime(v, now); err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+	} else if historical(ctx) {
+		end := opts.Until
+		if end.IsZero() {
+			end = now
+		}
+		opts.Since = end.Add(-defaultSearchWindow)
+	}
+
+	if !opts.Until.IsZero() && opts.Since.After(opts.Until) {
+		return errors.New("--since must be before --until")
+	}
+
+	return nil
+}
+
+// parseTime parses either a duration, relative to now, or an RFC 3339
+// timestamp.
+func parseTime(v string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(v); err == nil {
+		return now.Add(-d.Abs()), nil
+	}
+
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is neither a duration (e.g. 2h) nor an RFC 3339 timestamp", v)
+	}
+
+	return t, nil
+}
+
+func search(ctx context.Context, client *api.Client, opts *logs.LogOptions, filter fieldFilter) error {
+	var eg *errgroup.Group
+	eg, ctx = errgroup.WithContext(ctx)
+
+	c := make(chan logs.LogEntry)
+	eg.Go(func() error {
+		defer close(c)
+
+		return logs.Search(ctx, c, client, opts)
+	})
+
+	eg.Go(func() error {
+		var (
+			out    = iostreams.FromContext(ctx).Out
+			asJSON = config.FromContext(ctx).JSONOutput
+		)
+
+		return printStreams(ctx, out, asJSON, filter, c)
 	})
 
 	return eg.Wait()
@@ -129,25 +339,26 @@ func nats(ctx context.Context, eg *errgroup.Group, client *api.Client, opts *log
 	return c
 }
 
-func printStreams(ctx context.Context, streams ...<-chan logs.LogEntry) error {
+func printStreams(ctx context.Context, w io.Writer, asJSON bool, filter fieldFilter, streams ...<-chan logs.LogEntry) error {
 	var eg *errgroup.Group
 	eg, ctx = errgroup.WithContext(ctx)
 
-	out := iostreams.FromContext(ctx).Out
-	json := config.FromContext(ctx).JSONOutput
-
 	for _, stream := range streams {
 		stream := stream
 
 		eg.Go(func() error {
-			return printStream(ctx, out, stream, json)
+			return printStream(ctx, w, stream, filter, asJSON)
 		})
 	}
 
 	return eg.Wait()
 }
 
-func printStream(ctx context.Context, w io.Writer, stream <-chan logs.LogEntry, json bool) error {
+func printStream(ctx context.Context, w io.Writer, stream <-chan logs.LogEntry, filter fieldFilter, asJSON bool) error {
+	// entries are printed one per line so that the output may be piped to
+	// line-oriented tools such as jq
+	enc := json.NewEncoder(w)
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -157,9 +368,13 @@ func printStream(ctx context.Context, w io.Writer, stream <-chan logs.LogEntry,
 				return nil
 			}
 
+			if !filter.matches(entry) {
+				continue
+			}
+
 			var err error
-			if json {
-				err = render.JSON(w, entry)
+			if asJSON {
+				err = enc.Encode(entry)
 			} else {
 				err = render.LogEntry(w, entry,
 					render.HideAllocID(),
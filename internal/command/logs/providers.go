@@ -0,0 +1,233 @@
+package logs
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/superfly/flyctl/internal/prompt"
+)
+
+// logProvider describes a destination the log shipper is able to forward logs
+// to, along with the settings it needs.
+type logProvider struct {
+	Name     string
+	Title    string
+	Settings []providerSetting
+
+	// Sink is the vector sink config for the provider. ${SETTING} references
+	// are replaced with the per-app secret holding the setting.
+	Sink string
+}
+
+type providerSetting struct {
+	Name     string
+	Prompt   string
+	Default  string
+	Secret   bool
+	Optional bool
+}
+
+// logProviders is the catalog of providers `fly logs ship` can set up.
+var logProviders = []logProvider{
+	{
+		Name:  "logtail",
+		Title: "Logtail (provisioned by Fly.io)",
+	},
+	{
+		Name:  "datadog",
+		Title: "Datadog",
+		Settings: []providerSetting{
+			{Name: "DATADOG_API_KEY", Prompt: "Datadog API key", Secret: true},
+			{Name: "DATADOG_SITE", Prompt: "Datadog site", Default: "datadoghq.com"},
+		},
+		Sink: `type = "datadog_logs"
+default_api_key = "${DATADOG_API_KEY}"
+site = "${DATADOG_SITE}"
+compression = "gzip"
+`,
+	},
+	{
+		Name:  "loki",
+		Title: "Grafana Loki",
+		Settings: []providerSetting{
+			{Name: "LOKI_URL", Prompt: "Loki URL"},
+			{Name: "LOKI_USERNAME", Prompt: "Loki username"},
+			{Name: "LOKI_PASSWORD", Prompt: "Loki password", Secret: true},
+		},
+		Sink: `type = "loki"
+endpoint = "${LOKI_URL}"
+encoding.codec = "json"
+auth.strategy = "basic"
+auth.user = "${LOKI_USERNAME}"
+auth.password = "${LOKI_PASSWORD}"
+labels.app = "{{ fly.app.name }}"
+labels.region = "{{ fly.region }}"
+`,
+	},
+	{
+		Name:  "s3",
+		Title: "Amazon S3",
+		Settings: []providerSetting{
+			{Name: "AWS_BUCKET", Prompt: "S3 bucket"},
+			{Name: "AWS_REGION", Prompt: "S3 bucket region", Default: "us-east-1"},
+			{Name: "AWS_ACCESS_KEY_ID", Prompt: "AWS access key ID"},
+			{Name: "AWS_SECRET_ACCESS_KEY", Prompt: "AWS secret access key", Secret: true},
+			{Name: "S3_ENDPOINT", Prompt: "S3 endpoint (leave blank for AWS)", Optional: true},
+		},
+		Sink: `type = "aws_s3"
+bucket = "${AWS_BUCKET}"
+region = "${AWS_REGION}"
+endpoint = "${S3_ENDPOINT}"
+auth.access_key_id = "${AWS_ACCESS_KEY_ID}"
+auth.secret_access_key = "${AWS_SECRET_ACCESS_KEY}"
+key_prefix = "{{ fly.app.name }}/%Y/%m/%d/"
+compression = "gzip"
+encoding.codec = "json"
+`,
+	},
+	{
+		Name:  "honeycomb",
+		Title: "Honeycomb",
+		Settings: []providerSetting{
+			{Name: "HONEYCOMB_API_KEY", Prompt: "Honeycomb API key", Secret: true},
+			{Name: "HONEYCOMB_DATASET", Prompt: "Honeycomb dataset", Default: "fly-logs"},
+		},
+		Sink: `type = "honeycomb"
+api_key = "${HONEYCOMB_API_KEY}"
+dataset = "${HONEYCOMB_DATASET}"
+`,
+	},
+}
+
+func logProviderNames() []string {
+	names := make([]string, 0, len(logProviders))
+	for _, p := range logProviders {
+		names = append(names, p.Name)
+	}
+
+	return names
+}
+
+func findLogProvider(name string) (*logProvider, error) {
+	for i := range logProviders {
+		if logProviders[i].Name == name {
+			return &logProviders[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown log provider %q, must be one of %s", name, strings.Join(logProviderNames(), ", "))
+}
+
+func selectLogProvider(ctx context.Context, name string) (*logProvider, error) {
+	if name != "" {
+		return findLogProvider(name)
+	}
+
+	options := make([]string, 0, len(logProviders))
+	for _, p := range logProviders {
+		options = append(options, p.Title)
+	}
+
+	var index int
+	if err := prompt.Select(ctx, &index, "Where should logs be shipped to?", "", options...); err != nil {
+		if prompt.IsNonInteractive(err) {
+			return nil, fmt.Errorf("--provider must be specified when not running interactively")
+		}
+		return nil, err
+	}
+
+	return &logProviders[index], nil
+}
+
+// promptSettings asks for every setting of the provider which isn't already
+// part of values.
+func (p *logProvider) promptSettings(ctx context.Context, values map[string]string) error {
+	for _, s := range p.Settings {
+		if _, ok := values[s.Name]; ok {
+			continue
+		}
+
+		var (
+			v   string
+			err error
+		)
+		if s.Secret {
+			err = prompt.Password(ctx, &v, s.Prompt+":", !s.Optional)
+		} else {
+			err = prompt.String(ctx, &v, s.Prompt+":", s.Default, !s.Optional)
+		}
+
+		switch {
+		case prompt.IsNonInteractive(err):
+			return fmt.Errorf("%s must be specified with --setting %s=<value> when not running interactively", s.Prompt, s.Name)
+		case err != nil:
+			return err
+		}
+
+		values[s.Name] = v
+	}
+
+	return nil
+}
+
+var nonEnvChars = regexp.MustCompile(`[^A-Z0-9_]`)
+
+// secretName returns the name of the secret holding the given setting for an
+// app. Settings are namespaced per app since a single shipper serves every app
+// of an organization.
+func secretName(appName, setting string) string {
+	return nonEnvChars.ReplaceAllString(strings.ToUpper(appName), "_") + "_" + setting
+}
+
+// secrets returns the shipper app secrets for the given setting values.
+func (p *logProvider) secrets(appName string, values map[string]string) map[string]string {
+	secrets := make(map[string]string, len(values))
+	for _, s := range p.Settings {
+		secrets[secretName(appName, s.Name)] = values[s.Name]
+	}
+
+	return secrets
+}
+
+// vectorConfig renders the vector config forwarding the logs filter selects to
+// the provider.
+func (p *logProvider) vectorConfig(appName string, filter shipFilter) string {
+	var oldnew []string
+	for _, s := range p.Settings {
+		oldnew = append(oldnew, "${"+s.Name+"}", "${"+secretName(appName, s.Name)+"}")
+	}
+
+	id := strings.ReplaceAll(appName, "-", "_") + "_" + p.Name
+
+	header, _ := json.Marshal(filter)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s%s\n\n", filterHeader, header)
+	fmt.Fprintf(&b, "[transforms.%s_filter]\n", id)
+	fmt.Fprintf(&b, "type = \"filter\"\n")
+	fmt.Fprintf(&b, "inputs = [\"log_json\"]\n")
+	fmt.Fprintf(&b, "condition = '''\n%s'''\n\n", filter.condition(appName))
+	fmt.Fprintf(&b, "[sinks.%s]\n", id)
+	fmt.Fprintf(&b, "inputs = [\"%s_filter\"]\n", id)
+	b.WriteString(strings.NewReplacer(oldnew...).Replace(p.Sink))
+
+	return b.String()
+}
+
+// vectorConfigPath is where the shipper keeps the vector config of the app's
+// sink for the provider.
+func (p *logProvider) vectorConfigPath(appName string) string {
+	return fmt.Sprintf("/etc/vector/sinks/%s-%s.toml", appName, p.Name)
+}
+
+// writeVectorConfigCmd returns the shell command writing the provider's vector
+// config for the app on the shipper machine.
+func (p *logProvider) writeVectorConfigCmd(appName string, filter shipFilter) string {
+	encoded := base64.StdEncoding.EncodeToString([]byte(p.vectorConfig(appName, filter)))
+
+	return fmt.Sprintf("sh -c 'echo %s | base64 -d > %s'", encoded, p.vectorConfigPath(appName))
+}
@@ -0,0 +1,213 @@
+package logs
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/iostreams"
+	"github.com/superfly/flyctl/logs"
+)
+
+func newSave() (cmd *cobra.Command) {
+	const (
+		short = "Save live app logs to local files"
+		long  = short + `
+
+Logs are tailed until interrupted and written as JSON lines to files in the
+output directory, named after the app and the time each file was started. A new
+file is started once the current one reaches --rotate-size, measured before
+compression.
+`
+	)
+
+	cmd = command.New("save", short, long, runSave,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.Region(),
+		flag.String{
+			Name:        "instance",
+			Shorthand:   "i",
+			Description: "Filter by instance ID",
+		},
+		flag.StringSlice{
+			Name:        "field",
+			Description: "Only save logs whose field has the given value, in the form of name=value (e.g. level=error). Can be specified multiple times.",
+		},
+		flag.String{
+			Name:        "output",
+			Shorthand:   "o",
+			Description: "The directory to write log files to",
+			Default:     ".",
+		},
+		flag.String{
+			Name:        "rotate-size",
+			Description: "The size at which to start a new log file, e.g. 50MB",
+			Default:     "50MB",
+		},
+		flag.Bool{
+			Name:        "gzip",
+			Description: "Compress log files with gzip",
+		},
+	)
+
+	return
+}
+
+func runSave(ctx context.Context) error {
+	var (
+		client  = client.FromContext(ctx).API()
+		appName = appconfig.NameFromContext(ctx)
+	)
+
+	opts := &logs.LogOptions{
+		AppName:    appName,
+		RegionCode: config.FromContext(ctx).Region,
+		VMID:       flag.GetString(ctx, "instance"),
+	}
+
+	filter, err := parseFieldFilter(flag.GetStringSlice(ctx, "field"))
+	if err != nil {
+		return err
+	}
+
+	maxSize, err := humanize.ParseBytes(flag.GetString(ctx, "rotate-size"))
+	if err != nil {
+		return fmt.Errorf("invalid --rotate-size: %w", err)
+	}
+
+	dir := flag.GetString(ctx, "output")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed creating output directory: %w", err)
+	}
+
+	w := &rotatingFile{
+		dir:     dir,
+		prefix:  appName,
+		maxSize: int64(maxSize),
+		gzip:    flag.GetBool(ctx, "gzip"),
+		onOpen: func(path string) {
+			fmt.Fprintf(iostreams.FromContext(ctx).ErrOut, "Saving logs to %s\n", path)
+		},
+	}
+
+	err = tail(ctx, client, opts, filter, w, true)
+	if closeErr := w.Close(); err == nil || errors.Is(err, context.Canceled) {
+		err = closeErr
+	}
+
+	return err
+}
+
+// rotatingFile writes to files in a directory, starting a new one whenever the
+// current one would grow past maxSize. Instances of rotatingFile are safe for
+// concurrent use.
+type rotatingFile struct {
+	dir     string
+	prefix  string
+	maxSize int64
+	gzip    bool
+	onOpen  func(path string)
+
+	mu   sync.Mutex
+	f    *os.File
+	gz   *gzip.Writer
+	w    io.Writer
+	size int64
+	seq  int
+}
+
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.w != nil && r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r.close(); err != nil {
+			return 0, err
+		}
+	}
+
+	if r.w == nil {
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.w.Write(p)
+	r.size += int64(n)
+
+	return n, err
+}
+
+func (r *rotatingFile) open() error {
+	r.seq++
+
+	name := fmt.Sprintf("%s-%s-%03d.log", r.prefix, time.Now().UTC().Format("20060102T150405Z"), r.seq)
+	if r.gzip {
+		name += ".gz"
+	}
+	path := filepath.Join(r.dir, name)
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+
+	r.f, r.w, r.size = f, f, 0
+	if r.gzip {
+		r.gz = gzip.NewWriter(f)
+		r.w = r.gz
+	}
+
+	if r.onOpen != nil {
+		r.onOpen(path)
+	}
+
+	return nil
+}
+
+func (r *rotatingFile) close() (err error) {
+	if r.gz != nil {
+		err = r.gz.Close()
+	}
+	if closeErr := r.f.Close(); err == nil {
+		err = closeErr
+	}
+
+	r.f, r.gz, r.w = nil, nil, nil
+
+	return err
+}
+
+// Close closes the current file, if any.
+func (r *rotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.w == nil {
+		return nil
+	}
+
+	return r.close()
+}
@@ -0,0 +1,64 @@
+package logs
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatingFile(t *testing.T) {
+	for _, compress := range []bool{false, true} {
+		dir := t.TempDir()
+
+		var opened []string
+		r := &rotatingFile{
+			dir:     dir,
+			prefix:  "app",
+			maxSize: 10,
+			gzip:    compress,
+			onOpen:  func(path string) { opened = append(opened, path) },
+		}
+
+		for _, line := range []string{"aaaa\n", "bbbb\n", "cccc\n", "0123456789abc\n"} {
+			_, err := r.Write([]byte(line))
+			require.NoError(t, err)
+		}
+		require.NoError(t, r.Close())
+
+		require.Len(t, opened, 3)
+		assert.Equal(t, []string{"aaaa\nbbbb\n", "cccc\n", "0123456789abc\n"}, readFiles(t, opened, compress))
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Len(t, entries, 3)
+		if compress {
+			assert.Equal(t, ".gz", filepath.Ext(opened[0]))
+		}
+	}
+}
+
+func readFiles(t *testing.T, paths []string, compressed bool) (contents []string) {
+	for _, path := range paths {
+		f, err := os.Open(path)
+		require.NoError(t, err)
+		defer f.Close()
+
+		var r io.Reader = f
+		if compressed {
+			gz, err := gzip.NewReader(f)
+			require.NoError(t, err)
+			r = gz
+		}
+
+		data, err := io.ReadAll(r)
+		require.NoError(t, err)
+		contents = append(contents, string(data))
+	}
+
+	return
+}
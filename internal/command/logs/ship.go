@@ -12,6 +12,7 @@ import (
 	"github.com/superfly/flyctl/flaps"
 	"github.com/superfly/flyctl/gql"
 	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/cmdutil"
 	"github.com/superfly/flyctl/internal/command"
 	"github.com/superfly/flyctl/internal/flag"
 	"github.com/superfly/flyctl/iostreams"
@@ -20,83 +21,154 @@ import (
 func newShip() (cmd *cobra.Command) {
 
 	const (
-		short = "Ship application logs to Logtail"
-		long  = short + "\n"
+		short = "Ship application logs to a third-party provider"
+		long  = short + `
+
+Logs are shipped by a log shipper machine in a dedicated app of the
+organization. Supported providers are Logtail (provisioned by Fly.io),
+Datadog, Grafana Loki, Amazon S3 and Honeycomb. Provider credentials are
+stored as secrets of the log shipper app.
+
+By default only the logs of the app are shipped. Pass --include-app with
+glob patterns to also ship the logs of matching apps of the organization,
+--exclude-app to leave matching apps out and --min-level to only ship logs
+of a given level and above. Filters aren't supported by Logtail.
+
+The shipper reads logs with a token limited to reading the logs of the app, or
+of the apps of the organization when filters include other apps. Use
+--token-expiry to make it expire, and 'fly logs ship rotate-token' to replace
+it.
+`
 	)
 
 	cmd = command.New("ship", short, long, runSetup, command.RequireSession, command.RequireAppName)
+	cmd.AddCommand(newShipStatus(), newShipUpdate(), newShipRotateToken(), newShipDestroy())
 	flag.Add(cmd,
 		flag.App(),
 		flag.AppConfig(),
+		flag.String{
+			Name:        "provider",
+			Description: "The provider to ship logs to: " + strings.Join(logProviderNames(), ", "),
+		},
+		flag.StringSlice{
+			Name:        "setting",
+			Description: "Provider settings in the form of NAME=VALUE pairs, e.g. DATADOG_API_KEY=xxx. Can be specified multiple times.",
+		},
+		flag.StringSlice{
+			Name:        "include-app",
+			Description: "Also ship the logs of apps matching this glob pattern, e.g. '*-production'. Can be specified multiple times.",
+		},
+		flag.StringSlice{
+			Name:        "exclude-app",
+			Description: "Don't ship the logs of apps matching this glob pattern. Can be specified multiple times.",
+		},
+		flag.String{
+			Name:        "min-level",
+			Description: "Only ship logs of this level and above: " + strings.Join(logLevels, ", "),
+		},
+		tokenExpiryFlag,
 	)
 	return cmd
 }
 
+var tokenExpiryFlag = flag.Duration{
+	Name:        "token-expiry",
+	Description: "How long the token the log shipper reads logs with stays valid, e.g. 720h. Tokens don't expire by default",
+}
+
 func runSetup(ctx context.Context) (err error) {
 	client := client.FromContext(ctx).API().GenqClient
 	io := iostreams.FromContext(ctx)
 	appName := appconfig.NameFromContext(ctx)
-	var logtailToken string
 
+	provider, err := selectLogProvider(ctx, flag.GetString(ctx, "provider"))
 	if err != nil {
 		return err
 	}
 
-	// Fetch the target organization from the app
-	appNameResponse, err := gql.GetApp(ctx, client, appName)
+	filter, err := shipFilterFromFlags(ctx)
+	if err != nil {
+		return err
+	}
+	if provider.Name == "logtail" && !filter.isZero() {
+		return fmt.Errorf("log filters aren't supported when shipping logs to Logtail")
+	}
 
+	settings, err := cmdutil.ParseKVStringsToMap(flag.GetStringSlice(ctx, "setting"))
 	if err != nil {
+		return fmt.Errorf("invalid provider settings: %w", err)
+	}
+	if err := provider.promptSettings(ctx, settings); err != nil {
 		return err
 	}
 
-	targetApp := appNameResponse.App.AppData
-	targetOrg := targetApp.Organization
+	// Fetch the target organization from the app
+	appNameResponse, err := gql.GetApp(ctx, client, appName)
 
 	if err != nil {
 		return err
 	}
 
-	// Fetch or create the Logtail integration for the app
+	targetApp := appNameResponse.App.AppData
+	targetOrg := targetApp.Organization
 
-	var addOnName = appName + "-log-shipper"
-	getAddOnResponse, err := gql.GetAddOn(ctx, client, addOnName)
+	flapsClient, machine, err := EnsureShipperMachine(ctx, targetOrg)
 
 	if err != nil {
+		return
+	}
 
-		input := gql.CreateAddOnInput{
-			OrganizationId: targetOrg.Id,
-			Name:           addOnName,
-			AppId:          targetApp.Id,
-			Type:           "logtail",
+	var logtailToken string
+	if provider.Name == "logtail" {
+		if logtailToken, err = ensureLogtailToken(ctx, targetApp); err != nil {
+			return err
 		}
-
-		createAddOnResponse, err := gql.CreateAddOn(ctx, client, input)
-
-		if err != nil {
+	} else {
+		if err := configureProvider(ctx, flapsClient, machine, provider, appName, settings, filter); err != nil {
 			return err
 		}
+	}
 
-		logtailToken = createAddOnResponse.CreateAddOn.AddOn.Token
-
-	} else {
-		logtailToken = getAddOnResponse.AddOn.Token
+	if err := addLogger(ctx, flapsClient, machine, targetApp, provider.Name, logtailToken, filter.orgWide()); err != nil {
+		return err
 	}
+
+	fmt.Fprintf(io.Out, "Logs for %s are now being shipped to %s\n", appName, provider.Title)
+	return
+}
+
+// addLogger registers the app as a source of the shipper, using a freshly
+// created token limited to reading the app's logs, or the logs of every app of
+// the organization if orgWide is set.
+func addLogger(ctx context.Context, flapsClient *flaps.Client, machine *api.Machine, targetApp gql.AppData, providerName, logtailToken string, orgWide bool) error {
+	var (
+		io     = iostreams.FromContext(ctx)
+		client = client.FromContext(ctx).API().GenqClient
+	)
+
 	// Fetch a macaroon token whose access is limited to reading this app's logs
-	tokenResponse, err := gql.CreateLimitedAccessToken(ctx, client, appName+"-logs", targetOrg.Id, "read_organization_apps", &gql.LimitedAccessTokenOptions{
+	options := &gql.LimitedAccessTokenOptions{
 		"app_ids": []string{targetApp.Name},
-	}, "")
+	}
+	if orgWide {
+		options = &gql.LimitedAccessTokenOptions{}
+	}
 
-	if err != nil {
-		return
+	expiry := ""
+	if d := flag.GetDuration(ctx, tokenExpiryFlag.Name); d != 0 {
+		expiry = d.String()
 	}
 
-	flapsClient, machine, err := EnsureShipperMachine(ctx, targetOrg)
+	tokenResponse, err := gql.CreateLimitedAccessToken(ctx, client, targetApp.Name+"-logs", targetApp.Organization.Id, "read_organization_apps", options, expiry)
 
 	if err != nil {
-		return
+		return err
 	}
 
-	cmd := []string{"/add-logger.sh", targetApp.Name, "logtail", "'" + tokenResponse.CreateLimitedAccessToken.LimitedAccessToken.TokenHeader + "'", logtailToken}
+	cmd := []string{"/add-logger.sh", targetApp.Name, providerName, "'" + tokenResponse.CreateLimitedAccessToken.LimitedAccessToken.TokenHeader + "'"}
+	if logtailToken != "" {
+		cmd = append(cmd, logtailToken)
+	}
 
 	fmt.Fprintf(io.Out, "Add logger source to log shipper VM %s\n", machine.ID)
 	request := &api.MachineExecRequest{
@@ -107,27 +179,87 @@ func runSetup(ctx context.Context) (err error) {
 	response, err := flapsClient.Exec(ctx, machine.ID, request)
 
 	if err != nil {
-		fmt.Fprintf(io.ErrOut, response.StdErr)
+		if response != nil {
+			fmt.Fprint(io.ErrOut, response.StdErr)
+		}
 		return err
 	}
-	return
-}
 
-func EnsureShipperMachine(ctx context.Context, targetOrg gql.AppDataOrganization) (flapsClient *flaps.Client, machine *api.Machine, err error) {
+	return nil
+}
 
+// ensureLogtailToken fetches or creates the Logtail integration for the app and
+// returns its token.
+func ensureLogtailToken(ctx context.Context, targetApp gql.AppData) (string, error) {
 	client := client.FromContext(ctx).API().GenqClient
-	io := iostreams.FromContext(ctx)
 
-	appsResult, err := gql.GetAppsByRole(ctx, client, "log-shipper", targetOrg.Id)
+	var addOnName = targetApp.Name + "-log-shipper"
+	getAddOnResponse, err := gql.GetAddOn(ctx, client, addOnName)
+
+	if err == nil {
+		return getAddOnResponse.AddOn.Token, nil
+	}
+
+	input := gql.CreateAddOnInput{
+		OrganizationId: targetApp.Organization.Id,
+		Name:           addOnName,
+		AppId:          targetApp.Id,
+		Type:           "logtail",
+	}
+
+	createAddOnResponse, err := gql.CreateAddOn(ctx, client, input)
 
 	if err != nil {
-		return nil, nil, err
+		return "", err
 	}
 
+	return createAddOnResponse.CreateAddOn.AddOn.Token, nil
+}
+
+// configureProvider stores the provider settings as secrets of the shipper app,
+// writes the vector config of the app's sink and restarts the shipper so both
+// take effect.
+func configureProvider(ctx context.Context, flapsClient *flaps.Client, machine *api.Machine, provider *logProvider, appName string, settings map[string]string, filter shipFilter) error {
+	var (
+		io     = iostreams.FromContext(ctx)
+		client = client.FromContext(ctx).API()
+	)
+
+	fmt.Fprintf(io.Out, "Setting %s credentials on log shipper app %s\n", provider.Title, flapsClient.AppName())
+
+	if _, err := client.SetSecrets(ctx, flapsClient.AppName(), provider.secrets(appName, settings)); err != nil {
+		return fmt.Errorf("failed setting log shipper secrets: %w", err)
+	}
+
+	flapsClient.Wait(ctx, machine, "started", time.Second*5)
+	response, err := flapsClient.Exec(ctx, machine.ID, &api.MachineExecRequest{
+		Cmd: provider.writeVectorConfigCmd(appName, filter),
+	})
+	if err != nil {
+		if response != nil {
+			fmt.Fprint(io.ErrOut, response.StdErr)
+		}
+		return fmt.Errorf("failed writing log shipper config: %w", err)
+	}
+
+	if err := flapsClient.Restart(ctx, api.RestartMachineInput{ID: machine.ID}, machine.LeaseNonce); err != nil {
+		return fmt.Errorf("failed restarting log shipper: %w", err)
+	}
+
+	return nil
+}
+
+func EnsureShipperMachine(ctx context.Context, targetOrg gql.AppDataOrganization) (flapsClient *flaps.Client, machine *api.Machine, err error) {
+
+	client := client.FromContext(ctx).API().GenqClient
+	io := iostreams.FromContext(ctx)
+
 	var shipperApp gql.AppData
 
-	if len(appsResult.Apps.Nodes) > 0 {
-		shipperApp = appsResult.Apps.Nodes[0].AppData
+	if existing, err := findShipperApp(ctx, targetOrg); err != nil {
+		return nil, nil, err
+	} else if existing != nil {
+		shipperApp = *existing
 	} else {
 		input := gql.DefaultCreateAppInput()
 		input.Machines = true
@@ -171,7 +303,7 @@ func EnsureShipperMachine(ctx context.Context, targetOrg gql.AppDataOrganization
 				CPUs:     1,
 				MemoryMB: 256,
 			},
-			Image: "flyio/log-shipper:auto-a14aa63",
+			Image: shipperImage,
 		}
 
 		launchInput := api.LaunchMachineInput{
@@ -199,3 +331,39 @@ func EnsureShipperMachine(ctx context.Context, targetOrg gql.AppDataOrganization
 	}
 	return
 }
+
+// shipperImage is the log shipper image new shipper machines run, and that
+// `fly logs ship update` updates existing ones to.
+const shipperImage = "flyio/log-shipper:auto-a14aa63"
+
+// findShipperApp returns the log shipper app of the organization, or nil if it
+// has none.
+func findShipperApp(ctx context.Context, targetOrg gql.AppDataOrganization) (*gql.AppData, error) {
+	client := client.FromContext(ctx).API().GenqClient
+
+	appsResult, err := gql.GetAppsByRole(ctx, client, "log-shipper", targetOrg.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(appsResult.Apps.Nodes) == 0 {
+		return nil, nil
+	}
+
+	return &appsResult.Apps.Nodes[0].AppData, nil
+}
+
+// shipperForApp returns the app named in the context along with the log shipper
+// app of its organization, which is nil if there's none.
+func shipperForApp(ctx context.Context) (targetApp gql.AppData, shipperApp *gql.AppData, err error) {
+	client := client.FromContext(ctx).API().GenqClient
+
+	appResponse, err := gql.GetApp(ctx, client, appconfig.NameFromContext(ctx))
+	if err != nil {
+		return
+	}
+	targetApp = appResponse.App.AppData
+
+	shipperApp, err = findShipperApp(ctx, targetApp.Organization)
+	return
+}
@@ -0,0 +1,70 @@
+package logs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newShipDestroy() (cmd *cobra.Command) {
+	const (
+		short = "Destroy the organization's log shipper"
+		long  = `Destroy the log shipper app of the organization, which stops shipping the
+logs of every app of the organization. Logs already shipped are preserved by
+their provider.
+`
+	)
+
+	cmd = command.New("destroy", short, long, runShipDestroy, command.RequireSession, command.RequireAppName)
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.Yes(),
+	)
+	return cmd
+}
+
+func runShipDestroy(ctx context.Context) error {
+	var (
+		io       = iostreams.FromContext(ctx)
+		colorize = io.ColorScheme()
+		client   = client.FromContext(ctx).API()
+	)
+
+	targetApp, shipperApp, err := shipperForApp(ctx)
+	if err != nil {
+		return err
+	}
+	if shipperApp == nil {
+		return fmt.Errorf("no log shipper found for organization %s", targetApp.Organization.Slug)
+	}
+
+	if !flag.GetYes(ctx) {
+		fmt.Fprintln(io.ErrOut, colorize.Red("Logs of every app of the organization will stop being shipped."))
+
+		switch confirmed, err := prompt.Confirmf(ctx, "Destroy log shipper app %s?", shipperApp.Name); {
+		case err == nil:
+			if !confirmed {
+				return nil
+			}
+		case prompt.IsNonInteractive(err):
+			return prompt.NonInteractiveError("yes flag must be specified when not running interactively")
+		default:
+			return err
+		}
+	}
+
+	if err := client.DeleteApp(ctx, shipperApp.Name); err != nil {
+		return fmt.Errorf("failed destroying log shipper app %s: %w", shipperApp.Name, err)
+	}
+
+	fmt.Fprintf(io.Out, "Destroyed log shipper app %s\n", shipperApp.Name)
+	return nil
+}
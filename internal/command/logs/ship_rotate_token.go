@@ -0,0 +1,58 @@
+package logs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/gql"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+)
+
+func newShipRotateToken() (cmd *cobra.Command) {
+	const (
+		short = "Rotate the token the log shipper reads the app's logs with"
+		long  = short + `
+
+A new token, limited to reading the logs the shipper ships for the app, is
+created and handed to the shipper. Previous tokens are no longer used by the
+shipper but stay valid until they expire, so consider using --token-expiry.
+`
+	)
+
+	cmd = command.New("rotate-token", short, long, runShipRotateToken, command.RequireSession, command.RequireAppName)
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		tokenExpiryFlag,
+	)
+	return cmd
+}
+
+func runShipRotateToken(ctx context.Context) error {
+	targetApp, shipperApp, err := shipperForApp(ctx)
+	if err != nil {
+		return err
+	}
+	if shipperApp == nil {
+		return fmt.Errorf("no log shipper found, run `fly logs ship` to set one up")
+	}
+
+	flapsClient, err := flaps.New(ctx, gql.ToAppCompact(*shipperApp))
+	if err != nil {
+		return err
+	}
+
+	machines, err := flapsClient.List(ctx, "")
+	if err != nil {
+		return fmt.Errorf("failed listing log shipper machines: %w", err)
+	}
+	if len(machines) == 0 {
+		return fmt.Errorf("log shipper app %s has no machines, run `fly logs ship` to launch one", shipperApp.Name)
+	}
+
+	return rotateShipperToken(ctx, flapsClient, machines[0], targetApp)
+}
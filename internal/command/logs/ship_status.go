@@ -0,0 +1,153 @@
+package logs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/gql"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newShipStatus() (cmd *cobra.Command) {
+	const (
+		short = "Show the status of the organization's log shipper"
+		long  = short + "\n"
+	)
+
+	cmd = command.New("status", short, long, runShipStatus, command.RequireSession, command.RequireAppName)
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.JSONOutput(),
+	)
+	return cmd
+}
+
+// shippedSink is a sink the log shipper forwards an app's logs to.
+type shippedSink struct {
+	App      string `json:"app"`
+	Provider string `json:"provider"`
+}
+
+type shipperMachineStatus struct {
+	ID     string `json:"id"`
+	State  string `json:"state"`
+	Region string `json:"region"`
+	Image  string `json:"image"`
+	Checks string `json:"checks"`
+}
+
+func runShipStatus(ctx context.Context) error {
+	var (
+		io  = iostreams.FromContext(ctx)
+		cfg = config.FromContext(ctx)
+	)
+
+	_, shipperApp, err := shipperForApp(ctx)
+	if err != nil {
+		return err
+	}
+	if shipperApp == nil {
+		return fmt.Errorf("no log shipper found, run `fly logs ship` to set one up")
+	}
+
+	flapsClient, err := flaps.New(ctx, gql.ToAppCompact(*shipperApp))
+	if err != nil {
+		return err
+	}
+
+	machines, err := flapsClient.List(ctx, "")
+	if err != nil {
+		return fmt.Errorf("failed listing log shipper machines: %w", err)
+	}
+
+	var (
+		statuses = make([]shipperMachineStatus, 0, len(machines))
+		sinks    []shippedSink
+	)
+	for _, m := range machines {
+		statuses = append(statuses, shipperMachineStatus{
+			ID:     m.ID,
+			State:  m.State,
+			Region: m.Region,
+			Image:  m.ImageRefWithVersion(),
+			Checks: formatShipperChecks(m),
+		})
+
+		if m.State == api.MachineStateStarted {
+			machineSinks, err := configuredSinks(ctx, flapsClient, m)
+			if err != nil {
+				fmt.Fprintf(io.ErrOut, "Failed listing sinks of %s: %v\n", m.ID, err)
+				continue
+			}
+			sinks = append(sinks, machineSinks...)
+		}
+	}
+
+	if cfg.JSONOutput {
+		return render.Output(ctx, io.Out, struct {
+			App      string                 `json:"app"`
+			Machines []shipperMachineStatus `json:"machines"`
+			Sinks    []shippedSink          `json:"sinks"`
+		}{shipperApp.Name, statuses, sinks})
+	}
+
+	fmt.Fprintf(io.Out, "Log shipper app: %s\n\n", shipperApp.Name)
+
+	machineRows := make([][]string, 0, len(statuses))
+	for _, s := range statuses {
+		machineRows = append(machineRows, []string{s.ID, s.State, s.Region, s.Image, s.Checks})
+	}
+	if err := render.Table(io.Out, "Machines", machineRows, "ID", "State", "Region", "Image", "Checks"); err != nil {
+		return err
+	}
+
+	sinkRows := make([][]string, 0, len(sinks))
+	for _, s := range sinks {
+		sinkRows = append(sinkRows, []string{s.App, s.Provider})
+	}
+	return render.Table(io.Out, "Sinks", sinkRows, "App", "Provider")
+}
+
+func formatShipperChecks(m *api.Machine) string {
+	checks := m.HealthCheckStatus()
+	if checks.Total == 0 {
+		return "-"
+	}
+
+	return fmt.Sprintf("%d/%d passing", checks.Passing, checks.Total)
+}
+
+// configuredSinks lists the provider sinks configured on the shipper machine.
+// Logtail sources are managed by the shipper image itself and aren't listed.
+func configuredSinks(ctx context.Context, flapsClient *flaps.Client, machine *api.Machine) ([]shippedSink, error) {
+	response, err := flapsClient.Exec(ctx, machine.ID, &api.MachineExecRequest{
+		Cmd: "ls /etc/vector/sinks",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var sinks []shippedSink
+	for _, name := range strings.Fields(response.StdOut) {
+		name, ok := strings.CutSuffix(name, ".toml")
+		if !ok {
+			continue
+		}
+
+		if i := strings.LastIndex(name, "-"); i > 0 {
+			sinks = append(sinks, shippedSink{App: name[:i], Provider: name[i+1:]})
+		}
+	}
+
+	return sinks, nil
+}
@@ -0,0 +1,150 @@
+package logs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/gql"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	mach "github.com/superfly/flyctl/internal/machine"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newShipUpdate() (cmd *cobra.Command) {
+	const (
+		short = "Update the organization's log shipper"
+		long  = `Update the machines of the organization's log shipper to the given image,
+which defaults to the latest log shipper image known to flyctl.
+
+Pass --rotate-token to replace the token the shipper uses to read the app's
+logs with a new one, like 'fly logs ship rotate-token' does.
+`
+	)
+
+	cmd = command.New("update", short, long, runShipUpdate, command.RequireSession, command.RequireAppName)
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.String{
+			Name:        "image",
+			Description: "The log shipper image to run",
+			Default:     shipperImage,
+		},
+		flag.Bool{
+			Name:        "rotate-token",
+			Description: "Rotate the token used to read the app's logs",
+		},
+		tokenExpiryFlag,
+	)
+	return cmd
+}
+
+func runShipUpdate(ctx context.Context) error {
+	io := iostreams.FromContext(ctx)
+
+	targetApp, shipperApp, err := shipperForApp(ctx)
+	if err != nil {
+		return err
+	}
+	if shipperApp == nil {
+		return fmt.Errorf("no log shipper found, run `fly logs ship` to set one up")
+	}
+
+	flapsClient, err := flaps.New(ctx, gql.ToAppCompact(*shipperApp))
+	if err != nil {
+		return err
+	}
+
+	machines, err := flapsClient.List(ctx, "")
+	if err != nil {
+		return fmt.Errorf("failed listing log shipper machines: %w", err)
+	}
+	if len(machines) == 0 {
+		return fmt.Errorf("log shipper app %s has no machines, run `fly logs ship` to launch one", shipperApp.Name)
+	}
+
+	image := flag.GetString(ctx, "image")
+	for _, m := range machines {
+		if m.Config.Image == image {
+			fmt.Fprintf(io.Out, "Log shipper VM %s already runs %s\n", m.ID, image)
+			continue
+		}
+
+		conf := mach.CloneConfig(m.Config)
+		conf.Image = image
+
+		fmt.Fprintf(io.Out, "Updating log shipper VM %s to %s\n", m.ID, image)
+		if _, err := flapsClient.Update(ctx, api.LaunchMachineInput{
+			ID:     m.ID,
+			AppID:  shipperApp.Name,
+			Name:   m.Name,
+			Region: m.Region,
+			Config: conf,
+		}, ""); err != nil {
+			return fmt.Errorf("failed updating log shipper VM %s: %w", m.ID, err)
+		}
+	}
+
+	if flag.GetBool(ctx, "rotate-token") {
+		return rotateShipperToken(ctx, flapsClient, machines[0], targetApp)
+	}
+
+	return nil
+}
+
+// rotateShipperToken registers every provider the app's logs are shipped to
+// anew, each time with a new token.
+func rotateShipperToken(ctx context.Context, flapsClient *flaps.Client, machine *api.Machine, targetApp gql.AppData) error {
+	var (
+		io     = iostreams.FromContext(ctx)
+		client = client.FromContext(ctx).API().GenqClient
+	)
+
+	flapsClient.Wait(ctx, machine, "started", time.Second*5)
+	sinks, err := configuredSinks(ctx, flapsClient, machine)
+	if err != nil {
+		return fmt.Errorf("failed listing log shipper sinks: %w", err)
+	}
+
+	var rotated int
+	for _, sink := range sinks {
+		if sink.App != targetApp.Name {
+			continue
+		}
+		provider, err := findLogProvider(sink.Provider)
+		if err != nil {
+			return err
+		}
+
+		filter, err := readSinkFilter(ctx, flapsClient, machine, provider.vectorConfigPath(sink.App))
+		if err != nil {
+			return fmt.Errorf("failed reading log shipper config: %w", err)
+		}
+
+		if err := addLogger(ctx, flapsClient, machine, targetApp, sink.Provider, "", filter.orgWide()); err != nil {
+			return err
+		}
+		rotated++
+	}
+
+	if addOn, err := gql.GetAddOn(ctx, client, targetApp.Name+"-log-shipper"); err == nil {
+		if err := addLogger(ctx, flapsClient, machine, targetApp, "logtail", addOn.AddOn.Token, false); err != nil {
+			return err
+		}
+		rotated++
+	}
+
+	if rotated == 0 {
+		return fmt.Errorf("logs of %s aren't shipped, run `fly logs ship` to set up shipping", targetApp.Name)
+	}
+
+	fmt.Fprintf(io.Out, "Rotated the log shipping token of %s\n", targetApp.Name)
+	return nil
+}
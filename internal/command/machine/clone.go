@@ -24,7 +24,13 @@ import (
 func newClone() *cobra.Command {
 	const (
 		short = "Clone a Fly machine"
-		long  = short + "\n"
+		long  = short + `
+
+Volumes attached to the source machine are cloned as new volumes, empty or
+restored from a snapshot with --from-snapshot. To move a stateful machine to
+another region, use --region along with --across-region which restores the
+new volumes from the latest snapshot of the source volumes.
+`
 
 		usage = "clone <machine_id>"
 	)
@@ -58,6 +64,10 @@ func newClone() *cobra.Command {
 			Name:        "attach-volume",
 			Description: "Existing volume to attach to the new machine",
 		},
+		flag.Bool{
+			Name:        "across-region",
+			Description: "Clone a machine with volumes into the region given by --region, creating its volumes there from the latest snapshot of the source volumes",
+		},
 		flag.String{
 			Name:        "process-group",
 			Description: "For machines that are part of Fly Apps v2 does a regular clone and changes the process group to what is specified here",
@@ -110,6 +120,20 @@ func runMachineClone(ctx context.Context) (err error) {
 		region = source.Region
 	}
 
+	snapshotFlag := flag.GetString(ctx, "from-snapshot")
+	acrossRegion := flag.GetBool(ctx, "across-region")
+	if acrossRegion {
+		switch {
+		case region == source.Region:
+			return fmt.Errorf("--across-region requires --region to be set to a region other than %s", source.Region)
+		case flag.GetString(ctx, "attach-volume") != "":
+			return fmt.Errorf("--across-region and --attach-volume can't be used together")
+		case snapshotFlag != "" && snapshotFlag != "last":
+			return fmt.Errorf("--across-region always restores from the latest snapshot and can't be used with --from-snapshot %s", snapshotFlag)
+		}
+		snapshotFlag = "last"
+	}
+
 	fmt.Fprintf(out, "Cloning machine %s into region %s\n", colorize.Bold(source.ID), colorize.Bold(region))
 
 	targetConfig := source.Config
@@ -179,7 +203,7 @@ func runMachineClone(ctx context.Context) (err error) {
 			}
 		} else {
 			var snapshotID *string
-			switch snapID := flag.GetString(ctx, "from-snapshot"); snapID {
+			switch snapID := snapshotFlag; snapID {
 			case "last":
 				snapshots, err := client.GetVolumeSnapshots(ctx, mnt.Volume)
 				if err != nil {
@@ -189,6 +213,8 @@ func runMachineClone(ctx context.Context) (err error) {
 					snapshot := lo.MaxBy(snapshots, func(i, j api.Snapshot) bool { return i.CreatedAt.After(j.CreatedAt) })
 					snapshotID = &snapshot.ID
 					fmt.Fprintf(out, "Creating new volume from snapshot %s of %s\n", colorize.Bold(*snapshotID), colorize.Bold(mnt.Volume))
+				} else if acrossRegion {
+					return fmt.Errorf("source volume %s has no snapshot to copy into region %s", mnt.Volume, region)
 				} else {
 					fmt.Fprintf(out, "No snapshot for source volume %s, the new volume will start empty\n", colorize.Bold(mnt.Volume))
 					snapshotID = nil
@@ -209,6 +235,17 @@ func runMachineClone(ctx context.Context) (err error) {
 				SnapshotID:        snapshotID,
 				RequireUniqueZone: false,
 			}
+			if acrossRegion {
+				// mounts don't always carry the details of their volume,
+				// the copy must match the source volume
+				sourceVol, err := client.GetVolume(ctx, mnt.Volume)
+				if err != nil {
+					return fmt.Errorf("could not get source volume: %w", err)
+				}
+				volInput.Name = sourceVol.Name
+				volInput.SizeGb = lo.Max([]int{mnt.SizeGb, sourceVol.SizeGb})
+				volInput.Encrypted = sourceVol.Encrypted
+			}
 			vol, err = client.CreateVolume(ctx, volInput)
 			if err != nil {
 				return err
@@ -0,0 +1,100 @@
+package machine
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	mach "github.com/superfly/flyctl/internal/machine"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newCordon() *cobra.Command {
+	const (
+		short = "Take one or more Fly machines out of service"
+		long  = short + `
+
+Cordoned machines are removed from load balancing and skipped by deployments
+and rolling updates, so they can be debugged without receiving traffic or
+being replaced. Use 'fly machine uncordon' to return them to service.
+`
+
+		usage = "cordon <id> [<id>...]"
+	)
+
+	cmd := command.New(usage, short, long, runMachineCordon,
+		command.RequireSession,
+		command.LoadAppNameIfPresent,
+	)
+
+	cmd.Args = cobra.ArbitraryArgs
+
+	flag.Add(
+		cmd,
+		flag.App(),
+		flag.AppConfig(),
+		selectFlag,
+	)
+
+	return cmd
+}
+
+func newUncordon() *cobra.Command {
+	const (
+		short = "Return one or more cordoned Fly machines to service"
+		long  = short + "\n"
+
+		usage = "uncordon <id> [<id>...]"
+	)
+
+	cmd := command.New(usage, short, long, runMachineUncordon,
+		command.RequireSession,
+		command.LoadAppNameIfPresent,
+	)
+
+	cmd.Args = cobra.ArbitraryArgs
+
+	flag.Add(
+		cmd,
+		flag.App(),
+		flag.AppConfig(),
+		selectFlag,
+	)
+
+	return cmd
+}
+
+func runMachineCordon(ctx context.Context) error {
+	return cordonMachines(ctx, mach.Cordon, "cordon")
+}
+
+func runMachineUncordon(ctx context.Context) error {
+	return cordonMachines(ctx, mach.Uncordon, "uncordon")
+}
+
+func cordonMachines(ctx context.Context, apply func(context.Context, *api.Machine) error, action string) error {
+	io := iostreams.FromContext(ctx)
+
+	machines, ctx, err := selectManyMachines(ctx, flag.Args(ctx))
+	if err != nil {
+		return err
+	}
+
+	machines, releaseLeaseFunc, err := mach.AcquireLeases(ctx, machines)
+	defer releaseLeaseFunc(ctx, machines)
+	if err != nil {
+		return err
+	}
+
+	for _, machine := range machines {
+		if err := apply(ctx, machine); err != nil {
+			return fmt.Errorf("failed to %s machine %s: %w", action, machine.ID, err)
+		}
+		fmt.Fprintf(io.Out, "%s has been %sed\n", machine.ID, action)
+	}
+
+	return nil
+}
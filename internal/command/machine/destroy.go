@@ -2,25 +2,40 @@ package machine
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"strings"
+	"time"
 
+	"github.com/samber/lo"
 	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+
 	"github.com/superfly/flyctl/api"
 	"github.com/superfly/flyctl/client"
 	"github.com/superfly/flyctl/flaps"
 	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/cmdutil"
 	"github.com/superfly/flyctl/internal/command"
 	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/prompt"
 	"github.com/superfly/flyctl/iostreams"
 )
 
 func newDestroy() *cobra.Command {
 	const (
-		short = "Destroy a Fly machine."
-		long  = `Destroy a Fly machine.
-This command requires a machine to be in a stopped state unless the force flag is used.
+		short = "Destroy Fly machines."
+		long  = `Destroy Fly machines.
+This command requires machines to be in a stopped state unless the force flag is used.
+
+Machines may be given by ID, picked using --select, or selected by process
+group, region or metadata using --process-group, --region and --metadata.
+
+Volumes attached to destroyed machines are kept unless --destroy-volumes is
+used, in which case they are destroyed once their machine is.
 `
-		usage = "destroy <id>"
+		usage = "destroy [<id>...]"
 	)
 
 	cmd := command.New(usage, short, long, runMachineDestroy,
@@ -34,28 +49,53 @@ This command requires a machine to be in a stopped state unless the force flag i
 		cmd,
 		flag.App(),
 		flag.AppConfig(),
+		flag.Yes(),
 		selectFlag,
 		flag.Bool{
 			Name:        "force",
 			Shorthand:   "f",
 			Description: "force kill machine regardless of current state",
 		},
+		flag.Bool{
+			Name:        "wait",
+			Description: "Wait for machines to be destroyed",
+		},
+		flag.Bool{
+			Name:        "keep-volumes",
+			Description: "Keep the volumes attached to the machines (default)",
+		},
+		flag.Bool{
+			Name:        "destroy-volumes",
+			Description: "Destroy the volumes attached to the machines once they're destroyed",
+		},
+		flag.StringSlice{
+			Name:        "process-group",
+			Description: "Destroy the machines of the given process group. Can be specified multiple times.",
+		},
+		flag.Region(),
+		flag.StringSlice{
+			Name:        "metadata",
+			Description: "Destroy the machines with the given metadata, in the form of key=value. Can be specified multiple times.",
+		},
 	)
 
-	cmd.Args = cobra.RangeArgs(0, 1)
+	cmd.Args = cobra.ArbitraryArgs
 
 	return cmd
 }
 
 func runMachineDestroy(ctx context.Context) (err error) {
 	var (
-		out   = iostreams.FromContext(ctx).Out
-		force = flag.GetBool(ctx, "force")
+		out            = iostreams.FromContext(ctx).Out
+		force          = flag.GetBool(ctx, "force")
+		destroyVolumes = flag.GetBool(ctx, "destroy-volumes")
 	)
 
-	machineID := flag.FirstArg(ctx)
-	haveMachineID := len(flag.Args(ctx)) > 0
-	current, ctx, err := selectOneMachine(ctx, nil, machineID, haveMachineID)
+	if destroyVolumes && flag.GetBool(ctx, "keep-volumes") {
+		return errors.New("--keep-volumes and --destroy-volumes are mutually exclusive")
+	}
+
+	machines, ctx, err := selectMachinesToDestroy(ctx)
 	if err != nil {
 		return err
 	}
@@ -68,12 +108,153 @@ func runMachineDestroy(ctx context.Context) (err error) {
 		return fmt.Errorf("could not get app '%s': %w", appName, err)
 	}
 
-	err = Destroy(ctx, app, current, force)
+	volumes := attachedVolumes(machines)
+	if destroyVolumes && len(volumes) > 0 {
+		switch confirmed, err := confirmVolumesDestroy(ctx, volumes); {
+		case err != nil:
+			return err
+		case !confirmed:
+			return nil
+		}
+	}
+
+	for _, machine := range machines {
+		if err := Destroy(ctx, app, machine, force); err != nil {
+			return err
+		}
+
+		if flag.GetBool(ctx, "wait") || destroyVolumes {
+			if err := waitForDestroyed(ctx, machine); err != nil {
+				return err
+			}
+		}
+
+		fmt.Fprintf(out, "%s has been destroyed\n", machine.ID)
+	}
+
+	for _, volumeID := range volumes {
+		if !destroyVolumes {
+			fmt.Fprintf(out, "volume %s was kept, destroy it with `fly volumes destroy %s` if it's no longer needed\n", volumeID, volumeID)
+			continue
+		}
+
+		if _, err := client.DeleteVolume(ctx, volumeID, ""); err != nil {
+			return fmt.Errorf("failed destroying volume %s: %w", volumeID, err)
+		}
+		fmt.Fprintf(out, "volume %s has been destroyed\n", volumeID)
+	}
+
+	return nil
+}
+
+// selectMachinesToDestroy returns the machines given as arguments, picked with
+// --select, or matching the selector flags.
+func selectMachinesToDestroy(ctx context.Context) ([]*api.Machine, context.Context, error) {
+	var (
+		groups   = flag.GetStringSlice(ctx, "process-group")
+		region   = flag.GetRegion(ctx)
+		metadata = flag.GetStringSlice(ctx, "metadata")
+	)
+
+	if len(groups) == 0 && region == "" && len(metadata) == 0 {
+		return selectManyMachines(ctx, flag.Args(ctx))
+	}
+
+	switch {
+	case len(flag.Args(ctx)) > 0 || flag.GetBool(ctx, "select"):
+		return nil, nil, errors.New("machine IDs and --select can't be used with --process-group, --region or --metadata")
+	case appconfig.NameFromContext(ctx) == "":
+		return nil, nil, errors.New("an app name must be specified to select machines by process group, region or metadata")
+	}
+
+	wantMetadata, err := cmdutil.ParseKVStringsToMap(metadata)
 	if err != nil {
-		return err
+		return nil, nil, fmt.Errorf("invalid --metadata: %w", err)
+	}
+
+	ctx, err = buildContextFromAppNameOrMachineID(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	all, err := flaps.FromContext(ctx).ListActive(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not get a list of machines: %w", err)
+	}
+
+	machines := lo.Filter(all, func(m *api.Machine, _ int) bool {
+		if len(groups) > 0 && !slices.Contains(groups, m.ProcessGroup()) {
+			return false
+		}
+		if region != "" && m.Region != region {
+			return false
+		}
+		for k, v := range wantMetadata {
+			if m.Config == nil || m.Config.Metadata[k] != v {
+				return false
+			}
+		}
+		return true
+	})
+
+	if len(machines) == 0 {
+		return nil, nil, errors.New("no machines match the given process groups, regions and metadata")
+	}
+
+	return machines, ctx, nil
+}
+
+func attachedVolumes(machines []*api.Machine) (volumes []string) {
+	for _, m := range machines {
+		if m.Config == nil {
+			continue
+		}
+		for _, mount := range m.Config.Mounts {
+			if mount.Volume != "" {
+				volumes = append(volumes, mount.Volume)
+			}
+		}
+	}
+
+	return volumes
+}
+
+func confirmVolumesDestroy(ctx context.Context, volumes []string) (bool, error) {
+	if flag.GetYes(ctx) {
+		return true, nil
+	}
+
+	const msg = "Destroying volumes permanently deletes their data, there's no going back."
+	fmt.Fprintln(iostreams.FromContext(ctx).ErrOut, iostreams.FromContext(ctx).ColorScheme().Red(msg))
+
+	switch confirmed, err := prompt.Confirmf(ctx, "Destroy volumes %s?", strings.Join(volumes, ", ")); {
+	case err == nil:
+		return confirmed, nil
+	case prompt.IsNonInteractive(err):
+		return false, prompt.NonInteractiveError("yes flag must be specified to destroy volumes when not running interactively")
+	default:
+		return false, err
 	}
+}
+
+func waitForDestroyed(ctx context.Context, machine *api.Machine) error {
+	flapsClient := flaps.FromContext(ctx)
 
-	fmt.Fprintf(out, "%s has been destroyed\n", current.ID)
+	m, err := flapsClient.Get(ctx, machine.ID)
+
+	var flapsErr *flaps.FlapsError
+	switch {
+	case errors.As(err, &flapsErr) && flapsErr.ResponseStatusCode == http.StatusNotFound:
+		return nil
+	case err != nil:
+		return fmt.Errorf("could not get machine %s: %w", machine.ID, err)
+	case m.State == "destroyed":
+		return nil
+	}
+
+	if err := flapsClient.Wait(ctx, m, "destroyed", 60*time.Second); err != nil {
+		return fmt.Errorf("failed waiting for machine %s to be destroyed: %w", machine.ID, err)
+	}
 
 	return nil
 }
@@ -0,0 +1,229 @@
+package machine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+const eventsPollInterval = 2 * time.Second
+
+func newEvents() *cobra.Command {
+	const (
+		short = "Show the lifecycle events of machines"
+		long  = short + `
+
+Shows the events of the given machine, or of every machine of the app when no
+machine is given: starts, exits (including out of memory kills) and health
+check transitions. Use --follow to keep streaming new events, and --json to
+print one JSON object per event for scripting.
+`
+
+		usage = "events [<machine_id>]"
+	)
+
+	cmd := command.New(usage, short, long, runEvents,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	cmd.Args = cobra.RangeArgs(0, 1)
+
+	flag.Add(
+		cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.JSONOutput(),
+		flag.Bool{
+			Name:        "follow",
+			Shorthand:   "f",
+			Description: "Keep streaming new events until interrupted",
+		},
+	)
+
+	return cmd
+}
+
+// machineEvent is a lifecycle event of a machine, or a transition of one of
+// its health checks.
+type machineEvent struct {
+	MachineID string    `json:"machine_id"`
+	Region    string    `json:"region"`
+	Type      string    `json:"type"`
+	Status    string    `json:"status"`
+	Source    string    `json:"source,omitempty"`
+	Check     string    `json:"check,omitempty"`
+	ExitCode  *int      `json:"exit_code,omitempty"`
+	OOMKilled bool      `json:"oom_killed,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// eventTracker remembers the events and check statuses seen so far so that
+// only new ones are reported.
+type eventTracker struct {
+	seen   map[string]bool
+	checks map[string]string
+}
+
+func newEventTracker() *eventTracker {
+	return &eventTracker{
+		seen:   map[string]bool{},
+		checks: map[string]string{},
+	}
+}
+
+// collect returns the events of the machine which weren't seen before, oldest
+// first. Check transitions are only reported once a previous status is known.
+func (t *eventTracker) collect(m *api.Machine, now time.Time) (events []machineEvent) {
+	for _, e := range m.Events {
+		key := fmt.Sprintf("%s/%d/%s/%s", m.ID, e.Timestamp, e.Type, e.Status)
+		if t.seen[key] {
+			continue
+		}
+		t.seen[key] = true
+
+		event := machineEvent{
+			MachineID: m.ID,
+			Region:    m.Region,
+			Type:      e.Type,
+			Status:    e.Status,
+			Source:    e.Source,
+			Timestamp: time.UnixMilli(e.Timestamp),
+		}
+		if e.Request != nil {
+			if code, err := e.Request.GetExitCode(); err == nil {
+				event.ExitCode = &code
+			}
+			if e.Request.ExitEvent != nil {
+				event.OOMKilled = e.Request.ExitEvent.OOMKilled
+			}
+			if e.Request.MonitorEvent != nil && e.Request.MonitorEvent.ExitEvent != nil {
+				event.OOMKilled = event.OOMKilled || e.Request.MonitorEvent.ExitEvent.OOMKilled
+			}
+		}
+		events = append(events, event)
+	}
+
+	for _, c := range m.Checks {
+		key := m.ID + "/" + c.Name
+		previous, known := t.checks[key]
+		t.checks[key] = c.Status
+		if !known || previous == c.Status {
+			continue
+		}
+
+		ts := now
+		if c.UpdatedAt != nil {
+			ts = *c.UpdatedAt
+		}
+		events = append(events, machineEvent{
+			MachineID: m.ID,
+			Region:    m.Region,
+			Type:      "check",
+			Status:    c.Status,
+			Check:     c.Name,
+			Timestamp: ts,
+		})
+	}
+
+	slices.SortStableFunc(events, func(a, b machineEvent) bool { return a.Timestamp.Before(b.Timestamp) })
+
+	return events
+}
+
+func runEvents(ctx context.Context) error {
+	var (
+		io      = iostreams.FromContext(ctx)
+		appName = appconfig.NameFromContext(ctx)
+		asJSON  = config.FromContext(ctx).JSONOutput
+		follow  = flag.GetBool(ctx, "follow")
+	)
+
+	flapsClient, err := flaps.NewFromAppName(ctx, appName)
+	if err != nil {
+		return fmt.Errorf("could not create flaps client: %w", err)
+	}
+
+	list := func() ([]*api.Machine, error) {
+		if machineID := flag.FirstArg(ctx); machineID != "" {
+			m, err := flapsClient.Get(ctx, machineID)
+			if err != nil {
+				return nil, fmt.Errorf("could not get machine %s: %w", machineID, err)
+			}
+			return []*api.Machine{m}, nil
+		}
+
+		machines, err := flapsClient.List(ctx, "")
+		if err != nil {
+			return nil, fmt.Errorf("could not list machines: %w", err)
+		}
+		return machines, nil
+	}
+
+	tracker := newEventTracker()
+	for {
+		machines, err := list()
+		if err != nil {
+			return err
+		}
+
+		var events []machineEvent
+		for _, m := range machines {
+			events = append(events, tracker.collect(m, time.Now())...)
+		}
+		slices.SortStableFunc(events, func(a, b machineEvent) bool { return a.Timestamp.Before(b.Timestamp) })
+
+		for _, e := range events {
+			if err := writeEvent(io.Out, e, asJSON); err != nil {
+				return err
+			}
+		}
+
+		if !follow {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(eventsPollInterval):
+		}
+	}
+}
+
+func writeEvent(w io.Writer, e machineEvent, asJSON bool) error {
+	if asJSON {
+		return json.NewEncoder(w).Encode(e)
+	}
+
+	var info []string
+	if e.Check != "" {
+		info = append(info, "check="+e.Check)
+	}
+	if e.ExitCode != nil {
+		info = append(info, fmt.Sprintf("exit_code=%d", *e.ExitCode))
+	}
+	if e.OOMKilled {
+		info = append(info, "oom_killed=true")
+	}
+
+	line := fmt.Sprintf("%s %s [%s] %s %s %s",
+		e.Timestamp.UTC().Format(time.RFC3339), e.MachineID, e.Region, e.Type, e.Status, strings.Join(info, " "))
+	_, err := fmt.Fprintln(w, strings.TrimSpace(line))
+
+	return err
+}
@@ -2,7 +2,9 @@ package machine
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/superfly/flyctl/api"
@@ -10,6 +12,7 @@ import (
 	"github.com/superfly/flyctl/internal/command"
 	"github.com/superfly/flyctl/internal/config"
 	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/flyerr"
 	"github.com/superfly/flyctl/internal/render"
 	"github.com/superfly/flyctl/iostreams"
 )
@@ -18,8 +21,14 @@ func newMachineExec() *cobra.Command {
 
 	const (
 		short = "Execute a command on a machine"
-		long  = short + "\n"
-		usage = "exec <machine-id> <command>"
+		long  = short + `
+
+Runs the command through the Machines API, without establishing a WireGuard
+tunnel or an SSH session, and prints its output. The command's arguments can
+be given after '--', e.g. 'fly machine exec <machine-id> -- cat /etc/hosts'.
+flyctl exits with the exit code of the command.
+`
+		usage = "exec [<machine-id>] [--] <command> [<args>...]"
 	)
 
 	cmd := command.New(usage, short, long, runMachineExec,
@@ -39,7 +48,7 @@ func newMachineExec() *cobra.Command {
 		},
 	)
 
-	cmd.Args = cobra.RangeArgs(1, 2)
+	cmd.Args = cobra.MinimumNArgs(1)
 
 	return cmd
 }
@@ -52,15 +61,32 @@ func runMachineExec(ctx context.Context) (err error) {
 
 		machineID     string
 		haveMachineID bool
-		command       string
+		execCommand   string
 	)
 
-	if len(args) == 2 {
-		machineID = args[0]
-		haveMachineID = true
-		command = args[1]
+	if dash := command.FromContext(ctx).ArgsLenAtDash(); dash >= 0 {
+		if dash > 1 {
+			return errors.New("only a machine ID can be given before '--'")
+		}
+		if dash == len(args) {
+			return errors.New("a command must be given after '--'")
+		}
+		if dash == 1 {
+			machineID = args[0]
+			haveMachineID = true
+		}
+		execCommand = joinCommand(args[dash:])
 	} else {
-		command = args[0]
+		switch len(args) {
+		case 1:
+			execCommand = args[0]
+		case 2:
+			machineID = args[0]
+			haveMachineID = true
+			execCommand = args[1]
+		default:
+			return errors.New("too many arguments, give the command's arguments after '--'")
+		}
 	}
 
 	current, ctx, err := selectOneMachine(ctx, nil, machineID, haveMachineID)
@@ -72,7 +98,7 @@ func runMachineExec(ctx context.Context) (err error) {
 	var timeout = flag.GetInt(ctx, "timeout")
 
 	in := &api.MachineExecRequest{
-		Cmd:     command,
+		Cmd:     execCommand,
 		Timeout: timeout,
 	}
 
@@ -82,11 +108,13 @@ func runMachineExec(ctx context.Context) (err error) {
 	}
 
 	if config.JSONOutput {
-		return render.JSON(io.Out, out)
-	}
-
-	if out.ExitCode != 0 {
-		fmt.Fprintf(io.Out, "Exit code: %d\n", out.ExitCode)
+		if err := render.Output(ctx, io.Out, out); err != nil {
+			return err
+		}
+		if out.ExitCode != 0 {
+			return &flyerr.ExitCodeError{Code: int(out.ExitCode)}
+		}
+		return nil
 	}
 
 	if out.StdOut != "" {
@@ -96,5 +124,31 @@ func runMachineExec(ctx context.Context) (err error) {
 		fmt.Fprint(io.ErrOut, out.StdErr)
 	}
 
+	if out.ExitCode != 0 {
+		fmt.Fprintf(io.ErrOut, "Exit code: %d\n", out.ExitCode)
+		return &flyerr.ExitCodeError{Code: int(out.ExitCode)}
+	}
+
 	return
 }
+
+// joinCommand joins the arguments of a command into a single command line,
+// quoting the ones a POSIX shell would otherwise split or expand.
+func joinCommand(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = shellQuote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// shellQuote returns arg single-quoted unless it's made of characters shells
+// leave alone. Single quotes within arg are closed, escaped and reopened.
+func shellQuote(arg string) string {
+	if arg != "" && strings.Trim(arg, shellSafeChars) == "" {
+		return arg
+	}
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+const shellSafeChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789@%_+=:,./-"
@@ -0,0 +1,27 @@
+package machine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJoinCommand(t *testing.T) {
+	cases := []struct {
+		args []string
+		want string
+	}{
+		{args: []string{"ls", "-la", "/app"}, want: "ls -la /app"},
+		{args: []string{"echo", ""}, want: "echo ''"},
+		{args: []string{"echo", "hello world"}, want: "echo 'hello world'"},
+		{args: []string{"echo", "it's"}, want: `echo 'it'\''s'`},
+		{args: []string{"echo", `"quoted"`}, want: `echo '"quoted"'`},
+		{args: []string{"echo", "$HOME", "`id`"}, want: "echo '$HOME' '`id`'"},
+		{args: []string{"printf", `a\nb`}, want: `printf 'a\nb'`},
+		{args: []string{"echo", "héllo"}, want: "echo 'héllo'"},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.want, joinCommand(c.args), c.args)
+	}
+}
@@ -117,7 +117,7 @@ func runLeaseView(ctx context.Context) (err error) {
 	}
 
 	if cfg.JSONOutput {
-		return render.JSON(io.Out, leases)
+		return render.Output(ctx, io.Out, leases)
 	}
 
 	if len(leases) == 0 {
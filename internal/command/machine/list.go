@@ -87,7 +87,7 @@ func runMachineList(ctx context.Context) (err error) {
 	}
 
 	if cfg.JSONOutput {
-		return render.JSON(io.Out, machines)
+		return render.Output(ctx, io.Out, machines)
 	}
 
 	rows := [][]string{}
@@ -126,7 +126,7 @@ func runMachineList(ctx context.Context) (err error) {
 
 			}
 
-			rows = append(rows, []string{
+			row := []string{
 				machine.ID,
 				machine.Name,
 				machine.State,
@@ -138,11 +138,23 @@ func runMachineList(ctx context.Context) (err error) {
 				machine.UpdatedAt,
 				appPlatform,
 				machineProcessGroup,
-			})
+			}
+			if cfg.WideOutput {
+				var size string
+				if machine.Config != nil && machine.Config.Guest != nil {
+					size = machine.Config.Guest.ToSize()
+				}
+				row = append(row, size, machine.InstanceID)
+			}
+			rows = append(rows, row)
 
 		}
 
-		_ = render.Table(io.Out, appName, rows, "ID", "Name", "State", "Region", "Image", "IP Address", "Volume", "Created", "Last Updated", "App Platform", "Process Group")
+		cols := []string{"ID", "Name", "State", "Region", "Image", "IP Address", "Volume", "Created", "Last Updated", "App Platform", "Process Group"}
+		if cfg.WideOutput {
+			cols = append(cols, "Size", "Instance ID")
+		}
+		_ = render.Table(io.Out, appName, rows, cols...)
 	}
 	return nil
 }
@@ -32,6 +32,9 @@ func New() *cobra.Command {
 		newRestart(),
 		newLeases(),
 		newMachineExec(),
+		newEvents(),
+		newCordon(),
+		newUncordon(),
 	)
 
 	return cmd
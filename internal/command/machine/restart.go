@@ -16,9 +16,14 @@ import (
 func newRestart() *cobra.Command {
 	const (
 		short = "Restart one or more Fly machines"
-		long  = short + "\n"
+		long  = short + `
 
-		usage = "restart <id> [<id>...]"
+Machines are given by their IDs, picked interactively with --select, or
+matched by the --select-region, --select-group, --select-image-tag and
+--select-metadata flags, e.g. '--select-group worker --select-region fra'.
+`
+
+		usage = "restart [<id>...]"
 	)
 
 	cmd := command.New(usage, short, long, runMachineRestart,
@@ -52,7 +57,9 @@ func newRestart() *cobra.Command {
 			Description: "Restarts app without waiting for health checks. ( Machines only )",
 			Default:     false,
 		},
+		flag.Yes(),
 	)
+	flag.Add(cmd, selectorFlags...)
 
 	return cmd
 }
@@ -2,7 +2,9 @@ package machine
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"os"
 	"path"
 	"path/filepath"
 	"strconv"
@@ -25,10 +27,13 @@ import (
 	"github.com/superfly/flyctl/internal/build/imgsrc"
 	"github.com/superfly/flyctl/internal/cmdutil"
 	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
 	"github.com/superfly/flyctl/internal/env"
 	"github.com/superfly/flyctl/internal/flag"
 	mach "github.com/superfly/flyctl/internal/machine"
+	"github.com/superfly/flyctl/internal/policy"
 	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/internal/render"
 	"github.com/superfly/flyctl/internal/state"
 	"github.com/superfly/flyctl/internal/watch"
 )
@@ -142,6 +147,10 @@ var sharedFlags = flag.Set{
 		Name:        "standby-for",
 		Description: "Comma separated list of machine ids to watch for",
 	},
+	flag.String{
+		Name:        "machine-config",
+		Description: "Read machine config from a JSON or YAML file, or an inline JSON string. Other flags take precedence over its values",
+	},
 }
 
 var s = spinner.New(spinner.CharSets[9], 100*time.Millisecond)
@@ -149,7 +158,12 @@ var s = spinner.New(spinner.CharSets[9], 100*time.Millisecond)
 func newRun() *cobra.Command {
 	const (
 		short = "Run a machine"
-		long  = short + "\n"
+		long  = short + `
+
+Machines run with --rm are treated as one-off tasks: unless --detach is set,
+the command waits for the machine to exit, then prints how long it ran, its
+size and the estimated cost of the run.
+`
 
 		usage = "run <image> [command]"
 	)
@@ -180,6 +194,7 @@ func newRun() *cobra.Command {
 			Name:        "rm",
 			Description: "Automatically remove the machine when it exits",
 		},
+		flag.JSONOutput(),
 		flag.StringSlice{
 			Name:        "volume",
 			Shorthand:   "v",
@@ -277,6 +292,14 @@ func runMachineRun(ctx context.Context) error {
 		return err
 	}
 
+	orgPolicy, err := policy.Load(ctx, app.Organization.Slug)
+	if err != nil {
+		return err
+	}
+	if err := orgPolicy.Error(orgPolicy.MachineViolations(input.Region, machineConf.Guest)); err != nil {
+		return err
+	}
+
 	if flag.GetBool(ctx, "build-only") {
 		return nil
 	}
@@ -309,6 +332,11 @@ func runMachineRun(ctx context.Context) error {
 		return err
 	}
 
+	session := mach.NewSession(machine)
+	if session.Guest == nil {
+		session.Guest = machineConf.Guest
+	}
+
 	if !flag.GetDetach(ctx) {
 		fmt.Fprintln(io.Out, colorize.Green("==> "+"Monitoring health checks"))
 
@@ -321,7 +349,17 @@ func runMachineRun(ctx context.Context) error {
 	fmt.Fprintf(io.Out, "Machine started, you can connect via the following private ip\n")
 	fmt.Fprintf(io.Out, "  %s\n", privateIP)
 
-	return nil
+	if !machineConf.AutoDestroy || flag.GetDetach(ctx) {
+		return nil
+	}
+
+	fmt.Fprintf(io.Out, "\nWaiting for machine %s to exit...\n", machine.ID)
+	if err := mach.WaitForExit(ctx, machine); err != nil {
+		return fmt.Errorf("failed waiting for machine to exit: %w", err)
+	}
+	session.End()
+
+	return session.Render(ctx, io.Out, config.FromContext(ctx).JSONOutput)
 }
 
 func createApp(ctx context.Context, message, name string, client *api.Client) (*api.AppCompact, error) {
@@ -370,6 +408,31 @@ func createApp(ctx context.Context, message, name string, client *api.Client) (*
 	}, nil
 }
 
+// readMachineConfig overlays the machine config found in v onto conf. v is
+// either an inline JSON object or the path to a JSON or YAML file.
+func readMachineConfig(v string, conf *api.MachineConfig) error {
+	data := []byte(v)
+
+	if !strings.HasPrefix(strings.TrimSpace(v), "{") {
+		var err error
+		if data, err = os.ReadFile(v); err != nil {
+			return fmt.Errorf("failed reading machine config: %w", err)
+		}
+
+		if ext := filepath.Ext(v); ext == ".yaml" || ext == ".yml" {
+			if data, err = render.YAMLToJSON(data); err != nil {
+				return fmt.Errorf("failed parsing machine config %s: %w", v, err)
+			}
+		}
+	}
+
+	if err := json.Unmarshal(data, conf); err != nil {
+		return fmt.Errorf("failed parsing machine config: %w", err)
+	}
+
+	return nil
+}
+
 func parseKVFlag(ctx context.Context, flagName string, initialMap map[string]string) (parsed map[string]string, err error) {
 	parsed = initialMap
 
@@ -651,6 +714,12 @@ type determineMachineConfigInput struct {
 func determineMachineConfig(ctx context.Context, input *determineMachineConfigInput) (*api.MachineConfig, error) {
 	machineConf := mach.CloneConfig(&input.initialMachineConf)
 
+	if v := flag.GetString(ctx, "machine-config"); v != "" {
+		if err := readMachineConfig(v, machineConf); err != nil {
+			return nil, err
+		}
+	}
+
 	if guestSize := flag.GetString(ctx, "size"); guestSize != "" {
 		err := machineConf.Guest.SetSize(guestSize)
 		if err != nil {
@@ -703,7 +772,9 @@ func determineMachineConfig(ctx context.Context, input *determineMachineConfigIn
 		}
 	} else {
 		// Called from `run`. Command is specified by arguments.
-		machineConf.Init.Cmd = flag.Args(ctx)[1:]
+		if args := flag.Args(ctx)[1:]; len(args) > 0 {
+			machineConf.Init.Cmd = args
+		}
 	}
 
 	if machineConf.DNS == nil {
@@ -754,7 +825,7 @@ func determineMachineConfig(ctx context.Context, input *determineMachineConfigIn
 		if flag.IsSpecified(ctx, "restart") {
 			// An empty policy was explicitly requested.
 			machineConf.Restart.Policy = ""
-		} else if !input.updating {
+		} else if !input.updating && machineConf.Restart.Policy == "" {
 			// This is a new machine; apply the default.
 			machineConf.Restart.Policy = api.MachineRestartPolicyAlways
 		}
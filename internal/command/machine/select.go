@@ -7,12 +7,16 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/samber/lo"
 	"github.com/superfly/flyctl/api"
 	"github.com/superfly/flyctl/client"
 	"github.com/superfly/flyctl/flaps"
 	"github.com/superfly/flyctl/internal/appconfig"
 	"github.com/superfly/flyctl/internal/flag"
 	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+	"golang.org/x/exp/slices"
 )
 
 var selectFlag = flag.Bool{
@@ -20,6 +24,117 @@ var selectFlag = flag.Bool{
 	Description: "Select from a list of machines",
 }
 
+// selectorFlags pick the machines of the app to operate on by their
+// attributes, as an alternative to passing their IDs.
+var selectorFlags = []flag.Flag{
+	flag.StringSlice{
+		Name:        "select-region",
+		Description: "Operate on the machines in the given regions",
+	},
+	flag.StringSlice{
+		Name:        "select-group",
+		Description: "Operate on the machines of the given process groups",
+	},
+	flag.StringSlice{
+		Name:        "select-image-tag",
+		Description: "Operate on the machines running an image with the given tags",
+	},
+	flag.StringSlice{
+		Name:        "select-metadata",
+		Description: "Operate on the machines with the given metadata, as key=value pairs",
+	},
+}
+
+// machineSelectors are the criteria set with the selector flags. A machine
+// must match every criterion set, and any of the values given for each one.
+type machineSelectors struct {
+	regions   []string
+	groups    []string
+	imageTags []string
+	metadata  map[string]string
+}
+
+func selectorsFromContext(ctx context.Context) (*machineSelectors, error) {
+	sel := &machineSelectors{
+		regions:   flag.GetStringSlice(ctx, "select-region"),
+		groups:    flag.GetStringSlice(ctx, "select-group"),
+		imageTags: flag.GetStringSlice(ctx, "select-image-tag"),
+		metadata:  map[string]string{},
+	}
+
+	for _, pair := range flag.GetStringSlice(ctx, "select-metadata") {
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok || k == "" {
+			return nil, fmt.Errorf("invalid metadata selector '%s', expected key=value", pair)
+		}
+		sel.metadata[k] = v
+	}
+
+	if len(sel.regions) == 0 && len(sel.groups) == 0 && len(sel.imageTags) == 0 && len(sel.metadata) == 0 {
+		return nil, nil
+	}
+
+	return sel, nil
+}
+
+func (sel *machineSelectors) matches(m *api.Machine) bool {
+	if len(sel.regions) > 0 && !slices.Contains(sel.regions, m.Region) {
+		return false
+	}
+	if len(sel.groups) > 0 && !slices.Contains(sel.groups, m.ProcessGroup()) {
+		return false
+	}
+	if len(sel.imageTags) > 0 && !slices.Contains(sel.imageTags, m.ImageRef.Tag) {
+		return false
+	}
+	for k, v := range sel.metadata {
+		if m.Config == nil || m.Config.Metadata[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// selectMachinesBySelectors lists the machines of the app matching the
+// selectors and asks for confirmation before operating on them.
+func selectMachinesBySelectors(ctx context.Context, sel *machineSelectors) ([]*api.Machine, error) {
+	machines, err := flaps.FromContext(ctx).List(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("could not get a list of machines: %w", err)
+	}
+
+	machines = lo.Filter(machines, func(m *api.Machine, _ int) bool { return sel.matches(m) })
+	if len(machines) == 0 {
+		return nil, errors.New("no machines match the selectors")
+	}
+
+	sort.Slice(machines, func(i, j int) bool {
+		return machines[i].ID < machines[j].ID
+	})
+
+	if flag.GetYes(ctx) {
+		return machines, nil
+	}
+
+	io := iostreams.FromContext(ctx)
+	rows := lo.Map(machines, func(m *api.Machine, _ int) []string {
+		return []string{m.ID, m.Name, m.State, m.Region, m.ProcessGroup(), m.ImageRefWithVersion()}
+	})
+	_ = render.Table(io.Out, "Machines matching the selectors", rows, "ID", "Name", "State", "Region", "Process Group", "Image")
+
+	switch confirmed, err := prompt.Confirmf(ctx, "Continue with these %d machine(s)?", len(machines)); {
+	case err == nil:
+		if !confirmed {
+			return nil, errors.New("aborted")
+		}
+		return machines, nil
+	case prompt.IsNonInteractive(err):
+		return nil, prompt.NonInteractiveError("yes flag must be specified when not running interactively")
+	default:
+		return nil, err
+	}
+}
+
 func selectOneMachine(ctx context.Context, app *api.AppCompact, machineID string, haveMachineID bool) (*api.Machine, context.Context, error) {
 	if err := checkSelectCmdline(ctx, haveMachineID); err != nil {
 		return nil, nil, err
@@ -59,28 +174,37 @@ func selectManyMachines(ctx context.Context, machineIDs []string) ([]*api.Machin
 		return nil, nil, err
 	}
 
-	ctx, err := buildContextFromAppNameOrMachineID(ctx, machineIDs...)
+	sel, err := selectorsFromContext(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, err = buildContextFromAppNameOrMachineID(ctx, machineIDs...)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	var machines []*api.Machine
-	if flag.GetBool(ctx, "select") {
+	if sel != nil {
+		machines, err = selectMachinesBySelectors(ctx, sel)
+		if err != nil {
+			return nil, nil, err
+		}
+	} else if flag.GetBool(ctx, "select") {
 		machines, err = promptForManyMachines(ctx)
 		if err != nil {
 			return nil, nil, err
 		}
 	} else {
-		flapsClient := flaps.FromContext(ctx)
-		for _, machineID := range machineIDs {
-			machine, err := flapsClient.Get(ctx, machineID)
-			if err != nil {
-				if err := rewriteMachineNotFoundErrors(ctx, err, machineID); err != nil {
-					return nil, nil, err
-				}
-				return nil, nil, fmt.Errorf("could not get machine %s: %w", machineID, err)
+		machines, err = flaps.FromContext(ctx).GetMany(ctx, machineIDs)
+		var machineErr *flaps.MachineError
+		if errors.As(err, &machineErr) {
+			if err := rewriteMachineNotFoundErrors(ctx, err, machineErr.MachineID); err != nil {
+				return nil, nil, err
 			}
-			machines = append(machines, machine)
+			return nil, nil, fmt.Errorf("could not get machine %s: %w", machineErr.MachineID, err)
+		} else if err != nil {
+			return nil, nil, err
 		}
 	}
 	return machines, ctx, nil
@@ -92,12 +216,25 @@ func selectManyMachineIDs(ctx context.Context, machineIDs []string) ([]string, c
 		return nil, nil, err
 	}
 
-	ctx, err := buildContextFromAppNameOrMachineID(ctx, machineIDs...)
+	sel, err := selectorsFromContext(ctx)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	if flag.GetBool(ctx, "select") {
+	ctx, err = buildContextFromAppNameOrMachineID(ctx, machineIDs...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if sel != nil {
+		machines, err := selectMachinesBySelectors(ctx, sel)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, machine := range machines {
+			machineIDs = append(machineIDs, machine.ID)
+		}
+	} else if flag.GetBool(ctx, "select") {
 		// NOTE: machineIDs must be empty in this case.
 		machines, err := promptForManyMachines(ctx)
 		if err != nil {
@@ -213,12 +350,21 @@ func rewriteMachineNotFoundErrors(ctx context.Context, err error, machineID stri
 
 func checkSelectCmdline(ctx context.Context, haveMachineIDs bool) error {
 	haveSelectFlag := flag.GetBool(ctx, "select")
+	haveSelectors := lo.SomeBy([]string{"select-region", "select-group", "select-image-tag", "select-metadata"}, func(name string) bool {
+		return flag.IsSpecified(ctx, name)
+	})
 	appName := appconfig.NameFromContext(ctx)
 	switch {
+	case haveSelectors && (haveSelectFlag || haveMachineIDs):
+		return errors.New("machine IDs and --select can't be used with the selector flags")
+	case haveSelectors && appName == "":
+		return errors.New("an app name must be specified to use the selector flags")
+	case haveSelectors:
+		return nil
 	case haveSelectFlag && haveMachineIDs:
 		return errors.New("machine IDs can't be used with --select")
 	case !haveSelectFlag && !haveMachineIDs:
-		return errors.New("a machine ID must be provided unless --select is used")
+		return errors.New("a machine ID must be provided unless --select or a selector flag is used")
 	case haveSelectFlag && appName == "":
 		return errors.New("an app name must be specified to use --select")
 	default:
@@ -17,9 +17,14 @@ import (
 func newStop() *cobra.Command {
 	const (
 		short = "Stop one or more Fly machines"
-		long  = short + "\n"
+		long  = short + `
 
-		usage = "stop <id> [<id>...]"
+Machines are given by their IDs, picked interactively with --select, or
+matched by the --select-region, --select-group, --select-image-tag and
+--select-metadata flags, e.g. '--select-group worker --select-region fra'.
+`
+
+		usage = "stop [<id>...]"
 	)
 
 	cmd := command.New(usage, short, long, runMachineStop,
@@ -43,7 +48,9 @@ func newStop() *cobra.Command {
 			Name:        "timeout",
 			Description: "Seconds to wait before sending SIGKILL to the machine",
 		},
+		flag.Yes(),
 	)
+	flag.Add(cmd, selectorFlags...)
 
 	return cmd
 }
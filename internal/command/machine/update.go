@@ -3,6 +3,7 @@ package machine
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -11,8 +12,11 @@ import (
 
 	"github.com/superfly/flyctl/internal/appconfig"
 	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
 	"github.com/superfly/flyctl/internal/flag"
 	mach "github.com/superfly/flyctl/internal/machine"
+	"github.com/superfly/flyctl/internal/progress"
+	"github.com/superfly/flyctl/internal/render"
 	"github.com/superfly/flyctl/internal/watch"
 )
 
@@ -49,6 +53,23 @@ func newUpdate() *cobra.Command {
 			Name:        "mount-point",
 			Description: "New volume mount point",
 		},
+		flag.Bool{
+			Name:        "dry-run",
+			Description: "Print the machine config the update would apply, without acquiring a lease or changing the machine",
+		},
+		flag.Int{
+			Name:        "wait-timeout",
+			Description: "Seconds to wait for the machine to start. Defaults to deploy.wait_timeout in fly.toml, or 300.",
+		},
+		flag.Int{
+			Name:        "check-timeout",
+			Description: "Seconds to wait for health checks to pass. Defaults to deploy.check_timeout in fly.toml, or 300.",
+		},
+		flag.Int{
+			Name:        "check-grace-period",
+			Description: "Seconds to let the machine boot before requiring health checks to pass. Defaults to deploy.check_grace_period in fly.toml.",
+		},
+		flag.JSONOutput(),
 	)
 
 	cmd.Args = cobra.RangeArgs(0, 1)
@@ -67,6 +88,9 @@ func runUpdate(ctx context.Context) (err error) {
 		dockerfile       = flag.GetString(ctx, flag.Dockerfile().Name)
 	)
 
+	timeline := watch.NewTimeline()
+	ctx = watch.WithTimeline(ctx, timeline)
+
 	machineID := flag.FirstArg(ctx)
 	haveMachineID := len(flag.Args(ctx)) > 0
 	machine, ctx, err := selectOneMachine(ctx, nil, machineID, haveMachineID)
@@ -75,11 +99,16 @@ func runUpdate(ctx context.Context) (err error) {
 	}
 	appName := appconfig.NameFromContext(ctx)
 
+	dryRun := flag.GetBool(ctx, "dry-run")
+
 	// Acquire lease
-	machine, releaseLeaseFunc, err := mach.AcquireLease(ctx, machine)
-	defer releaseLeaseFunc(ctx, machine)
-	if err != nil {
-		return err
+	if !dryRun {
+		var releaseLeaseFunc func(context.Context, *api.Machine)
+		machine, releaseLeaseFunc, err = mach.AcquireLease(ctx, machine)
+		defer releaseLeaseFunc(ctx, machine)
+		if err != nil {
+			return err
+		}
 	}
 
 	var imageOrPath string
@@ -97,6 +126,7 @@ func runUpdate(ctx context.Context) (err error) {
 	}
 
 	// Identify configuration changes
+	endConfig := timeline.Begin("Resolve image and config")
 	machineConf, err := determineMachineConfig(ctx, &determineMachineConfigInput{
 		initialMachineConf: *machine.Config,
 		appName:            appName,
@@ -107,6 +137,7 @@ func runUpdate(ctx context.Context) (err error) {
 	if err != nil {
 		return err
 	}
+	endConfig()
 
 	if mp := flag.GetString(ctx, "mount-point"); mp != "" {
 		if len(machineConf.Mounts) != 1 {
@@ -115,6 +146,24 @@ func runUpdate(ctx context.Context) (err error) {
 		machineConf.Mounts[0].Path = mp
 	}
 
+	input := &api.LaunchMachineInput{
+		ID:               machine.ID,
+		AppID:            appName,
+		Name:             machine.Name,
+		Region:           machine.Region,
+		Config:           machineConf,
+		SkipHealthChecks: skipHealthChecks,
+		SkipLaunch:       len(machineConf.Standbys) > 0,
+	}
+
+	if dryRun {
+		return render.Output(ctx, io.Out, input)
+	}
+
+	// stdout holds the progress events when they're streamed
+	ctx = progress.SeparateOutput(ctx)
+	io = iostreams.FromContext(ctx)
+
 	// Prompt user to confirm changes
 	if !autoConfirm {
 		confirmed, err := mach.ConfirmConfigChanges(ctx, machine, *machineConf, "")
@@ -128,29 +177,70 @@ func runUpdate(ctx context.Context) (err error) {
 	}
 
 	// Perform update
-	input := &api.LaunchMachineInput{
-		ID:               machine.ID,
-		AppID:            appName,
-		Name:             machine.Name,
-		Region:           machine.Region,
-		Config:           machineConf,
-		SkipHealthChecks: skipHealthChecks,
-		SkipLaunch:       len(machineConf.Standbys) > 0,
-	}
-	if err := mach.Update(ctx, machine, input); err != nil {
+	opts := updateOptions(ctx)
+	endUpdate := timeline.Begin("Update machine " + machine.ID)
+	event := progress.Event{Stage: progress.StageUpdateMachine, MachineID: machine.ID}
+	progress.Publish(ctx, event.WithStatus(progress.StatusStarted))
+	if err := mach.UpdateWithOptions(ctx, machine, input, opts); err != nil {
+		progress.Fail(ctx, event, err)
 		return err
 	}
+	endUpdate()
 
 	if !(input.SkipLaunch || flag.GetDetach(ctx)) {
 		fmt.Fprintln(io.Out, colorize.Green("==> "+"Monitoring health checks"))
 
-		if err := watch.MachinesChecks(ctx, []*api.Machine{machine}); err != nil {
+		// the grace period is already over when the update waited for checks
+		gracePeriod := opts.CheckGracePeriod
+		if !skipHealthChecks {
+			gracePeriod = 0
+		}
+
+		endChecks := timeline.Begin("Health checks " + machine.ID)
+		endProgress := progress.Begin(ctx, progress.Event{Stage: progress.StageHealthChecks, MachineID: machine.ID})
+		err := watch.MachinesChecksWithTimeout(ctx, []*api.Machine{machine}, opts.CheckTimeout, gracePeriod)
+		endProgress(err)
+		if err != nil {
+			progress.Fail(ctx, event, err)
 			return err
 		}
+		endChecks()
 		fmt.Fprintln(io.Out)
 	}
 
-	fmt.Fprintf(io.Out, "\nMonitor machine status here:\nhttps://fly.io/apps/%s/machines/%s\n", appName, machine.ID)
+	fmt.Fprintf(io.Out, "\nMonitor machine status here:\nhttps://fly.io/apps/%s/machines/%s\n\n", appName, machine.ID)
+
+	if bus := progress.FromContext(ctx); bus.Active() {
+		// the timeline completes the stream of events rather than following it
+		event.Data = timeline.Summary()
+		bus.Publish(event.WithStatus(progress.StatusCompleted))
+		return nil
+	}
+
+	return timeline.Render(ctx, io.Out, config.FromContext(ctx).JSONOutput)
+}
+
+// updateOptions resolves how long to wait for the updated machine from flags,
+// falling back to the deploy section of the app config.
+func updateOptions(ctx context.Context) mach.UpdateOptions {
+	var deploy appconfig.Deploy
+	if cfg := appconfig.ConfigFromContext(ctx); cfg != nil && cfg.Deploy != nil && cfg.AppName == appconfig.NameFromContext(ctx) {
+		deploy = *cfg.Deploy
+	}
+
+	resolve := func(name string, fromConfig *api.Duration) time.Duration {
+		if flag.IsSpecified(ctx, name) {
+			return time.Duration(flag.GetInt(ctx, name)) * time.Second
+		}
+		if fromConfig != nil {
+			return fromConfig.Duration
+		}
+		return 0
+	}
 
-	return nil
+	return mach.UpdateOptions{
+		WaitTimeout:      resolve("wait-timeout", deploy.WaitTimeout),
+		CheckTimeout:     resolve("check-timeout", deploy.CheckTimeout),
+		CheckGracePeriod: resolve("check-grace-period", deploy.CheckGracePeriod),
+	}
 }
@@ -0,0 +1,138 @@
+// Package metrics implements the metrics command chain.
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+)
+
+func New() *cobra.Command {
+	const (
+		long = `Query the metrics Fly.io collects for the apps of an organization, with
+PromQL, without setting up Grafana.`
+
+		short = "Query the metrics of apps"
+	)
+
+	cmd := command.New("metrics", short, long, nil)
+
+	cmd.AddCommand(
+		newQuery(),
+	)
+
+	return cmd
+}
+
+// orgAndApp returns the organization whose metrics are queried, along with
+// the app the queries are scoped to, if any.
+func orgAndApp(ctx context.Context) (orgSlug, appName string, err error) {
+	appName = appconfig.NameFromContext(ctx)
+
+	if orgSlug = flag.GetOrg(ctx); orgSlug != "" {
+		return orgSlug, appName, nil
+	}
+
+	if appName == "" {
+		return "", "", fmt.Errorf("an app or organization is required, pass one with --app or --org")
+	}
+
+	app, err := client.FromContext(ctx).API().GetAppBasic(ctx, appName)
+	if err != nil {
+		return "", "", fmt.Errorf("failed retrieving app %s: %w", appName, err)
+	}
+
+	return app.Organization.Slug, appName, nil
+}
+
+// selectorRx matches the Fly.io metric names of a query, along with their
+// label matchers.
+var selectorRx = regexp.MustCompile(`\bfly_[a-zA-Z0-9_:]+(\{[^}]*\})?`)
+
+// appMatcherRx matches label matchers on the app.
+var appMatcherRx = regexp.MustCompile(`(^|,)\s*app\s*(=|!=|=~|!~)`)
+
+// scopeToApp adds an app label matcher to the Fly.io metrics of query which
+// don't already match on the app.
+func scopeToApp(query, appName string) string {
+	matcher := fmt.Sprintf("app=%q", appName)
+
+	return selectorRx.ReplaceAllStringFunc(query, func(selector string) string {
+		name, matchers, ok := strings.Cut(selector, "{")
+		if !ok {
+			return name + "{" + matcher + "}"
+		}
+
+		matchers = strings.TrimSuffix(matchers, "}")
+		if appMatcherRx.MatchString(matchers) {
+			return selector
+		}
+		if strings.TrimSpace(matchers) == "" {
+			return name + "{" + matcher + "}"
+		}
+
+		return name + "{" + matcher + "," + matchers + "}"
+	})
+}
+
+// formatLabels formats labels like Prometheus does, the metric name first.
+func formatLabels(labels map[string]string) string {
+	name := labels["__name__"]
+
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		if k != "__name__" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s=%q", k, labels[k]))
+	}
+
+	if len(pairs) == 0 && name != "" {
+		return name
+	}
+
+	return name + "{" + strings.Join(pairs, ", ") + "}"
+}
+
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline draws values with block characters, scaled between their minimum
+// and maximum. Missing values are drawn as spaces.
+func sparkline(values []float64) string {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			continue
+		}
+		lo, hi = math.Min(lo, v), math.Max(hi, v)
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		switch {
+		case math.IsNaN(v) || math.IsInf(v, 0):
+			b.WriteRune(' ')
+		case hi == lo:
+			b.WriteRune(sparks[len(sparks)/2])
+		default:
+			b.WriteRune(sparks[int((v-lo)/(hi-lo)*float64(len(sparks)-1))])
+		}
+	}
+
+	return b.String()
+}
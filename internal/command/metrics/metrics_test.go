@@ -0,0 +1,41 @@
+package metrics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScopeToApp(t *testing.T) {
+	cases := map[string]string{
+		`sum(rate(fly_app_http_responses_total[5m])) by (status)`: `sum(rate(fly_app_http_responses_total{app="my-app"}[5m])) by (status)`,
+		`fly_instance_memory_mem_total{region="fra"}`:             `fly_instance_memory_mem_total{app="my-app",region="fra"}`,
+		`fly_instance_up{}`:                         `fly_instance_up{app="my-app"}`,
+		`fly_instance_up{app="other"}`:              `fly_instance_up{app="other"}`,
+		`fly_instance_up{region="fra", app=~"a.*"}`: `fly_instance_up{region="fra", app=~"a.*"}`,
+		`up{instance="x"}`:                          `up{instance="x"}`,
+	}
+
+	for query, want := range cases {
+		assert.Equal(t, want, scopeToApp(query, "my-app"), query)
+	}
+}
+
+func TestFormatLabels(t *testing.T) {
+	assert.Equal(t, `fly_instance_up{app="a", region="fra"}`, formatLabels(map[string]string{
+		"__name__": "fly_instance_up",
+		"region":   "fra",
+		"app":      "a",
+	}))
+	assert.Equal(t, "fly_instance_up", formatLabels(map[string]string{"__name__": "fly_instance_up"}))
+	assert.Equal(t, `{status="200"}`, formatLabels(map[string]string{"status": "200"}))
+	assert.Equal(t, "{}", formatLabels(map[string]string{}))
+}
+
+func TestSparkline(t *testing.T) {
+	assert.Equal(t, "▁▂▃▄▅▆▇█", sparkline([]float64{0, 1, 2, 3, 4, 5, 6, 7}))
+	assert.Equal(t, "▅▅", sparkline([]float64{3, 3}))
+	assert.Equal(t, "▁ █", sparkline([]float64{1, math.NaN(), 2}))
+	assert.Equal(t, "", sparkline(nil))
+}
@@ -0,0 +1,152 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/prometheus"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+// rangePoints is how many points range queries return by default.
+const rangePoints = 60
+
+func newQuery() *cobra.Command {
+	const (
+		long = `Run a PromQL query against the metrics of the organization. The Fly.io
+metrics of the query (those named fly_*) are scoped to the app, unless they
+already match on the app label or only an organization is given.
+
+Without --range, the query is evaluated now and its values printed. With
+--range, it's evaluated over that duration, and each series is drawn as a
+sparkline along with its minimum, maximum and last values.
+
+For example:
+
+  fly metrics query 'sum(rate(fly_app_http_responses_total[5m])) by (status)' --app myapp --range 1h`
+
+		short = "Run a PromQL query against the metrics of apps"
+	)
+
+	cmd := command.New("query <promql>", short, long, runQuery,
+		command.RequireSession,
+		command.LoadAppNameIfPresent,
+	)
+
+	cmd.Args = cobra.ExactArgs(1)
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.Org(),
+		flag.JSONOutput(),
+		flag.Duration{
+			Name:        "range",
+			Description: "Evaluate the query over this duration, e.g. 1h, rather than now",
+		},
+		flag.Duration{
+			Name:        "step",
+			Description: "The resolution of --range queries, defaults to a 60th of the range",
+		},
+	)
+
+	return cmd
+}
+
+func runQuery(ctx context.Context) error {
+	io := iostreams.FromContext(ctx)
+
+	orgSlug, appName, err := orgAndApp(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := flag.FirstArg(ctx)
+	if appName != "" {
+		query = scopeToApp(query, appName)
+	}
+
+	var (
+		metrics  = prometheus.New(ctx, orgSlug)
+		now      = time.Now()
+		duration = flag.GetDuration(ctx, "range")
+		series   []prometheus.Series
+	)
+
+	if duration > 0 {
+		step := flag.GetDuration(ctx, "step")
+		if step <= 0 {
+			step = (duration / rangePoints).Round(time.Second)
+			if step < time.Second {
+				step = time.Second
+			}
+		}
+
+		series, err = metrics.QueryRange(ctx, query, now.Add(-duration), now, step)
+	} else {
+		series, err = metrics.Query(ctx, query, now)
+	}
+	if err != nil {
+		return fmt.Errorf("failed querying metrics: %w", err)
+	}
+
+	sort.Slice(series, func(i, j int) bool {
+		return formatLabels(series[i].Metric) < formatLabels(series[j].Metric)
+	})
+
+	if config.FromContext(ctx).JSONOutput {
+		return render.Output(ctx, io.Out, series)
+	}
+
+	if len(series) == 0 {
+		fmt.Fprintln(io.ErrOut, "No series match the query")
+		return nil
+	}
+
+	rows := make([][]string, 0, len(series))
+
+	if duration == 0 {
+		for _, s := range series {
+			rows = append(rows, []string{formatLabels(s.Metric), formatValue(s.Last())})
+		}
+
+		return render.Table(io.Out, "", rows, "Series", "Value")
+	}
+
+	for _, s := range series {
+		values := s.Values()
+		min, max := math.NaN(), math.NaN()
+		for _, v := range values {
+			if math.IsNaN(min) || v < min {
+				min = v
+			}
+			if math.IsNaN(max) || v > max {
+				max = v
+			}
+		}
+
+		rows = append(rows, []string{
+			formatLabels(s.Metric),
+			sparkline(values),
+			formatValue(min),
+			formatValue(max),
+			formatValue(s.Last()),
+		})
+	}
+
+	return render.Table(io.Out, "", rows, "Series", "Last "+duration.String(), "Min", "Max", "Last")
+}
+
+func formatValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', 6, 64)
+}
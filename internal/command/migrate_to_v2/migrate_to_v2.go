@@ -202,8 +202,8 @@ func NewV2PlatformMigrator(ctx context.Context, appName string) (V2PlatformMigra
 	if err != nil {
 		return nil, err
 	}
-	leaseTimeout := 13 * time.Second
-	leaseDelayBetween := (leaseTimeout - 1*time.Second) / 3
+	leaseTimeout := machine.DefaultLeaseTtl
+	leaseDelayBetween := machine.LeaseDelayBetween(leaseTimeout)
 	migrator := &v2PlatformMigrator{
 		apiClient:               apiClient,
 		flapsClient:             flapsClient,
@@ -26,6 +26,14 @@ organization the current user belongs to.
 	flag.Add(move,
 		flag.Yes(),
 		flag.Org(),
+		flag.Bool{
+			Name:        "move-postgres",
+			Description: "Move the Postgres clusters the app is attached to along with it, when no other app uses them",
+		},
+		flag.Bool{
+			Name:        "dry-run",
+			Description: "Only check what stands in the way of moving the app",
+		},
 	)
 
 	return move
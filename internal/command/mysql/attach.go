@@ -0,0 +1,165 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/helpers"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newAttach() *cobra.Command {
+	const (
+		short = "Attach a MySQL cluster to an app"
+		long  = short + `
+
+Creates a database and a user owning it on the primary of the cluster, and
+adds their connection string to the secrets of the app.
+`
+		usage = "attach <MYSQL APP>"
+	)
+
+	cmd := command.New(usage, short, long, runAttach,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+	cmd.Args = cobra.ExactArgs(1)
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.String{
+			Name:        "database-name",
+			Description: "The designated database name for this consuming app.",
+		},
+		flag.String{
+			Name:        "database-user",
+			Description: "The database user to create. By default, we will use the name of the consuming app.",
+		},
+		flag.String{
+			Name:        "variable-name",
+			Default:     "DATABASE_URL",
+			Description: "The environment variable name that will be added to the consuming app. ",
+		},
+	)
+
+	return cmd
+}
+
+func runAttach(ctx context.Context) error {
+	var (
+		io           = iostreams.FromContext(ctx)
+		client       = client.FromContext(ctx).API()
+		mysqlAppName = flag.FirstArg(ctx)
+		appName      = appconfig.NameFromContext(ctx)
+		dbName       = flag.GetString(ctx, "database-name")
+		dbUser       = flag.GetString(ctx, "database-user")
+		varName      = flag.GetString(ctx, "variable-name")
+	)
+
+	if dbName == "" {
+		dbName = appName
+	}
+	dbName = normalizeName(dbName)
+	if dbUser == "" {
+		dbUser = appName
+	}
+	dbUser = normalizeName(dbUser)
+
+	if _, err := client.GetAppCompact(ctx, appName); err != nil {
+		return fmt.Errorf("failed retrieving app %s: %w", appName, err)
+	}
+
+	ctx, mysqlApp, machines, err := clusterContext(ctx, mysqlAppName)
+	if err != nil {
+		return err
+	}
+	primary, err := pickPrimary(machines)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(io.Out, "Checking for existing attachments")
+
+	secrets, err := client.GetAppSecrets(ctx, appName)
+	if err != nil {
+		return err
+	}
+	for _, secret := range secrets {
+		if secret.Name == varName {
+			return fmt.Errorf("consumer app %q already contains a secret named %s", appName, varName)
+		}
+	}
+
+	exists, err := userExists(ctx, mysqlApp, primary, dbUser)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("database user %q already exists. Please specify a new database user via --database-user", dbUser)
+	}
+
+	pwd, err := helpers.RandString(24)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(io.Out, "Creating database and user")
+	if _, err := runSQL(ctx, mysqlApp, primary, createUserSQL(dbUser, pwd, dbName)); err != nil {
+		return err
+	}
+
+	connectionString := connectionURL(dbUser, pwd, primaryHost(mysqlApp.Name, primary), dbName)
+	if _, err := client.SetSecrets(ctx, appName, map[string]string{varName: connectionString}); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(io.Out, "\nMySQL cluster %s is now attached to %s\n", mysqlApp.Name, appName)
+	fmt.Fprintf(io.Out, "The following secret was added to %s:\n  %s=%s\n", appName, varName, connectionString)
+
+	return nil
+}
+
+func userExists(ctx context.Context, app *api.AppCompact, primary *api.Machine, user string) (bool, error) {
+	out, err := runSQL(ctx, app, primary, fmt.Sprintf("SELECT COUNT(*) FROM mysql.user WHERE User = %s;\n", quoteString(user)))
+	if err != nil {
+		return false, err
+	}
+	return strings.TrimSpace(out) != "0", nil
+}
+
+// createUserSQL creates user with every privilege on database, creating the
+// database when it doesn't exist. An empty database grants no privileges.
+func createUserSQL(user, password, database string) string {
+	var b strings.Builder
+	if database != "" {
+		fmt.Fprintf(&b, "CREATE DATABASE IF NOT EXISTS %s;\n", quoteIdentifier(database))
+	}
+	fmt.Fprintf(&b, "CREATE USER %s@'%%' IDENTIFIED BY %s;\n", quoteString(user), quoteString(password))
+	if database != "" {
+		fmt.Fprintf(&b, "GRANT ALL PRIVILEGES ON %s.* TO %s@'%%';\n", quoteIdentifier(database), quoteString(user))
+	}
+	return b.String()
+}
+
+// connectionURL is the URL apps connect to database with.
+func connectionURL(user, password, host, database string) string {
+	u := url.URL{
+		Scheme: "mysql",
+		User:   url.UserPassword(user, password),
+		Host:   net.JoinHostPort(host, strconv.Itoa(port)),
+		Path:   "/" + database,
+	}
+	return u.String()
+}
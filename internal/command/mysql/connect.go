@@ -0,0 +1,119 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+
+	"github.com/docker/docker/pkg/ioutils"
+	"github.com/mattn/go-colorable"
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/agent"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/command/ssh"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/iostreams"
+	"github.com/superfly/flyctl/proxy"
+)
+
+func newConnect() *cobra.Command {
+	const (
+		short = "Connect to the MySQL console"
+		long  = short + `
+
+Runs the MySQL client as root on the primary of the cluster. With --proxy,
+opens a local tunnel to the primary instead, for clients running on this
+machine.
+`
+		usage = "connect"
+	)
+
+	cmd := command.New(usage, short, long, runConnect,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.String{
+			Name:        "database",
+			Shorthand:   "d",
+			Description: "The database to connect to",
+		},
+		flag.Bool{
+			Name:        "proxy",
+			Description: "Open a local tunnel to the primary instead of connecting from inside the machine",
+		},
+		flag.String{
+			Name:        "local-port",
+			Description: "The local port of the tunnel",
+			Default:     "13306",
+		},
+	)
+
+	return cmd
+}
+
+func runConnect(ctx context.Context) error {
+	appName := appconfig.NameFromContext(ctx)
+
+	ctx, app, machines, err := clusterContext(ctx, appName)
+	if err != nil {
+		return err
+	}
+	primary, err := pickPrimary(machines)
+	if err != nil {
+		return err
+	}
+
+	if flag.GetBool(ctx, "proxy") {
+		return runProxyConnect(ctx, app, primary)
+	}
+
+	cmd := "mysql -uroot"
+	if database := flag.GetString(ctx, "database"); database != "" {
+		cmd += " " + database
+	}
+
+	return ssh.SSHConnect(&ssh.SSHParams{
+		Ctx:      ctx,
+		Org:      app.Organization,
+		Dialer:   agent.DialerFromContext(ctx),
+		App:      app.Name,
+		Username: ssh.DefaultSshUsername,
+		Cmd:      cmd,
+		Stdin:    os.Stdin,
+		Stdout:   ioutils.NewWriteCloserWrapper(colorable.NewColorableStdout(), func() error { return nil }),
+		Stderr:   ioutils.NewWriteCloserWrapper(colorable.NewColorableStderr(), func() error { return nil }),
+	}, primary.PrivateIP)
+}
+
+// runProxyConnect opens a local tunnel to the primary until interrupted.
+func runProxyConnect(ctx context.Context, app *api.AppCompact, primary *api.Machine) error {
+	io := iostreams.FromContext(ctx)
+
+	server, err := proxy.NewServer(ctx, &proxy.ConnectParams{
+		AppName:          app.Name,
+		OrganizationSlug: app.Organization.Slug,
+		Dialer:           agent.DialerFromContext(ctx),
+		Ports:            []string{flag.GetString(ctx, "local-port"), fmt.Sprint(port)},
+		RemoteHost:       primary.PrivateIP,
+	})
+	if err != nil {
+		return err
+	}
+
+	localPort := server.Listener.Addr().(*net.TCPAddr).Port
+
+	fmt.Fprintf(io.Out, "Connect with: mysql --host 127.0.0.1 --port %d --user root --password %s\n", localPort, flag.GetString(ctx, "database"))
+	fmt.Fprintln(io.Out, "Press Ctrl-C to close the tunnel")
+
+	return server.ProxyServer(ctx)
+}
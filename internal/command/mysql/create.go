@@ -0,0 +1,344 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/helpers"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	mach "github.com/superfly/flyctl/internal/machine"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/internal/watch"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newCreate() *cobra.Command {
+	const (
+		short = "Create a new MySQL cluster"
+		long  = short + `
+
+Creates an app running a MySQL or MariaDB primary, with a volume of its own,
+and replicas streaming from it when the cluster has more than one machine.
+An optional user owning a database of the same name is created with
+--database-user.
+`
+	)
+
+	cmd := command.New("create", short, long, runCreate,
+		command.RequireSession,
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd,
+		flag.Region(),
+		flag.Org(),
+		flag.Detach(),
+		flag.String{
+			Name:        "name",
+			Shorthand:   "n",
+			Description: "The name of your MySQL app",
+		},
+		flag.String{
+			Name:        "engine",
+			Description: "The engine of the cluster, mysql or mariadb",
+			Default:     "mysql",
+		},
+		flag.String{
+			Name:        "image-ref",
+			Description: "Specify a non-default image for the engine",
+		},
+		flag.String{
+			Name:        "password",
+			Shorthand:   "p",
+			Description: "The root password. The password will be generated for you if you leave this blank",
+		},
+		flag.String{
+			Name:        "vm-size",
+			Description: "The size of the VM",
+			Default:     "shared-cpu-1x",
+		},
+		flag.Int{
+			Name:        "initial-cluster-size",
+			Description: "Initial cluster size, the primary and its replicas",
+			Default:     1,
+		},
+		flag.Int{
+			Name:        "volume-size",
+			Description: "The volume size in GB",
+			Default:     10,
+		},
+		flag.String{
+			Name:        "database-user",
+			Description: "A user to create along with a database of the same name",
+		},
+	)
+
+	return cmd
+}
+
+// clusterInput describes the machines of a cluster.
+type clusterInput struct {
+	Engine string
+	Image  string
+	Guest  *api.MachineGuest
+	Region string
+	// DatabaseUser, when set, is created with a database of the same name.
+	DatabaseUser string
+}
+
+func runCreate(ctx context.Context) (err error) {
+	var (
+		io         = iostreams.FromContext(ctx)
+		colorize   = io.ColorScheme()
+		apiClient  = client.FromContext(ctx).API()
+		appName    = flag.GetString(ctx, "name")
+		engineName = flag.GetString(ctx, "engine")
+		size       = flag.GetInt(ctx, "initial-cluster-size")
+		volumeSize = flag.GetInt(ctx, "volume-size")
+		dbUser     = normalizeName(flag.GetString(ctx, "database-user"))
+	)
+
+	e, ok := engines[engineName]
+	if !ok {
+		return fmt.Errorf("unknown engine %s, must be mysql or mariadb", engineName)
+	}
+	if size < 1 {
+		return fmt.Errorf("the initial cluster size must be at least 1")
+	}
+	if volumeSize < 1 {
+		return fmt.Errorf("the volume size must be at least 1GB")
+	}
+
+	guest := &api.MachineGuest{}
+	if err := guest.SetSize(flag.GetString(ctx, "vm-size")); err != nil {
+		return err
+	}
+
+	input := clusterInput{
+		Engine:       engineName,
+		Image:        flag.GetString(ctx, "image-ref"),
+		Guest:        guest,
+		DatabaseUser: dbUser,
+	}
+	if input.Image == "" {
+		input.Image = e.Image
+	}
+
+	if appName == "" {
+		if appName, err = prompt.SelectAppName(ctx); err != nil {
+			return
+		}
+	}
+
+	org, err := prompt.Org(ctx)
+	if err != nil {
+		return err
+	}
+
+	region, err := prompt.Region(ctx, !org.PaidPlan, prompt.RegionParams{
+		Message: "Select region:",
+	})
+	if err != nil {
+		return err
+	}
+	input.Region = region.Code
+
+	fmt.Fprintln(io.Out, "Creating app...")
+	app, err := apiClient.CreateApp(ctx, api.CreateAppInput{
+		OrganizationID:  org.ID,
+		Name:            appName,
+		PreferredRegion: &region.Code,
+	})
+	if err != nil {
+		return err
+	}
+
+	secrets, err := clusterSecrets(e, flag.GetString(ctx, "password"), dbUser)
+	if err != nil {
+		return err
+	}
+	if _, err := apiClient.SetSecrets(ctx, app.Name, secrets); err != nil {
+		return err
+	}
+
+	flapsClient, err := flaps.New(ctx, &api.AppCompact{
+		ID:   app.ID,
+		Name: app.Name,
+		Organization: &api.OrganizationBasic{
+			ID:   app.Organization.ID,
+			Slug: app.Organization.Slug,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	ctx = flaps.NewContext(ctx, flapsClient)
+
+	var (
+		machines []*api.Machine
+		primary  *api.Machine
+	)
+	for i := 0; i < size; i++ {
+		fmt.Fprintf(io.Out, "Provisioning %d of %d machines with image %s\n", i+1, size, input.Image)
+
+		vol, err := apiClient.CreateVolume(ctx, api.CreateVolumeInput{
+			AppID:             app.ID,
+			Name:              volumeName,
+			Region:            region.Code,
+			SizeGb:            volumeSize,
+			Encrypted:         true,
+			RequireUniqueZone: true,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create volume: %w", err)
+		}
+
+		var config *api.MachineConfig
+		if primary == nil {
+			config = machineConfig(input, vol.ID, "")
+		} else {
+			config = machineConfig(input, vol.ID, primaryHost(app.Name, primary))
+		}
+
+		machine, err := flapsClient.Launch(ctx, api.LaunchMachineInput{
+			AppID:   app.ID,
+			OrgSlug: org.ID,
+			Region:  region.Code,
+			Config:  config,
+		})
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(io.Out, "Waiting for machine %s to start...\n", machine.ID)
+		if err := mach.WaitForStartOrStop(ctx, machine, "start", 5*time.Minute); err != nil {
+			return err
+		}
+
+		// replicas copy the primary once it accepts connections
+		if primary == nil {
+			primary = machine
+			if err := watch.MachinesChecks(ctx, []*api.Machine{primary}); err != nil {
+				return fmt.Errorf("failed to wait for health checks to pass: %w", err)
+			}
+		}
+		machines = append(machines, machine)
+	}
+
+	if !flag.GetDetach(ctx) && len(machines) > 1 {
+		fmt.Fprintln(io.Out, colorize.Green("==> "+"Monitoring health checks"))
+		if err := watch.MachinesChecks(ctx, machines[1:]); err != nil {
+			return err
+		}
+		fmt.Fprintln(io.Out)
+	}
+
+	host := primaryHost(app.Name, primary)
+
+	fmt.Fprintf(io.Out, "MySQL cluster %s created\n", app.Name)
+	fmt.Fprintf(io.Out, "  Username:    root\n")
+	fmt.Fprintf(io.Out, "  Password:    %s\n", secrets[e.EnvPrefix+"_ROOT_PASSWORD"])
+	fmt.Fprintf(io.Out, "  Hostname:    %s\n", host)
+	fmt.Fprintf(io.Out, "  Port:        %d\n", port)
+	if dbUser != "" {
+		fmt.Fprintf(io.Out, "  Connection string: %s\n", connectionURL(dbUser, secrets[e.EnvPrefix+"_PASSWORD"], host, dbUser))
+	}
+	fmt.Fprintln(io.Out, colorize.Italic("Save your credentials in a secure place -- you won't be able to see them again!"))
+
+	fmt.Fprintln(io.Out)
+	fmt.Fprintln(io.Out, colorize.Bold("Connect to MySQL"))
+	fmt.Fprintf(io.Out, "Any app within the %s organization can connect to the primary of this cluster at %s:%d\n", org.Name, host, port)
+	fmt.Fprintf(io.Out, "Run %s to create a database and a user for an app\n", colorize.Green("fly mysql attach "+app.Name))
+
+	return nil
+}
+
+// clusterSecrets generates the passwords of the cluster. Clients in the
+// machines authenticate as root with MYSQL_PWD.
+func clusterSecrets(e engine, rootPassword, dbUser string) (map[string]string, error) {
+	var err error
+	if rootPassword == "" {
+		if rootPassword, err = helpers.RandString(24); err != nil {
+			return nil, err
+		}
+	}
+	replicationPassword, err := helpers.RandString(24)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := map[string]string{
+		e.EnvPrefix + "_ROOT_PASSWORD":        rootPassword,
+		e.EnvPrefix + "_MASTER_ROOT_PASSWORD": rootPassword,
+		e.EnvPrefix + "_REPLICATION_PASSWORD": replicationPassword,
+		"MYSQL_PWD":                           rootPassword,
+	}
+
+	if dbUser != "" {
+		password, err := helpers.RandString(24)
+		if err != nil {
+			return nil, err
+		}
+		secrets[e.EnvPrefix+"_PASSWORD"] = password
+	}
+
+	return secrets, nil
+}
+
+// machineConfig builds the config of a machine of the cluster. The machine is
+// the primary when primaryHost is empty, a replica of primaryHost otherwise.
+func machineConfig(input clusterInput, volumeID, primaryHost string) *api.MachineConfig {
+	e := engines[input.Engine]
+
+	role := rolePrimary
+	env := map[string]string{
+		"PRIMARY_REGION":                  input.Region,
+		e.EnvPrefix + "_REPLICATION_USER": "replicator",
+		e.EnvPrefix + "_EXTRA_FLAGS":      "--bind-address=::",
+		e.EnvPrefix + "_REPLICATION_MODE": "master",
+	}
+	if input.DatabaseUser != "" {
+		env[e.EnvPrefix+"_USER"] = input.DatabaseUser
+		env[e.EnvPrefix+"_DATABASE"] = input.DatabaseUser
+	}
+	if primaryHost != "" {
+		role = roleReplica
+		env[e.EnvPrefix+"_REPLICATION_MODE"] = "slave"
+		env[e.EnvPrefix+"_MASTER_HOST"] = primaryHost
+		env[e.EnvPrefix+"_MASTER_PORT_NUMBER"] = fmt.Sprint(port)
+	}
+
+	config := &api.MachineConfig{
+		Image: input.Image,
+		Env:   env,
+		Guest: input.Guest,
+		Mounts: []api.MachineMount{{
+			Volume: volumeID,
+			Path:   e.DataPath,
+		}},
+		Checks: map[string]api.MachineCheck{
+			"mysql": {
+				Port:     api.Pointer(port),
+				Type:     api.Pointer("tcp"),
+				Interval: &api.Duration{Duration: 15 * time.Second},
+				Timeout:  &api.Duration{Duration: 10 * time.Second},
+			},
+		},
+		Metadata: map[string]string{
+			api.MachineConfigMetadataKeyFlyPlatformVersion: api.MachineFlyPlatformVersion2,
+			engineMetadataKey: input.Engine,
+			roleMetadataKey:   role,
+		},
+	}
+	config.Restart.Policy = api.MachineRestartPolicyAlways
+
+	return config
+}
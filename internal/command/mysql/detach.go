@@ -0,0 +1,114 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newDetach() *cobra.Command {
+	const (
+		short = "Detach a MySQL cluster from an app"
+		long  = short + `
+
+Drops the user of the app and removes its connection string from the secrets
+of the app. The database remains intact.
+`
+		usage = "detach <MYSQL APP>"
+	)
+
+	cmd := command.New(usage, short, long, runDetach,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	cmd.Args = cobra.ExactArgs(1)
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.String{
+			Name:        "database-user",
+			Description: "The database user to drop. By default, the name of the consuming app.",
+		},
+		flag.String{
+			Name:        "variable-name",
+			Default:     "DATABASE_URL",
+			Description: "The environment variable name to remove from the consuming app.",
+		},
+		flag.Yes(),
+	)
+
+	return cmd
+}
+
+func runDetach(ctx context.Context) error {
+	var (
+		io           = iostreams.FromContext(ctx)
+		client       = client.FromContext(ctx).API()
+		mysqlAppName = flag.FirstArg(ctx)
+		appName      = appconfig.NameFromContext(ctx)
+		dbUser       = flag.GetString(ctx, "database-user")
+		varName      = flag.GetString(ctx, "variable-name")
+	)
+
+	if dbUser == "" {
+		dbUser = appName
+	}
+	dbUser = normalizeName(dbUser)
+
+	if _, err := client.GetAppCompact(ctx, appName); err != nil {
+		return fmt.Errorf("get app: %w", err)
+	}
+
+	ctx, mysqlApp, machines, err := clusterContext(ctx, mysqlAppName)
+	if err != nil {
+		return err
+	}
+	primary, err := pickPrimary(machines)
+	if err != nil {
+		return err
+	}
+
+	if !flag.GetYes(ctx) {
+		switch confirmed, err := prompt.Confirmf(ctx, "Drop user %s from %s and remove %s from %s?", dbUser, mysqlApp.Name, varName, appName); {
+		case err == nil:
+			if !confirmed {
+				return nil
+			}
+		case prompt.IsNonInteractive(err):
+			return prompt.NonInteractiveError("yes flag must be specified when not running interactively")
+		default:
+			return err
+		}
+	}
+
+	if _, err := runSQL(ctx, mysqlApp, primary, dropUserSQL(dbUser)); err != nil {
+		return err
+	}
+	fmt.Fprintf(io.Out, "User %q was dropped from %s\n", dbUser, mysqlApp.Name)
+
+	// Remove secret from consumer app.
+	if _, err := client.UnsetSecrets(ctx, appName, []string{varName}); err != nil {
+		// This will error if secret doesn't exist, so just send to stdout.
+		fmt.Fprintln(io.Out, err.Error())
+	} else {
+		fmt.Fprintf(io.Out, "Secret %q was scheduled to be removed from app %s\n", varName, appName)
+	}
+
+	fmt.Fprintln(io.Out, "Detach completed successfully!")
+
+	return nil
+}
+
+func dropUserSQL(user string) string {
+	return fmt.Sprintf("DROP USER IF EXISTS %s@'%%';\n", quoteString(user))
+}
@@ -0,0 +1,188 @@
+package mysql
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/docker/docker/pkg/ioutils"
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/agent"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/command/apps"
+	"github.com/superfly/flyctl/internal/command/ssh"
+	mach "github.com/superfly/flyctl/internal/machine"
+)
+
+const (
+	// engineMetadataKey marks the machines of a MySQL cluster. Its value is the
+	// engine the machines run.
+	engineMetadataKey = "fly-managed-mysql"
+	// roleMetadataKey is either rolePrimary or roleReplica.
+	roleMetadataKey = "fly-mysql-role"
+
+	rolePrimary = "primary"
+	roleReplica = "replica"
+
+	port       = 3306
+	volumeName = "mysql_data"
+)
+
+type engine struct {
+	// Image is the default image of the engine.
+	Image string
+	// EnvPrefix prefixes the environment variables configuring the image.
+	EnvPrefix string
+	// DataPath is where the image keeps its data.
+	DataPath string
+}
+
+// engines are the supported engines, deployed with the Bitnami images which
+// set up replication from their environment.
+var engines = map[string]engine{
+	"mysql": {
+		Image:     "bitnami/mysql:8.0",
+		EnvPrefix: "MYSQL",
+		DataPath:  "/bitnami/mysql",
+	},
+	"mariadb": {
+		Image:     "bitnami/mariadb:11.1",
+		EnvPrefix: "MARIADB",
+		DataPath:  "/bitnami/mariadb",
+	},
+}
+
+func New() *cobra.Command {
+	const (
+		short = `Manage MySQL and MariaDB clusters.`
+
+		long = short + "\n"
+	)
+
+	cmd := command.New("mysql", short, long, nil)
+
+	cmd.AddCommand(
+		newCreate(),
+		newAttach(),
+		newDetach(),
+		newConnect(),
+		newUsers(),
+	)
+
+	return cmd
+}
+
+// clusterContext resolves the MySQL cluster appName and its machines, and
+// builds a context around it.
+func clusterContext(ctx context.Context, appName string) (context.Context, *api.AppCompact, []*api.Machine, error) {
+	client := client.FromContext(ctx).API()
+
+	app, err := client.GetAppCompact(ctx, appName)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed retrieving app %s: %w", appName, err)
+	}
+	if app.PlatformVersion != "machines" {
+		return nil, nil, nil, fmt.Errorf("app %s is not a mysql app", appName)
+	}
+
+	ctx, err = apps.BuildContext(ctx, app)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	machines, err := mach.ListActive(ctx)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("machines could not be retrieved %w", err)
+	}
+
+	var members []*api.Machine
+	for _, m := range machines {
+		if machineEngine(m) != "" {
+			members = append(members, m)
+		}
+	}
+	if len(members) == 0 {
+		return nil, nil, nil, fmt.Errorf("app %s is not a mysql app", appName)
+	}
+
+	return ctx, app, members, nil
+}
+
+// machineEngine returns the engine a machine of a MySQL cluster runs, or an
+// empty string for any other machine.
+func machineEngine(m *api.Machine) string {
+	if m.Config == nil {
+		return ""
+	}
+	return m.Config.Metadata[engineMetadataKey]
+}
+
+func pickPrimary(machines []*api.Machine) (*api.Machine, error) {
+	for _, m := range machines {
+		if m.Config != nil && m.Config.Metadata[roleMetadataKey] == rolePrimary {
+			return m, nil
+		}
+	}
+	return nil, fmt.Errorf("no active primary found")
+}
+
+// primaryHost is the address of the primary of the cluster on the private
+// network.
+func primaryHost(appName string, primary *api.Machine) string {
+	return fmt.Sprintf("%s.vm.%s.internal", primary.ID, appName)
+}
+
+// runSQL runs statements as root on the primary and returns their output as
+// tab separated rows. The client authenticates with the MYSQL_PWD secret.
+func runSQL(ctx context.Context, app *api.AppCompact, primary *api.Machine, sql string) (string, error) {
+	var out bytes.Buffer
+	if err := sshExec(ctx, app, primary.PrivateIP, "mysql -uroot -N -B", strings.NewReader(sql), &out); err != nil {
+		return "", fmt.Errorf("failed running SQL on %s: %w", primary.ID, err)
+	}
+	return out.String(), nil
+}
+
+// sshExec runs cmd on the machine at addr, feeding it stdin and collecting its
+// output in stdout. The error carries the output of cmd on stderr.
+func sshExec(ctx context.Context, app *api.AppCompact, addr, cmd string, stdin io.Reader, stdout io.Writer) error {
+	var errBuf bytes.Buffer
+	err := ssh.SSHConnect(&ssh.SSHParams{
+		Ctx:            ctx,
+		Org:            app.Organization,
+		Dialer:         agent.DialerFromContext(ctx),
+		App:            app.Name,
+		Username:       ssh.DefaultSshUsername,
+		Cmd:            cmd,
+		Stdin:          stdin,
+		Stdout:         ioutils.NewWriteCloserWrapper(stdout, func() error { return nil }),
+		Stderr:         ioutils.NewWriteCloserWrapper(&errBuf, func() error { return nil }),
+		DisableSpinner: true,
+		DisablePTY:     true,
+	}, addr)
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(errBuf.String()))
+	}
+
+	return nil
+}
+
+// quoteString quotes s as a SQL string literal.
+func quoteString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// quoteIdentifier quotes s as a SQL identifier.
+func quoteIdentifier(s string) string {
+	return "`" + strings.ReplaceAll(s, "`", "``") + "`"
+}
+
+// normalizeName turns an app name into a database or user name.
+func normalizeName(s string) string {
+	return strings.ToLower(strings.ReplaceAll(s, "-", "_"))
+}
@@ -0,0 +1,287 @@
+package mysql
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/helpers"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+// listUsersSQL lists the users of the cluster other than root and the system
+// users, one "user\thost\tdatabases" line per user.
+const listUsersSQL = `SELECT u.User, u.Host, COALESCE(GROUP_CONCAT(DISTINCT d.Db ORDER BY d.Db SEPARATOR ','), '')
+FROM mysql.user u LEFT JOIN mysql.db d ON d.User = u.User AND d.Host = u.Host
+WHERE u.User NOT IN ('', 'root', 'replicator', 'mysql.sys', 'mysql.session', 'mysql.infoschema', 'mariadb.sys')
+GROUP BY u.User, u.Host ORDER BY u.User;
+`
+
+type user struct {
+	Username  string   `json:"username"`
+	Host      string   `json:"host"`
+	Databases []string `json:"databases"`
+}
+
+func newUsers() *cobra.Command {
+	const (
+		short = "Manage users in a MySQL cluster"
+		long  = short + "\n"
+
+		usage = "users"
+	)
+
+	cmd := command.New(usage, short, long, nil)
+
+	cmd.AddCommand(
+		newListUsers(),
+		newCreateUser(),
+		newDeleteUser(),
+	)
+
+	return cmd
+}
+
+func newListUsers() *cobra.Command {
+	const (
+		short = "List users"
+		long  = short + "\n"
+
+		usage = "list"
+	)
+
+	cmd := command.New(usage, short, long, runListUsers,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.JSONOutput(),
+	)
+
+	return cmd
+}
+
+func runListUsers(ctx context.Context) error {
+	var (
+		io      = iostreams.FromContext(ctx)
+		cfg     = config.FromContext(ctx)
+		appName = appconfig.NameFromContext(ctx)
+	)
+
+	ctx, app, machines, err := clusterContext(ctx, appName)
+	if err != nil {
+		return err
+	}
+	primary, err := pickPrimary(machines)
+	if err != nil {
+		return err
+	}
+
+	out, err := runSQL(ctx, app, primary, listUsersSQL)
+	if err != nil {
+		return fmt.Errorf("error fetching users: %w", err)
+	}
+	users := parseUsers(out)
+
+	if cfg.JSONOutput {
+		return render.Output(ctx, io.Out, users)
+	}
+
+	if len(users) == 0 {
+		fmt.Fprintf(io.Out, "No users found\n")
+		return nil
+	}
+
+	rows := make([][]string, 0, len(users))
+	for _, u := range users {
+		rows = append(rows, []string{
+			u.Username,
+			u.Host,
+			strings.Join(u.Databases, ", "),
+		})
+	}
+
+	return render.Table(io.Out, "", rows, "Name", "Host", "Databases")
+}
+
+// parseUsers parses the output of listUsersSQL.
+func parseUsers(out string) []user {
+	var users []user
+
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), "\t")
+		if len(fields) != 3 {
+			continue
+		}
+
+		u := user{
+			Username:  fields[0],
+			Host:      fields[1],
+			Databases: []string{},
+		}
+		if fields[2] != "" {
+			u.Databases = strings.Split(fields[2], ",")
+		}
+		users = append(users, u)
+	}
+
+	return users
+}
+
+func newCreateUser() *cobra.Command {
+	const (
+		short = "Create a user"
+		long  = short + `
+
+Creates a user with every privilege on a database, created if it doesn't
+exist yet. The password is generated when not given.
+`
+		usage = "create <name>"
+	)
+
+	cmd := command.New(usage, short, long, runCreateUser,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	cmd.Args = cobra.ExactArgs(1)
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.String{
+			Name:        "database",
+			Shorthand:   "d",
+			Description: "The database the user owns, defaults to the name of the user",
+		},
+		flag.String{
+			Name:        "password",
+			Shorthand:   "p",
+			Description: "The password of the user",
+		},
+	)
+
+	return cmd
+}
+
+func runCreateUser(ctx context.Context) error {
+	var (
+		io       = iostreams.FromContext(ctx)
+		appName  = appconfig.NameFromContext(ctx)
+		name     = flag.FirstArg(ctx)
+		database = flag.GetString(ctx, "database")
+		password = flag.GetString(ctx, "password")
+	)
+
+	if database == "" {
+		database = normalizeName(name)
+	}
+
+	ctx, app, machines, err := clusterContext(ctx, appName)
+	if err != nil {
+		return err
+	}
+	primary, err := pickPrimary(machines)
+	if err != nil {
+		return err
+	}
+
+	exists, err := userExists(ctx, app, primary, name)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("database user %q already exists", name)
+	}
+
+	if password == "" {
+		if password, err = helpers.RandString(24); err != nil {
+			return err
+		}
+	}
+
+	if _, err := runSQL(ctx, app, primary, createUserSQL(name, password, database)); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(io.Out, "User %s was created with every privilege on database %s\n", name, database)
+	fmt.Fprintf(io.Out, "  Connection string: %s\n", connectionURL(name, password, primaryHost(app.Name, primary), database))
+
+	return nil
+}
+
+func newDeleteUser() *cobra.Command {
+	const (
+		short = "Delete a user"
+		long  = short + "\n"
+		usage = "delete <name>"
+	)
+
+	cmd := command.New(usage, short, long, runDeleteUser,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	cmd.Args = cobra.ExactArgs(1)
+	cmd.Aliases = []string{"rm"}
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.Yes(),
+	)
+
+	return cmd
+}
+
+func runDeleteUser(ctx context.Context) error {
+	var (
+		io      = iostreams.FromContext(ctx)
+		appName = appconfig.NameFromContext(ctx)
+		name    = flag.FirstArg(ctx)
+	)
+
+	ctx, app, machines, err := clusterContext(ctx, appName)
+	if err != nil {
+		return err
+	}
+	primary, err := pickPrimary(machines)
+	if err != nil {
+		return err
+	}
+
+	if !flag.GetYes(ctx) {
+		switch confirmed, err := prompt.Confirmf(ctx, "Delete user %s from %s? Its databases remain intact.", name, appName); {
+		case err == nil:
+			if !confirmed {
+				return nil
+			}
+		case prompt.IsNonInteractive(err):
+			return prompt.NonInteractiveError("yes flag must be specified when not running interactively")
+		default:
+			return err
+		}
+	}
+
+	if _, err := runSQL(ctx, app, primary, dropUserSQL(name)); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(io.Out, "User %s was deleted from %s\n", name, appName)
+	return nil
+}
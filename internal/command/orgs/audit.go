@@ -0,0 +1,373 @@
+package orgs
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/gql"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/format"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newAudit() *cobra.Command {
+	const (
+		long = `Lists the recent activity of an organization: deploys, secrets set,
+machines destroyed, members joining and invitations, along with who did it,
+when and to what, newest first.
+
+--since and --until accept durations ago, e.g. 12h, 7d or 2w, as well as
+dates and RFC 3339 timestamps.
+
+With --app, the organization is that of the app; a slug given along with it
+must match.
+`
+		short = "List the recent activity of an organization"
+		usage = "audit [slug]"
+	)
+
+	cmd := command.New(usage, short, long, runAudit,
+		command.RequireSession,
+	)
+
+	cmd.Args = cobra.MaximumNArgs(1)
+
+	flag.Add(cmd,
+		flag.JSONOutput(),
+		flag.String{
+			Name:        "app",
+			Shorthand:   "a",
+			Description: "Only list the activity of this app",
+		},
+		flag.String{
+			Name:        "since",
+			Description: "List the activity after this time",
+			Default:     "7d",
+		},
+		flag.String{
+			Name:        "until",
+			Description: "List the activity before this time",
+		},
+		flag.StringSlice{
+			Name:        "kind",
+			Description: "Only list activity of these kinds: deploy, secret, machine or member",
+		},
+	)
+
+	return cmd
+}
+
+// Kinds of audit events.
+const (
+	auditDeploy  = "deploy"
+	auditSecret  = "secret"
+	auditMachine = "machine"
+	auditMember  = "member"
+)
+
+// auditEvent is a change made to an organization or one of its apps.
+type auditEvent struct {
+	Time   time.Time `json:"time"`
+	Kind   string    `json:"kind"`
+	Actor  string    `json:"actor"`
+	Target string    `json:"target"`
+	Action string    `json:"action"`
+}
+
+func runAudit(ctx context.Context) error {
+	var (
+		io    = iostreams.FromContext(ctx)
+		now   = time.Now()
+		kinds = flag.GetStringSlice(ctx, "kind")
+	)
+
+	for _, kind := range kinds {
+		if !lo.Contains([]string{auditDeploy, auditSecret, auditMachine, auditMember}, kind) {
+			return fmt.Errorf("unknown kind %q, expected one of %s, %s, %s or %s", kind, auditDeploy, auditSecret, auditMachine, auditMember)
+		}
+	}
+
+	since, err := parseAuditTime(flag.GetString(ctx, "since"), now)
+	if err != nil {
+		return fmt.Errorf("invalid --since: %w", err)
+	}
+	until := now
+	if v := flag.GetString(ctx, "until"); v != "" {
+		if until, err = parseAuditTime(v, now); err != nil {
+			return fmt.Errorf("invalid --until: %w", err)
+		}
+	}
+
+	appName := flag.GetString(ctx, "app")
+
+	var org *api.OrganizationBasic
+	if appName != "" {
+		if org, err = appOrg(ctx, appName, flag.FirstArg(ctx)); err != nil {
+			return err
+		}
+	} else {
+		selected, err := OrgFromFirstArgOrSelect(ctx)
+		if err != nil {
+			return err
+		}
+		org = &api.OrganizationBasic{ID: selected.ID, Slug: selected.Slug}
+	}
+
+	events, err := auditEvents(ctx, org.ID, org.Slug, appName)
+	if err != nil {
+		return err
+	}
+
+	events = lo.Filter(events, func(e auditEvent, _ int) bool {
+		return !e.Time.Before(since) && !e.Time.After(until) && (len(kinds) == 0 || lo.Contains(kinds, e.Kind))
+	})
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Time.After(events[j].Time) })
+
+	if config.FromContext(ctx).JSONOutput {
+		return render.Output(ctx, io.Out, events)
+	}
+
+	rows := make([][]string, 0, len(events))
+	for _, e := range events {
+		rows = append(rows, []string{
+			format.RelativeTime(e.Time),
+			e.Kind,
+			lo.Ternary(e.Actor == "", "-", e.Actor),
+			e.Target,
+			e.Action,
+		})
+	}
+
+	return render.Table(io.Out, "", rows, "When", "Kind", "Actor", "Target", "Action")
+}
+
+// appOrg returns the organization of the app, which must be the one named by
+// orgSlug when set, so that no organization is prompted for.
+func appOrg(ctx context.Context, appName, orgSlug string) (*api.OrganizationBasic, error) {
+	app, err := client.FromContext(ctx).API().GetAppCompact(ctx, appName)
+	if err != nil {
+		return nil, fmt.Errorf("failed retrieving app %s: %w", appName, err)
+	}
+
+	if orgSlug != "" && orgSlug != app.Organization.Slug && orgSlug != app.Organization.RawSlug {
+		return nil, fmt.Errorf("app %s does not belong to organization %s", appName, orgSlug)
+	}
+
+	return app.Organization, nil
+}
+
+// auditEvents returns the events of the organization, or of only one of its
+// apps when appName is set. Apps are queried concurrently.
+func auditEvents(ctx context.Context, orgID, orgSlug, appName string) ([]auditEvent, error) {
+	apiClient := client.FromContext(ctx).API()
+
+	var events []auditEvent
+
+	appNames := []string{appName}
+	if appName == "" {
+		apps, err := apiClient.GetAppsForOrganization(ctx, orgID)
+		if err != nil {
+			return nil, fmt.Errorf("failed retrieving apps: %w", err)
+		}
+		appNames = lo.Map(apps, func(app api.App, _ int) string { return app.Name })
+
+		memberEvents, err := orgAuditEvents(ctx, orgSlug)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, memberEvents...)
+	}
+
+	// events of each app, in the order of the apps
+	appEvents := make([][]auditEvent, len(appNames))
+
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.SetLimit(flaps.MaxConcurrentRequests)
+	for i, name := range appNames {
+		i, name := i, name
+		eg.Go(func() (err error) {
+			appEvents[i], err = appAuditEvents(ctx, name)
+			return err
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+
+	for _, e := range appEvents {
+		events = append(events, e...)
+	}
+
+	return events, nil
+}
+
+func orgAuditEvents(ctx context.Context, orgSlug string) ([]auditEvent, error) {
+	_ = `# @genqlient
+	query OrgAuditLog($slug: String!) {
+		organization(slug: $slug) {
+			members {
+				edges {
+					joinedAt
+					node {
+						email
+					}
+				}
+			}
+			invitations {
+				nodes {
+					createdAt
+					email
+					inviter {
+						email
+					}
+				}
+			}
+		}
+	}
+	`
+
+	resp, err := gql.OrgAuditLog(ctx, client.FromContext(ctx).API().GenqClient, orgSlug)
+	if err != nil {
+		return nil, fmt.Errorf("failed retrieving members of %s: %w", orgSlug, err)
+	}
+
+	var events []auditEvent
+	for _, edge := range resp.Organization.Members.Edges {
+		events = append(events, auditEvent{
+			Time:   edge.JoinedAt,
+			Kind:   auditMember,
+			Actor:  edge.Node.Email,
+			Target: orgSlug,
+			Action: "Joined the organization",
+		})
+	}
+	for _, invitation := range resp.Organization.Invitations.Nodes {
+		events = append(events, auditEvent{
+			Time:   invitation.CreatedAt,
+			Kind:   auditMember,
+			Actor:  invitation.Inviter.Email,
+			Target: orgSlug,
+			Action: "Invited " + invitation.Email,
+		})
+	}
+
+	return events, nil
+}
+
+func appAuditEvents(ctx context.Context, appName string) ([]auditEvent, error) {
+	_ = `# @genqlient
+	query AppAuditLog($appName: String!) {
+		app(name: $appName) {
+			releasesUnprocessed(first: 50) {
+				nodes {
+					version
+					description
+					status
+					createdAt
+					user {
+						email
+					}
+				}
+			}
+			secrets {
+				name
+				createdAt
+				user {
+					email
+				}
+			}
+			machines(active: false, first: 100) {
+				nodes {
+					id
+					name
+					region
+					state
+					updatedAt
+				}
+			}
+		}
+	}
+	`
+
+	resp, err := gql.AppAuditLog(ctx, client.FromContext(ctx).API().GenqClient, appName)
+	if err != nil {
+		return nil, fmt.Errorf("failed retrieving activity of %s: %w", appName, err)
+	}
+
+	var events []auditEvent
+	for _, release := range resp.App.ReleasesUnprocessed.Nodes {
+		action := fmt.Sprintf("Release v%d %s", release.Version, release.Status)
+		if release.Description != "" {
+			action += ": " + release.Description
+		}
+		events = append(events, auditEvent{
+			Time:   release.CreatedAt,
+			Kind:   auditDeploy,
+			Actor:  release.User.Email,
+			Target: appName,
+			Action: action,
+		})
+	}
+	for _, secret := range resp.App.Secrets {
+		events = append(events, auditEvent{
+			Time:   secret.CreatedAt,
+			Kind:   auditSecret,
+			Actor:  secret.User.Email,
+			Target: appName,
+			Action: "Set secret " + secret.Name,
+		})
+	}
+	for _, machine := range resp.App.Machines.Nodes {
+		if machine.State != "destroyed" {
+			continue
+		}
+		events = append(events, auditEvent{
+			Time:   machine.UpdatedAt,
+			Kind:   auditMachine,
+			Target: appName,
+			Action: fmt.Sprintf("Destroyed machine %s (%s) in %s", machine.Id, machine.Name, machine.Region),
+		})
+	}
+
+	return events, nil
+}
+
+// parseAuditTime parses durations ago, which may be in days and weeks, dates
+// and RFC 3339 timestamps.
+func parseAuditTime(value string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+		return t, nil
+	}
+
+	days := map[string]int{"d": 1, "w": 7}
+	if len(value) > 1 {
+		if n, err := strconv.Atoi(value[:len(value)-1]); err == nil {
+			if multiplier, ok := days[value[len(value)-1:]]; ok && n >= 0 {
+				return now.AddDate(0, 0, -n*multiplier), nil
+			}
+		}
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return time.Time{}, fmt.Errorf("%q is not a duration like 12h, 7d or 2w, a date or a timestamp", value)
+	}
+
+	return now.Add(-d), nil
+}
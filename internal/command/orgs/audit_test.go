@@ -0,0 +1,47 @@
+package orgs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAuditTime(t *testing.T) {
+	now := time.Date(2023, 5, 17, 12, 30, 0, 0, time.UTC)
+
+	cases := []struct {
+		value    string
+		expected time.Time
+		err      bool
+	}{
+		{value: "2023-05-01T08:00:00Z", expected: time.Date(2023, 5, 1, 8, 0, 0, 0, time.UTC)},
+		{value: "2023-05-01T08:00:00+02:00", expected: time.Date(2023, 5, 1, 6, 0, 0, 0, time.UTC)},
+		{value: "2023-05-01", expected: time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{value: "7d", expected: time.Date(2023, 5, 10, 12, 30, 0, 0, time.UTC)},
+		{value: "2w", expected: time.Date(2023, 5, 3, 12, 30, 0, 0, time.UTC)},
+		{value: "0d", expected: now},
+		{value: "12h", expected: time.Date(2023, 5, 17, 0, 30, 0, 0, time.UTC)},
+		{value: "1h30m", expected: time.Date(2023, 5, 17, 11, 0, 0, 0, time.UTC)},
+		{value: "", err: true},
+		{value: "d", err: true},
+		{value: "-7d", err: true},
+		{value: "-12h", err: true},
+		{value: "7y", err: true},
+		{value: "yesterday", err: true},
+		{value: "2023-13-01", err: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.value, func(t *testing.T) {
+			actual, err := parseAuditTime(tc.value, now)
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, tc.expected.Equal(actual), "expected %s, got %s", tc.expected, actual)
+		})
+	}
+}
@@ -61,7 +61,7 @@ func runCreate(ctx context.Context) error {
 	}
 
 	if io := iostreams.FromContext(ctx); config.FromContext(ctx).JSONOutput {
-		_ = render.JSON(io.Out, org)
+		_ = render.Output(ctx, io.Out, org)
 	} else {
 		printOrg(io.Out, org, true)
 	}
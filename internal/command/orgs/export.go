@@ -0,0 +1,403 @@
+package orgs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newExport() *cobra.Command {
+	const (
+		long = `Exports the state of an organization as a single document.
+The export includes every app along with its machine configs, volume
+metadata, IP addresses, certificates and the names (never the values)
+of its secrets.
+
+Pass --diff with the path to a previous export to print what changed
+since then instead of the export itself. The changes are listed as text,
+or as JSON when --format json is given.
+`
+		short = "Export the state of an organization"
+		usage = "export [slug]"
+	)
+
+	cmd := command.New(usage, short, long, runExport,
+		command.RequireSession,
+	)
+
+	cmd.Args = cobra.MaximumNArgs(1)
+
+	flag.Add(cmd,
+		flag.String{
+			Name:        "format",
+			Description: "The format of the export. Only json is currently supported",
+			Default:     "json",
+		},
+		flag.String{
+			Name:        "output",
+			Shorthand:   "o",
+			Description: "Write the export to this file instead of stdout",
+		},
+		flag.String{
+			Name:        "diff",
+			Description: "Compare the current state against the export at this path",
+		},
+	)
+
+	return cmd
+}
+
+// OrgExport is the document produced by `fly orgs export`.
+type OrgExport struct {
+	Organization string      `json:"organization"`
+	ExportedAt   time.Time   `json:"exported_at"`
+	Apps         []AppExport `json:"apps"`
+}
+
+// AppExport captures the state of a single app within an OrgExport.
+type AppExport struct {
+	Name            string              `json:"name"`
+	PlatformVersion string              `json:"platform_version"`
+	Status          string              `json:"status"`
+	Machines        []MachineExport     `json:"machines,omitempty"`
+	Volumes         []VolumeExport      `json:"volumes,omitempty"`
+	IPAddresses     []IPAddressExport   `json:"ip_addresses,omitempty"`
+	Certificates    []CertificateExport `json:"certificates,omitempty"`
+	Secrets         []string            `json:"secrets,omitempty"`
+}
+
+type MachineExport struct {
+	ID     string             `json:"id"`
+	Name   string             `json:"name"`
+	Region string             `json:"region"`
+	Config *api.MachineConfig `json:"config"`
+}
+
+type VolumeExport struct {
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	Region          string `json:"region"`
+	SizeGb          int    `json:"size_gb"`
+	Encrypted       bool   `json:"encrypted"`
+	AttachedMachine string `json:"attached_machine,omitempty"`
+}
+
+type IPAddressExport struct {
+	Address string `json:"address"`
+	Type    string `json:"type"`
+	Region  string `json:"region"`
+}
+
+type CertificateExport struct {
+	Hostname string `json:"hostname"`
+	Status   string `json:"status"`
+}
+
+func runExport(ctx context.Context) error {
+	if format := flag.GetString(ctx, "format"); format != "json" {
+		return fmt.Errorf("unsupported export format %q", format)
+	}
+
+	org, err := OrgFromFirstArgOrSelect(ctx)
+	if err != nil {
+		return err
+	}
+
+	export, err := exportOrg(ctx, org)
+	if err != nil {
+		return err
+	}
+
+	io := iostreams.FromContext(ctx)
+
+	out := io.Out
+	if path := flag.GetString(ctx, "output"); path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("failed creating export file: %w", err)
+		}
+		defer f.Close() // skipcq: GO-S2307
+
+		out = f
+	}
+
+	if path := flag.GetString(ctx, "diff"); path != "" {
+		previous, err := readExport(path)
+		if err != nil {
+			return err
+		}
+
+		changes := diffExports(previous, export)
+
+		// changes are listed as text unless a format is asked for
+		if flag.IsSpecified(ctx, "format") {
+			return render.Output(ctx, out, changes)
+		}
+
+		if len(changes) == 0 {
+			fmt.Fprintf(out, "No changes since %s\n", previous.ExportedAt.Format(time.RFC3339))
+			return nil
+		}
+		for _, change := range changes {
+			fmt.Fprintln(out, change)
+		}
+		return nil
+	}
+
+	return render.Output(ctx, out, export)
+}
+
+func exportOrg(ctx context.Context, org *api.Organization) (*OrgExport, error) {
+	client := client.FromContext(ctx).API()
+
+	apps, err := client.GetAppsForOrganization(ctx, org.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed retrieving apps: %w", err)
+	}
+
+	export := &OrgExport{
+		Organization: org.Slug,
+		ExportedAt:   time.Now().UTC(),
+	}
+
+	for _, app := range apps {
+		appExport, err := ExportApp(ctx, app)
+		if err != nil {
+			return nil, fmt.Errorf("failed exporting app %s: %w", app.Name, err)
+		}
+		export.Apps = append(export.Apps, *appExport)
+	}
+
+	sort.Slice(export.Apps, func(i, j int) bool {
+		return export.Apps[i].Name < export.Apps[j].Name
+	})
+
+	return export, nil
+}
+
+// ExportApp captures the state of the app.
+func ExportApp(ctx context.Context, app api.App) (*AppExport, error) {
+	client := client.FromContext(ctx).API()
+
+	export := &AppExport{
+		Name:            app.Name,
+		PlatformVersion: app.PlatformVersion,
+		Status:          app.Status,
+	}
+
+	if app.PlatformVersion == appconfig.MachinesPlatform {
+		flapsClient, err := flaps.NewFromAppName(ctx, app.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		machines, err := flapsClient.List(ctx, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed retrieving machines: %w", err)
+		}
+		for _, m := range machines {
+			export.Machines = append(export.Machines, MachineExport{
+				ID:     m.ID,
+				Name:   m.Name,
+				Region: m.Region,
+				Config: m.Config,
+			})
+		}
+	}
+
+	volumes, err := client.GetVolumes(ctx, app.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed retrieving volumes: %w", err)
+	}
+	for _, v := range volumes {
+		volume := VolumeExport{
+			ID:        v.ID,
+			Name:      v.Name,
+			Region:    v.Region,
+			SizeGb:    v.SizeGb,
+			Encrypted: v.Encrypted,
+		}
+		if v.AttachedMachine != nil {
+			volume.AttachedMachine = v.AttachedMachine.ID
+		}
+		export.Volumes = append(export.Volumes, volume)
+	}
+
+	ips, err := client.GetIPAddresses(ctx, app.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed retrieving IP addresses: %w", err)
+	}
+	for _, ip := range ips {
+		export.IPAddresses = append(export.IPAddresses, IPAddressExport{
+			Address: ip.Address,
+			Type:    ip.Type,
+			Region:  ip.Region,
+		})
+	}
+
+	certs, err := client.GetAppCertificates(ctx, app.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed retrieving certificates: %w", err)
+	}
+	for _, cert := range certs {
+		export.Certificates = append(export.Certificates, CertificateExport{
+			Hostname: cert.Hostname,
+			Status:   cert.ClientStatus,
+		})
+	}
+
+	secrets, err := client.GetAppSecrets(ctx, app.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed retrieving secrets: %w", err)
+	}
+	for _, secret := range secrets {
+		export.Secrets = append(export.Secrets, secret.Name)
+	}
+	sort.Strings(export.Secrets)
+
+	return export, nil
+}
+
+func readExport(path string) (*OrgExport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading previous export: %w", err)
+	}
+
+	var export OrgExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("failed parsing previous export %s: %w", path, err)
+	}
+
+	return &export, nil
+}
+
+// Operations of an ExportChange.
+const (
+	exportAdded   = "added"
+	exportRemoved = "removed"
+	exportChanged = "changed"
+)
+
+// ExportChange is a resource added, removed or changed between two exports.
+type ExportChange struct {
+	Op     string `json:"op"`
+	App    string `json:"app"`
+	Kind   string `json:"kind"`
+	ID     string `json:"id,omitempty"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// String formats the change as a line prefixed by +, - or ~.
+func (c ExportChange) String() string {
+	line := map[string]string{exportAdded: "+", exportRemoved: "-", exportChanged: "~"}[c.Op] + " app " + c.App
+	if c.Kind != "app" {
+		line += fmt.Sprintf(": %s %s", c.Kind, c.ID)
+	}
+	if c.Detail != "" {
+		line += ": " + c.Detail
+	}
+	return line
+}
+
+// diffExports returns every resource that was added, removed or changed
+// between the previous and current exports.
+func diffExports(previous, current *OrgExport) []ExportChange {
+	changes := []ExportChange{}
+
+	prevApps := make(map[string]AppExport, len(previous.Apps))
+	for _, app := range previous.Apps {
+		prevApps[app.Name] = app
+	}
+
+	for _, app := range current.Apps {
+		prev, ok := prevApps[app.Name]
+		delete(prevApps, app.Name)
+
+		if !ok {
+			changes = append(changes, ExportChange{Op: exportAdded, App: app.Name, Kind: "app"})
+			continue
+		}
+
+		if prev.PlatformVersion != app.PlatformVersion {
+			changes = append(changes, ExportChange{
+				Op:     exportChanged,
+				App:    app.Name,
+				Kind:   "app",
+				Detail: fmt.Sprintf("platform %s -> %s", prev.PlatformVersion, app.PlatformVersion),
+			})
+		}
+
+		changes = append(changes, diffResources(app.Name, "machine", prev.Machines, app.Machines, func(m MachineExport) string { return m.ID })...)
+		changes = append(changes, diffResources(app.Name, "volume", prev.Volumes, app.Volumes, func(v VolumeExport) string { return v.ID })...)
+		changes = append(changes, diffResources(app.Name, "ip", prev.IPAddresses, app.IPAddresses, func(ip IPAddressExport) string { return ip.Address })...)
+		changes = append(changes, diffResources(app.Name, "certificate", prev.Certificates, app.Certificates, func(c CertificateExport) string { return c.Hostname })...)
+		changes = append(changes, diffResources(app.Name, "secret", prev.Secrets, app.Secrets, func(s string) string { return s })...)
+	}
+
+	removed := make([]string, 0, len(prevApps))
+	for name := range prevApps {
+		removed = append(removed, name)
+	}
+	sort.Strings(removed)
+	for _, name := range removed {
+		changes = append(changes, ExportChange{Op: exportRemoved, App: name, Kind: "app"})
+	}
+
+	return changes
+}
+
+func diffResources[T any](appName, kind string, previous, current []T, key func(T) string) (changes []ExportChange) {
+	prev := make(map[string]T, len(previous))
+	for _, r := range previous {
+		prev[key(r)] = r
+	}
+
+	for _, r := range current {
+		k := key(r)
+		p, ok := prev[k]
+		delete(prev, k)
+
+		switch {
+		case !ok:
+			changes = append(changes, ExportChange{Op: exportAdded, App: appName, Kind: kind, ID: k})
+		case !sameJSON(p, r):
+			changes = append(changes, ExportChange{Op: exportChanged, App: appName, Kind: kind, ID: k})
+		}
+	}
+
+	removed := make([]string, 0, len(prev))
+	for k := range prev {
+		removed = append(removed, k)
+	}
+	sort.Strings(removed)
+	for _, k := range removed {
+		changes = append(changes, ExportChange{Op: exportRemoved, App: appName, Kind: kind, ID: k})
+	}
+
+	return
+}
+
+// sameJSON compares values by their JSON encoding, since that's the form in
+// which previous exports are read back.
+func sameJSON(a, b any) bool {
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+
+	return errA == nil && errB == nil && bytes.Equal(ja, jb)
+}
@@ -0,0 +1,156 @@
+package orgs
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/internal/render"
+)
+
+func testAppExport() AppExport {
+	return AppExport{
+		Name:            "app",
+		PlatformVersion: "machines",
+		Machines: []MachineExport{
+			{ID: "m1", Region: "ord", Config: &api.MachineConfig{Image: "app:v1"}},
+			{ID: "m2", Region: "ord", Config: &api.MachineConfig{Image: "app:v1"}},
+		},
+		Volumes: []VolumeExport{
+			{ID: "vol_1", Name: "data", Region: "ord", SizeGb: 1},
+			{ID: "vol_2", Name: "data", Region: "ord", SizeGb: 1},
+		},
+		IPAddresses: []IPAddressExport{
+			{Address: "1.2.3.4", Type: "v4"},
+			{Address: "::1", Type: "v6"},
+		},
+		Certificates: []CertificateExport{
+			{Hostname: "a.example.com", Status: "Ready"},
+			{Hostname: "b.example.com", Status: "Ready"},
+		},
+		Secrets: []string{"A", "B"},
+	}
+}
+
+func TestDiffExportsNoChanges(t *testing.T) {
+	previous := &OrgExport{Apps: []AppExport{testAppExport()}}
+	current := &OrgExport{Apps: []AppExport{testAppExport()}}
+
+	changes := diffExports(previous, current)
+	assert.Empty(t, changes)
+
+	var buf bytes.Buffer
+	require.NoError(t, render.JSON(&buf, changes))
+	assert.JSONEq(t, `[]`, buf.String())
+}
+
+func TestDiffExportsApps(t *testing.T) {
+	moved := testAppExport()
+	moved.Name = "moved"
+	moved.PlatformVersion = "nomad"
+	gone := testAppExport()
+	gone.Name = "gone"
+	previous := &OrgExport{Apps: []AppExport{gone, moved}}
+
+	added := testAppExport()
+	added.Name = "added"
+	moved = testAppExport()
+	moved.Name = "moved"
+	current := &OrgExport{Apps: []AppExport{added, moved}}
+
+	assert.Equal(t, []ExportChange{
+		{Op: exportAdded, App: "added", Kind: "app"},
+		{Op: exportChanged, App: "moved", Kind: "app", Detail: "platform nomad -> machines"},
+		{Op: exportRemoved, App: "gone", Kind: "app"},
+	}, diffExports(previous, current))
+}
+
+func TestDiffExportsResources(t *testing.T) {
+	cases := []struct {
+		kind   string
+		change func(*AppExport)
+	}{
+		{
+			kind: "machine",
+			change: func(app *AppExport) {
+				app.Machines[0].Config = &api.MachineConfig{Image: "app:v2"}
+				app.Machines = append(app.Machines[:1], MachineExport{ID: "m3", Region: "ord"})
+			},
+		},
+		{
+			kind: "volume",
+			change: func(app *AppExport) {
+				app.Volumes[0].SizeGb = 2
+				app.Volumes = append(app.Volumes[:1], VolumeExport{ID: "vol_3"})
+			},
+		},
+		{
+			kind: "ip",
+			change: func(app *AppExport) {
+				app.IPAddresses[0].Region = "ord"
+				app.IPAddresses = append(app.IPAddresses[:1], IPAddressExport{Address: "5.6.7.8"})
+			},
+		},
+		{
+			kind: "certificate",
+			change: func(app *AppExport) {
+				app.Certificates[0].Status = "Awaiting configuration"
+				app.Certificates = append(app.Certificates[:1], CertificateExport{Hostname: "c.example.com"})
+			},
+		},
+	}
+
+	ids := map[string][3]string{
+		"machine":     {"m3", "m1", "m2"},
+		"volume":      {"vol_3", "vol_1", "vol_2"},
+		"ip":          {"5.6.7.8", "1.2.3.4", "::1"},
+		"certificate": {"c.example.com", "a.example.com", "b.example.com"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.kind, func(t *testing.T) {
+			previous := &OrgExport{Apps: []AppExport{testAppExport()}}
+			app := testAppExport()
+			tc.change(&app)
+			current := &OrgExport{Apps: []AppExport{app}}
+
+			id := ids[tc.kind]
+			assert.Equal(t, []ExportChange{
+				{Op: exportChanged, App: "app", Kind: tc.kind, ID: id[1]},
+				{Op: exportAdded, App: "app", Kind: tc.kind, ID: id[0]},
+				{Op: exportRemoved, App: "app", Kind: tc.kind, ID: id[2]},
+			}, diffExports(previous, current))
+		})
+	}
+}
+
+func TestDiffExportsSecrets(t *testing.T) {
+	previous := &OrgExport{Apps: []AppExport{testAppExport()}}
+	app := testAppExport()
+	app.Secrets = []string{"A", "C"}
+	current := &OrgExport{Apps: []AppExport{app}}
+
+	// secrets have no value to compare, so they are only ever added or removed
+	assert.Equal(t, []ExportChange{
+		{Op: exportAdded, App: "app", Kind: "secret", ID: "C"},
+		{Op: exportRemoved, App: "app", Kind: "secret", ID: "B"},
+	}, diffExports(previous, current))
+}
+
+func TestExportChangeString(t *testing.T) {
+	cases := map[string]ExportChange{
+		"+ app web":                             {Op: exportAdded, App: "web", Kind: "app"},
+		"- app web":                             {Op: exportRemoved, App: "web", Kind: "app"},
+		"~ app web: platform nomad -> machines": {Op: exportChanged, App: "web", Kind: "app", Detail: "platform nomad -> machines"},
+		"+ app web: machine m1":                 {Op: exportAdded, App: "web", Kind: "machine", ID: "m1"},
+		"~ app web: volume vol_1":               {Op: exportChanged, App: "web", Kind: "volume", ID: "vol_1"},
+		"- app web: secret A":                   {Op: exportRemoved, App: "web", Kind: "secret", ID: "A"},
+	}
+
+	for expected, change := range cases {
+		assert.Equal(t, expected, change.String())
+	}
+}
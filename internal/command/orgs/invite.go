@@ -57,7 +57,7 @@ func runInvite(ctx context.Context) error {
 	io := iostreams.FromContext(ctx)
 
 	if cfg.JSONOutput {
-		_ = render.JSON(io.Out, inv)
+		_ = render.Output(ctx, io.Out, inv)
 
 		return nil
 	}
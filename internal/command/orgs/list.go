@@ -50,7 +50,7 @@ func runList(ctx context.Context) error {
 			orgs[other.Slug] = other.Name
 		}
 
-		_ = render.JSON(out, orgs)
+		_ = render.Output(ctx, out, orgs)
 
 		return nil
 	}
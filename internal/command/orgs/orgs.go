@@ -39,6 +39,9 @@ Organization admins can also invite or remove users from Organizations.
 		newRemove(),
 		newCreate(),
 		newDelete(),
+		newExport(),
+		newPolicy(),
+		newAudit(),
 		appsv2.New(),
 	)
 
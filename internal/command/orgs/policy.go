@@ -0,0 +1,237 @@
+package orgs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/policy"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newPolicy() *cobra.Command {
+	const (
+		long = `Commands for managing the policy of an organization, the constraints
+flyctl enforces before deploying, launching, running and scaling machines and
+allocating IP addresses: the regions machines run in, the largest machine
+size, whether apps may have a dedicated IPv4 address and the least number of
+machines of production apps.
+
+Policies are stored with the organization, in an app holding them, and
+enforced by flyctl for every member and token of the organization.
+`
+		short = "Manage the policy of an organization"
+	)
+
+	cmd := command.New("policy", short, long, nil)
+
+	cmd.AddCommand(
+		newPolicySet(),
+		newPolicyShow(),
+		newPolicyUnset(),
+	)
+
+	return cmd
+}
+
+func newPolicySet() *cobra.Command {
+	const (
+		long = `Sets the constraints of the policy of an organization. Constraints that
+aren't passed are kept as they are, and are lifted by passing their zero
+value, e.g. --min-machines 0 or --allowed-regions "".
+`
+		short = "Set the policy of an organization"
+		usage = "set [slug]"
+	)
+
+	cmd := command.New(usage, short, long, runPolicySet,
+		command.RequireSession,
+	)
+
+	cmd.Args = cobra.MaximumNArgs(1)
+
+	flag.Add(cmd,
+		flag.StringSlice{
+			Name:        "allowed-regions",
+			Description: "The only regions machines may run in, comma separated",
+		},
+		flag.String{
+			Name:        "max-machine-size",
+			Description: "The largest machine size, e.g. shared-cpu-2x",
+		},
+		flag.Bool{
+			Name:        "forbid-dedicated-ipv4",
+			Description: "Forbid allocating dedicated IPv4 addresses",
+		},
+		flag.Int{
+			Name:        "min-machines",
+			Description: "The least number of machines of each process group serving requests of production apps",
+		},
+		flag.StringSlice{
+			Name:        "production-apps",
+			Description: "Glob patterns of the names of production apps, e.g. *-prod. Every app is a production app by default",
+		},
+	)
+
+	return cmd
+}
+
+func runPolicySet(ctx context.Context) error {
+	io := iostreams.FromContext(ctx)
+
+	org, err := OrgFromFirstArgOrSelect(ctx)
+	if err != nil {
+		return err
+	}
+
+	p, err := policy.Load(ctx, org.Slug)
+	if err != nil {
+		return err
+	}
+	if p == nil {
+		p = &policy.Policy{Org: org.Slug}
+	}
+
+	if flag.IsSpecified(ctx, "allowed-regions") {
+		p.AllowedRegions = nonEmpty(flag.GetStringSlice(ctx, "allowed-regions"))
+	}
+	if flag.IsSpecified(ctx, "max-machine-size") {
+		p.MaxMachineSize = flag.GetString(ctx, "max-machine-size")
+	}
+	if flag.IsSpecified(ctx, "forbid-dedicated-ipv4") {
+		p.ForbidDedicatedIPv4 = flag.GetBool(ctx, "forbid-dedicated-ipv4")
+	}
+	if flag.IsSpecified(ctx, "min-machines") {
+		p.MinMachines = flag.GetInt(ctx, "min-machines")
+	}
+	if flag.IsSpecified(ctx, "production-apps") {
+		p.ProductionApps = nonEmpty(flag.GetStringSlice(ctx, "production-apps"))
+	}
+	p.UpdatedAt = time.Now().UTC()
+
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	if err := policy.Save(ctx, p); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(io.Out, "Updated the policy of %s\n", org.Slug)
+	printPolicy(io, p)
+
+	return nil
+}
+
+func nonEmpty(values []string) []string {
+	var kept []string
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			kept = append(kept, v)
+		}
+	}
+
+	return kept
+}
+
+func newPolicyShow() *cobra.Command {
+	const (
+		long  = "Shows the constraints of the policy of an organization."
+		short = "Show the policy of an organization"
+		usage = "show [slug]"
+	)
+
+	cmd := command.New(usage, short, long, runPolicyShow,
+		command.RequireSession,
+	)
+
+	cmd.Args = cobra.MaximumNArgs(1)
+
+	flag.Add(cmd, flag.JSONOutput())
+
+	return cmd
+}
+
+func runPolicyShow(ctx context.Context) error {
+	io := iostreams.FromContext(ctx)
+
+	org, err := OrgFromFirstArgOrSelect(ctx)
+	if err != nil {
+		return err
+	}
+
+	p, err := policy.Load(ctx, org.Slug)
+	if err != nil {
+		return err
+	}
+
+	if config.FromContext(ctx).JSONOutput {
+		return render.Output(ctx, io.Out, p)
+	}
+
+	if p == nil {
+		fmt.Fprintf(io.Out, "%s has no policy\n", org.Slug)
+		return nil
+	}
+
+	printPolicy(io, p)
+
+	return nil
+}
+
+func printPolicy(io *iostreams.IOStreams, p *policy.Policy) {
+	lines := p.Describe()
+	if len(lines) == 0 {
+		fmt.Fprintln(io.Out, "The policy has no constraints")
+		return
+	}
+
+	for _, line := range lines {
+		fmt.Fprintf(io.Out, "  * %s\n", line)
+	}
+}
+
+func newPolicyUnset() *cobra.Command {
+	const (
+		long  = "Removes the policy of an organization, lifting all its constraints."
+		short = "Remove the policy of an organization"
+		usage = "unset [slug]"
+	)
+
+	cmd := command.New(usage, short, long, runPolicyUnset,
+		command.RequireSession,
+	)
+
+	cmd.Args = cobra.MaximumNArgs(1)
+
+	return cmd
+}
+
+func runPolicyUnset(ctx context.Context) error {
+	io := iostreams.FromContext(ctx)
+
+	org, err := OrgFromFirstArgOrSelect(ctx)
+	if err != nil {
+		return err
+	}
+
+	removed, err := policy.Remove(ctx, org.Slug)
+	if err != nil {
+		return err
+	}
+
+	if !removed {
+		fmt.Fprintf(io.Out, "%s has no policy\n", org.Slug)
+		return nil
+	}
+
+	fmt.Fprintf(io.Out, "Removed the policy of %s\n", org.Slug)
+
+	return nil
+}
@@ -50,7 +50,7 @@ func runShow(ctx context.Context) (err error) {
 
 	io := iostreams.FromContext(ctx)
 	if config.FromContext(ctx).JSONOutput {
-		_ = render.JSON(io.Out, org)
+		_ = render.Output(ctx, io.Out, org)
 
 		return nil
 	}
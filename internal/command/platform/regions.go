@@ -45,7 +45,7 @@ func runRegions(ctx context.Context) error {
 
 	out := iostreams.FromContext(ctx).Out
 	if config.FromContext(ctx).JSONOutput {
-		return render.JSON(out, regions)
+		return render.Output(ctx, out, regions)
 	}
 
 	var rows [][]string
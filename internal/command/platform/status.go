@@ -76,7 +76,7 @@ func runStatus(ctx context.Context) error {
 			return nil
 		}
 		out := iostreams.FromContext(ctx).Out
-		return render.JSON(out, result)
+		return render.Output(ctx, out, result)
 	}
 
 	w := iostreams.FromContext(ctx).ErrOut
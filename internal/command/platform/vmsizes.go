@@ -43,7 +43,7 @@ func runVMSizes(ctx context.Context) error {
 
 	out := iostreams.FromContext(ctx).Out
 	if config.FromContext(ctx).JSONOutput {
-		return render.JSON(out, sizes)
+		return render.Output(ctx, out, sizes)
 	}
 
 	var rows [][]string
@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -29,6 +30,14 @@ type AttachParams struct {
 	VariableName string
 	SuperUser    bool
 	Force        bool
+	// Environment suffixes the default database and user names, so that each
+	// environment of an app gets a database of its own.
+	Environment string
+	// LimitedGrants restricts the user to reading and writing data. Schema
+	// changes are made by a separate migration user, whose connection string
+	// is set as MigrationVariableName.
+	LimitedGrants         bool
+	MigrationVariableName string
 }
 
 func newAttach() *cobra.Command {
@@ -65,6 +74,19 @@ func newAttach() *cobra.Command {
 			Default:     true,
 			Description: "Grants attached user superuser privileges",
 		},
+		flag.String{
+			Name:        "environment",
+			Description: "The environment of the consuming app, appended to the default database name and user to keep environments apart",
+		},
+		flag.Bool{
+			Name:        "limited-grants",
+			Description: "Only grant the attached user access to data, and create a separate migration user owning the database for schema changes. Implies --superuser=false",
+		},
+		flag.String{
+			Name:        "migration-variable-name",
+			Default:     "MIGRATION_DATABASE_URL",
+			Description: "The environment variable name of the migration user that will be added to the consuming app, with --limited-grants",
+		},
 		flag.Yes(),
 	)
 
@@ -106,6 +128,10 @@ func runAttach(ctx context.Context) error {
 		VariableName: flag.GetString(ctx, "variable-name"),
 		Force:        flag.GetBool(ctx, "yes"),
 		SuperUser:    flag.GetBool(ctx, "superuser"),
+
+		Environment:           flag.GetString(ctx, "environment"),
+		LimitedGrants:         flag.GetBool(ctx, "limited-grants"),
+		MigrationVariableName: flag.GetString(ctx, "migration-variable-name"),
 	}
 
 	pgAppFull, err := client.GetApp(ctx, pgAppName)
@@ -193,6 +219,10 @@ func nomadAttachCluster(ctx context.Context, pgApp *api.AppCompact, params Attac
 		return err
 	}
 
+	if params.LimitedGrants {
+		return fmt.Errorf("limited grants are only supported by clusters running on machines")
+	}
+
 	agentclient, err := agent.Establish(ctx, client)
 	if err != nil {
 		return errors.Wrap(err, "can't establish agent")
@@ -260,11 +290,18 @@ func runAttachCluster(ctx context.Context, leaderIP string, params AttachParams,
 	)
 
 	if dbName == "" {
-		dbName = appName
+		dbName = environmentName(appName, params.Environment)
 	}
 
 	if dbUser == "" {
-		dbUser = appName
+		dbUser = environmentName(appName, params.Environment)
+	}
+
+	if params.LimitedGrants {
+		superuser = false
+		if params.MigrationVariableName == "" {
+			params.MigrationVariableName = "MIGRATION_DATABASE_URL"
+		}
 	}
 
 	dbUser = strings.ToLower(strings.ReplaceAll(dbUser, "-", "_"))
@@ -296,6 +333,9 @@ func runAttachCluster(ctx context.Context, leaderIP string, params AttachParams,
 		if secret.Name == *input.VariableName {
 			return fmt.Errorf("consumer app %q already contains a secret named %s", input.AppID, *input.VariableName)
 		}
+		if params.LimitedGrants && secret.Name == params.MigrationVariableName {
+			return fmt.Errorf("consumer app %q already contains a secret named %s", input.AppID, params.MigrationVariableName)
+		}
 	}
 
 	// Check to see if database exists
@@ -324,6 +364,17 @@ func runAttachCluster(ctx context.Context, leaderIP string, params AttachParams,
 		return fmt.Errorf("database user %q already exists. Please specify a new database user via --database-user", *input.DatabaseUser)
 	}
 
+	migrationUser := migrationUserName(*input.DatabaseUser)
+	if params.LimitedGrants {
+		exists, err := pgclient.UserExists(ctx, migrationUser)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return fmt.Errorf("database user %q already exists. Please specify a new database user via --database-user", migrationUser)
+		}
+	}
+
 	fmt.Fprintln(io.Out, "Registering attachment")
 
 	// Create attachment
@@ -358,18 +409,29 @@ func runAttachCluster(ctx context.Context, leaderIP string, params AttachParams,
 		return fmt.Errorf("failed executing create-user: %w", err)
 	}
 
-	connectionString := fmt.Sprintf(
-		"postgres://%s:%s@top2.nearest.of.%s.internal:5432/%s?sslmode=disable",
-		*input.DatabaseUser, pwd, input.PostgresClusterAppID, *input.DatabaseName,
-	)
-	if flycast != nil {
-		connectionString = fmt.Sprintf(
-			"postgres://%s:%s@%s.flycast:5432/%s?sslmode=disable",
-			*input.DatabaseUser, pwd, input.PostgresClusterAppID, *input.DatabaseName,
-		)
-	}
 	s := map[string]string{}
-	s[*input.VariableName] = connectionString
+	s[*input.VariableName] = attachConnectionString(*input.DatabaseUser, pwd, input.PostgresClusterAppID, *input.DatabaseName, flycast)
+
+	if params.LimitedGrants {
+		migrationPwd, err := helpers.RandString(15)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(io.Out, "Creating migration user")
+
+		if err := pgclient.CreateUser(ctx, migrationUser, migrationPwd, false); err != nil {
+			return fmt.Errorf("failed executing create-user: %w", err)
+		}
+
+		fmt.Fprintln(io.Out, "Limiting grants")
+
+		if err := grantLimitedAccess(ctx, params.PgAppName, *input.DatabaseName, *input.DatabaseUser, migrationUser); err != nil {
+			return err
+		}
+
+		s[params.MigrationVariableName] = attachConnectionString(migrationUser, migrationPwd, input.PostgresClusterAppID, *input.DatabaseName, flycast)
+	}
 
 	_, err = client.SetSecrets(ctx, input.AppID, s)
 	if err != nil {
@@ -377,7 +439,93 @@ func runAttachCluster(ctx context.Context, leaderIP string, params AttachParams,
 	}
 
 	fmt.Fprintf(io.Out, "\nPostgres cluster %s is now attached to %s\n", input.PostgresClusterAppID, input.AppID)
-	fmt.Fprintf(io.Out, "The following secret was added to %s:\n  %s=%s\n", input.AppID, *input.VariableName, connectionString)
+	fmt.Fprintf(io.Out, "The following secrets were added to %s:\n", input.AppID)
+	for _, name := range []string{*input.VariableName, params.MigrationVariableName} {
+		if value, ok := s[name]; ok {
+			fmt.Fprintf(io.Out, "  %s=%s\n", name, value)
+		}
+	}
 
 	return nil
 }
+
+func attachConnectionString(user, password, pgAppName, database string, flycast *string) string {
+	if flycast != nil {
+		return fmt.Sprintf("postgres://%s:%s@%s.flycast:5432/%s?sslmode=disable", user, password, pgAppName, database)
+	}
+	return fmt.Sprintf("postgres://%s:%s@top2.nearest.of.%s.internal:5432/%s?sslmode=disable", user, password, pgAppName, database)
+}
+
+// environmentName is the default name of the database and user of an app in
+// an environment.
+func environmentName(appName, environment string) string {
+	if environment == "" {
+		return appName
+	}
+	return appName + "_" + environment
+}
+
+// migrationUserName is the name of the user making the schema changes of the
+// database of user.
+func migrationUserName(user string) string {
+	return user + "_migrator"
+}
+
+// grantLimitedAccess hands the database over to the migration user, and only
+// lets user read and write the data of the tables migrations create.
+func grantLimitedAccess(ctx context.Context, pgAppName, database, user, migrationUser string) error {
+	pgApp, err := client.FromContext(ctx).API().GetAppCompact(ctx, pgAppName)
+	if err != nil {
+		return fmt.Errorf("failed retrieving postgres app %s: %w", pgAppName, err)
+	}
+
+	cluster, _, err := resolvePGCluster(ctx, pgApp)
+	if err != nil {
+		return err
+	}
+
+	cleanup, err := cluster.createTemporaryUser(grantsUser)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	cmd := fmt.Sprintf("psql %s -v ON_ERROR_STOP=1", cluster.uri(database))
+	if err := cluster.exec(cmd, strings.NewReader(limitedGrantsSQL(database, user, migrationUser)), io.Discard); err != nil {
+		return fmt.Errorf("failed limiting the grants of %s: %w", user, err)
+	}
+
+	return nil
+}
+
+// grantsUser is the temporary superuser granting limited access.
+const grantsUser = "flyctl_grants"
+
+// limitedGrantsSQL makes migrationUser the owner of database and its public
+// schema, and grants user DML on the tables and sequences of the schema,
+// including those migrationUser creates later.
+func limitedGrantsSQL(database, user, migrationUser string) string {
+	var (
+		db        = quoteIdent(database)
+		app       = quoteIdent(user)
+		migration = quoteIdent(migrationUser)
+	)
+
+	return strings.Join([]string{
+		fmt.Sprintf("ALTER DATABASE %s OWNER TO %s;", db, migration),
+		fmt.Sprintf("REVOKE ALL ON DATABASE %s FROM PUBLIC;", db),
+		fmt.Sprintf("GRANT CONNECT, TEMPORARY ON DATABASE %s TO %s;", db, app),
+		fmt.Sprintf("ALTER SCHEMA public OWNER TO %s;", migration),
+		"REVOKE CREATE ON SCHEMA public FROM PUBLIC;",
+		fmt.Sprintf("GRANT USAGE ON SCHEMA public TO %s;", app),
+		fmt.Sprintf("GRANT SELECT, INSERT, UPDATE, DELETE ON ALL TABLES IN SCHEMA public TO %s;", app),
+		fmt.Sprintf("GRANT USAGE, SELECT ON ALL SEQUENCES IN SCHEMA public TO %s;", app),
+		fmt.Sprintf("ALTER DEFAULT PRIVILEGES FOR ROLE %s IN SCHEMA public GRANT SELECT, INSERT, UPDATE, DELETE ON TABLES TO %s;", migration, app),
+		fmt.Sprintf("ALTER DEFAULT PRIVILEGES FOR ROLE %s IN SCHEMA public GRANT USAGE, SELECT ON SEQUENCES TO %s;", migration, app),
+	}, "\n") + "\n"
+}
+
+// quoteIdent quotes a Postgres identifier.
+func quoteIdent(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
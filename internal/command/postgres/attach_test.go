@@ -0,0 +1,32 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnvironmentName(t *testing.T) {
+	assert.Equal(t, "my-app", environmentName("my-app", ""))
+	assert.Equal(t, "my-app_staging", environmentName("my-app", "staging"))
+	assert.Equal(t, "my_app_migrator", migrationUserName("my_app"))
+}
+
+func TestLimitedGrantsSQL(t *testing.T) {
+	sql := limitedGrantsSQL("my_app", "my_app", "my_app_migrator")
+
+	assert.Contains(t, sql, `ALTER DATABASE "my_app" OWNER TO "my_app_migrator";`)
+	assert.Contains(t, sql, `GRANT SELECT, INSERT, UPDATE, DELETE ON ALL TABLES IN SCHEMA public TO "my_app";`)
+	assert.Contains(t, sql, `ALTER DEFAULT PRIVILEGES FOR ROLE "my_app_migrator" IN SCHEMA public GRANT SELECT, INSERT, UPDATE, DELETE ON TABLES TO "my_app";`)
+	assert.NotContains(t, sql, `GRANT CREATE`)
+}
+
+func TestQuoteIdent(t *testing.T) {
+	assert.Equal(t, `"weird""name"`, quoteIdent(`weird"name`))
+}
+
+func TestAttachConnectionString(t *testing.T) {
+	flycast := "fdaa::3"
+	assert.Equal(t, "postgres://app:pw@db.flycast:5432/app?sslmode=disable", attachConnectionString("app", "pw", "db", "app", &flycast))
+	assert.Equal(t, "postgres://app:pw@top2.nearest.of.db.internal:5432/app?sslmode=disable", attachConnectionString("app", "pw", "db", "app", nil))
+}
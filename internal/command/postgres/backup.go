@@ -0,0 +1,218 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flypg"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+// backupUser is the temporary superuser dumping and restoring the databases.
+const backupUser = "flyctl_backup"
+
+func newBackup() *cobra.Command {
+	const (
+		short = "Back up a database of a Postgres cluster"
+		long  = short + `
+
+Takes an on-demand logical backup of a database with pg_dump, run on the
+leader of the cluster, and streams it to a local file or, with --s3-bucket, to
+an S3-compatible bucket under the name of the app. Unlike volume snapshots,
+these backups can be restored to any cluster with fly pg backup restore.
+
+The credentials of the bucket are read from the AWS_ACCESS_KEY_ID,
+AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN environment variables.
+`
+		usage = "backup"
+	)
+
+	cmd := command.New(usage, short, long, runBackup,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		backupS3Flags,
+		flag.String{
+			Name:        "database",
+			Description: "The database to back up, defaults to the only database of the cluster",
+		},
+		flag.String{
+			Name:        "output",
+			Shorthand:   "o",
+			Description: "The name of the backup, defaults to <app>-<database>-<timestamp>.dump",
+		},
+	)
+
+	cmd.AddCommand(
+		newBackupList(),
+		newBackupRestore(),
+	)
+
+	return cmd
+}
+
+func runBackup(ctx context.Context) error {
+	var (
+		io      = iostreams.FromContext(ctx)
+		appName = appconfig.NameFromContext(ctx)
+	)
+
+	bucket, err := s3BucketFromFlags(ctx)
+	if err != nil {
+		return err
+	}
+
+	cluster, err := resolveBackupCluster(ctx, appName)
+	if err != nil {
+		return err
+	}
+
+	cleanup, err := cluster.createTemporaryUser(backupUser)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	database, err := backupDatabase(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
+	name := flag.GetString(ctx, "output")
+	if name == "" {
+		name = backupName(appName, database, time.Now())
+	}
+
+	if bucket == nil {
+		fmt.Fprintf(io.Out, "Backing up database %s of %s to %s\n", database, appName, name)
+		return dumpToFile(cluster, database, name)
+	}
+
+	// the size of the dump must be known before uploading it
+	tmp, err := os.CreateTemp("", "flyctl-pg-backup-*.dump")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	key := backupKey(appName, name)
+	fmt.Fprintf(io.Out, "Backing up database %s of %s to %s/%s\n", database, appName, bucket, key)
+
+	if err := cluster.exec(pgDumpCommand(cluster, database), nil, tmp); err != nil {
+		return fmt.Errorf("failed dumping database %s: %w", database, err)
+	}
+
+	info, err := tmp.Stat()
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Seek(0, 0); err != nil {
+		return err
+	}
+	if err := bucket.Put(ctx, key, tmp, info.Size()); err != nil {
+		return fmt.Errorf("failed uploading backup: %w", err)
+	}
+
+	fmt.Fprintf(io.Out, "Backup complete, %d bytes\n", info.Size())
+	return nil
+}
+
+// resolveBackupCluster returns the cluster of the app, which must be a Postgres
+// cluster running on machines.
+func resolveBackupCluster(ctx context.Context, appName string) (*pgCluster, error) {
+	app, err := client.FromContext(ctx).API().GetAppCompact(ctx, appName)
+	if err != nil {
+		return nil, fmt.Errorf("failed retrieving app %s: %w", appName, err)
+	}
+	if !app.IsPostgresApp() || app.PlatformVersion != "machines" {
+		return nil, fmt.Errorf("app %s is not a Postgres cluster running on machines", appName)
+	}
+
+	cluster, _, err := resolvePGCluster(ctx, app)
+	return cluster, err
+}
+
+// backupDatabase returns the database given with --database, or else the only
+// database of the cluster which isn't managed by the cluster itself.
+func backupDatabase(ctx context.Context, c *pgCluster) (string, error) {
+	if database := flag.GetString(ctx, "database"); database != "" {
+		return database, nil
+	}
+
+	databases, err := c.client.ListDatabases(c.ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed listing databases of %s: %w", c.app.Name, err)
+	}
+
+	return soleDatabase(c.app.Name, databases)
+}
+
+func soleDatabase(appName string, databases []flypg.PostgresDatabase) (string, error) {
+	var names []string
+	for _, db := range databases {
+		if !slices.Contains(upgradeSkippedDatabases, db.Name) {
+			names = append(names, db.Name)
+		}
+	}
+
+	switch len(names) {
+	case 0:
+		return "", fmt.Errorf("cluster %s has no database to back up", appName)
+	case 1:
+		return names[0], nil
+	default:
+		return "", fmt.Errorf("cluster %s has several databases, select one with --database: %v", appName, names)
+	}
+}
+
+func dumpToFile(c *pgCluster, database, name string) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+
+	err = c.exec(pgDumpCommand(c, database), nil, f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		// don't leave a truncated dump behind
+		os.Remove(name)
+		return fmt.Errorf("failed dumping database %s: %w", database, err)
+	}
+
+	fmt.Fprintf(iostreams.FromContext(c.ctx).Out, "Backup complete\n")
+	return nil
+}
+
+func pgDumpCommand(c *pgCluster, database string) string {
+	return fmt.Sprintf("pg_dump --format=custom --dbname=%s", c.uri(database))
+}
+
+// backupName returns the default name of a backup, sorting chronologically.
+func backupName(appName, database string, t time.Time) string {
+	return fmt.Sprintf("%s-%s-%s.dump", appName, database, t.UTC().Format("20060102T150405Z"))
+}
+
+// backupKey returns the key a backup is stored under in a bucket.
+func backupKey(appName, name string) string {
+	return path.Join(appName, filepath.Base(name))
+}
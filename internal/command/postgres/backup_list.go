@@ -0,0 +1,131 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/format"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+type backupFile struct {
+	Name         string    `json:"name"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+func newBackupList() *cobra.Command {
+	const (
+		short = "List the backups of a Postgres cluster"
+		long  = short + `
+
+Lists the backups taken with fly pg backup, either the local files of the
+cluster in --dir or the objects stored under its name in --s3-bucket.
+`
+		usage = "list"
+	)
+
+	cmd := command.New(usage, short, long, runBackupList,
+		command.RequireAppName,
+	)
+
+	cmd.Args = cobra.NoArgs
+	cmd.Aliases = []string{"ls"}
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.JSONOutput(),
+		backupS3Flags,
+		flag.String{
+			Name:        "dir",
+			Description: "The directory of the local backups",
+			Default:     ".",
+		},
+	)
+
+	return cmd
+}
+
+func runBackupList(ctx context.Context) error {
+	var (
+		io      = iostreams.FromContext(ctx)
+		cfg     = config.FromContext(ctx)
+		appName = appconfig.NameFromContext(ctx)
+	)
+
+	backups, err := listBackups(ctx, appName)
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].LastModified.After(backups[j].LastModified)
+	})
+
+	if cfg.JSONOutput {
+		return render.Output(ctx, io.Out, backups)
+	}
+
+	if len(backups) == 0 {
+		fmt.Fprintf(io.Out, "No backups found for %s\n", appName)
+		return nil
+	}
+
+	rows := make([][]string, 0, len(backups))
+	for _, b := range backups {
+		rows = append(rows, []string{
+			b.Name,
+			strconv.FormatInt(b.Size, 10),
+			format.RelativeTime(b.LastModified),
+		})
+	}
+
+	return render.Table(io.Out, "", rows, "Name", "Size", "Created")
+}
+
+func listBackups(ctx context.Context, appName string) ([]backupFile, error) {
+	bucket, err := s3BucketFromFlags(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []backupFile
+
+	if bucket != nil {
+		objects, err := bucket.List(ctx, appName+"/")
+		if err != nil {
+			return nil, fmt.Errorf("failed listing backups in %s: %w", bucket, err)
+		}
+		for _, o := range objects {
+			backups = append(backups, backupFile{Name: o.Key, Size: o.Size, LastModified: o.LastModified})
+		}
+		return backups, nil
+	}
+
+	matches, err := filepath.Glob(filepath.Join(flag.GetString(ctx, "dir"), appName+"-*.dump"))
+	if err != nil {
+		return nil, err
+	}
+	for _, match := range matches {
+		info, err := os.Stat(match)
+		if err != nil {
+			return nil, err
+		}
+		backups = append(backups, backupFile{Name: match, Size: info.Size(), LastModified: info.ModTime()})
+	}
+
+	return backups, nil
+}
@@ -0,0 +1,127 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/internal/s3"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newBackupRestore() *cobra.Command {
+	const (
+		short = "Restore a backup to a Postgres cluster"
+		long  = short + `
+
+Restores a backup taken with fly pg backup into a database of the cluster with
+pg_restore. The backup is a local file or, with --s3-bucket, the key of an
+object of the bucket as shown by fly pg backup list. The objects of the backup
+already in the database are dropped and recreated.
+`
+		usage = "restore <backup>"
+	)
+
+	cmd := command.New(usage, short, long, runBackupRestore,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	cmd.Args = cobra.ExactArgs(1)
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.Yes(),
+		backupS3Flags,
+		flag.String{
+			Name:        "database",
+			Description: "The database to restore to, defaults to the only database of the cluster",
+		},
+	)
+
+	return cmd
+}
+
+func runBackupRestore(ctx context.Context) error {
+	var (
+		io      = iostreams.FromContext(ctx)
+		appName = appconfig.NameFromContext(ctx)
+		backup  = flag.FirstArg(ctx)
+	)
+
+	bucket, err := s3BucketFromFlags(ctx)
+	if err != nil {
+		return err
+	}
+
+	cluster, err := resolveBackupCluster(ctx, appName)
+	if err != nil {
+		return err
+	}
+
+	cleanup, err := cluster.createTemporaryUser(backupUser)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	database, err := backupDatabase(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
+	if !flag.GetYes(ctx) {
+		switch confirmed, err := prompt.Confirmf(ctx, "Restore %s to database %s of %s? Its existing data will be overwritten.", backup, database, appName); {
+		case err == nil:
+			if !confirmed {
+				return nil
+			}
+		case prompt.IsNonInteractive(err):
+			return prompt.NonInteractiveError("yes flag must be specified when not running interactively")
+		default:
+			return err
+		}
+	}
+
+	dump, err := openBackup(ctx, bucket, appName, backup)
+	if err != nil {
+		return err
+	}
+	defer dump.Close()
+
+	fmt.Fprintf(io.Out, "Restoring %s to database %s of %s\n", backup, database, appName)
+
+	cmd := fmt.Sprintf("pg_restore --clean --if-exists --no-owner --dbname=%s", cluster.uri(database))
+	if err := cluster.exec(cmd, dump, io.Out); err != nil {
+		return fmt.Errorf("failed restoring %s: %w", backup, err)
+	}
+
+	fmt.Fprintf(io.Out, "Restore complete\n")
+	return nil
+}
+
+// openBackup opens the local backup file, or the object of the bucket when one
+// is selected. Bare names are looked up under the name of the app.
+func openBackup(ctx context.Context, bucket *s3.Bucket, appName, backup string) (io.ReadCloser, error) {
+	if bucket == nil {
+		return os.Open(backup)
+	}
+
+	if !strings.Contains(backup, "/") {
+		backup = backupKey(appName, backup)
+	}
+	dump, err := bucket.Get(ctx, backup)
+	if err != nil {
+		return nil, fmt.Errorf("failed downloading backup %s: %w", backup, err)
+	}
+	return dump, nil
+}
@@ -0,0 +1,38 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/s3"
+)
+
+// backupS3Flags select the S3-compatible bucket backups are stored in. The
+// credentials come from the usual AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and
+// AWS_SESSION_TOKEN environment variables.
+var backupS3Flags = flag.Set{
+	flag.String{
+		Name:        "s3-bucket",
+		Description: "Store backups in this S3-compatible bucket instead of local files",
+	},
+	flag.String{
+		Name:        "s3-endpoint",
+		Description: "The endpoint of the S3-compatible service, defaults to AWS S3",
+	},
+	flag.String{
+		Name:        "s3-region",
+		Description: "The region of the bucket",
+		Default:     "us-east-1",
+	},
+}
+
+// s3BucketFromFlags returns the bucket selected with --s3-bucket, or nil when
+// backups are local files.
+func s3BucketFromFlags(ctx context.Context) (*s3.Bucket, error) {
+	name := flag.GetString(ctx, "s3-bucket")
+	if name == "" {
+		return nil, nil
+	}
+
+	return s3.New(name, flag.GetString(ctx, "s3-region"), flag.GetString(ctx, "s3-endpoint"))
+}
@@ -0,0 +1,27 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/superfly/flyctl/flypg"
+)
+
+func TestSoleDatabase(t *testing.T) {
+	db, err := soleDatabase("my-db", []flypg.PostgresDatabase{{Name: "postgres"}, {Name: "my_app"}, {Name: "repmgr"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "my_app", db)
+
+	_, err = soleDatabase("my-db", []flypg.PostgresDatabase{{Name: "postgres"}})
+	assert.Error(t, err)
+
+	_, err = soleDatabase("my-db", []flypg.PostgresDatabase{{Name: "one"}, {Name: "two"}})
+	assert.ErrorContains(t, err, "--database")
+}
+
+func TestBackupName(t *testing.T) {
+	at := time.Date(2023, 6, 1, 12, 30, 5, 0, time.UTC)
+	assert.Equal(t, "my-db-my_app-20230601T123005Z.dump", backupName("my-db", "my_app", at))
+	assert.Equal(t, "my-db/my-db-my_app-20230601T123005Z.dump", backupKey("my-db", "backups/my-db-my_app-20230601T123005Z.dump"))
+}
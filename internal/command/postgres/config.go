@@ -28,6 +28,8 @@ func newConfig() (cmd *cobra.Command) {
 	cmd = command.New("config", short, long, nil)
 
 	cmd.AddCommand(
+		newConfigGet(),
+		newConfigSet(),
 		newConfigShow(),
 		newConfigUpdate(),
 	)
@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+)
+
+func newConfigGet() (cmd *cobra.Command) {
+	const (
+		short = "Get Postgres settings"
+		long  = short + `
+
+Shows the current value of any postgresql.conf setting of the cluster, such as
+shared_buffers or max_connections, along with whether changing it requires a
+restart of the cluster.
+`
+		usage = "get <name>..."
+	)
+
+	cmd = command.New(usage, short, long, runConfigGet,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	cmd.Args = cobra.MinimumNArgs(1)
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+	)
+
+	return
+}
+
+func runConfigGet(ctx context.Context) error {
+	settings := make([]string, 0, len(flag.Args(ctx)))
+	for _, name := range flag.Args(ctx) {
+		settings = append(settings, settingName(name))
+	}
+
+	return showConfig(ctx, settings)
+}
+
+// settingName returns the Postgres name of a setting given either as
+// shared_buffers or shared-buffers.
+func settingName(name string) string {
+	return strings.ReplaceAll(strings.ToLower(name), "-", "_")
+}
@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/internal/cmdutil"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+)
+
+func newConfigSet() (cmd *cobra.Command) {
+	const (
+		short = "Set Postgres settings"
+		long  = short + `
+
+Updates any postgresql.conf setting of the cluster, such as shared_buffers,
+max_connections or wal_level, given as NAME=VALUE pairs. Values are checked
+against the accepted ranges first, and the settings which only take effect
+after a restart of the cluster are shown. Pass --restart to perform the
+rolling restart right away.
+`
+		usage = "set NAME=VALUE..."
+	)
+
+	cmd = command.New(usage, short, long, runConfigSet,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	cmd.Args = cobra.MinimumNArgs(1)
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.Yes(),
+		flag.Bool{
+			Name:        "restart",
+			Description: "Restart the cluster without asking when a setting requires it",
+		},
+		flag.Bool{
+			Name:        "force",
+			Description: "Skips pg-setting value verification.",
+		},
+	)
+
+	return
+}
+
+func runConfigSet(ctx context.Context) error {
+	pairs, err := cmdutil.ParseKVStringsToMap(flag.Args(ctx))
+	if err != nil {
+		return fmt.Errorf("could not parse settings: %w", err)
+	}
+
+	changes := make(map[string]string, len(pairs))
+	for name, value := range pairs {
+		changes[settingName(name)] = value
+	}
+
+	return updateConfig(ctx, changes)
+}
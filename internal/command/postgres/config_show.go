@@ -44,6 +44,16 @@ func newConfigShow() (cmd *cobra.Command) {
 }
 
 func runConfigShow(ctx context.Context) error {
+	settings := make([]string, 0, len(pgSettings))
+	for _, k := range pgSettings {
+		settings = append(settings, k)
+	}
+
+	return showConfig(ctx, settings)
+}
+
+// showConfig renders the named settings of the cluster of the app.
+func showConfig(ctx context.Context, settings []string) error {
 	var (
 		client  = client.FromContext(ctx).API()
 		appName = appconfig.NameFromContext(ctx)
@@ -65,15 +75,15 @@ func runConfigShow(ctx context.Context) error {
 
 	switch app.PlatformVersion {
 	case "machines":
-		return runMachineConfigShow(ctx, app)
+		return runMachineConfigShow(ctx, app, settings)
 	case "nomad":
-		return runNomadConfigShow(ctx, app)
+		return runNomadConfigShow(ctx, app, settings)
 	default:
 		return fmt.Errorf("unknown platform version")
 	}
 }
 
-func runMachineConfigShow(ctx context.Context, app *api.AppCompact) (err error) {
+func runMachineConfigShow(ctx context.Context, app *api.AppCompact, settings []string) (err error) {
 	var (
 		MinPostgresHaVersion         = "0.0.19"
 		MinPostgresStandaloneVersion = "0.0.7"
@@ -104,10 +114,10 @@ func runMachineConfigShow(ctx context.Context, app *api.AppCompact) (err error)
 		manager = flypg.ReplicationManager
 	}
 
-	return showSettings(ctx, app, manager, leader.PrivateIP)
+	return showSettings(ctx, app, manager, leader.PrivateIP, settings)
 }
 
-func runNomadConfigShow(ctx context.Context, app *api.AppCompact) (err error) {
+func runNomadConfigShow(ctx context.Context, app *api.AppCompact, settings []string) (err error) {
 	var (
 		MinPostgresHaVersion = "0.0.19"
 		client               = client.FromContext(ctx).API()
@@ -136,10 +146,10 @@ func runNomadConfigShow(ctx context.Context, app *api.AppCompact) (err error) {
 		return err
 	}
 
-	return showSettings(ctx, app, flypg.StolonManager, leaderIP)
+	return showSettings(ctx, app, flypg.StolonManager, leaderIP, settings)
 }
 
-func showSettings(ctx context.Context, app *api.AppCompact, manager string, leaderIP string) error {
+func showSettings(ctx context.Context, app *api.AppCompact, manager string, leaderIP string, settings []string) error {
 	var (
 		io       = iostreams.FromContext(ctx)
 		colorize = io.ColorScheme()
@@ -148,11 +158,6 @@ func showSettings(ctx context.Context, app *api.AppCompact, manager string, lead
 
 	pgclient := flypg.NewFromInstance(leaderIP, dialer)
 
-	var settings []string
-	for _, k := range pgSettings {
-		settings = append(settings, k)
-	}
-
 	res, err := pgclient.ViewSettings(ctx, settings, manager)
 	if err != nil {
 		return err
@@ -198,10 +203,11 @@ func showSettings(ctx context.Context, app *api.AppCompact, manager string, lead
 			value,
 			setting.Unit,
 			desc,
+			fmt.Sprint(setting.Context == "postmaster"),
 			restart,
 		})
 	}
-	_ = render.Table(io.Out, "", rows, "Name", "Value", "Unit", "Description", "Pending Restart")
+	_ = render.Table(io.Out, "", rows, "Name", "Value", "Unit", "Description", "Restart Required", "Pending Restart")
 
 	if pendingRestart {
 		fmt.Fprintln(io.Out, colorize.Yellow("Some changes are awaiting a restart!"))
@@ -0,0 +1,24 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/superfly/flyctl/flypg"
+)
+
+func TestSettingName(t *testing.T) {
+	assert.Equal(t, "shared_buffers", settingName("shared-buffers"))
+	assert.Equal(t, "max_connections", settingName("MAX_CONNECTIONS"))
+}
+
+func TestUnknownSettings(t *testing.T) {
+	settings := &flypg.PGSettings{Settings: []flypg.PGSetting{{Name: "max_connections"}, {Name: "shared_buffers"}}}
+
+	assert.Empty(t, unknownSettings(map[string]string{"max_connections": "200"}, settings))
+	assert.Equal(t, []string{"max_conections", "shared_bufers"}, unknownSettings(map[string]string{
+		"max_conections": "200",
+		"shared_bufers":  "1GB",
+		"shared_buffers": "1GB",
+	}, settings))
+}
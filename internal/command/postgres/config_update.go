@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -21,6 +22,7 @@ import (
 	"github.com/superfly/flyctl/internal/prompt"
 	"github.com/superfly/flyctl/internal/render"
 	"github.com/superfly/flyctl/iostreams"
+	"golang.org/x/exp/slices"
 )
 
 func newConfigUpdate() (cmd *cobra.Command) {
@@ -82,6 +84,21 @@ func newConfigUpdate() (cmd *cobra.Command) {
 }
 
 func runConfigUpdate(ctx context.Context) error {
+	// Identify requested configuration changes.
+	changes := map[string]string{}
+	for key := range pgSettings {
+		val := flag.GetString(ctx, key)
+		if val != "" {
+			changes[pgSettings[key]] = val
+		}
+	}
+
+	return updateConfig(ctx, changes)
+}
+
+// updateConfig applies the changes to the settings of the cluster of the app,
+// restarting it when required.
+func updateConfig(ctx context.Context, changes map[string]string) error {
 	var (
 		client  = client.FromContext(ctx).API()
 		appName = appconfig.NameFromContext(ctx)
@@ -103,15 +120,15 @@ func runConfigUpdate(ctx context.Context) error {
 
 	switch app.PlatformVersion {
 	case "machines":
-		return runMachineConfigUpdate(ctx, app)
+		return runMachineConfigUpdate(ctx, app, changes)
 	case "nomad":
-		return runNomadConfigUpdate(ctx, app)
+		return runNomadConfigUpdate(ctx, app, changes)
 	default:
 		return fmt.Errorf("unknown platform version")
 	}
 }
 
-func runMachineConfigUpdate(ctx context.Context, app *api.AppCompact) error {
+func runMachineConfigUpdate(ctx context.Context, app *api.AppCompact, changes map[string]string) error {
 	var (
 		io          = iostreams.FromContext(ctx)
 		colorize    = io.ColorScheme()
@@ -146,12 +163,12 @@ func runMachineConfigUpdate(ctx context.Context, app *api.AppCompact) error {
 
 	switch manager {
 	case flypg.ReplicationManager:
-		requiresRestart, err = updateFlexConfig(ctx, app, leader.PrivateIP)
+		requiresRestart, err = updateFlexConfig(ctx, app, leader.PrivateIP, changes)
 		if err != nil {
 			return err
 		}
 	default:
-		requiresRestart, err = updateStolonConfig(ctx, app, leader.PrivateIP)
+		requiresRestart, err = updateStolonConfig(ctx, app, leader.PrivateIP, changes)
 		if err != nil {
 			return err
 		}
@@ -159,7 +176,7 @@ func runMachineConfigUpdate(ctx context.Context, app *api.AppCompact) error {
 	}
 
 	if requiresRestart {
-		if !autoConfirm {
+		if !autoConfirm && !flag.GetBool(ctx, "restart") {
 			fmt.Fprintln(io.Out, colorize.Yellow("Please note that some of your changes will require a cluster restart before they will be applied."))
 
 			switch confirmed, err := prompt.Confirm(ctx, "Restart cluster now?"); {
@@ -184,10 +201,10 @@ func runMachineConfigUpdate(ctx context.Context, app *api.AppCompact) error {
 	return nil
 }
 
-func updateStolonConfig(ctx context.Context, app *api.AppCompact, leaderIP string) (bool, error) {
+func updateStolonConfig(ctx context.Context, app *api.AppCompact, leaderIP string, changes map[string]string) (bool, error) {
 	io := iostreams.FromContext(ctx)
 
-	restartRequired, changes, err := resolveConfigChanges(ctx, app, flypg.StolonManager, leaderIP)
+	restartRequired, changes, err := resolveConfigChanges(ctx, app, flypg.StolonManager, leaderIP, changes)
 	if err != nil {
 		return false, err
 	}
@@ -207,13 +224,13 @@ func updateStolonConfig(ctx context.Context, app *api.AppCompact, leaderIP strin
 	return restartRequired, nil
 }
 
-func updateFlexConfig(ctx context.Context, app *api.AppCompact, leaderIP string) (bool, error) {
+func updateFlexConfig(ctx context.Context, app *api.AppCompact, leaderIP string, changes map[string]string) (bool, error) {
 	var (
 		io     = iostreams.FromContext(ctx)
 		dialer = agent.DialerFromContext(ctx)
 	)
 
-	restartRequired, changes, err := resolveConfigChanges(ctx, app, flypg.ReplicationManager, leaderIP)
+	restartRequired, changes, err := resolveConfigChanges(ctx, app, flypg.ReplicationManager, leaderIP, changes)
 	if err != nil {
 		return false, err
 	}
@@ -246,7 +263,7 @@ func updateFlexConfig(ctx context.Context, app *api.AppCompact, leaderIP string)
 	return restartRequired, nil
 }
 
-func resolveConfigChanges(ctx context.Context, app *api.AppCompact, manager string, leaderIP string) (bool, map[string]string, error) {
+func resolveConfigChanges(ctx context.Context, app *api.AppCompact, manager string, leaderIP string, changes map[string]string) (bool, map[string]string, error) {
 	var (
 		io     = iostreams.FromContext(ctx)
 		dialer = agent.DialerFromContext(ctx)
@@ -255,15 +272,9 @@ func resolveConfigChanges(ctx context.Context, app *api.AppCompact, manager stri
 		autoConfirm = flag.GetBool(ctx, "yes")
 	)
 
-	// Identify requested configuration changes.
-	changes := map[string]string{}
-	keys := []string{}
-	for key := range pgSettings {
-		val := flag.GetString(ctx, key)
-		if val != "" {
-			changes[pgSettings[key]] = val
-			keys = append(keys, pgSettings[key])
-		}
+	keys := make([]string, 0, len(changes))
+	for key := range changes {
+		keys = append(keys, key)
 	}
 
 	restartRequired := false
@@ -279,6 +290,9 @@ func resolveConfigChanges(ctx context.Context, app *api.AppCompact, manager stri
 		if len(changes) == 0 {
 			return false, nil, fmt.Errorf("no changes were specified")
 		}
+		if unknown := unknownSettings(changes, settings); len(unknown) > 0 {
+			return false, nil, fmt.Errorf("unknown settings: %s", strings.Join(unknown, ", "))
+		}
 
 		changelog, err := resolveChangeLog(ctx, changes, settings)
 		if err != nil {
@@ -347,6 +361,19 @@ func resolveChangeLog(ctx context.Context, changes map[string]string, settings *
 	return diff.Diff(oValues, changes)
 }
 
+// unknownSettings returns the names of the changed settings Postgres doesn't
+// know about.
+func unknownSettings(changes map[string]string, settings *flypg.PGSettings) []string {
+	var unknown []string
+	for name := range changes {
+		if !slices.ContainsFunc(settings.Settings, func(s flypg.PGSetting) bool { return s.Name == name }) {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
 func isRestartRequired(pgSettings *flypg.PGSettings, name string) bool {
 	for _, s := range pgSettings.Settings {
 		if s.Name == name {
@@ -359,7 +386,7 @@ func isRestartRequired(pgSettings *flypg.PGSettings, name string) bool {
 	return false
 }
 
-func runNomadConfigUpdate(ctx context.Context, app *api.AppCompact) error {
+func runNomadConfigUpdate(ctx context.Context, app *api.AppCompact, changes map[string]string) error {
 	var (
 		client      = client.FromContext(ctx).API()
 		io          = iostreams.FromContext(ctx)
@@ -391,13 +418,13 @@ func runNomadConfigUpdate(ctx context.Context, app *api.AppCompact) error {
 		return err
 	}
 
-	requiresRestart, err := updateStolonConfig(ctx, app, leaderIP)
+	requiresRestart, err := updateStolonConfig(ctx, app, leaderIP, changes)
 	if err != nil {
 		return err
 	}
 
 	if requiresRestart {
-		if !autoConfirm {
+		if !autoConfirm && !flag.GetBool(ctx, "restart") {
 			fmt.Fprintln(io.Out, colorize.Yellow("Please note that some of your changes will require a cluster restart before they will be applied."))
 
 			switch confirmed, err := prompt.Confirm(ctx, "Restart cluster now?"); {
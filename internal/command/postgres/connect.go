@@ -3,7 +3,11 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"net"
+	"net/url"
 	"os"
+	"os/exec"
+	"strconv"
 
 	"github.com/docker/docker/pkg/ioutils"
 	"github.com/mattn/go-colorable"
@@ -17,12 +21,19 @@ import (
 	"github.com/superfly/flyctl/internal/command/apps"
 	"github.com/superfly/flyctl/internal/command/ssh"
 	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/iostreams"
+	"github.com/superfly/flyctl/proxy"
 )
 
 func newConnect() *cobra.Command {
 	const (
 		short = "Connect to the Postgres console"
-		long  = short + "\n"
+		long  = short + `
+
+With --proxy, opens a local tunnel to the leader instead and prints a
+localhost connection URL, ready to paste in GUI clients such as TablePlus.
+With --client, also launches a local client such as psql or pgcli with it.
+`
 
 		usage = "connect"
 	)
@@ -52,6 +63,19 @@ func newConnect() *cobra.Command {
 			Shorthand:   "p",
 			Description: "The postgres user password",
 		},
+		flag.Bool{
+			Name:        "proxy",
+			Description: "Open a local tunnel to the leader and print its connection URL instead of connecting from inside the machine",
+		},
+		flag.String{
+			Name:        "local-port",
+			Description: "The local port of the tunnel",
+			Default:     "15432",
+		},
+		flag.String{
+			Name:        "client",
+			Description: "The local client to launch with the connection URL of the tunnel, e.g. psql or pgcli. Implies --proxy",
+		},
 	)
 
 	return cmd
@@ -113,6 +137,11 @@ func runMachineConnect(ctx context.Context, app *api.AppCompact) error {
 	if err != nil {
 		return err
 	}
+
+	if connectThroughProxy(ctx) {
+		return runProxyConnect(ctx, app, leader.PrivateIP)
+	}
+
 	return ssh.SSHConnect(&ssh.SSHParams{
 		Ctx:      ctx,
 		Org:      app.Organization,
@@ -159,6 +188,10 @@ func runNomadConnect(ctx context.Context, app *api.AppCompact) error {
 		return err
 	}
 
+	if connectThroughProxy(ctx) {
+		return runProxyConnect(ctx, app, leaderIP)
+	}
+
 	return ssh.SSHConnect(&ssh.SSHParams{
 		Ctx:      ctx,
 		Org:      app.Organization,
@@ -171,3 +204,75 @@ func runNomadConnect(ctx context.Context, app *api.AppCompact) error {
 		Stderr:   ioutils.NewWriteCloserWrapper(colorable.NewColorableStderr(), func() error { return nil }),
 	}, leaderIP)
 }
+
+func connectThroughProxy(ctx context.Context) bool {
+	return flag.GetBool(ctx, "proxy") || flag.GetString(ctx, "client") != ""
+}
+
+// runProxyConnect opens a local tunnel to Postgres on the leader, bypassing the
+// proxies of the cluster, and prints its connection URL for GUI clients. The
+// client given with --client is launched with the URL, otherwise the tunnel
+// stays open until interrupted.
+func runProxyConnect(ctx context.Context, app *api.AppCompact, leaderIP string) error {
+	var (
+		io       = iostreams.FromContext(ctx)
+		database = flag.GetString(ctx, "database")
+		user     = flag.GetString(ctx, "user")
+		password = flag.GetString(ctx, "password")
+		client   = flag.GetString(ctx, "client")
+	)
+
+	server, err := proxy.NewServer(ctx, &proxy.ConnectParams{
+		AppName:          app.Name,
+		OrganizationSlug: app.Organization.Slug,
+		Dialer:           agent.DialerFromContext(ctx),
+		Ports:            []string{flag.GetString(ctx, "local-port"), "5433"},
+		RemoteHost:       leaderIP,
+	})
+	if err != nil {
+		return err
+	}
+
+	port := server.Listener.Addr().(*net.TCPAddr).Port
+	connStr := localConnectionURL(user, password, port, database)
+
+	fmt.Fprintf(io.Out, "Connection URL: %s\n", connStr)
+
+	if client == "" {
+		fmt.Fprintln(io.Out, "Press Ctrl-C to close the tunnel")
+		return server.ProxyServer(ctx)
+	}
+
+	clientPath, err := exec.LookPath(client)
+	if err != nil {
+		server.Listener.Close()
+		return fmt.Errorf("could not find %s in your $PATH, install it or connect to the URL above with --proxy: %w", client, err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go server.ProxyServer(ctx)
+
+	cmd := exec.CommandContext(ctx, clientPath, connStr)
+	cmd.Stdin = io.In
+	cmd.Stdout = io.Out
+	cmd.Stderr = io.ErrOut
+
+	return cmd.Run()
+}
+
+// localConnectionURL returns the URL of the database through the local tunnel.
+// Clients prompt for the password when it isn't included.
+func localConnectionURL(user, password string, port int, database string) string {
+	u := url.URL{
+		Scheme:   "postgres",
+		User:     url.User(user),
+		Host:     net.JoinHostPort("localhost", strconv.Itoa(port)),
+		Path:     "/" + database,
+		RawQuery: "sslmode=disable",
+	}
+	if password != "" {
+		u.User = url.UserPassword(user, password)
+	}
+	return u.String()
+}
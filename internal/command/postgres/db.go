@@ -167,7 +167,7 @@ func listDBs(ctx context.Context, leaderIP string) error {
 	}
 
 	if cfg.JSONOutput {
-		return render.JSON(io.Out, databases)
+		return render.Output(ctx, io.Out, databases)
 	}
 
 	rows := make([][]string, len(databases))
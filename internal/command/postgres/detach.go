@@ -206,3 +206,77 @@ func detachAppFromPostgres(ctx context.Context, leaderIP string, app *api.AppCom
 
 	return nil
 }
+
+// DropAttachments detaches the app from the Postgres cluster, deleting the
+// users and databases of its attachments, e.g. before destroying an ephemeral
+// app. Only clusters on the machines platform are supported.
+func DropAttachments(ctx context.Context, pgAppName, appName string) error {
+	var (
+		client = client.FromContext(ctx).API()
+		io     = iostreams.FromContext(ctx)
+	)
+
+	pgApp, err := client.GetAppCompact(ctx, pgAppName)
+	if err != nil {
+		return fmt.Errorf("get postgres app: %w", err)
+	}
+	if pgApp.PlatformVersion != "machines" {
+		return fmt.Errorf("dropping databases of postgres clusters on the %s platform isn't supported", pgApp.PlatformVersion)
+	}
+
+	attachments, err := client.ListPostgresClusterAttachments(ctx, appName, pgAppName)
+	if err != nil {
+		return err
+	}
+	if len(attachments) == 0 {
+		return nil
+	}
+
+	ctx, err = apps.BuildContext(ctx, pgApp)
+	if err != nil {
+		return err
+	}
+
+	machines, err := mach.ListActive(ctx)
+	if err != nil {
+		return fmt.Errorf("machines could not be retrieved %w", err)
+	}
+
+	leader, err := pickLeader(ctx, machines)
+	if err != nil {
+		return err
+	}
+
+	pgclient := flypg.NewFromInstance(leader.PrivateIP, agent.DialerFromContext(ctx))
+
+	for _, attachment := range attachments {
+		if exists, err := pgclient.DatabaseExists(ctx, attachment.DatabaseName); err != nil {
+			return err
+		} else if exists {
+			if err := pgclient.DeleteDatabase(ctx, attachment.DatabaseName); err != nil {
+				return fmt.Errorf("error running database-delete: %w", err)
+			}
+		}
+
+		if exists, err := pgclient.UserExists(ctx, attachment.DatabaseUser); err != nil {
+			return err
+		} else if exists {
+			if err := pgclient.DeleteUser(ctx, attachment.DatabaseUser); err != nil {
+				return fmt.Errorf("error running user-delete: %w", err)
+			}
+		}
+
+		input := api.DetachPostgresClusterInput{
+			AppID:                       appName,
+			PostgresClusterId:           pgAppName,
+			PostgresClusterAttachmentId: attachment.ID,
+		}
+		if err := client.DetachPostgresCluster(ctx, input); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(io.Out, "Dropped database %s of %s on %s\n", attachment.DatabaseName, appName, pgAppName)
+	}
+
+	return nil
+}
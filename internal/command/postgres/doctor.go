@@ -0,0 +1,387 @@
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+// doctorUser is the temporary superuser reading the statistics of the cluster.
+const doctorUser = "flyctl_doctor"
+
+// doctorStatsSQL reads the statistics the doctor checks, one "name|value"
+// line per statistic.
+const doctorStatsSQL = `SELECT 'max_connections', setting FROM pg_settings WHERE name = 'max_connections'
+UNION ALL SELECT 'connections', count(*)::text FROM pg_stat_activity WHERE backend_type = 'client backend'
+UNION ALL SELECT 'checkpoints_timed', checkpoints_timed::text FROM pg_stat_bgwriter
+UNION ALL SELECT 'checkpoints_req', checkpoints_req::text FROM pg_stat_bgwriter
+UNION ALL SELECT 'buffers_checkpoint', buffers_checkpoint::text FROM pg_stat_bgwriter
+UNION ALL SELECT 'buffers_clean', buffers_clean::text FROM pg_stat_bgwriter
+UNION ALL SELECT 'buffers_backend', buffers_backend::text FROM pg_stat_bgwriter;
+`
+
+const (
+	doctorOK      = "ok"
+	doctorWarning = "warning"
+	doctorFailed  = "failed"
+)
+
+// doctorCheck is the outcome of one of the checks of fly pg doctor.
+type doctorCheck struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Details string `json:"details"`
+	Advice  string `json:"advice,omitempty"`
+}
+
+func newDoctor() *cobra.Command {
+	const (
+		short = "Diagnose common problems of a Postgres cluster"
+		long  = short + `
+
+Checks the health checks and image versions of the machines of the cluster,
+the disk usage of their volumes, the replication of every replica, connection
+saturation and checkpoint statistics, and suggests how to fix the problems
+found. Exits with an error when a check fails.
+`
+		usage = "doctor"
+	)
+
+	cmd := command.New(usage, short, long, runDoctor,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.JSONOutput(),
+	)
+
+	return cmd
+}
+
+func runDoctor(ctx context.Context) error {
+	var (
+		io       = iostreams.FromContext(ctx)
+		colorize = io.ColorScheme()
+		cfg      = config.FromContext(ctx)
+		appName  = appconfig.NameFromContext(ctx)
+	)
+
+	ctx, app, machines, err := replicaContext(ctx, appName)
+	if err != nil {
+		return err
+	}
+
+	checks := []doctorCheck{healthChecksCheck(machines)}
+	checks = append(checks, imageVersionsCheck(ctx, machines))
+	for _, m := range machines {
+		checks = append(checks, diskUsageCheck(ctx, app, m))
+	}
+	checks = append(checks, clusterStatsChecks(ctx, app, machines)...)
+
+	if cfg.JSONOutput {
+		return render.Output(ctx, io.Out, checks)
+	}
+
+	failed := 0
+	rows := make([][]string, 0, len(checks))
+	for _, c := range checks {
+		status := c.Status
+		switch c.Status {
+		case doctorOK:
+			status = colorize.Green(status)
+		case doctorWarning:
+			status = colorize.Yellow(status)
+		case doctorFailed:
+			failed++
+			status = colorize.Red(status)
+		}
+		rows = append(rows, []string{c.Name, status, c.Details})
+	}
+	_ = render.Table(io.Out, fmt.Sprintf("Diagnostics of %s", appName), rows, "Check", "Status", "Details")
+
+	for _, c := range checks {
+		if c.Advice != "" {
+			fmt.Fprintf(io.Out, "%s %s: %s\n", colorize.Bold("*"), c.Name, c.Advice)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(checks))
+	}
+	return nil
+}
+
+// clusterStatsChecks runs the checks reading the statistics of the leader.
+func clusterStatsChecks(ctx context.Context, app *api.AppCompact, machines []*api.Machine) []doctorCheck {
+	failed := func(err error) []doctorCheck {
+		return []doctorCheck{{
+			Name:    "statistics",
+			Status:  doctorFailed,
+			Details: err.Error(),
+			Advice:  "The statistics of the cluster couldn't be read, check that it has a healthy leader with fly status",
+		}}
+	}
+
+	cluster, _, err := resolvePGCluster(ctx, app)
+	if err != nil {
+		return failed(err)
+	}
+	cleanup, err := cluster.createTemporaryUser(doctorUser)
+	if err != nil {
+		return failed(err)
+	}
+	defer cleanup()
+
+	psql := fmt.Sprintf("psql %s -At", cluster.uri("postgres"))
+
+	var out bytes.Buffer
+	if err := cluster.exec(psql, strings.NewReader(doctorStatsSQL), &out); err != nil {
+		return failed(err)
+	}
+	stats := map[string]int64{}
+	for name, value := range parseRowCounts(out.String()) {
+		stats[name], _ = strconv.ParseInt(value, 10, 64)
+	}
+
+	out.Reset()
+	if err := cluster.exec(psql, strings.NewReader(replicationStatusSQL), &out); err != nil {
+		return failed(err)
+	}
+
+	var replicas []*api.Machine
+	for _, m := range machines {
+		if m.ID != cluster.leader.ID {
+			replicas = append(replicas, m)
+		}
+	}
+
+	return []doctorCheck{
+		replicationCheck(replicas, parseReplicationStatus(out.String())),
+		connectionsCheck(stats["connections"], stats["max_connections"]),
+		checkpointsCheck(stats),
+	}
+}
+
+func healthChecksCheck(machines []*api.Machine) doctorCheck {
+	check := doctorCheck{Name: "health checks", Status: doctorOK, Details: "all passing"}
+
+	var failing []string
+	for _, m := range machines {
+		for _, c := range m.Checks {
+			if c.Status != "passing" {
+				failing = append(failing, fmt.Sprintf("%s on %s", c.Name, m.ID))
+			}
+		}
+	}
+
+	if len(failing) > 0 {
+		check.Status = doctorFailed
+		check.Details = "failing: " + strings.Join(failing, ", ")
+		check.Advice = "Inspect the output of the failing checks with fly checks list and the logs of the machines with fly logs"
+	}
+	return check
+}
+
+func imageVersionsCheck(ctx context.Context, machines []*api.Machine) doctorCheck {
+	check := doctorCheck{Name: "image versions", Status: doctorOK, Details: "all up to date"}
+	apiClient := client.FromContext(ctx).API()
+
+	var outdated []string
+	for _, m := range machines {
+		image := fmt.Sprintf("%s:%s", m.ImageRef.Repository, m.ImageRef.Tag)
+		latest, err := apiClient.GetLatestImageDetails(ctx, image)
+		if err != nil {
+			if strings.Contains(err.Error(), "Unknown repository") {
+				continue
+			}
+			check.Status = doctorWarning
+			check.Details = fmt.Sprintf("unable to fetch latest image details for %s: %v", image, err)
+			return check
+		}
+		if m.ImageRef.Digest != latest.Digest {
+			outdated = append(outdated, fmt.Sprintf("%s runs %s, latest is %s", m.ID, m.ImageRefWithVersion(), latest.Version))
+		}
+	}
+
+	if len(outdated) > 0 {
+		check.Status = doctorWarning
+		check.Details = strings.Join(outdated, ", ")
+		check.Advice = "Update the machines to the latest image with fly image update"
+	}
+	return check
+}
+
+func diskUsageCheck(ctx context.Context, app *api.AppCompact, m *api.Machine) doctorCheck {
+	check := doctorCheck{Name: "disk usage of " + m.ID}
+	if len(m.Config.Mounts) == 0 {
+		check.Status = doctorWarning
+		check.Details = "no volume mounted"
+		return check
+	}
+
+	var out bytes.Buffer
+	if err := sshExec(ctx, app, m.PrivateIP, "df -P -B1 "+m.Config.Mounts[0].Path, nil, &out); err != nil {
+		check.Status = doctorWarning
+		check.Details = fmt.Sprintf("unable to read the disk usage: %v", err)
+		return check
+	}
+
+	used, total, err := parseDiskUsage(out.String())
+	if err != nil {
+		check.Status = doctorWarning
+		check.Details = err.Error()
+		return check
+	}
+	return diskCheck(check.Name, used, total)
+}
+
+// parseDiskUsage returns the used and total bytes of the filesystem from the
+// output of df -P -B1.
+func parseDiskUsage(out string) (used, total int64, err error) {
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) < 2 {
+		return 0, 0, fmt.Errorf("unexpected df output: %q", out)
+	}
+	fields := strings.Fields(lines[len(lines)-1])
+	if len(fields) < 4 {
+		return 0, 0, fmt.Errorf("unexpected df output: %q", out)
+	}
+	if total, err = strconv.ParseInt(fields[1], 10, 64); err != nil {
+		return 0, 0, fmt.Errorf("unexpected df output: %q", out)
+	}
+	if used, err = strconv.ParseInt(fields[2], 10, 64); err != nil {
+		return 0, 0, fmt.Errorf("unexpected df output: %q", out)
+	}
+	return used, total, nil
+}
+
+func diskCheck(name string, used, total int64) doctorCheck {
+	check := doctorCheck{Name: name, Status: doctorOK}
+	if total <= 0 {
+		check.Status = doctorWarning
+		check.Details = "unknown volume size"
+		return check
+	}
+
+	ratio := float64(used) / float64(total)
+	check.Details = fmt.Sprintf("%s of %s used (%.0f%%)", humanize.IBytes(uint64(used)), humanize.IBytes(uint64(total)), ratio*100)
+
+	switch {
+	case ratio >= 0.9:
+		check.Status = doctorFailed
+	case ratio >= 0.8:
+		check.Status = doctorWarning
+	}
+	if check.Status != doctorOK {
+		check.Advice = "Extend the volumes of the cluster with fly volumes extend before Postgres runs out of space"
+	}
+	return check
+}
+
+func replicationCheck(replicas []*api.Machine, statuses map[string]replicationStatus) doctorCheck {
+	// replicas further than this behind the leader are lagging
+	const maxBytesBehind = 16 * 1024 * 1024
+
+	check := doctorCheck{Name: "replication", Status: doctorOK}
+	if len(replicas) == 0 {
+		check.Status = doctorWarning
+		check.Details = "no replicas"
+		check.Advice = "Without replicas, the cluster can't fail over when its leader fails; add one with fly pg replica add"
+		return check
+	}
+
+	var disconnected, lagging []string
+	for _, m := range replicas {
+		status, ok := statuses[m.PrivateIP]
+		switch {
+		case !ok || status.State != "streaming":
+			disconnected = append(disconnected, m.ID)
+		case status.BytesBehind > maxBytesBehind:
+			lagging = append(lagging, fmt.Sprintf("%s (%s behind)", m.ID, humanize.IBytes(uint64(status.BytesBehind))))
+		}
+	}
+
+	switch {
+	case len(disconnected) > 0:
+		check.Status = doctorFailed
+		check.Details = "not streaming: " + strings.Join(disconnected, ", ")
+		check.Advice = "Replicas which don't stream from the leader don't protect the cluster, check their logs with fly logs"
+	case len(lagging) > 0:
+		check.Status = doctorWarning
+		check.Details = "lagging: " + strings.Join(lagging, ", ")
+		check.Advice = "Lagging replicas may lack the latest writes after a failover, check their load and disk I/O"
+	default:
+		check.Details = fmt.Sprintf("%d replicas streaming", len(replicas))
+	}
+	return check
+}
+
+func connectionsCheck(used, maxConnections int64) doctorCheck {
+	check := doctorCheck{Name: "connections", Status: doctorOK}
+	if maxConnections <= 0 {
+		check.Status = doctorWarning
+		check.Details = "unknown max_connections"
+		return check
+	}
+
+	ratio := float64(used) / float64(maxConnections)
+	check.Details = fmt.Sprintf("%d of %d used (%.0f%%)", used, maxConnections, ratio*100)
+
+	switch {
+	case ratio >= 0.95:
+		check.Status = doctorFailed
+	case ratio >= 0.8:
+		check.Status = doctorWarning
+	}
+	if check.Status != doctorOK {
+		check.Advice = "Use a connection pooler or raise max_connections with fly pg config set max_connections=<n>"
+	}
+	return check
+}
+
+func checkpointsCheck(stats map[string]int64) doctorCheck {
+	check := doctorCheck{Name: "checkpoints", Status: doctorOK}
+
+	checkpoints := stats["checkpoints_timed"] + stats["checkpoints_req"]
+	buffers := stats["buffers_checkpoint"] + stats["buffers_clean"] + stats["buffers_backend"]
+	if checkpoints == 0 || buffers == 0 {
+		check.Details = "not enough activity"
+		return check
+	}
+
+	requested := float64(stats["checkpoints_req"]) / float64(checkpoints)
+	backend := float64(stats["buffers_backend"]) / float64(buffers)
+	check.Details = fmt.Sprintf("%.0f%% requested checkpoints, %.0f%% buffers written by backends", requested*100, backend*100)
+
+	var advice []string
+	if requested > 0.5 {
+		advice = append(advice, "most checkpoints are forced by WAL volume, raise max_wal_size")
+	}
+	if backend > 0.2 {
+		advice = append(advice, "backends write many buffers themselves, raise shared_buffers")
+	}
+	if len(advice) > 0 {
+		check.Status = doctorWarning
+		check.Advice = "Tune the cluster with fly pg config set: " + strings.Join(advice, "; ")
+	}
+	return check
+}
@@ -0,0 +1,49 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/superfly/flyctl/api"
+)
+
+func TestParseDiskUsage(t *testing.T) {
+	used, total, err := parseDiskUsage("Filesystem     1-blocks      Used  Available Capacity Mounted on\n/dev/vdb     1040441344 524288000  463790080      54% /data\n")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(524288000), used)
+	assert.Equal(t, int64(1040441344), total)
+
+	_, _, err = parseDiskUsage("df: /data: No such file or directory")
+	assert.Error(t, err)
+}
+
+func TestDoctorThresholds(t *testing.T) {
+	assert.Equal(t, doctorOK, diskCheck("disk", 50, 100).Status)
+	assert.Equal(t, doctorWarning, diskCheck("disk", 85, 100).Status)
+	assert.Equal(t, doctorFailed, diskCheck("disk", 95, 100).Status)
+
+	assert.Equal(t, doctorOK, connectionsCheck(10, 100).Status)
+	assert.Equal(t, doctorWarning, connectionsCheck(80, 100).Status)
+	assert.Equal(t, doctorFailed, connectionsCheck(99, 100).Status)
+
+	assert.Equal(t, doctorOK, checkpointsCheck(map[string]int64{"checkpoints_timed": 9, "checkpoints_req": 1, "buffers_checkpoint": 100}).Status)
+	assert.Equal(t, doctorWarning, checkpointsCheck(map[string]int64{"checkpoints_timed": 1, "checkpoints_req": 9, "buffers_checkpoint": 100}).Status)
+}
+
+func TestReplicationCheck(t *testing.T) {
+	replicas := []*api.Machine{{ID: "a", PrivateIP: "fdaa::a"}, {ID: "b", PrivateIP: "fdaa::b"}}
+
+	assert.Equal(t, doctorWarning, replicationCheck(nil, nil).Status)
+	assert.Equal(t, doctorOK, replicationCheck(replicas, map[string]replicationStatus{
+		"fdaa::a": {State: "streaming"},
+		"fdaa::b": {State: "streaming"},
+	}).Status)
+	assert.Equal(t, doctorWarning, replicationCheck(replicas, map[string]replicationStatus{
+		"fdaa::a": {State: "streaming"},
+		"fdaa::b": {State: "streaming", BytesBehind: 64 * 1024 * 1024},
+	}).Status)
+
+	check := replicationCheck(replicas, map[string]replicationStatus{"fdaa::a": {State: "streaming"}})
+	assert.Equal(t, doctorFailed, check.Status)
+	assert.Equal(t, "not streaming: b", check.Details)
+}
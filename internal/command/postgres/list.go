@@ -49,7 +49,7 @@ func runList(ctx context.Context) (err error) {
 
 	// if --json
 	if cfg.JSONOutput {
-		return render.JSON(io.Out, apps)
+		return render.Output(ctx, io.Out, apps)
 	}
 
 	rows := make([][]string, 0, len(apps))
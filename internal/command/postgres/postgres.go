@@ -1,17 +1,26 @@
 package postgres
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"net"
 	"os"
+	"strings"
 
+	"github.com/docker/docker/pkg/ioutils"
 	"github.com/hashicorp/go-version"
 	"github.com/spf13/cobra"
 	"github.com/superfly/flyctl/agent"
 	"github.com/superfly/flyctl/api"
 	"github.com/superfly/flyctl/flypg"
+	"github.com/superfly/flyctl/helpers"
 	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/command/apps"
+	"github.com/superfly/flyctl/internal/command/ssh"
 	mach "github.com/superfly/flyctl/internal/machine"
+	"github.com/superfly/flyctl/iostreams"
 )
 
 func New() *cobra.Command {
@@ -27,18 +36,24 @@ func New() *cobra.Command {
 
 	cmd.AddCommand(
 		newAttach(),
+		newBackup(),
 		newConfig(),
 		newConnect(),
 		newCreate(),
 		newDb(),
 		newDetach(),
+		newDoctor(),
 		newList(),
+		newReplica(),
 		newRestart(),
+		newRestore(),
 		newUsers(),
 		newFailover(),
 		newNomadToMachines(),
 		newAddFlycast(),
 		newImport(),
+		newUpgrade(),
+		newWALArchive(),
 	)
 
 	return cmd
@@ -231,6 +246,104 @@ func leaderIpFromNomadInstances(ctx context.Context, addrs []string) (string, er
 	return "", fmt.Errorf("no instances found with leader role")
 }
 
+// pgCluster is a Postgres cluster reached through its leader, for commands
+// which connect to its databases directly.
+type pgCluster struct {
+	ctx      context.Context
+	app      *api.AppCompact
+	leader   *api.Machine
+	client   *flypg.Client
+	user     string
+	password string
+}
+
+// resolvePGCluster returns the cluster of the app along with its active
+// machines.
+func resolvePGCluster(ctx context.Context, app *api.AppCompact) (*pgCluster, []*api.Machine, error) {
+	ctx, err := apps.BuildContext(ctx, app)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	machines, err := mach.ListActive(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("machines could not be retrieved: %w", err)
+	}
+
+	leader, err := pickLeader(ctx, machines)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &pgCluster{
+		ctx:    ctx,
+		app:    app,
+		leader: leader,
+		client: flypg.NewFromInstance(leader.PrivateIP, agent.DialerFromContext(ctx)),
+	}, machines, nil
+}
+
+// createTemporaryUser creates a superuser with a random password on the
+// cluster, to be removed with the returned func once done.
+func (c *pgCluster) createTemporaryUser(name string) (func(), error) {
+	password, err := helpers.RandString(24)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.client.CreateUser(c.ctx, name, password, true); err != nil {
+		return nil, fmt.Errorf("failed creating user %s on %s: %w", name, c.app.Name, err)
+	}
+	c.user, c.password = name, password
+
+	return func() {
+		if err := c.client.DeleteUser(c.ctx, name); err != nil {
+			fmt.Fprintf(iostreams.FromContext(c.ctx).ErrOut, "failed removing user %s from %s: %v\n", name, c.app.Name, err)
+		}
+	}, nil
+}
+
+// uri returns the URI of the database on the leader of the cluster, connecting
+// as the temporary user.
+func (c *pgCluster) uri(database string) string {
+	return fmt.Sprintf("postgres://%s:%s@%s/%s?sslmode=disable",
+		c.user, c.password, net.JoinHostPort(c.leader.PrivateIP, "5433"), database)
+}
+
+// exec runs the command on the leader of the cluster.
+func (c *pgCluster) exec(cmd string, stdin io.Reader, stdout io.Writer) error {
+	return sshExec(c.ctx, c.app, c.leader.PrivateIP, cmd, stdin, stdout)
+}
+
+// sshExec runs the command over SSH without a pseudo-terminal, so that binary
+// output such as dumps is streamed intact. Its stderr is included in the error
+// when it fails.
+func sshExec(ctx context.Context, app *api.AppCompact, addr, cmd string, stdin io.Reader, stdout io.Writer) error {
+	if stdin == nil {
+		stdin = bytes.NewReader(nil)
+	}
+
+	var errBuf bytes.Buffer
+	err := ssh.SSHConnect(&ssh.SSHParams{
+		Ctx:            ctx,
+		Org:            app.Organization,
+		Dialer:         agent.DialerFromContext(ctx),
+		App:            app.Name,
+		Username:       ssh.DefaultSshUsername,
+		Cmd:            cmd,
+		Stdin:          stdin,
+		Stdout:         ioutils.NewWriteCloserWrapper(stdout, func() error { return nil }),
+		Stderr:         ioutils.NewWriteCloserWrapper(&errBuf, func() error { return nil }),
+		DisableSpinner: true,
+		DisablePTY:     true,
+	}, addr)
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(errBuf.String()))
+	}
+
+	return nil
+}
+
 func pickLeader(ctx context.Context, machines []*api.Machine) (*api.Machine, error) {
 	for _, machine := range machines {
 		if machineRole(machine) == "leader" || machineRole(machine) == "primary" {
@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/command/apps"
+	mach "github.com/superfly/flyctl/internal/machine"
+)
+
+func newReplica() *cobra.Command {
+	const (
+		short = "Manage the replicas of a Postgres cluster"
+		long  = short + "\n"
+	)
+
+	cmd := command.New("replica", short, long, nil)
+
+	cmd.Aliases = []string{"replicas"}
+
+	cmd.AddCommand(
+		newReplicaAdd(),
+		newReplicaList(),
+		newReplicaRemove(),
+		newReplicaPromote(),
+	)
+
+	return cmd
+}
+
+// replicaContext returns the context of the cluster of the app, which must run
+// on machines, along with its active machines.
+func replicaContext(ctx context.Context, appName string) (context.Context, *api.AppCompact, []*api.Machine, error) {
+	app, err := client.FromContext(ctx).API().GetAppCompact(ctx, appName)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed retrieving app %s: %w", appName, err)
+	}
+	if !app.IsPostgresApp() || app.PlatformVersion != "machines" {
+		return nil, nil, nil, fmt.Errorf("app %s is not a Postgres cluster running on machines", appName)
+	}
+
+	ctx, err = apps.BuildContext(ctx, app)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	machines, err := mach.ListActive(ctx)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("machines could not be retrieved: %w", err)
+	}
+
+	return ctx, app, machines, nil
+}
+
+// findReplica returns the machine of the cluster with the ID, which must not be
+// its leader.
+func findReplica(machines []*api.Machine, id string) (*api.Machine, error) {
+	for _, m := range machines {
+		if m.ID != id {
+			continue
+		}
+		if role := machineRole(m); role == "leader" || role == "primary" {
+			return nil, fmt.Errorf("machine %s is the leader of the cluster, not a replica", id)
+		}
+		return m, nil
+	}
+
+	return nil, fmt.Errorf("machine %s is not part of the cluster", id)
+}
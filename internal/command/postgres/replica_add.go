@@ -0,0 +1,133 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	mach "github.com/superfly/flyctl/internal/machine"
+	"github.com/superfly/flyctl/internal/watch"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newReplicaAdd() *cobra.Command {
+	const (
+		short = "Add replicas to a Postgres cluster"
+		long  = short + `
+
+Adds standby replicas in a region, cloned from the configuration of the
+leader with volumes as large as its own. Replicas outside of the primary
+region of the cluster serve reads but are never elected leader.
+`
+		usage = "add"
+	)
+
+	cmd := command.New(usage, short, long, runReplicaAdd,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.Region(),
+		flag.Int{
+			Name:        "count",
+			Description: "The number of replicas to add",
+			Default:     1,
+		},
+	)
+
+	return cmd
+}
+
+func runReplicaAdd(ctx context.Context) error {
+	var (
+		io        = iostreams.FromContext(ctx)
+		apiClient = client.FromContext(ctx).API()
+		appName   = appconfig.NameFromContext(ctx)
+		region    = flag.GetRegion(ctx)
+		count     = flag.GetInt(ctx, "count")
+	)
+
+	if region == "" {
+		return fmt.Errorf("the region of the replicas must be specified with --region")
+	}
+	if count < 1 {
+		return fmt.Errorf("the number of replicas must be at least 1")
+	}
+
+	ctx, app, machines, err := replicaContext(ctx, appName)
+	if err != nil {
+		return err
+	}
+
+	leader, err := pickLeader(ctx, machines)
+	if err != nil {
+		return err
+	}
+	if len(leader.Config.Mounts) == 0 {
+		return fmt.Errorf("leader %s has no volume to clone", leader.ID)
+	}
+
+	leaderVolume, err := apiClient.GetVolume(ctx, leader.Config.Mounts[0].Volume)
+	if err != nil {
+		return fmt.Errorf("failed retrieving volume %s: %w", leader.Config.Mounts[0].Volume, err)
+	}
+
+	replicas := make([]*api.Machine, 0, count)
+	for i := 0; i < count; i++ {
+		fmt.Fprintf(io.Out, "Provisioning replica %d of %d in %s\n", i+1, count, region)
+
+		vol, err := apiClient.CreateVolume(ctx, api.CreateVolumeInput{
+			AppID:             app.ID,
+			Name:              leaderVolume.Name,
+			Region:            region,
+			SizeGb:            leaderVolume.SizeGb,
+			Encrypted:         true,
+			RequireUniqueZone: true,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create volume: %w", err)
+		}
+
+		config := mach.CloneConfig(leader.Config)
+		config.Mounts = []api.MachineMount{{
+			Volume: vol.ID,
+			Path:   leader.Config.Mounts[0].Path,
+		}}
+
+		replica, err := flaps.FromContext(ctx).Launch(ctx, api.LaunchMachineInput{
+			AppID:   app.ID,
+			OrgSlug: app.Organization.ID,
+			Region:  region,
+			Config:  config,
+		})
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(io.Out, "Waiting for machine %s to start...\n", replica.ID)
+		if err := mach.WaitForStartOrStop(ctx, replica, "start", 5*time.Minute); err != nil {
+			return err
+		}
+		replicas = append(replicas, replica)
+	}
+
+	if err := watch.MachinesChecks(ctx, replicas); err != nil {
+		return fmt.Errorf("failed to wait for health checks to pass: %w", err)
+	}
+
+	fmt.Fprintf(io.Out, "Added %d replicas to %s in %s\n", count, appName, region)
+	return nil
+}
@@ -0,0 +1,164 @@
+package postgres
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+// replicaUser is the temporary superuser reading the replication status.
+const replicaUser = "flyctl_replica"
+
+// replicationStatusSQL lists the replicas streaming from the leader, one
+// "address|state|replay lag|bytes behind" line per replica.
+const replicationStatusSQL = `SELECT host(client_addr), state, COALESCE(replay_lag::text, ''), COALESCE(pg_wal_lsn_diff(sent_lsn, replay_lsn), 0)::bigint
+FROM pg_stat_replication;
+`
+
+type replicationStatus struct {
+	State       string
+	ReplayLag   string
+	BytesBehind int64
+}
+
+type replicaStatus struct {
+	ID          string `json:"id"`
+	Region      string `json:"region"`
+	Role        string `json:"role"`
+	State       string `json:"state"`
+	ReplayLag   string `json:"replay_lag"`
+	BytesBehind int64  `json:"bytes_behind"`
+}
+
+func newReplicaList() *cobra.Command {
+	const (
+		short = "List the replicas of a Postgres cluster and their replication lag"
+		long  = short + "\n"
+		usage = "list"
+	)
+
+	cmd := command.New(usage, short, long, runReplicaList,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	cmd.Args = cobra.NoArgs
+	cmd.Aliases = []string{"ls"}
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.JSONOutput(),
+	)
+
+	return cmd
+}
+
+func runReplicaList(ctx context.Context) error {
+	var (
+		io      = iostreams.FromContext(ctx)
+		cfg     = config.FromContext(ctx)
+		appName = appconfig.NameFromContext(ctx)
+	)
+
+	ctx, app, machines, err := replicaContext(ctx, appName)
+	if err != nil {
+		return err
+	}
+
+	cluster, _, err := resolvePGCluster(ctx, app)
+	if err != nil {
+		return err
+	}
+
+	cleanup, err := cluster.createTemporaryUser(replicaUser)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	var out bytes.Buffer
+	cmd := fmt.Sprintf("psql %s -At", cluster.uri("postgres"))
+	if err := cluster.exec(cmd, strings.NewReader(replicationStatusSQL), &out); err != nil {
+		return fmt.Errorf("failed reading the replication status of %s: %w", appName, err)
+	}
+	statuses := parseReplicationStatus(out.String())
+
+	var replicas []replicaStatus
+	for _, m := range machines {
+		if m.ID == cluster.leader.ID {
+			continue
+		}
+
+		replica := replicaStatus{
+			ID:     m.ID,
+			Region: m.Region,
+			Role:   machineRole(m),
+			State:  "disconnected",
+		}
+		if status, ok := statuses[m.PrivateIP]; ok {
+			replica.State = status.State
+			replica.ReplayLag = status.ReplayLag
+			replica.BytesBehind = status.BytesBehind
+		}
+		replicas = append(replicas, replica)
+	}
+
+	if cfg.JSONOutput {
+		return render.Output(ctx, io.Out, replicas)
+	}
+
+	if len(replicas) == 0 {
+		fmt.Fprintf(io.Out, "Cluster %s has no replicas\n", appName)
+		return nil
+	}
+
+	rows := make([][]string, 0, len(replicas))
+	for _, r := range replicas {
+		rows = append(rows, []string{
+			r.ID,
+			r.Region,
+			r.Role,
+			r.State,
+			r.ReplayLag,
+			strconv.FormatInt(r.BytesBehind, 10),
+		})
+	}
+
+	return render.Table(io.Out, fmt.Sprintf("Replicas of %s (leader %s in %s)", appName, cluster.leader.ID, cluster.leader.Region), rows,
+		"ID", "Region", "Role", "State", "Replay Lag", "Bytes Behind")
+}
+
+// parseReplicationStatus parses the output of replicationStatusSQL, by address
+// of the replica.
+func parseReplicationStatus(out string) map[string]replicationStatus {
+	statuses := map[string]replicationStatus{}
+
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		fields := strings.Split(strings.TrimSpace(scanner.Text()), "|")
+		if len(fields) != 4 {
+			continue
+		}
+		bytesBehind, _ := strconv.ParseInt(fields[3], 10, 64)
+		statuses[fields[0]] = replicationStatus{
+			State:       fields[1],
+			ReplayLag:   fields[2],
+			BytesBehind: bytesBehind,
+		}
+	}
+
+	return statuses
+}
@@ -0,0 +1,177 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/avast/retry-go/v4"
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	mach "github.com/superfly/flyctl/internal/machine"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/internal/watch"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newReplicaPromote() *cobra.Command {
+	const (
+		short = "Promote a replica to primary"
+		long  = short + `
+
+Makes a replica of a Postgres Flex cluster its new primary. When the replica
+runs outside of the primary region, the primary region of every machine is
+moved to its region first. The current primary is then stopped for the
+cluster to fail over to the replica, and started again as a replica.
+`
+		usage = "promote <machine-id>"
+	)
+
+	cmd := command.New(usage, short, long, runReplicaPromote,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	cmd.Args = cobra.ExactArgs(1)
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.Yes(),
+	)
+
+	return cmd
+}
+
+func runReplicaPromote(ctx context.Context) error {
+	var (
+		io       = iostreams.FromContext(ctx)
+		colorize = io.ColorScheme()
+		appName  = appconfig.NameFromContext(ctx)
+	)
+
+	ctx, _, _, err := replicaContext(ctx, appName)
+	if err != nil {
+		return err
+	}
+
+	machines, releaseLeaseFunc, err := mach.AcquireAllLeases(ctx)
+	defer releaseLeaseFunc(ctx, machines)
+	if err != nil {
+		return err
+	}
+
+	replica, err := findReplica(machines, flag.FirstArg(ctx))
+	if err != nil {
+		return err
+	}
+	if !IsFlex(replica) {
+		return fmt.Errorf("only replicas of Postgres Flex clusters can be promoted, use fly pg failover for others")
+	}
+	leader, err := pickLeader(ctx, machines)
+	if err != nil {
+		return err
+	}
+
+	if !flag.GetYes(ctx) {
+		switch confirmed, err := prompt.Confirmf(ctx, "Promote replica %s in %s to primary of %s?", replica.ID, replica.Region, appName); {
+		case err == nil:
+			if !confirmed {
+				return nil
+			}
+		case prompt.IsNonInteractive(err):
+			return prompt.NonInteractiveError("yes flag must be specified when not running interactively")
+		default:
+			return err
+		}
+	}
+
+	// only machines in the primary region are eligible to lead
+	if replica.Config.Env["PRIMARY_REGION"] != replica.Region {
+		fmt.Fprintf(io.Out, "%s Moving the primary region of %s to %s\n", colorize.Bold("==>"), appName, replica.Region)
+		if err := setPrimaryRegion(ctx, machines, leader, replica.Region); err != nil {
+			return err
+		}
+	}
+
+	flapsClient := flaps.FromContext(ctx)
+
+	fmt.Fprintf(io.Out, "%s Stopping primary %s\n", colorize.Bold("==>"), leader.ID)
+	if err := flapsClient.Stop(ctx, api.StopMachineInput{ID: leader.ID}, leader.LeaseNonce); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(io.Out, "%s Waiting for a new primary to be elected\n", colorize.Bold("==>"))
+	var promoted *api.Machine
+	if err := retry.Do(
+		func() error {
+			for _, m := range machines {
+				if m.ID == leader.ID {
+					continue
+				}
+				current, err := flapsClient.Get(ctx, m.ID)
+				if err != nil {
+					return err
+				}
+				if role := machineRole(current); role == "primary" || role == "leader" {
+					promoted = current
+					return nil
+				}
+			}
+			return fmt.Errorf("no replica has been promoted yet")
+		},
+		retry.Context(ctx), retry.Attempts(60), retry.Delay(time.Second), retry.DelayType(retry.FixedDelay),
+	); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(io.Out, "%s Starting %s again as a replica\n", colorize.Bold("==>"), leader.ID)
+	if _, err := flapsClient.Start(ctx, leader.ID); err != nil {
+		return err
+	}
+	if err := watch.MachinesChecks(ctx, machines); err != nil {
+		return fmt.Errorf("failed to wait for health checks to pass: %w", err)
+	}
+
+	if promoted.ID != replica.ID {
+		return fmt.Errorf("machine %s was elected primary instead of %s, run the command again to promote %s", promoted.ID, replica.ID, replica.ID)
+	}
+
+	fmt.Fprintf(io.Out, "Replica %s is the new primary of %s\n", replica.ID, appName)
+	return nil
+}
+
+// setPrimaryRegion updates every machine of the cluster to the primary region,
+// the leader last so that it fails over only once.
+func setPrimaryRegion(ctx context.Context, machines []*api.Machine, leader *api.Machine, region string) error {
+	ordered := make([]*api.Machine, 0, len(machines))
+	for _, m := range machines {
+		if m.ID != leader.ID {
+			ordered = append(ordered, m)
+		}
+	}
+	ordered = append(ordered, leader)
+
+	for _, m := range ordered {
+		config := mach.CloneConfig(m.Config)
+		if config.Env == nil {
+			config.Env = map[string]string{}
+		}
+		config.Env["PRIMARY_REGION"] = region
+
+		input := &api.LaunchMachineInput{
+			Region: m.Region,
+			Config: config,
+		}
+		if err := mach.Update(ctx, m, input); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
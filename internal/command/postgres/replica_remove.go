@@ -0,0 +1,104 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newReplicaRemove() *cobra.Command {
+	const (
+		short = "Remove a replica from a Postgres cluster"
+		long  = short + `
+
+Unregisters the replica from the cluster, then destroys its machine and its
+volume. The leader of the cluster can't be removed, promote another replica
+first.
+`
+		usage = "remove <machine-id>"
+	)
+
+	cmd := command.New(usage, short, long, runReplicaRemove,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	cmd.Args = cobra.ExactArgs(1)
+	cmd.Aliases = []string{"rm"}
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.Yes(),
+	)
+
+	return cmd
+}
+
+func runReplicaRemove(ctx context.Context) error {
+	var (
+		io        = iostreams.FromContext(ctx)
+		apiClient = client.FromContext(ctx).API()
+		appName   = appconfig.NameFromContext(ctx)
+	)
+
+	ctx, app, machines, err := replicaContext(ctx, appName)
+	if err != nil {
+		return err
+	}
+
+	replica, err := findReplica(machines, flag.FirstArg(ctx))
+	if err != nil {
+		return err
+	}
+
+	if !flag.GetYes(ctx) {
+		switch confirmed, err := prompt.Confirmf(ctx, "Destroy replica %s in %s along with its volume?", replica.ID, replica.Region); {
+		case err == nil:
+			if !confirmed {
+				return nil
+			}
+		case prompt.IsNonInteractive(err):
+			return prompt.NonInteractiveError("yes flag must be specified when not running interactively")
+		default:
+			return err
+		}
+	}
+
+	if IsFlex(replica) {
+		fmt.Fprintf(io.Out, "Unregistering replica %s from the cluster\n", replica.ID)
+		if err := UnregisterMember(ctx, app, replica); err != nil {
+			return fmt.Errorf("failed to unregister replica %s: %w", replica.ID, err)
+		}
+	}
+
+	fmt.Fprintf(io.Out, "Destroying machine %s\n", replica.ID)
+	flapsClient := flaps.FromContext(ctx)
+	if err := flapsClient.Destroy(ctx, api.RemoveMachineInput{AppID: app.Name, ID: replica.ID, Kill: true}, ""); err != nil {
+		return err
+	}
+	// volumes can only be destroyed once detached
+	if err := flapsClient.Wait(ctx, replica, "destroyed", 60*time.Second); err != nil {
+		return err
+	}
+
+	for _, mount := range replica.Config.Mounts {
+		if _, err := apiClient.DeleteVolume(ctx, mount.Volume, ""); err != nil {
+			return fmt.Errorf("failed to destroy volume %s: %w", mount.Volume, err)
+		}
+		fmt.Fprintf(io.Out, "Destroyed volume %s\n", mount.Volume)
+	}
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/superfly/flyctl/api"
+)
+
+func TestParseReplicationStatus(t *testing.T) {
+	statuses := parseReplicationStatus("fdaa:0:1::3|streaming|00:00:00.012|0\nfdaa:0:1::4|catchup||1048576\nbogus\n")
+	assert.Equal(t, map[string]replicationStatus{
+		"fdaa:0:1::3": {State: "streaming", ReplayLag: "00:00:00.012"},
+		"fdaa:0:1::4": {State: "catchup", BytesBehind: 1048576},
+	}, statuses)
+}
+
+func TestFindReplica(t *testing.T) {
+	machines := []*api.Machine{
+		{ID: "leader", Checks: []*api.MachineCheckStatus{{Name: "role", Status: "passing", Output: "primary"}}},
+		{ID: "replica", Checks: []*api.MachineCheckStatus{{Name: "role", Status: "passing", Output: "replica"}}},
+	}
+
+	replica, err := findReplica(machines, "replica")
+	assert.NoError(t, err)
+	assert.Equal(t, "replica", replica.ID)
+
+	_, err = findReplica(machines, "leader")
+	assert.ErrorContains(t, err, "leader")
+
+	_, err = findReplica(machines, "other")
+	assert.ErrorContains(t, err, "not part of the cluster")
+}
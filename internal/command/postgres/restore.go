@@ -0,0 +1,232 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flypg"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newRestore() *cobra.Command {
+	const (
+		short = "Restore a Postgres cluster to a point in time"
+		long  = short + `
+
+Creates a new Postgres Flex cluster from the latest volume snapshot of the
+leader taken before --timestamp, then replays the WAL archived with
+fly pg wal-archive up to that timestamp. The original cluster is left
+untouched, so that data deleted by accident can be recovered from the restored
+one or the apps attached to it.
+`
+		usage = "restore --timestamp <time>"
+	)
+
+	cmd := command.New(usage, short, long, runRestore,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.Yes(),
+		backupS3Flags,
+		flag.String{
+			Name:        "timestamp",
+			Description: "The time to restore the cluster to, in RFC 3339 format, e.g. 2023-06-01T12:30:00Z",
+		},
+		flag.String{
+			Name:        "name",
+			Description: "The name of the restored cluster, defaults to <app>-restored",
+		},
+		flag.String{
+			Name:        "snapshot-id",
+			Description: "The volume snapshot to start from, defaults to the latest one taken before the timestamp",
+		},
+		flag.String{
+			Name:        "image",
+			Description: "The image of the restored cluster, defaults to the image of the current one",
+		},
+		flag.String{
+			Name:        "vm-size",
+			Description: "The size of the restored cluster's VMs, defaults to the size of the current ones",
+		},
+	)
+
+	return cmd
+}
+
+func runRestore(ctx context.Context) error {
+	var (
+		io        = iostreams.FromContext(ctx)
+		colorize  = io.ColorScheme()
+		apiClient = client.FromContext(ctx).API()
+		appName   = appconfig.NameFromContext(ctx)
+	)
+
+	target, err := restoreTimestamp(flag.GetString(ctx, "timestamp"), time.Now())
+	if err != nil {
+		return err
+	}
+
+	bucket, err := s3BucketFromFlags(ctx)
+	if err != nil {
+		return err
+	}
+	if bucket == nil {
+		return fmt.Errorf("the bucket the WAL is archived to must be specified with --s3-bucket")
+	}
+	archiveURL, err := walArchiveURL(bucket, appName)
+	if err != nil {
+		return err
+	}
+
+	app, err := apiClient.GetAppCompact(ctx, appName)
+	if err != nil {
+		return fmt.Errorf("failed retrieving app %s: %w", appName, err)
+	}
+	if !app.IsPostgresApp() || app.PlatformVersion != "machines" {
+		return fmt.Errorf("app %s is not a Postgres cluster running on machines", appName)
+	}
+
+	source, machines, err := resolvePGCluster(ctx, app)
+	if err != nil {
+		return err
+	}
+	if !IsFlex(source.leader) {
+		return fmt.Errorf("only Postgres Flex clusters can be restored to a point in time")
+	}
+
+	snapshotID := flag.GetString(ctx, "snapshot-id")
+	if snapshotID == "" {
+		if snapshotID, err = leaderSnapshotBefore(ctx, source.leader, target); err != nil {
+			return err
+		}
+	}
+
+	newName := flag.GetString(ctx, "name")
+	if newName == "" {
+		newName = appName + "-restored"
+	}
+
+	if !flag.GetYes(ctx) {
+		switch confirmed, err := prompt.Confirmf(ctx, "Restore %s as of %s to the new cluster %s?", appName, target.Format(time.RFC3339), newName); {
+		case err == nil:
+			if !confirmed {
+				return nil
+			}
+		case prompt.IsNonInteractive(err):
+			return prompt.NonInteractiveError("yes flag must be specified when not running interactively")
+		default:
+			return err
+		}
+	}
+
+	org, err := apiClient.GetOrganizationBySlug(ctx, app.Organization.Slug)
+	if err != nil {
+		return fmt.Errorf("failed retrieving organization %s: %w", app.Organization.Slug, err)
+	}
+	vmSize, err := upgradeVMSize(ctx, source.leader)
+	if err != nil {
+		return err
+	}
+	volumeSize, err := leaderVolumeSize(ctx, source.leader)
+	if err != nil {
+		return err
+	}
+	image := flag.GetString(ctx, "image")
+	if image == "" {
+		image = source.leader.FullImageRef()
+	}
+
+	fmt.Fprintf(io.Out, "%s Restoring %s from snapshot %s to %s\n", colorize.Bold("==>"), appName, snapshotID, target.Format(time.RFC3339))
+
+	input := &flypg.CreateClusterInput{
+		AppName:            newName,
+		Organization:       org,
+		ImageRef:           image,
+		Region:             source.leader.Region,
+		Manager:            flypg.ReplicationManager,
+		InitialClusterSize: len(machines),
+		VMSize:             vmSize,
+		VolumeSize:         api.IntPointer(volumeSize),
+		SnapshotID:         api.StringPointer(snapshotID),
+		RestoreConfig:      restoreConfig(archiveURL, target),
+	}
+	if err := flypg.NewLauncher(apiClient).LaunchMachinesPostgres(ctx, input, false); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(io.Out, "%s Cluster %s restored, attach it with fly pg attach %s\n", colorize.Bold("==>"), newName, newName)
+	return nil
+}
+
+// restoreTimestamp parses the timestamp to restore to, which must be in the
+// past.
+func restoreTimestamp(s string, now time.Time) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, fmt.Errorf("the time to restore to must be specified with --timestamp")
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %s, expected RFC 3339 format, e.g. 2023-06-01T12:30:00Z", s)
+	}
+	if t.After(now) {
+		return time.Time{}, fmt.Errorf("can't restore to %s, it is in the future", s)
+	}
+
+	return t.UTC(), nil
+}
+
+// leaderSnapshotBefore returns the latest snapshot of the volume of the leader
+// taken before the time, which the WAL is replayed on top of.
+func leaderSnapshotBefore(ctx context.Context, leader *api.Machine, t time.Time) (string, error) {
+	mounts := leader.Config.Mounts
+	if len(mounts) == 0 {
+		return "", fmt.Errorf("leader %s has no volume to restore from", leader.ID)
+	}
+
+	snapshots, err := client.FromContext(ctx).API().GetVolumeSnapshots(ctx, mounts[0].Volume)
+	if err != nil {
+		return "", fmt.Errorf("failed retrieving snapshots of volume %s: %w", mounts[0].Volume, err)
+	}
+
+	snapshot := snapshotBefore(snapshots, t)
+	if snapshot == nil {
+		return "", fmt.Errorf("volume %s has no snapshot taken before %s", mounts[0].Volume, t.Format(time.RFC3339))
+	}
+	return snapshot.ID, nil
+}
+
+func snapshotBefore(snapshots []api.Snapshot, t time.Time) *api.Snapshot {
+	var latest *api.Snapshot
+	for i, s := range snapshots {
+		if s.CreatedAt.After(t) {
+			continue
+		}
+		if latest == nil || s.CreatedAt.After(latest.CreatedAt) {
+			latest = &snapshots[i]
+		}
+	}
+	return latest
+}
+
+// restoreConfig returns the barman-cloud configuration replaying the archive
+// up to the time.
+func restoreConfig(archiveURL string, t time.Time) string {
+	return archiveURL + "?" + url.Values{"targetTime": {t.Format(time.RFC3339)}}.Encode()
+}
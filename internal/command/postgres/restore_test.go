@@ -0,0 +1,38 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/superfly/flyctl/api"
+)
+
+func TestRestoreTimestamp(t *testing.T) {
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	ts, err := restoreTimestamp("2023-06-01T13:30:00+02:00", now)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2023, 6, 1, 11, 30, 0, 0, time.UTC), ts)
+
+	_, err = restoreTimestamp("2023-06-01T12:30:00Z", now)
+	assert.ErrorContains(t, err, "future")
+
+	_, err = restoreTimestamp("yesterday", now)
+	assert.Error(t, err)
+}
+
+func TestSnapshotBefore(t *testing.T) {
+	at := func(hour int) time.Time { return time.Date(2023, 6, 1, hour, 0, 0, 0, time.UTC) }
+	snapshots := []api.Snapshot{
+		{ID: "vs_1", CreatedAt: at(1)},
+		{ID: "vs_3", CreatedAt: at(3)},
+		{ID: "vs_2", CreatedAt: at(2)},
+	}
+
+	assert.Equal(t, "vs_2", snapshotBefore(snapshots, at(2)).ID)
+	assert.Equal(t, "vs_3", snapshotBefore(snapshots, at(5)).ID)
+	assert.Nil(t, snapshotBefore(snapshots, at(0)))
+
+	assert.Equal(t, "https://k:s@s3.amazonaws.com/b/pg?targetTime=2023-06-01T02%3A00%3A00Z", restoreConfig("https://k:s@s3.amazonaws.com/b/pg", at(2)))
+}
@@ -0,0 +1,547 @@
+package postgres
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/flypg"
+	"github.com/superfly/flyctl/helpers"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	mach "github.com/superfly/flyctl/internal/machine"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+// upgradeUser is the temporary superuser created on both clusters to copy and
+// verify the data.
+const upgradeUser = "flyctl_upgrade"
+
+// upgradeSkippedDatabases are managed by the cluster itself and never copied.
+var upgradeSkippedDatabases = []string{"postgres", "repmgr", "template0", "template1"}
+
+// upgradeRowCountsSQL counts the rows of every table of a database, one
+// "schema.table|count" line per table.
+const upgradeRowCountsSQL = `SELECT table_schema || '.' || table_name,
+  (xpath('/row/c/text()', query_to_xml(format('SELECT count(*) AS c FROM %I.%I', table_schema, table_name), false, true, '')))[1]::text
+FROM information_schema.tables
+WHERE table_type = 'BASE TABLE' AND table_schema NOT IN ('pg_catalog', 'information_schema')
+ORDER BY 1;
+`
+
+func newUpgrade() *cobra.Command {
+	const (
+		short = "Upgrade a Postgres cluster to a new major version"
+		long  = short + `
+
+Upgrades a Postgres Flex cluster to a new major version. The volumes of the
+cluster are snapshotted first, then a new cluster running the target version
+is created next to it and every database is copied over with pg_dump and
+pg_restore. Row counts of every table are compared between both clusters
+before the attachments of the apps given with --attached-app are switched to
+the new cluster. The original cluster is left untouched, destroy it once the
+new one is verified.
+`
+		usage = "upgrade --to <major-version>"
+	)
+
+	cmd := command.New(usage, short, long, runUpgrade,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.Yes(),
+		flag.Int{
+			Name:        "to",
+			Description: "The major version of Postgres to upgrade to, e.g. 16",
+		},
+		flag.String{
+			Name:        "name",
+			Description: "The name of the new cluster, defaults to <app>-pg<version>",
+		},
+		flag.StringSlice{
+			Name:        "attached-app",
+			Description: "Apps whose attachments are switched to the new cluster. Can be specified multiple times.",
+		},
+		flag.String{
+			Name:        "image",
+			Description: "The image of the new cluster, defaults to flyio/postgres-flex:<version>",
+		},
+		flag.String{
+			Name:        "vm-size",
+			Description: "The size of the new cluster's VMs, defaults to the size of the current ones",
+		},
+		flag.String{
+			Name:        "importer-image",
+			Description: "The image of the machine copying the data, defaults to the latest flyio/postgres-importer",
+		},
+	)
+
+	return cmd
+}
+
+func runUpgrade(ctx context.Context) error {
+	var (
+		io        = iostreams.FromContext(ctx)
+		colorize  = io.ColorScheme()
+		apiClient = client.FromContext(ctx).API()
+		appName   = appconfig.NameFromContext(ctx)
+		target    = flag.GetInt(ctx, "to")
+	)
+
+	if target == 0 {
+		return fmt.Errorf("the major version to upgrade to must be specified with --to")
+	}
+
+	app, err := apiClient.GetAppCompact(ctx, appName)
+	if err != nil {
+		return fmt.Errorf("failed retrieving app %s: %w", appName, err)
+	}
+	if !app.IsPostgresApp() || app.PlatformVersion != "machines" {
+		return fmt.Errorf("app %s is not a Postgres cluster running on machines", appName)
+	}
+
+	source, machines, err := resolvePGCluster(ctx, app)
+	if err != nil {
+		return err
+	}
+	if !IsFlex(source.leader) {
+		return fmt.Errorf("only Postgres Flex clusters can be upgraded, see https://fly.io/docs/postgres/ for migrating others")
+	}
+	if current := imageMajorVersion(source.leader.ImageRef.Tag); current != 0 && current >= target {
+		return fmt.Errorf("cluster %s already runs Postgres %d", appName, current)
+	}
+
+	newName := flag.GetString(ctx, "name")
+	if newName == "" {
+		newName = fmt.Sprintf("%s-pg%d", appName, target)
+	}
+
+	if !flag.GetYes(ctx) {
+		switch confirmed, err := prompt.Confirmf(ctx, "Upgrade %s to Postgres %d by creating the new cluster %s?", appName, target, newName); {
+		case err == nil:
+			if !confirmed {
+				return nil
+			}
+		case prompt.IsNonInteractive(err):
+			return prompt.NonInteractiveError("yes flag must be specified when not running interactively")
+		default:
+			return err
+		}
+	}
+
+	fmt.Fprintf(io.Out, "%s Snapshotting volumes of %s\n", colorize.Bold("==>"), appName)
+	if err := snapshotVolumes(ctx, machines); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(io.Out, "%s Creating cluster %s on Postgres %d\n", colorize.Bold("==>"), newName, target)
+	newApp, err := createUpgradeCluster(ctx, app, source.leader, len(machines), newName, target)
+	if err != nil {
+		return err
+	}
+	dest, _, err := resolvePGCluster(ctx, newApp)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range []*pgCluster{source, dest} {
+		cleanup, err := c.createTemporaryUser(upgradeUser)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+	}
+
+	databases, err := source.client.ListDatabases(ctx)
+	if err != nil {
+		return fmt.Errorf("failed listing databases of %s: %w", appName, err)
+	}
+
+	attachments, err := upgradeAttachments(ctx, app)
+	if err != nil {
+		return err
+	}
+
+	// the users of the attachments must exist before the data is restored for
+	// the restored objects to keep their owners
+	passwords := map[string]string{}
+	for _, a := range attachments {
+		if _, ok := passwords[a.DatabaseUser]; ok {
+			continue
+		}
+		if passwords[a.DatabaseUser], err = helpers.RandString(15); err != nil {
+			return err
+		}
+		if err := dest.client.CreateUser(dest.ctx, a.DatabaseUser, passwords[a.DatabaseUser], false); err != nil {
+			return fmt.Errorf("failed creating user %s on %s: %w", a.DatabaseUser, newName, err)
+		}
+	}
+
+	importer, err := launchImporter(dest.ctx, newApp, source.leader.Region)
+	if err != nil {
+		return err
+	}
+	defer destroyImporter(dest.ctx, newApp, importer)
+
+	for _, db := range databases {
+		if slices.Contains(upgradeSkippedDatabases, db.Name) {
+			continue
+		}
+
+		fmt.Fprintf(io.Out, "%s Copying database %s\n", colorize.Bold("==>"), db.Name)
+		cmd := fmt.Sprintf("env SOURCE_DATABASE_URI=%s migrate -create", source.uri(db.Name))
+		if err := runImporter(dest.ctx, newApp, importer, cmd, nil, io.Out); err != nil {
+			return fmt.Errorf("failed copying database %s: %w", db.Name, err)
+		}
+
+		fmt.Fprintf(io.Out, "%s Verifying row counts of database %s\n", colorize.Bold("==>"), db.Name)
+		if err := verifyRowCounts(dest.ctx, newApp, importer, source.uri(db.Name), dest.uri(db.Name)); err != nil {
+			return fmt.Errorf("database %s wasn't copied correctly, %s is left as is for inspection: %w", db.Name, newName, err)
+		}
+	}
+
+	for _, a := range attachments {
+		fmt.Fprintf(io.Out, "%s Switching %s of %s to %s\n", colorize.Bold("==>"), a.EnvironmentVariableName, a.appName, newName)
+		if err := switchAttachment(ctx, a, app, newApp, passwords[a.DatabaseUser]); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(io.Out, "\nCluster %s was upgraded to Postgres %d as %s\n", appName, target, colorize.Bold(newName))
+	if len(attachments) > 0 {
+		fmt.Fprintf(io.Out, "Deploy the attached apps for their new connection strings to take effect.\n")
+	}
+	fmt.Fprintf(io.Out, "%s is left untouched, destroy it with `fly apps destroy %s` once %s is verified.\n", appName, appName, newName)
+
+	return nil
+}
+
+// imageMajorVersion returns the major version of Postgres from an image tag
+// such as 15.3, or 0 when unknown.
+func imageMajorVersion(tag string) int {
+	major, _, _ := strings.Cut(strings.TrimPrefix(tag, "v"), ".")
+	v, err := strconv.Atoi(major)
+	if err != nil {
+		return 0
+	}
+	return v
+}
+
+func snapshotVolumes(ctx context.Context, machines []*api.Machine) error {
+	var (
+		io        = iostreams.FromContext(ctx)
+		apiClient = client.FromContext(ctx).API()
+	)
+
+	for _, m := range machines {
+		if m.Config == nil {
+			continue
+		}
+		for _, mount := range m.Config.Mounts {
+			if err := apiClient.CreateVolumeSnapshot(ctx, mount.Volume); err != nil {
+				return fmt.Errorf("failed snapshotting volume %s: %w", mount.Volume, err)
+			}
+			fmt.Fprintf(io.Out, "  Requested a snapshot of volume %s of machine %s\n", mount.Volume, m.ID)
+		}
+	}
+
+	return nil
+}
+
+// createUpgradeCluster launches the new cluster with as many machines, of the
+// same size and with volumes as large, as the upgraded one.
+func createUpgradeCluster(ctx context.Context, app *api.AppCompact, leader *api.Machine, size int, name string, target int) (*api.AppCompact, error) {
+	apiClient := client.FromContext(ctx).API()
+
+	org, err := apiClient.GetOrganizationBySlug(ctx, app.Organization.Slug)
+	if err != nil {
+		return nil, fmt.Errorf("failed retrieving organization %s: %w", app.Organization.Slug, err)
+	}
+
+	vmSize, err := upgradeVMSize(ctx, leader)
+	if err != nil {
+		return nil, err
+	}
+
+	volumeSize, err := leaderVolumeSize(ctx, leader)
+	if err != nil {
+		return nil, err
+	}
+
+	image := flag.GetString(ctx, "image")
+	if image == "" {
+		image = fmt.Sprintf("flyio/postgres-flex:%d", target)
+	}
+
+	input := &flypg.CreateClusterInput{
+		AppName:            name,
+		Organization:       org,
+		ImageRef:           image,
+		Region:             leader.Region,
+		Manager:            flypg.ReplicationManager,
+		InitialClusterSize: size,
+		VMSize:             vmSize,
+		VolumeSize:         api.IntPointer(volumeSize),
+	}
+	if err := flypg.NewLauncher(apiClient).LaunchMachinesPostgres(ctx, input, false); err != nil {
+		return nil, err
+	}
+
+	return apiClient.GetAppCompact(ctx, name)
+}
+
+// leaderVolumeSize returns the size of the volume of the leader, so that new
+// clusters have room for its data.
+func leaderVolumeSize(ctx context.Context, leader *api.Machine) (int, error) {
+	mounts := leader.Config.Mounts
+	if len(mounts) == 0 {
+		return 10, nil
+	}
+
+	vol, err := client.FromContext(ctx).API().GetVolume(ctx, mounts[0].Volume)
+	if err != nil {
+		return 0, fmt.Errorf("failed retrieving volume %s: %w", mounts[0].Volume, err)
+	}
+	return vol.SizeGb, nil
+}
+
+// upgradeVMSize returns the size given with --vm-size, or else the one of the
+// leader of the upgraded cluster.
+func upgradeVMSize(ctx context.Context, leader *api.Machine) (*api.VMSize, error) {
+	if size := flag.GetString(ctx, "vm-size"); size != "" {
+		return resolveVMSize(ctx, size)
+	}
+
+	if guest := leader.Config.Guest; guest != nil {
+		for _, size := range MachineVMSizes() {
+			if size.CPUClass == guest.CPUKind && int(size.CPUCores) == guest.CPUs && size.MemoryMB == guest.MemoryMB {
+				return &size, nil
+			}
+		}
+	}
+
+	return resolveVMSize(ctx, "")
+}
+
+// upgradeAttachment is an attachment of the upgraded cluster to one of the
+// apps given with --attached-app.
+type upgradeAttachment struct {
+	*api.PostgresClusterAttachment
+	appName string
+}
+
+func upgradeAttachments(ctx context.Context, app *api.AppCompact) ([]upgradeAttachment, error) {
+	apiClient := client.FromContext(ctx).API()
+
+	var attachments []upgradeAttachment
+	for _, appName := range flag.GetStringSlice(ctx, "attached-app") {
+		list, err := apiClient.ListPostgresClusterAttachments(ctx, appName, app.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed listing attachments of %s: %w", appName, err)
+		}
+		if len(list) == 0 {
+			return nil, fmt.Errorf("app %s isn't attached to %s", appName, app.Name)
+		}
+		for _, a := range list {
+			attachments = append(attachments, upgradeAttachment{PostgresClusterAttachment: a, appName: appName})
+		}
+	}
+
+	return attachments, nil
+}
+
+func launchImporter(ctx context.Context, app *api.AppCompact, region string) (*api.Machine, error) {
+	var (
+		io          = iostreams.FromContext(ctx)
+		apiClient   = client.FromContext(ctx).API()
+		flapsClient = flaps.FromContext(ctx)
+	)
+
+	image := flag.GetString(ctx, "importer-image")
+	if image == "" {
+		var err error
+		if image, err = apiClient.GetLatestImageTag(ctx, "flyio/postgres-importer", nil); err != nil {
+			return nil, err
+		}
+	}
+
+	machine, err := flapsClient.Launch(ctx, api.LaunchMachineInput{
+		AppID:   app.ID,
+		OrgSlug: app.Organization.ID,
+		Region:  region,
+		Config: &api.MachineConfig{
+			Image: image,
+			Env: map[string]string{
+				"POSTGRES_PASSWORD": "pass",
+			},
+			Guest: &api.MachineGuest{
+				CPUKind:  "shared",
+				CPUs:     1,
+				MemoryMB: 1024,
+			},
+			DNS: &api.DNSConfig{
+				SkipRegistration: true,
+			},
+			Restart: api.MachineRestart{
+				Policy: api.MachineRestartPolicyNo,
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed launching importer machine: %w", err)
+	}
+
+	fmt.Fprintf(io.Out, "Waiting for importer machine %s to start...\n", machine.ID)
+	if err := mach.WaitForStartOrStop(ctx, machine, "start", time.Minute); err != nil {
+		return nil, err
+	}
+
+	return machine, nil
+}
+
+func destroyImporter(ctx context.Context, app *api.AppCompact, machine *api.Machine) {
+	io := iostreams.FromContext(ctx)
+
+	if err := flaps.FromContext(ctx).Destroy(ctx, api.RemoveMachineInput{ID: machine.ID, AppID: app.ID, Kill: true}, machine.LeaseNonce); err != nil {
+		fmt.Fprintf(io.ErrOut, "failed destroying importer machine %s: %v\n", machine.ID, err)
+	}
+}
+
+// runImporter runs the command on the importer machine. Commands aren't run
+// by a shell, so input which needs quoting must come through stdin.
+func runImporter(ctx context.Context, app *api.AppCompact, machine *api.Machine, cmd string, stdin []byte, stdout io.Writer) error {
+	return sshExec(ctx, app, machine.PrivateIP, cmd, bytes.NewReader(stdin), stdout)
+}
+
+// verifyRowCounts compares the number of rows of every table between both
+// databases.
+func verifyRowCounts(ctx context.Context, app *api.AppCompact, importer *api.Machine, sourceURI, destURI string) error {
+	counts := func(uri string) (map[string]string, error) {
+		var out bytes.Buffer
+		if err := runImporter(ctx, app, importer, fmt.Sprintf("psql %s -At", uri), []byte(upgradeRowCountsSQL), &out); err != nil {
+			return nil, err
+		}
+		return parseRowCounts(out.String()), nil
+	}
+
+	before, err := counts(sourceURI)
+	if err != nil {
+		return fmt.Errorf("failed counting rows of the original database: %w", err)
+	}
+	after, err := counts(destURI)
+	if err != nil {
+		return fmt.Errorf("failed counting rows of the new database: %w", err)
+	}
+
+	if mismatches := rowCountMismatches(before, after); len(mismatches) > 0 {
+		return fmt.Errorf("row counts differ: %s", strings.Join(mismatches, ", "))
+	}
+
+	return nil
+}
+
+// parseRowCounts parses the "schema.table|count" lines printed by psql.
+func parseRowCounts(out string) map[string]string {
+	counts := map[string]string{}
+
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		table, count, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "|")
+		if ok {
+			counts[table] = count
+		}
+	}
+
+	return counts
+}
+
+// rowCountMismatches describes the tables whose row counts differ, including
+// tables missing from either side.
+func rowCountMismatches(before, after map[string]string) (mismatches []string) {
+	for table, count := range before {
+		switch got, ok := after[table]; {
+		case !ok:
+			mismatches = append(mismatches, fmt.Sprintf("%s is missing", table))
+		case got != count:
+			mismatches = append(mismatches, fmt.Sprintf("%s has %s rows instead of %s", table, got, count))
+		}
+	}
+	for table := range after {
+		if _, ok := before[table]; !ok {
+			mismatches = append(mismatches, fmt.Sprintf("%s is unexpected", table))
+		}
+	}
+	sort.Strings(mismatches)
+
+	return mismatches
+}
+
+// switchAttachment attaches the app to the new cluster with the same database,
+// user and variable name, and detaches it from the upgraded one. The user was
+// created on the new cluster with password before the data was copied.
+func switchAttachment(ctx context.Context, a upgradeAttachment, from, to *api.AppCompact, password string) error {
+	apiClient := client.FromContext(ctx).API()
+
+	if _, err := apiClient.AttachPostgresCluster(ctx, api.AttachPostgresClusterInput{
+		AppID:                a.appName,
+		PostgresClusterAppID: to.Name,
+		ManualEntry:          true,
+		DatabaseName:         api.StringPointer(a.DatabaseName),
+		DatabaseUser:         api.StringPointer(a.DatabaseUser),
+		VariableName:         api.StringPointer(a.EnvironmentVariableName),
+	}); err != nil {
+		return fmt.Errorf("failed attaching %s to %s: %w", a.appName, to.Name, err)
+	}
+
+	toFull, err := apiClient.GetApp(ctx, to.Name)
+	if err != nil {
+		return fmt.Errorf("failed retrieving postgres app %s: %w", to.Name, err)
+	}
+
+	// same as fly pg attach
+	connectionString := fmt.Sprintf(
+		"postgres://%s:%s@top2.nearest.of.%s.internal:5432/%s?sslmode=disable",
+		a.DatabaseUser, password, to.Name, a.DatabaseName,
+	)
+	for _, ip := range toFull.IPAddresses.Nodes {
+		if ip.Type == "private_v6" {
+			connectionString = fmt.Sprintf(
+				"postgres://%s:%s@%s.flycast:5432/%s?sslmode=disable",
+				a.DatabaseUser, password, to.Name, a.DatabaseName,
+			)
+		}
+	}
+	if _, err := apiClient.SetSecrets(ctx, a.appName, map[string]string{a.EnvironmentVariableName: connectionString}); err != nil {
+		return fmt.Errorf("failed setting %s of %s: %w", a.EnvironmentVariableName, a.appName, err)
+	}
+
+	if err := apiClient.DetachPostgresCluster(ctx, api.DetachPostgresClusterInput{
+		AppID:                       a.appName,
+		PostgresClusterId:           from.Name,
+		PostgresClusterAttachmentId: a.ID,
+	}); err != nil {
+		return fmt.Errorf("failed detaching %s from %s: %w", a.appName, from.Name, err)
+	}
+
+	return nil
+}
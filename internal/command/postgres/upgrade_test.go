@@ -0,0 +1,29 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImageMajorVersion(t *testing.T) {
+	assert.Equal(t, 15, imageMajorVersion("15.3"))
+	assert.Equal(t, 16, imageMajorVersion("16"))
+	assert.Equal(t, 14, imageMajorVersion("v14.8"))
+	assert.Equal(t, 0, imageMajorVersion("latest"))
+	assert.Equal(t, 0, imageMajorVersion(""))
+}
+
+func TestRowCountMismatches(t *testing.T) {
+	before := parseRowCounts("public.users|10\npublic.posts|25\npublic.tags|3\n")
+	assert.Equal(t, map[string]string{"public.users": "10", "public.posts": "25", "public.tags": "3"}, before)
+
+	assert.Empty(t, rowCountMismatches(before, parseRowCounts("public.tags|3\npublic.users|10\npublic.posts|25")))
+
+	after := parseRowCounts("public.users|10\npublic.posts|24\npublic.audit|1\n")
+	assert.Equal(t, []string{
+		"public.audit is unexpected",
+		"public.posts has 24 rows instead of 25",
+		"public.tags is missing",
+	}, rowCountMismatches(before, after))
+}
@@ -165,7 +165,7 @@ func renderUsers(ctx context.Context, leaderIP string) error {
 	}
 
 	if cfg.JSONOutput {
-		return render.JSON(io.Out, users)
+		return render.Output(ctx, io.Out, users)
 	}
 
 	rows := make([][]string, len(users))
@@ -0,0 +1,136 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/command/apps"
+	"github.com/superfly/flyctl/internal/flag"
+	mach "github.com/superfly/flyctl/internal/machine"
+	"github.com/superfly/flyctl/internal/s3"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+// walArchiveSecret configures the continuous archiving of base backups and WAL
+// segments of Postgres Flex clusters with barman-cloud.
+const walArchiveSecret = "S3_ARCHIVE_CONFIG"
+
+func newWALArchive() *cobra.Command {
+	const (
+		short = "Archive the WAL of a Postgres cluster to S3"
+		long  = short + `
+
+Configures a Postgres Flex cluster to continuously archive its write-ahead log
+to an S3-compatible bucket, under the name of the app, and updates its
+machines for the configuration to take effect. The archive is what fly pg
+restore replays to restore the cluster to a point in time.
+
+The credentials of the bucket are read from the AWS_ACCESS_KEY_ID and
+AWS_SECRET_ACCESS_KEY environment variables and stored in the secrets of the
+cluster.
+`
+		usage = "wal-archive"
+	)
+
+	cmd := command.New(usage, short, long, runWALArchive,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		backupS3Flags,
+	)
+
+	return cmd
+}
+
+func runWALArchive(ctx context.Context) error {
+	var (
+		io        = iostreams.FromContext(ctx)
+		apiClient = client.FromContext(ctx).API()
+		appName   = appconfig.NameFromContext(ctx)
+	)
+
+	bucket, err := s3BucketFromFlags(ctx)
+	if err != nil {
+		return err
+	}
+	if bucket == nil {
+		return fmt.Errorf("the bucket to archive the WAL to must be specified with --s3-bucket")
+	}
+	archiveURL, err := walArchiveURL(bucket, appName)
+	if err != nil {
+		return err
+	}
+
+	app, err := apiClient.GetAppCompact(ctx, appName)
+	if err != nil {
+		return fmt.Errorf("failed retrieving app %s: %w", appName, err)
+	}
+	if !app.IsPostgresApp() || app.PlatformVersion != "machines" {
+		return fmt.Errorf("app %s is not a Postgres cluster running on machines", appName)
+	}
+
+	ctx, err = apps.BuildContext(ctx, app)
+	if err != nil {
+		return err
+	}
+
+	machines, releaseLeaseFunc, err := mach.AcquireAllLeases(ctx)
+	defer releaseLeaseFunc(ctx, machines)
+	if err != nil {
+		return err
+	}
+	if len(machines) == 0 || !IsFlex(machines[0]) {
+		return fmt.Errorf("only Postgres Flex clusters can archive their WAL")
+	}
+
+	if _, err := apiClient.SetSecrets(ctx, appName, map[string]string{walArchiveSecret: archiveURL}); err != nil {
+		return fmt.Errorf("failed setting the WAL archive configuration: %w", err)
+	}
+
+	// the leader is updated last to fail over only once
+	leader, replicas := machinesNodeRoles(ctx, machines)
+	if leader != nil {
+		replicas = append(replicas, leader)
+	}
+	for _, m := range replicas {
+		input := &api.LaunchMachineInput{
+			Region: m.Region,
+			Config: m.Config,
+		}
+		if err := mach.Update(ctx, m, input); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(io.Out, "WAL of %s is archived to %s/%s\n", appName, bucket, appName)
+	return nil
+}
+
+// walArchiveURL returns the barman-cloud configuration of the archive of the
+// cluster in the bucket.
+func walArchiveURL(b *s3.Bucket, appName string) (string, error) {
+	if b.SessionToken != "" {
+		return "", fmt.Errorf("temporary credentials can't be stored in the cluster, unset AWS_SESSION_TOKEN and use long-lived credentials")
+	}
+
+	u := url.URL{
+		Scheme: b.Endpoint.Scheme,
+		User:   url.UserPassword(b.AccessKey, b.SecretKey),
+		Host:   b.Endpoint.Host,
+		Path:   "/" + b.Name + "/" + appName,
+	}
+	return u.String(), nil
+}
@@ -0,0 +1,85 @@
+package prapps
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/superfly/graphql"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newDestroy() *cobra.Command {
+	const (
+		long = `Destroys the preview app of a pull request, along with its database
+on the shared Postgres cluster when --postgres is passed. Nothing is done when
+the pull request has no preview app.
+`
+		short = "Destroy the preview app of a pull request"
+	)
+
+	cmd := command.New("destroy", short, long, runDestroy,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.Yes(),
+		prFlag,
+		branchFlag,
+		nameTemplateFlag,
+		postgresFlag,
+	)
+
+	_ = cmd.MarkFlagRequired(prFlag.Name)
+
+	return cmd
+}
+
+func runDestroy(ctx context.Context) error {
+	var (
+		io  = iostreams.FromContext(ctx)
+		app = appconfig.NameFromContext(ctx)
+		pr  = flag.GetInt(ctx, prFlag.Name)
+	)
+
+	n, err := namerFromContext(ctx, app)
+	if err != nil {
+		return err
+	}
+	name, err := n.name(pr, flag.GetString(ctx, branchFlag.Name))
+	if err != nil {
+		return err
+	}
+
+	switch _, err := client.FromContext(ctx).API().GetAppBasic(ctx, name); {
+	case api.IsNotFoundError(err) || graphql.IsNotFoundError(err):
+		fmt.Fprintf(io.Out, "Pull request #%d has no preview app %s\n", pr, name)
+		return nil
+	case err != nil:
+		return fmt.Errorf("failed retrieving app %s: %w", name, err)
+	}
+
+	if !flag.GetYes(ctx) {
+		msg := fmt.Sprintf("Destroy the preview app %s of pull request #%d?", name, pr)
+		switch confirmed, err := prompt.Confirm(ctx, msg); {
+		case err != nil:
+			return err
+		case !confirmed:
+			return nil
+		}
+	}
+
+	return destroyPreviewApp(ctx, name, flag.GetString(ctx, postgresFlag.Name))
+}
@@ -0,0 +1,192 @@
+package prapps
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/format"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newList() *cobra.Command {
+	const (
+		long = `Lists the preview apps of the app along with the pull requests they're
+of and when they were last deployed. Pass --ttl to see which have expired.
+`
+		short = "List preview apps"
+	)
+
+	cmd := command.New("list", short, long, runList,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	cmd.Aliases = []string{"ls"}
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.Org(),
+		flag.JSONOutput(),
+		nameTemplateFlag,
+		flag.Duration{
+			Name:        "ttl",
+			Description: "How long preview apps live after their last deploy",
+		},
+	)
+
+	return cmd
+}
+
+func runList(ctx context.Context) error {
+	var (
+		io  = iostreams.FromContext(ctx)
+		app = appconfig.NameFromContext(ctx)
+		ttl = flag.GetDuration(ctx, "ttl")
+		now = time.Now()
+	)
+
+	n, err := namerFromContext(ctx, app)
+	if err != nil {
+		return err
+	}
+
+	org, err := previewOrg(ctx, app)
+	if err != nil {
+		return err
+	}
+
+	previews, err := listPreviewApps(ctx, n, org)
+	if err != nil {
+		return err
+	}
+	sort.Slice(previews, func(i, j int) bool { return previews[i].PR < previews[j].PR })
+
+	if config.FromContext(ctx).JSONOutput {
+		return render.Output(ctx, io.Out, previews)
+	}
+
+	rows := make([][]string, 0, len(previews))
+	for _, p := range previews {
+		expires := "-"
+		switch {
+		case p.expired(ttl, now):
+			expires = "expired"
+		case ttl > 0:
+			expires = format.RelativeTime(p.LastDeployed.Add(ttl))
+		}
+
+		rows = append(rows, []string{
+			p.Name,
+			"#" + strconv.Itoa(p.PR),
+			format.RelativeTime(p.LastDeployed),
+			expires,
+		})
+	}
+
+	return render.Table(io.Out, "", rows, "Name", "Pull Request", "Last Deployed", "Expires")
+}
+
+func newPrune() *cobra.Command {
+	const (
+		long = `Destroys the preview apps not deployed for longer than --ttl, along
+with their databases on the shared Postgres cluster when --postgres is
+passed. Run it on a schedule to clean up after pull requests that were never
+closed.
+`
+		short = "Destroy expired preview apps"
+	)
+
+	cmd := command.New("prune", short, long, runPrune,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.Org(),
+		nameTemplateFlag,
+		postgresFlag,
+		flag.Duration{
+			Name:        "ttl",
+			Description: "Destroy preview apps not deployed for this long",
+			Default:     72 * time.Hour,
+		},
+		flag.Bool{
+			Name:        "dry-run",
+			Description: "List the preview apps that would be destroyed, without destroying them",
+		},
+	)
+
+	return cmd
+}
+
+func runPrune(ctx context.Context) error {
+	app := appconfig.NameFromContext(ctx)
+
+	ttl := flag.GetDuration(ctx, "ttl")
+	if ttl <= 0 {
+		return fmt.Errorf("--ttl must be positive")
+	}
+
+	n, err := namerFromContext(ctx, app)
+	if err != nil {
+		return err
+	}
+
+	org, err := previewOrg(ctx, app)
+	if err != nil {
+		return err
+	}
+
+	return prune(ctx, n, org, ttl, flag.GetString(ctx, postgresFlag.Name), "", flag.GetBool(ctx, "dry-run"))
+}
+
+// prune destroys the preview apps not deployed for longer than ttl, except the
+// one named keep.
+func prune(ctx context.Context, n *namer, org *api.Organization, ttl time.Duration, pgAppName, keep string, dryRun bool) error {
+	io := iostreams.FromContext(ctx)
+	now := time.Now()
+
+	previews, err := listPreviewApps(ctx, n, org)
+	if err != nil {
+		return err
+	}
+
+	pruned := 0
+	for _, p := range previews {
+		if p.Name == keep || !p.expired(ttl, now) {
+			continue
+		}
+		pruned++
+
+		if dryRun {
+			fmt.Fprintf(io.Out, "Would destroy preview app %s of pull request #%d, last deployed %s\n", p.Name, p.PR, format.RelativeTime(p.LastDeployed))
+			continue
+		}
+		if err := destroyPreviewApp(ctx, p.Name, pgAppName); err != nil {
+			return err
+		}
+	}
+
+	if pruned == 0 {
+		fmt.Fprintf(io.Out, "No preview app was deployed more than %s ago\n", ttl)
+	}
+
+	return nil
+}
@@ -0,0 +1,277 @@
+// Package prapps implements the pr-apps command chain.
+package prapps
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/gql"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/command/postgres"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+// New initializes and returns a new pr-apps Command.
+func New() *cobra.Command {
+	const (
+		long = `The PR-APPS commands manage preview apps of pull requests: copies of
+an app, named after each pull request and deployed from its branch, which are
+destroyed once the pull request is closed or they expire.
+
+Run 'fly pr-apps sync --pr <number>' from the CI workflow of pull requests
+to create or update their preview app, 'fly pr-apps destroy --pr <number>'
+once they're closed, and 'fly pr-apps prune' on a schedule, or pass
+--prune-ttl to sync, to destroy preview apps not deployed for a while.
+
+Preview apps are named after the --name-template, {app}-pr-{pr} by default,
+where {app} is the name of the app, {pr} the number of the pull request and
+{branch} its branch. Pass --postgres to give each of them a database of
+their own on a shared Postgres cluster, which is dropped along with them.
+`
+		short = "Manage preview apps of pull requests"
+	)
+
+	cmd := command.New("pr-apps", short, long, nil)
+
+	cmd.AddCommand(
+		newSync(),
+		newDestroy(),
+		newList(),
+		newPrune(),
+	)
+
+	return cmd
+}
+
+const defaultNameTemplate = "{app}-pr-{pr}"
+
+var (
+	nameTemplateFlag = flag.String{
+		Name:        "name-template",
+		Description: "The name of preview apps, where {app} is the name of the app, {pr} the number of the pull request and {branch} its branch",
+		Default:     defaultNameTemplate,
+	}
+	postgresFlag = flag.String{
+		Name:        "postgres",
+		Description: "The shared Postgres cluster preview apps have a database of their own on",
+	}
+	prFlag = flag.Int{
+		Name:        "pr",
+		Description: "The number of the pull request",
+	}
+	branchFlag = flag.String{
+		Name:        "branch",
+		Description: "The branch of the pull request, for name templates using {branch}",
+	}
+)
+
+var appNameRE = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,61}[a-z0-9]$`)
+
+// namer names the preview apps of an app.
+type namer struct {
+	template string
+	app      string
+	re       *regexp.Regexp
+}
+
+func newNamer(template, app string) (*namer, error) {
+	if !strings.Contains(template, "{pr}") {
+		return nil, fmt.Errorf("name template %q must contain {pr}", template)
+	}
+
+	pattern := regexp.QuoteMeta(template)
+	pattern = strings.ReplaceAll(pattern, regexp.QuoteMeta("{app}"), regexp.QuoteMeta(app))
+	pattern = strings.Replace(pattern, regexp.QuoteMeta("{pr}"), "([0-9]+)", 1)
+	pattern = strings.ReplaceAll(pattern, regexp.QuoteMeta("{pr}"), "[0-9]+")
+	pattern = strings.ReplaceAll(pattern, regexp.QuoteMeta("{branch}"), "[a-z0-9-]+")
+
+	re, err := regexp.Compile("^" + pattern + "$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid name template %q: %w", template, err)
+	}
+
+	return &namer{template: template, app: app, re: re}, nil
+}
+
+// name returns the name of the preview app of the pull request.
+func (n *namer) name(pr int, branch string) (string, error) {
+	if pr <= 0 {
+		return "", fmt.Errorf("invalid pull request number %d", pr)
+	}
+
+	branch = slugify(branch)
+	if strings.Contains(n.template, "{branch}") && branch == "" {
+		return "", fmt.Errorf("name template %q uses {branch}, pass --branch", n.template)
+	}
+
+	name := strings.NewReplacer(
+		"{app}", n.app,
+		"{pr}", strconv.Itoa(pr),
+		"{branch}", branch,
+	).Replace(n.template)
+
+	if !appNameRE.MatchString(name) {
+		return "", fmt.Errorf("%q isn't a valid app name, use lowercase letters, digits and dashes, up to 63 characters", name)
+	}
+
+	return name, nil
+}
+
+// match returns the number of the pull request of the preview app name.
+func (n *namer) match(name string) (int, bool) {
+	m := n.re.FindStringSubmatch(name)
+	if m == nil {
+		return 0, false
+	}
+
+	pr, err := strconv.Atoi(m[1])
+	return pr, err == nil
+}
+
+var nonSlugRE = regexp.MustCompile(`[^a-z0-9]+`)
+
+// slugify turns a branch name into something usable in app names, e.g.
+// feature/Login-form into feature-login-form.
+func slugify(branch string) string {
+	slug := nonSlugRE.ReplaceAllString(strings.ToLower(branch), "-")
+	slug = strings.Trim(slug, "-")
+	if len(slug) > 30 {
+		slug = strings.TrimRight(slug[:30], "-")
+	}
+
+	return slug
+}
+
+func namerFromContext(ctx context.Context, app string) (*namer, error) {
+	return newNamer(flag.GetString(ctx, nameTemplateFlag.Name), app)
+}
+
+// previewOrg returns the organization preview apps live in: the one passed
+// with --org, or the one of the app.
+func previewOrg(ctx context.Context, app string) (*api.Organization, error) {
+	apiClient := client.FromContext(ctx).API()
+
+	slug := flag.GetOrg(ctx)
+	if slug == "" {
+		appCompact, err := apiClient.GetAppCompact(ctx, app)
+		if err != nil {
+			return nil, fmt.Errorf("failed retrieving app %s, pass --org to select the organization of preview apps: %w", app, err)
+		}
+		slug = appCompact.Organization.Slug
+	}
+
+	org, err := apiClient.GetOrganizationBySlug(ctx, slug)
+	if err != nil {
+		return nil, fmt.Errorf("failed retrieving organization %s: %w", slug, err)
+	}
+
+	return org, nil
+}
+
+// previewApp is a preview app of a pull request.
+type previewApp struct {
+	Name string
+	PR   int
+	// LastDeployed is when the app was last deployed, or created if it never
+	// was.
+	LastDeployed time.Time
+}
+
+// expired reports whether the app wasn't deployed for longer than ttl.
+func (p previewApp) expired(ttl time.Duration, now time.Time) bool {
+	return ttl > 0 && now.Sub(p.LastDeployed) > ttl
+}
+
+// listPreviewApps returns the preview apps of the organization.
+func listPreviewApps(ctx context.Context, n *namer, org *api.Organization) ([]previewApp, error) {
+	apiClient := client.FromContext(ctx).API()
+
+	apps, err := apiClient.GetAppsForOrganization(ctx, org.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed retrieving apps: %w", err)
+	}
+
+	_ = `# @genqlient
+	query PreviewAppActivity($appName: String!) {
+		app(name: $appName) {
+			createdAt
+			currentReleaseUnprocessed {
+				createdAt
+			}
+		}
+	}
+	`
+
+	var previews []previewApp
+	for _, app := range apps {
+		pr, ok := n.match(app.Name)
+		if !ok {
+			continue
+		}
+
+		resp, err := gql.PreviewAppActivity(ctx, apiClient.GenqClient, app.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed retrieving activity of %s: %w", app.Name, err)
+		}
+
+		p := previewApp{
+			Name:         app.Name,
+			PR:           pr,
+			LastDeployed: resp.App.CreatedAt,
+		}
+		if t := resp.App.CurrentReleaseUnprocessed.CreatedAt; t.After(p.LastDeployed) {
+			p.LastDeployed = t
+		}
+		previews = append(previews, p)
+	}
+
+	return previews, nil
+}
+
+// destroyPreviewApp destroys the app, after dropping its database on the
+// Postgres cluster when there's one. Its machines are destroyed first so that
+// none of them is connected to the database as it's dropped.
+func destroyPreviewApp(ctx context.Context, name, pgAppName string) error {
+	var (
+		io        = iostreams.FromContext(ctx)
+		apiClient = client.FromContext(ctx).API()
+	)
+
+	if pgAppName != "" {
+		flapsClient, err := flaps.NewFromAppName(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		machines, err := flapsClient.List(ctx, "")
+		if err != nil {
+			return fmt.Errorf("failed listing machines of %s: %w", name, err)
+		}
+		for _, m := range machines {
+			if err := flapsClient.Destroy(ctx, api.RemoveMachineInput{ID: m.ID, Kill: true}, ""); err != nil {
+				return fmt.Errorf("failed destroying machine %s of %s: %w", m.ID, name, err)
+			}
+		}
+
+		if err := postgres.DropAttachments(ctx, pgAppName, name); err != nil {
+			return fmt.Errorf("failed dropping the database of %s: %w", name, err)
+		}
+	}
+
+	if err := apiClient.DeleteApp(ctx, name); err != nil {
+		return fmt.Errorf("failed destroying %s: %w", name, err)
+	}
+	fmt.Fprintf(io.Out, "Destroyed preview app %s\n", name)
+
+	return nil
+}
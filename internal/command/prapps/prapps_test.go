@@ -0,0 +1,71 @@
+package prapps
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamer(t *testing.T) {
+	n, err := newNamer(defaultNameTemplate, "shop")
+	require.NoError(t, err)
+
+	name, err := n.name(123, "")
+	require.NoError(t, err)
+	assert.Equal(t, "shop-pr-123", name)
+
+	pr, ok := n.match("shop-pr-123")
+	assert.True(t, ok)
+	assert.Equal(t, 123, pr)
+
+	for _, other := range []string{"shop", "shop-pr-", "shop-pr-12a", "other-pr-1", "shop-pr-1-db"} {
+		_, ok := n.match(other)
+		assert.False(t, ok, other)
+	}
+
+	_, err = n.name(0, "")
+	assert.Error(t, err)
+}
+
+func TestNamerWithBranch(t *testing.T) {
+	n, err := newNamer("{app}-{branch}-{pr}", "shop")
+	require.NoError(t, err)
+
+	name, err := n.name(7, "feature/Login_form")
+	require.NoError(t, err)
+	assert.Equal(t, "shop-feature-login-form-7", name)
+
+	pr, ok := n.match(name)
+	assert.True(t, ok)
+	assert.Equal(t, 7, pr)
+
+	_, err = n.name(7, "")
+	assert.ErrorContains(t, err, "pass --branch")
+}
+
+func TestNamerErrors(t *testing.T) {
+	_, err := newNamer("{app}-preview", "shop")
+	assert.ErrorContains(t, err, "must contain {pr}")
+
+	n, err := newNamer("{app}_pr_{pr}", "shop")
+	require.NoError(t, err)
+	_, err = n.name(1, "")
+	assert.ErrorContains(t, err, "isn't a valid app name")
+}
+
+func TestSlugify(t *testing.T) {
+	assert.Equal(t, "fix-123-crash", slugify("Fix/#123 crash!"))
+	assert.Equal(t, "a-very-long-branch-name-that-g", slugify("a-very-long-branch-name-that-goes-on-and-on"))
+	assert.Equal(t, "", slugify("///"))
+}
+
+func TestExpired(t *testing.T) {
+	now := time.Date(2023, 5, 10, 12, 0, 0, 0, time.UTC)
+	p := previewApp{LastDeployed: now.Add(-48 * time.Hour)}
+
+	assert.True(t, p.expired(24*time.Hour, now))
+	assert.False(t, p.expired(72*time.Hour, now))
+	assert.False(t, p.expired(0, now))
+}
@@ -0,0 +1,161 @@
+package prapps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/superfly/graphql"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/command/deploy"
+	"github.com/superfly/flyctl/internal/command/postgres"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/watch"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newSync() *cobra.Command {
+	const (
+		long = `Creates the preview app of a pull request if it doesn't exist yet and
+deploys the working directory to it, using the fly.toml of the app. Preview
+apps run without spare machines unless --ha is passed. With --postgres, new
+preview apps are given a database of their own on the shared cluster.
+
+Pass --prune-ttl to also destroy the preview apps of other pull requests not
+deployed for that long.
+`
+		short = "Create or update the preview app of a pull request"
+	)
+
+	cmd := command.New("sync", short, long, runSync,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd,
+		deploy.CommonFlags,
+		flag.App(),
+		flag.AppConfig(),
+		flag.Org(),
+		prFlag,
+		branchFlag,
+		nameTemplateFlag,
+		postgresFlag,
+		flag.Duration{
+			Name:        "prune-ttl",
+			Description: "Destroy the preview apps of other pull requests not deployed for this long, e.g. 72h",
+		},
+	)
+
+	_ = cmd.MarkFlagRequired(prFlag.Name)
+
+	return cmd
+}
+
+func runSync(ctx context.Context) error {
+	var (
+		io        = iostreams.FromContext(ctx)
+		apiClient = client.FromContext(ctx).API()
+		app       = appconfig.NameFromContext(ctx)
+		pgAppName = flag.GetString(ctx, postgresFlag.Name)
+		pr        = flag.GetInt(ctx, prFlag.Name)
+	)
+
+	cfg := appconfig.ConfigFromContext(ctx)
+	if cfg == nil {
+		return errors.New("preview apps are deployed with the fly.toml of the app, which wasn't found")
+	}
+
+	n, err := namerFromContext(ctx, app)
+	if err != nil {
+		return err
+	}
+	name, err := n.name(pr, flag.GetString(ctx, branchFlag.Name))
+	if err != nil {
+		return err
+	}
+
+	org, err := previewOrg(ctx, app)
+	if err != nil {
+		return err
+	}
+
+	switch _, err := apiClient.GetAppBasic(ctx, name); {
+	case err == nil:
+		fmt.Fprintf(io.Out, "Updating preview app %s of pull request #%d\n", name, pr)
+	case api.IsNotFoundError(err) || graphql.IsNotFoundError(err):
+		input := api.CreateAppInput{
+			Name:           name,
+			OrganizationID: org.ID,
+			Machines:       true,
+		}
+		if region := flag.GetRegion(ctx); region != "" {
+			input.PreferredRegion = api.StringPointer(region)
+		} else if cfg.PrimaryRegion != "" {
+			input.PreferredRegion = api.StringPointer(cfg.PrimaryRegion)
+		}
+
+		if _, err := apiClient.CreateApp(ctx, input); err != nil {
+			return fmt.Errorf("failed creating preview app %s: %w", name, err)
+		}
+		fmt.Fprintf(io.Out, "Created preview app %s of pull request #%d in organization %s\n", name, pr, org.Slug)
+
+		if pgAppName != "" {
+			err := postgres.AttachCluster(ctx, postgres.AttachParams{
+				AppName:   name,
+				PgAppName: pgAppName,
+				Force:     true,
+			})
+			if err != nil {
+				return fmt.Errorf("failed attaching %s to the postgres cluster %s: %w", name, pgAppName, err)
+			}
+		}
+	default:
+		return fmt.Errorf("failed retrieving app %s: %w", name, err)
+	}
+
+	cfg.AppName = name
+	if err := cfg.SetMachinesPlatform(); err != nil {
+		return fmt.Errorf("failed preparing the config of %s: %w", name, err)
+	}
+
+	if !flag.IsSpecified(ctx, "ha") {
+		if err := flag.FromContext(ctx).Set("ha", "false"); err != nil {
+			return err
+		}
+	}
+
+	ctx = appconfig.WithName(ctx, name)
+	ctx = appconfig.WithConfig(ctx, cfg)
+	ctx = watch.WithTimeline(ctx, watch.NewTimeline())
+
+	flapsClient, err := flaps.NewFromAppName(ctx, name)
+	if err != nil {
+		return fmt.Errorf("could not create flaps client: %w", err)
+	}
+	ctx = flaps.NewContext(ctx, flapsClient)
+
+	err = deploy.DeployWithConfig(ctx, cfg, deploy.DeployWithConfigArgs{
+		ForceMachines: true,
+		ForceYes:      true,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(io.Out, "\nPreview app of pull request #%d: https://%s.fly.dev\n", pr, name)
+
+	if ttl := flag.GetDuration(ctx, "prune-ttl"); ttl > 0 {
+		return prune(ctx, n, org, ttl, pgAppName, name, false)
+	}
+
+	return nil
+}
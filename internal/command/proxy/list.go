@@ -0,0 +1,73 @@
+package proxy
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/agent"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newList() *cobra.Command {
+	const (
+		long = `Lists the proxies running in the background, along with their traffic
+since they started.`
+
+		short = "List background proxies"
+	)
+
+	cmd := command.New("list", short, long, runList,
+		command.RequireSession)
+
+	cmd.Aliases = []string{"ls", "status"}
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd,
+		flag.JSONOutput(),
+	)
+
+	return cmd
+}
+
+func runList(ctx context.Context) error {
+	io := iostreams.FromContext(ctx)
+
+	agentclient, err := agent.Establish(ctx, client.FromContext(ctx).API())
+	if err != nil {
+		return err
+	}
+
+	proxies, err := agentclient.Proxies(ctx)
+	if err != nil {
+		return err
+	}
+
+	if config.FromContext(ctx).JSONOutput {
+		return render.Output(ctx, io.Out, proxies)
+	}
+
+	rows := make([][]string, 0, len(proxies))
+	for _, p := range proxies {
+		rows = append(rows, []string{
+			p.ID,
+			p.Org,
+			p.Network,
+			p.Local,
+			p.Remote,
+			strconv.FormatInt(p.Connections, 10),
+			humanize.IBytes(uint64(p.BytesSent)),
+			humanize.IBytes(uint64(p.BytesReceived)),
+			humanize.Time(p.StartedAt),
+		})
+	}
+
+	return render.Table(io.Out, "", rows, "ID", "Organization", "Protocol", "Local", "Remote", "Connections", "Sent", "Received", "Started")
+}
@@ -6,7 +6,10 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/BurntSushi/toml"
 	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+
 	"github.com/superfly/flyctl/agent"
 	"github.com/superfly/flyctl/client"
 	"github.com/superfly/flyctl/internal/appconfig"
@@ -18,14 +21,36 @@ import (
 
 func New() *cobra.Command {
 	var (
-		long  = strings.Trim(`Proxies connections to a fly VM through a Wireguard tunnel The current application DNS is the default remote host`, "\n")
+		long = strings.Trim(`Proxies connections to a fly VM through a Wireguard tunnel The current application DNS is the default remote host.
+
+Ports are given as local:remote, or a single port when both are the same. Append /udp to
+forward UDP rather than TCP, like 5353:53/udp. Several ports are forwarded at once with
+--port, and --forward-config reads forwards from a TOML file:
+
+  [[forward]]
+  ports = "15432:5432"
+  host = "my-db.internal"
+
+  [[forward]]
+  ports = "9091"
+
+Forwards without a host use the remote host of the command.
+
+To keep proxies running without a terminal, start them in the background with
+fly proxy start, which registers them with the flyctl agent.`, "\n")
 		short = `Proxies connections to a fly VM`
 	)
 
-	cmd := command.New("proxy <local:remote> [remote_host]", short, long, run,
+	cmd := command.New("proxy [local:remote] [remote_host]", short, long, run,
 		command.RequireSession, command.LoadAppNameIfPresent)
 
-	cmd.Args = cobra.RangeArgs(1, 2)
+	cmd.Args = cobra.RangeArgs(0, 2)
+
+	cmd.AddCommand(
+		newStart(),
+		newStop(),
+		newList(),
+	)
 
 	flag.Add(cmd,
 		flag.App(),
@@ -42,11 +67,30 @@ func New() *cobra.Command {
 			Shorthand:   "q",
 			Description: "Don't print progress indicators for WireGuard",
 		},
+		flag.StringSlice{
+			Name:        "port",
+			Shorthand:   "p",
+			Description: "A port to forward as local:remote[/udp], can be specified multiple times",
+		},
+		flag.String{
+			Name:        "forward-config",
+			Description: "Path to a TOML file listing the forwards",
+		},
 	)
 
 	return cmd
 }
 
+// forward is a port forwarded by the proxy.
+type forward struct {
+	Ports string `toml:"ports"`
+	Host  string `toml:"host"`
+}
+
+type forwardConfig struct {
+	Forward []forward `toml:"forward"`
+}
+
 func run(ctx context.Context) (err error) {
 	client := client.FromContext(ctx).API()
 	appName := appconfig.NameFromContext(ctx)
@@ -58,28 +102,31 @@ func run(ctx context.Context) (err error) {
 		return errors.New("--app required when --select flag provided")
 	}
 
-	if orgSlug != "" {
-		_, err := client.GetOrganizationBySlug(ctx, orgSlug)
-		if err != nil {
-			return err
-		}
+	remoteHost := fmt.Sprintf("%s.internal", appName)
+	if len(args) > 1 {
+		remoteHost = args[1]
 	}
 
-	if appName == "" && orgSlug == "" {
-		org, err := prompt.Org(ctx)
-		if err != nil {
-			return err
+	var forwards []forward
+	if len(args) > 0 {
+		forwards = append(forwards, forward{Ports: args[0]})
+	}
+	for _, ports := range flag.GetStringSlice(ctx, "port") {
+		forwards = append(forwards, forward{Ports: ports})
+	}
+	if path := flag.GetString(ctx, "forward-config"); path != "" {
+		var cfg forwardConfig
+		if _, err := toml.DecodeFile(path, &cfg); err != nil {
+			return fmt.Errorf("failed reading forwards from %s: %w", path, err)
 		}
-		orgSlug = org.Slug
+		forwards = append(forwards, cfg.Forward...)
+	}
+	if len(forwards) == 0 {
+		return errors.New("specify the ports to forward as an argument, with --port or with --forward-config")
 	}
 
-	// var app *api.App
-	if appName != "" {
-		app, err := client.GetAppBasic(ctx, appName)
-		if err != nil {
-			return err
-		}
-		orgSlug = app.Organization.Slug
+	if orgSlug, err = resolveOrg(ctx, appName, orgSlug); err != nil {
+		return err
 	}
 
 	agentclient, err := agent.Establish(ctx, client)
@@ -98,21 +145,94 @@ func run(ctx context.Context) (err error) {
 		return err
 	}
 
-	ports := strings.Split(args[0], ":")
+	// prompt once for the instance all the forwards without a host go to
+	if promptInstance {
+		if remoteHost, err = proxy.SelectInstance(ctx, orgSlug, appName, agentclient); err != nil {
+			return err
+		}
+	}
+
+	params := make([]*proxy.ConnectParams, 0, len(forwards))
+	for _, f := range forwards {
+		ports, network, err := parsePorts(f.Ports)
+		if err != nil {
+			return err
+		}
+
+		p := &proxy.ConnectParams{
+			Ports:            ports,
+			AppName:          appName,
+			OrganizationSlug: orgSlug,
+			Dialer:           dialer,
+			RemoteHost:       f.Host,
+			Network:          network,
+		}
+		if p.RemoteHost == "" {
+			p.RemoteHost = remoteHost
+		}
+		params = append(params, p)
+	}
 
-	params := &proxy.ConnectParams{
-		Ports:            ports,
-		AppName:          appName,
-		OrganizationSlug: orgSlug,
-		Dialer:           dialer,
-		PromptInstance:   promptInstance,
+	if len(params) == 1 {
+		return proxy.Connect(ctx, params[0])
 	}
 
-	if len(args) > 1 {
-		params.RemoteHost = args[1]
-	} else {
-		params.RemoteHost = fmt.Sprintf("%s.internal", appName)
+	eg, ctx := errgroup.WithContext(ctx)
+	for _, p := range params {
+		p := p
+		eg.Go(func() error {
+			return proxy.Connect(ctx, p)
+		})
+	}
+	return eg.Wait()
+}
+
+// resolveOrg returns the slug of the organization of the app, or of the
+// organization given, prompting for one when neither is.
+func resolveOrg(ctx context.Context, appName, orgSlug string) (string, error) {
+	client := client.FromContext(ctx).API()
+
+	if orgSlug != "" {
+		_, err := client.GetOrganizationBySlug(ctx, orgSlug)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if appName == "" && orgSlug == "" {
+		org, err := prompt.Org(ctx)
+		if err != nil {
+			return "", err
+		}
+		orgSlug = org.Slug
 	}
 
-	return proxy.Connect(ctx, params)
+	if appName != "" {
+		app, err := client.GetAppBasic(ctx, appName)
+		if err != nil {
+			return "", err
+		}
+		orgSlug = app.Organization.Slug
+	}
+
+	return orgSlug, nil
+}
+
+// parsePorts parses a forward given as local:remote[/udp] into the ports and
+// network of the forward.
+func parsePorts(spec string) ([]string, string, error) {
+	// local ports may also be unix socket paths, which contain slashes
+	network := "tcp"
+	for _, proto := range []string{"tcp", "udp"} {
+		if strings.HasSuffix(spec, "/"+proto) {
+			network = proto
+			spec = strings.TrimSuffix(spec, "/"+proto)
+		}
+	}
+
+	ports := strings.Split(spec, ":")
+	if len(ports) > 2 || ports[0] == "" {
+		return nil, "", fmt.Errorf("invalid ports %s, must be local:remote", spec)
+	}
+	return ports, network, nil
 }
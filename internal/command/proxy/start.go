@@ -0,0 +1,105 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/agent"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newStart() *cobra.Command {
+	const (
+		long = `Starts proxies in the background. The flyctl agent forwards the ports
+through the WireGuard tunnel of the organization, so the proxies keep running
+after the terminal is closed, until they're stopped with fly proxy stop or the
+agent exits.
+
+Ports are given like with fly proxy, as local:remote[/udp]. The current
+application DNS is the default remote host.`
+
+		short = "Start proxies in the background"
+	)
+
+	cmd := command.New("start [local:remote] [remote_host]", short, long, runStart,
+		command.RequireSession, command.LoadAppNameIfPresent)
+
+	cmd.Args = cobra.RangeArgs(0, 2)
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.Org(),
+		flag.StringSlice{
+			Name:        "port",
+			Shorthand:   "p",
+			Description: "A port to forward as local:remote[/udp], can be specified multiple times",
+		},
+	)
+
+	return cmd
+}
+
+func runStart(ctx context.Context) (err error) {
+	var (
+		io      = iostreams.FromContext(ctx)
+		appName = appconfig.NameFromContext(ctx)
+		orgSlug = flag.GetString(ctx, "org")
+		args    = flag.Args(ctx)
+	)
+
+	remoteHost := fmt.Sprintf("%s.internal", appName)
+	if len(args) > 1 {
+		remoteHost = args[1]
+	} else if appName == "" {
+		return errors.New("specify the remote host or the app to proxy to")
+	}
+
+	var specs []string
+	if len(args) > 0 {
+		specs = append(specs, args[0])
+	}
+	specs = append(specs, flag.GetStringSlice(ctx, "port")...)
+	if len(specs) == 0 {
+		return errors.New("specify the ports to forward as an argument or with --port")
+	}
+
+	if orgSlug, err = resolveOrg(ctx, appName, orgSlug); err != nil {
+		return err
+	}
+
+	agentclient, err := agent.Establish(ctx, client.FromContext(ctx).API())
+	if err != nil {
+		return err
+	}
+
+	if _, err = agentclient.Establish(ctx, orgSlug); err != nil {
+		return err
+	}
+
+	for _, spec := range specs {
+		ports, network, err := parsePorts(spec)
+		if err != nil {
+			return err
+		}
+
+		remote := net.JoinHostPort(remoteHost, ports[len(ports)-1])
+
+		p, err := agentclient.StartProxy(ctx, orgSlug, network, ports[0], remote)
+		if err != nil {
+			return fmt.Errorf("failed starting proxy for %s: %w", spec, err)
+		}
+
+		fmt.Fprintf(io.Out, "Proxying %s %s to %s in the background (ID %s)\n", p.Network, p.Local, p.Remote, p.ID)
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/agent"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newStop() *cobra.Command {
+	const (
+		long = `Stops background proxies, given by their ID, local address or local port.`
+
+		short = "Stop background proxies"
+	)
+
+	cmd := command.New("stop [id|local]...", short, long, runStop,
+		command.RequireSession)
+
+	flag.Add(cmd,
+		flag.Bool{
+			Name:        "all",
+			Description: "Stop all the background proxies",
+		},
+	)
+
+	return cmd
+}
+
+func runStop(ctx context.Context) error {
+	var (
+		io   = iostreams.FromContext(ctx)
+		args = flag.Args(ctx)
+		all  = flag.GetBool(ctx, "all")
+	)
+
+	switch {
+	case all && len(args) > 0:
+		return errors.New("either specify the proxies to stop or --all, not both")
+	case !all && len(args) == 0:
+		return errors.New("specify the proxies to stop or --all")
+	}
+
+	agentclient, err := agent.Establish(ctx, client.FromContext(ctx).API())
+	if err != nil {
+		return err
+	}
+
+	proxies, err := agentclient.Proxies(ctx)
+	if err != nil {
+		return err
+	}
+
+	var stop []agent.Proxy
+	if all {
+		stop = proxies
+	} else {
+		for _, arg := range args {
+			var found bool
+			for _, p := range proxies {
+				if matchesProxy(p, arg) {
+					stop = append(stop, p)
+					found = true
+				}
+			}
+			if !found {
+				return fmt.Errorf("no background proxy matches %s", arg)
+			}
+		}
+	}
+
+	for _, p := range stop {
+		if err := agentclient.StopProxy(ctx, p.ID); err != nil {
+			return fmt.Errorf("failed stopping proxy %s: %w", p.ID, err)
+		}
+
+		fmt.Fprintf(io.Out, "Stopped proxy %s (%s %s to %s)\n", p.ID, p.Network, p.Local, p.Remote)
+	}
+
+	return nil
+}
+
+// matchesProxy reports whether the argument is the ID, the local address or
+// the local port of the proxy.
+func matchesProxy(p agent.Proxy, arg string) bool {
+	if p.ID == arg || p.Local == arg {
+		return true
+	}
+
+	_, port, err := net.SplitHostPort(p.Local)
+	return err == nil && port == arg
+}
@@ -3,32 +3,42 @@ package redis
 import (
 	"context"
 	"fmt"
+	"os"
 	"os/exec"
 	"time"
 
+	"github.com/docker/docker/pkg/ioutils"
+	"github.com/mattn/go-colorable"
 	"github.com/spf13/cobra"
 
 	"github.com/superfly/flyctl/agent"
+	"github.com/superfly/flyctl/api"
 	"github.com/superfly/flyctl/gql"
 	"github.com/superfly/flyctl/iostreams"
 	"github.com/superfly/flyctl/proxy"
 
 	"github.com/superfly/flyctl/client"
 	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/command/ssh"
 	"github.com/superfly/flyctl/internal/flag"
 	"github.com/superfly/flyctl/internal/prompt"
 )
 
 func newConnect() (cmd *cobra.Command) {
 	const (
-		long = `Connect to a Redis database using redis-cli`
+		short = `Connect to a Redis database using redis-cli`
+		long  = short + `
 
-		short = long
-		usage = "connect"
+Prompts for the Upstash Redis database to connect to when no name is given.
+Self-hosted databases are connected to with the redis-cli of their machine.
+`
+		usage = "connect [name]"
 	)
 
 	cmd = command.New(usage, short, long, runConnect, command.RequireSession)
 
+	cmd.Args = cobra.MaximumNArgs(1)
+
 	flag.Add(cmd,
 		flag.Org(),
 		flag.Region(),
@@ -43,30 +53,40 @@ func runConnect(ctx context.Context) (err error) {
 		io     = iostreams.FromContext(ctx)
 	)
 
-	if err != nil {
-		return err
-	}
+	name := flag.FirstArg(ctx)
 
-	var index int
-	var options []string
+	if name != "" {
+		ctx, app, machine, err := findSelfHosted(ctx, name)
+		if err != nil {
+			return err
+		}
+		if app != nil {
+			return connectSelfHosted(ctx, app, machine)
+		}
+	} else {
+		var index int
+		var options []string
 
-	result, err := gql.ListAddOns(ctx, client.GenqClient, "redis")
-	if err != nil {
-		return
-	}
+		result, err := gql.ListAddOns(ctx, client.GenqClient, "redis")
+		if err != nil {
+			return err
+		}
 
-	databases := result.AddOns.Nodes
+		databases := result.AddOns.Nodes
 
-	for _, database := range databases {
-		options = append(options, fmt.Sprintf("%s (%s) %s", database.Name, database.PrimaryRegion, database.Organization.Slug))
-	}
+		for _, database := range databases {
+			options = append(options, fmt.Sprintf("%s (%s) %s", database.Name, database.PrimaryRegion, database.Organization.Slug))
+		}
 
-	err = prompt.Select(ctx, &index, "Select a database to connect to", "", options...)
-	if err != nil {
-		return
+		err = prompt.Select(ctx, &index, "Select a database to connect to", "", options...)
+		if err != nil {
+			return err
+		}
+
+		name = databases[index].Name
 	}
 
-	response, err := gql.GetAddOn(ctx, client.GenqClient, databases[index].Name)
+	response, err := gql.GetAddOn(ctx, client.GenqClient, name)
 	if err != nil {
 		return err
 	}
@@ -112,3 +132,31 @@ func runConnect(ctx context.Context) (err error) {
 
 	return
 }
+
+// connectSelfHosted runs the client of the engine inside the machine, which
+// authenticates with the REDISCLI_AUTH secret.
+func connectSelfHosted(ctx context.Context, app *api.AppCompact, machine *api.Machine) error {
+	client := client.FromContext(ctx).API()
+
+	agentclient, err := agent.Establish(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	dialer, err := agentclient.Dialer(ctx, app.Organization.Slug)
+	if err != nil {
+		return fmt.Errorf("can't build tunnel for %s: %w", app.Organization.Slug, err)
+	}
+
+	return ssh.SSHConnect(&ssh.SSHParams{
+		Ctx:      ctx,
+		Org:      app.Organization,
+		Dialer:   dialer,
+		App:      app.Name,
+		Username: ssh.DefaultSshUsername,
+		Cmd:      selfHostedEngine(machine) + "-cli",
+		Stdin:    os.Stdin,
+		Stdout:   ioutils.NewWriteCloserWrapper(colorable.NewColorableStdout(), func() error { return nil }),
+		Stderr:   ioutils.NewWriteCloserWrapper(colorable.NewColorableStderr(), func() error { return nil }),
+	}, machine.PrivateIP)
+}
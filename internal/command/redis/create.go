@@ -17,6 +17,33 @@ import (
 	"github.com/superfly/flyctl/internal/spinner"
 )
 
+// createFlags are shared with the Upstash path of fly redis launch.
+var createFlags = flag.Set{
+	flag.Org(),
+	flag.Region(),
+	flag.String{
+		Name:        "name",
+		Shorthand:   "n",
+		Description: "The name of your Redis database",
+	},
+	flag.Bool{
+		Name:        "no-replicas",
+		Description: "Don't prompt for selecting replica regions",
+	},
+	flag.Bool{
+		Name:        "enable-eviction",
+		Description: "Evict objects when memory is full",
+	},
+	flag.Bool{
+		Name:        "disable-eviction",
+		Description: "Disallow writes when the max data size limit has been reached",
+	},
+	flag.String{
+		Name:        "plan",
+		Description: "Upstash Redis plan",
+	},
+}
+
 func newCreate() (cmd *cobra.Command) {
 	const (
 		long = `Create an Upstash Redis database`
@@ -27,31 +54,7 @@ func newCreate() (cmd *cobra.Command) {
 
 	cmd = command.New(usage, short, long, runCreate, command.RequireSession)
 
-	flag.Add(cmd,
-		flag.Org(),
-		flag.Region(),
-		flag.String{
-			Name:        "name",
-			Shorthand:   "n",
-			Description: "The name of your Redis database",
-		},
-		flag.Bool{
-			Name:        "no-replicas",
-			Description: "Don't prompt for selecting replica regions",
-		},
-		flag.Bool{
-			Name:        "enable-eviction",
-			Description: "Evict objects when memory is full",
-		},
-		flag.Bool{
-			Name:        "disable-eviction",
-			Description: "Disallow writes when the max data size limit has been reached",
-		},
-		flag.String{
-			Name:        "plan",
-			Description: "Upstash Redis plan",
-		},
-	)
+	flag.Add(cmd, createFlags)
 
 	return cmd
 }
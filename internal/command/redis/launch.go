@@ -0,0 +1,206 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/helpers"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	mach "github.com/superfly/flyctl/internal/machine"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/internal/watch"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newLaunch() (cmd *cobra.Command) {
+	const (
+		short = "Launch a Redis database"
+		long  = short + `
+
+Creates an Upstash Redis database, like fly redis create. With --self-hosted,
+deploys Redis or Valkey to a machine of a new app in your organization instead.
+The self-hosted server persists to a volume with RDB snapshots and an append
+only file, and is reachable by the apps of the organization on port 6379 of
+its .internal address. Metrics are scraped from port 9121 when the image ships
+redis_exporter.
+`
+		usage = "launch"
+	)
+
+	cmd = command.New(usage, short, long, runLaunch, command.RequireSession)
+
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd,
+		createFlags,
+		flag.Bool{
+			Name:        "self-hosted",
+			Description: "Deploy Redis to a machine in your organization instead of using Upstash",
+		},
+		flag.String{
+			Name:        "engine",
+			Description: "The self-hosted engine, redis or valkey",
+			Default:     "redis",
+		},
+		flag.String{
+			Name:        "image",
+			Description: "The image of the self-hosted engine, defaults to the official image of the engine",
+		},
+		flag.String{
+			Name:        "vm-size",
+			Description: "The VM size of the self-hosted machine",
+			Default:     "shared-cpu-1x",
+		},
+		flag.Int{
+			Name:        "volume-size",
+			Description: "The volume size of the self-hosted machine in GB",
+			Default:     1,
+		},
+		flag.String{
+			Name:        "maxmemory-policy",
+			Description: "The eviction policy of the self-hosted engine when memory is full",
+			Default:     "noeviction",
+		},
+	)
+
+	return cmd
+}
+
+func runLaunch(ctx context.Context) error {
+	if !flag.GetBool(ctx, "self-hosted") {
+		return runCreate(ctx)
+	}
+	return launchSelfHosted(ctx)
+}
+
+func launchSelfHosted(ctx context.Context) error {
+	var (
+		io        = iostreams.FromContext(ctx)
+		colorize  = io.ColorScheme()
+		apiClient = client.FromContext(ctx).API()
+		engine    = flag.GetString(ctx, "engine")
+		image     = flag.GetString(ctx, "image")
+	)
+
+	if _, ok := selfHostedImages[engine]; !ok {
+		return fmt.Errorf("unknown engine %s, must be redis or valkey", engine)
+	}
+	if image == "" {
+		image = selfHostedImages[engine]
+	}
+
+	guest := &api.MachineGuest{}
+	if err := guest.SetSize(flag.GetString(ctx, "vm-size")); err != nil {
+		return err
+	}
+
+	volumeSize := flag.GetInt(ctx, "volume-size")
+	if volumeSize < 1 {
+		return fmt.Errorf("the volume size must be at least 1GB")
+	}
+
+	org, err := prompt.Org(ctx)
+	if err != nil {
+		return err
+	}
+
+	name := flag.GetString(ctx, "name")
+	if name == "" {
+		if err := prompt.String(ctx, &name, "Choose an app name (leave blank to generate one):", "", false); err != nil {
+			return err
+		}
+	}
+
+	region, err := prompt.Region(ctx, !org.PaidPlan, prompt.RegionParams{
+		Message: "Choose a region",
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(io.Out, "Creating app...")
+	app, err := apiClient.CreateApp(ctx, api.CreateAppInput{
+		OrganizationID:  org.ID,
+		Name:            name,
+		PreferredRegion: &region.Code,
+	})
+	if err != nil {
+		return err
+	}
+
+	password, err := helpers.RandString(32)
+	if err != nil {
+		return err
+	}
+	// redis-cli reads REDISCLI_AUTH, which lets fly redis connect authenticate
+	// from inside the machine
+	if _, err := apiClient.SetSecrets(ctx, app.Name, map[string]string{
+		"REDIS_PASSWORD": password,
+		"REDISCLI_AUTH":  password,
+	}); err != nil {
+		return err
+	}
+
+	vol, err := apiClient.CreateVolume(ctx, api.CreateVolumeInput{
+		AppID:     app.ID,
+		Name:      selfHostedVolumeName,
+		Region:    region.Code,
+		SizeGb:    volumeSize,
+		Encrypted: true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create volume: %w", err)
+	}
+
+	flapsClient, err := flaps.New(ctx, &api.AppCompact{
+		ID:   app.ID,
+		Name: app.Name,
+		Organization: &api.OrganizationBasic{
+			ID:   app.Organization.ID,
+			Slug: app.Organization.Slug,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	ctx = flaps.NewContext(ctx, flapsClient)
+
+	fmt.Fprintf(io.Out, "Provisioning %s machine with image %s\n", engine, image)
+	machine, err := flapsClient.Launch(ctx, api.LaunchMachineInput{
+		AppID:   app.ID,
+		OrgSlug: org.ID,
+		Region:  region.Code,
+		Config: selfHostedConfig(selfHostedInput{
+			Engine:          engine,
+			Image:           image,
+			Guest:           guest,
+			VolumeID:        vol.ID,
+			MaxMemoryPolicy: flag.GetString(ctx, "maxmemory-policy"),
+		}),
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(io.Out, "Waiting for machine %s to start...\n", machine.ID)
+	if err := mach.WaitForStartOrStop(ctx, machine, "start", 5*time.Minute); err != nil {
+		return err
+	}
+	if err := watch.MachinesChecks(ctx, []*api.Machine{machine}); err != nil {
+		return fmt.Errorf("failed to wait for health checks to pass: %w", err)
+	}
+
+	fmt.Fprintf(io.Out, "\nYour self-hosted Redis %s is ready.\n", colorize.Green(app.Name))
+	fmt.Fprintf(io.Out, "Apps in the %s org can connect to %s\n", colorize.Green(org.Slug), colorize.Green(selfHostedURL(app.Name, password)))
+	fmt.Fprintln(io.Out, colorize.Italic("Save your password in a secure place -- you won't be able to see it again!"))
+	fmt.Fprintf(io.Out, "Use %s to connect to your database.\n", colorize.Green("fly redis connect "+app.Name))
+
+	return nil
+}
@@ -13,7 +13,7 @@ import (
 // TODO: make internal once the open command has been deprecated
 func New() (cmd *cobra.Command) {
 	const (
-		long  = `Launch and manage Redis databases managed by Upstash.com`
+		long  = `Launch and manage Redis databases managed by Upstash.com or self-hosted in your organization`
 		short = long
 	)
 
@@ -21,6 +21,7 @@ func New() (cmd *cobra.Command) {
 
 	cmd.AddCommand(
 		newCreate(),
+		newLaunch(),
 		newList(),
 		newDestroy(),
 		newStatus(),
@@ -0,0 +1,145 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
+)
+
+const (
+	// selfHostedMetadataKey marks the machine of a self-hosted Redis app. Its
+	// value is the engine the machine runs.
+	selfHostedMetadataKey = "fly-managed-redis"
+
+	selfHostedPort       = 6379
+	selfHostedMetricPort = 9121
+	selfHostedVolumeName = "redis_data"
+	selfHostedVolumePath = "/data"
+)
+
+// selfHostedImages are the default images of the self-hosted engines.
+var selfHostedImages = map[string]string{
+	"redis":  "redis:7.2-alpine",
+	"valkey": "valkey/valkey:7.2-alpine",
+}
+
+type selfHostedInput struct {
+	Engine          string
+	Image           string
+	Guest           *api.MachineGuest
+	VolumeID        string
+	MaxMemoryPolicy string
+}
+
+// selfHostedConfig builds the machine config of a self-hosted Redis. The server
+// persists to the volume with both RDB snapshots and an append only file, and
+// reads its password from the REDIS_PASSWORD secret. Images shipping
+// redis_exporter also serve metrics.
+func selfHostedConfig(input selfHostedInput) *api.MachineConfig {
+	// leave a quarter of the memory to the OS and the forks writing snapshots
+	maxMemory := input.Guest.MemoryMB * 3 / 4
+
+	server := []string{
+		"exec " + input.Engine + "-server",
+		"--dir " + selfHostedVolumePath,
+		"--appendonly yes",
+		"--appendfsync everysec",
+		`--save "3600 1 300 100 60 10000"`,
+		`--bind "0.0.0.0 ::"`,
+		fmt.Sprintf("--port %d", selfHostedPort),
+		fmt.Sprintf("--maxmemory %dmb", maxMemory),
+		"--maxmemory-policy " + input.MaxMemoryPolicy,
+		`--requirepass "$REDIS_PASSWORD"`,
+	}
+	script := fmt.Sprintf(`if command -v redis_exporter >/dev/null; then redis_exporter --web.listen-address=":%d" --redis.password="$REDIS_PASSWORD" & fi
+%s`, selfHostedMetricPort, strings.Join(server, " "))
+
+	config := &api.MachineConfig{
+		Image: input.Image,
+		Init: api.MachineInit{
+			Cmd: []string{"sh", "-c", script},
+		},
+		Guest: input.Guest,
+		Mounts: []api.MachineMount{{
+			Volume: input.VolumeID,
+			Path:   selfHostedVolumePath,
+		}},
+		Metrics: &api.MachineMetrics{
+			Path: "/metrics",
+			Port: selfHostedMetricPort,
+		},
+		Checks: map[string]api.MachineCheck{
+			"redis": {
+				Port:     api.Pointer(selfHostedPort),
+				Type:     api.Pointer("tcp"),
+				Interval: &api.Duration{Duration: 15 * time.Second},
+				Timeout:  &api.Duration{Duration: 10 * time.Second},
+			},
+		},
+		Metadata: map[string]string{
+			api.MachineConfigMetadataKeyFlyPlatformVersion: api.MachineFlyPlatformVersion2,
+			selfHostedMetadataKey:                          input.Engine,
+		},
+	}
+	config.Restart.Policy = api.MachineRestartPolicyAlways
+
+	return config
+}
+
+// findSelfHosted looks up the self-hosted Redis app called name. It returns a
+// nil app when there is no such app, so that name can be tried as an Upstash
+// database instead. The returned context carries a flaps client for the app.
+func findSelfHosted(ctx context.Context, name string) (context.Context, *api.AppCompact, *api.Machine, error) {
+	apiClient := client.FromContext(ctx).API()
+
+	app, err := apiClient.GetAppCompact(ctx, name)
+	switch {
+	case err != nil && strings.Contains(err.Error(), "Could not find App"):
+		return ctx, nil, nil, nil
+	case err != nil:
+		return ctx, nil, nil, fmt.Errorf("failed retrieving app %s: %w", name, err)
+	case app.PlatformVersion != "machines":
+		return ctx, nil, nil, nil
+	}
+
+	flapsClient, err := flaps.New(ctx, app)
+	if err != nil {
+		return ctx, nil, nil, err
+	}
+	ctx = flaps.NewContext(ctx, flapsClient)
+
+	machines, err := flapsClient.ListActive(ctx)
+	if err != nil {
+		return ctx, nil, nil, fmt.Errorf("machines could not be retrieved %w", err)
+	}
+
+	for _, m := range machines {
+		if selfHostedEngine(m) != "" {
+			return ctx, app, m, nil
+		}
+	}
+
+	return ctx, nil, nil, nil
+}
+
+// selfHostedEngine returns the engine a self-hosted Redis machine runs, or an
+// empty string for any other machine.
+func selfHostedEngine(m *api.Machine) string {
+	if m.Config == nil {
+		return ""
+	}
+	return m.Config.Metadata[selfHostedMetadataKey]
+}
+
+// selfHostedURL is the URL apps of the organization connect to.
+func selfHostedURL(appName, password string) string {
+	if password == "" {
+		return fmt.Sprintf("redis://%s.internal:%d", appName, selfHostedPort)
+	}
+	return fmt.Sprintf("redis://default:%s@%s.internal:%d", password, appName, selfHostedPort)
+}
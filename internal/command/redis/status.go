@@ -2,9 +2,11 @@ package redis
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/api"
 	"github.com/superfly/flyctl/client"
 	"github.com/superfly/flyctl/gql"
 	"github.com/superfly/flyctl/internal/command"
@@ -39,6 +41,14 @@ func runStatus(ctx context.Context) (err error) {
 		client = client.FromContext(ctx).API().GenqClient
 	)
 
+	ctx, app, machine, err := findSelfHosted(ctx, name)
+	if err != nil {
+		return err
+	}
+	if app != nil {
+		return statusSelfHosted(ctx, app, machine)
+	}
+
 	response, err := gql.GetAddOn(ctx, client, name)
 	if err != nil {
 		return err
@@ -80,3 +90,36 @@ func runStatus(ctx context.Context) (err error) {
 
 	return
 }
+
+func statusSelfHosted(ctx context.Context, app *api.AppCompact, machine *api.Machine) error {
+	io := iostreams.FromContext(ctx)
+
+	var volume string
+	if len(machine.Config.Mounts) > 0 {
+		volume = machine.Config.Mounts[0].Volume
+	}
+
+	var checks []string
+	for _, check := range machine.Checks {
+		checks = append(checks, fmt.Sprintf("%s: %s", check.Name, check.Status))
+	}
+
+	obj := [][]string{
+		{
+			app.ID,
+			app.Name,
+			selfHostedEngine(machine),
+			machine.FullImageRef(),
+			machine.Region,
+			machine.ID,
+			machine.State,
+			strings.Join(checks, ", "),
+			volume,
+			selfHostedURL(app.Name, ""),
+		},
+	}
+
+	cols := []string{"ID", "Name", "Engine", "Image", "Region", "Machine", "State", "Checks", "Volume", "Private URL"}
+
+	return render.VerticalTable(io.Out, "Redis (self-hosted)", obj, cols...)
+}
@@ -0,0 +1,169 @@
+package releases
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/command/deploy"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/sentry"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+// promoteReleasesLimit is how far back in the history of the source app a
+// release can be promoted from.
+const promoteReleasesLimit = 100
+
+func newPromote() *cobra.Command {
+	const (
+		short = "Promote the image of a release to another app"
+		long  = short + `
+
+Deploys the Docker image of a release of an app, e.g. a staging app, to
+another app of the same organization, e.g. the production app, without
+rebuilding it. The target app keeps its own configuration, as currently
+deployed, so only the image changes.
+`
+		usage = "promote <app> <version> --to <other-app>"
+	)
+
+	cmd := command.New(usage, short, long, runPromote,
+		command.RequireSession,
+	)
+
+	cmd.Args = cobra.ExactArgs(2)
+
+	flag.Add(cmd,
+		flag.Yes(),
+		flag.Detach(),
+		flag.String{
+			Name:        "to",
+			Description: "The app to promote the release to",
+		},
+		flag.String{
+			Name:        "strategy",
+			Description: "The strategy for replacing running instances. Options are canary, rolling, bluegreen, or immediate. Default is canary, or rolling when max-per-region is set.",
+		},
+	)
+
+	return cmd
+}
+
+func runPromote(ctx context.Context) error {
+	var (
+		io        = iostreams.FromContext(ctx)
+		apiClient = client.FromContext(ctx).API()
+		args      = flag.Args(ctx)
+		fromName  = args[0]
+		toName    = flag.GetString(ctx, "to")
+	)
+
+	if toName == "" {
+		return fmt.Errorf("the app to promote the release to must be specified with --to")
+	}
+	if toName == fromName {
+		return fmt.Errorf("can't promote a release of %s to itself", fromName)
+	}
+
+	version, err := strconv.Atoi(strings.TrimPrefix(args[1], "v"))
+	if err != nil {
+		return fmt.Errorf("invalid release version %s", args[1])
+	}
+
+	fromApp, err := apiClient.GetAppCompact(ctx, fromName)
+	if err != nil {
+		return fmt.Errorf("failed retrieving app %s: %w", fromName, err)
+	}
+	toApp, err := apiClient.GetAppCompact(ctx, toName)
+	if err != nil {
+		return fmt.Errorf("failed retrieving app %s: %w", toName, err)
+	}
+	if fromApp.Organization.ID != toApp.Organization.ID {
+		return fmt.Errorf("apps %s and %s must belong to the same organization", fromName, toName)
+	}
+	if toApp.PlatformVersion != "machines" {
+		return fmt.Errorf("releases can only be promoted to apps running on machines")
+	}
+
+	release, err := findRelease(ctx, apiClient, fromApp, version)
+	if err != nil {
+		return err
+	}
+	if release.ImageRef == "" {
+		return fmt.Errorf("release v%d of %s has no image to promote", release.Version, fromName)
+	}
+
+	switch confirmed, err := confirm(ctx, "Deploy image %s of release v%d of %s to %s?", release.ImageRef, release.Version, fromName, toName); {
+	case err != nil:
+		return err
+	case !confirmed:
+		return nil
+	}
+
+	flapsClient, err := flaps.New(ctx, toApp)
+	if err != nil {
+		return fmt.Errorf("could not create flaps client: %w", err)
+	}
+	ctx = flaps.NewContext(ctx, flapsClient)
+
+	// The image is the only thing being promoted, the target app keeps
+	// running with the config it was last deployed with
+	cfg, err := appconfig.FromRemoteApp(ctx, toName)
+	if err != nil {
+		return err
+	}
+	ctx = appconfig.WithName(ctx, toName)
+	ctx = appconfig.WithConfig(ctx, cfg)
+
+	fmt.Fprintf(io.Out, "Promoting release v%d of %s (%s) to %s\n", release.Version, fromName, release.ImageRef, toName)
+
+	md, err := deploy.NewMachineDeployment(ctx, deploy.MachineDeploymentArgs{
+		AppCompact:       toApp,
+		DeploymentImage:  release.ImageRef,
+		Strategy:         flag.GetString(ctx, "strategy"),
+		SkipHealthChecks: flag.GetDetach(ctx),
+	})
+	if err != nil {
+		sentry.CaptureExceptionWithAppInfo(err, "releases-promote", toApp)
+		return err
+	}
+
+	if err := md.DeployMachinesApp(ctx); err != nil {
+		sentry.CaptureExceptionWithAppInfo(err, "releases-promote", toApp)
+		return err
+	}
+
+	return nil
+}
+
+func findRelease(ctx context.Context, apiClient *api.Client, app *api.AppCompact, version int) (*api.Release, error) {
+	var (
+		releases []api.Release
+		err      error
+	)
+	if app.PlatformVersion == "machines" {
+		releases, err = apiClient.GetAppReleasesMachines(ctx, app.Name, promoteReleasesLimit)
+	} else {
+		releases, err = apiClient.GetAppReleasesNomad(ctx, app.Name, promoteReleasesLimit)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed retrieving app releases %s: %w", app.Name, err)
+	}
+
+	for _, r := range releases {
+		if r.Version == version {
+			return &r, nil
+		}
+	}
+
+	return nil, fmt.Errorf("release v%d of %s not found among its last %d releases", version, app.Name, promoteReleasesLimit)
+}
@@ -1,12 +1,38 @@
 package releases
 
 import (
+	"context"
+
 	"github.com/spf13/cobra"
 
 	"github.com/superfly/flyctl/internal/command/apps"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/prompt"
 )
 
 // TODO: deprecate
 func New() *cobra.Command {
-	return apps.NewReleases()
+	cmd := apps.NewReleases()
+
+	cmd.AddCommand(
+		newPromote(),
+		newRollback(),
+	)
+
+	return cmd
+}
+
+func confirm(ctx context.Context, format string, a ...interface{}) (bool, error) {
+	if flag.GetYes(ctx) {
+		return true, nil
+	}
+
+	switch confirmed, err := prompt.Confirmf(ctx, format, a...); {
+	case err == nil:
+		return confirmed, nil
+	case prompt.IsNonInteractive(err):
+		return false, prompt.NonInteractiveError("yes flag must be specified when not running interactively")
+	default:
+		return false, err
+	}
 }
@@ -0,0 +1,179 @@
+package releases
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/command/deploy"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/sentry"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+// rollbackReleasesLimit is how far back in the history of the app a release
+// can be rolled back to.
+const rollbackReleasesLimit = 100
+
+func newRollback() *cobra.Command {
+	const (
+		short = "Roll back to a previous release"
+		long  = short + `
+
+Deploys the Docker image and the app config of a previous release again,
+updating the machines of every process group with the same health check
+waiting as fly deploy. Without a version, rolls back to the latest successful
+release before the current one.
+`
+		usage = "rollback [<version>]"
+	)
+
+	cmd := command.New(usage, short, long, runRollback,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	cmd.Args = cobra.MaximumNArgs(1)
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.Yes(),
+		flag.Detach(),
+		flag.String{
+			Name:        "strategy",
+			Description: "The strategy for replacing running instances. Options are canary, rolling, bluegreen, or immediate.",
+			Default:     "rolling",
+		},
+	)
+
+	return cmd
+}
+
+func runRollback(ctx context.Context) error {
+	var (
+		io        = iostreams.FromContext(ctx)
+		apiClient = client.FromContext(ctx).API()
+		appName   = appconfig.NameFromContext(ctx)
+	)
+
+	app, err := apiClient.GetAppCompact(ctx, appName)
+	if err != nil {
+		return fmt.Errorf("failed retrieving app %s: %w", appName, err)
+	}
+	if app.PlatformVersion != "machines" {
+		return fmt.Errorf("only apps running on machines can be rolled back, use fly deploy --image for others")
+	}
+
+	version, err := rollbackVersion(ctx, apiClient, appName)
+	if err != nil {
+		return err
+	}
+
+	release, err := apiClient.GetAppReleaseMachinesByVersion(ctx, appName, version, rollbackReleasesLimit)
+	if err != nil {
+		return fmt.Errorf("failed retrieving release v%d: %w", version, err)
+	}
+	if release.ImageRef == "" {
+		return fmt.Errorf("release v%d has no image to roll back to", release.Version)
+	}
+
+	flapsClient, err := flaps.New(ctx, app)
+	if err != nil {
+		return fmt.Errorf("could not create flaps client: %w", err)
+	}
+	ctx = flaps.NewContext(ctx, flapsClient)
+
+	cfg, err := releaseConfig(ctx, release, appName)
+	if err != nil {
+		return err
+	}
+	ctx = appconfig.WithConfig(ctx, cfg)
+
+	switch confirmed, err := confirm(ctx, "Roll back %s to release v%d (%s)?", appName, release.Version, release.ImageRef); {
+	case err != nil:
+		return err
+	case !confirmed:
+		return nil
+	}
+
+	fmt.Fprintf(io.Out, "Rolling back %s to release v%d\n", appName, release.Version)
+
+	md, err := deploy.NewMachineDeployment(ctx, deploy.MachineDeploymentArgs{
+		AppCompact:       app,
+		DeploymentImage:  release.ImageRef,
+		Strategy:         flag.GetString(ctx, "strategy"),
+		SkipHealthChecks: flag.GetDetach(ctx),
+	})
+	if err != nil {
+		sentry.CaptureExceptionWithAppInfo(err, "releases-rollback", app)
+		return err
+	}
+
+	if err := md.DeployMachinesApp(ctx); err != nil {
+		sentry.CaptureExceptionWithAppInfo(err, "releases-rollback", app)
+		return err
+	}
+
+	return nil
+}
+
+// rollbackVersion returns the version given as argument, or else the latest
+// successful release before the current one.
+func rollbackVersion(ctx context.Context, apiClient *api.Client, appName string) (int, error) {
+	if arg := flag.FirstArg(ctx); arg != "" {
+		version, err := strconv.Atoi(strings.TrimPrefix(arg, "v"))
+		if err != nil {
+			return 0, fmt.Errorf("invalid release version %s", arg)
+		}
+		return version, nil
+	}
+
+	releases, err := apiClient.GetAppReleasesMachines(ctx, appName, rollbackReleasesLimit)
+	if err != nil {
+		return 0, fmt.Errorf("failed retrieving app releases %s: %w", appName, err)
+	}
+
+	sort.Slice(releases, func(i, j int) bool {
+		return releases[i].Version > releases[j].Version
+	})
+
+	if len(releases) > 0 {
+		for _, r := range releases[1:] {
+			if r.Status == "complete" && r.ImageRef != "" {
+				return r.Version, nil
+			}
+		}
+	}
+
+	return 0, fmt.Errorf("no successful release before the current one to roll back to")
+}
+
+// releaseConfig returns the app config the release was deployed with. Releases
+// which didn't record it are rolled back with the current app config.
+func releaseConfig(ctx context.Context, release *api.Release, appName string) (*appconfig.Config, error) {
+	if release.ConfigDefinition == nil {
+		fmt.Fprintf(iostreams.FromContext(ctx).ErrOut, "Release v%d has no app config recorded, only its image will be rolled back\n", release.Version)
+		return appconfig.FromRemoteApp(ctx, appName)
+	}
+
+	cfg, err := appconfig.FromDefinition(release.ConfigDefinition)
+	if err != nil {
+		return nil, fmt.Errorf("error creating app config of release v%d: %w", release.Version, err)
+	}
+	if err := cfg.SetMachinesPlatform(); err != nil {
+		return nil, err
+	}
+	cfg.AppName = appName
+
+	return cfg, nil
+}
@@ -10,8 +10,13 @@ import (
 	"github.com/superfly/flyctl/internal/command"
 	"github.com/superfly/flyctl/internal/command/agent"
 	"github.com/superfly/flyctl/internal/command/apps"
+	"github.com/superfly/flyctl/internal/command/apps/clone"
+	"github.com/superfly/flyctl/internal/command/attach"
 	"github.com/superfly/flyctl/internal/command/auth"
+	"github.com/superfly/flyctl/internal/command/autoscale"
+	"github.com/superfly/flyctl/internal/command/billing"
 	"github.com/superfly/flyctl/internal/command/checks"
+	"github.com/superfly/flyctl/internal/command/ci"
 	"github.com/superfly/flyctl/internal/command/config"
 	"github.com/superfly/flyctl/internal/command/consul"
 	"github.com/superfly/flyctl/internal/command/create"
@@ -19,6 +24,7 @@ import (
 	"github.com/superfly/flyctl/internal/command/deploy"
 	"github.com/superfly/flyctl/internal/command/destroy"
 	"github.com/superfly/flyctl/internal/command/dig"
+	"github.com/superfly/flyctl/internal/command/dns"
 	"github.com/superfly/flyctl/internal/command/docs"
 	"github.com/superfly/flyctl/internal/command/doctor"
 	"github.com/superfly/flyctl/internal/command/extensions"
@@ -26,19 +32,23 @@ import (
 	"github.com/superfly/flyctl/internal/command/history"
 	"github.com/superfly/flyctl/internal/command/image"
 	"github.com/superfly/flyctl/internal/command/info"
+	"github.com/superfly/flyctl/internal/command/infra"
 	"github.com/superfly/flyctl/internal/command/ips"
 	"github.com/superfly/flyctl/internal/command/jobs"
 	"github.com/superfly/flyctl/internal/command/launch"
 	"github.com/superfly/flyctl/internal/command/logs"
 	"github.com/superfly/flyctl/internal/command/machine"
+	"github.com/superfly/flyctl/internal/command/metrics"
 	"github.com/superfly/flyctl/internal/command/migrate_to_v2"
 	"github.com/superfly/flyctl/internal/command/monitor"
 	"github.com/superfly/flyctl/internal/command/move"
+	"github.com/superfly/flyctl/internal/command/mysql"
 	"github.com/superfly/flyctl/internal/command/open"
 	"github.com/superfly/flyctl/internal/command/orgs"
 	"github.com/superfly/flyctl/internal/command/ping"
 	"github.com/superfly/flyctl/internal/command/platform"
 	"github.com/superfly/flyctl/internal/command/postgres"
+	"github.com/superfly/flyctl/internal/command/prapps"
 	"github.com/superfly/flyctl/internal/command/proxy"
 	"github.com/superfly/flyctl/internal/command/redis"
 	"github.com/superfly/flyctl/internal/command/releases"
@@ -55,6 +65,7 @@ import (
 	"github.com/superfly/flyctl/internal/command/version"
 	"github.com/superfly/flyctl/internal/command/vm"
 	"github.com/superfly/flyctl/internal/command/volumes"
+	"github.com/superfly/flyctl/internal/completion"
 )
 
 // New initializes and returns a reference to a new root command.
@@ -127,10 +138,14 @@ func New() *cobra.Command {
 	// already. the commented out code above, is what should remain after the
 	// migration is complete.
 
+	// apps clone attaches Postgres clusters, whose commands depend on apps
+	appsCmd := apps.New()
+	appsCmd.AddCommand(clone.New())
+
 	// newCommands is the set of commands which work with the new way
 	newCommands := []*cobra.Command{
 		version.New(),
-		apps.New(),
+		appsCmd,
 		create.New(),  // TODO: deprecate
 		destroy.New(), // TODO: deprecate
 		move.New(),    // TODO: deprecate
@@ -148,8 +163,12 @@ func New() *cobra.Command {
 		history.New(),
 		status.New(),
 		logs.New(),
+		metrics.New(),
+		billing.New(),
+		infra.New(),
 		doctor.New(),
 		dig.New(),
+		dns.New(),
 		volumes.New(),
 		agent.New(),
 		image.New(),
@@ -158,6 +177,8 @@ func New() *cobra.Command {
 		machine.New(),
 		monitor.New(),
 		postgres.New(),
+		mysql.New(),
+		attach.New(),
 		ips.New(),
 		secrets.New(),
 		ssh.New(),
@@ -165,6 +186,8 @@ func New() *cobra.Command {
 		redis.New(),
 		vm.New(),
 		checks.New(),
+		ci.New(),
+		prapps.New(),
 		launch.New(),
 		info.New(),
 		jobs.New(),
@@ -176,6 +199,7 @@ func New() *cobra.Command {
 		tokens.New(),
 		extensions.New(),
 		consul.New(),
+		autoscale.New(),
 	}
 
 	// if os.Getenv("DEV") != "" {
@@ -215,6 +239,8 @@ func New() *cobra.Command {
 
 	root.RunE = help.NewRootHelp().RunE
 
+	completion.Register(root)
+
 	return root
 }
 
@@ -32,6 +32,7 @@ For pricing, see https://fly.io/docs/about/pricing/`
 		flag.Yes(),
 		flag.Int{Name: "max-per-region", Description: "Max number of VMs per region", Default: -1},
 		flag.String{Name: "region", Description: "Comma separated list of regions to act on. Defaults to all regions where there is at least one machine running for the app"},
+		flag.Bool{Name: "fork-volumes", Description: "Create volumes for new machines in regions without an unattached volume from the latest snapshot of the nearest volume. (Machines only)"},
 	)
 	return cmd
 }
@@ -8,10 +8,14 @@ import (
 
 	"github.com/samber/lo"
 	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
 	"github.com/superfly/flyctl/flaps"
 	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/config"
 	"github.com/superfly/flyctl/internal/flag"
 	mach "github.com/superfly/flyctl/internal/machine"
+	"github.com/superfly/flyctl/internal/policy"
+	"github.com/superfly/flyctl/internal/progress"
 	"github.com/superfly/flyctl/internal/prompt"
 	"github.com/superfly/flyctl/iostreams"
 	"golang.org/x/exp/maps"
@@ -19,6 +23,8 @@ import (
 )
 
 func runMachinesScaleCount(ctx context.Context, appName string, expectedGroupCounts map[string]int, maxPerRegion int) error {
+	// stdout holds the progress events when they're streamed
+	ctx = progress.SeparateOutput(ctx)
 	io := iostreams.FromContext(ctx)
 
 	flapsClient, err := flaps.NewFromAppName(ctx, appName)
@@ -73,6 +79,13 @@ func runMachinesScaleCount(ctx context.Context, appName string, expectedGroupCou
 		return nil
 	}
 
+	err = checkPolicy(ctx, appName, func(p *policy.Policy) []string {
+		return countViolations(p, appName, machines, actions, policy.ServingGroups(appConfig))
+	})
+	if err != nil {
+		return err
+	}
+
 	fmt.Fprintf(io.Out, "App '%s' is going to be scaled according to this plan:\n", appName)
 
 	needsVolumes := map[string]bool{}
@@ -84,14 +97,24 @@ func runMachinesScaleCount(ctx context.Context, appName string, expectedGroupCou
 		}
 	}
 
+	var volumes *volumeProvider
 	if len(needsVolumes) > 0 {
 		groupNames := maps.Keys(needsVolumes)
 		slices.Sort(groupNames)
-		return fmt.Errorf(
-			"'fly scale count' can't scale up groups with mounts, "+
-				"use 'fly machine clone' to add machines for: %s",
-			strings.Join(groupNames, " "),
-		)
+		fmt.Fprintf(io.Out, "Groups with mounts need a volume per new machine: %s\n", strings.Join(groupNames, " "))
+
+		app, err := client.FromContext(ctx).API().GetAppCompact(ctx, appName)
+		if err != nil {
+			return err
+		}
+		if volumes, err = newVolumeProvider(ctx, app, flag.GetBool(ctx, "fork-volumes")); err != nil {
+			return err
+		}
+	}
+
+	if config.FromContext(ctx).DryRun {
+		fmt.Fprintln(io.Out, "Dry run: the scale plan above wasn't executed")
+		return nil
 	}
 
 	if !flag.GetYes(ctx) {
@@ -107,24 +130,45 @@ func runMachinesScaleCount(ctx context.Context, appName string, expectedGroupCou
 		}
 	}
 
+	var done, total int
+	for _, action := range actions {
+		if action.Delta < 0 {
+			total -= action.Delta
+		} else {
+			total += action.Delta
+		}
+	}
+
 	fmt.Fprintf(io.Out, "Executing scale plan\n")
 	for _, action := range actions {
 		switch {
 		case action.Delta > 0:
 			for i := 0; i < action.Delta; i++ {
-				m, err := launchMachine(ctx, action)
+				event := progress.Event{Stage: progress.StageLaunchMachine, Message: "group " + action.GroupName + " region " + action.Region, Percent: progress.Percent(done, total)}
+				progress.Publish(ctx, event.WithStatus(progress.StatusStarted))
+				m, err := launchMachine(ctx, action, volumes)
 				if err != nil {
+					progress.Fail(ctx, event, err)
 					return err
 				}
+				done++
+				event.MachineID, event.Percent = m.ID, progress.Percent(done, total)
+				progress.Publish(ctx, event.WithStatus(progress.StatusCompleted))
 				fmt.Fprintf(io.Out, "  Created %s group:%s region:%s size:%s\n", m.ID, action.GroupName, action.Region, m.Config.Guest.ToSize())
 			}
 		case action.Delta < 0:
 			for i := 0; i > action.Delta; i-- {
 				m := action.Machines[-i]
+				event := progress.Event{Stage: progress.StageDestroyMachine, MachineID: m.ID, Percent: progress.Percent(done, total)}
+				progress.Publish(ctx, event.WithStatus(progress.StatusStarted))
 				err := destroyMachine(ctx, m)
 				if err != nil {
+					progress.Fail(ctx, event, err)
 					return err
 				}
+				done++
+				event.Percent = progress.Percent(done, total)
+				progress.Publish(ctx, event.WithStatus(progress.StatusCompleted))
 				fmt.Fprintf(io.Out, "  Destroyed %s group:%s region:%s size:%s\n", m.ID, action.GroupName, action.Region, m.Config.Guest.ToSize())
 			}
 		}
@@ -133,7 +177,7 @@ func runMachinesScaleCount(ctx context.Context, appName string, expectedGroupCou
 	return nil
 }
 
-func launchMachine(ctx context.Context, action *planItem) (*api.Machine, error) {
+func launchMachine(ctx context.Context, action *planItem, volumes *volumeProvider) (*api.Machine, error) {
 	appName := appconfig.NameFromContext(ctx)
 	flapsClient := flaps.FromContext(ctx)
 
@@ -143,6 +187,17 @@ func launchMachine(ctx context.Context, action *planItem) (*api.Machine, error)
 		Config: action.MachineConfig,
 	}
 
+	if len(action.MachineConfig.Mounts) > 0 {
+		vol, err := volumes.volumeFor(ctx, action.MachineConfig.Mounts[0], action.Region)
+		if err != nil {
+			return nil, err
+		}
+
+		input.Config = mach.CloneConfig(action.MachineConfig)
+		input.Config.Mounts[0].Volume = vol.ID
+		input.Config.Mounts[0].Name = vol.Name
+	}
+
 	m, err := flapsClient.Launch(ctx, input)
 	if err != nil {
 		return nil, fmt.Errorf("could not launch machine: %w", err)
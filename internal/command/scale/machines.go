@@ -9,6 +9,7 @@ import (
 	"github.com/superfly/flyctl/flaps"
 	"github.com/superfly/flyctl/internal/appconfig"
 	mach "github.com/superfly/flyctl/internal/machine"
+	"github.com/superfly/flyctl/internal/policy"
 )
 
 func v2ScaleVM(ctx context.Context, appName, group, sizeName string, memoryMB int) (*api.VMSize, error) {
@@ -48,6 +49,23 @@ func v2ScaleVM(ctx context.Context, appName, group, sizeName string, memoryMB in
 		return nil, err
 	}
 
+	err = checkPolicy(ctx, appName, func(p *policy.Policy) (violations []string) {
+		for _, machine := range machines {
+			guest := *machine.Config.Guest
+			if sizeName != "" {
+				guest.SetSize(sizeName)
+			}
+			if memoryMB > 0 {
+				guest.MemoryMB = memoryMB
+			}
+			violations = append(violations, p.MachineViolations(machine.Region, &guest)...)
+		}
+		return violations
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	for _, machine := range machines {
 		if sizeName != "" {
 			machine.Config.Guest.SetSize(sizeName)
@@ -0,0 +1,51 @@
+package scale
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/policy"
+)
+
+// checkPolicy returns an error if the policy of the organization of the app
+// reports violations.
+func checkPolicy(ctx context.Context, appName string, violations func(p *policy.Policy) []string) error {
+	app, err := client.FromContext(ctx).API().GetAppCompact(ctx, appName)
+	if err != nil {
+		return fmt.Errorf("failed retrieving app %s: %w", appName, err)
+	}
+
+	p, err := policy.Load(ctx, app.Organization.Slug)
+	if err != nil || p == nil {
+		return err
+	}
+
+	return p.Error(violations(p))
+}
+
+// countViolations returns why the machines left by the actions violate the
+// policy. Only the counts of the serving groups the actions change are
+// checked, so that scaling a group isn't refused over the counts of others.
+func countViolations(p *policy.Policy, appName string, machines []*api.Machine, actions []*planItem, serving map[string]bool) []string {
+	var violations []string
+
+	counts := map[string]int{}
+	for _, action := range actions {
+		if action.Delta > 0 {
+			violations = append(violations, p.MachineViolations(action.Region, action.MachineConfig.Guest)...)
+		}
+		if serving[action.GroupName] {
+			counts[action.GroupName] += action.Delta
+		}
+	}
+
+	for _, m := range machines {
+		if _, ok := counts[m.ProcessGroup()]; ok {
+			counts[m.ProcessGroup()]++
+		}
+	}
+
+	return append(violations, p.CountViolations(appName, counts)...)
+}
@@ -0,0 +1,39 @@
+package scale
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/internal/policy"
+)
+
+func TestCountViolations(t *testing.T) {
+	p := &policy.Policy{Org: "acme", AllowedRegions: []string{"ams"}, MinMachines: 2}
+
+	groupMachine := func(group string) *api.Machine {
+		return &api.Machine{Region: "ams", Config: &api.MachineConfig{
+			Metadata: map[string]string{api.MachineConfigMetadataKeyFlyProcessGroup: group},
+		}}
+	}
+	machines := []*api.Machine{groupMachine("app"), groupMachine("app"), groupMachine("worker")}
+	serving := map[string]bool{"app": true}
+	guest := &api.MachineConfig{Guest: &api.MachineGuest{CPUKind: "shared", CPUs: 1, MemoryMB: 256}}
+
+	assert.Empty(t, countViolations(p, "my-app", machines, []*planItem{
+		{GroupName: "app", Region: "ams", Delta: 1, MachineConfig: guest},
+		{GroupName: "worker", Region: "ams", Delta: -1, MachineConfig: guest},
+	}, serving))
+
+	assert.Equal(t, []string{
+		"process group app of production app my-app would run 1 machines, it must run at least 2",
+	}, countViolations(p, "my-app", machines, []*planItem{
+		{GroupName: "app", Region: "ams", Delta: -1, MachineConfig: guest},
+	}, serving))
+
+	assert.Equal(t, []string{
+		"region ord is not allowed, machines must run in ams",
+	}, countViolations(p, "my-app", machines, []*planItem{
+		{GroupName: "worker", Region: "ord", Delta: 1, MachineConfig: guest},
+	}, serving))
+}
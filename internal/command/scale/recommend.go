@@ -0,0 +1,143 @@
+package scale
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/samber/lo"
+	"golang.org/x/exp/slices"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/internal/prometheus"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+const (
+	// recommendationWindow is how far back usage is looked at, as a PromQL
+	// duration.
+	recommendationWindow = "24h"
+	// recommendationHeadroom is the margin kept above the p95 usage.
+	recommendationHeadroom = 1.25
+)
+
+// groupUsage is the p95 usage of the busiest machine of a process group.
+type groupUsage struct {
+	// CPUs is the number of CPUs busy.
+	CPUs     float64
+	MemoryMB float64
+}
+
+// printRecommendations suggests a guest size for each process group, from the
+// CPU and memory usage of their machines reported by the metrics API.
+func printRecommendations(ctx context.Context, app *api.AppCompact, groupNames []string, machineGroups map[string][]*api.Machine) error {
+	io := iostreams.FromContext(ctx)
+
+	selector := fmt.Sprintf(`app=%q`, app.Name)
+	metrics := prometheus.New(ctx, app.Organization.Slug)
+
+	memory, err := metrics.ValuesBy(ctx, fmt.Sprintf(
+		`max by (instance) (quantile_over_time(0.95, (fly_instance_memory_mem_total{%[1]s} - fly_instance_memory_mem_available{%[1]s})[%[2]s:1m]))`,
+		selector, recommendationWindow), "instance")
+	if err != nil {
+		return fmt.Errorf("failed querying memory usage: %w", err)
+	}
+
+	cpu, err := metrics.ValuesBy(ctx, fmt.Sprintf(
+		`max by (instance) (quantile_over_time(0.95, (sum by (instance) (rate(fly_instance_cpu{%s,mode!="idle"}[1m])) / 100)[%s:1m]))`,
+		selector, recommendationWindow), "instance")
+	if err != nil {
+		return fmt.Errorf("failed querying CPU usage: %w", err)
+	}
+
+	fmt.Fprintf(io.Out, "\nRecommendations, from the p95 usage of the last %s:\n", recommendationWindow)
+
+	for _, groupName := range groupNames {
+		machines := machineGroups[groupName]
+		guest := machines[0].Config.Guest
+
+		usage, ok := usageOf(machines, cpu, memory)
+		if !ok || guest == nil {
+			fmt.Fprintf(io.Out, "  %s: no metrics reported\n", groupName)
+			continue
+		}
+
+		summary := fmt.Sprintf("%s group p95 memory %.0fMB of %dMB, p95 CPU %.2f of %d %s CPUs",
+			groupName, usage.MemoryMB, guest.MemoryMB, usage.CPUs, guest.CPUs, guest.CPUKind)
+
+		rec := recommendGuest(guest, usage)
+		if rec.CPUs == guest.CPUs && rec.MemoryMB == guest.MemoryMB {
+			fmt.Fprintf(io.Out, "  %s: sized appropriately\n", summary)
+		} else {
+			fmt.Fprintf(io.Out, "  %s: consider %s/%dMB\n", summary, rec.ToSize(), rec.MemoryMB)
+		}
+	}
+
+	return nil
+}
+
+// usageOf returns the usage of the busiest of machines, and whether any of
+// them reported metrics.
+func usageOf(machines []*api.Machine, cpu, memory map[string]float64) (groupUsage, bool) {
+	var (
+		usage groupUsage
+		found bool
+	)
+
+	for _, m := range machines {
+		if v, ok := cpu[m.ID]; ok {
+			usage.CPUs = math.Max(usage.CPUs, v)
+			found = true
+		}
+		if v, ok := memory[m.ID]; ok {
+			usage.MemoryMB = math.Max(usage.MemoryMB, v/(1024*1024))
+			found = true
+		}
+	}
+
+	return usage, found
+}
+
+// recommendGuest returns the smallest guest of the same CPU kind fitting the
+// usage given, with some headroom. CPUs and memory are powers of two, within
+// the memory allowed per CPU.
+func recommendGuest(current *api.MachineGuest, usage groupUsage) *api.MachineGuest {
+	minPerCPU, maxPerCPU := api.MIN_MEMORY_MB_PER_SHARED_CPU, api.MAX_MEMORY_MB_PER_SHARED_CPU
+	if current.CPUKind == "performance" {
+		minPerCPU, maxPerCPU = api.MIN_MEMORY_MB_PER_CPU, api.MAX_MEMORY_MB_PER_CPU
+	}
+
+	sizes := lo.Uniq(lo.FilterMap(lo.Values(api.MachinePresets), func(g *api.MachineGuest, _ int) (int, bool) {
+		return g.CPUs, g.CPUKind == current.CPUKind
+	}))
+	slices.Sort(sizes)
+	if len(sizes) == 0 {
+		return current
+	}
+
+	memoryMB := api.MIN_MEMORY_MB_PER_SHARED_CPU
+	for float64(memoryMB) < usage.MemoryMB*recommendationHeadroom {
+		memoryMB *= 2
+	}
+
+	cpus := sizes[len(sizes)-1]
+	for _, size := range sizes {
+		if float64(size) >= usage.CPUs*recommendationHeadroom && size*maxPerCPU >= memoryMB {
+			cpus = size
+			break
+		}
+	}
+
+	if memoryMB < cpus*minPerCPU {
+		memoryMB = cpus * minPerCPU
+	}
+	if memoryMB > cpus*maxPerCPU {
+		memoryMB = cpus * maxPerCPU
+	}
+
+	return &api.MachineGuest{
+		CPUKind:  current.CPUKind,
+		CPUs:     cpus,
+		MemoryMB: memoryMB,
+	}
+}
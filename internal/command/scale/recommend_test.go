@@ -0,0 +1,71 @@
+package scale
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/superfly/flyctl/api"
+)
+
+func Test_recommendGuest(t *testing.T) {
+	shared := &api.MachineGuest{CPUKind: "shared", CPUs: 1, MemoryMB: 2048}
+	performance := &api.MachineGuest{CPUKind: "performance", CPUs: 4, MemoryMB: 8192}
+
+	cases := []struct {
+		name    string
+		current *api.MachineGuest
+		usage   groupUsage
+		want    *api.MachineGuest
+	}{
+		{
+			name:    "idle shared",
+			current: shared,
+			usage:   groupUsage{CPUs: 0.05, MemoryMB: 180},
+			want:    &api.MachineGuest{CPUKind: "shared", CPUs: 1, MemoryMB: 256},
+		},
+		{
+			name:    "memory headroom",
+			current: shared,
+			usage:   groupUsage{CPUs: 0.1, MemoryMB: 450},
+			want:    &api.MachineGuest{CPUKind: "shared", CPUs: 1, MemoryMB: 1024},
+		},
+		{
+			name:    "more CPUs for memory",
+			current: shared,
+			usage:   groupUsage{CPUs: 0.1, MemoryMB: 3000},
+			want:    &api.MachineGuest{CPUKind: "shared", CPUs: 2, MemoryMB: 4096},
+		},
+		{
+			name:    "busy CPUs",
+			current: shared,
+			usage:   groupUsage{CPUs: 1.9, MemoryMB: 200},
+			want:    &api.MachineGuest{CPUKind: "shared", CPUs: 4, MemoryMB: 1024},
+		},
+		{
+			name:    "performance minimum memory",
+			current: performance,
+			usage:   groupUsage{CPUs: 0.5, MemoryMB: 300},
+			want:    &api.MachineGuest{CPUKind: "performance", CPUs: 1, MemoryMB: 2048},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, recommendGuest(tc.current, tc.usage))
+		})
+	}
+}
+
+func Test_usageOf(t *testing.T) {
+	machines := []*api.Machine{{ID: "a"}, {ID: "b"}}
+
+	usage, ok := usageOf(machines,
+		map[string]float64{"a": 0.2, "b": 0.5},
+		map[string]float64{"a": 300 * 1024 * 1024, "b": 100 * 1024 * 1024},
+	)
+	assert.True(t, ok)
+	assert.Equal(t, groupUsage{CPUs: 0.5, MemoryMB: 300}, usage)
+
+	_, ok = usageOf(machines, map[string]float64{"c": 1}, nil)
+	assert.False(t, ok)
+}
@@ -17,6 +17,7 @@ func New() *cobra.Command {
 		newScaleMemory(),
 		newScaleShow(),
 		newScaleCount(),
+		newScaleSchedule(),
 	)
 	return cmd
 }
@@ -0,0 +1,97 @@
+package scale
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"golang.org/x/exp/slices"
+)
+
+func newScaleSchedule() *cobra.Command {
+	const (
+		short = "Stop process groups on a schedule"
+		long  = `Stop the machines of process groups during daily windows, to cut the costs of
+non-production environments. Windows are set in fly.toml and take effect once
+deployed:
+
+  [[scale_schedules]]
+    stop = "22:00"
+    start = "07:00"
+    days = ["mon", "tue", "wed", "thu", "fri"]
+    timezone = "Europe/Paris"
+    processes = ["staging"]
+
+'fly scale schedule apply' stops the machines inside a window and starts the
+ones it stopped once the window is over. Run it regularly, e.g. every few
+minutes from cron or a CI pipeline.`
+	)
+	cmd := command.New("schedule", short, long, nil)
+	cmd.AddCommand(
+		newScaleScheduleShow(),
+		newScaleScheduleApply(),
+	)
+	return cmd
+}
+
+// scheduleWindow is an occurrence of the window of a scale schedule.
+type scheduleWindow struct {
+	Start time.Time
+	End   time.Time
+}
+
+// lastWindow returns the latest window of the schedule which began at or
+// before now, which is ongoing if now is before its end.
+func lastWindow(schedule appconfig.ScaleSchedule, now time.Time) (*scheduleWindow, error) {
+	loc, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone '%s': %w", schedule.Timezone, err)
+	}
+	stop, err := time.Parse("15:04", schedule.Stop)
+	if err != nil {
+		return nil, fmt.Errorf("invalid stop time '%s': %w", schedule.Stop, err)
+	}
+	start, err := time.Parse("15:04", schedule.Start)
+	if err != nil {
+		return nil, fmt.Errorf("invalid start time '%s': %w", schedule.Start, err)
+	}
+
+	days := make([]string, len(schedule.Days))
+	for i, d := range schedule.Days {
+		days[i] = strings.ToLower(d)
+	}
+
+	now = now.In(loc)
+	for back := 0; back <= 7; back++ {
+		day := now.AddDate(0, 0, -back)
+		if len(days) > 0 && !slices.Contains(days, strings.ToLower(day.Weekday().String()[:3])) {
+			continue
+		}
+
+		w := &scheduleWindow{
+			Start: time.Date(day.Year(), day.Month(), day.Day(), stop.Hour(), stop.Minute(), 0, 0, loc),
+			End:   time.Date(day.Year(), day.Month(), day.Day(), start.Hour(), start.Minute(), 0, 0, loc),
+		}
+		if !w.End.After(w.Start) {
+			// the window spans midnight
+			w.End = w.End.AddDate(0, 0, 1)
+		}
+		if !w.Start.After(now) {
+			return w, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// schedulesFor returns the scale schedules of the process group.
+func schedulesFor(appConfig *appconfig.Config, group string) ([]appconfig.ScaleSchedule, error) {
+	fc, err := appConfig.Flatten(group)
+	if err != nil {
+		return nil, err
+	}
+	return fc.ScaleSchedules, nil
+}
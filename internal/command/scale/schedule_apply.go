@@ -0,0 +1,115 @@
+package scale
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newScaleScheduleApply() *cobra.Command {
+	const (
+		short = "Stop or start machines according to the scale schedules"
+		long  = `Stop the started machines of the process groups whose scale schedule window
+is ongoing, and start the machines which were stopped during the last window
+once it's over. Machines stopped outside of a window, e.g. by the proxy, are
+left alone.`
+	)
+	cmd := command.New("apply", short, long, runScaleScheduleApply,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+	cmd.Args = cobra.NoArgs
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+	)
+	return cmd
+}
+
+func runScaleScheduleApply(ctx context.Context) error {
+	var (
+		io      = iostreams.FromContext(ctx)
+		appName = appconfig.NameFromContext(ctx)
+		now     = time.Now()
+	)
+
+	appConfig, err := appconfig.FromRemoteApp(ctx, appName)
+	if err != nil {
+		return fmt.Errorf("failed to get the configuration of app %s: %w", appName, err)
+	}
+
+	flapsClient, err := flaps.NewFromAppName(ctx, appName)
+	if err != nil {
+		return fmt.Errorf("could not create flaps client: %w", err)
+	}
+
+	machines, err := flapsClient.ListActive(ctx)
+	if err != nil {
+		return fmt.Errorf("could not list machines: %w", err)
+	}
+
+	for _, m := range machines {
+		if m.IsCordoned() {
+			continue
+		}
+
+		schedules, err := schedulesFor(appConfig, m.ProcessGroup())
+		if err != nil {
+			return err
+		}
+
+		var (
+			inWindow        bool
+			stoppedInWindow bool
+		)
+		for _, schedule := range schedules {
+			w, err := lastWindow(schedule, now)
+			if err != nil {
+				return err
+			}
+			if w == nil {
+				continue
+			}
+			if now.Before(w.End) {
+				inWindow = true
+			} else if last := lastEventTime(m); !last.Before(w.Start) && last.Before(w.End) {
+				stoppedInWindow = true
+			}
+		}
+
+		switch {
+		case inWindow && m.State == api.MachineStateStarted:
+			fmt.Fprintf(io.Out, "Stopping machine %s (%s), its scale schedule window is ongoing\n", m.ID, m.ProcessGroup())
+			if err := flapsClient.Stop(ctx, api.StopMachineInput{ID: m.ID}, ""); err != nil {
+				return fmt.Errorf("could not stop machine %s: %w", m.ID, err)
+			}
+		case !inWindow && stoppedInWindow && m.State == api.MachineStateStopped:
+			fmt.Fprintf(io.Out, "Starting machine %s (%s), its scale schedule window is over\n", m.ID, m.ProcessGroup())
+			if _, err := flapsClient.Start(ctx, m.ID); err != nil {
+				return fmt.Errorf("could not start machine %s: %w", m.ID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// lastEventTime returns the time of the latest event of the machine, which is
+// when it stopped for stopped machines.
+func lastEventTime(m *api.Machine) time.Time {
+	var last time.Time
+	for _, e := range m.Events {
+		if ts := time.UnixMilli(e.Timestamp); ts.After(last) {
+			last = ts
+		}
+	}
+	return last
+}
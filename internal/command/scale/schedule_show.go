@@ -0,0 +1,94 @@
+package scale
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newScaleScheduleShow() *cobra.Command {
+	const (
+		short = "Show the scale schedules of an app"
+		long  = `Show the scale schedules of the deployed app configuration and whether their
+windows are ongoing.`
+	)
+	cmd := command.New("show", short, long, runScaleScheduleShow,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+	cmd.Args = cobra.NoArgs
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.JSONOutput(),
+	)
+	return cmd
+}
+
+func runScaleScheduleShow(ctx context.Context) error {
+	var (
+		io      = iostreams.FromContext(ctx)
+		appName = appconfig.NameFromContext(ctx)
+	)
+
+	appConfig, err := appconfig.FromRemoteApp(ctx, appName)
+	if err != nil {
+		return fmt.Errorf("failed to get the configuration of app %s: %w", appName, err)
+	}
+
+	if config.FromContext(ctx).JSONOutput {
+		return render.Output(ctx, io.Out, appConfig.ScaleSchedules)
+	}
+
+	if len(appConfig.ScaleSchedules) == 0 {
+		fmt.Fprintln(io.Out, "No scale schedules are set, add a [[scale_schedules]] section to fly.toml and deploy to set some")
+		return nil
+	}
+
+	now := time.Now()
+	rows := make([][]string, 0, len(appConfig.ScaleSchedules))
+	for _, schedule := range appConfig.ScaleSchedules {
+		groups := schedule.Processes
+		if len(groups) == 0 {
+			groups = []string{appConfig.DefaultProcessName()}
+		}
+
+		days := "every day"
+		if len(schedule.Days) > 0 {
+			days = strings.Join(schedule.Days, ",")
+		}
+
+		timezone := schedule.Timezone
+		if timezone == "" {
+			timezone = "UTC"
+		}
+
+		w, err := lastWindow(schedule, now)
+		if err != nil {
+			return err
+		}
+		state := "running"
+		if w != nil && now.Before(w.End) {
+			state = fmt.Sprintf("stopped until %s", w.End.Format("Mon 15:04 MST"))
+		}
+
+		rows = append(rows, []string{
+			strings.Join(groups, ","),
+			schedule.Stop + "-" + schedule.Start,
+			days,
+			timezone,
+			state,
+		})
+	}
+
+	return render.Table(io.Out, "", rows, "Process Groups", "Window", "Days", "Timezone", "State")
+}
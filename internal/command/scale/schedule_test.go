@@ -0,0 +1,47 @@
+package scale
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/superfly/flyctl/internal/appconfig"
+)
+
+func TestLastWindow(t *testing.T) {
+	at := func(s string) time.Time {
+		ts, err := time.Parse(time.RFC3339, s)
+		require.NoError(t, err)
+		return ts
+	}
+
+	overnight := appconfig.ScaleSchedule{Stop: "22:00", Start: "07:00"}
+
+	// 2023-06-07 is a wednesday
+	w, err := lastWindow(overnight, at("2023-06-07T23:30:00Z"))
+	require.NoError(t, err)
+	assert.Equal(t, &scheduleWindow{Start: at("2023-06-07T22:00:00Z"), End: at("2023-06-08T07:00:00Z")}, w)
+
+	w, err = lastWindow(overnight, at("2023-06-08T06:59:00Z"))
+	require.NoError(t, err)
+	assert.Equal(t, at("2023-06-07T22:00:00Z"), w.Start)
+
+	w, err = lastWindow(overnight, at("2023-06-08T12:00:00Z"))
+	require.NoError(t, err)
+	assert.Equal(t, at("2023-06-08T07:00:00Z"), w.End)
+
+	daytime := appconfig.ScaleSchedule{Stop: "01:00", Start: "06:00", Days: []string{"Sat"}}
+
+	w, err = lastWindow(daytime, at("2023-06-07T03:00:00Z"))
+	require.NoError(t, err)
+	assert.Equal(t, &scheduleWindow{Start: at("2023-06-03T01:00:00Z"), End: at("2023-06-03T06:00:00Z")}, w)
+
+	paris := appconfig.ScaleSchedule{Stop: "22:00", Start: "07:00", Timezone: "Europe/Paris"}
+
+	w, err = lastWindow(paris, at("2023-06-07T21:00:00Z"))
+	require.NoError(t, err)
+	assert.True(t, w.Start.Equal(at("2023-06-07T20:00:00Z")))
+	assert.True(t, w.End.Equal(at("2023-06-08T05:00:00Z")))
+}
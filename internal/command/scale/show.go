@@ -2,7 +2,6 @@ package scale
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"strconv"
 
@@ -11,14 +10,20 @@ import (
 	"github.com/superfly/flyctl/client"
 	"github.com/superfly/flyctl/internal/appconfig"
 	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
 	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/render"
 	"github.com/superfly/flyctl/iostreams"
 )
 
 func newScaleShow() *cobra.Command {
 	const (
 		short = "Show current resources"
-		long  = `Show current VM size and counts`
+		long  = `Show current VM size and counts.
+
+With --recommendations, the CPU and memory usage of the machines of each
+process group over the last day is pulled from the metrics API, and a guest
+size fitting it is suggested.`
 	)
 	cmd := command.New("show", short, long, runScaleShow,
 		command.RequireSession,
@@ -28,6 +33,10 @@ func newScaleShow() *cobra.Command {
 	flag.Add(cmd,
 		flag.App(),
 		flag.AppConfig(),
+		flag.Bool{
+			Name:        "recommendations",
+			Description: "Suggest guest sizes for each process group from their recent usage",
+		},
 	)
 	return cmd
 }
@@ -42,6 +51,9 @@ func runScaleShow(ctx context.Context) error {
 	if isV2 {
 		return runMachinesScaleShow(ctx)
 	}
+	if flag.GetBool(ctx, "recommendations") {
+		return fmt.Errorf("--recommendations is only supported for apps on the machines platform")
+	}
 	return runNomadScaleShow(ctx)
 }
 
@@ -108,7 +120,7 @@ func printVMResources(ctx context.Context, vmSize api.VMSize, count string, maxP
 	io := iostreams.FromContext(ctx)
 	appName := appconfig.NameFromContext(ctx)
 
-	if flag.GetBool(ctx, "json") {
+	if config.FromContext(ctx).JSONOutput {
 		out := struct {
 			api.VMSize
 			Count        string
@@ -119,8 +131,7 @@ func printVMResources(ctx context.Context, vmSize api.VMSize, count string, maxP
 			MaxPerRegion: maxPerRegion,
 		}
 
-		prettyJSON, _ := json.MarshalIndent(out, "", "    ")
-		fmt.Fprintln(io.Out, string(prettyJSON))
+		_ = render.Output(ctx, io.Out, out)
 		return
 	}
 
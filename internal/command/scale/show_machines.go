@@ -7,8 +7,10 @@ import (
 
 	"github.com/samber/lo"
 	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
 	"github.com/superfly/flyctl/flaps"
 	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/flag"
 	"github.com/superfly/flyctl/internal/render"
 	"github.com/superfly/flyctl/iostreams"
 	"golang.org/x/exp/slices"
@@ -54,7 +56,16 @@ func runMachinesScaleShow(ctx context.Context) error {
 	fmt.Fprintf(io.Out, "VM Resources for app: %s\n\n", appName)
 	render.Table(io.Out, "Groups", rows, "Name", "Count", "Kind", "CPUs", "Memory", "Regions")
 
-	return nil
+	if !flag.GetBool(ctx, "recommendations") {
+		return nil
+	}
+
+	app, err := client.FromContext(ctx).API().GetAppCompact(ctx, appName)
+	if err != nil {
+		return fmt.Errorf("failed to get app %s: %w", appName, err)
+	}
+
+	return printRecommendations(ctx, app, groupNames, machineGroups)
 }
 
 func formatRegions(machines []*api.Machine) string {
@@ -0,0 +1,150 @@
+package scale
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/samber/lo"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/iostreams"
+	"golang.org/x/exp/slices"
+)
+
+// volumeProvider finds volumes for the machines scale count launches, either
+// reusing unattached volumes or, when allowed, forking the latest snapshot of
+// the nearest volume with the same name into the machine's region.
+type volumeProvider struct {
+	appID   string
+	volumes []api.Volume
+	regions map[string]api.Region
+	fork    bool
+}
+
+func newVolumeProvider(ctx context.Context, app *api.AppCompact, fork bool) (*volumeProvider, error) {
+	apiClient := client.FromContext(ctx).API()
+
+	volumes, err := apiClient.GetVolumes(ctx, app.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed fetching volumes: %w", err)
+	}
+
+	p := &volumeProvider{appID: app.ID, volumes: volumes, fork: fork}
+	if fork {
+		regions, _, err := apiClient.PlatformRegions(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed fetching regions: %w", err)
+		}
+		p.regions = lo.KeyBy(regions, func(r api.Region) string { return r.Code })
+	}
+
+	return p, nil
+}
+
+// volumeName returns the name of the volume mounted by the mount, looking it
+// up by ID for configs which only reference volumes by ID.
+func (p *volumeProvider) volumeName(mount api.MachineMount) string {
+	if mount.Name != "" {
+		return mount.Name
+	}
+
+	vol, ok := lo.Find(p.volumes, func(v api.Volume) bool { return v.ID == mount.Volume })
+	if !ok {
+		return ""
+	}
+
+	return vol.Name
+}
+
+// volumeFor returns a volume for the mount in the region.
+func (p *volumeProvider) volumeFor(ctx context.Context, mount api.MachineMount, region string) (*api.Volume, error) {
+	name := p.volumeName(mount)
+
+	for i, v := range p.volumes {
+		if v.Name == name && v.Region == region && v.AttachedMachine == nil && v.AttachedAllocation == nil {
+			// mark it as taken for the next machines
+			p.volumes = append(p.volumes[:i], p.volumes[i+1:]...)
+			return &v, nil
+		}
+	}
+
+	if !p.fork {
+		return nil, fmt.Errorf("no unattached volume named '%s' in region %s, create one or pass --fork-volumes", name, region)
+	}
+
+	source, snapshot, err := p.nearestSnapshot(ctx, name, region)
+	if err != nil {
+		return nil, err
+	}
+
+	io := iostreams.FromContext(ctx)
+	fmt.Fprintf(io.Out, "  Forking snapshot %s of volume %s (%s) into region %s\n", snapshot.ID, source.ID, source.Region, region)
+
+	vol, err := client.FromContext(ctx).API().CreateVolume(ctx, api.CreateVolumeInput{
+		AppID:      p.appID,
+		Name:       name,
+		Region:     region,
+		SizeGb:     lo.Max([]int{mount.SizeGb, source.SizeGb}),
+		Encrypted:  source.Encrypted,
+		SnapshotID: &snapshot.ID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed creating volume from snapshot %s: %w", snapshot.ID, err)
+	}
+
+	return vol, nil
+}
+
+// nearestSnapshot returns the latest snapshot of the volume named name which is
+// the closest to the region.
+func (p *volumeProvider) nearestSnapshot(ctx context.Context, name, region string) (*api.Volume, *api.Snapshot, error) {
+	apiClient := client.FromContext(ctx).API()
+
+	candidates := lo.Filter(p.volumes, func(v api.Volume, _ int) bool { return v.Name == name })
+	for _, source := range sortByDistance(candidates, p.regions, region) {
+		snapshots, err := apiClient.GetVolumeSnapshots(ctx, source.ID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed fetching snapshots of volume %s: %w", source.ID, err)
+		}
+		if len(snapshots) == 0 {
+			continue
+		}
+
+		snapshot := lo.MaxBy(snapshots, func(a, b api.Snapshot) bool { return a.CreatedAt.After(b.CreatedAt) })
+		return &source, &snapshot, nil
+	}
+
+	return nil, nil, fmt.Errorf("no snapshot found for any volume named '%s' to fork into region %s", name, region)
+}
+
+// sortByDistance returns the volumes ordered by the distance of their region
+// to the target one, nearest first. Volumes in unknown regions come last.
+func sortByDistance(volumes []api.Volume, regions map[string]api.Region, target string) []api.Volume {
+	distance := func(v api.Volume) float64 {
+		from, ok1 := regions[v.Region]
+		to, ok2 := regions[target]
+		if !ok1 || !ok2 {
+			return math.Inf(1)
+		}
+		return haversine(float64(from.Latitude), float64(from.Longitude), float64(to.Latitude), float64(to.Longitude))
+	}
+
+	sorted := append([]api.Volume(nil), volumes...)
+	slices.SortStableFunc(sorted, func(a, b api.Volume) bool { return distance(a) < distance(b) })
+
+	return sorted
+}
+
+// haversine returns the great-circle distance in kilometers between two
+// coordinates given in degrees.
+func haversine(lat1, lon1, lat2, lon2 float64) float64 {
+	const earthRadiusKm = 6371
+
+	rad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat, dLon := rad(lat2-lat1), rad(lon2-lon1)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(rad(lat1))*math.Cos(rad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
@@ -0,0 +1,39 @@
+package scale
+
+import (
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/superfly/flyctl/api"
+)
+
+func TestSortByDistance(t *testing.T) {
+	regions := map[string]api.Region{
+		"cdg": {Code: "cdg", Latitude: 48.86, Longitude: 2.35},
+		"lhr": {Code: "lhr", Latitude: 51.51, Longitude: -0.13},
+		"sjc": {Code: "sjc", Latitude: 37.35, Longitude: -121.96},
+		"syd": {Code: "syd", Latitude: -33.87, Longitude: 151.21},
+	}
+
+	volumes := []api.Volume{
+		{ID: "vol_syd", Region: "syd"},
+		{ID: "vol_unknown", Region: "xyz"},
+		{ID: "vol_sjc", Region: "sjc"},
+		{ID: "vol_lhr", Region: "lhr"},
+	}
+
+	sorted := sortByDistance(volumes, regions, "cdg")
+	assert.Equal(t,
+		[]string{"vol_lhr", "vol_sjc", "vol_syd", "vol_unknown"},
+		lo.Map(sorted, func(v api.Volume, _ int) string { return v.ID }),
+	)
+	assert.Equal(t, "vol_syd", volumes[0].ID, "the input isn't reordered")
+}
+
+func TestHaversine(t *testing.T) {
+	// Paris to London is about 344km
+	assert.InDelta(t, 344, haversine(48.86, 2.35, 51.51, -0.13), 5)
+	assert.Zero(t, haversine(10, 10, 10, 10))
+}
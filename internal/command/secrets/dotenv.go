@@ -0,0 +1,128 @@
+package secrets
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+var dotenvName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+// parseDotenv parses secrets in the dotenv format. Lines may start with
+// export, and comments start with #. Unquoted values end at a comment, single
+// quoted values are taken literally and double quoted values expand \n, \t,
+// \" and \\. Quoted values may span several lines.
+func parseDotenv(reader io.Reader) (map[string]string, error) {
+	secrets := map[string]string{}
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	lineNum := 0
+
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		name, value, ok := strings.Cut(line, "=")
+		name = strings.TrimSpace(name)
+		if !ok || !dotenvName.MatchString(name) {
+			return nil, fmt.Errorf("line %d: secrets must be provided as NAME=VALUE pairs", lineNum)
+		}
+		value = strings.TrimSpace(value)
+
+		if value == "" || (value[0] != '"' && value[0] != '\'') {
+			if i := strings.Index(value, " #"); i >= 0 {
+				value = strings.TrimSpace(value[:i])
+			}
+			secrets[name] = value
+			continue
+		}
+
+		// read lines until the closing quote
+		quote := value[0]
+		value = value[1:]
+		start := lineNum
+		for {
+			if end := closingQuote(value, quote); end >= 0 {
+				rest := strings.TrimSpace(value[end+1:])
+				if rest != "" && !strings.HasPrefix(rest, "#") {
+					return nil, fmt.Errorf("line %d: unexpected characters after the value of %s", lineNum, name)
+				}
+				value = value[:end]
+				break
+			}
+			if !scanner.Scan() {
+				return nil, fmt.Errorf("line %d: unterminated quoted value of %s", start, name)
+			}
+			lineNum++
+			value += "\n" + strings.TrimRight(scanner.Text(), "\r")
+		}
+
+		if quote == '"' {
+			value = unescapeDotenv(value)
+		}
+		secrets[name] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return secrets, nil
+}
+
+// closingQuote returns the index of the quote closing s, or -1. Double quotes
+// may be escaped with a backslash.
+func closingQuote(s string, quote byte) int {
+	for i := 0; i < len(s); i++ {
+		switch {
+		case quote == '"' && s[i] == '\\':
+			i++
+		case s[i] == quote:
+			return i
+		}
+	}
+	return -1
+}
+
+var dotenvEscapes = strings.NewReplacer(`\n`, "\n", `\r`, "\r", `\t`, "\t", `\"`, `"`, `\\`, `\`)
+
+func unescapeDotenv(s string) string {
+	return dotenvEscapes.Replace(s)
+}
+
+// parseJSONSecrets parses secrets from a JSON object. Numbers and booleans are
+// set as their JSON text.
+func parseJSONSecrets(reader io.Reader) (map[string]string, error) {
+	var raw map[string]json.RawMessage
+	if err := json.NewDecoder(reader).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("secrets must be provided as a JSON object: %w", err)
+	}
+
+	secrets := make(map[string]string, len(raw))
+	for name, value := range raw {
+		var s string
+		if err := json.Unmarshal(value, &s); err == nil {
+			secrets[name] = s
+			continue
+		}
+
+		var v interface{}
+		if err := json.Unmarshal(value, &v); err != nil {
+			return nil, err
+		}
+		switch v.(type) {
+		case float64, bool:
+			secrets[name] = string(value)
+		default:
+			return nil, fmt.Errorf("the value of %s must be a string, a number or a boolean", name)
+		}
+	}
+
+	return secrets, nil
+}
@@ -0,0 +1,86 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newExport() (cmd *cobra.Command) {
+	const (
+		long = `Export the names and digests of the secrets of the application, sorted by
+name, one per line. Values can't be exported, they are only available to the
+application. Comparing the exports of two apps shows the secrets they don't
+share and the ones holding different values.`
+		short = `Export application secret names and digests`
+		usage = "export --names [flags]"
+	)
+
+	cmd = command.New(usage, short, long, runExport, command.RequireSession, command.RequireAppName)
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.JSONOutput(),
+		flag.Bool{
+			Name:        "names",
+			Description: "Export the names and digests of the secrets",
+		},
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	return cmd
+}
+
+func runExport(ctx context.Context) error {
+	if !flag.GetBool(ctx, "names") {
+		return errors.New("secret values can't be exported, use --names to export their names and digests")
+	}
+
+	var (
+		out     = iostreams.FromContext(ctx).Out
+		appName = appconfig.NameFromContext(ctx)
+	)
+
+	secrets, err := client.FromContext(ctx).API().GetAppSecrets(ctx, appName)
+	if err != nil {
+		return err
+	}
+
+	digests := secretDigests(secrets)
+	if config.FromContext(ctx).JSONOutput {
+		return render.Output(ctx, out, digests)
+	}
+
+	names := make([]string, 0, len(digests))
+	for name := range digests {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Fprintf(out, "%s\t%s\n", name, digests[name])
+	}
+
+	return nil
+}
+
+func secretDigests(secrets []api.Secret) map[string]string {
+	digests := make(map[string]string, len(secrets))
+	for _, s := range secrets {
+		digests[s.Name] = s.Digest
+	}
+	return digests
+}
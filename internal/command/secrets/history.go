@@ -0,0 +1,112 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/format"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+// secretsReleaseReason is the reason of the releases created by setting or
+// unsetting secrets.
+const secretsReleaseReason = "change_secrets"
+
+func newHistory() (cmd *cobra.Command) {
+	const (
+		long = `List the versions of the secrets of the application. Each time secrets are
+set or unset, from flyctl, the dashboard or the API, a release is created;
+the releases are listed along with the secrets they changed. Values are
+never shown.`
+		short = `List the versions of the application secrets`
+		usage = "history [flags]"
+	)
+
+	cmd = command.New(usage, short, long, runHistory, command.RequireSession, command.RequireAppName)
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.JSONOutput(),
+		flag.Int{
+			Name:        "limit",
+			Description: "Number of most recent releases to look for secret changes in",
+			Default:     50,
+		},
+	)
+
+	return cmd
+}
+
+func runHistory(ctx context.Context) error {
+	var (
+		io        = iostreams.FromContext(ctx)
+		apiClient = client.FromContext(ctx).API()
+		appName   = appconfig.NameFromContext(ctx)
+		limit     = flag.GetInt(ctx, "limit")
+	)
+
+	app, err := apiClient.GetAppCompact(ctx, appName)
+	if err != nil {
+		return fmt.Errorf("failed retrieving app %s: %w", appName, err)
+	}
+
+	var releases []api.Release
+	if app.PlatformVersion == "machines" {
+		releases, err = apiClient.GetAppReleasesMachines(ctx, appName, limit)
+	} else {
+		releases, err = apiClient.GetAppReleasesNomad(ctx, appName, limit)
+	}
+	if err != nil {
+		return fmt.Errorf("failed retrieving releases of %s: %w", appName, err)
+	}
+
+	versions := secretReleases(releases)
+
+	if config.FromContext(ctx).JSONOutput {
+		return render.Output(ctx, io.Out, versions)
+	}
+
+	if len(versions) == 0 {
+		fmt.Fprintf(io.Out, "No secrets were changed in the last %d releases of %s\n", limit, appName)
+		return nil
+	}
+
+	rows := make([][]string, 0, len(versions))
+	for _, release := range versions {
+		rows = append(rows, []string{
+			fmt.Sprintf("v%d", release.Version),
+			release.Status,
+			release.Description,
+			release.User.Email,
+			format.RelativeTime(release.CreatedAt),
+		})
+	}
+
+	return render.Table(io.Out, "", rows, "Release", "Status", "Changes", "User", "Date")
+}
+
+// secretReleases returns the releases which changed secrets, newest first.
+func secretReleases(releases []api.Release) []api.Release {
+	versions := []api.Release{}
+	for _, release := range releases {
+		if release.Reason == secretsReleaseReason {
+			versions = append(versions, release)
+		}
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Version > versions[j].Version
+	})
+
+	return versions
+}
@@ -0,0 +1,21 @@
+package secrets
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/superfly/flyctl/api"
+)
+
+func TestSecretReleases(t *testing.T) {
+	releases := []api.Release{
+		{Version: 3, Reason: "change_image"},
+		{Version: 5, Reason: "change_secrets", Description: "Remove API_KEY secret"},
+		{Version: 4, Reason: "change_secrets", Description: "Set API_KEY secret"},
+		{Version: 2, Reason: "change_code"},
+	}
+
+	assert.Equal(t, []api.Release{releases[1], releases[2]}, secretReleases(releases))
+	assert.Equal(t, []api.Release{}, secretReleases(releases[:1]))
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/superfly/flyctl/client"
@@ -15,8 +18,10 @@ import (
 
 func newImport() (cmd *cobra.Command) {
 	const (
-		long  = `Set one or more encrypted secrets for an application. Values are read from stdin as NAME=VALUE pairs`
-		short = `Set secrets as NAME=VALUE pairs from stdin`
+		long = `Set one or more encrypted secrets for an application. Values are read from stdin as NAME=VALUE pairs,
+or from a dotenv file with --from-dotenv or a JSON object with --from-json. Use - as the file to read it from stdin.
+Use --stage to set the secrets without deploying them.`
+		short = `Set secrets as NAME=VALUE pairs from stdin or a file`
 		usage = "import [flags]"
 	)
 
@@ -24,6 +29,14 @@ func newImport() (cmd *cobra.Command) {
 
 	flag.Add(cmd,
 		sharedFlags,
+		flag.String{
+			Name:        "from-dotenv",
+			Description: "Read secrets from a dotenv file",
+		},
+		flag.String{
+			Name:        "from-json",
+			Description: "Read secrets from a JSON file holding an object of names and values",
+		},
 	)
 
 	return cmd
@@ -37,9 +50,22 @@ func runImport(ctx context.Context) (err error) {
 		return
 	}
 
-	secrets, err := parseSecrets(os.Stdin)
+	var secrets map[string]string
+	switch dotenv, jsonFile := flag.GetString(ctx, "from-dotenv"), flag.GetString(ctx, "from-json"); {
+	case dotenv != "" && jsonFile != "":
+		return errors.New("only one of --from-dotenv and --from-json may be specified")
+	case dotenv != "":
+		secrets, err = parseSecretsFile(dotenv, parseDotenv)
+	case jsonFile != "":
+		secrets, err = parseSecretsFile(jsonFile, parseJSONSecrets)
+	default:
+		secrets, err = parseSecrets(os.Stdin)
+		if err != nil {
+			err = fmt.Errorf("Failed to parse secrets from stdin: %w", err)
+		}
+	}
 	if err != nil {
-		return fmt.Errorf("Failed to parse secrets from stdin: %w", err)
+		return err
 	}
 	if len(secrets) < 1 {
 		return errors.New("requires at least one SECRET=VALUE pair")
@@ -47,3 +73,36 @@ func runImport(ctx context.Context) (err error) {
 
 	return SetSecretsAndDeploy(ctx, app, secrets, flag.GetBool(ctx, "stage"), flag.GetBool(ctx, "detach"))
 }
+
+// ReadFile reads the secrets of a JSON file, when its name ends in .json, or
+// of a dotenv file.
+func ReadFile(path string) (map[string]string, error) {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return parseSecretsFile(path, parseJSONSecrets)
+	}
+
+	return parseSecretsFile(path, parseDotenv)
+}
+
+// parseSecretsFile parses the secrets of path, which is stdin when path is -.
+func parseSecretsFile(path string, parse func(io.Reader) (map[string]string, error)) (map[string]string, error) {
+	if path == "-" {
+		secrets, err := parse(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse secrets from stdin: %w", err)
+		}
+		return secrets, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	secrets, err := parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse secrets from %s: %w", path, err)
+	}
+	return secrets, nil
+}
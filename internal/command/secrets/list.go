@@ -63,7 +63,7 @@ func runList(ctx context.Context) (err error) {
 		"Created At",
 	}
 	if cfg.JSONOutput {
-		return render.JSON(out, secrets)
+		return render.Output(ctx, out, secrets)
 	} else {
 		return render.Table(out, "", rows, headers...)
 	}
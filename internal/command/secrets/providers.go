@@ -0,0 +1,141 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// provider reads the secrets stored at a path of an external secret manager
+// with the CLI of the manager, which authenticates with the local credentials
+// of the user.
+type provider struct {
+	// CLI is the command line client of the manager.
+	CLI string
+	// Args are the arguments of CLI printing the secrets at path.
+	Args func(path string) []string
+	// Parse extracts the secrets from the output of CLI.
+	Parse func(out []byte) (map[string]string, error)
+}
+
+var providers = map[string]provider{
+	"vault": {
+		CLI: "vault",
+		Args: func(path string) []string {
+			return []string{"kv", "get", "-format=json", path}
+		},
+		Parse: parseVaultSecrets,
+	},
+	"aws-sm": {
+		CLI: "aws",
+		Args: func(path string) []string {
+			return []string{"secretsmanager", "get-secret-value", "--secret-id", path, "--query", "SecretString", "--output", "text"}
+		},
+		Parse: parseAWSSecrets,
+	},
+	"1password": {
+		CLI: "op",
+		Args: func(path string) []string {
+			// path is either an item or vault/item
+			if vault, item, ok := strings.Cut(path, "/"); ok {
+				return []string{"item", "get", item, "--vault", vault, "--format", "json"}
+			}
+			return []string{"item", "get", path, "--format", "json"}
+		},
+		Parse: parseOnePasswordSecrets,
+	},
+}
+
+func (p provider) read(ctx context.Context, path string) (map[string]string, error) {
+	if _, err := exec.LookPath(p.CLI); err != nil {
+		return nil, fmt.Errorf("%s must be installed to read secrets from this provider: %w", p.CLI, err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, p.CLI, p.Args(path)...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed reading secrets at %s: %w: %s", path, err, strings.TrimSpace(stderr.String()))
+	}
+
+	secrets, err := p.Parse(stdout.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing secrets at %s: %w", path, err)
+	}
+	return secrets, nil
+}
+
+// parseVaultSecrets parses the output of vault kv get for both versions of
+// the key/value engine. Version 2 nests the secrets along with metadata.
+func parseVaultSecrets(out []byte) (map[string]string, error) {
+	var resp struct {
+		Data map[string]json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, err
+	}
+
+	if _, ok := resp.Data["metadata"]; !ok {
+		return stringValues(resp.Data)
+	}
+
+	var data map[string]json.RawMessage
+	if err := json.Unmarshal(resp.Data["data"], &data); err != nil {
+		return nil, err
+	}
+	return stringValues(data)
+}
+
+// parseAWSSecrets parses a secret string of AWS Secrets Manager, which must be
+// a JSON object of key/value pairs.
+func parseAWSSecrets(out []byte) (map[string]string, error) {
+	var data map[string]json.RawMessage
+	if err := json.Unmarshal(bytes.TrimSpace(out), &data); err != nil {
+		return nil, errors.New("the secret must hold key/value pairs")
+	}
+	return stringValues(data)
+}
+
+// parseOnePasswordSecrets parses the fields of a 1Password item. Fields are
+// named after their labels, and the notes and empty fields are skipped.
+func parseOnePasswordSecrets(out []byte) (map[string]string, error) {
+	var item struct {
+		Fields []struct {
+			Label   string `json:"label"`
+			Value   string `json:"value"`
+			Purpose string `json:"purpose"`
+		} `json:"fields"`
+	}
+	if err := json.Unmarshal(out, &item); err != nil {
+		return nil, err
+	}
+
+	secrets := map[string]string{}
+	for _, f := range item.Fields {
+		if f.Value == "" || f.Purpose == "NOTES" {
+			continue
+		}
+		if !dotenvName.MatchString(f.Label) {
+			return nil, fmt.Errorf("field %q is not a valid secret name", f.Label)
+		}
+		secrets[f.Label] = f.Value
+	}
+	return secrets, nil
+}
+
+func stringValues(data map[string]json.RawMessage) (map[string]string, error) {
+	secrets := make(map[string]string, len(data))
+	for name, value := range data {
+		var s string
+		if err := json.Unmarshal(value, &s); err != nil {
+			return nil, fmt.Errorf("the value of %s must be a string", name)
+		}
+		secrets[name] = s
+	}
+	return secrets, nil
+}
@@ -45,6 +45,9 @@ func New() *cobra.Command {
 		newSet(),
 		newUnset(),
 		newImport(),
+		newExport(),
+		newSync(),
+		newHistory(),
 	)
 
 	return secrets
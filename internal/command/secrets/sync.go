@@ -0,0 +1,139 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newSync() (cmd *cobra.Command) {
+	const (
+		long = `Read the secrets stored at a path of an external secret manager and stage
+them onto the application. The secrets are read with the CLI of the manager,
+which must be installed and logged in: vault for HashiCorp Vault, aws for AWS
+Secrets Manager and op for 1Password. With --prune, secrets of the application
+which are missing from the manager are unset.`
+		short = `Sync secrets from an external secret manager`
+		usage = "sync --provider PROVIDER --path PATH [flags]"
+	)
+
+	cmd = command.New(usage, short, long, runSync, command.RequireSession, command.RequireAppName)
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.Detach(),
+		flag.Yes(),
+		flag.String{
+			Name:        "provider",
+			Description: "The secret manager, vault, aws-sm or 1password",
+		},
+		flag.String{
+			Name:        "path",
+			Description: "The path of the secrets in the manager: a key/value path for vault, a secret ID for aws-sm, an item or vault/item for 1password",
+		},
+		flag.Bool{
+			Name:        "prune",
+			Description: "Unset the secrets of the app which are missing from the manager",
+		},
+		flag.Bool{
+			Name:        "deploy",
+			Description: "Deploy machines with the secrets instead of staging them",
+		},
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	return cmd
+}
+
+func runSync(ctx context.Context) error {
+	var (
+		io        = iostreams.FromContext(ctx)
+		apiClient = client.FromContext(ctx).API()
+		appName   = appconfig.NameFromContext(ctx)
+		path      = flag.GetString(ctx, "path")
+	)
+
+	p, ok := providers[flag.GetString(ctx, "provider")]
+	if !ok {
+		return errors.New("--provider must be one of vault, aws-sm or 1password")
+	}
+	if path == "" {
+		return errors.New("--path must be specified")
+	}
+
+	app, err := apiClient.GetAppCompact(ctx, appName)
+	if err != nil {
+		return err
+	}
+
+	secrets, err := p.read(ctx, path)
+	if err != nil {
+		return err
+	}
+	if len(secrets) == 0 {
+		return fmt.Errorf("no secrets found at %s", path)
+	}
+
+	var prune []string
+	if flag.GetBool(ctx, "prune") {
+		current, err := apiClient.GetAppSecrets(ctx, appName)
+		if err != nil {
+			return err
+		}
+		for _, s := range current {
+			if _, ok := secrets[s.Name]; !ok {
+				prune = append(prune, s.Name)
+			}
+		}
+		sort.Strings(prune)
+	}
+
+	names := make([]string, 0, len(secrets))
+	for name := range secrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintf(io.Out, "Syncing %d secrets of %s from %s\n", len(names), appName, path)
+	fmt.Fprintf(io.Out, "  set: %s\n", strings.Join(names, ", "))
+	if len(prune) > 0 {
+		fmt.Fprintf(io.Out, "  unset: %s\n", strings.Join(prune, ", "))
+
+		if !flag.GetYes(ctx) {
+			switch confirmed, err := prompt.Confirmf(ctx, "Unset %d secrets missing from %s?", len(prune), path); {
+			case err == nil:
+				if !confirmed {
+					return nil
+				}
+			case prompt.IsNonInteractive(err):
+				return prompt.NonInteractiveError("yes flag must be specified when not running interactively")
+			default:
+				return err
+			}
+		}
+	}
+
+	release, err := apiClient.SetSecrets(ctx, appName, secrets)
+	if err != nil {
+		return err
+	}
+	if len(prune) > 0 {
+		if release, err = apiClient.UnsetSecrets(ctx, appName, prune); err != nil {
+			return err
+		}
+	}
+
+	return deployForSecrets(ctx, app, release, !flag.GetBool(ctx, "deploy"), flag.GetDetach(ctx))
+}
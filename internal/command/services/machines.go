@@ -8,7 +8,7 @@ import (
 	"github.com/superfly/flyctl/api"
 	"github.com/superfly/flyctl/client"
 	"github.com/superfly/flyctl/internal/command/apps"
-	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/config"
 	"github.com/superfly/flyctl/internal/machine"
 	"github.com/superfly/flyctl/internal/render"
 	"github.com/superfly/flyctl/iostreams"
@@ -18,7 +18,7 @@ func ShowMachineServiceInfo(ctx context.Context, app *api.AppInfo) error {
 	var (
 		io        = iostreams.FromContext(ctx)
 		client    = client.FromContext(ctx).API()
-		jsonOuput = flag.GetBool(ctx, "json")
+		jsonOuput = config.FromContext(ctx).JSONOutput
 	)
 
 	if jsonOuput {
@@ -0,0 +1,302 @@
+package ssh
+
+import (
+	"context"
+	"crypto/ed25519"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/superfly/flyctl/agent"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/state"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newConfig() *cobra.Command {
+	const (
+		long = `Print an OpenSSH config for the machines of the app, so that plain ssh,
+scp and editors like VS Code Remote-SSH connect to them. The app gets a host
+named after it, connecting to the nearest machine, and each machine a host
+named <app>-<machine name>.
+
+Connections go through the WireGuard tunnel of the flyctl agent, with flyctl
+as the ProxyCommand. The SSH credential of the organization is kept in the
+flyctl config directory and renewed by the ProxyCommand before it expires.
+
+With --write, the config is written to ~/.ssh/config, replacing the previous
+config of the app.`
+		short = "Print an OpenSSH config for the machines of the app"
+	)
+
+	cmd := command.New("config", short, long, runConfig,
+		command.RequireSession, command.RequireAppName)
+
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.String{
+			Name:        "user",
+			Shorthand:   "u",
+			Description: "Unix username to connect as",
+			Default:     DefaultSshUsername,
+		},
+		flag.Bool{
+			Name:        "write",
+			Description: "Write the config to ~/.ssh/config",
+		},
+	)
+
+	return cmd
+}
+
+func newProxyCommand() *cobra.Command {
+	const (
+		long = `Connect stdin and stdout to a port of the private network of an
+organization, renewing the SSH credential of the organization when needed.
+Used as the ProxyCommand of the configs of fly ssh config.`
+		short = "Connect to a host of the private network, for OpenSSH"
+	)
+
+	cmd := command.New("proxy-command <org> <host> <port>", short, long, runProxyCommand,
+		command.RequireSession)
+
+	cmd.Args = cobra.ExactArgs(3)
+	cmd.Hidden = true
+
+	return cmd
+}
+
+func runConfig(ctx context.Context) error {
+	var (
+		io      = iostreams.FromContext(ctx)
+		client  = client.FromContext(ctx).API()
+		appName = appconfig.NameFromContext(ctx)
+	)
+
+	app, err := client.GetAppCompact(ctx, appName)
+	if err != nil {
+		return fmt.Errorf("get app: %w", err)
+	}
+
+	flapsClient, err := flaps.New(ctx, app)
+	if err != nil {
+		return err
+	}
+
+	machines, err := flapsClient.ListActive(ctx)
+	if err != nil {
+		return fmt.Errorf("failed listing machines: %w", err)
+	}
+
+	key, err := ensureCredential(ctx, app.Organization, time.Hour)
+	if err != nil {
+		return err
+	}
+
+	flyctl, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	block := sshConfig(app.Name, app.Organization.Slug, flag.GetString(ctx, "user"), key, flyctl, machines)
+
+	if !flag.GetBool(ctx, "write") {
+		fmt.Fprint(io.Out, block)
+		return nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(home, ".ssh", "config")
+
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(replaceConfigBlock(string(existing), app.Name, block)), 0o600); err != nil {
+		return fmt.Errorf("failed writing %s: %w", path, err)
+	}
+
+	fmt.Fprintf(io.Out, "Wrote the SSH config of %s to %s, connect with ssh %s\n", app.Name, path, app.Name)
+
+	return nil
+}
+
+func configMarkers(appName string) (string, string) {
+	return "# BEGIN fly " + appName, "# END fly " + appName
+}
+
+// sshConfig returns the OpenSSH config block of the app and its machines.
+func sshConfig(appName, orgSlug, user, key, flyctl string, machines []*api.Machine) string {
+	begin, end := configMarkers(appName)
+
+	var b strings.Builder
+	fmt.Fprintln(&b, begin)
+
+	host := func(alias, hostname string) {
+		fmt.Fprintf(&b, "Host %s\n", alias)
+		fmt.Fprintf(&b, "  HostName %s\n", hostname)
+		fmt.Fprintf(&b, "  User %s\n", user)
+		fmt.Fprintf(&b, "  IdentityFile %s\n", quoteConfig(key))
+		fmt.Fprintf(&b, "  CertificateFile %s\n", quoteConfig(key+"-cert.pub"))
+		fmt.Fprintf(&b, "  IdentitiesOnly yes\n")
+		fmt.Fprintf(&b, "  ProxyCommand %s ssh proxy-command %s %%h %%p\n", quoteConfig(flyctl), orgSlug)
+		// host keys of machines change as they're replaced, like with fly ssh console
+		fmt.Fprintf(&b, "  StrictHostKeyChecking no\n")
+		fmt.Fprintf(&b, "  UserKnownHostsFile /dev/null\n")
+		fmt.Fprintf(&b, "  LogLevel ERROR\n")
+	}
+
+	host(appName, fmt.Sprintf("top1.nearest.of.%s.internal", appName))
+	for _, m := range machines {
+		name := m.Name
+		if name == "" {
+			name = m.ID
+		}
+		host(appName+"-"+name, m.PrivateIP)
+	}
+
+	fmt.Fprintln(&b, end)
+
+	return b.String()
+}
+
+// quoteConfig quotes the values of OpenSSH configs containing spaces.
+func quoteConfig(s string) string {
+	if strings.ContainsAny(s, " \t") {
+		return `"` + s + `"`
+	}
+
+	return s
+}
+
+// replaceConfigBlock replaces the config block of the app in config, or
+// appends it when there's none.
+func replaceConfigBlock(config, appName, block string) string {
+	begin, end := configMarkers(appName)
+
+	if i := strings.Index(config, begin+"\n"); i >= 0 {
+		if j := strings.Index(config[i:], end+"\n"); j >= 0 {
+			return config[:i] + block + config[i+j+len(end)+1:]
+		}
+	}
+
+	if config != "" && !strings.HasSuffix(config, "\n") {
+		config += "\n"
+	}
+	if config != "" {
+		config += "\n"
+	}
+
+	return config + block
+}
+
+// credentialPath returns the path of the private key of the SSH credential
+// of the organization; the certificate is next to it.
+func credentialPath(ctx context.Context, orgSlug string) string {
+	return filepath.Join(state.ConfigDirectory(ctx), "ssh", orgSlug)
+}
+
+// ensureCredential issues a new SSH credential for the organization unless
+// the current one is valid for at least minValidity, and returns the path of
+// its private key.
+func ensureCredential(ctx context.Context, org api.OrganizationImpl, minValidity time.Duration) (string, error) {
+	path := credentialPath(ctx, org.GetSlug())
+
+	if buf, err := os.ReadFile(path + "-cert.pub"); err == nil {
+		if pub, _, _, _, err := ssh.ParseAuthorizedKey(buf); err == nil {
+			if cert, ok := pub.(*ssh.Certificate); ok && time.Unix(int64(cert.ValidBefore), 0).After(time.Now().Add(minValidity)) {
+				return path, nil
+			}
+		}
+	}
+
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		return "", err
+	}
+
+	hours := 24
+	icert, err := client.FromContext(ctx).API().IssueSSHCertificate(ctx, org, []string{DefaultSshUsername, "fly"}, nil, &hours, pub)
+	if err != nil {
+		return "", fmt.Errorf("failed issuing SSH credential: %w (if you haven't created a key for your org yet, try `flyctl ssh issue`)", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, MarshalED25519PrivateKey(priv, "fly.io"), 0o600); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path+"-cert.pub", []byte(icert.Certificate), 0o600); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+func runProxyCommand(ctx context.Context) error {
+	var (
+		streams = iostreams.FromContext(ctx)
+		client  = client.FromContext(ctx).API()
+		args    = flag.Args(ctx)
+	)
+
+	org, err := client.GetOrganizationBySlug(ctx, args[0])
+	if err != nil {
+		return err
+	}
+
+	// ssh reads the credential once the ProxyCommand is connected, so it can
+	// still be renewed here
+	if _, err := ensureCredential(ctx, org, 5*time.Minute); err != nil {
+		return err
+	}
+
+	agentclient, err := agent.Establish(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	dialer, err := agentclient.Dialer(ctx, org.Slug)
+	if err != nil {
+		return err
+	}
+
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(args[1], args[2]))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	go func() {
+		_, _ = io.Copy(conn, streams.In)
+		if c, ok := conn.(interface{ CloseWrite() error }); ok {
+			_ = c.CloseWrite()
+		}
+	}()
+
+	_, err = io.Copy(streams.Out, conn)
+
+	return err
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/superfly/flyctl/internal/appconfig"
 	"github.com/superfly/flyctl/internal/command"
 	"github.com/superfly/flyctl/internal/flag"
+	mach "github.com/superfly/flyctl/internal/machine"
 	"github.com/superfly/flyctl/internal/sentry"
 	"github.com/superfly/flyctl/iostreams"
 	"github.com/superfly/flyctl/ip"
@@ -61,6 +62,10 @@ func stdArgsSSH(cmd *cobra.Command) {
 			Shorthand:   "A",
 			Description: "Address of VM to connect to",
 		},
+		flag.String{
+			Name:        "machine",
+			Description: "ID of the machine to connect to",
+		},
 		flag.Bool{
 			Name:        "pty",
 			Description: "Allocate a pseudo-terminal (default: on when no command is provided)",
@@ -102,8 +107,21 @@ func lookupAddress(ctx context.Context, cli *agent.Client, dialer agent.Dialer,
 
 func newConsole() *cobra.Command {
 	const (
-		long  = `Connect to a running instance of the current app.`
-		short = long
+		short = `Connect to a running instance of the current app.`
+		long  = short + `
+
+Ports are forwarded over the session with -L and -R, like with OpenSSH:
+-L 5432:localhost:5432 makes port 5432 of the machine reachable on the local
+port 5432, and -R 8080:localhost:3000 makes the local port 3000 reachable on
+port 8080 of the machine. Forwards stop when the session ends.
+
+With --record, the session is recorded to a file as an asciinema typescript,
+input included, for audits. With --record-s3-bucket, the recording is uploaded
+to the bucket under <app>/<time>-<address>.cast once the session ends.
+
+When the session ends, the time spent connected, the size of the machine and
+the estimated cost of running it for that time are printed, unless --quiet is
+set.`
 		usage = "console"
 	)
 
@@ -113,9 +131,46 @@ func newConsole() *cobra.Command {
 
 	stdArgsSSH(cmd)
 
+	flag.Add(cmd,
+		flag.StringSlice{
+			Name:        "local-forward",
+			Shorthand:   "L",
+			Description: "Forward a local port to the machine, as [bind_address:]port:host:hostport, like -L 5432:localhost:5432",
+		},
+		flag.StringSlice{
+			Name:        "remote-forward",
+			Shorthand:   "R",
+			Description: "Forward a port of the machine to the local host, as [bind_address:]port:host:hostport",
+		},
+		recordFlags,
+	)
+
 	return cmd
 }
 
+// forwards returns the port forwards of the console session.
+func forwards(ctx context.Context) ([]ssh.Forward, error) {
+	var ret []ssh.Forward
+
+	for _, opt := range []struct {
+		name   string
+		remote bool
+	}{
+		{"local-forward", false},
+		{"remote-forward", true},
+	} {
+		for _, spec := range flag.GetStringSlice(ctx, opt.name) {
+			f, err := ssh.ParseForward(spec, opt.remote)
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, f)
+		}
+	}
+
+	return ret, nil
+}
+
 func captureError(err error, app *api.AppCompact) {
 	// ignore cancelled errors
 	if errors.Is(err, context.Canceled) {
@@ -214,12 +269,35 @@ func runConsole(ctx context.Context) error {
 		params.DisableSpinner = true
 	}
 
+	fwds, err := forwards(ctx)
+	if err != nil {
+		return err
+	}
+
 	sshc, err := sshConnect(params, addr)
 	if err != nil {
 		captureError(err, app)
 		return err
 	}
 
+	fwdCtx, cancelForwards := context.WithCancel(ctx)
+	defer cancelForwards()
+
+	for _, f := range fwds {
+		if err := sshc.StartForward(fwdCtx, f); err != nil {
+			return fmt.Errorf("failed forwarding %s: %w", f, err)
+		}
+
+		if !quiet(ctx) {
+			fmt.Fprintf(iostreams.FromContext(ctx).ErrOut, "Forwarding %s\n", f)
+		}
+	}
+
+	finishRecording, err := startRecording(ctx, app, addr, params)
+	if err != nil {
+		return err
+	}
+
 	sessIO := &ssh.SessionIO{
 		Stdin:    params.Stdin,
 		Stdout:   params.Stdout,
@@ -236,14 +314,52 @@ func runConsole(ctx context.Context) error {
 		return nil
 	}()
 
-	if err := sshc.Shell(params.Ctx, sessIO, params.Cmd); err != nil {
+	var session *mach.Session
+	if app.PlatformVersion == "machines" && !quiet(ctx) {
+		session = sessionForAddr(ctx, app, addr)
+	}
+
+	err = sshc.Shell(params.Ctx, sessIO, params.Cmd)
+	if recErr := finishRecording(); recErr != nil {
+		terminal.Warn(recErr.Error())
+	}
+	if err != nil {
 		captureError(err, app)
 		return errors.Wrap(err, "ssh shell")
 	}
 
+	if session != nil {
+		session.End()
+		_ = session.Render(ctx, iostreams.FromContext(ctx).ErrOut, false)
+	}
+
 	return err
 }
 
+// sessionForAddr starts accounting for a session on the machine with the given
+// private IP, if it can be found.
+func sessionForAddr(ctx context.Context, app *api.AppCompact, addr string) *mach.Session {
+	flapsClient, err := flaps.New(ctx, app)
+	if err != nil {
+		terminal.Debugf("could not make flaps client: %v\n", err)
+		return nil
+	}
+
+	machines, err := flapsClient.ListActive(ctx)
+	if err != nil {
+		terminal.Debugf("could not list machines: %v\n", err)
+		return nil
+	}
+
+	for _, m := range machines {
+		if m.PrivateIP == addr {
+			return mach.NewSession(m)
+		}
+	}
+
+	return nil
+}
+
 func sshConnect(p *SSHParams, addr string) (*ssh.Client, error) {
 	terminal.Debugf("Fetching certificate for %s\n", addr)
 
@@ -313,6 +429,14 @@ func addrForMachines(ctx context.Context, app *api.AppCompact, console bool) (ad
 	var namesWithRegion []string
 	var selectedMachine *api.Machine
 
+	if id := flag.GetString(ctx, "machine"); id != "" {
+		m, ok := lo.Find(machines, func(m *api.Machine) bool { return m.ID == id })
+		if !ok {
+			return "", fmt.Errorf("machine %s of app %s doesn't exist or isn't started", id, app.Name)
+		}
+		return m.PrivateIP, nil
+	}
+
 	for _, machine := range machines {
 		namesWithRegion = append(namesWithRegion, fmt.Sprintf("%s: %s %s %s", machine.Region, machine.ID, machine.PrivateIP, machine.Name))
 	}
@@ -49,7 +49,7 @@ func runLog(ctx context.Context) (err error) {
 	}
 
 	if jsonOutput {
-		render.JSON(out, certs)
+		render.Output(ctx, out, certs)
 		return nil
 	}
 
@@ -0,0 +1,141 @@
+package ssh
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"golang.org/x/term"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/s3"
+	"github.com/superfly/flyctl/iostreams"
+	"github.com/superfly/flyctl/ssh"
+)
+
+// recordFlags enable recording console sessions, to a local file or to an
+// S3-compatible bucket. The credentials of the bucket come from the usual
+// AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN environment
+// variables.
+var recordFlags = flag.Set{
+	flag.String{
+		Name:        "record",
+		Description: "Record the session to this file, as an asciinema typescript",
+	},
+	flag.String{
+		Name:        "record-s3-bucket",
+		Description: "Upload the recording of the session to this S3-compatible bucket",
+	},
+	flag.String{
+		Name:        "record-s3-endpoint",
+		Description: "The endpoint of the S3-compatible service, defaults to AWS S3",
+	},
+	flag.String{
+		Name:        "record-s3-region",
+		Description: "The region of the bucket",
+		Default:     "us-east-1",
+	},
+}
+
+// startRecording records the session of params when it's requested, and
+// returns the function to call once the session ends.
+func startRecording(ctx context.Context, app *api.AppCompact, addr string, params *SSHParams) (func() error, error) {
+	var (
+		path       = flag.GetString(ctx, "record")
+		bucketName = flag.GetString(ctx, "record-s3-bucket")
+		bucket     *s3.Bucket
+		err        error
+	)
+
+	if path == "" && bucketName == "" {
+		return func() error { return nil }, nil
+	}
+
+	if bucketName != "" {
+		bucket, err = s3.New(bucketName, flag.GetString(ctx, "record-s3-region"), flag.GetString(ctx, "record-s3-endpoint"))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var f *os.File
+	if path != "" {
+		f, err = os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	} else {
+		f, err = os.CreateTemp("", "fly-ssh-*.cast")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed creating the recording: %w", err)
+	}
+
+	width, height := ssh.DefaultWidth, ssh.DefaultHeight
+	if w, h, err := term.GetSize(int(os.Stdout.Fd())); err == nil {
+		width, height = w, h
+	}
+
+	started := time.Now().UTC()
+	rec, err := ssh.NewRecorder(f, width, height, fmt.Sprintf("%s %s", app.Name, addr), map[string]string{
+		"TERM": determineTermEnv(),
+	})
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed writing the recording: %w", err)
+	}
+
+	params.Stdin = rec.Input(params.Stdin)
+	params.Stdout = rec.Output(params.Stdout)
+	params.Stderr = rec.Output(params.Stderr)
+
+	return func() error {
+		io := iostreams.FromContext(ctx)
+
+		if err := rec.Err(); err != nil {
+			f.Close()
+			return fmt.Errorf("failed writing the recording: %w", err)
+		}
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("failed writing the recording: %w", err)
+		}
+
+		if path != "" {
+			fmt.Fprintf(io.ErrOut, "Session recorded to %s\n", path)
+		} else {
+			defer os.Remove(f.Name())
+		}
+
+		if bucket == nil {
+			return nil
+		}
+
+		key := recordingKey(app.Name, addr, started)
+		if err := uploadRecording(ctx, bucket, key, f.Name()); err != nil {
+			return fmt.Errorf("failed uploading the recording: %w", err)
+		}
+		fmt.Fprintf(io.ErrOut, "Session recording uploaded to %s/%s\n", bucket, key)
+
+		return nil
+	}, nil
+}
+
+// recordingKey returns the key the recording of a session is stored under in
+// a bucket.
+func recordingKey(appName, addr string, started time.Time) string {
+	return fmt.Sprintf("%s/%s-%s.cast", appName, started.Format("20060102T150405Z"), addr)
+}
+
+func uploadRecording(ctx context.Context, bucket *s3.Bucket, key, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	return bucket.Put(ctx, key, f, info.Size())
+}
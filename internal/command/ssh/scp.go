@@ -0,0 +1,96 @@
+package ssh
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newSCP() *cobra.Command {
+	const (
+		long = `Copy files and directories between the local host and a VM, like scp.
+
+Paths on the VM are prefixed with a colon, like :/data. Either the sources or
+the destination are on the VM. Sources may be glob patterns, quoted for the
+patterns of the VM to reach it:
+
+  fly ssh scp ./assets :/app/public --recursive
+  fly ssh scp ':/data/*.log' ./logs
+
+With --delete, the files of destination directories which are missing from
+the source directories are deleted, so the destination mirrors the source.`
+		short = "Copy files and directories between the local host and a VM"
+		usage = "scp <source>... <destination>"
+	)
+
+	cmd := command.New(usage, short, long, runSCP, command.RequireSession, command.LoadAppNameIfPresent)
+
+	cmd.Args = cobra.MinimumNArgs(2)
+
+	stdArgsSSH(cmd)
+	transferFlags(cmd)
+
+	return cmd
+}
+
+func transferFlags(cmd *cobra.Command) {
+	flag.Add(cmd,
+		flag.Bool{
+			Name:        "recursive",
+			Description: "Copy directories recursively",
+		},
+		flag.Bool{
+			Name:        "delete",
+			Description: "Delete the files of destination directories missing from the source directories",
+		},
+	)
+}
+
+// remotePath reports whether the path is on the VM, along with the path
+// without its colon prefix.
+func remotePath(p string) (string, bool) {
+	if strings.HasPrefix(p, ":") {
+		return p[1:], true
+	}
+
+	return p, false
+}
+
+func runSCP(ctx context.Context) error {
+	args := flag.Args(ctx)
+	sources, dst := args[:len(args)-1], args[len(args)-1]
+
+	dst, upload := remotePath(dst)
+
+	for i, src := range sources {
+		var remote bool
+		if sources[i], remote = remotePath(src); remote == upload {
+			return errors.New("either the sources or the destination must be on the VM, prefixed with a colon")
+		}
+	}
+
+	ftp, err := newSFTPConnection(ctx)
+	if err != nil {
+		return err
+	}
+	defer ftp.Close()
+
+	t := &transfer{
+		io:        iostreams.FromContext(ctx),
+		src:       remoteFS{ftp},
+		dst:       localFS{},
+		recursive: flag.GetBool(ctx, "recursive"),
+		delete:    flag.GetBool(ctx, "delete"),
+	}
+	if upload {
+		t.src, t.dst = t.dst, t.src
+	}
+
+	return t.copy(sources, dst)
+}
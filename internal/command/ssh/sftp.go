@@ -18,6 +18,7 @@ import (
 	"github.com/superfly/flyctl/internal/appconfig"
 	"github.com/superfly/flyctl/internal/command"
 	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/iostreams"
 
 	"github.com/chzyer/readline"
 	"github.com/google/shlex"
@@ -36,6 +37,7 @@ func NewSFTP() *cobra.Command {
 		newFind(),
 		newSFTPShell(),
 		newGet(),
+		newPut(),
 	)
 
 	return cmd
@@ -85,6 +87,25 @@ func newGet() *cobra.Command {
 	return cmd
 }
 
+func newPut() *cobra.Command {
+	const (
+		long = `The SFTP PUT uploads files to a remote VM. The local path may be a glob
+pattern, and directories are uploaded with --recursive. With --delete, the
+files of the remote directories missing from the local ones are deleted.`
+		short = `The SFTP PUT uploads files to a remote VM.`
+		usage = "put <local-path> [remote-path]"
+	)
+
+	cmd := command.New(usage, short, long, runPut, command.RequireSession, command.LoadAppNameIfPresent)
+
+	cmd.Args = cobra.RangeArgs(1, 2)
+
+	stdArgsSSH(cmd)
+	transferFlags(cmd)
+
+	return cmd
+}
+
 func newSFTPConnection(ctx context.Context) (*sftp.Client, error) {
 	client := client.FromContext(ctx).API()
 	appName := appconfig.NameFromContext(ctx)
@@ -201,6 +222,31 @@ func runGet(ctx context.Context) error {
 	return nil
 }
 
+func runPut(ctx context.Context) error {
+	args := flag.Args(ctx)
+
+	remote := "."
+	if len(args) > 1 {
+		remote = args[1]
+	}
+
+	ftp, err := newSFTPConnection(ctx)
+	if err != nil {
+		return err
+	}
+	defer ftp.Close()
+
+	t := &transfer{
+		io:        iostreams.FromContext(ctx),
+		src:       localFS{},
+		dst:       remoteFS{ftp},
+		recursive: flag.GetBool(ctx, "recursive"),
+		delete:    flag.GetBool(ctx, "delete"),
+	}
+
+	return t.copy(args[:1], remote)
+}
+
 var completer = readline.NewPrefixCompleter(
 	readline.PcItem("ls"),
 	readline.PcItem("cd"),
@@ -19,6 +19,9 @@ func New() *cobra.Command {
 		newIssue(),
 		newLog(),
 		NewSFTP(),
+		newSCP(),
+		newConfig(),
+		newProxyCommand(),
 	)
 
 	return cmd
@@ -59,6 +59,9 @@ type SSHParams struct {
 	Stdout         io.WriteCloser
 	Stderr         io.WriteCloser
 	DisableSpinner bool
+	// DisablePTY runs the command without a pseudo-terminal, keeping binary
+	// output intact and forwarding the end of stdin
+	DisablePTY bool
 }
 
 func RunSSHCommand(ctx context.Context, app *api.AppCompact, dialer agent.Dialer, addr string, cmd string, username string) ([]byte, error) {
@@ -136,7 +139,7 @@ func SSHConnect(p *SSHParams, addr string) error {
 		Stdin:    p.Stdin,
 		Stdout:   p.Stdout,
 		Stderr:   p.Stderr,
-		AllocPTY: true,
+		AllocPTY: !p.DisablePTY,
 		TermEnv:  "xterm",
 	}
 
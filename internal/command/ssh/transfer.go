@@ -0,0 +1,256 @@
+package ssh
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/mitchellh/ioprogress"
+	"github.com/pkg/sftp"
+
+	"github.com/superfly/flyctl/iostreams"
+)
+
+// fileSystem is either side of a transfer, the local host or the VM.
+type fileSystem interface {
+	Stat(name string) (fs.FileInfo, error)
+	ReadDir(name string) ([]fs.FileInfo, error)
+	Glob(pattern string) ([]string, error)
+	Open(name string) (io.ReadCloser, error)
+	Create(name string) (io.WriteCloser, error)
+	MkdirAll(name string) error
+	Chmod(name string, mode fs.FileMode) error
+	// Remove removes a file or an empty directory.
+	Remove(name string) error
+	Join(elem ...string) string
+	Base(name string) string
+}
+
+type localFS struct{}
+
+func (localFS) Stat(name string) (fs.FileInfo, error) { return os.Stat(name) }
+
+func (localFS) ReadDir(name string) ([]fs.FileInfo, error) {
+	entries, err := os.ReadDir(name)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]fs.FileInfo, 0, len(entries))
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, info)
+	}
+
+	return infos, nil
+}
+
+func (localFS) Glob(pattern string) ([]string, error)      { return filepath.Glob(pattern) }
+func (localFS) Open(name string) (io.ReadCloser, error)    { return os.Open(name) }
+func (localFS) Create(name string) (io.WriteCloser, error) { return os.Create(name) }
+func (localFS) MkdirAll(name string) error                 { return os.MkdirAll(name, 0o755) }
+func (localFS) Chmod(name string, mode fs.FileMode) error  { return os.Chmod(name, mode) }
+func (localFS) Remove(name string) error                   { return os.Remove(name) }
+func (localFS) Join(elem ...string) string                 { return filepath.Join(elem...) }
+func (localFS) Base(name string) string                    { return filepath.Base(name) }
+
+type remoteFS struct {
+	ftp *sftp.Client
+}
+
+func (r remoteFS) Stat(name string) (fs.FileInfo, error)      { return r.ftp.Stat(name) }
+func (r remoteFS) ReadDir(name string) ([]fs.FileInfo, error) { return r.ftp.ReadDir(name) }
+func (r remoteFS) Glob(pattern string) ([]string, error)      { return r.ftp.Glob(pattern) }
+func (r remoteFS) Open(name string) (io.ReadCloser, error)    { return r.ftp.Open(name) }
+func (r remoteFS) Create(name string) (io.WriteCloser, error) { return r.ftp.Create(name) }
+func (r remoteFS) MkdirAll(name string) error                 { return r.ftp.MkdirAll(name) }
+func (r remoteFS) Chmod(name string, mode fs.FileMode) error  { return r.ftp.Chmod(name, mode) }
+func (r remoteFS) Remove(name string) error                   { return r.ftp.Remove(name) }
+func (remoteFS) Join(elem ...string) string                   { return path.Join(elem...) }
+func (remoteFS) Base(name string) string                      { return path.Base(name) }
+
+// transfer copies files and directories between the local host and a VM,
+// like scp -r. With delete set, the files of a destination directory which
+// are missing from the source directory are removed, like rsync --delete.
+type transfer struct {
+	io        *iostreams.IOStreams
+	src, dst  fileSystem
+	recursive bool
+	delete    bool
+}
+
+// copy copies the sources matching the patterns given to dst. When dst is an
+// existing directory, the sources are copied into it.
+func (t *transfer) copy(patterns []string, dst string) error {
+	var sources []string
+	for _, pattern := range patterns {
+		matches, err := t.src.Glob(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern %s: %w", pattern, err)
+		}
+		if len(matches) == 0 {
+			return fmt.Errorf("%s: no such file or directory", pattern)
+		}
+		sources = append(sources, matches...)
+	}
+
+	into := false
+	switch info, err := t.dst.Stat(dst); {
+	case err == nil:
+		into = info.IsDir()
+	case len(sources) > 1:
+		if err := t.dst.MkdirAll(dst); err != nil {
+			return fmt.Errorf("failed creating %s: %w", dst, err)
+		}
+		into = true
+	}
+	if len(sources) > 1 && !into {
+		return fmt.Errorf("%s: not a directory", dst)
+	}
+
+	for _, src := range sources {
+		target := dst
+		if into {
+			target = t.dst.Join(dst, t.src.Base(src))
+		}
+
+		if err := t.copyPath(src, target); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t *transfer) copyPath(src, dst string) error {
+	info, err := t.src.Stat(src)
+	if err != nil {
+		return err
+	}
+
+	if !info.IsDir() {
+		return t.copyFile(src, dst, info)
+	}
+
+	if !t.recursive {
+		return fmt.Errorf("%s is a directory, use --recursive to copy it", src)
+	}
+
+	if err := t.dst.MkdirAll(dst); err != nil {
+		return fmt.Errorf("failed creating %s: %w", dst, err)
+	}
+
+	entries, err := t.src.ReadDir(src)
+	if err != nil {
+		return fmt.Errorf("failed reading %s: %w", src, err)
+	}
+
+	names := map[string]bool{}
+	for _, e := range entries {
+		names[e.Name()] = true
+
+		if err := t.copyPath(t.src.Join(src, e.Name()), t.dst.Join(dst, e.Name())); err != nil {
+			return err
+		}
+	}
+
+	if t.delete {
+		return t.prune(dst, names)
+	}
+
+	return nil
+}
+
+// prune removes the entries of the destination directory dir which aren't
+// in keep.
+func (t *transfer) prune(dir string, keep map[string]bool) error {
+	entries, err := t.dst.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed reading %s: %w", dir, err)
+	}
+
+	for _, e := range entries {
+		if keep[e.Name()] {
+			continue
+		}
+
+		if err := t.removeAll(t.dst.Join(dir, e.Name()), e); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t *transfer) removeAll(name string, info fs.FileInfo) error {
+	if info.IsDir() {
+		if err := t.prune(name, nil); err != nil {
+			return err
+		}
+	}
+
+	if err := t.dst.Remove(name); err != nil {
+		return fmt.Errorf("failed deleting %s: %w", name, err)
+	}
+
+	fmt.Fprintf(t.io.ErrOut, "deleted %s\n", name)
+
+	return nil
+}
+
+func (t *transfer) copyFile(src, dst string, info fs.FileInfo) (err error) {
+	if !info.Mode().IsRegular() {
+		fmt.Fprintf(t.io.ErrOut, "skipping %s, not a regular file\n", src)
+		return nil
+	}
+
+	in, err := t.src.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed opening %s: %w", src, err)
+	}
+	defer in.Close()
+
+	out, err := t.dst.Create(dst)
+	if err != nil {
+		return fmt.Errorf("failed creating %s: %w", dst, err)
+	}
+	defer func() {
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	var r io.Reader = in
+	if t.io.IsStderrTTY() && info.Size() > 0 {
+		bar := ioprogress.DrawTextFormatBar(30)
+		r = &ioprogress.Reader{
+			Reader: in,
+			Size:   info.Size(),
+			DrawFunc: ioprogress.DrawTerminalf(t.io.ErrOut, func(progress, total int64) string {
+				return fmt.Sprintf("%s %s %s", src, bar(progress, total), ioprogress.DrawTextFormatBytes(progress, total))
+			}),
+		}
+	}
+
+	n, err := io.Copy(out, r)
+	if err != nil {
+		return fmt.Errorf("failed copying %s to %s: %w (%d bytes written)", src, dst, err, n)
+	}
+
+	if !t.io.IsStderrTTY() || info.Size() == 0 {
+		fmt.Fprintf(t.io.ErrOut, "%s -> %s (%d bytes)\n", src, dst, n)
+	}
+
+	if err := t.dst.Chmod(dst, info.Mode().Perm()); err != nil && !errors.Is(err, fs.ErrPermission) {
+		return fmt.Errorf("failed setting the permissions of %s: %w", dst, err)
+	}
+
+	return nil
+}
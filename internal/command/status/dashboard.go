@@ -0,0 +1,178 @@
+package status
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/samber/lo"
+	"golang.org/x/exp/slices"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/render"
+)
+
+// dashboardEvents is how many of the most recent machine events the
+// dashboard shows.
+const dashboardEvents = 10
+
+// renderMachineDashboard renders the live view of --watch for machines apps:
+// the machines by process group and region, followed by their most recent
+// events.
+func renderMachineDashboard(ctx context.Context, flapsClient *flaps.Client, app *api.AppCompact, out io.Writer) error {
+	machines, err := flapsClient.ListActive(ctx)
+	if err != nil {
+		return err
+	}
+
+	machines = lo.Filter(machines, func(m *api.Machine, _ int) bool {
+		return m.IsAppsV2()
+	})
+
+	image, err := getImage(machines)
+	if err != nil {
+		return err
+	}
+
+	obj := [][]string{{app.Name, app.Organization.Slug, app.Hostname, image}}
+	if err := render.VerticalTable(out, "App", obj, "Name", "Owner", "Hostname", "Image"); err != nil {
+		return err
+	}
+
+	if len(machines) == 0 {
+		_, err := fmt.Fprintln(out, "No machines are running on this app.")
+		return err
+	}
+
+	groups := lo.GroupBy(machines, func(m *api.Machine) string {
+		return getProcessgroup(m)
+	})
+	groupNames := lo.Keys(groups)
+	slices.Sort(groupNames)
+
+	summary := make([][]string, 0, len(groups))
+	for _, name := range groupNames {
+		group := groups[name]
+		started := lo.CountBy(group, func(m *api.Machine) bool {
+			return m.State == api.MachineStateStarted
+		})
+		summary = append(summary, []string{
+			name,
+			fmt.Sprintf("%d/%d", started, len(group)),
+			formatRegions(group),
+			render.MachineHealthChecksSummary(group...),
+		})
+	}
+
+	if err := render.Table(out, "Process Groups", summary, "Process", "Started", "Regions", "Checks"); err != nil {
+		return err
+	}
+
+	sort.Slice(machines, func(i, j int) bool {
+		a, b := machines[i], machines[j]
+		return slices.Compare(
+			[]string{getProcessgroup(a), a.Region, a.ID},
+			[]string{getProcessgroup(b), b.Region, b.ID},
+		) < 0
+	})
+
+	rows := make([][]string, 0, len(machines))
+	for _, m := range machines {
+		rows = append(rows, []string{
+			getProcessgroup(m),
+			m.Region,
+			m.ID,
+			m.State,
+			render.MachineHealthChecksSummary(m),
+			strconv.Itoa(restartCount(m)),
+			getReleaseVersion(m),
+			m.ImageRefWithVersion(),
+			m.UpdatedAt,
+		})
+	}
+
+	if err := render.Table(out, "Machines", rows, "Process", "Region", "ID", "State", "Checks", "Restarts", "Version", "Image", "Last Updated"); err != nil {
+		return err
+	}
+
+	events := recentEvents(machines, dashboardEvents)
+	if len(events) == 0 {
+		return nil
+	}
+
+	eventRows := make([][]string, 0, len(events))
+	for _, e := range events {
+		eventRows = append(eventRows, []string{
+			humanize.Time(time.UnixMilli(e.Timestamp)),
+			e.machine.ID,
+			e.machine.Region,
+			e.Type,
+			e.Status,
+			e.Source,
+		})
+	}
+
+	return render.Table(out, "Recent Events", eventRows, "When", "Machine", "Region", "Type", "Status", "Source")
+}
+
+// formatRegions returns the regions of machines along with how many machines
+// are in each, e.g. "fra(3),mia".
+func formatRegions(machines []*api.Machine) string {
+	counts := lo.CountValues(lo.Map(machines, func(m *api.Machine, _ int) string {
+		return m.Region
+	}))
+
+	regions := make([]string, 0, len(counts))
+	for region, count := range counts {
+		if count > 1 {
+			region = fmt.Sprintf("%s(%d)", region, count)
+		}
+		regions = append(regions, region)
+	}
+	slices.Sort(regions)
+
+	return strings.Join(regions, ",")
+}
+
+// restartCount returns how many times the machine was restarted by its restart
+// policy, as reported by its most recent events.
+func restartCount(m *api.Machine) (count int) {
+	for _, e := range m.Events {
+		if e.Request != nil && e.Request.RestartCount > count {
+			count = e.Request.RestartCount
+		}
+	}
+
+	return
+}
+
+type machineEvent struct {
+	*api.MachineEvent
+	machine *api.Machine
+}
+
+// recentEvents returns the n most recent events of machines, newest first.
+func recentEvents(machines []*api.Machine, n int) []machineEvent {
+	var events []machineEvent
+	for _, m := range machines {
+		for _, e := range m.Events {
+			events = append(events, machineEvent{MachineEvent: e, machine: m})
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp > events[j].Timestamp
+	})
+
+	if len(events) > n {
+		events = events[:n]
+	}
+
+	return events
+}
@@ -0,0 +1,54 @@
+package status
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/superfly/flyctl/api"
+)
+
+func TestRestartCount(t *testing.T) {
+	m := &api.Machine{
+		Events: []*api.MachineEvent{
+			{Type: "exit", Request: &api.MachineRequest{RestartCount: 2}},
+			{Type: "start"},
+			{Type: "exit", Request: &api.MachineRequest{RestartCount: 1}},
+		},
+	}
+
+	require.Equal(t, 2, restartCount(m))
+	require.Equal(t, 0, restartCount(&api.Machine{}))
+}
+
+func TestRecentEvents(t *testing.T) {
+	a := &api.Machine{
+		ID: "a",
+		Events: []*api.MachineEvent{
+			{Type: "start", Timestamp: 30},
+			{Type: "launch", Timestamp: 10},
+		},
+	}
+	b := &api.Machine{
+		ID: "b",
+		Events: []*api.MachineEvent{
+			{Type: "exit", Timestamp: 40},
+			{Type: "start", Timestamp: 20},
+		},
+	}
+
+	events := recentEvents([]*api.Machine{a, b}, 3)
+
+	require.Len(t, events, 3)
+	require.Equal(t, int64(40), events[0].Timestamp)
+	require.Equal(t, "b", events[0].machine.ID)
+	require.Equal(t, int64(30), events[1].Timestamp)
+	require.Equal(t, int64(20), events[2].Timestamp)
+}
+
+func TestFormatRegions(t *testing.T) {
+	require.Equal(t, "fra(2),mia", formatRegions([]*api.Machine{
+		{Region: "mia"},
+		{Region: "fra"},
+		{Region: "fra"},
+	}))
+}
@@ -66,7 +66,7 @@ func runInstance(ctx context.Context) (err error) {
 	out := iostreams.FromContext(ctx).Out
 	if config.FromContext(ctx).JSONOutput {
 		// TODO: checks & recent events are being outputted twice
-		err = render.JSON(out,
+		err = render.Output(ctx, out,
 			map[string]interface{}{
 				"Instance":      alloc,
 				"Recent Events": alloc.Events,
@@ -7,6 +7,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/superfly/flyctl/api"
 	"github.com/superfly/flyctl/client"
@@ -16,6 +17,7 @@ import (
 	"github.com/superfly/flyctl/internal/render"
 	"github.com/superfly/flyctl/iostreams"
 	"golang.org/x/exp/slices"
+	"golang.org/x/sync/errgroup"
 )
 
 func getFromMetadata(m *api.Machine, key string) string {
@@ -76,7 +78,6 @@ func renderMachineStatus(ctx context.Context, app *api.AppCompact, out io.Writer
 	var (
 		io         = iostreams.FromContext(ctx)
 		colorize   = io.ColorScheme()
-		client     = client.FromContext(ctx).API()
 		jsonOutput = config.FromContext(ctx).JSONOutput
 	)
 
@@ -107,15 +108,15 @@ func renderMachineStatus(ctx context.Context, app *api.AppCompact, out io.Writer
 
 	var updatable []*api.Machine
 
-	for _, machine := range machines {
-		image := fmt.Sprintf("%s:%s", machine.ImageRef.Repository, machine.ImageRef.Tag)
+	latestImages, err := getLatestImageDetails(ctx, machines)
+	if err != nil {
+		return err
+	}
 
-		latestImage, err := client.GetLatestImageDetails(ctx, image)
-		if err != nil {
-			if strings.Contains(err.Error(), "Unknown repository") {
-				continue
-			}
-			return fmt.Errorf("unable to fetch latest image details for %s: %w", image, err)
+	for _, machine := range machines {
+		latestImage, ok := latestImages[machineImage(machine)]
+		if !ok {
+			continue
 		}
 
 		if latest == nil {
@@ -251,14 +252,13 @@ func renderMachineJSONStatus(ctx context.Context, app *api.AppCompact, machines
 		"PlatformVersion": app.PlatformVersion,
 		"Machines":        machinesToShow,
 	}
-	return render.JSON(out, status)
+	return render.Output(ctx, out, status)
 }
 
 func renderPGStatus(ctx context.Context, app *api.AppCompact, machines []*api.Machine, out io.Writer) (err error) {
 	var (
 		io       = iostreams.FromContext(ctx)
 		colorize = io.ColorScheme()
-		client   = client.FromContext(ctx).API()
 	)
 
 	if len(machines) > 0 {
@@ -277,17 +277,16 @@ func renderPGStatus(ctx context.Context, app *api.AppCompact, machines []*api.Ma
 	var latest *api.ImageVersion
 	var updatable []*api.Machine
 
-	for _, machine := range machines {
-		image := fmt.Sprintf("%s:%s", machine.ImageRef.Repository, machine.ImageRef.Tag)
-
-		latestImage, err := client.GetLatestImageDetails(ctx, image)
+	latestImages, err := getLatestImageDetails(ctx, machines)
+	if err != nil {
+		return err
+	}
 
-		if err != nil && strings.Contains(err.Error(), "Unknown repository") {
+	for _, machine := range machines {
+		latestImage, ok := latestImages[machineImage(machine)]
+		if !ok {
 			continue
 		}
-		if err != nil {
-			return fmt.Errorf("unable to fetch latest image details for %s: %w", image, err)
-		}
 
 		if latest == nil {
 			latest = latestImage
@@ -374,3 +373,49 @@ func isQuorumMet(machines []*api.Machine) (bool, string) {
 
 	return true, ""
 }
+
+func machineImage(machine *api.Machine) string {
+	return fmt.Sprintf("%s:%s", machine.ImageRef.Repository, machine.ImageRef.Tag)
+}
+
+// getLatestImageDetails fetches the details of the latest version of each of
+// the images the machines run, once per image and concurrently. Images of
+// unknown repositories are left out.
+func getLatestImageDetails(ctx context.Context, machines []*api.Machine) (map[string]*api.ImageVersion, error) {
+	var (
+		client  = client.FromContext(ctx).API()
+		mu      sync.Mutex
+		latest  = map[string]*api.ImageVersion{}
+		fetched = map[string]bool{}
+	)
+
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.SetLimit(flaps.MaxConcurrentRequests)
+	for _, machine := range machines {
+		image := machineImage(machine)
+		if fetched[image] {
+			continue
+		}
+		fetched[image] = true
+
+		eg.Go(func() error {
+			latestImage, err := client.GetLatestImageDetails(ctx, image)
+			if err != nil {
+				if strings.Contains(err.Error(), "Unknown repository") {
+					return nil
+				}
+				return fmt.Errorf("unable to fetch latest image details for %s: %w", image, err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			latest[image] = latestImage
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+
+	return latest, nil
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/superfly/flyctl/api"
 
 	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
 	"github.com/superfly/flyctl/internal/appconfig"
 	"github.com/superfly/flyctl/internal/command"
 	"github.com/superfly/flyctl/internal/config"
@@ -32,6 +33,10 @@ func New() (cmd *cobra.Command) {
 		long = `Show the application's current status including application
 details, tasks, most recent deployment details and in which regions it is
 currently allocated.
+
+With --watch, the status is refreshed continuously. For machines apps, it
+shows the machines by process group and region, with their state, checks,
+restarts and image version, along with their most recent events.
 `
 		short = "Show app status"
 	)
@@ -124,7 +129,7 @@ func once(ctx context.Context, out io.Writer) (err error) {
 	}
 
 	if jsonOutput {
-		err = render.JSON(out, status)
+		err = render.Output(ctx, out, status)
 
 		return
 	}
@@ -202,12 +207,29 @@ func runWatch(ctx context.Context) (err error) {
 
 	appName := appconfig.NameFromContext(ctx)
 
+	app, err := client.FromContext(ctx).API().GetAppCompact(ctx, appName)
+	if err != nil {
+		return fmt.Errorf("failed to get app: %w", err)
+	}
+
+	show := once
+	if app.PlatformVersion == "machines" && !app.IsPostgresApp() {
+		flapsClient, err := flaps.New(ctx, app)
+		if err != nil {
+			return err
+		}
+
+		show = func(ctx context.Context, out io.Writer) error {
+			return renderMachineDashboard(ctx, flapsClient, app, out)
+		}
+	}
+
 	var buf bytes.Buffer
 
 	for err == nil {
 		buf.Reset()
 
-		if err = once(ctx, &buf); err != nil {
+		if err = show(ctx, &buf); err != nil {
 			break
 		}
 
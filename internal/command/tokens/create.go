@@ -3,6 +3,9 @@ package tokens
 import (
 	"context"
 	"fmt"
+	"math"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -27,25 +30,40 @@ func newCreate() *cobra.Command {
 
 	cmd.AddCommand(
 		newDeploy(),
+		newReadOnly(),
 	)
 
 	return cmd
 }
 
+// expiryFlag is the expiry of tokens, which accepts days, weeks and years on
+// top of the units of Go durations.
+var expiryFlag = flag.String{
+	Name:        "expiry",
+	Shorthand:   "x",
+	Description: "The duration that the token will be valid, e.g. 90d, 12w or 1y",
+	Default:     "20y",
+}
+
 func newDeploy() *cobra.Command {
 	const (
 		short = "Create deploy tokens"
-		long  = "Create an API token limited to managing a single app and its resources. Also available as TOKENS DEPLOY. Tokens are valid for 20 years by default. We recommend using a shorter expiry if practical."
+		long  = `Create an API token limited to deploying and managing the resources of a
+single app, or of every app of an organization with --scope org:<slug>. The
+token is limited to the current app by default. Also available as TOKENS
+DEPLOY. Tokens are valid for 20 years by default. We recommend using a
+shorter expiry if practical.`
 		usage = "deploy"
 	)
 
 	cmd := command.New(usage, short, long, runDeploy,
 		command.RequireSession,
-		command.RequireAppName,
+		command.LoadAppNameIfPresent,
 	)
 
 	flag.Add(cmd,
 		flag.App(),
+		flag.AppConfig(),
 		flag.JSONOutput(),
 		flag.String{
 			Name:        "name",
@@ -53,35 +71,166 @@ func newDeploy() *cobra.Command {
 			Description: "Token name",
 			Default:     "flyctl deploy token",
 		},
-		flag.Duration{
-			Name:        "expiry",
-			Shorthand:   "x",
-			Description: "The duration that the token will be valid",
-			Default:     time.Hour * 24 * 365 * 20,
+		flag.String{
+			Name:        "scope",
+			Description: "What the token is limited to, app:<name> or org:<slug>",
+		},
+		expiryFlag,
+	)
+
+	return cmd
+}
+
+func newReadOnly() *cobra.Command {
+	const (
+		short = "Create read-only tokens"
+		long  = `Create an API token limited to reading the state, metrics and logs of the
+apps of an organization, without changing them. Tokens are valid for 20
+years by default. We recommend using a shorter expiry if practical.`
+		usage = "readonly"
+	)
+
+	cmd := command.New(usage, short, long, runReadOnly,
+		command.RequireSession,
+	)
+
+	flag.Add(cmd,
+		flag.JSONOutput(),
+		flag.String{
+			Name:        "name",
+			Shorthand:   "n",
+			Description: "Token name",
+			Default:     "flyctl read-only token",
+		},
+		flag.String{
+			Name:        "scope",
+			Description: "The organization the token is limited to, org:<slug>",
 		},
+		expiryFlag,
 	)
 
 	return cmd
 }
 
 func runDeploy(ctx context.Context) (err error) {
-	appName := appconfig.NameFromContext(ctx)
-	apiClient := client.FromContext(ctx).API()
+	expiry, err := parseExpiry(flag.GetString(ctx, "expiry"))
+	if err != nil {
+		return err
+	}
 
-	app, err := apiClient.GetAppCompact(ctx, appName)
+	kind, name, err := parseScope(flag.GetString(ctx, "scope"))
+	if err != nil {
+		return err
+	}
+
+	var token string
+	switch kind {
+	case "app":
+		if name == "" {
+			if name = appconfig.NameFromContext(ctx); name == "" {
+				return fmt.Errorf("no app to limit the token to, pass --scope app:<name> or --app")
+			}
+		}
+		token, err = CreateDeployToken(ctx, name, flag.GetString(ctx, "name"), expiry)
+	case "org":
+		token, err = createOrgToken(ctx, name, "deploy_organization", flag.GetString(ctx, "name"), expiry)
+	}
+	if err != nil {
+		return err
+	}
+
+	return printToken(ctx, token)
+}
+
+func runReadOnly(ctx context.Context) (err error) {
+	expiry, err := parseExpiry(flag.GetString(ctx, "expiry"))
 	if err != nil {
-		return fmt.Errorf("failed retrieving app %s: %w", appName, err)
+		return err
 	}
 
-	expiry := ""
-	if expiryDuration := flag.GetDuration(ctx, "expiry"); expiryDuration != 0 {
-		expiry = expiryDuration.String()
+	kind, name, err := parseScope(flag.GetString(ctx, "scope"))
+	if err != nil {
+		return err
+	}
+	if kind != "org" {
+		return fmt.Errorf("read-only tokens are limited to organizations, pass --scope org:<slug>")
+	}
+
+	token, err := createOrgToken(ctx, name, "readonly_organization", flag.GetString(ctx, "name"), expiry)
+	if err != nil {
+		return err
+	}
+
+	return printToken(ctx, token)
+}
+
+func printToken(ctx context.Context, token string) error {
+	io := iostreams.FromContext(ctx)
+	if config.FromContext(ctx).JSONOutput {
+		return render.Output(ctx, io.Out, map[string]string{"token": token})
+	}
+
+	fmt.Fprintln(io.Out, token)
+	return nil
+}
+
+// parseScope splits scopes of the form kind:name. An empty scope is the app
+// of the command.
+func parseScope(scope string) (kind, name string, err error) {
+	if scope == "" {
+		return "app", "", nil
+	}
+
+	kind, name, ok := strings.Cut(scope, ":")
+	if !ok || name == "" || (kind != "app" && kind != "org") {
+		return "", "", fmt.Errorf("invalid scope %q, use app:<name> or org:<slug>", scope)
+	}
+
+	return kind, name, nil
+}
+
+// maxExpiryDays is the largest number of days a duration holds.
+const maxExpiryDays = int64(math.MaxInt64 / (24 * time.Hour))
+
+// parseExpiry converts the expiry to the Go duration the API expects.
+func parseExpiry(expiry string) (string, error) {
+	if expiry == "" {
+		return "", nil
+	}
+
+	days := map[string]int64{"d": 1, "w": 7, "y": 365}
+	if n, err := strconv.ParseInt(expiry[:len(expiry)-1], 10, 64); err == nil {
+		if multiplier, ok := days[expiry[len(expiry)-1:]]; ok && n > 0 {
+			// durations can't exceed about 292 years
+			if n > maxExpiryDays/multiplier {
+				return "", fmt.Errorf("expiry %q is too long, it can be at most %dd", expiry, maxExpiryDays)
+			}
+			return (time.Duration(n*multiplier) * 24 * time.Hour).String(), nil
+		}
+	}
+
+	d, err := time.ParseDuration(expiry)
+	if err != nil || d <= 0 {
+		return "", fmt.Errorf("invalid expiry %q, use a duration like 90d, 12w, 1y or 720h", expiry)
+	}
+
+	return d.String(), nil
+}
+
+// CreateDeployToken creates a token limited to deploying the app, valid for
+// the expiry or the default duration when empty, and returns its header.
+func CreateDeployToken(ctx context.Context, appName, name, expiry string) (string, error) {
+	apiClient := client.FromContext(ctx).API()
+
+	app, err := apiClient.GetAppCompact(ctx, appName)
+	if err != nil {
+		return "", fmt.Errorf("failed retrieving app %s: %w", appName, err)
 	}
 
 	resp, err := gql.CreateLimitedAccessToken(
 		ctx,
 		apiClient.GenqClient,
-		flag.GetString(ctx, "name"),
+		name,
 		app.Organization.ID,
 		"deploy",
 		&gql.LimitedAccessTokenOptions{
@@ -90,15 +239,33 @@ func runDeploy(ctx context.Context) (err error) {
 		expiry,
 	)
 	if err != nil {
-		return fmt.Errorf("failed creating deploy token: %w", err)
+		return "", fmt.Errorf("failed creating deploy token: %w", err)
 	}
 
-	io := iostreams.FromContext(ctx)
-	if config.FromContext(ctx).JSONOutput {
-		render.JSON(io.Out, map[string]string{"token": resp.CreateLimitedAccessToken.LimitedAccessToken.TokenHeader})
-	} else {
-		fmt.Fprintln(io.Out, resp.CreateLimitedAccessToken.LimitedAccessToken.TokenHeader)
+	return resp.CreateLimitedAccessToken.LimitedAccessToken.TokenHeader, nil
+}
+
+// createOrgToken creates a token of the profile limited to the organization.
+func createOrgToken(ctx context.Context, orgSlug, profile, name, expiry string) (string, error) {
+	apiClient := client.FromContext(ctx).API()
+
+	org, err := apiClient.GetOrganizationBySlug(ctx, orgSlug)
+	if err != nil {
+		return "", fmt.Errorf("failed retrieving organization %s: %w", orgSlug, err)
 	}
 
-	return nil
+	resp, err := gql.CreateLimitedAccessToken(
+		ctx,
+		apiClient.GenqClient,
+		name,
+		org.ID,
+		profile,
+		&gql.LimitedAccessTokenOptions{},
+		expiry,
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed creating token: %w", err)
+	}
+
+	return resp.CreateLimitedAccessToken.LimitedAccessToken.TokenHeader, nil
 }
@@ -0,0 +1,43 @@
+package tokens
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseExpiry(t *testing.T) {
+	cases := []struct {
+		expiry   string
+		expected string
+		err      string
+	}{
+		{expiry: "", expected: ""},
+		{expiry: "90d", expected: "2160h0m0s"},
+		{expiry: "12w", expected: "2016h0m0s"},
+		{expiry: "1y", expected: "8760h0m0s"},
+		{expiry: "720h", expected: "720h0m0s"},
+		{expiry: "106751d", expected: "2562024h0m0s"},
+		{expiry: "106752d", err: "is too long"},
+		{expiry: "1000y", err: "is too long"},
+		{expiry: "999999999999w", err: "is too long"},
+		{expiry: "99999999999999999999d", err: "invalid expiry"},
+		{expiry: "0d", err: "invalid expiry"},
+		{expiry: "-1y", err: "invalid expiry"},
+		{expiry: "-1h", err: "invalid expiry"},
+		{expiry: "soon", err: "invalid expiry"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.expiry, func(t *testing.T) {
+			actual, err := parseExpiry(tc.expiry)
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package tokens
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/gql"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/format"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newList() *cobra.Command {
+	const (
+		short = "List the tokens of an app"
+		long  = "List the tokens limited to an app, along with their scope, creator and expiry. Revoke them with TOKENS REVOKE."
+		usage = "list"
+	)
+
+	cmd := command.New(usage, short, long, runList,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	cmd.Aliases = []string{"ls"}
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.JSONOutput(),
+	)
+
+	return cmd
+}
+
+// token is a token as listed.
+type token struct {
+	ID        string
+	Name      string
+	Scope     string
+	CreatedBy string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+func runList(ctx context.Context) error {
+	var (
+		io      = iostreams.FromContext(ctx)
+		appName = appconfig.NameFromContext(ctx)
+	)
+
+	_ = `# @genqlient
+	query ListAppTokens($appName: String!) {
+		app(name: $appName) {
+			limitedAccessTokens {
+				nodes {
+					id
+					name
+					createdAt
+					expiresAt
+					user {
+						email
+					}
+				}
+			}
+		}
+	}
+	`
+	resp, err := gql.ListAppTokens(ctx, client.FromContext(ctx).API().GenqClient, appName)
+	if err != nil {
+		return fmt.Errorf("failed listing tokens of %s: %w", appName, err)
+	}
+
+	tokens := make([]token, 0, len(resp.App.LimitedAccessTokens.Nodes))
+	for _, t := range resp.App.LimitedAccessTokens.Nodes {
+		tokens = append(tokens, token{
+			ID:        t.Id,
+			Name:      t.Name,
+			Scope:     "app:" + appName,
+			CreatedBy: t.User.Email,
+			CreatedAt: t.CreatedAt,
+			ExpiresAt: t.ExpiresAt,
+		})
+	}
+
+	if config.FromContext(ctx).JSONOutput {
+		return render.Output(ctx, io.Out, tokens)
+	}
+
+	rows := make([][]string, 0, len(tokens))
+	for _, t := range tokens {
+		rows = append(rows, []string{
+			t.ID,
+			t.Name,
+			t.Scope,
+			t.CreatedBy,
+			format.RelativeTime(t.CreatedAt),
+			t.ExpiresAt.Format("2006-01-02"),
+		})
+	}
+
+	return render.Table(io.Out, "", rows, "ID", "Name", "Scope", "Created By", "Created", "Expires")
+}
@@ -0,0 +1,52 @@
+package tokens
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/gql"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newRevoke() *cobra.Command {
+	const (
+		short = "Revoke tokens"
+		long  = "Revoke tokens by the IDs listed by TOKENS LIST. Revoked tokens can't be used anymore."
+		usage = "revoke <ID>..."
+	)
+
+	cmd := command.New(usage, short, long, runRevoke,
+		command.RequireSession,
+	)
+
+	cmd.Args = cobra.MinimumNArgs(1)
+
+	return cmd
+}
+
+func runRevoke(ctx context.Context) error {
+	var (
+		out    = iostreams.FromContext(ctx).Out
+		client = client.FromContext(ctx).API().GenqClient
+	)
+
+	_ = `# @genqlient
+	mutation RevokeLimitedAccessToken($token: ID!) {
+		deleteLimitedAccessToken(input: {token: $token}) {
+			token
+		}
+	}
+	`
+	for _, id := range flag.Args(ctx) {
+		if _, err := gql.RevokeLimitedAccessToken(ctx, client, id); err != nil {
+			return fmt.Errorf("failed revoking token %s: %w", id, err)
+		}
+		fmt.Fprintf(out, "Revoked token %s\n", id)
+	}
+
+	return nil
+}
@@ -19,6 +19,8 @@ func New() *cobra.Command {
 
 	cmd.AddCommand(
 		newCreate(),
+		newList(),
+		newRevoke(),
 		hiddenDeploy,
 	)
 
@@ -3,7 +3,6 @@ package version
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 
 	"github.com/spf13/cobra"
@@ -15,6 +14,7 @@ import (
 	"github.com/superfly/flyctl/internal/command"
 	"github.com/superfly/flyctl/internal/config"
 	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/render"
 )
 
 const saveInstallName = "saveinstall"
@@ -66,7 +66,7 @@ func run(ctx context.Context) (err error) {
 	)
 
 	if cfg.JSONOutput {
-		err = json.NewEncoder(out).Encode(info)
+		err = render.Output(ctx, out, info)
 	} else {
 		_, err = fmt.Fprintln(out, info)
 	}
@@ -117,7 +117,7 @@ func runCreate(ctx context.Context) error {
 	out := iostreams.FromContext(ctx).Out
 
 	if cfg.JSONOutput {
-		return render.JSON(out, volume)
+		return render.Output(ctx, out, volume)
 	}
 
 	return printVolume(out, volume)
@@ -3,10 +3,13 @@ package volumes
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/dustin/go-humanize"
 	"github.com/spf13/cobra"
 
 	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/flaps"
 	"github.com/superfly/flyctl/iostreams"
 
 	"github.com/superfly/flyctl/client"
@@ -14,15 +17,19 @@ import (
 	"github.com/superfly/flyctl/internal/command"
 	"github.com/superfly/flyctl/internal/config"
 	"github.com/superfly/flyctl/internal/flag"
+	mach "github.com/superfly/flyctl/internal/machine"
 	"github.com/superfly/flyctl/internal/prompt"
 	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/internal/watch"
 )
 
 func newExtend() *cobra.Command {
 	const (
 		long = `Extends a target volume to the size specified. Volumes with attached nomad allocations
 		will be restarted automatically. Machines will require a manual restart to increase the size
-		of the FS.`
+		of the FS, unless --restart is set: the machine is then cordoned, restarted and uncordoned
+		once its checks pass, and its FS is checked to have grown. With --cluster, every volume of
+		the Postgres cluster the volume belongs to is extended this way, the leader last.`
 
 		short = "Extend a target volume"
 
@@ -48,6 +55,14 @@ func newExtend() *cobra.Command {
 			Name:        "auto-confirm",
 			Description: "Will automatically confirm changes without an interactive prompt.",
 		},
+		flag.Bool{
+			Name:        "restart",
+			Description: "Restart the machine the volume is attached to for its FS to grow",
+		},
+		flag.Bool{
+			Name:        "cluster",
+			Description: "Extend and restart every member of the Postgres cluster of the volume. Implies --restart",
+		},
 	)
 
 	flag.Add(cmd, flag.JSONOutput())
@@ -74,6 +89,10 @@ func runExtend(ctx context.Context) error {
 		return fmt.Errorf("Volume size must be specified")
 	}
 
+	if app.PlatformVersion == "machines" && (flag.GetBool(ctx, "restart") || flag.GetBool(ctx, "cluster")) {
+		return runExtendWithRestart(ctx, app.Name, volID, sizeGB)
+	}
+
 	if app.PlatformVersion == "nomad" {
 		if !flag.GetBool(ctx, "auto-confirm") {
 			switch confirmed, err := prompt.Confirm(ctx, "Extending this volume will result in a VM restart. Continue?"); {
@@ -102,7 +121,7 @@ func runExtend(ctx context.Context) error {
 	out := iostreams.FromContext(ctx).Out
 
 	if cfg.JSONOutput {
-		return render.JSON(out, volume)
+		return render.Output(ctx, out, volume)
 	}
 
 	if err := printVolume(out, volume); err != nil {
@@ -110,8 +129,187 @@ func runExtend(ctx context.Context) error {
 	}
 
 	if app.PlatformVersion == "machines" {
-		fmt.Fprintln(out, colorize.Yellow("You will need to stop and start your machine to increase the size of the FS"))
+		fmt.Fprintln(out, colorize.Yellow("You will need to stop and start your machine to increase the size of the FS, or extend with --restart"))
 	}
 
 	return nil
 }
+
+// runExtendWithRestart extends the volume, or every volume of its Postgres
+// cluster, restarting the machines they are attached to one at a time.
+func runExtendWithRestart(ctx context.Context, appName, volID string, sizeGB int) error {
+	io := iostreams.FromContext(ctx)
+
+	app, err := client.FromContext(ctx).API().GetAppCompact(ctx, appName)
+	if err != nil {
+		return fmt.Errorf("failed retrieving app %s: %w", appName, err)
+	}
+
+	if flag.GetBool(ctx, "cluster") && !app.IsPostgresApp() {
+		return fmt.Errorf("--cluster is only supported by Postgres apps")
+	}
+
+	flapsClient, err := flaps.New(ctx, app)
+	if err != nil {
+		return err
+	}
+	ctx = flaps.NewContext(ctx, flapsClient)
+
+	machines, err := mach.ListActive(ctx)
+	if err != nil {
+		return fmt.Errorf("machines could not be retrieved %w", err)
+	}
+
+	targets, err := extendTargets(machines, volID, flag.GetBool(ctx, "cluster"))
+	if err != nil {
+		return err
+	}
+
+	if !flag.GetBool(ctx, "auto-confirm") {
+		switch confirmed, err := prompt.Confirmf(ctx, "Extending will restart %d machine(s) of %s one at a time. Continue?", len(targets), app.Name); {
+		case err == nil:
+			if !confirmed {
+				return nil
+			}
+		case prompt.IsNonInteractive(err):
+			return prompt.NonInteractiveError("auto-confirm flag must be specified when not running interactively")
+		default:
+			return err
+		}
+	}
+
+	targets, releaseLeaseFunc, err := mach.AcquireLeases(ctx, targets)
+	defer releaseLeaseFunc(ctx, targets)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range targets {
+		if err := extendAndRestart(ctx, m, sizeGB); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(io.Out, "Extended %d volume(s) of %s to %dGB\n", len(targets), app.Name, sizeGB)
+	return nil
+}
+
+// extendTargets returns the machine the volume is attached to, or with
+// cluster every machine with a volume, the leader last.
+func extendTargets(machines []*api.Machine, volID string, cluster bool) ([]*api.Machine, error) {
+	var (
+		attached *api.Machine
+		replicas []*api.Machine
+		leader   *api.Machine
+	)
+
+	for _, m := range machines {
+		if m.Config == nil || len(m.Config.Mounts) == 0 {
+			continue
+		}
+		if m.Config.Mounts[0].Volume == volID {
+			attached = m
+		}
+		if isLeader(m) {
+			leader = m
+		} else {
+			replicas = append(replicas, m)
+		}
+	}
+
+	switch {
+	case attached == nil:
+		return nil, fmt.Errorf("volume %s isn't attached to any active machine", volID)
+	case !cluster:
+		return []*api.Machine{attached}, nil
+	case leader == nil:
+		return nil, fmt.Errorf("no active leader found")
+	default:
+		return append(replicas, leader), nil
+	}
+}
+
+// isLeader reports whether the machine leads its Postgres cluster, according
+// to its role check.
+func isLeader(m *api.Machine) bool {
+	for _, check := range m.Checks {
+		if check.Name == "role" && check.Status == "passing" {
+			return check.Output == "leader" || check.Output == "primary"
+		}
+	}
+	return false
+}
+
+// extendAndRestart extends the volume of the machine, then takes the machine
+// out of service while it restarts for its FS to grow.
+func extendAndRestart(ctx context.Context, m *api.Machine, sizeGB int) error {
+	var (
+		io          = iostreams.FromContext(ctx)
+		colorize    = io.ColorScheme()
+		client      = client.FromContext(ctx).API()
+		flapsClient = flaps.FromContext(ctx)
+		mount       = m.Config.Mounts[0]
+	)
+
+	fmt.Fprintf(io.Out, "%s Extending volume %s of machine %s\n", colorize.Bold("==>"), mount.Volume, m.ID)
+	if _, err := client.ExtendVolume(ctx, api.ExtendVolumeInput{VolumeID: mount.Volume, SizeGb: sizeGB}); err != nil {
+		return fmt.Errorf("failed to extend volume %s: %w", mount.Volume, err)
+	}
+
+	if err := mach.Cordon(ctx, m); err != nil {
+		return fmt.Errorf("failed to cordon machine %s: %w", m.ID, err)
+	}
+	m, err := refreshMachine(ctx, m)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(io.Out, "%s Restarting machine %s\n", colorize.Bold("==>"), m.ID)
+	if err := flapsClient.Restart(ctx, api.RestartMachineInput{ID: m.ID}, m.LeaseNonce); err != nil {
+		return err
+	}
+	if err := mach.WaitForStartOrStop(ctx, m, "start", 5*time.Minute); err != nil {
+		return err
+	}
+	if err := watch.MachinesChecks(ctx, []*api.Machine{m}); err != nil {
+		return fmt.Errorf("failed to wait for health checks to pass: %w", err)
+	}
+
+	if err := mach.Uncordon(ctx, m); err != nil {
+		return fmt.Errorf("failed to uncordon machine %s: %w", m.ID, err)
+	}
+
+	res, err := flapsClient.Exec(ctx, m.ID, &api.MachineExecRequest{
+		Cmd:     "df -P -B1 " + mount.Path,
+		Timeout: 30,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to check the FS of machine %s: %w", m.ID, err)
+	}
+	size, _, err := parseDF(res.StdOut)
+	if err != nil {
+		return fmt.Errorf("failed to check the FS of machine %s: %w", m.ID, err)
+	}
+	if !filesystemGrew(size, sizeGB) {
+		return fmt.Errorf("the FS of machine %s is %s after restarting, smaller than the %dGB volume", m.ID, humanize.IBytes(uint64(size)), sizeGB)
+	}
+
+	fmt.Fprintf(io.Out, "FS of machine %s is now %s\n", m.ID, humanize.IBytes(uint64(size)))
+	return nil
+}
+
+// refreshMachine fetches the machine after an update, keeping its lease.
+func refreshMachine(ctx context.Context, m *api.Machine) (*api.Machine, error) {
+	updated, err := flaps.FromContext(ctx).Get(ctx, m.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed retrieving machine %s: %w", m.ID, err)
+	}
+	updated.LeaseNonce = m.LeaseNonce
+	return updated, nil
+}
+
+// filesystemGrew reports whether an FS of size bytes fills a volume of sizeGB,
+// allowing for the space the FS itself takes.
+func filesystemGrew(size int64, sizeGB int) bool {
+	return float64(size) >= 0.9*float64(sizeGB)*(1<<30)
+}
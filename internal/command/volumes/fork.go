@@ -88,7 +88,7 @@ func runFork(ctx context.Context) error {
 	out := iostreams.FromContext(ctx).Out
 
 	if cfg.JSONOutput {
-		return render.JSON(out, volume)
+		return render.Output(ctx, out, volume)
 	}
 
 	if err := printVolume(out, volume); err != nil {
@@ -8,6 +8,8 @@ import (
 	"github.com/dustin/go-humanize"
 	"github.com/spf13/cobra"
 
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/flaps"
 	"github.com/superfly/flyctl/iostreams"
 
 	"github.com/superfly/flyctl/client"
@@ -20,7 +22,9 @@ import (
 
 func newList() *cobra.Command {
 	const (
-		long = "List all the volumes associated with this application."
+		long = `List all the volumes associated with this application. With --usage,
+the space used on the volumes attached to started machines is reported, and
+volumes filled above the threshold are flagged.`
 
 		short = "List the volumes for app"
 	)
@@ -35,6 +39,15 @@ func newList() *cobra.Command {
 	flag.Add(cmd,
 		flag.App(),
 		flag.AppConfig(),
+		flag.Bool{
+			Name:        "usage",
+			Description: "Report the space used on each volume",
+		},
+		flag.Int{
+			Name:        "threshold",
+			Description: "The usage percentage above which volumes are flagged, with --usage",
+			Default:     80,
+		},
 	)
 
 	flag.Add(cmd, flag.JSONOutput())
@@ -52,10 +65,14 @@ func runList(ctx context.Context) error {
 		return fmt.Errorf("failed retrieving volumes: %w", err)
 	}
 
+	if flag.GetBool(ctx, "usage") {
+		return listUsage(ctx, appName, volumes)
+	}
+
 	out := iostreams.FromContext(ctx).Out
 
 	if cfg.JSONOutput {
-		return render.JSON(out, volumes)
+		return render.Output(ctx, out, volumes)
 	}
 
 	rows := make([][]string, 0, len(volumes))
@@ -91,3 +108,122 @@ func runList(ctx context.Context) error {
 
 	return render.Table(out, "", rows, "ID", "State", "Name", "Size", "Region", "Zone", "Encrypted", "Attached VM", "Created At")
 }
+
+type volumeUsage struct {
+	api.Volume
+	SizeBytes int64   `json:"size_bytes,omitempty"`
+	UsedBytes int64   `json:"used_bytes,omitempty"`
+	UsedPct   float64 `json:"used_percent,omitempty"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// listUsage reports the space used on the volumes, read with df on the
+// started machines they are attached to.
+func listUsage(ctx context.Context, appName string, volumes []api.Volume) error {
+	var (
+		io        = iostreams.FromContext(ctx)
+		cfg       = config.FromContext(ctx)
+		colorize  = io.ColorScheme()
+		threshold = float64(flag.GetInt(ctx, "threshold"))
+	)
+
+	flapsClient, err := flaps.NewFromAppName(ctx, appName)
+	if err != nil {
+		return err
+	}
+
+	usages := make([]volumeUsage, 0, len(volumes))
+	for _, volume := range volumes {
+		usage := volumeUsage{Volume: volume}
+		if err := readUsage(ctx, flapsClient, &usage); err != nil {
+			usage.Error = err.Error()
+		}
+		usages = append(usages, usage)
+	}
+
+	if cfg.JSONOutput {
+		return render.Output(ctx, io.Out, usages)
+	}
+
+	var flagged []volumeUsage
+	rows := make([][]string, 0, len(usages))
+	for _, usage := range usages {
+		used := "-"
+		switch {
+		case usage.Error != "":
+			used = usage.Error
+		case usage.SizeBytes > 0:
+			used = fmt.Sprintf("%s / %s (%.0f%%)", humanize.IBytes(uint64(usage.UsedBytes)), humanize.IBytes(uint64(usage.SizeBytes)), usage.UsedPct)
+			if usage.UsedPct >= threshold {
+				used = colorize.Red(used)
+				flagged = append(flagged, usage)
+			}
+		}
+
+		var attachedVMID string
+		if usage.AttachedMachine != nil {
+			attachedVMID = usage.AttachedMachine.ID
+		}
+
+		rows = append(rows, []string{
+			usage.ID,
+			usage.Name,
+			strconv.Itoa(usage.SizeGb) + "GB",
+			usage.Region,
+			attachedVMID,
+			used,
+		})
+	}
+
+	if err := render.Table(io.Out, "", rows, "ID", "Name", "Size", "Region", "Attached VM", "Used"); err != nil {
+		return err
+	}
+
+	for _, usage := range flagged {
+		fmt.Fprintln(io.Out, colorize.Yellow(fmt.Sprintf("Volume %s is %.0f%% full, extend it with fly volumes extend %s --size <GB> --restart", usage.ID, usage.UsedPct, usage.ID)))
+	}
+
+	return nil
+}
+
+// readUsage fills the usage of the volume when it is attached to a started
+// machine.
+func readUsage(ctx context.Context, flapsClient *flaps.Client, usage *volumeUsage) error {
+	if usage.App.PlatformVersion != "machines" || usage.AttachedMachine == nil {
+		return nil
+	}
+
+	m, err := flapsClient.Get(ctx, usage.AttachedMachine.ID)
+	if err != nil {
+		return err
+	}
+	if m.State != api.MachineStateStarted {
+		return nil
+	}
+
+	var path string
+	for _, mount := range m.Config.Mounts {
+		if mount.Volume == usage.ID {
+			path = mount.Path
+		}
+	}
+	if path == "" {
+		return nil
+	}
+
+	res, err := flapsClient.Exec(ctx, m.ID, &api.MachineExecRequest{
+		Cmd:     "df -P -B1 " + path,
+		Timeout: 30,
+	})
+	if err != nil {
+		return err
+	}
+
+	if usage.SizeBytes, usage.UsedBytes, err = parseDF(res.StdOut); err != nil {
+		return err
+	}
+	if usage.SizeBytes > 0 {
+		usage.UsedPct = 100 * float64(usage.UsedBytes) / float64(usage.SizeBytes)
+	}
+	return nil
+}
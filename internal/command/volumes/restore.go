@@ -0,0 +1,233 @@
+package volumes
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/internal/spinner"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newRestore() *cobra.Command {
+	const (
+		long = `Restore a snapshot into a new volume of the app, in any region. The
+volume is usable once the snapshot has been restored, which is reported
+until done unless --detach is set. With --attach-to, the volume replaces the
+volume of a stopped machine, mounted at the same path.`
+
+		short = "Restore a snapshot into a new volume"
+
+		usage = "restore <snapshot-id>"
+	)
+
+	cmd := command.New(usage, short, long, runRestore,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	cmd.Args = cobra.ExactArgs(1)
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.Region(),
+		flag.Detach(),
+		flag.String{
+			Name:        "name",
+			Description: "Name of the new volume, defaults to the name of the volume of the machine it is attached to",
+		},
+		flag.Int{
+			Name:        "size",
+			Shorthand:   "s",
+			Default:     3,
+			Description: "Size of volume in gigabytes, at least the size of the snapshotted volume",
+		},
+		flag.Bool{
+			Name:        "no-encryption",
+			Description: "Do not encrypt the volume contents",
+			Default:     false,
+		},
+		flag.String{
+			Name:        "attach-to",
+			Description: "ID of a stopped machine to attach the volume to, in place of its current volume",
+		},
+		flag.JSONOutput(),
+	)
+
+	return cmd
+}
+
+func runRestore(ctx context.Context) error {
+	var (
+		io     = iostreams.FromContext(ctx)
+		cfg    = config.FromContext(ctx)
+		client = client.FromContext(ctx).API()
+
+		snapshotID = flag.FirstArg(ctx)
+		appName    = appconfig.NameFromContext(ctx)
+		name       = flag.GetString(ctx, "name")
+		regionCode = flag.GetRegion(ctx)
+		machineID  = flag.GetString(ctx, "attach-to")
+	)
+
+	app, err := client.GetAppBasic(ctx, appName)
+	if err != nil {
+		return err
+	}
+
+	var machine *api.Machine
+	if machineID != "" {
+		flapsClient, err := flaps.NewFromAppName(ctx, appName)
+		if err != nil {
+			return err
+		}
+		ctx = flaps.NewContext(ctx, flapsClient)
+
+		if machine, err = flapsClient.Get(ctx, machineID); err != nil {
+			return fmt.Errorf("failed retrieving machine %s: %w", machineID, err)
+		}
+		if err := checkRestoreTarget(machine, regionCode); err != nil {
+			return err
+		}
+		regionCode = machine.Region
+
+		if name == "" {
+			current, err := client.GetVolume(ctx, machine.Config.Mounts[0].Volume)
+			if err != nil {
+				return fmt.Errorf("failed retrieving volume: %w", err)
+			}
+			name = current.Name
+		}
+	}
+
+	if name == "" {
+		return fmt.Errorf("the name of the volume must be specified with --name")
+	}
+
+	if regionCode == "" {
+		region, err := prompt.Region(ctx, !app.Organization.PaidPlan, prompt.RegionParams{
+			Message: "Select the region of the volume:",
+		})
+		if err != nil {
+			return err
+		}
+		regionCode = region.Code
+	}
+
+	volume, err := client.CreateVolume(ctx, api.CreateVolumeInput{
+		AppID:      app.ID,
+		Name:       name,
+		Region:     regionCode,
+		SizeGb:     flag.GetInt(ctx, "size"),
+		Encrypted:  !flag.GetBool(ctx, "no-encryption"),
+		SnapshotID: api.StringPointer(snapshotID),
+	})
+	if err != nil {
+		return fmt.Errorf("failed restoring snapshot %s: %w", snapshotID, err)
+	}
+
+	if !flag.GetDetach(ctx) {
+		if volume, err = waitForRestore(ctx, volume); err != nil {
+			return err
+		}
+	}
+
+	if machine != nil {
+		if err := attachVolume(ctx, machine, volume); err != nil {
+			return err
+		}
+		fmt.Fprintf(io.ErrOut, "Volume %s was attached to machine %s\n", volume.ID, machine.ID)
+	}
+
+	if cfg.JSONOutput {
+		return render.Output(ctx, io.Out, volume)
+	}
+
+	return printVolume(io.Out, volume)
+}
+
+// checkRestoreTarget checks that the restored volume can replace the volume of
+// the machine.
+func checkRestoreTarget(machine *api.Machine, region string) error {
+	switch {
+	case machine.State != api.MachineStateStopped:
+		return fmt.Errorf("machine %s must be stopped to attach a volume to it, it is %s", machine.ID, machine.State)
+	case machine.Config == nil || len(machine.Config.Mounts) == 0:
+		return fmt.Errorf("machine %s has no volume to replace", machine.ID)
+	case region != "" && region != machine.Region:
+		return fmt.Errorf("machine %s is in %s, the volume must be restored in the same region", machine.ID, machine.Region)
+	}
+	return nil
+}
+
+// waitForRestore reports the state of the volume until the snapshot is
+// restored.
+func waitForRestore(ctx context.Context, volume *api.Volume) (*api.Volume, error) {
+	var (
+		io     = iostreams.FromContext(ctx)
+		client = client.FromContext(ctx).API()
+	)
+
+	s := spinner.Run(io, fmt.Sprintf("Restoring volume %s", volume.ID))
+	defer s.Stop()
+
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		current, err := client.GetVolume(ctx, volume.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed retrieving volume: %w", err)
+		}
+
+		switch current.State {
+		case "created":
+			s.StopWithMessage(fmt.Sprintf("Restored volume %s", volume.ID))
+			return current, nil
+		case "failed":
+			return nil, fmt.Errorf("failed restoring volume %s", volume.ID)
+		case "":
+		default:
+			s.Set(fmt.Sprintf("Restoring volume %s (%s)", volume.ID, current.State))
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// attachVolume mounts the volume in place of the volume of the stopped
+// machine, without starting it.
+func attachVolume(ctx context.Context, machine *api.Machine, volume *api.Volume) error {
+	config := machine.Config
+	config.Mounts = []api.MachineMount{{
+		Volume: volume.ID,
+		Path:   config.Mounts[0].Path,
+	}}
+
+	_, err := flaps.FromContext(ctx).Update(ctx, api.LaunchMachineInput{
+		ID:         machine.ID,
+		Region:     machine.Region,
+		Config:     config,
+		SkipLaunch: true,
+	}, "")
+	if err != nil {
+		return fmt.Errorf("failed attaching volume %s to machine %s: %w", volume.ID, machine.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package snapshots
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newCreate() *cobra.Command {
+	const (
+		long = `Take an on-demand snapshot of the specified volume. The snapshot is
+taken in the background and is listed by the list command once done. It is kept
+as long as the daily snapshots of the volume.`
+		short = "Snapshot a volume"
+
+		usage = "create <volume-id>"
+	)
+
+	cmd := command.New(usage, short, long, runCreate,
+		command.RequireSession,
+	)
+
+	cmd.Args = cobra.ExactArgs(1)
+
+	return cmd
+}
+
+func runCreate(ctx context.Context) error {
+	var (
+		io     = iostreams.FromContext(ctx)
+		client = client.FromContext(ctx).API()
+	)
+
+	volID := flag.FirstArg(ctx)
+
+	if err := client.CreateVolumeSnapshot(ctx, volID); err != nil {
+		return fmt.Errorf("failed creating snapshot: %w", err)
+	}
+
+	fmt.Fprintf(io.Out, "Scheduled a snapshot of volume %s\n", volID)
+	return nil
+}
@@ -50,7 +50,7 @@ func runList(ctx context.Context) error {
 	}
 
 	if cfg.JSONOutput {
-		return render.JSON(io.Out, snapshots)
+		return render.Output(ctx, io.Out, snapshots)
 	}
 
 	if len(snapshots) == 0 {
@@ -58,6 +58,18 @@ func runList(ctx context.Context) error {
 		return nil
 	}
 
+	// the retention is only shown when the app can be reached through flaps
+	title := "Snapshots"
+	var retention int
+	if flapsClient, err := volumeFlaps(ctx, volID); err != nil {
+		fmt.Fprintf(io.ErrOut, "Could not retrieve the snapshot retention of volume %s: %v\n", volID, err)
+	} else if volume, err := flapsClient.GetVolume(ctx, volID); err != nil {
+		fmt.Fprintf(io.ErrOut, "Could not retrieve the snapshot retention of volume %s: %v\n", volID, err)
+	} else {
+		retention = volume.SnapshotRetention
+		title = fmt.Sprintf("Snapshots (kept for %d days)", retention)
+	}
+
 	// sort snapshots from newest to oldest
 	sort.Slice(snapshots, func(i, j int) bool {
 		return snapshots[i].CreatedAt.After(snapshots[j].CreatedAt)
@@ -65,12 +77,17 @@ func runList(ctx context.Context) error {
 
 	rows := make([][]string, 0, len(snapshots))
 	for _, snapshot := range snapshots {
+		expires := ""
+		if retention > 0 {
+			expires = humanize.Time(snapshot.CreatedAt.AddDate(0, 0, retention))
+		}
 		rows = append(rows, []string{
 			snapshot.ID,
 			snapshot.Size,
 			humanize.Time(snapshot.CreatedAt),
+			expires,
 		})
 	}
 
-	return render.Table(io.Out, "Snapshots", rows, "ID", "Size", "Created At")
+	return render.Table(io.Out, title, rows, "ID", "Size", "Created At", "Expires")
 }
@@ -0,0 +1,70 @@
+package snapshots
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/command"
+)
+
+// maxRetentionDays is the longest the platform keeps snapshots for.
+const maxRetentionDays = 60
+
+func newPolicy() *cobra.Command {
+	const (
+		long = `Commands for managing the retention policy of volume snapshots. Volumes
+are snapshotted daily, and their snapshots are kept for the number of days of
+the policy.`
+		short = "Manage the retention policy of volume snapshots"
+
+		usage = "policy"
+	)
+
+	cmd := command.New(usage, short, long, nil)
+
+	cmd.AddCommand(
+		newPolicySet(),
+		newPolicyShow(),
+	)
+
+	return cmd
+}
+
+// volumeFlaps returns a flaps client for the app of the volume.
+func volumeFlaps(ctx context.Context, volID string) (*flaps.Client, error) {
+	client := client.FromContext(ctx).API()
+
+	volume, err := client.GetVolume(ctx, volID)
+	if err != nil {
+		return nil, fmt.Errorf("failed retrieving volume: %w", err)
+	}
+
+	return flaps.NewFromAppName(ctx, volume.App.Name)
+}
+
+// retentionDays is the number of days daily snapshots are kept for, to keep
+// the given number of daily and weekly snapshots. Snapshots are taken daily,
+// so weekly ones are kept by retaining every snapshot of their weeks.
+func retentionDays(keepDaily, keepWeekly int) (int, error) {
+	if keepDaily < 0 || keepWeekly < 0 {
+		return 0, fmt.Errorf("the number of snapshots to keep can't be negative")
+	}
+
+	days := keepDaily
+	if weekly := keepWeekly * 7; weekly > days {
+		days = weekly
+	}
+
+	switch {
+	case days < 1:
+		return 0, fmt.Errorf("at least one daily or weekly snapshot must be kept")
+	case days > maxRetentionDays:
+		return 0, fmt.Errorf("snapshots can be kept for at most %d days, %d requested", maxRetentionDays, days)
+	}
+
+	return days, nil
+}
@@ -0,0 +1,69 @@
+package snapshots
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newPolicySet() *cobra.Command {
+	const (
+		long = `Set how many daily and weekly snapshots of the specified volume are kept.
+Snapshots are taken daily, so keeping weekly snapshots keeps every snapshot of
+the weeks they cover, for up to 60 days.`
+		short = "Set the retention policy of volume snapshots"
+
+		usage = "set <volume-id>"
+	)
+
+	cmd := command.New(usage, short, long, runPolicySet,
+		command.RequireSession,
+	)
+
+	cmd.Args = cobra.ExactArgs(1)
+
+	flag.Add(cmd,
+		flag.Int{
+			Name:        "keep-daily",
+			Description: "The number of daily snapshots to keep",
+		},
+		flag.Int{
+			Name:        "keep-weekly",
+			Description: "The number of weekly snapshots to keep",
+		},
+	)
+
+	return cmd
+}
+
+func runPolicySet(ctx context.Context) error {
+	io := iostreams.FromContext(ctx)
+
+	volID := flag.FirstArg(ctx)
+
+	days, err := retentionDays(flag.GetInt(ctx, "keep-daily"), flag.GetInt(ctx, "keep-weekly"))
+	if err != nil {
+		return err
+	}
+
+	flapsClient, err := volumeFlaps(ctx, volID)
+	if err != nil {
+		return err
+	}
+
+	volume, err := flapsClient.UpdateVolume(ctx, volID, api.UpdateVolumeRequest{
+		SnapshotRetention: api.Pointer(days),
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(io.Out, "Snapshots of volume %s are now kept for %d days\n", volume.ID, volume.SnapshotRetention)
+	return nil
+}
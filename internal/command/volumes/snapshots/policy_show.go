@@ -0,0 +1,64 @@
+package snapshots
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newPolicyShow() *cobra.Command {
+	const (
+		long  = "Show the retention policy of the snapshots of the specified volume"
+		short = "Show the retention policy of volume snapshots"
+
+		usage = "show <volume-id>"
+	)
+
+	cmd := command.New(usage, short, long, runPolicyShow,
+		command.RequireSession,
+	)
+
+	cmd.Args = cobra.ExactArgs(1)
+
+	flag.Add(cmd, flag.JSONOutput())
+	return cmd
+}
+
+func runPolicyShow(ctx context.Context) error {
+	var (
+		io  = iostreams.FromContext(ctx)
+		cfg = config.FromContext(ctx)
+	)
+
+	volID := flag.FirstArg(ctx)
+
+	flapsClient, err := volumeFlaps(ctx, volID)
+	if err != nil {
+		return err
+	}
+
+	volume, err := flapsClient.GetVolume(ctx, volID)
+	if err != nil {
+		return err
+	}
+
+	if cfg.JSONOutput {
+		return render.Output(ctx, io.Out, map[string]int{"snapshot_retention": volume.SnapshotRetention})
+	}
+
+	rows := [][]string{{
+		volume.ID,
+		volume.Name,
+		strconv.Itoa(volume.SnapshotRetention),
+		strconv.Itoa(volume.SnapshotRetention / 7),
+	}}
+
+	return render.VerticalTable(io.Out, "Snapshot Policy", rows, "Volume", "Name", "Retention Days", "Weekly Snapshots Kept")
+}
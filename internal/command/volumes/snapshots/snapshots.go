@@ -22,6 +22,8 @@ func New() *cobra.Command {
 
 	snapshots.AddCommand(
 		newList(),
+		newCreate(),
+		newPolicy(),
 	)
 
 	return snapshots
@@ -5,6 +5,8 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -36,6 +38,7 @@ func New() *cobra.Command {
 		newExtend(),
 		newShow(),
 		newFork(),
+		newRestore(),
 		snapshots.New(),
 	)
 
@@ -80,3 +83,23 @@ func countVolumesMatchingName(ctx context.Context, appName string, volumeName st
 
 	return matches, nil
 }
+
+// parseDF parses the size and used space in bytes of the FS from the output of
+// df -P -B1.
+func parseDF(out string) (size, used int64, err error) {
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) < 2 {
+		return 0, 0, fmt.Errorf("unexpected df output: %q", out)
+	}
+	fields := strings.Fields(lines[len(lines)-1])
+	if len(fields) < 3 {
+		return 0, 0, fmt.Errorf("unexpected df output: %q", out)
+	}
+	if size, err = strconv.ParseInt(fields[1], 10, 64); err != nil {
+		return 0, 0, fmt.Errorf("unexpected df output: %q", out)
+	}
+	if used, err = strconv.ParseInt(fields[2], 10, 64); err != nil {
+		return 0, 0, fmt.Errorf("unexpected df output: %q", out)
+	}
+	return size, used, nil
+}
@@ -0,0 +1,95 @@
+package completion
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/superfly/flyctl/internal/filemu"
+)
+
+// cacheFileName denotes the name of the file completion values are cached in,
+// in the config directory.
+const cacheFileName = "completions.yml"
+
+// maxCacheAge is how long values are kept in the cache file at most.
+const maxCacheAge = 24 * time.Hour
+
+type cacheEntry struct {
+	Values    []string  `yaml:"values"`
+	FetchedAt time.Time `yaml:"fetched_at"`
+}
+
+// valueCache maps keys, e.g. apps or machines:my-app, to the completion values
+// last fetched for them.
+type valueCache map[string]cacheEntry
+
+// get returns the values of the key when they were fetched less than ttl ago.
+func (c valueCache) get(key string, ttl time.Duration, now time.Time) ([]string, bool) {
+	e, ok := c[key]
+	if !ok || now.Sub(e.FetchedAt) > ttl {
+		return nil, false
+	}
+	return e.Values, true
+}
+
+func (c valueCache) set(key string, values []string, now time.Time) {
+	c[key] = cacheEntry{Values: values, FetchedAt: now}
+}
+
+// prune removes the entries older than maxCacheAge, so that the values of apps
+// no longer completed don't pile up.
+func (c valueCache) prune(now time.Time) {
+	for key, e := range c {
+		if now.Sub(e.FetchedAt) > maxCacheAge {
+			delete(c, key)
+		}
+	}
+}
+
+// loadCache loads the cache file at path. Missing or unreadable files result
+// in an empty cache since completions work without it.
+func loadCache(path string) valueCache {
+	c := valueCache{}
+
+	unlock, err := filemu.RLock(context.Background(), path+".lock")
+	if err != nil {
+		return c
+	}
+	defer unlock() // skipcq: GO-S2307
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return c
+	}
+	if err := yaml.Unmarshal(data, &c); err != nil {
+		return valueCache{}
+	}
+
+	return c
+}
+
+// save writes the cache to the file at path.
+func (c valueCache) save(path string, now time.Time) (err error) {
+	c.prune(now)
+
+	var b bytes.Buffer
+	if err = yaml.NewEncoder(&b).Encode(c); err != nil {
+		return
+	}
+
+	var unlock filemu.UnlockFunc
+	if unlock, err = filemu.Lock(context.Background(), path+".lock"); err != nil {
+		return
+	}
+	defer func() {
+		if e := unlock(); err == nil {
+			err = e
+		}
+	}()
+
+	return os.WriteFile(path, b.Bytes(), 0o600)
+}
@@ -0,0 +1,174 @@
+// Package completion implements completing the values of flags naming
+// resources, such as apps, machines and regions, from the API.
+package completion
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/appconfig"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/state"
+)
+
+// completeFunc completes the value of a flag.
+type completeFunc func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+const (
+	appsTTL     = 5 * time.Minute
+	machinesTTL = time.Minute
+	regionsTTL  = 24 * time.Hour
+)
+
+// flagFuncs maps the names of the flags completed from the API to the
+// functions completing them.
+var flagFuncs = map[string]completeFunc{
+	flag.AppName:    completeApps,
+	"machine":       completeMachines,
+	flag.RegionName: completeRegions,
+}
+
+// Register registers the completion functions of the flags naming resources of
+// cmd and its subcommands.
+func Register(cmd *cobra.Command) {
+	for name, fn := range flagFuncs {
+		if cmd.Flags().Lookup(name) != nil {
+			// commands sharing flags get them registered once
+			_ = cmd.RegisterFlagCompletionFunc(name, fn)
+		}
+	}
+
+	for _, sub := range cmd.Commands() {
+		Register(sub)
+	}
+}
+
+func completeApps(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	ctx, err := command.PrepareCompletion(cmd)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	values, err := cached(ctx, "apps", appsTTL, func() ([]string, error) {
+		apps, err := client.FromContext(ctx).API().GetApps(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		values := make([]string, 0, len(apps))
+		for _, app := range apps {
+			values = append(values, app.Name+"\t"+app.Organization.Slug)
+		}
+		return values, nil
+	})
+
+	return result(values, toComplete, err)
+}
+
+func completeMachines(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	ctx, err := command.PrepareCompletion(cmd, command.LoadAppNameIfPresent)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	appName := appconfig.NameFromContext(ctx)
+	if appName == "" {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	values, err := cached(ctx, "machines:"+appName, machinesTTL, func() ([]string, error) {
+		flapsClient, err := flaps.NewFromAppName(ctx, appName)
+		if err != nil {
+			return nil, err
+		}
+
+		machines, err := flapsClient.ListActive(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		values := make([]string, 0, len(machines))
+		for _, m := range machines {
+			values = append(values, fmt.Sprintf("%s\t%s %s %s", m.ID, m.Name, m.Region, m.State))
+		}
+		return values, nil
+	})
+
+	return result(values, toComplete, err)
+}
+
+func completeRegions(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	ctx, err := command.PrepareCompletion(cmd)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	values, err := cached(ctx, "regions", regionsTTL, func() ([]string, error) {
+		regions, _, err := client.FromContext(ctx).API().PlatformRegions(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		values := make([]string, 0, len(regions))
+		for _, r := range regions {
+			values = append(values, r.Code+"\t"+r.Name)
+		}
+		return values, nil
+	})
+
+	return result(values, toComplete, err)
+}
+
+// cached returns the values of the key from the cache file of the config
+// directory when they were fetched less than ttl ago, and fetches them
+// otherwise.
+func cached(ctx context.Context, key string, ttl time.Duration, fetch func() ([]string, error)) ([]string, error) {
+	path := filepath.Join(state.ConfigDirectory(ctx), cacheFileName)
+	now := time.Now()
+
+	c := loadCache(path)
+	if values, ok := c.get(key, ttl, now); ok {
+		return values, nil
+	}
+
+	values, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	c.set(key, values, now)
+	// completing still works when the cache can't be written
+	_ = c.save(path, now)
+
+	return values, nil
+}
+
+func result(values []string, toComplete string, err error) ([]string, cobra.ShellCompDirective) {
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return filter(values, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// filter returns the sorted values starting with prefix, ignoring their
+// descriptions, which follow a tab.
+func filter(values []string, prefix string) []string {
+	var matches []string
+	for _, v := range values {
+		name, _, _ := strings.Cut(v, "\t")
+		if strings.HasPrefix(name, prefix) {
+			matches = append(matches, v)
+		}
+	}
+	sort.Strings(matches)
+	return matches
+}
@@ -0,0 +1,60 @@
+package completion
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/superfly/flyctl/internal/flag"
+)
+
+func TestCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), cacheFileName)
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	c := loadCache(path)
+	_, ok := c.get("apps", appsTTL, now)
+	assert.False(t, ok)
+
+	c.set("apps", []string{"web\tpersonal"}, now)
+	c.set("machines:old", []string{"1234"}, now.Add(-2*maxCacheAge))
+	require.NoError(t, c.save(path, now))
+
+	c = loadCache(path)
+	values, ok := c.get("apps", appsTTL, now.Add(time.Minute))
+	assert.True(t, ok)
+	assert.Equal(t, []string{"web\tpersonal"}, values)
+
+	_, ok = c.get("apps", appsTTL, now.Add(appsTTL+time.Second))
+	assert.False(t, ok)
+
+	_, ok = c["machines:old"]
+	assert.False(t, ok, "stale entries are pruned")
+}
+
+func TestFilter(t *testing.T) {
+	values := []string{"web-staging\tpersonal", "api\tacme", "web\tpersonal"}
+
+	assert.Equal(t, []string{"web\tpersonal", "web-staging\tpersonal"}, filter(values, "web"))
+	assert.Equal(t, []string{"api\tacme", "web\tpersonal", "web-staging\tpersonal"}, filter(values, ""))
+	assert.Empty(t, filter(values, "acme"), "descriptions aren't matched")
+}
+
+func TestRegister(t *testing.T) {
+	root := &cobra.Command{Use: "root"}
+	sub := &cobra.Command{Use: "sub"}
+	root.AddCommand(sub)
+	flag.Add(sub, flag.App(), flag.Region(), flag.String{Name: "machine"}, flag.Org())
+
+	Register(root)
+
+	for _, name := range []string{flag.AppName, flag.RegionName, "machine"} {
+		err := sub.RegisterFlagCompletionFunc(name, completeApps)
+		assert.ErrorContains(t, err, "already registered", name)
+	}
+	assert.NoError(t, sub.RegisterFlagCompletionFunc(flag.OrgName, completeApps))
+}
@@ -23,6 +23,7 @@ const (
 	MetricsTokenEnvKey    = envKeyPrefix + "METRICS_TOKEN"
 	MetricsTokenFileKey   = "metrics_token"
 	WireGuardStateFileKey = "wire_guard_state"
+	DefaultOrgFileKey     = "default_org"
 	APITokenEnvKey        = envKeyPrefix + "API_TOKEN"
 	orgEnvKey             = envKeyPrefix + "ORG"
 	registryHostEnvKey    = envKeyPrefix + "REGISTRY_HOST"
@@ -32,6 +33,7 @@ const (
 	jsonOutputEnvKey      = envKeyPrefix + "JSON"
 	logGQLEnvKey          = envKeyPrefix + "LOG_GQL_ERRORS"
 	localOnlyEnvKey       = envKeyPrefix + "LOCAL_ONLY"
+	answersFileEnvKey     = envKeyPrefix + "ANSWERS_FILE"
 
 	defaultAPIBaseURL     = "https://api.fly.io"
 	defaultFlapsBaseURL   = "https://api.machines.dev"
@@ -63,10 +65,19 @@ type Config struct {
 	// JSONOutput denotes whether the user wants the output to be JSON.
 	JSONOutput bool
 
+	// WideOutput denotes whether the user wants tables to include their
+	// additional columns.
+	WideOutput bool
+
+	// DryRun denotes whether the user wants commands to describe the changes
+	// they'd make instead of making them.
+	DryRun bool
+
 	// LogGQLErrors denotes whether the user wants the log GraphQL errors.
 	LogGQLErrors bool
 
-	// Organization denotes the organizational slug the user has selected.
+	// Organization denotes the organizational slug the user has selected, or
+	// the default organization of the profile.
 	Organization string
 
 	// Region denotes the region slug the user has selected.
@@ -80,6 +91,10 @@ type Config struct {
 
 	// MetricsToken denotes the user's metrics token.
 	MetricsToken string
+
+	// AnswersFile denotes the path to a JSON file of answers to interactive
+	// prompts. "-" denotes stdin.
+	AnswersFile string
 }
 
 // New returns a new instance of Config populated with default values.
@@ -114,6 +129,7 @@ func (cfg *Config) ApplyEnv() {
 	cfg.Organization = env.FirstOrDefault(cfg.Organization,
 		orgEnvKey, organizationEnvKey)
 	cfg.Region = env.FirstOrDefault(cfg.Region, regionEnvKey)
+	cfg.AnswersFile = env.FirstOrDefault(cfg.AnswersFile, answersFileEnvKey)
 	cfg.RegistryHost = env.FirstOrDefault(cfg.RegistryHost, registryHostEnvKey)
 	cfg.APIBaseURL = env.FirstOrDefault(cfg.APIBaseURL, apiBaseURLEnvKey)
 	cfg.FlapsBaseURL = env.FirstOrDefault(cfg.FlapsBaseURL, flapsBaseURLEnvKey)
@@ -129,11 +145,13 @@ func (cfg *Config) ApplyFile(path string) (err error) {
 	var w struct {
 		AccessToken  string `yaml:"access_token"`
 		MetricsToken string `yaml:"metrics_token"`
+		DefaultOrg   string `yaml:"default_org"`
 	}
 
 	if err = unmarshal(path, &w); err == nil {
 		cfg.AccessToken = w.AccessToken
 		cfg.MetricsToken = w.MetricsToken
+		cfg.Organization = w.DefaultOrg
 	}
 
 	return
@@ -149,12 +167,14 @@ func (cfg *Config) ApplyFlags(fs *pflag.FlagSet) {
 		flag.AccessTokenName: &cfg.AccessToken,
 		flag.OrgName:         &cfg.Organization,
 		flag.RegionName:      &cfg.Region,
+		flag.AnswersFileName: &cfg.AnswersFile,
 	})
 
 	applyBoolFlags(fs, map[string]*bool{
 		flag.VerboseName:    &cfg.VerboseOutput,
 		flag.JSONOutputName: &cfg.JSONOutput,
 		flag.LocalOnlyName:  &cfg.LocalOnly,
+		flag.DryRunName:     &cfg.DryRun,
 	})
 }
 
@@ -28,6 +28,14 @@ func SetMetricsToken(path, token string) error {
 	})
 }
 
+// SetDefaultOrg sets the organization commands use when none is selected at
+// the configuration file found at path.
+func SetDefaultOrg(path, slug string) error {
+	return set(path, map[string]interface{}{
+		DefaultOrgFileKey: slug,
+	})
+}
+
 // Clear clears the access token, metrics token, and wireguard-related keys of the configuration
 // file found at path.
 func Clear(path string) (err error) {
@@ -18,6 +18,12 @@ const (
 	// JSONOutputName denotes the name of the json output flag.
 	JSONOutputName = "json"
 
+	// OutputName denotes the name of the global output format flag.
+	OutputName = "output"
+
+	// DryRunName denotes the name of the global dry-run flag.
+	DryRunName = "dry-run"
+
 	// LocalOnlyName denotes the name of the local-only flag.
 	LocalOnlyName = "local-only"
 
@@ -50,6 +56,9 @@ const (
 
 	// DetachName denotes the name of the detach flag.
 	DetachName = "detach"
+
+	// AnswersFileName denotes the name of the answers file flag.
+	AnswersFileName = "answers-file"
 )
 
 // Flag wraps the set of flags.
@@ -11,6 +11,26 @@ import (
 // ErrAbort is an error for when the CLI aborts
 var ErrAbort = errors.New("abort")
 
+// ExitCodeError is returned by commands which need the CLI to exit with a
+// specific code, like the one of a command they ran remotely. The CLI exits
+// with Code without printing the error.
+type ExitCodeError struct {
+	Code int
+}
+
+func (e *ExitCodeError) Error() string {
+	return fmt.Sprintf("exited with code %d", e.Code)
+}
+
+// GetExitCode returns the exit code carried by err, if any.
+func GetExitCode(err error) (int, bool) {
+	var eerr *ExitCodeError
+	if errors.As(err, &eerr) {
+		return eerr.Code, true
+	}
+	return 0, false
+}
+
 // ErrorDescription is an error with a detailed description that will be printed before the CLI exits
 type ErrorDescription interface {
 	error
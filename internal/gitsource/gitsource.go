@@ -0,0 +1,95 @@
+// Package gitsource implements fetching the source of apps from Git
+// repositories.
+package gitsource
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+)
+
+// DirName returns the name of the directory git clone would clone the
+// repository into, e.g. rails for https://github.com/rails/rails.git.
+func DirName(repo string) (string, error) {
+	p := strings.TrimSuffix(strings.TrimRight(repo, "/"), ".git")
+
+	switch u, err := url.Parse(p); {
+	case err == nil && u.Scheme != "" && u.Host != "":
+		p = u.Path
+	case strings.Contains(p, ":"):
+		// scp-like syntax, e.g. git@github.com:rails/rails
+		p = p[strings.LastIndex(p, ":")+1:]
+	}
+
+	name := path.Base(p)
+	if name == "" || name == "." || name == "/" {
+		return "", fmt.Errorf("can't determine a directory name for repository %s", repo)
+	}
+
+	return name, nil
+}
+
+// Clone fetches the ref of the repository into dir, which must not exist or
+// be empty, without its history. The default branch is fetched when ref is
+// empty. Refs may be branches, tags or, for hosts that allow it, commits.
+func Clone(ctx context.Context, repo, ref, dir string) error {
+	if _, err := exec.LookPath("git"); err != nil {
+		return errors.New("git must be installed to deploy from a Git repository")
+	}
+
+	switch entries, err := os.ReadDir(dir); {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return fmt.Errorf("failed reading %s: %w", dir, err)
+	case len(entries) > 0:
+		return fmt.Errorf("can't clone into %s, it isn't empty", dir)
+	}
+
+	if ref == "" {
+		return git(ctx, "", "clone", "--depth", "1", "--recurse-submodules", "--shallow-submodules", repo, dir)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed creating %s: %w", dir, err)
+	}
+
+	steps := [][]string{
+		{"init", "--quiet"},
+		{"remote", "add", "origin", repo},
+		{"fetch", "--depth", "1", "origin", ref},
+		{"checkout", "--quiet", "FETCH_HEAD"},
+		{"submodule", "update", "--init", "--recursive", "--depth", "1"},
+	}
+	for _, args := range steps {
+		if err := git(ctx, dir, args...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func git(ctx context.Context, dir string, args ...string) error {
+	var stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, "git", args...) // #nosec G204
+	cmd.Dir = dir
+	cmd.Stderr = &stderr
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("git %s failed: %s", args[0], msg)
+		}
+		return fmt.Errorf("git %s failed: %w", args[0], err)
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package gitsource
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDirName(t *testing.T) {
+	cases := map[string]string{
+		"https://github.com/rails/rails.git":  "rails",
+		"https://github.com/rails/rails/":     "rails",
+		"git@github.com:superfly/flyctl.git":  "flyctl",
+		"ssh://git@example.com:2222/team/api": "api",
+		"/srv/git/project.git":                "project",
+	}
+	for repo, want := range cases {
+		got, err := DirName(repo)
+		assert.NoError(t, err, repo)
+		assert.Equal(t, want, got, repo)
+	}
+
+	_, err := DirName("https://example.com/")
+	assert.Error(t, err)
+}
+
+func TestClone(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git isn't installed")
+	}
+
+	ctx := context.Background()
+	repo := t.TempDir()
+
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+		)
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	run("init", "--quiet")
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "app.txt"), []byte("v1"), 0o644))
+	run("add", ".")
+	run("commit", "--quiet", "-m", "v1")
+	run("tag", "v1")
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "app.txt"), []byte("v2"), 0o644))
+	run("commit", "--quiet", "-am", "v2")
+
+	latest := filepath.Join(t.TempDir(), "latest")
+	require.NoError(t, Clone(ctx, "file://"+repo, "", latest))
+	data, err := os.ReadFile(filepath.Join(latest, "app.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "v2", string(data))
+
+	tagged := filepath.Join(t.TempDir(), "tagged")
+	require.NoError(t, Clone(ctx, "file://"+repo, "v1", tagged))
+	data, err = os.ReadFile(filepath.Join(tagged, "app.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "v1", string(data))
+
+	assert.ErrorContains(t, Clone(ctx, "file://"+repo, "", tagged), "isn't empty")
+}
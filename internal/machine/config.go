@@ -2,12 +2,8 @@ package machine
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"regexp"
-	"strings"
 
-	"github.com/google/go-cmp/cmp"
 	"github.com/superfly/flyctl/api"
 	"github.com/superfly/flyctl/helpers"
 	"github.com/superfly/flyctl/internal/prompt"
@@ -26,8 +22,8 @@ func ConfirmConfigChanges(ctx context.Context, machine *api.Machine, targetConfi
 		colorize = io.ColorScheme()
 	)
 
-	diff := configCompare(ctx, *machine.Config, targetConfig)
-	if diff == "" {
+	changes := DiffConfigs(machine.Config, &targetConfig)
+	if len(changes) == 0 {
 		return false, &ErrNoConfigChangesFound{}
 	}
 
@@ -37,7 +33,9 @@ func ConfirmConfigChanges(ctx context.Context, machine *api.Machine, targetConfi
 		fmt.Fprintf(io.Out, "Configuration changes to be applied to machine: %s (%s)\n", colorize.Bold(machine.ID), colorize.Bold(machine.Name))
 	}
 
-	fmt.Fprintf(io.Out, "\n%s\n", diff)
+	fmt.Fprintln(io.Out)
+	renderChanges(io.Out, colorize, changes)
+	fmt.Fprintln(io.Out)
 
 	const msg = "Apply changes?"
 	switch confirmed, err := prompt.Confirmf(ctx, msg); {
@@ -62,54 +60,3 @@ func CloneConfig(orig *api.MachineConfig) *api.MachineConfig {
 	}
 	return helpers.Clone(orig)
 }
-
-var cmpOptions = cmp.Options{
-	cmp.FilterValues(
-		func(x, y []byte) bool { return json.Valid(x) && json.Valid(y) },
-		cmp.Transformer("parseJSON",
-			func(in []byte) (out string) {
-				return string(in)
-			})),
-}
-
-func configCompare(ctx context.Context, original api.MachineConfig, new api.MachineConfig) string {
-	io := iostreams.FromContext(ctx)
-	colorize := io.ColorScheme()
-
-	origBytes, _ := json.MarshalIndent(original, "", "  ")
-	newBytes, _ := json.MarshalIndent(new, "", "  ")
-
-	if cmp.Equal(origBytes, newBytes, cmpOptions) {
-		return ""
-	}
-
-	diff := cmp.Diff(origBytes, newBytes, cmpOptions)
-	diffSlice := strings.Split(diff, "\n")
-
-	var str string
-	additionReg := regexp.MustCompile(`^\+.*`)
-	deletionReg := regexp.MustCompile(`^\-.*`)
-
-	// Highlight additions/deletions
-	for _, val := range diffSlice {
-		vB := []byte(val)
-
-		if additionReg.Match(vB) {
-			str += colorize.Green(val) + "\n"
-		} else if deletionReg.Match(vB) {
-			str += colorize.Red(val) + "\n"
-		} else {
-			str += val + "\n"
-		}
-	}
-
-	// Cleanup output
-	delim := "\"\"\""
-	rx := regexp.MustCompile(`(?s)` + regexp.QuoteMeta(delim) + `(.*?)` + regexp.QuoteMeta(delim))
-	match := rx.FindStringSubmatch(str)
-	if len(match) > 0 {
-		return strings.Trim(match[1], "\n")
-	}
-	// We know the objects are different, if we can't cleanup return the best we have got
-	return str
-}
@@ -0,0 +1,216 @@
+package machine
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/samber/lo"
+	"golang.org/x/exp/slices"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+// ConfigChange is a change to a single field of a machine config. An empty
+// From or To denotes the field being added or removed.
+type ConfigChange struct {
+	Field string
+	From  string
+	To    string
+}
+
+// DiffConfigs returns the changes between two machine configs. The fields
+// operators care the most about (image, env, metadata, guest, services and
+// mounts) are summarized, any other is compared as JSON.
+func DiffConfigs(from, to *api.MachineConfig) (changes []ConfigChange) {
+	if from == nil {
+		from = &api.MachineConfig{}
+	}
+	if to == nil {
+		to = &api.MachineConfig{}
+	}
+
+	add := func(field, from, to string) {
+		if from != to {
+			changes = append(changes, ConfigChange{Field: field, From: from, To: to})
+		}
+	}
+
+	add("image", from.Image, to.Image)
+
+	for _, k := range sortedKeys(from.Env, to.Env) {
+		add("env."+k, from.Env[k], to.Env[k])
+	}
+
+	for _, k := range sortedKeys(from.Metadata, to.Metadata) {
+		add("metadata."+k, from.Metadata[k], to.Metadata[k])
+	}
+
+	fromGuest, toGuest := guestSummary(from.Guest), guestSummary(to.Guest)
+	if fromGuest == toGuest && !jsonEqual(from.Guest, to.Guest) {
+		toGuest += " (settings changed)"
+	}
+	add("guest", fromGuest, toGuest)
+
+	fromServices, toServices := servicesByKey(from.Services), servicesByKey(to.Services)
+	for _, k := range sortedKeys(fromServices, toServices) {
+		f, inFrom := fromServices[k]
+		t, inTo := toServices[k]
+
+		switch {
+		case !inFrom:
+			add("services."+k, "", serviceSummary(t))
+		case !inTo:
+			add("services."+k, serviceSummary(f), "")
+		case !jsonEqual(f, t):
+			fs, ts := serviceSummary(f), serviceSummary(t)
+			if fs == ts {
+				// the summary only covers ports, make sure changes to other
+				// settings still show
+				ts += " (settings changed)"
+			}
+			add("services."+k, fs, ts)
+		}
+	}
+
+	fromMounts, toMounts := mountsByPath(from.Mounts), mountsByPath(to.Mounts)
+	for _, k := range sortedKeys(fromMounts, toMounts) {
+		f, inFrom := fromMounts[k]
+		t, inTo := toMounts[k]
+
+		switch {
+		case !inFrom:
+			add("mounts."+k, "", mountSummary(t))
+		case !inTo:
+			add("mounts."+k, mountSummary(f), "")
+		case !jsonEqual(f, t):
+			fs, ts := mountSummary(f), mountSummary(t)
+			if fs == ts {
+				ts += " (settings changed)"
+			}
+			add("mounts."+k, fs, ts)
+		}
+	}
+
+	fromFields, toFields := otherFields(from), otherFields(to)
+	for _, k := range sortedKeys(fromFields, toFields) {
+		add(k, fromFields[k], toFields[k])
+	}
+
+	return changes
+}
+
+// summarizedFields are the JSON names of the fields DiffConfigs summarizes.
+var summarizedFields = []string{"image", "env", "metadata", "guest", "services", "mounts"}
+
+// otherFields returns the JSON of the fields of the config DiffConfigs doesn't
+// summarize, keyed by their JSON name.
+func otherFields(c *api.MachineConfig) map[string]string {
+	var raw map[string]json.RawMessage
+	if data, err := json.Marshal(c); err == nil {
+		_ = json.Unmarshal(data, &raw)
+	}
+
+	fields := map[string]string{}
+	for k, v := range raw {
+		if !lo.Contains(summarizedFields, k) && string(v) != "null" {
+			fields[k] = string(v)
+		}
+	}
+
+	return fields
+}
+
+func sortedKeys[V any](maps ...map[string]V) []string {
+	var keys []string
+	for _, m := range maps {
+		keys = append(keys, lo.Keys(m)...)
+	}
+	keys = lo.Uniq(keys)
+	slices.Sort(keys)
+
+	return keys
+}
+
+func guestSummary(g *api.MachineGuest) string {
+	if g == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%s-cpu-%dx@%dMB", g.CPUKind, g.CPUs, g.MemoryMB)
+}
+
+func servicesByKey(services []api.MachineService) map[string]api.MachineService {
+	return lo.KeyBy(services, func(s api.MachineService) string {
+		return fmt.Sprintf("%s/%d", s.Protocol, s.InternalPort)
+	})
+}
+
+// serviceSummary describes the ports of a service along with their handlers,
+// e.g. "80[http] 443[tls,http]".
+func serviceSummary(s api.MachineService) string {
+	ports := lo.Map(s.Ports, func(p api.MachinePort, _ int) string {
+		var port string
+		switch {
+		case p.Port != nil:
+			port = fmt.Sprint(*p.Port)
+		case p.StartPort != nil && p.EndPort != nil:
+			port = fmt.Sprintf("%d-%d", *p.StartPort, *p.EndPort)
+		}
+		if len(p.Handlers) > 0 {
+			port += "[" + strings.Join(p.Handlers, ",") + "]"
+		}
+		return port
+	})
+
+	if len(ports) == 0 {
+		return "no public ports"
+	}
+
+	return strings.Join(ports, " ")
+}
+
+func jsonEqual(a, b any) bool {
+	ab, _ := json.Marshal(a)
+	bb, _ := json.Marshal(b)
+
+	return string(ab) == string(bb)
+}
+
+func mountsByPath(mounts []api.MachineMount) map[string]api.MachineMount {
+	return lo.KeyBy(mounts, func(m api.MachineMount) string { return m.Path })
+}
+
+// mountSummary names the volume of a mount, or the volume to create for it.
+func mountSummary(m api.MachineMount) string {
+	switch {
+	case m.Volume != "":
+		return m.Volume
+	case m.Name != "":
+		return "new volume " + m.Name
+	default:
+		return "new volume"
+	}
+}
+
+// RenderConfigChanges writes the changes to the config of a machine to w,
+// one field per line.
+func RenderConfigChanges(w io.Writer, colorize *iostreams.ColorScheme, machine *api.Machine, changes []ConfigChange) {
+	fmt.Fprintf(w, "Machine %s (%s) in %s:\n", colorize.Bold(machine.ID), machine.Name, machine.Region)
+	renderChanges(w, colorize, changes)
+}
+
+func renderChanges(w io.Writer, colorize *iostreams.ColorScheme, changes []ConfigChange) {
+	for _, c := range changes {
+		switch {
+		case c.From == "":
+			fmt.Fprintf(w, "  %s %s: %s\n", colorize.Green("+"), c.Field, colorize.Green(c.To))
+		case c.To == "":
+			fmt.Fprintf(w, "  %s %s: %s\n", colorize.Red("-"), c.Field, colorize.Red(c.From))
+		default:
+			fmt.Fprintf(w, "  %s %s: %s -> %s\n", colorize.Yellow("~"), c.Field, colorize.Red(c.From), colorize.Green(c.To))
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package machine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func TestDiffConfigs(t *testing.T) {
+	port := func(p int) *int { return &p }
+
+	from := &api.MachineConfig{
+		Image: "registry.fly.io/app:v1",
+		Env:   map[string]string{"A": "1", "B": "2"},
+		Guest: &api.MachineGuest{CPUKind: "shared", CPUs: 1, MemoryMB: 256},
+		Services: []api.MachineService{
+			{Protocol: "tcp", InternalPort: 8080, Ports: []api.MachinePort{{Port: port(80), Handlers: []string{"http"}}}},
+		},
+		Mounts: []api.MachineMount{{Path: "/data", Volume: "vol_1"}},
+	}
+
+	to := CloneConfig(from)
+	assert.Empty(t, DiffConfigs(from, to))
+
+	to.Image = "registry.fly.io/app:v2"
+	to.Env["B"] = "3"
+	delete(to.Env, "A")
+	to.Env["C"] = "4"
+	to.Guest.MemoryMB = 512
+	to.Services[0].Ports = append(to.Services[0].Ports, api.MachinePort{Port: port(443), Handlers: []string{"tls", "http"}})
+	to.Mounts = nil
+
+	assert.Equal(t, []ConfigChange{
+		{Field: "image", From: "registry.fly.io/app:v1", To: "registry.fly.io/app:v2"},
+		{Field: "env.A", From: "1"},
+		{Field: "env.B", From: "2", To: "3"},
+		{Field: "env.C", To: "4"},
+		{Field: "guest", From: "shared-cpu-1x@256MB", To: "shared-cpu-1x@512MB"},
+		{Field: "services.tcp/8080", From: "80[http]", To: "80[http] 443[tls,http]"},
+		{Field: "mounts./data", From: "vol_1"},
+	}, DiffConfigs(from, to))
+}
+
+func TestDiffConfigsServiceSettings(t *testing.T) {
+	from := &api.MachineConfig{
+		Services: []api.MachineService{{Protocol: "tcp", InternalPort: 8080}},
+	}
+
+	to := CloneConfig(from)
+	to.Services[0].Concurrency = &api.MachineServiceConcurrency{Type: "requests", HardLimit: 25}
+
+	assert.Equal(t, []ConfigChange{
+		{Field: "services.tcp/8080", From: "no public ports", To: "no public ports (settings changed)"},
+	}, DiffConfigs(from, to))
+}
+
+func TestDiffConfigsServices(t *testing.T) {
+	port := func(p int) *int { return &p }
+
+	from := &api.MachineConfig{
+		Services: []api.MachineService{
+			{Protocol: "tcp", InternalPort: 8080, Ports: []api.MachinePort{{Port: port(80), Handlers: []string{"http"}}}},
+			{Protocol: "udp", InternalPort: 5353},
+		},
+	}
+
+	to := CloneConfig(from)
+	to.Services = []api.MachineService{
+		{Protocol: "tcp", InternalPort: 8080, Ports: []api.MachinePort{{Port: port(443), Handlers: []string{"tls", "http"}}}},
+		{Protocol: "tcp", InternalPort: 9000, Ports: []api.MachinePort{{StartPort: port(9000), EndPort: port(9010)}}},
+	}
+
+	assert.Equal(t, []ConfigChange{
+		{Field: "services.tcp/8080", From: "80[http]", To: "443[tls,http]"},
+		{Field: "services.tcp/9000", To: "9000-9010"},
+		{Field: "services.udp/5353", From: "no public ports"},
+	}, DiffConfigs(from, to))
+}
+
+func TestDiffConfigsMounts(t *testing.T) {
+	from := &api.MachineConfig{
+		Mounts: []api.MachineMount{
+			{Path: "/data", Volume: "vol_1"},
+			{Path: "/logs", Volume: "vol_2", SizeGb: 1},
+			{Path: "/tmp", Volume: "vol_3"},
+		},
+	}
+
+	to := CloneConfig(from)
+	to.Mounts = []api.MachineMount{
+		{Path: "/data", Volume: "vol_4"},
+		{Path: "/logs", Volume: "vol_2", SizeGb: 2},
+		{Path: "/cache", Name: "cache"},
+	}
+
+	assert.Equal(t, []ConfigChange{
+		{Field: "mounts./cache", To: "new volume cache"},
+		{Field: "mounts./data", From: "vol_1", To: "vol_4"},
+		{Field: "mounts./logs", From: "vol_2", To: "vol_2 (settings changed)"},
+		{Field: "mounts./tmp", From: "vol_3"},
+	}, DiffConfigs(from, to))
+}
+
+func TestDiffConfigsGuest(t *testing.T) {
+	guest := &api.MachineGuest{CPUKind: "shared", CPUs: 1, MemoryMB: 256}
+
+	cases := []struct {
+		name     string
+		from, to *api.MachineGuest
+		expected []ConfigChange
+	}{
+		{
+			name:     "unchanged",
+			from:     guest,
+			to:       &api.MachineGuest{CPUKind: "shared", CPUs: 1, MemoryMB: 256},
+			expected: nil,
+		},
+		{
+			name:     "cpus",
+			from:     guest,
+			to:       &api.MachineGuest{CPUKind: "performance", CPUs: 2, MemoryMB: 4096},
+			expected: []ConfigChange{{Field: "guest", From: "shared-cpu-1x@256MB", To: "performance-cpu-2x@4096MB"}},
+		},
+		{
+			name:     "kernel args",
+			from:     guest,
+			to:       &api.MachineGuest{CPUKind: "shared", CPUs: 1, MemoryMB: 256, KernelArgs: []string{"quiet"}},
+			expected: []ConfigChange{{Field: "guest", From: "shared-cpu-1x@256MB", To: "shared-cpu-1x@256MB (settings changed)"}},
+		},
+		{
+			name:     "added",
+			to:       guest,
+			expected: []ConfigChange{{Field: "guest", To: "shared-cpu-1x@256MB"}},
+		},
+		{
+			name:     "removed",
+			from:     guest,
+			expected: []ConfigChange{{Field: "guest", From: "shared-cpu-1x@256MB"}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, DiffConfigs(&api.MachineConfig{Guest: tc.from}, &api.MachineConfig{Guest: tc.to}))
+		})
+	}
+}
+
+func TestDiffConfigsOtherFields(t *testing.T) {
+	from := &api.MachineConfig{
+		Metadata: map[string]string{"fly_process_group": "app", "fly_release_version": "1"},
+		Restart:  api.MachineRestart{Policy: api.MachineRestartPolicyAlways},
+		Schedule: "daily",
+	}
+
+	to := CloneConfig(from)
+	to.Metadata["fly_release_version"] = "2"
+	to.Restart = api.MachineRestart{Policy: api.MachineRestartPolicyNo}
+	to.Schedule = ""
+	to.AutoDestroy = true
+
+	assert.Equal(t, []ConfigChange{
+		{Field: "metadata.fly_release_version", From: "1", To: "2"},
+		{Field: "auto_destroy", To: "true"},
+		{Field: "restart", From: `{"policy":"always"}`, To: `{"policy":"no"}`},
+		{Field: "schedule", From: `"daily"`},
+	}, DiffConfigs(from, to))
+}
+
+func TestDiffConfigsNil(t *testing.T) {
+	assert.Empty(t, DiffConfigs(nil, nil))
+	assert.Empty(t, DiffConfigs(nil, &api.MachineConfig{}))
+	assert.Equal(t, []ConfigChange{
+		{Field: "image", To: "app:v1"},
+	}, DiffConfigs(nil, &api.MachineConfig{Image: "app:v1"}))
+}
+
+func TestConfirmConfigChanges(t *testing.T) {
+	ios, _, out, _ := iostreams.Test()
+	ctx := iostreams.NewContext(context.Background(), ios)
+
+	machine := &api.Machine{
+		ID:     "m1",
+		Name:   "web",
+		Config: &api.MachineConfig{Image: "app:v1", Env: map[string]string{"A": "1"}},
+	}
+
+	_, err := ConfirmConfigChanges(ctx, machine, *CloneConfig(machine.Config), "")
+	assert.ErrorIs(t, err, &ErrNoConfigChangesFound{})
+
+	target := CloneConfig(machine.Config)
+	target.Image = "app:v2"
+	delete(target.Env, "A")
+
+	ctx, err = prompt.WithAnswer(ctx, "Apply changes?", true)
+	require.NoError(t, err)
+
+	confirmed, err := ConfirmConfigChanges(ctx, machine, *target, "")
+	require.NoError(t, err)
+	assert.True(t, confirmed)
+	assert.Equal(t, `Configuration changes to be applied to machine: m1 (web)
+
+  ~ image: app:v1 -> app:v2
+  - env.A: 1
+
+`, out.String())
+}
+
+func TestRenderConfigChanges(t *testing.T) {
+	ios, _, out, _ := iostreams.Test()
+
+	RenderConfigChanges(out, ios.ColorScheme(), &api.Machine{ID: "m1", Name: "web", Region: "ord"}, []ConfigChange{
+		{Field: "image", From: "app:v1", To: "app:v2"},
+		{Field: "env.A", From: "1"},
+		{Field: "env.B", To: "2"},
+	})
+
+	assert.Equal(t, `Machine m1 (web) in ord:
+  ~ image: app:v1 -> app:v2
+  - env.A: 1
+  + env.B: 2
+`, out.String())
+}
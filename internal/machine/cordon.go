@@ -0,0 +1,59 @@
+package machine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/superfly/flyctl/api"
+)
+
+// Cordon takes the machine out of service: its services are moved into its
+// metadata so that it no longer receives traffic, and it's flagged so that
+// deployments leave it alone. The machine must hold a lease.
+func Cordon(ctx context.Context, m *api.Machine) error {
+	if m.IsCordoned() {
+		return nil
+	}
+
+	services, err := json.Marshal(m.Config.Services)
+	if err != nil {
+		return fmt.Errorf("could not save the services of machine %s: %w", m.ID, err)
+	}
+
+	config := CloneConfig(m.Config)
+	if config.Metadata == nil {
+		config.Metadata = map[string]string{}
+	}
+	config.Metadata[api.MachineConfigMetadataKeyFlyCordoned] = "true"
+	config.Metadata[api.MachineConfigMetadataKeyFlyCordonedServices] = string(services)
+	config.Services = nil
+
+	return Update(ctx, m, &api.LaunchMachineInput{
+		Region:           m.Region,
+		Config:           config,
+		SkipHealthChecks: true,
+	})
+}
+
+// Uncordon returns a machine taken out of service by Cordon to service,
+// restoring its services. The machine must hold a lease.
+func Uncordon(ctx context.Context, m *api.Machine) error {
+	if !m.IsCordoned() {
+		return nil
+	}
+
+	config := CloneConfig(m.Config)
+	if saved := config.Metadata[api.MachineConfigMetadataKeyFlyCordonedServices]; saved != "" {
+		if err := json.Unmarshal([]byte(saved), &config.Services); err != nil {
+			return fmt.Errorf("could not restore the services of machine %s: %w", m.ID, err)
+		}
+	}
+	delete(config.Metadata, api.MachineConfigMetadataKeyFlyCordoned)
+	delete(config.Metadata, api.MachineConfigMetadataKeyFlyCordonedServices)
+
+	return Update(ctx, m, &api.LaunchMachineInput{
+		Region: m.Region,
+		Config: config,
+	})
+}
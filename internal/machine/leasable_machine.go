@@ -30,6 +30,9 @@ type LeasableMachine interface {
 	WaitForHealthchecksToPass(context.Context, time.Duration, string) error
 	WaitForEventTypeAfterType(context.Context, string, string, time.Duration) (*api.MachineEvent, error)
 	FormattedMachineId() string
+	SetMetadata(context.Context, string, string) error
+	DeleteMetadata(context.Context, string) error
+	IsDestroyed() bool
 }
 
 type leasableMachine struct {
@@ -43,6 +46,10 @@ type leasableMachine struct {
 }
 
 func NewLeasableMachine(flapsClient *flaps.Client, io *iostreams.IOStreams, machine *api.Machine) LeasableMachine {
+	return newLeasableMachine(flapsClient, io, machine)
+}
+
+func newLeasableMachine(flapsClient *flaps.Client, io *iostreams.IOStreams, machine *api.Machine) *leasableMachine {
 	return &leasableMachine{
 		flapsClient: flapsClient,
 		io:          io,
@@ -83,6 +90,18 @@ func (lm *leasableMachine) Destroy(ctx context.Context, kill bool) error {
 	return nil
 }
 
+// SetMetadata sets a metadata key of the machine, under its lease if held,
+// without updating the machine.
+func (lm *leasableMachine) SetMetadata(ctx context.Context, key, value string) error {
+	return lm.flapsClient.SetMetadata(ctx, lm.machine.ID, key, value, lm.leaseNonce)
+}
+
+// DeleteMetadata removes a metadata key of the machine, under its lease if
+// held, without updating the machine.
+func (lm *leasableMachine) DeleteMetadata(ctx context.Context, key string) error {
+	return lm.flapsClient.DeleteMetadata(ctx, lm.machine.ID, key, lm.leaseNonce)
+}
+
 func (lm *leasableMachine) FormattedMachineId() string {
 	res := lm.Machine().ID
 	if lm.Machine().Config.Metadata == nil {
@@ -306,27 +325,36 @@ func (lm *leasableMachine) AcquireLease(ctx context.Context, duration time.Durat
 }
 
 func (lm *leasableMachine) RefreshLease(ctx context.Context, duration time.Duration) error {
+	return lm.refreshLease(ctx, lm.machine.ID, lm.leaseNonce, duration)
+}
+
+func (lm *leasableMachine) refreshLease(ctx context.Context, machineID, nonce string, duration time.Duration) error {
 	seconds := int(duration.Seconds())
-	refreshedLease, err := lm.flapsClient.RefreshLease(ctx, lm.machine.ID, &seconds, lm.leaseNonce)
+	refreshedLease, err := lm.flapsClient.RefreshLease(ctx, machineID, &seconds, nonce)
 	if err != nil {
 		return err
 	}
 	if refreshedLease.Status != "success" {
-		return fmt.Errorf("did not acquire lease for machine %s status: %s code: %s message: %s", lm.machine.ID, refreshedLease.Status, refreshedLease.Code, refreshedLease.Message)
+		return fmt.Errorf("did not acquire lease for machine %s status: %s code: %s message: %s", machineID, refreshedLease.Status, refreshedLease.Code, refreshedLease.Message)
 	} else if refreshedLease.Data == nil {
-		return fmt.Errorf("missing data from lease response for machine %s, assuming not successful", lm.machine.ID)
-	} else if refreshedLease.Data.Nonce != lm.leaseNonce {
-		return fmt.Errorf("unexpectedly received a new nonce when trying to refresh lease on machine %s", lm.machine.ID)
+		return fmt.Errorf("missing data from lease response for machine %s, assuming not successful", machineID)
+	} else if refreshedLease.Data.Nonce != nonce {
+		return fmt.Errorf("unexpectedly received a new nonce when trying to refresh lease on machine %s", machineID)
 	}
 	return nil
 }
 
 func (lm *leasableMachine) StartBackgroundLeaseRefresh(ctx context.Context, leaseDuration time.Duration, delayBetween time.Duration) {
+	// don't leave a previous refresh running
+	if lm.leaseRefreshCancelFunc != nil {
+		lm.leaseRefreshCancelFunc()
+	}
 	ctx, lm.leaseRefreshCancelFunc = context.WithCancel(ctx)
-	go lm.refreshLeaseUntilCanceled(ctx, leaseDuration, delayBetween)
+	// the machine and lease may change while refreshing, so refresh the ones at hand
+	go lm.refreshLeaseUntilCanceled(ctx, lm.machine.ID, lm.leaseNonce, leaseDuration, delayBetween)
 }
 
-func (lm *leasableMachine) refreshLeaseUntilCanceled(ctx context.Context, duration time.Duration, delayBetween time.Duration) {
+func (lm *leasableMachine) refreshLeaseUntilCanceled(ctx context.Context, machineID, nonce string, duration time.Duration, delayBetween time.Duration) {
 	var (
 		err error
 		b   = &backoff.Backoff{
@@ -336,14 +364,18 @@ func (lm *leasableMachine) refreshLeaseUntilCanceled(ctx context.Context, durati
 		}
 	)
 	for {
-		err = lm.RefreshLease(ctx, duration)
+		err = lm.refreshLease(ctx, machineID, nonce, duration)
 		switch {
 		case errors.Is(err, context.Canceled):
 			return
 		case err != nil:
-			terminal.Warnf("error refreshing lease for machine %s: %v\n", lm.machine.ID, err)
+			terminal.Warnf("error refreshing lease for machine %s: %v\n", machineID, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(b.Duration()):
 		}
-		time.Sleep(b.Duration())
 	}
 }
 
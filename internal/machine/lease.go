@@ -2,8 +2,11 @@ package machine
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"strings"
+	"time"
 
 	"github.com/superfly/flyctl/api"
 	"github.com/superfly/flyctl/flaps"
@@ -13,6 +16,57 @@ import (
 type releaseLeasesFunc func(ctx context.Context, machines []*api.Machine)
 type releaseLeaseFunc func(ctx context.Context, machine *api.Machine)
 
+// DefaultLeaseTtl is how long leases are held for between refreshes.
+const DefaultLeaseTtl = 13 * time.Second
+
+// LeaseDelayBetween returns how long to wait between refreshes of leases held
+// for ttl, leaving room for a couple of failed refreshes.
+func LeaseDelayBetween(ttl time.Duration) time.Duration {
+	return (ttl - 1*time.Second) / 3
+}
+
+// leaseTtl is the TTL of the leases taken by AcquireLease.
+var leaseTtl = DefaultLeaseTtl
+
+// LeaseExpiredError is returned when the lease on a machine expired because
+// it couldn't be refreshed, meaning other clients may have updated the machine.
+type LeaseExpiredError struct {
+	MachineID string
+	Err       error
+}
+
+func (e *LeaseExpiredError) Error() string {
+	return fmt.Sprintf("lease on machine %s expired: %v", e.MachineID, e.Err)
+}
+
+func (e *LeaseExpiredError) Unwrap() error {
+	return e.Err
+}
+
+// CheckLease returns a *LeaseExpiredError wrapping err if the machine lost
+// the lease it held, which is the likely cause of err. It returns nil when the
+// lease is still held or can't be checked.
+func CheckLease(ctx context.Context, m *api.Machine, err error) error {
+	if m.LeaseNonce == "" {
+		return nil
+	}
+
+	lease, findErr := flaps.FromContext(ctx).FindLease(ctx, m.ID)
+	if findErr != nil {
+		var flapsErr *flaps.FlapsError
+		if errors.As(findErr, &flapsErr) && flapsErr.ResponseStatusCode == http.StatusNotFound {
+			return &LeaseExpiredError{MachineID: m.ID, Err: err}
+		}
+		return nil
+	}
+
+	if lease.Data == nil || lease.Data.Nonce != m.LeaseNonce {
+		return &LeaseExpiredError{MachineID: m.ID, Err: err}
+	}
+
+	return nil
+}
+
 // AcquireAllLeases works to acquire/attach a lease for each active machine.
 func AcquireAllLeases(ctx context.Context) ([]*api.Machine, releaseLeasesFunc, error) {
 	releaseFunc := func(ctx context.Context, machines []*api.Machine) {}
@@ -30,10 +84,14 @@ func AcquireLeases(ctx context.Context, machines []*api.Machine) ([]*api.Machine
 	var (
 		flapsClient = flaps.FromContext(ctx)
 		io          = iostreams.FromContext(ctx)
+		leased      = map[string]*leasableMachine{}
 	)
 
 	releaseFunc := func(ctx context.Context, machines []*api.Machine) {
 		for _, m := range machines {
+			if lm, ok := leased[m.ID]; ok {
+				lm.resetLease()
+			}
 			if err := flapsClient.ReleaseLease(ctx, m.ID, m.LeaseNonce); err != nil {
 				if !strings.Contains(err.Error(), "lease not found") {
 					fmt.Fprintf(io.Out, "failed to release lease for machine %s: %s", m.ID, err.Error())
@@ -44,17 +102,24 @@ func AcquireLeases(ctx context.Context, machines []*api.Machine) ([]*api.Machine
 
 	leaseHoldingMachines := []*api.Machine{}
 	for _, machine := range machines {
-		m, _, err := AcquireLease(ctx, machine)
+		lm, m, err := leaseMachine(ctx, machine)
 		if err != nil {
+			if lm != nil {
+				// the machine isn't handed back, so don't leave it leased
+				_ = lm.ReleaseLease(ctx)
+			}
 			return leaseHoldingMachines, releaseFunc, err
 		}
+		leased[m.ID] = lm
 		leaseHoldingMachines = append(leaseHoldingMachines, m)
 	}
 
 	return leaseHoldingMachines, releaseFunc, nil
 }
 
-// AcquireLease works to acquire/attach a lease for the specified machine.
+// AcquireLease works to acquire/attach a lease for the specified machine. The
+// lease is refreshed in the background until released, so that it doesn't
+// expire during long operations.
 // WARNING: Make sure you defer the lease release process.
 func AcquireLease(ctx context.Context, machine *api.Machine) (*api.Machine, releaseLeaseFunc, error) {
 	var (
@@ -62,7 +127,12 @@ func AcquireLease(ctx context.Context, machine *api.Machine) (*api.Machine, rele
 		io          = iostreams.FromContext(ctx)
 	)
 
+	lm, machine, err := leaseMachine(ctx, machine)
+
 	releaseFunc := func(ctx context.Context, machine *api.Machine) {
+		if lm != nil {
+			lm.resetLease()
+		}
 		if machine != nil {
 			if err := flapsClient.ReleaseLease(ctx, machine.ID, machine.LeaseNonce); err != nil {
 				fmt.Fprintf(io.Out, "failed to release lease for machine %s: %s\n", machine.ID, err.Error())
@@ -70,22 +140,35 @@ func AcquireLease(ctx context.Context, machine *api.Machine) (*api.Machine, rele
 		}
 	}
 
-	lease, err := flapsClient.AcquireLease(ctx, machine.ID, api.IntPointer(120))
-	if err != nil {
-		return nil, releaseFunc, fmt.Errorf("failed to obtain lease: %w", err)
+	return machine, releaseFunc, err
+}
+
+// leaseMachine acquires a lease on the machine and refreshes it in the
+// background, the same way deploys do, until the lease is reset. The lease is
+// returned whenever acquired, even along with an error.
+func leaseMachine(ctx context.Context, machine *api.Machine) (*leasableMachine, *api.Machine, error) {
+	var (
+		flapsClient = flaps.FromContext(ctx)
+		io          = iostreams.FromContext(ctx)
+		lm          = newLeasableMachine(flapsClient, io, machine)
+	)
+
+	if err := lm.AcquireLease(ctx, leaseTtl); err != nil {
+		return nil, nil, fmt.Errorf("failed to obtain lease: %w", err)
 	}
+	lm.StartBackgroundLeaseRefresh(ctx, leaseTtl, LeaseDelayBetween(leaseTtl))
 
 	// Set lease nonce before we re-fetch the Machines latest configuration.
 	// This will ensure the lease can still be released in the event the upcoming GET fails.
-	machine.LeaseNonce = lease.Data.Nonce
+	machine.LeaseNonce = lm.leaseNonce
 
 	// Re-query machine post-lease acquisition to ensure we are working against the latest configuration.
-	machine, err = flapsClient.Get(ctx, machine.ID)
+	m, err := flapsClient.Get(ctx, machine.ID)
 	if err != nil {
-		return machine, releaseFunc, err
+		return lm, machine, err
 	}
 
-	machine.LeaseNonce = lease.Data.Nonce
+	m.LeaseNonce = lm.leaseNonce
 
-	return machine, releaseFunc, nil
+	return lm, m, nil
 }
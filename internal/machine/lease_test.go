@@ -0,0 +1,187 @@
+package machine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/logger"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+// leaseFlaps serves the machines and leases of an app named app. Leases are
+// handed out with the nonce "nonce".
+type leaseFlaps struct {
+	mu        sync.Mutex
+	lease     *api.MachineLeaseData
+	refreshes int
+	releases  int
+}
+
+func (f *leaseFlaps) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/v1/apps/app/machines/")
+	id, rest, _ := strings.Cut(path, "/")
+
+	switch {
+	case r.Method == http.MethodGet && rest == "":
+		_ = json.NewEncoder(w).Encode(api.Machine{ID: id, Config: &api.MachineConfig{}})
+	case r.Method == http.MethodGet && rest == "lease":
+		if f.lease == nil {
+			http.Error(w, `{"error":"lease not found"}`, http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(api.MachineLease{Status: "success", Data: f.lease})
+	case r.Method == http.MethodPost && rest == "lease":
+		if r.Header.Get(flaps.NonceHeader) != "" {
+			f.refreshes++
+		}
+		f.lease = &api.MachineLeaseData{Nonce: "nonce"}
+		_ = json.NewEncoder(w).Encode(api.MachineLease{Status: "success", Data: f.lease})
+	case r.Method == http.MethodDelete && rest == "lease":
+		f.releases++
+		f.lease = nil
+		w.WriteHeader(http.StatusOK)
+	default:
+		http.Error(w, "unexpected request", http.StatusBadRequest)
+	}
+}
+
+func (f *leaseFlaps) counts() (refreshes, releases int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.refreshes, f.releases
+}
+
+func newLeaseTestContext(t *testing.T, fake *leaseFlaps) context.Context {
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	t.Setenv("FLY_FLAPS_BASE_URL", server.URL)
+	t.Setenv("FLY_FLAPS_RETRIES", "0")
+
+	ctx := logger.NewContext(context.Background(), logger.FromEnv(io.Discard))
+	flapsClient, err := flaps.NewWithOptions(ctx, &flaps.NewClientOpts{AppName: "app"})
+	require.NoError(t, err)
+
+	streams, _, _, _ := iostreams.Test()
+	ctx = iostreams.NewContext(ctx, streams)
+
+	return flaps.NewContext(ctx, flapsClient)
+}
+
+func TestAcquireLeaseRefreshesUntilReleased(t *testing.T) {
+	defer func(ttl time.Duration) { leaseTtl = ttl }(leaseTtl)
+	leaseTtl = 1300 * time.Millisecond // refreshed every 100ms
+
+	fake := &leaseFlaps{}
+	ctx := newLeaseTestContext(t, fake)
+
+	m, release, err := AcquireLease(ctx, &api.Machine{ID: "m1"})
+	require.NoError(t, err)
+	assert.Equal(t, "nonce", m.LeaseNonce)
+
+	assert.Eventually(t, func() bool {
+		refreshes, _ := fake.counts()
+		return refreshes >= 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	release(ctx, m)
+	refreshes, releases := fake.counts()
+	assert.Equal(t, 1, releases)
+
+	time.Sleep(300 * time.Millisecond)
+	after, _ := fake.counts()
+	assert.Equal(t, refreshes, after, "lease refreshed after being released")
+}
+
+func TestAcquireLeasesRefreshesUntilReleased(t *testing.T) {
+	defer func(ttl time.Duration) { leaseTtl = ttl }(leaseTtl)
+	leaseTtl = 1300 * time.Millisecond
+
+	fake := &leaseFlaps{}
+	ctx := newLeaseTestContext(t, fake)
+
+	machines, release, err := AcquireLeases(ctx, []*api.Machine{{ID: "m1"}, {ID: "m2"}})
+	require.NoError(t, err)
+	require.Len(t, machines, 2)
+
+	assert.Eventually(t, func() bool {
+		refreshes, _ := fake.counts()
+		return refreshes >= 4
+	}, 5*time.Second, 10*time.Millisecond)
+
+	release(ctx, machines)
+	refreshes, releases := fake.counts()
+	assert.Equal(t, 2, releases)
+
+	time.Sleep(300 * time.Millisecond)
+	after, _ := fake.counts()
+	assert.Equal(t, refreshes, after, "leases refreshed after being released")
+}
+
+func TestCheckLease(t *testing.T) {
+	updateErr := errors.New("update failed")
+
+	cases := []struct {
+		name    string
+		lease   *api.MachineLeaseData
+		nonce   string
+		expired bool
+	}{
+		{name: "held", lease: &api.MachineLeaseData{Nonce: "nonce"}, nonce: "nonce"},
+		{name: "taken by another client", lease: &api.MachineLeaseData{Nonce: "other"}, nonce: "nonce", expired: true},
+		{name: "gone", nonce: "nonce", expired: true},
+		{name: "never leased", lease: &api.MachineLeaseData{Nonce: "other"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := newLeaseTestContext(t, &leaseFlaps{lease: tc.lease})
+
+			err := CheckLease(ctx, &api.Machine{ID: "m1", LeaseNonce: tc.nonce}, updateErr)
+			if !tc.expired {
+				assert.NoError(t, err)
+				return
+			}
+
+			var leaseErr *LeaseExpiredError
+			require.ErrorAs(t, err, &leaseErr)
+			assert.Equal(t, "m1", leaseErr.MachineID)
+			assert.ErrorIs(t, err, updateErr)
+			assert.EqualError(t, err, "lease on machine m1 expired: update failed")
+		})
+	}
+}
+
+func TestCheckLeaseUnknown(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	t.Setenv("FLY_FLAPS_BASE_URL", server.URL)
+	t.Setenv("FLY_FLAPS_RETRIES", "0")
+
+	ctx := logger.NewContext(context.Background(), logger.FromEnv(io.Discard))
+	flapsClient, err := flaps.NewWithOptions(ctx, &flaps.NewClientOpts{AppName: "app"})
+	require.NoError(t, err)
+
+	// the update error stands when the lease can't be checked
+	assert.NoError(t, CheckLease(flaps.NewContext(ctx, flapsClient), &api.Machine{ID: "m1", LeaseNonce: "nonce"}, errors.New("update failed")))
+}
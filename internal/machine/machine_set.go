@@ -36,6 +36,14 @@ func NewMachineSet(flapsClient *flaps.Client, io *iostreams.IOStreams, machines
 	}
 }
 
+// NewMachineSetFromLeasables returns a set of already leasable machines, e.g.
+// a subset of another set. The machines share their leases between both sets.
+func NewMachineSetFromLeasables(machines []LeasableMachine) MachineSet {
+	return &machineSet{
+		machines: machines,
+	}
+}
+
 func (ms *machineSet) IsEmpty() bool {
 	return len(ms.machines) == 0
 }
@@ -0,0 +1,26 @@
+package machine
+
+import (
+	"fmt"
+
+	"github.com/superfly/flyctl/api"
+)
+
+// RemapMounts points the mounts of the config at the volumes replacing the
+// ones they mount, as given by the IDs of the replacements by the IDs of the
+// originals, e.g. when recreating machines in another app. The config is left
+// as is when a mounted volume has no replacement.
+func RemapMounts(config *api.MachineConfig, volumes map[string]string) error {
+	mounts := make([]api.MachineMount, len(config.Mounts))
+	for i, mount := range config.Mounts {
+		id, ok := volumes[mount.Volume]
+		if !ok {
+			return fmt.Errorf("volume %s mounted at %s has no replacement", mount.Volume, mount.Path)
+		}
+		mount.Volume = id
+		mounts[i] = mount
+	}
+	config.Mounts = mounts
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package machine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/superfly/flyctl/api"
+)
+
+func TestRemapMounts(t *testing.T) {
+	config := &api.MachineConfig{Mounts: []api.MachineMount{
+		{Volume: "vol_old1", Path: "/data"},
+		{Volume: "vol_old2", Path: "/logs"},
+	}}
+	original := append([]api.MachineMount(nil), config.Mounts...)
+
+	volumes := map[string]string{"vol_old1": "vol_new1", "vol_old2": "vol_new2"}
+	require.NoError(t, RemapMounts(config, volumes))
+	assert.Equal(t, []api.MachineMount{
+		{Volume: "vol_new1", Path: "/data"},
+		{Volume: "vol_new2", Path: "/logs"},
+	}, config.Mounts)
+
+	// configs mounting volumes without replacements are left alone
+	config.Mounts = original
+	err := RemapMounts(config, map[string]string{"vol_old1": "vol_new1"})
+	assert.EqualError(t, err, "volume vol_old2 mounted at /logs has no replacement")
+	assert.Equal(t, original, config.Mounts)
+
+	// the mounts of the original config aren't modified in place
+	assert.Equal(t, "vol_old1", original[0].Volume)
+}
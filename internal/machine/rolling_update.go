@@ -0,0 +1,254 @@
+package machine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/samber/lo"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+// RollingUpdateTarget is a machine along with the update to apply to it.
+type RollingUpdateTarget struct {
+	Machine *api.Machine
+	Input   *api.LaunchMachineInput
+}
+
+type RollingUpdateOptions struct {
+	// Rollback denotes whether machines which were updated before one failed
+	// should be updated back to their previous config.
+	Rollback bool
+
+	// BatchSize is the number of machines updated at once. It defaults to 1.
+	BatchSize int
+
+	// MaxUnavailable caps the number of machines updated at once, either as a
+	// number of machines (e.g. "3") or as a percentage of them (e.g. "25%").
+	MaxUnavailable string
+
+	// AutoConfirm skips showing the changes to apply and asking for
+	// confirmation before updating any machine.
+	AutoConfirm bool
+}
+
+// batchSize returns how many of n machines may be updated at once.
+func (opts RollingUpdateOptions) batchSize(n int) (int, error) {
+	size := opts.BatchSize
+	if size < 1 {
+		size = 1
+	}
+
+	if opts.MaxUnavailable != "" {
+		max, err := ParseMaxUnavailable(opts.MaxUnavailable, n)
+		if err != nil {
+			return 0, err
+		}
+		if opts.BatchSize < 1 || max < size {
+			size = max
+		}
+	}
+
+	return size, nil
+}
+
+// ParseMaxUnavailable parses a maximum number of unavailable machines out of n,
+// given either as a number (e.g. "3") or a percentage (e.g. "25%"). The result
+// is at least 1 so that updates make progress.
+func ParseMaxUnavailable(v string, n int) (int, error) {
+	var max int
+
+	if pct, ok := strings.CutSuffix(v, "%"); ok {
+		f, err := strconv.ParseFloat(pct, 64)
+		if err != nil || f <= 0 || f > 100 {
+			return 0, fmt.Errorf("invalid max unavailable %q, percentages must be within (0%%, 100%%]", v)
+		}
+		max = int(math.Floor(float64(n) * f / 100))
+	} else {
+		i, err := strconv.Atoi(v)
+		if err != nil || i < 1 {
+			return 0, fmt.Errorf("invalid max unavailable %q, must be a positive number or a percentage", v)
+		}
+		max = i
+	}
+
+	if max < 1 {
+		max = 1
+	}
+
+	return max, nil
+}
+
+const (
+	rolloutPending        = "pending"
+	rolloutUpdated        = "updated"
+	rolloutFailed         = "failed"
+	rolloutLeaseExpired   = "lease expired"
+	rolloutRolledBack     = "rolled back"
+	rolloutRollbackFailed = "rollback failed"
+)
+
+// RollingUpdate updates the target machines in batches, one machine at a time
+// unless configured otherwise, waiting for each batch to be healthy before
+// moving on. Unless auto-confirmed, the changes to every machine are shown and
+// confirmed first; machines without changes are skipped. It stops after the
+// first batch in which a machine fails to update and, if requested, rolls the
+// machines updated so far back. A summary of the image every machine runs is
+// printed at the end.
+func RollingUpdate(ctx context.Context, targets []RollingUpdateTarget, opts RollingUpdateOptions) (err error) {
+	io := iostreams.FromContext(ctx)
+
+	targets = lo.Filter(targets, func(t RollingUpdateTarget, _ int) bool {
+		if t.Machine.IsCordoned() {
+			fmt.Fprintf(io.Out, "Skipping cordoned machine %s\n", t.Machine.ID)
+			return false
+		}
+		return len(DiffConfigs(t.Machine.Config, t.Input.Config)) > 0
+	})
+	if len(targets) == 0 {
+		fmt.Fprintln(io.Out, "No changes to apply")
+		return nil
+	}
+
+	if !opts.AutoConfirm {
+		switch confirmed, err := confirmRollingUpdate(ctx, targets); {
+		case err != nil:
+			return err
+		case !confirmed:
+			return errors.New("update aborted")
+		}
+	}
+
+	var (
+		status = make([]string, len(targets))
+		failed = -1
+	)
+
+	size, err := opts.batchSize(len(targets))
+	if err != nil {
+		return err
+	}
+
+	for i := range status {
+		status[i] = rolloutPending
+	}
+
+	defer func() {
+		_ = renderRolloutSummary(io, targets, status)
+	}()
+
+	for start := 0; start < len(targets) && failed < 0; start += size {
+		end := start + size
+		if end > len(targets) {
+			end = len(targets)
+		}
+
+		if size > 1 {
+			fmt.Fprintf(io.Out, "Updating machines %d to %d of %d\n", start+1, end, len(targets))
+		}
+
+		var (
+			wg   sync.WaitGroup
+			mu   sync.Mutex
+			errs []error
+		)
+		for i := start; i < end; i++ {
+			i := i
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				if err := Update(ctx, targets[i].Machine, targets[i].Input); err != nil {
+					mu.Lock()
+					defer mu.Unlock()
+
+					status[i] = rolloutFailed
+					if leaseErr := (*LeaseExpiredError)(nil); errors.As(err, &leaseErr) {
+						status[i] = rolloutLeaseExpired
+					}
+					errs = append(errs, err)
+					return
+				}
+
+				status[i] = rolloutUpdated
+			}()
+		}
+		wg.Wait()
+
+		if len(errs) > 0 {
+			err = errors.Join(errs...)
+			failed = end - 1
+		}
+	}
+
+	if failed < 0 || !opts.Rollback {
+		return err
+	}
+
+	fmt.Fprintln(io.ErrOut, "Machines failed to update, rolling back the machines updated so far")
+
+	// failed machines may have been updated even though they aren't healthy,
+	// so they're rolled back as well
+	for i := failed; i >= 0; i-- {
+		t := targets[i]
+
+		input := *t.Input
+		input.Config = CloneConfig(t.Machine.Config)
+
+		if rollbackErr := Update(ctx, t.Machine, &input); rollbackErr != nil {
+			fmt.Fprintf(io.ErrOut, "failed rolling back machine %s: %v\n", t.Machine.ID, rollbackErr)
+			status[i] = rolloutRollbackFailed
+			continue
+		}
+
+		status[i] = rolloutRolledBack
+	}
+
+	return err
+}
+
+// confirmRollingUpdate shows the changes to apply to every target and asks for
+// confirmation.
+func confirmRollingUpdate(ctx context.Context, targets []RollingUpdateTarget) (bool, error) {
+	io := iostreams.FromContext(ctx)
+
+	fmt.Fprintln(io.Out, "Configuration changes to be applied:")
+	for _, t := range targets {
+		fmt.Fprintln(io.Out)
+		RenderConfigChanges(io.Out, io.ColorScheme(), t.Machine, DiffConfigs(t.Machine.Config, t.Input.Config))
+	}
+	fmt.Fprintln(io.Out)
+
+	switch confirmed, err := prompt.Confirmf(ctx, "Apply changes to %d machine(s)?", len(targets)); {
+	case err == nil:
+		return confirmed, nil
+	case prompt.IsNonInteractive(err):
+		return false, prompt.NonInteractiveError("yes flag must be specified when not running interactively")
+	default:
+		return false, err
+	}
+}
+
+func renderRolloutSummary(io *iostreams.IOStreams, targets []RollingUpdateTarget, status []string) error {
+	rows := make([][]string, 0, len(targets))
+	for i, t := range targets {
+		// failed machines may run either image, the new one is the likeliest
+		image := t.Machine.Config.Image
+		if status[i] == rolloutUpdated || status[i] == rolloutFailed {
+			image = t.Input.Config.Image
+		}
+
+		rows = append(rows, []string{t.Machine.ID, t.Machine.Region, image, status[i]})
+	}
+
+	return render.Table(io.Out, "Rolling update summary", rows, "Machine", "Region", "Image", "Status")
+}
@@ -0,0 +1,34 @@
+package machine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRollingUpdateBatchSize(t *testing.T) {
+	cases := []struct {
+		opts RollingUpdateOptions
+		n    int
+		exp  int
+	}{
+		{RollingUpdateOptions{}, 10, 1},
+		{RollingUpdateOptions{BatchSize: 4}, 10, 4},
+		{RollingUpdateOptions{MaxUnavailable: "25%"}, 100, 25},
+		{RollingUpdateOptions{MaxUnavailable: "25%"}, 3, 1},
+		{RollingUpdateOptions{MaxUnavailable: "3"}, 10, 3},
+		{RollingUpdateOptions{BatchSize: 10, MaxUnavailable: "50%"}, 10, 5},
+		{RollingUpdateOptions{BatchSize: 2, MaxUnavailable: "50%"}, 10, 2},
+	}
+
+	for _, c := range cases {
+		got, err := c.opts.batchSize(c.n)
+		assert.NoError(t, err)
+		assert.Equal(t, c.exp, got, "%+v of %d", c.opts, c.n)
+	}
+
+	for _, v := range []string{"0", "-1", "0%", "101%", "a%", "many"} {
+		_, err := ParseMaxUnavailable(v, 10)
+		assert.Error(t, err, v)
+	}
+}
@@ -0,0 +1,143 @@
+package machine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/render"
+)
+
+// Per-second list prices used to estimate the cost of sessions. Memory is
+// billed on top of the CPU price, per GB.
+const (
+	sharedCPUPricePerSecond      = 0.000000266
+	performanceCPUPricePerSecond = 0.0000081
+	memoryGBPricePerSecond       = 0.00000193
+)
+
+// Session accounts for the time a machine was used for a single ad-hoc task,
+// such as a one-off `fly machine run --rm` or an ssh console.
+type Session struct {
+	MachineID string
+	Region    string
+	Guest     *api.MachineGuest
+	StartedAt time.Time
+	EndedAt   time.Time
+}
+
+// NewSession starts accounting for a session on the machine now.
+func NewSession(machine *api.Machine) *Session {
+	s := &Session{
+		MachineID: machine.ID,
+		Region:    machine.Region,
+		StartedAt: time.Now(),
+	}
+	if machine.Config != nil {
+		s.Guest = machine.Config.Guest
+	}
+
+	return s
+}
+
+// End marks the session as ended now.
+func (s *Session) End() {
+	s.EndedAt = time.Now()
+}
+
+// Duration returns how long the session lasted, or has lasted so far.
+func (s *Session) Duration() time.Duration {
+	end := s.EndedAt
+	if end.IsZero() {
+		end = time.Now()
+	}
+
+	return end.Sub(s.StartedAt)
+}
+
+// Size returns a description of the guest the session ran on.
+func (s *Session) Size() string {
+	if s.Guest == nil {
+		return "unknown"
+	}
+
+	return fmt.Sprintf("%s-cpu-%dx@%dMB", s.Guest.CPUKind, s.Guest.CPUs, s.Guest.MemoryMB)
+}
+
+// EstimatedCost returns the estimated cost of the session in US dollars, based
+// on list prices. It doesn't account for volumes, bandwidth or any allowances.
+func (s *Session) EstimatedCost() float64 {
+	if s.Guest == nil {
+		return 0
+	}
+
+	cpuPrice := sharedCPUPricePerSecond
+	if s.Guest.CPUKind == "performance" {
+		cpuPrice = performanceCPUPricePerSecond
+	}
+
+	perSecond := float64(s.Guest.CPUs)*cpuPrice + float64(s.Guest.MemoryMB)/1024*memoryGBPricePerSecond
+
+	return perSecond * s.Duration().Seconds()
+}
+
+// Render writes a summary of the session to w, as text or as JSON.
+func (s *Session) Render(ctx context.Context, w io.Writer, asJSON bool) error {
+	if asJSON {
+		return render.Output(ctx, w, struct {
+			MachineID        string    `json:"machine_id"`
+			Region           string    `json:"region"`
+			Size             string    `json:"size"`
+			StartedAt        time.Time `json:"started_at"`
+			EndedAt          time.Time `json:"ended_at"`
+			DurationSeconds  float64   `json:"duration_seconds"`
+			EstimatedCostUSD float64   `json:"estimated_cost_usd"`
+		}{
+			MachineID:        s.MachineID,
+			Region:           s.Region,
+			Size:             s.Size(),
+			StartedAt:        s.StartedAt,
+			EndedAt:          s.EndedAt,
+			DurationSeconds:  s.Duration().Seconds(),
+			EstimatedCostUSD: s.EstimatedCost(),
+		})
+	}
+
+	_, err := fmt.Fprintf(w, "Session on machine %s ran for %s on %s, estimated cost $%.4f\n",
+		s.MachineID, s.Duration().Round(time.Second), s.Size(), s.EstimatedCost())
+
+	return err
+}
+
+// WaitForExit blocks until the machine stops or is destroyed.
+func WaitForExit(ctx context.Context, machine *api.Machine) error {
+	flapsClient := flaps.FromContext(ctx)
+
+	for {
+		m, err := flapsClient.Get(ctx, machine.ID)
+
+		var flapsErr *flaps.FlapsError
+		switch {
+		case errors.As(err, &flapsErr) && flapsErr.ResponseStatusCode == http.StatusNotFound:
+			return nil
+		case err != nil:
+			return err
+		}
+
+		switch m.State {
+		case "stopped", "destroying", "destroyed":
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(2 * time.Second):
+		}
+	}
+}
@@ -20,7 +20,35 @@ var cpusPerKind = map[string][]int{
 	"performance": {1, 2, 4, 6, 8, 10, 12, 14, 16},
 }
 
+// UpdateOptions tunes how long Update waits for machines to become healthy.
+// Zero values fall back to defaults.
+type UpdateOptions struct {
+	// WaitTimeout is how long to wait for the machine to start or stop.
+	WaitTimeout time.Duration
+
+	// CheckTimeout is how long to wait for health checks to pass.
+	CheckTimeout time.Duration
+
+	// CheckGracePeriod is how long to let the machine boot before requiring
+	// health checks to pass.
+	CheckGracePeriod time.Duration
+}
+
+// DefaultUpdateWaitTimeout is how long Update waits for machines to start or
+// stop by default.
+const DefaultUpdateWaitTimeout = 5 * time.Minute
+
 func Update(ctx context.Context, m *api.Machine, input *api.LaunchMachineInput) error {
+	return UpdateWithOptions(ctx, m, input, UpdateOptions{})
+}
+
+// UpdateWithOptions updates the machine and waits for it to be healthy, as
+// configured by opts.
+func UpdateWithOptions(ctx context.Context, m *api.Machine, input *api.LaunchMachineInput, opts UpdateOptions) error {
+	if opts.WaitTimeout == 0 {
+		opts.WaitTimeout = DefaultUpdateWaitTimeout
+	}
+
 	var (
 		flapsClient    = flaps.FromContext(ctx)
 		io             = iostreams.FromContext(ctx)
@@ -84,20 +112,23 @@ func Update(ctx context.Context, m *api.Machine, input *api.LaunchMachineInput)
 	input.ID = m.ID
 	updatedMachine, err = flapsClient.Update(ctx, *input, m.LeaseNonce)
 	if err != nil {
-		return fmt.Errorf("could not stop machine %s: %w", input.ID, err)
+		if leaseErr := CheckLease(ctx, m, err); leaseErr != nil {
+			return leaseErr
+		}
+		return fmt.Errorf("could not update machine %s: %w", input.ID, err)
 	}
 
 	waitForAction := "start"
 	if input.SkipLaunch || m.Config.Schedule != "" {
 		waitForAction = "stop"
 	}
-	if err := WaitForStartOrStop(ctx, updatedMachine, waitForAction, time.Minute*5); err != nil {
+	if err := WaitForStartOrStop(ctx, updatedMachine, waitForAction, opts.WaitTimeout); err != nil {
 		return err
 	}
 
 	if !input.SkipLaunch {
 		if !input.SkipHealthChecks {
-			if err := watch.MachinesChecks(ctx, []*api.Machine{updatedMachine}); err != nil {
+			if err := watch.MachinesChecksWithTimeout(ctx, []*api.Machine{updatedMachine}, opts.CheckTimeout, opts.CheckGracePeriod); err != nil {
 				return fmt.Errorf("failed to wait for health checks to pass: %w", err)
 			}
 		}
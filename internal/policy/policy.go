@@ -0,0 +1,219 @@
+// Package policy implements the constraints organizations declare on the
+// regions, sizes, IP addresses and redundancy of the machines of their apps,
+// which flyctl enforces before changing them.
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/internal/appconfig"
+)
+
+// Policy is the set of constraints of an organization. The zero value of each
+// field doesn't constrain anything.
+type Policy struct {
+	Org string
+	// AllowedRegions are the only regions machines may run in.
+	AllowedRegions []string `json:",omitempty"`
+	// MaxMachineSize is the name of the largest machine preset, e.g.
+	// shared-cpu-2x. Performance machines are larger than shared ones.
+	MaxMachineSize string `json:",omitempty"`
+	// ForbidDedicatedIPv4 forbids allocating dedicated IPv4 addresses.
+	ForbidDedicatedIPv4 bool `json:",omitempty"`
+	// MinMachines is the least number of machines of each process group of
+	// production apps serving requests.
+	MinMachines int `json:",omitempty"`
+	// ProductionApps are the glob patterns of the names of production apps.
+	// Every app is a production app when there are none.
+	ProductionApps []string `json:",omitempty"`
+	UpdatedAt      time.Time
+}
+
+// Validate returns an error if the policy can't be enforced.
+func (p *Policy) Validate() error {
+	if p.MaxMachineSize != "" {
+		if _, ok := api.MachinePresets[p.MaxMachineSize]; !ok {
+			sizes := lo.Keys(api.MachinePresets)
+			sort.Strings(sizes)
+			return fmt.Errorf("unknown machine size %q, expected one of %s", p.MaxMachineSize, strings.Join(sizes, ", "))
+		}
+	}
+	if p.MinMachines < 0 {
+		return errors.New("the minimum number of machines can't be negative")
+	}
+	for _, pattern := range p.ProductionApps {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid pattern of production apps %q: %w", pattern, err)
+		}
+	}
+
+	return nil
+}
+
+// Describe returns the constraints of the policy, one per line.
+func (p *Policy) Describe() []string {
+	var lines []string
+
+	if len(p.AllowedRegions) > 0 {
+		lines = append(lines, "Machines run in "+strings.Join(p.AllowedRegions, ", "))
+	}
+	if p.MaxMachineSize != "" {
+		lines = append(lines, "Machines are at most "+p.MaxMachineSize)
+	}
+	if p.ForbidDedicatedIPv4 {
+		lines = append(lines, "Apps have no dedicated IPv4 address")
+	}
+	if p.MinMachines > 0 {
+		apps := "every app"
+		if len(p.ProductionApps) > 0 {
+			apps = "apps matching " + strings.Join(p.ProductionApps, ", ")
+		}
+		lines = append(lines, fmt.Sprintf("Process groups serving requests of %s have at least %d machines", apps, p.MinMachines))
+	}
+
+	return lines
+}
+
+// IsProduction reports whether the app is a production app.
+func (p *Policy) IsProduction(app string) bool {
+	if len(p.ProductionApps) == 0 {
+		return true
+	}
+
+	return lo.ContainsBy(p.ProductionApps, func(pattern string) bool {
+		ok, _ := path.Match(pattern, app)
+		return ok
+	})
+}
+
+// RegionViolations returns why machines can't run in the region.
+func (p *Policy) RegionViolations(region string) []string {
+	if p == nil || len(p.AllowedRegions) == 0 || region == "" || lo.Contains(p.AllowedRegions, region) {
+		return nil
+	}
+
+	return []string{fmt.Sprintf("region %s is not allowed, machines must run in %s", region, strings.Join(p.AllowedRegions, ", "))}
+}
+
+// MachineViolations returns why a machine of the guest can't run in the
+// region. Machines without a guest are the smallest shared machines.
+func (p *Policy) MachineViolations(region string, guest *api.MachineGuest) []string {
+	if p == nil {
+		return nil
+	}
+
+	violations := p.RegionViolations(region)
+
+	if p.MaxMachineSize != "" {
+		if guest == nil {
+			guest = api.MachinePresets["shared-cpu-1x"]
+		}
+		if max := api.MachinePresets[p.MaxMachineSize]; max != nil && exceeds(guest, max) {
+			violations = append(violations, fmt.Sprintf("machine size %s exceeds the maximum %s", describeGuest(guest), p.MaxMachineSize))
+		}
+	}
+
+	return violations
+}
+
+func exceeds(guest, max *api.MachineGuest) bool {
+	return guest.CPUs > max.CPUs ||
+		(guest.CPUKind == "performance" && max.CPUKind != "performance") ||
+		guest.MemoryMB > max.MemoryMB*maxMemoryFactor(max)
+}
+
+// maxMemoryFactor is how much more memory than their preset machines of a
+// size may be given, as presets have the least memory of their size.
+func maxMemoryFactor(max *api.MachineGuest) int {
+	if max.CPUKind == "performance" {
+		return api.MAX_MEMORY_MB_PER_CPU / api.MIN_MEMORY_MB_PER_CPU
+	}
+
+	return api.MAX_MEMORY_MB_PER_SHARED_CPU / api.MIN_MEMORY_MB_PER_SHARED_CPU
+}
+
+func describeGuest(guest *api.MachineGuest) string {
+	return fmt.Sprintf("%s with %dMB", guest.ToSize(), guest.MemoryMB)
+}
+
+// CountViolations returns why the app can't run the number of machines of
+// its process groups serving requests.
+func (p *Policy) CountViolations(app string, counts map[string]int) []string {
+	if p == nil || p.MinMachines == 0 || !p.IsProduction(app) {
+		return nil
+	}
+
+	groups := lo.Keys(counts)
+	sort.Strings(groups)
+
+	var violations []string
+	for _, group := range groups {
+		if n := counts[group]; n < p.MinMachines {
+			violations = append(violations, fmt.Sprintf("process group %s of production app %s would run %d machines, it must run at least %d", group, app, n, p.MinMachines))
+		}
+	}
+
+	return violations
+}
+
+// IPViolations returns why an address of the type can't be allocated.
+func (p *Policy) IPViolations(addrType string) []string {
+	if p == nil || !p.ForbidDedicatedIPv4 || addrType != "v4" {
+		return nil
+	}
+
+	return []string{"dedicated IPv4 addresses are forbidden, use a shared IPv4 address instead"}
+}
+
+// ViolationError is the error of changes violating the policy of an
+// organization.
+type ViolationError struct {
+	Org        string
+	Violations []string
+}
+
+func (e *ViolationError) Error() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "this violates the policy of organization %s:", e.Org)
+	for _, v := range e.Violations {
+		fmt.Fprintf(&b, "\n  * %s", v)
+	}
+	fmt.Fprintf(&b, "\nsee 'fly orgs policy show %s'", e.Org)
+
+	return b.String()
+}
+
+// Error returns the error of the violations, or nil when there are none.
+func (p *Policy) Error(violations []string) error {
+	if p == nil || len(violations) == 0 {
+		return nil
+	}
+
+	return &ViolationError{Org: p.Org, Violations: lo.Uniq(violations)}
+}
+
+// ServingGroups returns the process groups of the config serving requests,
+// which are those with services.
+func ServingGroups(cfg *appconfig.Config) map[string]bool {
+	groups := map[string]bool{}
+	for _, s := range cfg.AllServices() {
+		processes := s.Processes
+		if len(processes) == 0 {
+			processes = []string{cfg.DefaultProcessName()}
+		}
+		for _, g := range processes {
+			groups[g] = true
+		}
+	}
+
+	return groups
+}
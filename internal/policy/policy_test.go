@@ -0,0 +1,62 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/superfly/flyctl/api"
+)
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, (&Policy{MaxMachineSize: "shared-cpu-2x", ProductionApps: []string{"*-prod"}}).Validate())
+	assert.ErrorContains(t, (&Policy{MaxMachineSize: "huge"}).Validate(), "unknown machine size")
+	assert.Error(t, (&Policy{MinMachines: -1}).Validate())
+	assert.Error(t, (&Policy{ProductionApps: []string{"["}}).Validate())
+}
+
+func TestMachineViolations(t *testing.T) {
+	p := &Policy{Org: "acme", AllowedRegions: []string{"ams", "fra"}, MaxMachineSize: "shared-cpu-2x"}
+
+	assert.Empty(t, p.MachineViolations("ams", nil))
+	assert.Empty(t, p.MachineViolations("fra", &api.MachineGuest{CPUKind: "shared", CPUs: 2, MemoryMB: 4096}))
+	assert.Len(t, p.MachineViolations("ord", nil), 1)
+	assert.Len(t, p.MachineViolations("ams", &api.MachineGuest{CPUKind: "shared", CPUs: 4, MemoryMB: 1024}), 1)
+	assert.Len(t, p.MachineViolations("ams", &api.MachineGuest{CPUKind: "performance", CPUs: 1, MemoryMB: 2048}), 1)
+	assert.Len(t, p.MachineViolations("ord", &api.MachineGuest{CPUKind: "shared", CPUs: 1, MemoryMB: 8192}), 2)
+
+	var none *Policy
+	assert.Empty(t, none.MachineViolations("ord", nil))
+	assert.NoError(t, none.Error([]string{"anything"}))
+}
+
+func TestCountViolations(t *testing.T) {
+	p := &Policy{Org: "acme", MinMachines: 2, ProductionApps: []string{"*-prod"}}
+
+	assert.Empty(t, p.CountViolations("web-staging", map[string]int{"app": 1}))
+	assert.Empty(t, p.CountViolations("web-prod", map[string]int{"app": 2}))
+	assert.Equal(t,
+		[]string{"process group app of production app web-prod would run 1 machines, it must run at least 2"},
+		p.CountViolations("web-prod", map[string]int{"app": 1, "web": 3}),
+	)
+}
+
+func TestIPViolations(t *testing.T) {
+	p := &Policy{ForbidDedicatedIPv4: true}
+
+	assert.Len(t, p.IPViolations("v4"), 1)
+	assert.Empty(t, p.IPViolations("v6"))
+	assert.Empty(t, p.IPViolations("shared_v4"))
+}
+
+func TestError(t *testing.T) {
+	p := &Policy{Org: "acme"}
+
+	assert.NoError(t, p.Error(nil))
+
+	err := p.Error([]string{"region ord is not allowed", "region ord is not allowed"})
+	var verr *ViolationError
+	require.ErrorAs(t, err, &verr)
+	assert.Equal(t, []string{"region ord is not allowed"}, verr.Violations)
+	assert.Equal(t, "this violates the policy of organization acme:\n  * region ord is not allowed\nsee 'fly orgs policy show acme'", err.Error())
+}
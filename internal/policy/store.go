@@ -0,0 +1,170 @@
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/gql"
+)
+
+// Policies are stored in the metadata of a machine of an app of the
+// organization, so every member and token of the organization enforces them.
+// The machine is never launched.
+const (
+	appRole     = "org-policy"
+	metadataKey = "fly_org_policy"
+	// machineImage is the image of the policy machine, which it needs despite
+	// never running.
+	machineImage = "flyio/hellofly:latest"
+)
+
+// Load returns the policy of the organization, or nil if it has none.
+func Load(ctx context.Context, org string) (*Policy, error) {
+	flapsClient, err := store(ctx, org, false)
+	if err != nil || flapsClient == nil {
+		return nil, err
+	}
+
+	_, p, err := load(ctx, flapsClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading the policy of %s: %w", org, err)
+	}
+
+	return p, nil
+}
+
+// Save stores the policy, replacing the one of its organization.
+func Save(ctx context.Context, p *Policy) error {
+	flapsClient, err := store(ctx, p.Org, true)
+	if err != nil {
+		return err
+	}
+
+	if err := save(ctx, flapsClient, p); err != nil {
+		return fmt.Errorf("failed storing the policy of %s: %w", p.Org, err)
+	}
+
+	return nil
+}
+
+// Remove deletes the policy of the organization, reporting whether it had
+// one.
+func Remove(ctx context.Context, org string) (bool, error) {
+	flapsClient, err := store(ctx, org, false)
+	if err != nil || flapsClient == nil {
+		return false, err
+	}
+
+	removed, err := remove(ctx, flapsClient)
+	if err != nil {
+		return false, fmt.Errorf("failed removing the policy of %s: %w", org, err)
+	}
+
+	return removed, nil
+}
+
+// store returns a flaps client of the policy app of the organization. The app
+// is created when create is set, otherwise the client is nil when the
+// organization has none.
+func store(ctx context.Context, orgSlug string, create bool) (*flaps.Client, error) {
+	apiClient := client.FromContext(ctx).API()
+
+	org, err := apiClient.GetOrganizationBySlug(ctx, orgSlug)
+	if err != nil {
+		return nil, fmt.Errorf("failed retrieving organization %s: %w", orgSlug, err)
+	}
+
+	apps, err := gql.GetAppsByRole(ctx, apiClient.GenqClient, appRole, org.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed retrieving the policy app of %s: %w", orgSlug, err)
+	}
+
+	var app gql.AppData
+	switch {
+	case len(apps.Apps.Nodes) > 0:
+		app = apps.Apps.Nodes[0].AppData
+	case !create:
+		return nil, nil
+	default:
+		input := gql.DefaultCreateAppInput()
+		input.Machines = true
+		input.OrganizationId = org.ID
+		input.AppRoleId = appRole
+		input.Name = org.RawSlug + "-policy"
+
+		created, err := gql.CreateApp(ctx, apiClient.GenqClient, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed creating the policy app of %s: %w", orgSlug, err)
+		}
+		app = created.CreateApp.App.AppData
+	}
+
+	return flaps.New(ctx, gql.ToAppCompact(app))
+}
+
+// load returns the policy stored by the machines of the policy app, along
+// with the machine holding it, or the machine to store one in if there's
+// none.
+func load(ctx context.Context, flapsClient *flaps.Client) (*api.Machine, *Policy, error) {
+	machines, err := flapsClient.List(ctx, "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, m := range machines {
+		if m.Config == nil || m.Config.Metadata[metadataKey] == "" {
+			continue
+		}
+
+		var p Policy
+		if err := json.Unmarshal([]byte(m.Config.Metadata[metadataKey]), &p); err != nil {
+			return nil, nil, fmt.Errorf("failed parsing the policy of machine %s: %w", m.ID, err)
+		}
+		return m, &p, nil
+	}
+
+	if len(machines) > 0 {
+		return machines[0], nil, nil
+	}
+	return nil, nil, nil
+}
+
+func save(ctx context.Context, flapsClient *flaps.Client, p *Policy) error {
+	m, _, err := load(ctx, flapsClient)
+	if err != nil {
+		return err
+	}
+
+	raw, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	if m != nil {
+		return flapsClient.SetMetadata(ctx, m.ID, metadataKey, string(raw), "")
+	}
+
+	_, err = flapsClient.Launch(ctx, api.LaunchMachineInput{
+		Name:       "policy",
+		SkipLaunch: true,
+		Config: &api.MachineConfig{
+			Image:    machineImage,
+			Guest:    api.MachinePresets["shared-cpu-1x"],
+			Metadata: map[string]string{metadataKey: string(raw)},
+		},
+	})
+	return err
+}
+
+func remove(ctx context.Context, flapsClient *flaps.Client) (bool, error) {
+	m, p, err := load(ctx, flapsClient)
+	if err != nil || p == nil {
+		return false, err
+	}
+
+	return true, flapsClient.DeleteMetadata(ctx, m.ID, metadataKey, "")
+}
@@ -0,0 +1,113 @@
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/logger"
+)
+
+// policyFlaps serves the machines of the policy app, an app named policy.
+type policyFlaps struct {
+	machines []*api.Machine
+}
+
+func (f *policyFlaps) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/v1/apps/policy/machines")
+	id, key, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/metadata/")
+
+	find := func() *api.Machine {
+		for _, m := range f.machines {
+			if m.ID == id {
+				return m
+			}
+		}
+		return nil
+	}
+
+	switch {
+	case r.Method == http.MethodGet && path == "":
+		_ = json.NewEncoder(w).Encode(f.machines)
+	case r.Method == http.MethodPost && path == "":
+		var input api.LaunchMachineInput
+		_ = json.NewDecoder(r.Body).Decode(&input)
+		m := &api.Machine{ID: "m1", Config: input.Config}
+		f.machines = append(f.machines, m)
+		_ = json.NewEncoder(w).Encode(m)
+	case r.Method == http.MethodPost && key != "" && find() != nil:
+		var body struct{ Value string }
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		find().Config.Metadata[key] = body.Value
+	case r.Method == http.MethodDelete && key != "" && find() != nil:
+		delete(find().Config.Metadata, key)
+	default:
+		http.Error(w, "unexpected request", http.StatusBadRequest)
+	}
+}
+
+func newStoreTestClient(t *testing.T, fake *policyFlaps) (context.Context, *flaps.Client) {
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	t.Setenv("FLY_FLAPS_BASE_URL", server.URL)
+	t.Setenv("FLY_FLAPS_RETRIES", "0")
+
+	ctx := logger.NewContext(context.Background(), logger.FromEnv(io.Discard))
+	flapsClient, err := flaps.NewWithOptions(ctx, &flaps.NewClientOpts{AppName: "policy"})
+	require.NoError(t, err)
+
+	return ctx, flapsClient
+}
+
+func TestStore(t *testing.T) {
+	fake := &policyFlaps{}
+	ctx, flapsClient := newStoreTestClient(t, fake)
+
+	_, p, err := load(ctx, flapsClient)
+	require.NoError(t, err)
+	assert.Nil(t, p)
+
+	removed, err := remove(ctx, flapsClient)
+	require.NoError(t, err)
+	assert.False(t, removed)
+
+	// the first policy launches the machine holding it, later ones update it
+	require.NoError(t, save(ctx, flapsClient, &Policy{Org: "acme", MinMachines: 2}))
+	require.Len(t, fake.machines, 1)
+	require.NoError(t, save(ctx, flapsClient, &Policy{Org: "acme", MinMachines: 3}))
+	require.Len(t, fake.machines, 1)
+
+	m, p, err := load(ctx, flapsClient)
+	require.NoError(t, err)
+	assert.Equal(t, "m1", m.ID)
+	assert.Equal(t, "acme", p.Org)
+	assert.Equal(t, 3, p.MinMachines)
+
+	removed, err = remove(ctx, flapsClient)
+	require.NoError(t, err)
+	assert.True(t, removed)
+
+	_, p, err = load(ctx, flapsClient)
+	require.NoError(t, err)
+	assert.Nil(t, p)
+}
+
+func TestStoreInvalid(t *testing.T) {
+	fake := &policyFlaps{machines: []*api.Machine{
+		{ID: "m1", Config: &api.MachineConfig{Metadata: map[string]string{metadataKey: "{not json"}}},
+	}}
+	ctx, flapsClient := newStoreTestClient(t, fake)
+
+	_, _, err := load(ctx, flapsClient)
+	assert.ErrorContains(t, err, "failed parsing the policy of machine m1")
+}
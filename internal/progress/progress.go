@@ -0,0 +1,178 @@
+// Package progress implements publishing the progress of long running
+// commands, such as deployments and machine updates, as events that callers of
+// flyctl may render instead of the progress text meant for humans.
+package progress
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/superfly/flyctl/iostreams"
+)
+
+// Status denotes the status of a stage an event reports.
+type Status string
+
+const (
+	StatusStarted   Status = "started"
+	StatusProgress  Status = "progress"
+	StatusCompleted Status = "completed"
+	StatusFailed    Status = "failed"
+)
+
+// The stages commands publish events for. They're part of the output of
+// flyctl, so they must not be renamed.
+const (
+	StageDeploy         = "deploy"
+	StageBuild          = "build"
+	StageReleaseCommand = "release_command"
+	StageUpdateMachines = "update_machines"
+	StageUpdateMachine  = "update_machine"
+	StageLaunchMachine  = "launch_machine"
+	StageDestroyMachine = "destroy_machine"
+	StageHealthChecks   = "health_checks"
+	StageSmokeChecks    = "smoke_checks"
+)
+
+// Event reports the progress of a stage of a command.
+type Event struct {
+	Time      time.Time   `json:"time"`
+	Stage     string      `json:"stage"`
+	Status    Status      `json:"status"`
+	Percent   *int        `json:"percent,omitempty"`
+	MachineID string      `json:"machine_id,omitempty"`
+	Message   string      `json:"message,omitempty"`
+	Error     string      `json:"error,omitempty"`
+	Data      interface{} `json:"data,omitempty"`
+}
+
+// WithStatus returns a copy of e with the given status.
+func (e Event) WithStatus(status Status) Event {
+	e.Status = status
+	return e
+}
+
+// Percent returns the percent of total done, for events to point to.
+func Percent(done, total int) *int {
+	if total <= 0 {
+		return nil
+	}
+	p := done * 100 / total
+	return &p
+}
+
+// Bus delivers the events commands publish to its subscribers.
+//
+// A nil *Bus is valid and delivers nothing. Instances of Bus are safe for
+// concurrent use.
+type Bus struct {
+	mu          sync.Mutex
+	subscribers []func(Event)
+}
+
+// NewBus returns a Bus without subscribers.
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe adds fn to the functions events are delivered to.
+func (b *Bus) Subscribe(fn func(Event)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.subscribers = append(b.subscribers, fn)
+}
+
+// Active reports whether events published to b are delivered anywhere.
+func (b *Bus) Active() bool {
+	if b == nil {
+		return false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return len(b.subscribers) > 0
+}
+
+// Publish delivers e to the subscribers of b, one event at a time.
+func (b *Bus) Publish(e Event) {
+	if b == nil {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, fn := range b.subscribers {
+		fn(e)
+	}
+}
+
+type contextKey struct{}
+
+// NewContext derives a context that carries b from ctx.
+func NewContext(ctx context.Context, b *Bus) context.Context {
+	return context.WithValue(ctx, contextKey{}, b)
+}
+
+// FromContext returns the Bus ctx carries, or nil.
+func FromContext(ctx context.Context) *Bus {
+	b, _ := ctx.Value(contextKey{}).(*Bus)
+	return b
+}
+
+// Publish publishes e to the Bus ctx carries, if any.
+func Publish(ctx context.Context, e Event) {
+	FromContext(ctx).Publish(e)
+}
+
+// Begin publishes the start of the stage e denotes and returns the function
+// publishing its end, as failed when given an error.
+func Begin(ctx context.Context, e Event) (end func(error)) {
+	Publish(ctx, e.WithStatus(StatusStarted))
+
+	return func(err error) {
+		if err != nil {
+			Fail(ctx, e, err)
+			return
+		}
+		Publish(ctx, e.WithStatus(StatusCompleted))
+	}
+}
+
+// Fail publishes the failure of the stage e denotes with err.
+func Fail(ctx context.Context, e Event, err error) {
+	e.Error = err.Error()
+	Publish(ctx, e.WithStatus(StatusFailed))
+}
+
+// SeparateOutput derives a context whose IOStreams write to stderr rather than
+// stdout while events stream to stdout, so that the text meant for humans
+// doesn't get in the way of parsing them.
+func SeparateOutput(ctx context.Context) context.Context {
+	if !FromContext(ctx).Active() {
+		return ctx
+	}
+
+	io := iostreams.FromContext(ctx)
+	separated := *io
+	separated.Out = io.ErrOut
+
+	return iostreams.NewContext(ctx, &separated)
+}
+
+// NDJSON returns a subscriber writing events to w as newline delimited JSON.
+func NDJSON(w io.Writer) func(Event) {
+	enc := json.NewEncoder(w)
+
+	return func(e Event) {
+		_ = enc.Encode(e)
+	}
+}
@@ -0,0 +1,85 @@
+package progress
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func TestNilBus(t *testing.T) {
+	var b *Bus
+	assert.False(t, b.Active())
+	b.Publish(Event{Stage: StageDeploy})
+
+	// contexts without a bus publish nowhere
+	Begin(context.Background(), Event{Stage: StageDeploy})(nil)
+}
+
+func TestPercent(t *testing.T) {
+	assert.Nil(t, Percent(1, 0))
+	assert.Equal(t, 0, *Percent(0, 3))
+	assert.Equal(t, 66, *Percent(2, 3))
+	assert.Equal(t, 100, *Percent(3, 3))
+}
+
+func TestBeginPublishesNDJSON(t *testing.T) {
+	var out bytes.Buffer
+	bus := NewBus()
+	bus.Subscribe(NDJSON(&out))
+	assert.True(t, bus.Active())
+
+	ctx := NewContext(context.Background(), bus)
+
+	Begin(ctx, Event{Stage: StageUpdateMachine, MachineID: "m1", Percent: Percent(0, 2)})(nil)
+	Begin(ctx, Event{Stage: StageHealthChecks, MachineID: "m1"})(errors.New("check failed"))
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 4)
+
+	var events []map[string]interface{}
+	for _, line := range lines {
+		var e map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &e))
+		assert.NotEmpty(t, e["time"])
+		delete(e, "time")
+		events = append(events, e)
+	}
+
+	assert.Equal(t, []map[string]interface{}{
+		{"stage": "update_machine", "status": "started", "machine_id": "m1", "percent": float64(0)},
+		{"stage": "update_machine", "status": "completed", "machine_id": "m1", "percent": float64(0)},
+		{"stage": "health_checks", "status": "started", "machine_id": "m1"},
+		{"stage": "health_checks", "status": "failed", "machine_id": "m1", "error": "check failed"},
+	}, events)
+}
+
+func TestSeparateOutput(t *testing.T) {
+	io, _, stdout, stderr := iostreams.Test()
+	ctx := iostreams.NewContext(context.Background(), io)
+
+	// without subscribers, stdout is kept
+	ctx = NewContext(ctx, NewBus())
+	assert.Equal(t, ctx, SeparateOutput(ctx))
+
+	bus := NewBus()
+	bus.Subscribe(NDJSON(io.Out))
+	ctx = SeparateOutput(NewContext(ctx, bus))
+
+	fmt.Fprintln(iostreams.FromContext(ctx).Out, "human")
+	bus.Publish(Event{Stage: StageDeploy, Status: StatusStarted})
+
+	assert.Equal(t, "human\n", stderr.String())
+	assert.Contains(t, stdout.String(), `"stage":"deploy"`)
+
+	// the streams of the parent context are left as they were
+	assert.True(t, io.Out == stdout)
+}
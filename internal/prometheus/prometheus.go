@@ -0,0 +1,226 @@
+// Package prometheus implements a client of the Prometheus-compatible metrics
+// API of organizations.
+package prometheus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/internal/config"
+)
+
+// Client queries the metrics of an organization.
+type Client struct {
+	endpoint string
+	token    string
+	client   *http.Client
+}
+
+// New returns a client querying the metrics of the organization, with the
+// API and credentials of the config of ctx.
+func New(ctx context.Context, orgSlug string) *Client {
+	cfg := config.FromContext(ctx)
+
+	return &Client{
+		endpoint: fmt.Sprintf("%s/prometheus/%s", strings.TrimSuffix(cfg.APIBaseURL, "/"), url.PathEscape(orgSlug)),
+		token:    cfg.AccessToken,
+		client:   http.DefaultClient,
+	}
+}
+
+// Point is a value of a series at a time.
+type Point struct {
+	Time  time.Time
+	Value float64
+}
+
+// Series is a time series along with its labels. Instant queries return series
+// of a single point.
+type Series struct {
+	Metric map[string]string
+	Points []Point
+}
+
+// Last returns the value of the last point of the series, or NaN when it has
+// none.
+func (s Series) Last() float64 {
+	if len(s.Points) == 0 {
+		return math.NaN()
+	}
+
+	return s.Points[len(s.Points)-1].Value
+}
+
+// Values returns the values of the points of the series.
+func (s Series) Values() []float64 {
+	values := make([]float64, len(s.Points))
+	for i, p := range s.Points {
+		values[i] = p.Value
+	}
+
+	return values
+}
+
+// Query evaluates query at time at.
+func (c *Client) Query(ctx context.Context, query string, at time.Time) ([]Series, error) {
+	return c.get(ctx, "/api/v1/query", url.Values{
+		"query": {query},
+		"time":  {formatTime(at)},
+	})
+}
+
+// QueryRange evaluates query every step between start and end.
+func (c *Client) QueryRange(ctx context.Context, query string, start, end time.Time, step time.Duration) ([]Series, error) {
+	return c.get(ctx, "/api/v1/query_range", url.Values{
+		"query": {query},
+		"start": {formatTime(start)},
+		"end":   {formatTime(end)},
+		"step":  {strconv.FormatFloat(step.Seconds(), 'f', -1, 64)},
+	})
+}
+
+// ValuesBy runs the instant query and returns the values of its series by the
+// label given, such as "instance".
+func (c *Client) ValuesBy(ctx context.Context, query, label string) (map[string]float64, error) {
+	series, err := c.Query(ctx, query, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]float64, len(series))
+	for _, s := range series {
+		if v := s.Last(); !math.IsNaN(v) {
+			values[s.Metric[label]] = v
+		}
+	}
+
+	return values, nil
+}
+
+func formatTime(t time.Time) string {
+	return strconv.FormatFloat(float64(t.UnixMilli())/1000, 'f', -1, 64)
+}
+
+type response struct {
+	Status    string `json:"status"`
+	ErrorType string `json:"errorType"`
+	Error     string `json:"error"`
+	Data      struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	} `json:"data"`
+}
+
+func (c *Client) get(ctx context.Context, path string, params url.Values) ([]Series, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.endpoint+path+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", api.AuthorizationHeader(c.token))
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var body response
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("metrics API returned %s", resp.Status)
+	}
+	if body.Status != "success" {
+		return nil, fmt.Errorf("metrics API returned %s: %s", resp.Status, body.Error)
+	}
+
+	return parseResult(body.Data.ResultType, body.Data.Result)
+}
+
+func parseResult(resultType string, result json.RawMessage) ([]Series, error) {
+	switch resultType {
+	case "scalar":
+		var sample []interface{}
+		if err := json.Unmarshal(result, &sample); err != nil {
+			return nil, err
+		}
+		p, err := parsePoint(sample)
+		if err != nil {
+			return nil, err
+		}
+		return []Series{{Metric: map[string]string{}, Points: []Point{p}}}, nil
+
+	case "vector":
+		var vector []struct {
+			Metric map[string]string `json:"metric"`
+			Value  []interface{}     `json:"value"`
+		}
+		if err := json.Unmarshal(result, &vector); err != nil {
+			return nil, err
+		}
+
+		series := make([]Series, 0, len(vector))
+		for _, v := range vector {
+			p, err := parsePoint(v.Value)
+			if err != nil {
+				return nil, err
+			}
+			series = append(series, Series{Metric: v.Metric, Points: []Point{p}})
+		}
+		return series, nil
+
+	case "matrix":
+		var matrix []struct {
+			Metric map[string]string `json:"metric"`
+			Values [][]interface{}   `json:"values"`
+		}
+		if err := json.Unmarshal(result, &matrix); err != nil {
+			return nil, err
+		}
+
+		series := make([]Series, 0, len(matrix))
+		for _, m := range matrix {
+			s := Series{Metric: m.Metric, Points: make([]Point, 0, len(m.Values))}
+			for _, v := range m.Values {
+				p, err := parsePoint(v)
+				if err != nil {
+					return nil, err
+				}
+				s.Points = append(s.Points, p)
+			}
+			series = append(series, s)
+		}
+		return series, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported result type %q", resultType)
+	}
+}
+
+// parsePoint parses a [<unix time>, "<value>"] pair.
+func parsePoint(sample []interface{}) (Point, error) {
+	if len(sample) != 2 {
+		return Point{}, fmt.Errorf("malformed sample %v", sample)
+	}
+
+	ts, ok := sample[0].(float64)
+	if !ok {
+		return Point{}, fmt.Errorf("malformed sample time %v", sample[0])
+	}
+	s, ok := sample[1].(string)
+	if !ok {
+		return Point{}, fmt.Errorf("malformed sample value %v", sample[1])
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return Point{}, fmt.Errorf("malformed sample value %q: %w", s, err)
+	}
+
+	return Point{Time: time.UnixMilli(int64(ts * 1000)), Value: v}, nil
+}
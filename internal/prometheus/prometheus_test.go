@@ -0,0 +1,65 @@
+package prometheus
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseResult(t *testing.T) {
+	series, err := parseResult("vector", []byte(`[{"metric":{"instance":"a"},"value":[1700000000.5,"42"]}]`))
+	require.NoError(t, err)
+	require.Len(t, series, 1)
+	assert.Equal(t, "a", series[0].Metric["instance"])
+	assert.Equal(t, 42.0, series[0].Last())
+	assert.Equal(t, time.UnixMilli(1700000000500), series[0].Points[0].Time)
+
+	series, err = parseResult("matrix", []byte(`[{"metric":{"status":"200"},"values":[[1,"1"],[2,"2.5"],[3,"NaN"]]}]`))
+	require.NoError(t, err)
+	require.Len(t, series, 1)
+	assert.Equal(t, []float64{1, 2.5}, series[0].Values()[:2])
+	assert.True(t, math.IsNaN(series[0].Last()))
+
+	series, err = parseResult("scalar", []byte(`[1,"7"]`))
+	require.NoError(t, err)
+	assert.Equal(t, 7.0, series[0].Last())
+
+	_, err = parseResult("string", []byte(`[1,"x"]`))
+	assert.Error(t, err)
+}
+
+func TestQuery(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/prometheus/my-org/api/v1/query", r.URL.Path)
+		assert.Equal(t, "up", r.URL.Query().Get("query"))
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{"instance":"a"},"value":[1,"1"]},{"metric":{"instance":"b"},"value":[1,"0"]}]}}`))
+	}))
+	defer srv.Close()
+
+	c := &Client{endpoint: srv.URL + "/prometheus/my-org", token: "token", client: srv.Client()}
+
+	values, err := c.ValuesBy(context.Background(), "up", "instance")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"a": 1, "b": 0}, values)
+}
+
+func TestQueryError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"status":"error","errorType":"bad_data","error":"parse error"}`))
+	}))
+	defer srv.Close()
+
+	c := &Client{endpoint: srv.URL, client: srv.Client()}
+
+	_, err := c.Query(context.Background(), "up{", time.Now())
+	assert.ErrorContains(t, err, "parse error")
+}
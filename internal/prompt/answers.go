@@ -0,0 +1,153 @@
+package prompt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+// Answers maps prompt messages to pre-recorded answers, allowing interactive
+// flows to run unattended.
+//
+// Keys are matched against prompt messages case-insensitively, ignoring
+// surrounding whitespace and trailing punctuation, so the answer to
+// "Choose an app name (leave blank to generate one):" may be keyed as
+// "choose an app name (leave blank to generate one)".
+type Answers map[string]json.RawMessage
+
+// ReadAnswers decodes a JSON object of answers from r.
+func ReadAnswers(r io.Reader) (Answers, error) {
+	var raw map[string]json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("failed decoding answers: %w", err)
+	}
+
+	answers := make(Answers, len(raw))
+	for k, v := range raw {
+		answers[normalizeMessage(k)] = v
+	}
+
+	return answers, nil
+}
+
+type answersContextKey struct{}
+
+// WithAnswers derives a context that carries answers from ctx.
+func WithAnswers(ctx context.Context, answers Answers) context.Context {
+	return context.WithValue(ctx, answersContextKey{}, answers)
+}
+
+// WithAnswer derives a context from ctx that answers msg with value, on top of
+// the answers ctx carries already.
+func WithAnswer(ctx context.Context, msg string, value any) (context.Context, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid answer for %q: %w", msg, err)
+	}
+
+	existing := answersFromContext(ctx)
+	answers := make(Answers, len(existing)+1)
+	for k, v := range existing {
+		answers[k] = v
+	}
+	answers[normalizeMessage(msg)] = raw
+
+	return WithAnswers(ctx, answers), nil
+}
+
+func answersFromContext(ctx context.Context) Answers {
+	if answers, ok := ctx.Value(answersContextKey{}).(Answers); ok {
+		return answers
+	}
+
+	return nil
+}
+
+func normalizeMessage(msg string) string {
+	msg = strings.Join(strings.Fields(strings.ToLower(msg)), " ")
+
+	return strings.TrimRight(msg, ":? ")
+}
+
+// answer decodes the recorded answer to msg, if any, into dst. It reports
+// whether an answer was found.
+func answer(ctx context.Context, msg string, dst any) (bool, error) {
+	raw, ok := answersFromContext(ctx)[normalizeMessage(msg)]
+	if !ok {
+		return false, nil
+	}
+
+	if err := json.Unmarshal(raw, dst); err != nil {
+		return true, fmt.Errorf("invalid answer for %q: %w", msg, err)
+	}
+
+	return true, nil
+}
+
+// answerOption resolves the recorded answer to msg, given either as an option
+// or its index, to the index of the option.
+func answerOption(ctx context.Context, msg string, options []string) (int, bool, error) {
+	var v any
+	if ok, err := answer(ctx, msg, &v); !ok || err != nil {
+		return 0, ok, err
+	}
+
+	index, err := optionIndex(v, options)
+	if err != nil {
+		return 0, true, fmt.Errorf("invalid answer for %q: %w", msg, err)
+	}
+
+	return index, true, nil
+}
+
+// answerOptions is the multiple choice counterpart of answerOption.
+func answerOptions(ctx context.Context, msg string, options []string) ([]int, bool, error) {
+	var values []any
+	if ok, err := answer(ctx, msg, &values); !ok || err != nil {
+		return nil, ok, err
+	}
+
+	indices := make([]int, 0, len(values))
+	for _, v := range values {
+		index, err := optionIndex(v, options)
+		if err != nil {
+			return nil, true, fmt.Errorf("invalid answer for %q: %w", msg, err)
+		}
+		indices = append(indices, index)
+	}
+
+	return indices, true, nil
+}
+
+func optionIndex(v any, options []string) (int, error) {
+	switch v := v.(type) {
+	case float64:
+		if index := int(v); float64(index) == v && index >= 0 && index < len(options) {
+			return index, nil
+		}
+		return 0, fmt.Errorf("option index %v out of range", v)
+	case string:
+		// options often carry extra details, e.g. "Amsterdam, Netherlands (ams)",
+		// so fall back to matching on a parenthesized code or a prefix.
+		if index := lo.IndexOf(options, v); index >= 0 {
+			return index, nil
+		}
+		for i, option := range options {
+			if strings.Contains(option, "("+v+")") {
+				return i, nil
+			}
+		}
+		for i, option := range options {
+			if strings.HasPrefix(option, v) {
+				return i, nil
+			}
+		}
+		return 0, fmt.Errorf("%q is not one of the options", v)
+	default:
+		return 0, fmt.Errorf("expected an option or its index, got %v", v)
+	}
+}
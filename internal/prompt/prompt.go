@@ -26,6 +26,13 @@ type RegionParams struct {
 }
 
 func String(ctx context.Context, dst *string, msg, def string, required bool) error {
+	if ok, err := answer(ctx, msg, dst); ok {
+		if err == nil && required && *dst == "" {
+			err = fmt.Errorf("answer for %q must not be empty", msg)
+		}
+		return err
+	}
+
 	opt, err := newSurveyIO(ctx)
 	if err != nil {
 		return err
@@ -45,6 +52,10 @@ func String(ctx context.Context, dst *string, msg, def string, required bool) er
 }
 
 func Int(ctx context.Context, dst *int, msg string, def int, required bool) error {
+	if ok, err := answer(ctx, msg, dst); ok {
+		return err
+	}
+
 	opt, err := newSurveyIO(ctx)
 	if err != nil {
 		return err
@@ -71,6 +82,13 @@ func Int(ctx context.Context, dst *int, msg string, def int, required bool) erro
 }
 
 func Password(ctx context.Context, dst *string, msg string, required bool) error {
+	if ok, err := answer(ctx, msg, dst); ok {
+		if err == nil && required && *dst == "" {
+			err = fmt.Errorf("answer for %q must not be empty", msg)
+		}
+		return err
+	}
+
 	opt, err := newSurveyIO(ctx)
 	if err != nil {
 		return err
@@ -89,6 +107,11 @@ func Password(ctx context.Context, dst *string, msg string, required bool) error
 }
 
 func MultiSelect(ctx context.Context, indices *[]int, msg string, def []int, options ...string) error {
+	if answered, ok, err := answerOptions(ctx, msg, options); ok {
+		*indices = answered
+		return err
+	}
+
 	opt, err := newSurveyIO(ctx)
 	if err != nil {
 		return err
@@ -105,6 +128,11 @@ func MultiSelect(ctx context.Context, indices *[]int, msg string, def []int, opt
 }
 
 func Select(ctx context.Context, index *int, msg, def string, options ...string) error {
+	if answered, ok, err := answerOption(ctx, msg, options); ok {
+		*index = answered
+		return err
+	}
+
 	opt, err := newSurveyIO(ctx)
 	if err != nil {
 		return err
@@ -128,6 +156,11 @@ func Confirmf(ctx context.Context, format string, a ...interface{}) (bool, error
 }
 
 func Confirm(ctx context.Context, message string) (confirm bool, err error) {
+	var ok bool
+	if ok, err = answer(ctx, message, &confirm); ok {
+		return
+	}
+
 	var opt survey.AskOpt
 	if opt, err = newSurveyIO(ctx); err != nil {
 		return
@@ -1,7 +1,9 @@
 package prompt
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"testing"
 	"testing/quick"
 
@@ -32,3 +34,51 @@ func TestNonInteractiveError(t *testing.T) {
 	}
 	require.NoError(t, quick.Check(fn, nil))
 }
+
+func TestAnswers(t *testing.T) {
+	answers, err := ReadAnswers(strings.NewReader(`{
+		"Choose an app name:": "my-app",
+		"select region": "ams",
+		"Would you like to set up a Postgresql database now?": true,
+		"Select processes": [0, "worker"]
+	}`))
+	require.NoError(t, err)
+
+	ctx := WithAnswers(context.Background(), answers)
+
+	var name string
+	require.NoError(t, String(ctx, &name, "Choose an app name", "", true))
+	assert.Equal(t, "my-app", name)
+
+	var index int
+	require.NoError(t, Select(ctx, &index, "Select region:", "", "Amsterdam, Netherlands (ams)", "Paris, France (cdg)"))
+	assert.Equal(t, 0, index)
+
+	confirmed, err := Confirm(ctx, "Would you like to set up a Postgresql database now?")
+	require.NoError(t, err)
+	assert.True(t, confirmed)
+
+	var indices []int
+	require.NoError(t, MultiSelect(ctx, &indices, "Select processes:", nil, "app", "worker"))
+	assert.Equal(t, []int{0, 1}, indices)
+
+	err = Select(ctx, &index, "Select region:", "", "Paris, France (cdg)")
+	assert.Error(t, err)
+}
+
+func TestWithAnswer(t *testing.T) {
+	answers, err := ReadAnswers(strings.NewReader(`{"Choose an app name:": "my-app"}`))
+	require.NoError(t, err)
+
+	ctx, err := WithAnswer(WithAnswers(context.Background(), answers), "Would you like to deploy now?", false)
+	require.NoError(t, err)
+
+	var name string
+	require.NoError(t, String(ctx, &name, "Choose an app name", "", true))
+	assert.Equal(t, "my-app", name)
+
+	confirmed, err := Confirm(ctx, "would you like to deploy now")
+	require.NoError(t, err)
+	assert.False(t, confirmed)
+	assert.Len(t, answers, 1, "answers of the parent context are left untouched")
+}
@@ -0,0 +1,191 @@
+package render
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FormatKind denotes a kind of output commands render.
+type FormatKind string
+
+const (
+	// FormatTable is the default output meant to be read by humans.
+	FormatTable FormatKind = "table"
+	// FormatWide is the table output with additional columns.
+	FormatWide FormatKind = "wide"
+	// FormatJSON is the structured output encoded as JSON.
+	FormatJSON FormatKind = "json"
+	// FormatYAML is the structured output encoded as YAML.
+	FormatYAML FormatKind = "yaml"
+	// FormatTemplate is the structured output rendered by a Go template.
+	FormatTemplate FormatKind = "go-template"
+)
+
+// Format denotes the output format requested by the user.
+type Format struct {
+	Kind FormatKind
+
+	// Template holds the text of the template of FormatTemplate outputs.
+	Template string
+}
+
+// Structured reports whether the format renders the data of commands rather
+// than tables.
+func (f Format) Structured() bool {
+	switch f.Kind {
+	case FormatJSON, FormatYAML, FormatTemplate:
+		return true
+	default:
+		return false
+	}
+}
+
+// Wide reports whether tables should include their additional columns.
+func (f Format) Wide() bool {
+	return f.Kind == FormatWide
+}
+
+func (f Format) String() string {
+	if f.Kind == FormatTemplate {
+		return string(f.Kind) + "=" + f.Template
+	}
+	return string(f.Kind)
+}
+
+// FormatUsage is the usage of flags accepting formats.
+const FormatUsage = "Output format: table, wide, json, yaml or go-template='{{...}}'"
+
+// ParseFormat parses the value of the output flag. An empty value denotes the
+// table format.
+func ParseFormat(s string) (f Format, err error) {
+	kind, text, hasText := strings.Cut(s, "=")
+
+	switch k := FormatKind(strings.ToLower(strings.TrimSpace(kind))); k {
+	case "":
+		f.Kind = FormatTable
+	case FormatTable, FormatWide, FormatJSON, FormatYAML:
+		f.Kind = k
+	case FormatTemplate:
+		if !hasText || text == "" {
+			return f, fmt.Errorf("output format %s requires a template over the JSON field names, e.g. %s='{{.id}}'", k, k)
+		}
+		if _, err = template.New("output").Parse(text); err != nil {
+			return f, fmt.Errorf("invalid output template: %w", err)
+		}
+		f.Kind, f.Template = k, text
+		return
+	default:
+		return f, fmt.Errorf("unsupported output format %q, expected table, wide, json, yaml or go-template", s)
+	}
+
+	if hasText {
+		return Format{}, fmt.Errorf("output format %s doesn't accept a value", f.Kind)
+	}
+	return
+}
+
+type contextKey struct{}
+
+// NewContext derives a Context that carries the given output format from ctx.
+func NewContext(ctx context.Context, f Format) context.Context {
+	return context.WithValue(ctx, contextKey{}, f)
+}
+
+// FormatFromContext returns the output format ctx carries, which is the table
+// format in case ctx carries none.
+func FormatFromContext(ctx context.Context) Format {
+	if f, ok := ctx.Value(contextKey{}).(Format); ok {
+		return f
+	}
+	return Format{Kind: FormatTable}
+}
+
+// Output renders v into w in the structured format ctx carries, which is JSON
+// unless the user asked for YAML or a template.
+func Output(ctx context.Context, w io.Writer, v interface{}) error {
+	return Structured(w, FormatFromContext(ctx), v)
+}
+
+// Structured renders v into w in the given format, which defaults to JSON
+// when it isn't structured. YAML and templates use the field names of the
+// JSON encoding of v, so that they're the same across all formats.
+func Structured(w io.Writer, f Format, v interface{}) error {
+	if f.Kind != FormatYAML && f.Kind != FormatTemplate {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "    ")
+		return enc.Encode(v)
+	}
+
+	generic, err := toGeneric(v)
+	if err != nil {
+		return err
+	}
+
+	if f.Kind == FormatYAML {
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(generic); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+
+	tmpl, err := template.New("output").Option("missingkey=zero").Parse(f.Template)
+	if err != nil {
+		return fmt.Errorf("invalid output template: %w", err)
+	}
+	if err := tmpl.Execute(w, generic); err != nil {
+		return fmt.Errorf("failed rendering output template: %w", err)
+	}
+	_, err = fmt.Fprintln(w)
+	return err
+}
+
+// toGeneric converts v to maps, slices and scalars through its JSON encoding.
+func toGeneric(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var generic interface{}
+	if err := dec.Decode(&generic); err != nil {
+		return nil, err
+	}
+	return numbersToScalars(generic), nil
+}
+
+// numbersToScalars replaces the json.Numbers of v by int64s or float64s, which
+// YAML encodes as numbers rather than strings.
+func numbersToScalars(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			v[k] = numbersToScalars(e)
+		}
+		return v
+	case []interface{}:
+		for i, e := range v {
+			v[i] = numbersToScalars(e)
+		}
+		return v
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	default:
+		return v
+	}
+}
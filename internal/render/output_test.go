@@ -0,0 +1,101 @@
+package render
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFormat(t *testing.T) {
+	cases := []struct {
+		in   string
+		want Format
+		err  string
+	}{
+		{in: "", want: Format{Kind: FormatTable}},
+		{in: "table", want: Format{Kind: FormatTable}},
+		{in: "wide", want: Format{Kind: FormatWide}},
+		{in: "JSON", want: Format{Kind: FormatJSON}},
+		{in: "yaml", want: Format{Kind: FormatYAML}},
+		{in: "go-template={{.id}}", want: Format{Kind: FormatTemplate, Template: "{{.id}}"}},
+		{in: "go-template={{.a}}={{.b}}", want: Format{Kind: FormatTemplate, Template: "{{.a}}={{.b}}"}},
+		{in: "go-template", err: "requires a template over the JSON field names, e.g. go-template='{{.id}}'"},
+		{in: "go-template={{.id", err: "invalid output template"},
+		{in: "json=x", err: "doesn't accept a value"},
+		{in: "xml", err: "unsupported output format"},
+	}
+
+	for _, c := range cases {
+		got, err := ParseFormat(c.in)
+		if c.err != "" {
+			assert.ErrorContains(t, err, c.err, c.in)
+			continue
+		}
+		require.NoError(t, err, c.in)
+		assert.Equal(t, c.want, got, c.in)
+	}
+}
+
+func TestFormatStructured(t *testing.T) {
+	assert.False(t, Format{Kind: FormatTable}.Structured())
+	assert.False(t, Format{Kind: FormatWide}.Structured())
+	assert.True(t, Format{Kind: FormatJSON}.Structured())
+	assert.True(t, Format{Kind: FormatYAML}.Structured())
+	assert.True(t, Format{Kind: FormatTemplate}.Structured())
+}
+
+type testItem struct {
+	ID     string            `json:"id"`
+	Count  int               `json:"count"`
+	Ratio  float64           `json:"ratio"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+func TestStructured(t *testing.T) {
+	items := []testItem{
+		{ID: "a", Count: 2, Ratio: 0.5, Labels: map[string]string{"k": "v"}},
+		{ID: "b", Count: 3},
+	}
+
+	var b bytes.Buffer
+	require.NoError(t, Structured(&b, Format{Kind: FormatYAML}, items))
+	assert.Equal(t, `- count: 2
+  id: a
+  labels:
+    k: v
+  ratio: 0.5
+- count: 3
+  id: b
+  ratio: 0
+`, b.String())
+
+	b.Reset()
+	require.NoError(t, Structured(&b, Format{Kind: FormatTemplate, Template: "{{range .}}{{.id}}:{{.count}} {{end}}"}, items))
+	assert.Equal(t, "a:2 b:3 \n", b.String())
+
+	b.Reset()
+	require.NoError(t, Structured(&b, Format{Kind: FormatJSON}, testItem{ID: "a"}))
+	assert.Equal(t, "{\n    \"id\": \"a\",\n    \"count\": 0,\n    \"ratio\": 0\n}\n", b.String())
+}
+
+func TestOutput(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, Format{Kind: FormatTable}, FormatFromContext(ctx))
+
+	var b bytes.Buffer
+	require.NoError(t, Output(ctx, &b, testItem{ID: "a"}))
+	assert.Equal(t, "{\n    \"id\": \"a\",\n    \"count\": 0,\n    \"ratio\": 0\n}\n", b.String())
+
+	// the example of the template error renders the field
+	f, err := ParseFormat("go-template={{.id}}")
+	require.NoError(t, err)
+	ctx = NewContext(ctx, f)
+	assert.Equal(t, f, FormatFromContext(ctx))
+
+	b.Reset()
+	require.NoError(t, Output(ctx, &b, testItem{ID: "a"}))
+	assert.Equal(t, "a\n", b.String())
+}
@@ -0,0 +1,33 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestYAMLToJSON(t *testing.T) {
+	data, err := YAMLToJSON([]byte(`
+image: nginx
+env:
+  PORT: 8080
+services:
+  - ports:
+      - port: 443
+        handlers: [tls, http]
+codes:
+  1: one
+  true: yes
+`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"image": "nginx",
+		"env": {"PORT": 8080},
+		"services": [{"ports": [{"port": 443, "handlers": ["tls", "http"]}]}],
+		"codes": {"1": "one", "true": "yes"}
+	}`, string(data))
+
+	_, err = YAMLToJSON([]byte("image: [nginx"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,14 @@
+package s3
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanonicalQuery(t *testing.T) {
+	query := url.Values{"prefix": {"my db/"}, "list-type": {"2"}}
+	assert.Equal(t, "list-type=2&prefix=my%20db%2F", canonicalQuery(query))
+	assert.Equal(t, "/bucket/my-db/a%2Bb~.dump", escapePath("/bucket/my-db/a+b~.dump"))
+}
@@ -0,0 +1,151 @@
+package watch
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/superfly/flyctl/internal/render"
+)
+
+// Timeline records how long each phase of a deployment takes, so that a
+// summary can be printed once it's done.
+//
+// A nil *Timeline is valid and records nothing. Instances of Timeline are safe
+// for concurrent use.
+type Timeline struct {
+	mu      sync.Mutex
+	started time.Time
+	phases  []TimelinePhase
+}
+
+// TimelinePhase is a single phase of a Timeline.
+type TimelinePhase struct {
+	Name      string        `json:"name"`
+	StartedAt time.Time     `json:"started_at"`
+	Duration  time.Duration `json:"-"`
+}
+
+// NewTimeline returns a Timeline whose wall time starts now.
+func NewTimeline() *Timeline {
+	return &Timeline{started: time.Now()}
+}
+
+type timelineContextKey struct{}
+
+// WithTimeline derives a context that carries t from ctx.
+func WithTimeline(ctx context.Context, t *Timeline) context.Context {
+	return context.WithValue(ctx, timelineContextKey{}, t)
+}
+
+// TimelineFromContext returns the Timeline ctx carries, or nil.
+func TimelineFromContext(ctx context.Context) *Timeline {
+	t, _ := ctx.Value(timelineContextKey{}).(*Timeline)
+	return t
+}
+
+// Begin starts a phase with the given name and returns the function ending it.
+func (t *Timeline) Begin(name string) (end func()) {
+	started := time.Now()
+
+	return func() {
+		t.add(name, started, time.Since(started))
+	}
+}
+
+// Add records a phase measured elsewhere, ending now.
+func (t *Timeline) Add(name string, d time.Duration) {
+	t.add(name, time.Now().Add(-d), d)
+}
+
+func (t *Timeline) add(name string, startedAt time.Time, d time.Duration) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.phases = append(t.phases, TimelinePhase{
+		Name:      name,
+		StartedAt: startedAt,
+		Duration:  d,
+	})
+}
+
+// Phases returns the phases recorded so far, in the order they ended.
+func (t *Timeline) Phases() []TimelinePhase {
+	if t == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]TimelinePhase(nil), t.phases...)
+}
+
+// TimelineSummary is the JSON summary of a Timeline.
+type TimelineSummary struct {
+	StartedAt time.Time              `json:"started_at"`
+	TotalMs   int64                  `json:"total_ms"`
+	Phases    []TimelinePhaseSummary `json:"phases"`
+}
+
+// TimelinePhaseSummary is a phase of a TimelineSummary.
+type TimelinePhaseSummary struct {
+	TimelinePhase
+	DurationMs int64 `json:"duration_ms"`
+}
+
+// Summary returns the summary of the phases recorded so far.
+func (t *Timeline) Summary() TimelineSummary {
+	if t == nil {
+		return TimelineSummary{}
+	}
+
+	phases := t.Phases()
+	summary := TimelineSummary{
+		StartedAt: t.started,
+		TotalMs:   time.Since(t.started).Milliseconds(),
+		Phases:    make([]TimelinePhaseSummary, 0, len(phases)),
+	}
+	for _, p := range phases {
+		summary.Phases = append(summary.Phases, TimelinePhaseSummary{p, p.Duration.Milliseconds()})
+	}
+
+	return summary
+}
+
+// Render writes a summary of the timeline to w, as a table or as JSON.
+func (t *Timeline) Render(ctx context.Context, w io.Writer, asJSON bool) error {
+	if t == nil {
+		return nil
+	}
+
+	if asJSON {
+		return render.Output(ctx, w, t.Summary())
+	}
+
+	var (
+		phases = t.Phases()
+		total  = time.Since(t.started)
+	)
+
+	rows := make([][]string, 0, len(phases)+1)
+	for _, p := range phases {
+		rows = append(rows, []string{p.Name, formatPhaseDuration(p.Duration)})
+	}
+	rows = append(rows, []string{"Total", formatPhaseDuration(total)})
+
+	return render.Table(w, "Deployment timeline", rows, "Phase", "Duration")
+}
+
+func formatPhaseDuration(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+
+	return d.Round(100 * time.Millisecond).String()
+}
@@ -283,7 +283,7 @@ func renderLogs(ctx context.Context, alloc *api.AllocationStatus) {
 		}
 
 		if cfg.JSONOutput {
-			render.JSON(out, entry)
+			render.Output(ctx, out, entry)
 		}
 
 		render.LogEntry(
@@ -295,18 +295,42 @@ func renderLogs(ctx context.Context, alloc *api.AllocationStatus) {
 	}
 }
 
+// DefaultChecksTimeout is how long MachinesChecks waits for checks to pass.
+const DefaultChecksTimeout = 300 * time.Second
+
 func MachinesChecks(ctx context.Context, machines []*api.Machine) error {
+	return MachinesChecksWithTimeout(ctx, machines, DefaultChecksTimeout, 0)
+}
+
+// MachinesChecksWithTimeout waits up to timeout for the checks of the machines
+// to pass. Checks are only waited on once the grace period is over, which lets
+// slow-booting machines start up without failing. A zero timeout stands for
+// DefaultChecksTimeout.
+func MachinesChecksWithTimeout(ctx context.Context, machines []*api.Machine, timeout, gracePeriod time.Duration) error {
 	io := iostreams.FromContext(ctx)
 	colorize := io.ColorScheme()
 
+	if timeout <= 0 {
+		timeout = DefaultChecksTimeout
+	}
+
 	checksTotal := lo.SumBy(machines, func(m *api.Machine) int { return len(m.Checks) })
 	if checksTotal == 0 {
 		fmt.Fprintln(io.Out, "No health checks found")
 		return nil
 	}
 
+	if gracePeriod > 0 {
+		fmt.Fprintf(io.ErrOut, "  Waiting %s before checking health\n", gracePeriod)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(gracePeriod):
+		}
+	}
+
 	machineIDs := lo.Map(machines, func(m *api.Machine, _ int) string { return m.ID })
-	ctx, cancel := context.WithTimeout(ctx, 300*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 	iteration := 0
 
@@ -14,6 +14,11 @@ type LogOptions struct {
 	AppName    string
 	VMID       string
 	RegionCode string
+
+	// Since, Until and Search narrow down historical log searches.
+	Since  time.Time
+	Until  time.Time
+	Search string
 }
 
 func (opts *LogOptions) toNatsSubject() (subject string) {
@@ -82,14 +82,7 @@ func Poll(ctx context.Context, out chan<- LogEntry, client *api.Client, opts *Lo
 		}
 
 		for _, entry := range entries {
-			out <- LogEntry{
-				Instance:  entry.Instance,
-				Level:     entry.Level,
-				Message:   entry.Message,
-				Region:    entry.Region,
-				Timestamp: entry.Timestamp,
-				Meta:      entry.Meta,
-			}
+			out <- newLogEntry(entry)
 		}
 	}
 }
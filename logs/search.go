@@ -0,0 +1,78 @@
+package logs
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/superfly/flyctl/api"
+)
+
+// Search sends the historical logs matching opts to out, oldest first, and
+// returns once there are no more.
+func Search(ctx context.Context, out chan<- LogEntry, client *api.Client, opts *LogOptions) error {
+	query := api.AppLogsQuery{
+		Region:   opts.RegionCode,
+		Instance: opts.VMID,
+		Search:   opts.Search,
+		Start:    opts.Since,
+		End:      opts.Until,
+	}
+
+	var nextToken string
+	for {
+		entries, token, err := client.SearchAppLogs(ctx, opts.AppName, nextToken, query)
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			switch matched, past := opts.matches(entry); {
+			case past:
+				return nil
+			case matched:
+				select {
+				case out <- newLogEntry(entry):
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+		}
+
+		if len(entries) == 0 || token == "" || token == nextToken {
+			return nil
+		}
+		nextToken = token
+	}
+}
+
+// matches reports whether the entry matches the time range and search term of
+// opts, and whether it's past the end of the time range. The API is expected
+// to do the filtering already; this guards against it ignoring any criteria.
+func (opts *LogOptions) matches(entry api.LogEntry) (matched, past bool) {
+	if ts, err := time.Parse(time.RFC3339Nano, entry.Timestamp); err == nil {
+		if !opts.Since.IsZero() && ts.Before(opts.Since) {
+			return false, false
+		}
+		if !opts.Until.IsZero() && ts.After(opts.Until) {
+			return false, true
+		}
+	}
+
+	if opts.Search != "" && !strings.Contains(strings.ToLower(entry.Message), strings.ToLower(opts.Search)) {
+		return false, false
+	}
+
+	return true, false
+}
+
+func newLogEntry(entry api.LogEntry) LogEntry {
+	return LogEntry{
+		Instance:  entry.Instance,
+		Level:     entry.Level,
+		Message:   entry.Message,
+		Region:    entry.Region,
+		Timestamp: entry.Timestamp,
+		Meta:      entry.Meta,
+	}
+}
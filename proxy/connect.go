@@ -21,9 +21,20 @@ type ConnectParams struct {
 	RemoteHost       string
 	PromptInstance   bool
 	DisableSpinner   bool
+	// Network is either tcp, the default, or udp.
+	Network string
 }
 
 func Connect(ctx context.Context, p *ConnectParams) (err error) {
+	if p.Network == "udp" {
+		server, err := NewPacketServer(ctx, p)
+		if err != nil {
+			return err
+		}
+
+		return server.ProxyServer(ctx)
+	}
+
 	server, err := NewServer(ctx, p)
 	if err != nil {
 		return err
@@ -32,14 +43,12 @@ func Connect(ctx context.Context, p *ConnectParams) (err error) {
 	return server.ProxyServer(ctx)
 }
 
-func NewServer(ctx context.Context, p *ConnectParams) (*Server, error) {
+// remoteAddress resolves the address connections are proxied to.
+func remoteAddress(ctx context.Context, p *ConnectParams) (string, error) {
 	var (
-		io         = iostreams.FromContext(ctx)
 		client     = client.FromContext(ctx).API()
 		orgSlug    = p.OrganizationSlug
-		localPort  = p.Ports[0]
-		remotePort = localPort
-		remoteAddr string
+		remotePort = p.Ports[0]
 	)
 
 	if len(p.Ports) > 1 {
@@ -48,30 +57,43 @@ func NewServer(ctx context.Context, p *ConnectParams) (*Server, error) {
 
 	agentclient, err := agent.Establish(ctx, client)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
 	// Prompt for a specific instance and set it as the remote target
 	if p.PromptInstance {
-		instance, err := selectInstance(ctx, p.OrganizationSlug, p.AppName, agentclient)
+		instance, err := SelectInstance(ctx, p.OrganizationSlug, p.AppName, agentclient)
 		if err != nil {
-			return nil, err
+			return "", err
 		}
 
-		remoteAddr = fmt.Sprintf("[%s]:%s", instance, remotePort)
+		return fmt.Sprintf("[%s]:%s", instance, remotePort), nil
 	}
 
-	if remoteAddr == "" && p.RemoteHost != "" {
+	if p.RemoteHost == "" {
+		return "", nil
+	}
 
-		// If a host is specified that isn't an IpV6 address, assume it's a DNS entry and wait for that
-		// entry to resolve
-		if !ip.IsV6(p.RemoteHost) {
-			if err := agentclient.WaitForDNS(ctx, p.Dialer, orgSlug, p.RemoteHost); err != nil {
-				return nil, fmt.Errorf("%s: %w", p.RemoteHost, err)
-			}
+	// If a host is specified that isn't an IpV6 address, assume it's a DNS entry and wait for that
+	// entry to resolve
+	if !ip.IsV6(p.RemoteHost) {
+		if err := agentclient.WaitForDNS(ctx, p.Dialer, orgSlug, p.RemoteHost); err != nil {
+			return "", fmt.Errorf("%s: %w", p.RemoteHost, err)
 		}
+	}
+
+	return fmt.Sprintf("[%s]:%s", p.RemoteHost, remotePort), nil
+}
 
-		remoteAddr = fmt.Sprintf("[%s]:%s", p.RemoteHost, remotePort)
+func NewServer(ctx context.Context, p *ConnectParams) (*Server, error) {
+	var (
+		io        = iostreams.FromContext(ctx)
+		localPort = p.Ports[0]
+	)
+
+	remoteAddr, err := remoteAddress(ctx, p)
+	if err != nil {
+		return nil, err
 	}
 
 	var listener net.Listener
@@ -109,7 +131,39 @@ func NewServer(ctx context.Context, p *ConnectParams) (*Server, error) {
 	}, nil
 }
 
-func selectInstance(ctx context.Context, org, app string, c *agent.Client) (instance string, err error) {
+// NewPacketServer creates a server forwarding the UDP datagrams received on
+// the local port to the remote address.
+func NewPacketServer(ctx context.Context, p *ConnectParams) (*PacketServer, error) {
+	var (
+		io        = iostreams.FromContext(ctx)
+		localPort = p.Ports[0]
+	)
+
+	if _, err := strconv.Atoi(localPort); err != nil {
+		return nil, fmt.Errorf("UDP can only be proxied from a local port, not %s", localPort)
+	}
+
+	remoteAddr, err := remoteAddress(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := net.ListenPacket("udp", fmt.Sprintf("127.0.0.1:%s", localPort))
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(io.Out, "Proxying local UDP port %s to remote %s\n", localPort, remoteAddr)
+
+	return &PacketServer{
+		Addr: remoteAddr,
+		Conn: conn,
+		Dial: p.Dialer.DialContext,
+	}, nil
+}
+
+// SelectInstance prompts for an instance of app and returns its address.
+func SelectInstance(ctx context.Context, org, app string, c *agent.Client) (instance string, err error) {
 	instances, err := c.Instances(ctx, org, app)
 	if err != nil {
 		return "", fmt.Errorf("look up %s: %w", app, err)
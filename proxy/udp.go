@@ -0,0 +1,116 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/superfly/flyctl/terminal"
+)
+
+// udpIdleTimeout is how long a UDP flow lives without datagrams.
+const udpIdleTimeout = 2 * time.Minute
+
+// PacketServer forwards the datagrams received on Conn to Addr. Each local
+// peer gets its own flow to Addr, so replies reach the peer which sent the
+// request.
+type PacketServer struct {
+	Addr string
+	Conn net.PacketConn
+	Dial func(ctx context.Context, network, addr string) (net.Conn, error)
+
+	mu    sync.Mutex
+	flows map[string]net.Conn
+}
+
+func (srv *PacketServer) ProxyServer(ctx context.Context) error {
+	defer srv.Conn.Close()
+
+	srv.flows = map[string]net.Conn{}
+	defer func() {
+		srv.mu.Lock()
+		defer srv.mu.Unlock()
+		for _, target := range srv.flows {
+			target.Close()
+		}
+	}()
+
+	buf := make([]byte, 65535)
+	for {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if err := srv.Conn.SetReadDeadline(time.Now().Add(time.Second)); err != nil {
+			return err
+		}
+
+		n, peer, err := srv.Conn.ReadFrom(buf)
+		switch {
+		case errors.Is(err, os.ErrDeadlineExceeded):
+			continue
+		case err != nil:
+			terminal.Debug("Error reading datagram: ", err)
+			continue
+		}
+
+		target, err := srv.flow(ctx, peer)
+		if err != nil {
+			terminal.Debug("failed to connect to target: ", err)
+			continue
+		}
+
+		target.SetReadDeadline(time.Now().Add(udpIdleTimeout))
+		if _, err := target.Write(buf[:n]); err != nil {
+			terminal.Debug("failed to forward datagram: ", err)
+		}
+	}
+}
+
+// flow returns the flow of peer to the target, dialing it on the first
+// datagram of peer.
+func (srv *PacketServer) flow(ctx context.Context, peer net.Addr) (net.Conn, error) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	if target, ok := srv.flows[peer.String()]; ok {
+		return target, nil
+	}
+
+	target, err := srv.Dial(ctx, "udp", srv.Addr)
+	if err != nil {
+		return nil, err
+	}
+	srv.flows[peer.String()] = target
+
+	terminal.Debug("new UDP flow from: ", peer)
+
+	// relay replies until the flow is idle
+	go func() {
+		defer func() {
+			srv.mu.Lock()
+			delete(srv.flows, peer.String())
+			srv.mu.Unlock()
+			target.Close()
+
+			terminal.Debug("UDP flow closed: ", peer)
+		}()
+
+		buf := make([]byte, 65535)
+		for {
+			target.SetReadDeadline(time.Now().Add(udpIdleTimeout))
+			n, err := target.Read(buf)
+			if err != nil {
+				return
+			}
+			if _, err := srv.Conn.WriteTo(buf[:n], peer); err != nil {
+				return
+			}
+		}
+	}()
+
+	return target, nil
+}
@@ -0,0 +1,224 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Scanner plugins let frameworks not covered here provide their own
+// Dockerfiles and fly.toml defaults. A plugin is an executable file in the
+// plugin directory, ~/.fly/scanners by default, named fly-scanner-<name>.
+// Plugins run in the order of their names, before the scanners of flyctl.
+//
+// flyctl runs each plugin in the source directory, with the path to it as its
+// only argument and a JSON pluginRequest on stdin. A plugin which doesn't
+// recognize the source prints nothing, or {"detected": false}. Otherwise it
+// prints a pluginResponse, whose files, like a Dockerfile, are written to the
+// source directory. A plugin exiting with an error fails the scan.
+const (
+	PluginPrefix = "fly-scanner-"
+
+	pluginTimeout = 30 * time.Second
+)
+
+type pluginRequest struct {
+	SourceDir    string `json:"source_dir"`
+	Mode         string `json:"mode"`
+	ExistingPort int    `json:"existing_port,omitempty"`
+}
+
+type pluginSecret struct {
+	Key   string `json:"key"`
+	Help  string `json:"help"`
+	Value string `json:"value,omitempty"`
+}
+
+type pluginFile struct {
+	Path     string `json:"path"`
+	Contents string `json:"contents"`
+}
+
+type pluginResponse struct {
+	Detected         bool              `json:"detected"`
+	Family           string            `json:"family"`
+	Version          string            `json:"version"`
+	Port             int               `json:"port"`
+	Env              map[string]string `json:"env"`
+	BuildArgs        map[string]string `json:"build_args"`
+	Processes        map[string]string `json:"processes"`
+	Secrets          []pluginSecret    `json:"secrets"`
+	Files            []pluginFile      `json:"files"`
+	Statics          []Static          `json:"statics"`
+	Volumes          []Volume          `json:"volumes"`
+	ReleaseCmd       string            `json:"release_command"`
+	DockerCommand    string            `json:"docker_command"`
+	DockerEntrypoint string            `json:"docker_entrypoint"`
+	KillSignal       string            `json:"kill_signal"`
+	HttpCheckPath    string            `json:"http_check_path"`
+	Concurrency      map[string]int    `json:"concurrency"`
+	SkipDatabase     bool              `json:"skip_database"`
+	DeployDocs       string            `json:"deploy_docs"`
+	Notice           string            `json:"notice"`
+}
+
+// Plugins returns the paths to the scanner plugins of the directory, in the
+// order they run.
+func Plugins(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed listing scanner plugins: %w", err)
+	}
+
+	var paths []string
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasPrefix(e.Name(), PluginPrefix) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			return nil, fmt.Errorf("failed reading scanner plugin %s: %w", e.Name(), err)
+		}
+		if runtime.GOOS != "windows" && info.Mode().Perm()&0o111 == 0 {
+			continue
+		}
+		paths = append(paths, filepath.Join(dir, e.Name()))
+	}
+	sort.Strings(paths)
+
+	return paths, nil
+}
+
+// PluginName returns the name of the scanner plugin at the path.
+func PluginName(path string) string {
+	name := strings.TrimPrefix(filepath.Base(path), PluginPrefix)
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
+
+func configurePlugins(sourceDir string, config *ScannerConfig) (*SourceInfo, error) {
+	if config.PluginDir == "" {
+		return nil, nil
+	}
+
+	plugins, err := Plugins(config.PluginDir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range plugins {
+		si, err := runPlugin(path, sourceDir, config)
+		if err != nil {
+			return nil, fmt.Errorf("scanner plugin %s failed: %w", PluginName(path), err)
+		}
+		if si != nil {
+			return si, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func runPlugin(path, sourceDir string, config *ScannerConfig) (*SourceInfo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), pluginTimeout)
+	defer cancel()
+
+	absDir, err := filepath.Abs(sourceDir)
+	if err != nil {
+		return nil, err
+	}
+
+	input, err := json.Marshal(pluginRequest{
+		SourceDir:    absDir,
+		Mode:         config.Mode,
+		ExistingPort: config.ExistingPort,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, path, absDir) // #nosec G204
+	cmd.Dir = absDir
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+
+	return parsePluginResponse(stdout.Bytes())
+}
+
+func parsePluginResponse(output []byte) (*SourceInfo, error) {
+	if len(bytes.TrimSpace(output)) == 0 {
+		return nil, nil
+	}
+
+	var resp pluginResponse
+	if err := json.Unmarshal(output, &resp); err != nil {
+		return nil, fmt.Errorf("invalid output: %w", err)
+	}
+	if !resp.Detected {
+		return nil, nil
+	}
+	if resp.Family == "" {
+		return nil, errors.New("invalid output: family is required")
+	}
+
+	s := &SourceInfo{
+		Family:           resp.Family,
+		Version:          resp.Version,
+		Port:             resp.Port,
+		Env:              resp.Env,
+		BuildArgs:        resp.BuildArgs,
+		Processes:        resp.Processes,
+		Statics:          resp.Statics,
+		Volumes:          resp.Volumes,
+		ReleaseCmd:       resp.ReleaseCmd,
+		DockerCommand:    resp.DockerCommand,
+		DockerEntrypoint: resp.DockerEntrypoint,
+		KillSignal:       resp.KillSignal,
+		HttpCheckPath:    resp.HttpCheckPath,
+		Concurrency:      resp.Concurrency,
+		SkipDatabase:     resp.SkipDatabase,
+		DeployDocs:       resp.DeployDocs,
+		Notice:           resp.Notice,
+	}
+
+	for _, secret := range resp.Secrets {
+		s.Secrets = append(s.Secrets, Secret{
+			Key:   secret.Key,
+			Help:  secret.Help,
+			Value: secret.Value,
+		})
+	}
+
+	for _, f := range resp.Files {
+		clean := filepath.Clean(f.Path)
+		if filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf("invalid output: file %s is outside of the source directory", f.Path)
+		}
+		s.Files = append(s.Files, SourceFile{
+			Path:     clean,
+			Contents: []byte(f.Contents),
+		})
+	}
+
+	return s, nil
+}
@@ -0,0 +1,96 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writePlugin(t *testing.T, dir, name, script string, mode os.FileMode) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script), mode))
+}
+
+func TestPlugins(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are shell scripts")
+	}
+
+	dir := t.TempDir()
+	writePlugin(t, dir, "fly-scanner-zig", "", 0o755)
+	writePlugin(t, dir, "fly-scanner-crystal", "", 0o755)
+	writePlugin(t, dir, "fly-scanner-notexec", "", 0o644)
+	writePlugin(t, dir, "other", "", 0o755)
+
+	plugins, err := Plugins(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "fly-scanner-crystal"), filepath.Join(dir, "fly-scanner-zig")}, plugins)
+	assert.Equal(t, "zig", PluginName(plugins[1]))
+
+	plugins, err = Plugins(filepath.Join(dir, "missing"))
+	assert.NoError(t, err)
+	assert.Empty(t, plugins)
+}
+
+func TestScanWithPlugins(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are shell scripts")
+	}
+
+	pluginDir := t.TempDir()
+	sourceDir := t.TempDir()
+
+	writePlugin(t, pluginDir, "fly-scanner-a-skip", "echo '{\"detected\": false}'\n", 0o755)
+	writePlugin(t, pluginDir, "fly-scanner-b-zig", `test -f build.zig || exit 0
+cat <<'JSON'
+{
+  "detected": true,
+  "family": "Zig",
+  "port": 3000,
+  "env": {"PORT": "3000"},
+  "files": [{"path": "Dockerfile", "contents": "FROM alpine\n"}]
+}
+JSON
+`, 0o755)
+
+	config := &ScannerConfig{Mode: "launch", PluginDir: pluginDir}
+
+	si, err := configurePlugins(sourceDir, config)
+	require.NoError(t, err)
+	assert.Nil(t, si)
+
+	require.NoError(t, os.WriteFile(filepath.Join(sourceDir, "build.zig"), nil, 0o644))
+
+	si, err = Scan(sourceDir, config)
+	require.NoError(t, err)
+	require.NotNil(t, si)
+	assert.Equal(t, "Zig", si.Family)
+	assert.Equal(t, 3000, si.Port)
+	assert.Equal(t, map[string]string{"PORT": "3000"}, si.Env)
+	assert.Equal(t, []SourceFile{{Path: "Dockerfile", Contents: []byte("FROM alpine\n")}}, si.Files)
+
+	writePlugin(t, pluginDir, "fly-scanner-0-broken", "echo oops >&2; exit 1\n", 0o755)
+	_, err = Scan(sourceDir, config)
+	assert.ErrorContains(t, err, "scanner plugin 0-broken failed")
+	assert.ErrorContains(t, err, "oops")
+}
+
+func TestParsePluginResponse(t *testing.T) {
+	si, err := parsePluginResponse([]byte("  \n"))
+	assert.NoError(t, err)
+	assert.Nil(t, si)
+
+	_, err = parsePluginResponse([]byte(`{"detected": true}`))
+	assert.ErrorContains(t, err, "family is required")
+
+	_, err = parsePluginResponse([]byte(`{"detected": true, "family": "X", "files": [{"path": "../evil"}]}`))
+	assert.ErrorContains(t, err, "outside of the source directory")
+
+	si, err = parsePluginResponse([]byte(`{"detected": true, "family": "X", "secrets": [{"key": "API_KEY", "help": "The key"}]}`))
+	require.NoError(t, err)
+	assert.Equal(t, []Secret{{Key: "API_KEY", Help: "The key"}}, si.Secrets)
+}
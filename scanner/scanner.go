@@ -73,10 +73,14 @@ type Volume struct {
 type ScannerConfig struct {
 	Mode         string
 	ExistingPort int
+	// PluginDir is the directory of the scanner plugins, which run before
+	// the other scanners. Plugins are skipped when it's empty.
+	PluginDir string
 }
 
 func Scan(sourceDir string, config *ScannerConfig) (*SourceInfo, error) {
 	scanners := []sourceScanner{
+		configurePlugins,
 		configureDjango,
 		configureLaravel,
 		configurePhoenix,
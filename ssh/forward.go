@@ -0,0 +1,168 @@
+package ssh
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/superfly/flyctl/terminal"
+)
+
+// Forward is a port forwarded over the SSH connection, like the -L and -R
+// options of OpenSSH. Local forwards listen on BindAddr locally and connect to
+// Target from the server; remote forwards do the opposite.
+type Forward struct {
+	Remote   bool
+	BindAddr string
+	Target   string
+}
+
+func (f Forward) String() string {
+	if f.Remote {
+		return fmt.Sprintf("remote %s to local %s", f.BindAddr, f.Target)
+	}
+
+	return fmt.Sprintf("local %s to remote %s", f.BindAddr, f.Target)
+}
+
+// ParseForward parses a forward given as [bind_address:]port:host:hostport.
+// The bind address defaults to the loopback interface.
+func ParseForward(spec string, remote bool) (Forward, error) {
+	parts, err := splitForward(spec)
+	if err != nil {
+		return Forward{}, err
+	}
+
+	bind := "127.0.0.1"
+	switch len(parts) {
+	case 3:
+	case 4:
+		bind, parts = parts[0], parts[1:]
+	default:
+		return Forward{}, fmt.Errorf("invalid forward %s, must be [bind_address:]port:host:hostport", spec)
+	}
+
+	for _, port := range []string{parts[0], parts[2]} {
+		if _, err := net.LookupPort("tcp", port); err != nil {
+			return Forward{}, fmt.Errorf("invalid forward %s: %w", spec, err)
+		}
+	}
+
+	return Forward{
+		Remote:   remote,
+		BindAddr: net.JoinHostPort(bind, parts[0]),
+		Target:   net.JoinHostPort(parts[1], parts[2]),
+	}, nil
+}
+
+// splitForward splits a forward on colons, except for the ones of bracketed
+// IPv6 addresses.
+func splitForward(spec string) (parts []string, err error) {
+	for spec != "" {
+		var part string
+		if strings.HasPrefix(spec, "[") {
+			end := strings.Index(spec, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("invalid forward %s, missing ]", spec)
+			}
+			part, spec = spec[1:end], spec[end+1:]
+			if spec != "" && !strings.HasPrefix(spec, ":") {
+				return nil, fmt.Errorf("invalid forward %s", spec)
+			}
+			spec = strings.TrimPrefix(spec, ":")
+		} else {
+			part, spec, _ = strings.Cut(spec, ":")
+		}
+		parts = append(parts, part)
+	}
+
+	return parts, nil
+}
+
+// StartForward starts forwarding f over the connection of the client until
+// ctx is done.
+func (c *Client) StartForward(ctx context.Context, f Forward) error {
+	if c.Client == nil {
+		if err := c.Connect(ctx); err != nil {
+			return err
+		}
+	}
+
+	var (
+		l    net.Listener
+		dial func() (net.Conn, error)
+		err  error
+	)
+
+	if f.Remote {
+		if l, err = c.Client.Listen("tcp", f.BindAddr); err != nil {
+			return fmt.Errorf("the server refused to forward %s: %w", f.BindAddr, err)
+		}
+		dial = func() (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "tcp", f.Target)
+		}
+	} else {
+		if l, err = net.Listen("tcp", f.BindAddr); err != nil {
+			return err
+		}
+		dial = func() (net.Conn, error) {
+			return c.Client.Dial("tcp", f.Target)
+		}
+	}
+
+	go func() {
+		<-ctx.Done()
+		l.Close()
+	}()
+
+	go func() {
+		for {
+			source, err := l.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer source.Close()
+
+				target, err := dial()
+				if err != nil {
+					terminal.Debugf("failed forwarding %s: %v\n", f, err)
+					return
+				}
+				defer target.Close()
+
+				pipe(source, target)
+			}()
+		}
+	}()
+
+	return nil
+}
+
+// pipe copies between a and b until either side is done.
+func pipe(a, b net.Conn) {
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	copyFunc := func(dst, src net.Conn) {
+		defer wg.Done()
+		io.Copy(dst, src)
+
+		// close the write half if it exports a CloseWrite() method
+		if conn, ok := dst.(interface{ CloseWrite() error }); ok {
+			conn.CloseWrite()
+		} else {
+			dst.Close()
+		}
+	}
+
+	go copyFunc(a, b)
+	go copyFunc(b, a)
+
+	wg.Wait()
+}
@@ -100,8 +100,17 @@ func (s *SessionIO) attach(ctx context.Context, sess *ssh.Session, cmd string) e
 		})
 		io.Copy(stdin, s.Stdin)
 	}()
-	go io.Copy(s.Stdout, stdout)
-	go io.Copy(s.Stderr, stderr)
+	// wait for the output to be fully copied once the command exits
+	var output sync.WaitGroup
+	output.Add(2)
+	go func() {
+		defer output.Done()
+		io.Copy(s.Stdout, stdout)
+	}()
+	go func() {
+		defer output.Done()
+		io.Copy(s.Stderr, stderr)
+	}()
 
 	cmdC := make(chan error, 1)
 	go func() {
@@ -118,6 +127,7 @@ func (s *SessionIO) attach(ctx context.Context, sess *ssh.Session, cmd string) e
 
 	select {
 	case err := <-cmdC:
+		output.Wait()
 		return err
 	case <-ctx.Done():
 		return errors.New("session forcibly closed; the remote process may still be running")
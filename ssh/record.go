@@ -0,0 +1,137 @@
+package ssh
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Recorder records a session as an asciinema v2 typescript: a header line,
+// then a line per chunk of input or output, timestamped in seconds since the
+// start of the session.
+type Recorder struct {
+	mu      sync.Mutex
+	w       io.Writer
+	start   time.Time
+	pending map[string][]byte
+	err     error
+}
+
+type castHeader struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// NewRecorder writes the header of the typescript to w and returns a recorder
+// for the events of the session.
+func NewRecorder(w io.Writer, width, height int, title string, env map[string]string) (*Recorder, error) {
+	start := time.Now()
+
+	err := json.NewEncoder(w).Encode(castHeader{
+		Version:   2,
+		Width:     width,
+		Height:    height,
+		Timestamp: start.Unix(),
+		Title:     title,
+		Env:       env,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Recorder{w: w, start: start, pending: map[string][]byte{}}, nil
+}
+
+// Err returns the first error writing the typescript.
+func (r *Recorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.err
+}
+
+// record writes an event of the kind given, "o" for output or "i" for input.
+// Multi-byte characters split across chunks are recorded whole with the next
+// chunk.
+func (r *Recorder) record(kind string, b []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.err != nil {
+		return
+	}
+
+	data := append(r.pending[kind], b...)
+	complete := len(data)
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(data[i]) {
+			if !utf8.FullRune(data[i:]) {
+				complete = i
+			}
+			break
+		}
+	}
+	r.pending[kind] = append([]byte(nil), data[complete:]...)
+
+	if complete == 0 {
+		return
+	}
+
+	elapsed := time.Since(r.start).Seconds()
+	r.err = json.NewEncoder(r.w).Encode([]interface{}{elapsed, kind, string(data[:complete])})
+}
+
+// Output returns a writer recording the output written to w.
+func (r *Recorder) Output(w io.WriteCloser) io.WriteCloser {
+	return &recordWriter{WriteCloser: w, rec: r}
+}
+
+// Input returns a reader recording the input read from rd. The reader keeps
+// the file descriptor of rd, so that terminals are still detected.
+func (r *Recorder) Input(rd io.Reader) io.Reader {
+	in := &recordReader{Reader: rd, rec: r}
+	if fd, ok := rd.(FdReader); ok {
+		return &fdRecordReader{recordReader: in, fd: fd}
+	}
+
+	return in
+}
+
+type recordWriter struct {
+	io.WriteCloser
+	rec *Recorder
+}
+
+func (w *recordWriter) Write(b []byte) (int, error) {
+	n, err := w.WriteCloser.Write(b)
+	w.rec.record("o", b[:n])
+
+	return n, err
+}
+
+type recordReader struct {
+	io.Reader
+	rec *Recorder
+}
+
+func (r *recordReader) Read(b []byte) (int, error) {
+	n, err := r.Reader.Read(b)
+	r.rec.record("i", b[:n])
+
+	return n, err
+}
+
+type fdRecordReader struct {
+	*recordReader
+	fd FdReader
+}
+
+func (r *fdRecordReader) Fd() uintptr {
+	return r.fd.Fd()
+}
@@ -0,0 +1,135 @@
+package wg
+
+import (
+	"bufio"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// Stats are the counters of a tunnel since it was connected.
+type Stats struct {
+	Endpoint      string
+	LastHandshake time.Time
+	BytesSent     int64
+	BytesReceived int64
+
+	Dials      int64
+	DialErrors int64
+
+	DNSQueries   int64
+	DNSCacheHits int64
+	DNSErrors    int64
+}
+
+type counters struct {
+	dials      atomic.Int64
+	dialErrors atomic.Int64
+
+	dnsQueries   atomic.Int64
+	dnsCacheHits atomic.Int64
+	dnsErrors    atomic.Int64
+}
+
+// Stats returns the counters of the tunnel, along with the ones of its
+// WireGuard peer.
+func (t *Tunnel) Stats() (Stats, error) {
+	s := Stats{
+		Dials:        t.counters.dials.Load(),
+		DialErrors:   t.counters.dialErrors.Load(),
+		DNSQueries:   t.counters.dnsQueries.Load(),
+		DNSCacheHits: t.counters.dnsCacheHits.Load(),
+		DNSErrors:    t.counters.dnsErrors.Load(),
+	}
+
+	ipc, err := t.dev.IpcGet()
+	if err != nil {
+		return s, err
+	}
+
+	var sec, nsec int64
+	scanner := bufio.NewScanner(strings.NewReader(ipc))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if !ok {
+			continue
+		}
+
+		switch key {
+		case "endpoint":
+			s.Endpoint = value
+		case "rx_bytes":
+			s.BytesReceived, _ = strconv.ParseInt(value, 10, 64)
+		case "tx_bytes":
+			s.BytesSent, _ = strconv.ParseInt(value, 10, 64)
+		case "last_handshake_time_sec":
+			sec, _ = strconv.ParseInt(value, 10, 64)
+		case "last_handshake_time_nsec":
+			nsec, _ = strconv.ParseInt(value, 10, 64)
+		}
+	}
+	if sec > 0 {
+		s.LastHandshake = time.Unix(sec, nsec)
+	}
+
+	return s, nil
+}
+
+// maxDNSCacheTTL caps how long answers are cached, so that machines which
+// were just started or stopped are soon seen.
+const maxDNSCacheTTL = 10 * time.Second
+
+// dnsCache caches the positive answers of the DNS server of a tunnel.
+type dnsCache struct {
+	mu      sync.Mutex
+	entries map[dns.Question]dnsCacheEntry
+}
+
+type dnsCacheEntry struct {
+	msg     *dns.Msg
+	expires time.Time
+}
+
+func (c *dnsCache) get(q dns.Question) *dns.Msg {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[q]
+	if !ok {
+		return nil
+	}
+	if time.Now().After(e.expires) {
+		delete(c.entries, q)
+		return nil
+	}
+
+	return e.msg.Copy()
+}
+
+func (c *dnsCache) put(q dns.Question, msg *dns.Msg) {
+	if len(msg.Answer) == 0 {
+		return
+	}
+
+	ttl := maxDNSCacheTTL
+	for _, rr := range msg.Answer {
+		if d := time.Duration(rr.Header().Ttl) * time.Second; d < ttl {
+			ttl = d
+		}
+	}
+	if ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries == nil {
+		c.entries = map[dns.Question]dnsCacheEntry{}
+	}
+	c.entries[q] = dnsCacheEntry{msg: msg.Copy(), expires: time.Now().Add(ttl)}
+}
@@ -8,6 +8,7 @@ import (
 	"math/rand"
 	"net"
 	"net/netip"
+	"time"
 
 	"github.com/miekg/dns"
 	"golang.zx2c4.com/wireguard/conn"
@@ -26,6 +27,9 @@ type Tunnel struct {
 
 	wscancel func()
 	resolv   *net.Resolver
+
+	counters counters
+	dnsCache dnsCache
 }
 
 func Connect(ctx context.Context, state *WireGuardState) (*Tunnel, error) {
@@ -127,7 +131,14 @@ func (t *Tunnel) Close() error {
 }
 
 func (t *Tunnel) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
-	return t.net.DialContext(ctx, network, addr)
+	t.counters.dials.Add(1)
+
+	conn, err := t.net.DialContext(ctx, network, addr)
+	if err != nil {
+		t.counters.dialErrors.Add(1)
+	}
+
+	return conn, err
 }
 
 func (t *Tunnel) Resolver() *net.Resolver {
@@ -190,7 +201,42 @@ func (t *Tunnel) LookupAAAA(ctx context.Context, name string) ([]net.IP, error)
 	return results, nil
 }
 
+// RTT measures the round trip time to the DNS server of the tunnel, which is
+// on the gateway of the peer.
+func (t *Tunnel) RTT(ctx context.Context) (time.Duration, error) {
+	var m dns.Msg
+	_ = m.SetQuestion(dns.Fqdn("_api.internal"), dns.TypeAAAA)
+
+	start := time.Now()
+	if _, err := t.exchangeDNS(ctx, &m); err != nil {
+		return 0, err
+	}
+
+	return time.Since(start), nil
+}
+
 func (t *Tunnel) queryDNS(ctx context.Context, msg *dns.Msg) (*dns.Msg, error) {
+	t.counters.dnsQueries.Add(1)
+
+	q := msg.Question[0]
+	if r := t.dnsCache.get(q); r != nil {
+		t.counters.dnsCacheHits.Add(1)
+		r.Id = msg.Id
+		return r, nil
+	}
+
+	r, err := t.exchangeDNS(ctx, msg)
+	if err != nil {
+		t.counters.dnsErrors.Add(1)
+		return nil, err
+	}
+
+	t.dnsCache.put(q, r)
+
+	return r, nil
+}
+
+func (t *Tunnel) exchangeDNS(ctx context.Context, msg *dns.Msg) (*dns.Msg, error) {
 	client := dns.Client{
 		Net: "tcp",
 		Dialer: &net.Dialer{